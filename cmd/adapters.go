@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/optimizer"
+
+	"github.com/spf13/cobra"
+)
+
+var adaptersCmd = &cobra.Command{
+	Use:   "adapters",
+	Short: "Show and tune network adapter power saving, RSS, interrupt moderation and offloads",
+	Long: `Show the physical network adapters and tune the driver settings that
+affect latency. --apply sets the gaming recommended values:
+
+  power-saving           "Allow the computer to turn off this device to save
+                         power"; turned off so the adapter never sleeps
+  rss                    receive-side scaling spreads received packets over
+                         CPU cores; turned on
+  interrupt-moderation   batches interrupts to save CPU time; turned off for
+                         lower latency, or kept with --interrupt-moderation keep
+  checksum-offload       the adapter computes checksums; kept on
+  lso                    large send offload batches outgoing data; turned off
+  rsc                    receive segment coalescing holds received packets
+                         back to merge them; turned off, as it causes latency
+                         spikes with some Realtek drivers
+
+Without flags, lists each adapter with the settings its driver supports.
+--apply tunes them (with --dry-run, only shows what would change); --set
+changes single settings instead, e.g. --set rsc=off,lso=on. --adapter limits
+either to one adapter. An adapter restarts as its driver applies a change,
+dropping the connection for a few seconds. The values each setting had
+first, down to the driver's per-protocol values, are kept, and --restore
+puts them all back.
+
+Tuning adapters needs administrator rights.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		adapter, _ := cmd.Flags().GetString("adapter")
+		moderation, _ := cmd.Flags().GetString("interrupt-moderation")
+		set, _ := cmd.Flags().GetString("set")
+		restore, _ := cmd.Flags().GetBool("restore")
+		dryRun = dryRun || admin.AuditMode()
+
+		if restore || (apply || set != "") && !dryRun {
+			if err := admin.RequireElevation("Tuning network adapters"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		switch {
+		case restore:
+			n, err := optimizer.RestoreAdapters()
+			fmt.Printf("Restored %d adapter setting(s)\n", n)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		case set != "":
+			tuning, err := parseAdapterSettings(set)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if dryRun {
+				fmt.Println("Previewing network adapter changes (dry run, nothing will be changed)...")
+			}
+			optimizer.PrintAdaptersResult(optimizer.TuneAdapters(adapter, tuning, optimizer.OptimizeOptions{DryRun: dryRun}))
+		case apply:
+			tuning := optimizer.GamingAdapterTuning()
+			switch strings.ToLower(moderation) {
+			case "on":
+				tuning[optimizer.AdapterInterruptModeration] = true
+			case "off":
+			case "keep":
+				delete(tuning, optimizer.AdapterInterruptModeration)
+			default:
+				fmt.Println("Error: --interrupt-moderation takes on, off or keep")
+				return
+			}
+			if dryRun {
+				fmt.Println("Previewing network adapter tuning (dry run, nothing will be changed)...")
+			}
+			optimizer.PrintAdaptersResult(optimizer.TuneAdapters(adapter, tuning, optimizer.OptimizeOptions{DryRun: dryRun}))
+		default:
+			printAdapters()
+		}
+	},
+}
+
+// parseAdapterSettings parses --set, a comma-separated list of
+// SETTING=on|off.
+func parseAdapterSettings(s string) (optimizer.AdapterTuning, error) {
+	known := make(map[string]bool)
+	for _, name := range optimizer.AdapterSettings() {
+		known[name] = true
+	}
+	tuning := make(optimizer.AdapterTuning)
+	for _, part := range strings.Split(s, ",") {
+		name, state, ok := strings.Cut(strings.TrimSpace(part), "=")
+		name = strings.ToLower(name)
+		if !ok || !known[name] {
+			return nil, fmt.Errorf("--set takes SETTING=on|off, with SETTING one of %s", strings.Join(optimizer.AdapterSettings(), ", "))
+		}
+		switch strings.ToLower(state) {
+		case "on":
+			tuning[name] = true
+		case "off":
+			tuning[name] = false
+		default:
+			return nil, fmt.Errorf("%s takes on or off, not %q", name, state)
+		}
+	}
+	return tuning, nil
+}
+
+func printAdapters() {
+	adapters, err := optimizer.ListAdapters()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(adapters) == 0 {
+		fmt.Println("No physical network adapters found.")
+		return
+	}
+	for _, a := range adapters {
+		fmt.Printf("%s (%s)\n", a.Name, a.Description)
+		for _, s := range optimizer.AdapterSettings() {
+			state := "not supported"
+			if on, ok := a.Enabled(s); ok && on {
+				state = "on"
+			} else if ok {
+				state = "off"
+			}
+			fmt.Printf("  %-28s %-22s %s\n", optimizer.AdapterSettingLabel(s)+":", s, state)
+		}
+	}
+}
+
+func init() {
+	adaptersCmd.Flags().Bool("apply", false, "Set every adapter setting to the gaming recommended value")
+	adaptersCmd.Flags().Bool("dry-run", false, "With --apply or --set, show what would change without changing anything")
+	adaptersCmd.Flags().String("set", "", "Turn settings on or off, as SETTING=on|off[,SETTING=on|off...]")
+	adaptersCmd.Flags().String("adapter", "", "Tune only this adapter, by name (default: all physical adapters)")
+	adaptersCmd.Flags().String("interrupt-moderation", "off", "With --apply, set interrupt moderation: on, off or keep")
+	adaptersCmd.Flags().Bool("restore", false, "Put back every adapter setting SysCleaner changed")
+	rootCmd.AddCommand(adaptersCmd)
+}
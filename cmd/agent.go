@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/agent"
+
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Auto-boost games in the background, even with SysCleaner closed",
+	Long: `The background agent runs the game watcher (see "gaming --watch") without a
+window. It is started by a scheduled task at every logon, so the active
+profile's gaming mode settings and hooks apply when a game starts whether
+or not SysCleaner is open. Config and profile changes are picked up once
+no game is running.
+
+The agent logs to agent.log next to the SysCleaner log file.
+
+Examples:
+  syscleaner agent --install
+  syscleaner agent --status
+  syscleaner agent --uninstall`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		install, _ := cmd.Flags().GetBool("install")
+		uninstall, _ := cmd.Flags().GetBool("uninstall")
+
+		switch {
+		case install:
+			if err := agent.Install(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Println("Background agent installed; it starts at every logon and is running now.")
+		case uninstall:
+			if err := agent.Uninstall(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Println("Background agent uninstalled.")
+			if pid, ok := agent.Running(); ok {
+				fmt.Printf("The running agent (PID %d) stops when you log off.\n", pid)
+			}
+		default:
+			installed, err := agent.Installed()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if installed {
+				fmt.Println("  Installed: yes (starts at logon)")
+			} else {
+				fmt.Println("  Installed: no")
+			}
+			if pid, ok := agent.Running(); ok {
+				fmt.Printf("  Running:   yes (PID %d)\n", pid)
+			} else {
+				fmt.Println("  Running:   no")
+			}
+		}
+	},
+}
+
+func init() {
+	agentCmd.Flags().Bool("install", false, "Start the agent at every logon, and now")
+	agentCmd.Flags().Bool("uninstall", false, "Stop starting the agent at logon")
+	agentCmd.Flags().Bool("status", false, "Show whether the agent is installed and running (default)")
+	rootCmd.AddCommand(agentCmd)
+}
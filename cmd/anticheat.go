@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/gaming"
+
+	"github.com/spf13/cobra"
+)
+
+var antiCheatCmd = &cobra.Command{
+	Use:   "anticheat-check",
+	Short: "Show running anti-cheats and the processes gaming mode leaves alone",
+	Long: `Looks for Easy Anti-Cheat, BattlEye, Riot Vanguard and Ricochet by their
+processes, services and drivers.
+
+While one is running, gaming mode never boosts, suspends, closes or stops
+the anti-cheat's processes and services, never suspends games, their child
+processes or other processes their launcher started, and skips tweaks that
+anti-cheats treat as tampering: game CPU affinity changes and priorities
+above "above normal". Each decision is logged.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		r := gaming.CheckAntiCheat()
+		if len(r.Active) == 0 {
+			fmt.Println("No anti-cheat is running.")
+		} else {
+			fmt.Println("Anti-cheats running:")
+			for _, ac := range r.Active {
+				fmt.Printf("  %-18s %s\n", ac.Name, strings.Join(ac.Found, ", "))
+			}
+		}
+		if len(r.Protected) == 0 {
+			return
+		}
+		fmt.Println()
+		fmt.Printf("%-8s %-32s %s\n", "PID", "Process", "Protected because")
+		fmt.Println(strings.Repeat("-", 75))
+		for _, p := range r.Protected {
+			fmt.Printf("%-8d %-32s %s\n", p.PID, p.Name, p.Reason)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(antiCheatCmd)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of files deleted and settings changed",
+	Long: `Lists destructive actions from the audit log: each batch of files deleted,
+each registry value changed, each service stopped and each process priority
+changed. The audit log is kept separately from the normal log and is only
+ever appended to.
+
+Each entry carries the operation ID of the clean, optimize or boost run that
+made it, and an undo token where the action can be reverted:
+
+  quarantine:<dir>        the files are in quarantine; see "syscleaner quarantine"
+  reg:<key>\<name>=<data> the .reg assignment that restores the old value
+  service-start:<name>    start the service again
+  priority:<pid>=<nice>   the process's priority before the change
+
+--action is one of delete, registry, service-stop or priority. --since takes
+a duration such as 2h, or a time such as "2024-03-01" or "2024-03-01 14:00".
+
+Examples:
+  syscleaner audit
+  syscleaner audit --action registry --since 168h
+  syscleaner audit --op clean-20240301-101500-3f9a
+  syscleaner audit --grep FontCache --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("lines")
+		since, _ := cmd.Flags().GetString("since")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		var filter logger.AuditFilter
+		filter.Action, _ = cmd.Flags().GetString("action")
+		filter.OperationID, _ = cmd.Flags().GetString("op")
+		filter.Grep, _ = cmd.Flags().GetString("grep")
+		switch filter.Action {
+		case "", logger.AuditDelete, logger.AuditRegistry, logger.AuditServiceStop, logger.AuditPriority:
+		default:
+			fmt.Printf("Error: unknown --action %q (want delete, registry, service-stop or priority)\n", filter.Action)
+			return
+		}
+		if since != "" {
+			var err error
+			if filter.Since, err = parseSince(since, time.Now()); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		entries, err := logger.ReadAudit(logger.AuditPath(), filter)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if entries == nil {
+				entries = []logger.AuditEntry{}
+			}
+			if err := enc.Encode(entries); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println("No audit entries found.")
+			return
+		}
+		fmt.Printf("%-19s %-12s %-36s %s\n", "Time", "Action", "Operation", "Target")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, e := range entries {
+			fmt.Printf("%-19s %-12s %-36s %s\n", e.Time.Format("2006-01-02 15:04:05"), e.Action, e.OperationID, e.Target)
+			if e.Detail != "" {
+				fmt.Printf("    %s\n", e.Detail)
+			}
+			if e.Undo != "" {
+				fmt.Printf("    undo: %s\n", e.Undo)
+			}
+		}
+	},
+}
+
+func init() {
+	auditCmd.Flags().IntP("lines", "n", 0, "Show only the newest N entries; 0 shows all")
+	auditCmd.Flags().String("action", "", "Only show this action: delete, registry, service-stop or priority")
+	auditCmd.Flags().String("op", "", "Only show entries from this operation ID")
+	auditCmd.Flags().String("since", "", "Only show entries after this duration ago or time")
+	auditCmd.Flags().String("grep", "", "Only show entries whose target or detail contains this text")
+	auditCmd.Flags().Bool("json", false, "Print entries as JSON")
+	rootCmd.AddCommand(auditCmd)
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"syscleaner/pkg/admin"
 	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
 
 	"github.com/spf13/cobra"
 )
@@ -13,15 +16,21 @@ var cleanCmd = &cobra.Command{
 	Short: "Clean system junk files and free disk space",
 	Long: `Remove temporary files, browser caches, log files, prefetch data, and thumbnails.
 
-You can select specific categories or use group flags like --all, --system, --browsers, --apps.`,
+You can select specific categories or use group flags like --all, --system, --browsers, --apps.
+Privacy items (--privacy) are never included in --all and must be selected explicitly.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		all, _ := cmd.Flags().GetBool("all")
 		systemGroup, _ := cmd.Flags().GetBool("system")
 		browsersGroup, _ := cmd.Flags().GetBool("browsers")
 		appsGroup, _ := cmd.Flags().GetBool("apps")
+		privacyGroup, _ := cmd.Flags().GetBool("privacy")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		dryRun = dryRun || admin.AuditMode()
+		reportPath, _ := cmd.Flags().GetString("report")
+		closeHolders, _ := cmd.Flags().GetBool("close-holders")
+		background, _ := cmd.Flags().GetBool("background")
 
-		opts := cleaner.CleanOptions{DryRun: dryRun}
+		opts := cleaner.CleanOptions{DryRun: dryRun, Background: background}
 
 		// Group flags
 		if all {
@@ -64,6 +73,14 @@ You can select specific categories or use group flags like --all, --system, --br
 			opts.TeamsCache = true
 			opts.VSCodeCache = true
 			opts.JavaCache = true
+			opts.StoreAppCache = true
+		}
+
+		if privacyGroup {
+			opts.RecentDocuments = true
+			opts.JumpLists = true
+			opts.ClipboardHistory = true
+			opts.ExplorerMRU = true
 		}
 
 		// Individual flags override groups
@@ -115,6 +132,9 @@ You can select specific categories or use group flags like --all, --system, --br
 		if cmd.Flags().Changed("recyclebin") {
 			opts.RecycleBin, _ = cmd.Flags().GetBool("recyclebin")
 		}
+		if cmd.Flags().Changed("font-icon-rebuild") {
+			opts.FontIconRebuild, _ = cmd.Flags().GetBool("font-icon-rebuild")
+		}
 		if cmd.Flags().Changed("chrome") {
 			opts.ChromeCache, _ = cmd.Flags().GetBool("chrome")
 		}
@@ -148,6 +168,47 @@ You can select specific categories or use group flags like --all, --system, --br
 		if cmd.Flags().Changed("java") {
 			opts.JavaCache, _ = cmd.Flags().GetBool("java")
 		}
+		if cmd.Flags().Changed("store-apps") {
+			opts.StoreAppCache, _ = cmd.Flags().GetBool("store-apps")
+		}
+		if cmd.Flags().Changed("recent") {
+			opts.RecentDocuments, _ = cmd.Flags().GetBool("recent")
+		}
+		if cmd.Flags().Changed("jumplists") {
+			opts.JumpLists, _ = cmd.Flags().GetBool("jumplists")
+		}
+		if cmd.Flags().Changed("clipboard") {
+			opts.ClipboardHistory, _ = cmd.Flags().GetBool("clipboard")
+		}
+		if cmd.Flags().Changed("mru") {
+			opts.ExplorerMRU, _ = cmd.Flags().GetBool("mru")
+		}
+
+		if cfg, err := config.LoadConfig(); err == nil {
+			cfg.Performance.Apply()
+			cfg.Cleaner.Apply()
+			cleaner.SetCloseLockHolders(closeHolders, cfg.EffectiveWhitelist())
+			opts.Volumes = cfg.ActiveVolumePolicy()
+		} else {
+			cleaner.SetCloseLockHolders(closeHolders, nil)
+		}
+		if cleaner.ForceDryRun {
+			dryRun = true
+			opts.DryRun = true
+		}
+
+		// Rule-pack rules: --rules all selects every loaded rule
+		rules, _ := cmd.Flags().GetStringSlice("rules")
+		for _, id := range rules {
+			if strings.EqualFold(id, "all") {
+				opts.Rules = nil
+				for _, r := range cleaner.Rules() {
+					opts.Rules = append(opts.Rules, r.ID)
+				}
+				break
+			}
+			opts.Rules = append(opts.Rules, id)
+		}
 
 		// Check if any category is selected
 		hasSelection := opts.WindowsTemp || opts.UserTemp || opts.WindowsUpdate ||
@@ -155,10 +216,13 @@ You can select specific categories or use group flags like --all, --system, --br
 			opts.ErrorReports || opts.ThumbnailCache || opts.IconCache ||
 			opts.FontCache || opts.ShaderCache || opts.DNSCache ||
 			opts.WindowsLogs || opts.EventLogs || opts.DeliveryOptimization ||
-			opts.RecycleBin || opts.ChromeCache || opts.FirefoxCache ||
+			opts.RecycleBin || opts.FontIconRebuild || opts.ChromeCache || opts.FirefoxCache ||
 			opts.EdgeCache || opts.BraveCache || opts.OperaCache ||
 			opts.DiscordCache || opts.SpotifyCache || opts.SteamCache ||
-			opts.TeamsCache || opts.VSCodeCache || opts.JavaCache
+			opts.TeamsCache || opts.VSCodeCache || opts.JavaCache || opts.StoreAppCache ||
+			opts.RecentDocuments || opts.JumpLists || opts.ClipboardHistory ||
+			opts.ExplorerMRU ||
+			len(opts.Rules) > 0
 
 		if !hasSelection {
 			fmt.Println("No cleaning targets specified.")
@@ -167,10 +231,19 @@ You can select specific categories or use group flags like --all, --system, --br
 			fmt.Println("  --system      : All system categories")
 			fmt.Println("  --browsers    : All browser categories")
 			fmt.Println("  --apps        : All application categories")
+			fmt.Println("  --privacy     : Recent documents, jump lists, clipboard, Explorer MRU")
 			fmt.Println("\nRun 'syscleaner clean --help' for a full list of categories.")
 			return
 		}
 
+		// Drop categories that need admin up front instead of letting
+		// each one fail separately.
+		var skips []admin.Skip
+		opts, skips = cleaner.PlanForPrivileges(opts, admin.IsElevated())
+		if offerElevation(skips, !background) {
+			return
+		}
+
 		if dryRun {
 			fmt.Println("[DRY RUN] Scanning files without deleting...")
 			fmt.Println()
@@ -189,8 +262,20 @@ You can select specific categories or use group flags like --all, --system, --br
 		fmt.Printf("  Files skipped: %d\n", result.SkippedFiles)
 		fmt.Printf("  Space freed:   %s\n", cleaner.FormatBytes(result.SpaceFreed))
 		fmt.Printf("  Time taken:    %s\n", result.Duration.Round(1e6))
+		fmt.Printf("  Operation:     %s\n", result.OperationID)
+		for _, cat := range result.CategoriesBySpace() {
+			c := result.Categories[cat]
+			if c.FilesDeleted > 0 || c.SpaceFreed > 0 {
+				fmt.Printf("    %-24s %10s  (%d files)\n", cat+":", cleaner.FormatBytes(c.SpaceFreed), c.FilesDeleted)
+			}
+		}
 		if result.LockedFiles > 0 {
 			fmt.Printf("  Skipped (in use): %d\n", result.LockedFiles)
+			for _, ce := range result.Locked {
+				if len(ce.Holders) > 0 {
+					fmt.Printf("    %s\n", ce)
+				}
+			}
 		}
 		if result.PermissionFiles > 0 {
 			fmt.Printf("  Permission errors: %d\n", result.PermissionFiles)
@@ -199,6 +284,25 @@ You can select specific categories or use group flags like --all, --system, --br
 			fmt.Printf("  Other errors:  %d\n", len(result.Errors))
 		}
 		fmt.Println()
+
+		if !dryRun {
+			if err := cleaner.SaveRunReport(cleaner.DefaultReportsDir(), result); err != nil {
+				fmt.Printf("Error saving run report: %v\n", err)
+			}
+		}
+
+		if reportPath != "" {
+			format, err := cleaner.ReportFormatFromPath(reportPath)
+			if err == nil {
+				err = result.Export(format, reportPath)
+			}
+			if err != nil {
+				fmt.Printf("Error writing report: %v\n", err)
+			} else {
+				fmt.Printf("Report written to %s\n\n", reportPath)
+			}
+		}
+
 		if dryRun {
 			fmt.Println("Run without --dry-run to actually delete files.")
 		} else {
@@ -213,6 +317,7 @@ func init() {
 	cleanCmd.Flags().Bool("system", false, "All system categories")
 	cleanCmd.Flags().Bool("browsers", false, "All browser categories")
 	cleanCmd.Flags().Bool("apps", false, "All application categories")
+	cleanCmd.Flags().Bool("privacy", false, "All privacy categories (not included in --all)")
 
 	// System category flags
 	cleanCmd.Flags().Bool("win-temp", false, "Windows Temp directory")
@@ -231,6 +336,7 @@ func init() {
 	cleanCmd.Flags().Bool("eventlogs", false, "Windows Event Logs")
 	cleanCmd.Flags().Bool("deliveryopt", false, "Delivery Optimization cache")
 	cleanCmd.Flags().Bool("recyclebin", false, "Recycle Bin")
+	cleanCmd.Flags().Bool("font-icon-rebuild", false, "Stop the Font Cache service and rebuild font and icon caches")
 
 	// Application category flags
 	cleanCmd.Flags().Bool("chrome", false, "Chrome cache")
@@ -244,9 +350,22 @@ func init() {
 	cleanCmd.Flags().Bool("teams", false, "Teams cache")
 	cleanCmd.Flags().Bool("vscode", false, "VS Code cache")
 	cleanCmd.Flags().Bool("java", false, "Java cache")
+	cleanCmd.Flags().Bool("store-apps", false, "Microsoft Store app caches (AC\\Temp and LocalCache)")
+
+	// Privacy category flags
+	cleanCmd.Flags().Bool("recent", false, "Recent documents list")
+	cleanCmd.Flags().Bool("jumplists", false, "Taskbar and Start jump lists")
+	cleanCmd.Flags().Bool("clipboard", false, "Clipboard contents and history")
+	cleanCmd.Flags().Bool("mru", false, "Explorer MRU lists (Run box, typed paths, search, open/save dialogs)")
+
+	// Rule-pack rules
+	cleanCmd.Flags().StringSlice("rules", nil, "Rule-pack rule IDs to run, or \"all\" (see 'syscleaner rules')")
 
 	// Execution options
 	cleanCmd.Flags().Bool("dry-run", false, "Show what would be cleaned without deleting")
+	cleanCmd.Flags().String("report", "", "Write a report to this file (.json, .csv, or .html)")
+	cleanCmd.Flags().Bool("close-holders", false, "Close non-whitelisted apps holding locked files and retry")
+	cleanCmd.Flags().Bool("background", false, "Run at low I/O priority and rate-limited (for scheduled cleans)")
 
 	rootCmd.AddCommand(cleanCmd)
 }
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"syscleaner/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change settings, or export and import them as a bundle",
+	Long: `Individual settings are read and changed with the get, set and list
+subcommands, using dotted keys such as ram_monitor.free_threshold_percent
+(camelCase like ramMonitor.freeThresholdPercent works too).
+
+A settings bundle holds the config file (including game overrides), every
+saved profile, and the rule packs that define custom clean targets. Use it
+to move settings to another machine or share a tuned setup.
+
+Importing replaces the config and overwrites profiles and rule packs with
+the same name; the previous config is kept as config.yaml.pre-import.bak.
+Game overrides and profile hooks in the bundle are checked like a shared
+game profile: a bundle that stops an anti-cheat, audio or input service is
+refused, and pre-launch tools and hooks run with administrator rights, so
+a bundle with any is refused unless --allow-tools is given.
+
+Use --path to show which config file is in use and how it was found. A
+machine-wide config in %ProgramData%\SysCleaner\config.yaml, if present,
+supplies the defaults that the per-user file overrides.
+
+Every save keeps the previous config file as a numbered backup
+(config.yaml.bak1 is the newest). List them with --backups and bring one
+back with --restore-backup; the config being replaced becomes backup 1.
+
+Examples:
+  syscleaner config list ram_monitor
+  syscleaner config get log_level
+  syscleaner config set ramMonitor.freeThresholdPercent 20
+  syscleaner config --path
+  syscleaner config --backups
+  syscleaner config --restore-backup 2
+  syscleaner config --export settings.scbundle
+  syscleaner config --import settings.scbundle`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exportPath, _ := cmd.Flags().GetString("export")
+		importPath, _ := cmd.Flags().GetString("import")
+		showPath, _ := cmd.Flags().GetBool("path")
+		listBackups, _ := cmd.Flags().GetBool("backups")
+		restore, _ := cmd.Flags().GetInt("restore-backup")
+		allowTools, _ := cmd.Flags().GetBool("allow-tools")
+
+		switch {
+		case showPath:
+			path, source, err := config.ResolveConfigPath()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("%s (%s)\n", path, source)
+			if machine := config.MachineConfigPath(); machine != "" {
+				if _, err := os.Stat(machine); err == nil {
+					fmt.Printf("Layered over machine config: %s\n", machine)
+				}
+			}
+		case listBackups:
+			backups, err := config.ListConfigBackups()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if len(backups) == 0 {
+				fmt.Println("No config backups yet.")
+				return
+			}
+			for _, b := range backups {
+				fmt.Printf("  %d  %s  %s\n", b.N, b.ModTime.Format("2006-01-02 15:04:05"), b.Path)
+			}
+		case restore > 0:
+			if err := config.RestoreConfigBackup(restore); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Config restored from backup %d.\n", restore)
+		case exportPath != "":
+			if err := config.ExportBundle(exportPath); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Settings exported to %s\n", exportPath)
+		case importPath != "":
+			risks, err := config.ImportBundle(importPath, allowTools)
+			if len(risks) > 0 {
+				fmt.Println("Risks:")
+				for _, r := range risks {
+					fmt.Printf("  %s\n", r)
+				}
+				fmt.Println()
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Settings imported from %s\n", importPath)
+		default:
+			cmd.Help()
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print one setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+		s, err := cfg.Setting(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Println(s.Value)
+		if s.Source != "file" {
+			fmt.Printf("(set by %s)\n", s.Source)
+		}
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change one setting in the config file",
+	Long: `Changes one setting and saves the config file. Values are JSON; strings
+can be given without quotes and lists of names as comma-separated items:
+
+  syscleaner config set log_level debug
+  syscleaner config set process_whitelist obs64.exe,Discord.exe
+  syscleaner config set low_disk '{"enabled": true, "threshold_gb": 20}'
+
+The new value is checked the same way as when the config is loaded, and
+refused if it would be ignored or replaced.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := config.SetSetting(args[0], args[1])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("%s = %s\n", s.Key, s.Value)
+		if cfg, err := config.LoadConfig(); err == nil {
+			if eff, err := cfg.Setting(s.Key); err == nil && eff.Source != "file" {
+				fmt.Printf("Note: %s is currently set by %s to %s.\n", s.Key, eff.Source, eff.Value)
+			}
+			if s.Key == "process_whitelist" {
+				printWhitelistWarnings(cfg)
+			}
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config for settings that will be ignored or look wrong",
+	Long: `Loads the config, reporting any entries that are dropped as invalid, and
+checks the process whitelist (global plus the active profile's) against
+known and running process names, suggesting fixes for likely typos:
+
+  Warning: process whitelist: "explorer.ex" matches no known or running process; did you mean "explorer.exe"?
+
+An entry for an app that is simply not running right now is also flagged;
+ignore the warning if the name is right.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+		if printWhitelistWarnings(cfg) == 0 {
+			fmt.Println("No problems found.")
+		}
+	},
+}
+
+// printWhitelistWarnings prints the whitelist warnings for cfg and returns
+// how many there were.
+func printWhitelistWarnings(cfg *config.Config) int {
+	warnings := cfg.WhitelistWarnings()
+	for _, w := range warnings {
+		fmt.Printf("Warning: process whitelist: %s\n", w)
+	}
+	return len(warnings)
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "Print every setting, or those under a key",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+		settings, err := cfg.Settings()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		prefix := ""
+		if len(args) == 1 {
+			s, err := cfg.Setting(args[0])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			prefix = s.Key
+		}
+		for _, s := range settings {
+			if prefix != "" && s.Key != prefix && !strings.HasPrefix(s.Key, prefix+".") {
+				continue
+			}
+			line := fmt.Sprintf("%s = %s", s.Key, s.Value)
+			if s.Source != "file" {
+				line += fmt.Sprintf("  (%s)", s.Source)
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd, configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+	configCmd.Flags().String("export", "", "Write config, profiles and rule packs to this file")
+	configCmd.Flags().String("import", "", "Load config, profiles and rule packs from this bundle file")
+	configCmd.Flags().Bool("allow-tools", false, "With --import, allow pre-launch tools and profile hooks, which run as administrator")
+	configCmd.Flags().Bool("backups", false, "List the rotating backups of the config file")
+	configCmd.Flags().Int("restore-backup", 0, "Replace the config with backup N (1 is the newest)")
+	configCmd.Flags().Bool("path", false, "Print the config file in use and where it was found")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/conflicts"
+
+	"github.com/spf13/cobra"
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Detect other cleaners whose schedules overlap with SysCleaner",
+	Long: `Checks for Storage Sense and scheduled tasks from other cleaners (CCleaner,
+Wise Disk Cleaner, Glary Utilities, ...) that clean the same folders or run
+on low disk space like the SysCleaner low disk trigger.
+
+Examples:
+  syscleaner conflicts
+  syscleaner conflicts --disable-storage-sense
+  syscleaner conflicts --disable-low-disk`,
+	Run: func(cmd *cobra.Command, args []string) {
+		disableSense, _ := cmd.Flags().GetBool("disable-storage-sense")
+		disableLowDisk, _ := cmd.Flags().GetBool("disable-low-disk")
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if disableSense {
+			if err := conflicts.DisableStorageSense(); err != nil {
+				fmt.Printf("Error disabling Storage Sense: %v\n", err)
+			} else {
+				fmt.Println("Storage Sense disabled.")
+			}
+		}
+		if disableLowDisk {
+			cfg.LowDisk.Enabled = false
+			if err := config.SaveConfig(cfg); err != nil {
+				fmt.Printf("Error saving config: %v\n", err)
+			} else {
+				fmt.Println("SysCleaner low disk trigger disabled.")
+			}
+		}
+		if disableSense || disableLowDisk {
+			return
+		}
+
+		sources := conflicts.Detect()
+		if len(sources) == 0 {
+			fmt.Println("No other cleaners detected.")
+			return
+		}
+
+		fmt.Println("Other cleaners on this machine:")
+		for _, s := range sources {
+			fmt.Printf("  %-24s %s\n", s.Name, s.Schedule)
+		}
+
+		found := conflicts.Analyze(sources, cfg.LowDisk.Enabled)
+		if len(found) == 0 {
+			fmt.Println("\nNo overlapping schedules.")
+			return
+		}
+		fmt.Println("\nOverlaps:")
+		for _, c := range found {
+			fmt.Printf("  - %s\n", c)
+		}
+	},
+}
+
+func init() {
+	conflictsCmd.Flags().Bool("disable-storage-sense", false, "Turn off Storage Sense for the current user")
+	conflictsCmd.Flags().Bool("disable-low-disk", false, "Turn off the SysCleaner low disk trigger")
+
+	rootCmd.AddCommand(conflictsCmd)
+}
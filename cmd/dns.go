@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/dns"
+
+	"github.com/spf13/cobra"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Benchmark DNS resolvers and switch adapters to the fastest",
+	Long: `Show the DNS servers of the connected network adapters, time public
+resolvers (Cloudflare, Google, Quad9, OpenDNS and AdGuard) against the
+adapters' current servers, and switch to one.
+
+--benchmark looks up a list of popular sites, game stores and chat
+services on each resolver and ranks them by median lookup time; a resolver
+that fails more than one lookup in ten is ranked last. --apply sets a
+resolver by name, or "fastest" to benchmark first and pick the winner, on
+every connected adapter or on the one named with --adapter. --dhcp puts an
+adapter back on the DNS servers its network hands out.
+
+Changing DNS servers needs administrator rights.
+
+Examples:
+  syscleaner dns --benchmark
+  syscleaner dns --apply fastest
+  syscleaner dns --apply cloudflare --adapter Ethernet
+  syscleaner dns --dhcp`,
+	Run: func(cmd *cobra.Command, args []string) {
+		benchmark, _ := cmd.Flags().GetBool("benchmark")
+		apply, _ := cmd.Flags().GetString("apply")
+		dhcp, _ := cmd.Flags().GetBool("dhcp")
+		adapter, _ := cmd.Flags().GetString("adapter")
+		rounds, _ := cmd.Flags().GetInt("rounds")
+
+		if (apply != "" || dhcp) && !admin.AuditMode() {
+			if err := admin.RequireElevation("Changing DNS servers"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		switch {
+		case dhcp:
+			if admin.AuditMode() {
+				fmt.Printf("Would reset the DNS servers of %s to DHCP (audit mode)\n", adapterLabel(adapter))
+				return
+			}
+			if err := dns.ResetToDHCP(adapter); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("DNS servers of %s now come from DHCP.\n", adapterLabel(adapter))
+		case apply != "":
+			var r dns.Resolver
+			if strings.EqualFold(apply, "fastest") {
+				best, isCurrent, ok := runDNSBenchmark(rounds)
+				if !ok {
+					fmt.Println("Error: no resolver answered reliably; DNS servers left unchanged")
+					return
+				}
+				if isCurrent {
+					fmt.Println("The current DNS servers are already the fastest; left unchanged.")
+					return
+				}
+				r = best
+				fmt.Println()
+			} else {
+				var err error
+				if r, err = dns.Find(apply); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+			}
+			if admin.AuditMode() {
+				fmt.Printf("Would set the DNS servers of %s to %s (%s) (audit mode)\n",
+					adapterLabel(adapter), r.Name, strings.Join(r.Servers(), ", "))
+				return
+			}
+			if err := dns.Apply(adapter, r); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("DNS servers of %s set to %s (%s).\n", adapterLabel(adapter), r.Name, strings.Join(r.Servers(), ", "))
+			fmt.Println("Restore with: syscleaner dns --dhcp")
+		case benchmark:
+			if best, isCurrent, ok := runDNSBenchmark(rounds); ok && !isCurrent {
+				fmt.Printf("\nSwitch with: syscleaner dns --apply %s\n", strings.ToLower(best.Name))
+			}
+		default:
+			printDNSAdapters()
+		}
+	},
+}
+
+func adapterLabel(adapter string) string {
+	if adapter == "" {
+		return "all connected adapters"
+	}
+	return adapter
+}
+
+// runDNSBenchmark times the public resolvers and the adapters' current
+// servers, prints the ranking and returns the recommended resolver, and
+// whether it is one of the current servers, leaving nothing to switch to.
+func runDNSBenchmark(rounds int) (best dns.Resolver, isCurrent, ok bool) {
+	resolvers := append([]dns.Resolver(nil), dns.PublicResolvers...)
+	current := make(map[string]bool)
+	if adapters, err := dns.Adapters(); err == nil {
+		public := make(map[string]bool)
+		for _, r := range dns.PublicResolvers {
+			public[r.Primary], public[r.Secondary] = true, true
+		}
+		for _, a := range adapters {
+			if len(a.Servers) == 0 || public[a.Servers[0]] || current[a.Servers[0]] {
+				continue
+			}
+			current[a.Servers[0]] = true
+			resolvers = append(resolvers, dns.Resolver{Name: "Current (" + a.Alias + ")", Primary: a.Servers[0]})
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Printf("Timing %d resolver(s) on %d domains, %d round(s)...\n\n", len(resolvers), len(dns.SampleDomains), rounds)
+	results := dns.Benchmark(ctx, resolvers, dns.SampleDomains, rounds)
+
+	fmt.Printf("%-28s %-16s %8s %8s %s\n", "Resolver", "Server", "Median", "Average", "Failed")
+	fmt.Println(strings.Repeat("-", 72))
+	for _, r := range results {
+		fmt.Printf("%-28s %-16s %8s %8s %d/%d\n", r.Resolver.Name, r.Resolver.Primary,
+			r.Median.Round(100*time.Microsecond), r.Average.Round(100*time.Microsecond), r.Failed, r.Queries)
+	}
+	winner, ok := dns.Recommend(results)
+	if !ok {
+		fmt.Println("\nNo resolver answered reliably.")
+		return dns.Resolver{}, false, false
+	}
+	fmt.Printf("\nRecommended: %s (%s, median %v)\n", winner.Resolver.Name, winner.Resolver.Primary, winner.Median.Round(100*time.Microsecond))
+	return winner.Resolver, current[winner.Resolver.Primary], true
+}
+
+func printDNSAdapters() {
+	adapters, err := dns.Adapters()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(adapters) == 0 {
+		fmt.Println("No connected network adapters found.")
+		return
+	}
+	for _, a := range adapters {
+		source := "from DHCP"
+		if a.Static {
+			source = "set by hand"
+		}
+		servers := strings.Join(a.Servers, ", ")
+		if servers == "" {
+			servers = "none"
+		}
+		fmt.Printf("  %-24s %s (%s)\n", a.Alias, servers, source)
+	}
+	fmt.Println("\nCompare resolvers with: syscleaner dns --benchmark")
+}
+
+func init() {
+	dnsCmd.Flags().Bool("benchmark", false, "Time the public resolvers and the current DNS servers and recommend the fastest")
+	dnsCmd.Flags().String("apply", "", "Set the DNS servers to a resolver: cloudflare, google, quad9, opendns, adguard or fastest")
+	dnsCmd.Flags().Bool("dhcp", false, "Take the DNS servers from DHCP again")
+	dnsCmd.Flags().String("adapter", "", "Change only this adapter, by name (default: all connected adapters)")
+	dnsCmd.Flags().Int("rounds", 3, "How many times to look up each domain when benchmarking")
+	rootCmd.AddCommand(dnsCmd)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"syscleaner/pkg/admin"
+)
+
+// offerElevation lists what will be skipped without administrator rights
+// and, when prompt is set, asks once whether to restart elevated with the
+// same arguments. It returns true if an elevated copy was started, in which
+// case the caller should stop.
+func offerElevation(skips []admin.Skip, prompt bool) bool {
+	if len(skips) == 0 {
+		return false
+	}
+	fmt.Println("Not running as administrator. These will be skipped:")
+	fmt.Print(admin.FormatSkips(skips))
+	if !prompt {
+		fmt.Println()
+		return false
+	}
+
+	fmt.Print("Restart as administrator to include them? [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Println()
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return false
+	}
+	if err := admin.Relaunch(os.Args[1:]); err != nil {
+		fmt.Printf("Could not restart as administrator: %v\nContinuing without elevation.\n\n", err)
+		return false
+	}
+	fmt.Println("Continuing in the elevated window.")
+	return true
+}
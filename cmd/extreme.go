@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"syscleaner/pkg/config"
 	"syscleaner/pkg/gaming"
 
 	"github.com/spf13/cobra"
@@ -14,29 +16,85 @@ var extremeCmd = &cobra.Command{
 	Long: `Extreme performance mode stops Windows Explorer and all non-essential services
 for maximum gaming performance. Anti-cheat services are preserved.
 
-WARNING: This mode removes the desktop shell. Use the GUI launcher to start games.`,
+It has three levels, set with --level or the profile's extreme_level:
+
+  light     close background apps
+  medium    also stop non-essential services
+  extreme   also stop Windows Explorer and visual effects (the default)
+
+Everything the level stops is listed before it is activated; --plan lists
+it without activating.
+
+WARNING: The extreme level removes the desktop shell. Use the GUI launcher to start games.
+
+--enable leaves extreme mode on after SysCleaner exits, until --disable.
+A watchdog process restarts Explorer and the stopped services if SysCleaner
+crashes before it is done enabling it, or while the GUI has it on.
+
+The services and apps stopped come from extreme_stop_list in the config, or
+a built-in list (see --list). Each entry has a risk level; entries for
+audio, anti-cheat and input are critical and are only stopped when the
+entry sets "force": true.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		enable, _ := cmd.Flags().GetBool("enable")
 		disable, _ := cmd.Flags().GetBool("disable")
 		showStatus, _ := cmd.Flags().GetBool("status")
+		list, _ := cmd.Flags().GetBool("list")
+		plan, _ := cmd.Flags().GetBool("plan")
+		level, _ := cmd.Flags().GetString("level")
 
-		if enable {
-			fmt.Println("Enabling extreme performance mode...")
+		cfg, err := config.LoadConfig()
+		if err == nil {
+			gaming.ProcessWhitelist = cfg.EffectiveWhitelist()
+			gaming.SetExtremeStopList(cfg.ExtremeStopList)
+			if !cmd.Flags().Changed("level") {
+				level = cfg.ActiveProfileSettings().GamingConfig.ExtremeLevel
+			}
+		}
+		if err := gaming.ValidateExtremeLevel(level); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if list {
+			printStopList()
+		} else if plan {
+			fmt.Println(gaming.PlanExtremeMode(level))
+		} else if enable {
+			p := gaming.PlanExtremeMode(level)
+			fmt.Printf("Enabling extreme performance mode (%s)...\n", p.Level)
 			fmt.Println()
-			fmt.Println("WARNING: This will stop Windows Explorer (no desktop/taskbar).")
+			fmt.Println(p)
+			fmt.Println()
+			if p.StopsShell {
+				fmt.Println("WARNING: This will stop Windows Explorer (no desktop/taskbar).")
+			}
 			fmt.Println("Use 'syscleaner extreme --disable' to restore.")
 			fmt.Println()
 
-			if err := gaming.EnableExtremeMode(); err != nil {
+			if cfg != nil {
+				cfg.EffectiveRAMMonitor().Apply()
+			}
+			if err := gaming.EnableExtremeMode(level); err != nil {
 				fmt.Printf("  Error: %v\n", err)
 				return
 			}
+			// Extreme mode stays on after this run exits; the watchdog
+			// must not take that for a crash.
+			gaming.DetachSession()
 
-			fmt.Println("  Stopped Windows Explorer")
-			fmt.Println("  Stopped all non-essential services")
+			fmt.Println("  Closed background apps")
+			if len(p.Services) > 0 {
+				fmt.Println("  Stopped non-essential services")
+			}
+			if p.StopsShell {
+				fmt.Println("  Stopped Windows Explorer")
+			}
 			fmt.Println("  Enabled anti-cheat services")
 			fmt.Println("  Set ultimate performance power plan")
-			fmt.Println("  Disabled visual effects")
+			if p.StopsShell {
+				fmt.Println("  Disabled visual effects")
+			}
 			fmt.Println()
 			fmt.Println("EXTREME PERFORMANCE MODE is now ACTIVE")
 		} else if disable {
@@ -51,7 +109,7 @@ WARNING: This mode removes the desktop shell. Use the GUI launcher to start game
 			fmt.Println("  Restored Windows Explorer")
 			fmt.Println("  Restored services")
 			fmt.Println("  Re-enabled visual effects")
-			fmt.Println("  Restored balanced power plan")
+			fmt.Println("  Restored the previous power plan")
 			fmt.Println()
 			fmt.Println("System restored to normal mode.")
 		} else if showStatus {
@@ -62,15 +120,35 @@ WARNING: This mode removes the desktop shell. Use the GUI launcher to start game
 	},
 }
 
+// printStopList shows what extreme mode stops, with each entry's risk.
+func printStopList() {
+	fmt.Printf("%-28s %-8s %-9s %s\n", "Name", "Kind", "Risk", "Note")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, e := range gaming.ExtremeStopList() {
+		kind := gaming.StopService
+		if e.IsProcess() {
+			kind = gaming.StopProcess
+		}
+		note := e.Note
+		if e.EffectiveRisk() == gaming.RiskCritical && !e.Force {
+			note = "not stopped (critical)"
+		}
+		fmt.Printf("%-28s %-8s %-9s %s\n", e.Name, kind, e.EffectiveRisk(), note)
+	}
+}
+
 func printExtremeStatus() {
 	fmt.Println("--- Extreme Performance Mode Status ---")
 	fmt.Println()
 
-	if gaming.IsExtremeModeActive() {
+	if level := gaming.ExtremeModeLevel(); level != "" {
 		fmt.Println("  Status:  ACTIVE")
 		fmt.Println()
-		fmt.Println("  Windows Explorer: STOPPED")
-		fmt.Println("  Visual Effects:   DISABLED")
+		fmt.Printf("  Level:            %s\n", level)
+		if level == gaming.ExtremeFull {
+			fmt.Println("  Windows Explorer: STOPPED")
+			fmt.Println("  Visual Effects:   DISABLED")
+		}
 		fmt.Println("  Power Plan:       Ultimate Performance")
 	} else {
 		fmt.Println("  Status:  INACTIVE")
@@ -88,5 +166,8 @@ func init() {
 	extremeCmd.Flags().Bool("enable", false, "Enable extreme performance mode")
 	extremeCmd.Flags().Bool("disable", false, "Disable extreme performance mode")
 	extremeCmd.Flags().Bool("status", false, "Show extreme mode status")
+	extremeCmd.Flags().Bool("list", false, "List the services and apps extreme mode stops, with their risk")
+	extremeCmd.Flags().Bool("plan", false, "List what the level would stop, without activating it")
+	extremeCmd.Flags().String("level", "", "Level: light, medium or extreme (default: the profile's extreme_level, else extreme)")
 	rootCmd.AddCommand(extremeCmd)
 }
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+
+	"github.com/spf13/cobra"
+)
+
+var gameProfileCmd = &cobra.Command{
+	Use:   "game-profile",
+	Short: "Share tuned per-game settings as game profile files",
+	Long: `A game profile holds one game's override from game_overrides (priority,
+affinity, services to stop and keep, pre-launch cleans and tools) in a JSON
+file other players can import. The file format is described by the JSON
+Schema printed by "syscleaner game-profile schema".
+
+Imports are checked before they are saved. A profile that stops an
+anti-cheat, audio or input service is refused. Pre-launch tools run with
+administrator rights, so a profile with any is refused unless --allow-tools
+is given; read the listed commands before allowing them.
+
+Examples:
+  syscleaner game-profile export cs2.exe cs2.json --author me
+  syscleaner game-profile import cs2.json
+  syscleaner game-profile schema > game-profile.schema.json`,
+}
+
+var gameProfileExportCmd = &cobra.Command{
+	Use:   "export <game> <file>",
+	Short: "Write a game's override to a game profile file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		author, _ := cmd.Flags().GetString("author")
+		notes, _ := cmd.Flags().GetString("notes")
+		if err := config.ExportGameProfile(args[0], author, notes, args[1]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Game profile for %s exported to %s\n", args[0], args[1])
+	},
+}
+
+var gameProfileImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Check a game profile file and save it as the game's override",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		allowTools, _ := cmd.Flags().GetBool("allow-tools")
+		p, risks, err := config.ImportGameProfile(args[0], allowTools)
+		if len(risks) > 0 {
+			fmt.Println("Risks:")
+			for _, r := range risks {
+				fmt.Printf("  %s\n", r)
+			}
+			fmt.Println()
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Game profile for %s imported", p.Game)
+		if p.Author != "" {
+			fmt.Printf(" (by %s)", p.Author)
+		}
+		fmt.Println()
+		if p.Notes != "" {
+			fmt.Printf("  %s\n", p.Notes)
+		}
+	},
+}
+
+var gameProfileSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for game profile files",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Stdout.Write(gaming.SharedProfileSchema())
+	},
+}
+
+func init() {
+	gameProfileExportCmd.Flags().String("author", "", "Name to credit in the profile")
+	gameProfileExportCmd.Flags().String("notes", "", "Notes for whoever imports the profile")
+	gameProfileImportCmd.Flags().Bool("allow-tools", false, "Import the profile's pre-launch tools, which run as administrator")
+
+	gameProfileCmd.AddCommand(gameProfileExportCmd, gameProfileImportCmd, gameProfileSchemaCmd)
+	rootCmd.AddCommand(gameProfileCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+
+	"github.com/spf13/cobra"
+)
+
+var gamesCmd = &cobra.Command{
+	Use:   "games",
+	Short: "List the games SysCleaner detects, or update the game database",
+	Long: `Games are detected by executable using a game database. A built-in copy
+ships with SysCleaner; a newer copy saved as games.json in the SysCleaner
+config folder replaces it, so new releases can be detected without a new
+SysCleaner build.
+
+Game databases must be signed by a key listed under game_database.keys in
+the config. --update downloads one from game_database.url (or --url) and
+keeps it only if the signature checks out and it is newer than the
+database in use.
+
+Examples:
+  syscleaner games
+  syscleaner games --update
+  syscleaner games --update --url https://example.com/games.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		update, _ := cmd.Flags().GetBool("update")
+		url, _ := cmd.Flags().GetString("url")
+
+		if update {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				fmt.Printf("Error loading config: %v\n", err)
+				return
+			}
+			if url == "" {
+				url = cfg.GameDatabase.URL
+			}
+			if url == "" {
+				fmt.Println("Error: no URL given and game_database.url is not set")
+				return
+			}
+			keys := cfg.GameDatabase.TrustedKeys()
+			if len(keys) == 0 {
+				fmt.Println("Error: no game database keys are configured (game_database.keys)")
+				return
+			}
+			before, _ := gaming.GameDatabaseInfo()
+			after, err := gaming.UpdateGameDatabase(url, keys)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if after > before {
+				fmt.Printf("Game database updated to version %d\n", after)
+			} else {
+				fmt.Printf("Game database version %d is already the newest\n", before)
+			}
+			return
+		}
+
+		version, source := gaming.GameDatabaseInfo()
+		if source == "" {
+			source = "built-in"
+		}
+		fmt.Printf("Game database version %d (%s)\n\n", version, source)
+		fmt.Printf("%-28s %-14s %s\n", "Game", "Priority", "Executables")
+		fmt.Println(strings.Repeat("-", 75))
+		for _, g := range gaming.Games() {
+			fmt.Printf("%-28s %-14s %s\n", g.Name, g.CPUPriority, strings.Join(g.Executables, ", "))
+		}
+	},
+}
+
+func init() {
+	gamesCmd.Flags().Bool("update", false, "Download a newer signed game database")
+	gamesCmd.Flags().String("url", "", "Where --update downloads from (default game_database.url)")
+	rootCmd.AddCommand(gamesCmd)
+}
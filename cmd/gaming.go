@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
 
 	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
 	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/launcher"
+	"syscleaner/pkg/monitor"
 
 	"github.com/spf13/cobra"
 )
@@ -12,7 +20,50 @@ import (
 var gamingCmd = &cobra.Command{
 	Use:   "gaming",
 	Short: "Gaming mode - optimize system for gaming performance",
-	Long:  `Enable gaming mode to stop background services, boost game process priority, and optimize network settings.`,
+	Long: `Enable gaming mode to stop background services, boost game process priority, and optimize network settings.
+
+--enable leaves gaming mode on after SysCleaner exits, until --disable.
+If SysCleaner crashes while enabling it, the next run restores the
+settings it changed.
+
+With --watch, SysCleaner stays running and turns gaming mode on by itself
+when a known game starts, and off again when it exits. Known games are the
+built-in ones, games installed through a supported launcher (see --library)
+and exes with a game_overrides entry. Overrides can also be keyed by the
+name --library shows.
+
+With --discrete-gpu, detected games are set to run on the high-performance
+GPU (Settings > Display > Graphics). The setting applies from a game's next
+launch and stays after gaming mode is disabled; --restore-gpu puts back the
+previous settings.
+
+A profile with high_res_timer set raises the system timer resolution to
+0.5 ms. Windows drops the request when SysCleaner exits, so it only lasts
+while the GUI or --watch is running; --status shows the current value.
+
+Servers listed under server_endpoints in the profile ("host:port") are
+probed while their game runs, recording latency, jitter and loss so
+network stutter can be told apart from local stutter. --latency probes
+the --game's servers on demand.
+
+A streamer profile (type "streamer", see the streamer preset) keeps OBS
+and other streaming apps at high priority alongside the game, optionally
+pinned to streaming.encoder_cores, and never suspends or closes capture,
+camera and audio processes.
+
+A profile with usb_latency set turns off USB selective suspend and lets no
+mouse or keyboard be powered down while gaming mode is on, so an input
+device idle for a moment does not wake with a delay. Both are restored
+when gaming mode is disabled.
+
+A profile with trim_working_sets set empties the working sets of background
+processes that are not whitelisted each time a game is detected, pushing
+their pages out so the game gets the memory. The total reclaimed is shown
+by 'syscleaner sessions'.
+
+--benchmark measures free RAM, the standby list, the process count, free
+disk space and timer latency (a rough stand-in for DPC latency), enables
+gaming mode and measures again, then prints the two side by side.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		enable, _ := cmd.Flags().GetBool("enable")
 		disable, _ := cmd.Flags().GetBool("disable")
@@ -20,24 +71,151 @@ var gamingCmd = &cobra.Command{
 		autoDetect, _ := cmd.Flags().GetBool("auto-detect")
 		cpuBoost, _ := cmd.Flags().GetInt("cpu-boost")
 		ramReserve, _ := cmd.Flags().GetInt("ram-reserve")
+		focus, _ := cmd.Flags().GetBool("focus")
+		game, _ := cmd.Flags().GetString("game")
+		watch, _ := cmd.Flags().GetBool("watch")
+		library, _ := cmd.Flags().GetBool("library")
+		discreteGPU, _ := cmd.Flags().GetBool("discrete-gpu")
+		restoreGPU, _ := cmd.Flags().GetBool("restore-gpu")
 
-		if enable {
+		suspendApps, _ := cmd.Flags().GetBool("suspend-apps")
+		latency, _ := cmd.Flags().GetBool("latency")
+		benchmark, _ := cmd.Flags().GetBool("benchmark")
+
+		var profile *config.Profile
+		var whitelist []string
+		if cfg, err := config.LoadConfig(); err == nil {
+			gaming.SetGameOverrides(cfg.GameOverrides)
+			whitelist = cfg.EffectiveWhitelist()
+			cfg.EffectiveRAMMonitor().Apply()
+			if cfg.ActiveProfile != "" {
+				if p, err := config.LoadProfile(cfg.ActiveProfile); err == nil {
+					profile = p
+				}
+			}
+		}
+
+		var suspend []string
+		var gameSettings gaming.GameSettings
+		var highResTimer, purgeStandby, usbLatency, trimWorkingSets bool
+		var streaming *gaming.StreamingConfig
+		powerPlan, _ := cmd.Flags().GetString("power-plan")
+		if profile != nil {
+			gameSettings = profile.GamingConfig.WindowsGameSettings
+			highResTimer = profile.GamingConfig.HighResTimer
+			purgeStandby = profile.GamingConfig.PurgeStandby
+			usbLatency = profile.GamingConfig.USBLatency
+			trimWorkingSets = profile.GamingConfig.TrimWorkingSets
+			streaming = profile.StreamingConfig()
+			gaming.SetQoSOverrides(profile.GamingConfig.QoSDSCP)
+			gaming.SetLatencyTargets(profile.GamingConfig.ServerEndpoints)
+			suspend = profile.GamingConfig.SuspendWhileGaming
+			if !cmd.Flags().Changed("suspend-apps") {
+				suspendApps = profile.GamingConfig.SuspendBackgroundApps
+			}
+			if !cmd.Flags().Changed("focus") {
+				focus = profile.GamingConfig.FocusMode
+			}
+			if !cmd.Flags().Changed("power-plan") && profile.GamingConfig.PowerPlan != "" {
+				powerPlan = profile.GamingConfig.PowerPlan
+			}
+			if !cmd.Flags().Changed("discrete-gpu") {
+				discreteGPU = profile.GamingConfig.DiscreteGPU
+			}
+		}
+		config := gaming.Config{
+			AutoDetectGames:       autoDetect,
+			CPUBoost:              cpuBoost,
+			RAMReserveGB:          ramReserve,
+			SuspendProcesses:      suspend,
+			SuspendBackgroundApps: suspendApps,
+			Whitelist:             whitelist,
+			FocusMode:             focus,
+			DiscreteGPU:           discreteGPU,
+			GameSettings:          gameSettings,
+			HighResTimer:          highResTimer,
+			PowerPlan:             powerPlan,
+			PurgeStandby:          purgeStandby,
+			USBLatency:            usbLatency,
+			TrimWorkingSets:       trimWorkingSets,
+			Streaming:             streaming,
+			Game:                  game,
+		}
+
+		if err := gaming.ValidatePowerPlan(powerPlan); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if restoreGPU {
+			n, err := gaming.RestoreGPUPreferences()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Restored the GPU preference of %d game(s)\n", n)
+		} else if latency {
+			probeLatency(game)
+		} else if library {
+			printInstalledGames()
+		} else if watch {
+			watchGames(config, profile)
+		} else if benchmark {
+			runBenchmark(config)
+		} else if enable {
 			fmt.Println("Enabling gaming mode...")
 			fmt.Println()
-			config := gaming.Config{
-				AutoDetectGames: autoDetect,
-				CPUBoost:        cpuBoost,
-				RAMReserveGB:    ramReserve,
-			}
 			if err := gaming.Enable(config); err != nil {
 				fmt.Printf("  Error: %v\n", err)
 				return
 			}
+			// Gaming mode stays on after this run exits; the next run must
+			// not take that for a crash.
+			gaming.DetachSession()
 			fmt.Println("  Stopped background services")
-			fmt.Println("  Set high performance power plan")
+			switch strings.ToLower(powerPlan) {
+			case gaming.PowerPlanNone:
+			case gaming.PowerPlanUltimate:
+				fmt.Println("  Set ultimate performance power plan")
+			default:
+				fmt.Println("  Set high performance power plan")
+			}
 			fmt.Println("  Optimized network settings")
+			if len(suspend) > 0 {
+				fmt.Printf("  Suspended background apps: %s\n", strings.Join(suspend, ", "))
+			}
+			if suspendApps {
+				fmt.Println("  Suspended browsers, chat and sync apps that are not whitelisted")
+			}
+			if focus {
+				fmt.Println("  Suppressed notifications and focus stealing")
+			}
+			if purgeStandby {
+				fmt.Println("  Purged standby memory")
+			}
+			if usbLatency {
+				fmt.Println("  Disabled USB selective suspend and input device power saving")
+			}
+			if gameSettings != (gaming.GameSettings{}) {
+				fmt.Println("  Applied the profile's Windows Game Mode, Game DVR and Game Bar settings")
+			}
+			if discreteGPU {
+				fmt.Println("  Detected games will use the high-performance GPU from their next launch")
+			}
+			if _, ok := gaming.OverrideFor(game); game != "" && ok {
+				fmt.Printf("  Applied game overrides for %s\n", game)
+			}
 			if autoDetect {
 				fmt.Println("  Game auto-detection enabled")
+				if trimWorkingSets {
+					fmt.Println("  Background working sets will be trimmed when a game starts")
+				}
+			}
+			if streaming != nil {
+				fmt.Println("  Streamer mode: streaming apps boosted, capture and audio left running")
+			}
+			if profile != nil {
+				runGamingHooks(profile, profile.Hooks.GamingEnable)
 			}
 			fmt.Println()
 			fmt.Println("Gaming mode is now ACTIVE")
@@ -49,8 +227,13 @@ var gamingCmd = &cobra.Command{
 				return
 			}
 			fmt.Println("  Restarted background services")
-			fmt.Println("  Restored balanced power plan")
+			fmt.Println("  Restored the previous power plan")
 			fmt.Println("  Restored process priorities")
+			fmt.Println("  Resumed suspended apps")
+			fmt.Println("  Restored notification and focus settings")
+			if profile != nil {
+				runGamingHooks(profile, profile.Hooks.GamingDisable)
+			}
 			fmt.Println()
 			fmt.Println("Gaming mode is now DISABLED")
 		} else if showStatus {
@@ -61,6 +244,112 @@ var gamingCmd = &cobra.Command{
 	},
 }
 
+// runBenchmark enables gaming mode between two sets of measurements and
+// prints how they compare. Gaming mode is left on.
+func runBenchmark(cfg gaming.Config) {
+	fmt.Println("Measuring, enabling gaming mode and measuring again...")
+	fmt.Println()
+	report, err := gaming.Benchmark(func() error { return gaming.Enable(cfg) })
+	gaming.DetachSession()
+	if err != nil {
+		fmt.Printf("  Error: %v\n", err)
+		return
+	}
+	fmt.Print(report)
+	fmt.Println()
+	fmt.Println("Gaming mode is now ACTIVE. Use 'syscleaner gaming --disable' to restore.")
+}
+
+// watchGames turns gaming mode on whenever a known game starts and off
+// when it exits, until Ctrl+C.
+func watchGames(cfg gaming.Config, profile *config.Profile) {
+	w := gaming.NewWatcher(cfg)
+	w.OnChange = func(game string, started bool, err error) {
+		switch {
+		case err != nil:
+			fmt.Printf("  Error: %v\n", err)
+		case started:
+			fmt.Printf("  %s started: gaming mode is now ACTIVE\n", game)
+			if profile != nil {
+				runGamingHooks(profile, profile.Hooks.GamingEnable)
+			}
+		default:
+			fmt.Printf("  %s exited: gaming mode is now DISABLED\n", game)
+			if profile != nil {
+				runGamingHooks(profile, profile.Hooks.GamingDisable)
+			}
+		}
+	}
+
+	fmt.Println("Watching for games. Press Ctrl+C to stop.")
+	fmt.Println()
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if err := w.Run(ctx); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// probeLatency measures the latency of game's configured servers until
+// Ctrl+C, printing the results every few seconds.
+func probeLatency(game string) {
+	targets := gaming.LatencyTargetsFor(game)
+	if len(targets) == 0 {
+		fmt.Printf("Error: no server_endpoints configured for %q in the active profile\n", game)
+		return
+	}
+	fmt.Printf("Probing %s servers. Press Ctrl+C to stop.\n", game)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	probe := &monitor.LatencyProbe{Targets: targets}
+	go probe.Run(ctx)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Println()
+			for _, s := range probe.Stats() {
+				fmt.Printf("  %s\n", s)
+			}
+		}
+	}
+}
+
+// printInstalledGames lists the games found in launcher libraries.
+func printInstalledGames() {
+	games := gaming.ScanLibraries()
+	if len(games) == 0 {
+		fmt.Println("No installed games found.")
+		return
+	}
+	fmt.Printf("%-32s %-12s %s\n", "Game", "Launcher", "Executable")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, g := range games {
+		exe := ""
+		if len(g.Executables) > 0 {
+			exe = g.Executables[0]
+		}
+		fmt.Printf("%-32s %-12s %s\n", g.Name, g.Launcher, exe)
+	}
+}
+
+// runGamingHooks launches a profile's gaming hook actions and reports
+// each failure.
+func runGamingHooks(profile *config.Profile, actions []launcher.Action) {
+	if len(actions) == 0 {
+		return
+	}
+	errs := profile.RunHooks(actions)
+	fmt.Printf("  Ran %d of %d hook action(s)\n", len(actions)-len(errs), len(actions))
+	for _, err := range errs {
+		fmt.Printf("    Error: %v\n", err)
+	}
+}
+
 func printGamingStatus() {
 	status := gaming.GetStatus()
 
@@ -80,6 +369,9 @@ func printGamingStatus() {
 		status.RAMUsagePercent,
 		cleaner.FormatBytes(int64(status.RAMUsed)),
 		cleaner.FormatBytes(int64(status.RAMTotal)))
+	if res, err := monitor.CheckTimerResolution(); err == nil {
+		fmt.Printf("    Timer:      %s\n", res)
+	}
 	fmt.Println()
 
 	if len(status.ActiveGames) > 0 {
@@ -109,5 +401,15 @@ func init() {
 	gamingCmd.Flags().Bool("auto-detect", true, "Auto-detect and boost game processes")
 	gamingCmd.Flags().Int("cpu-boost", 80, "CPU boost percentage (0-100)")
 	gamingCmd.Flags().Int("ram-reserve", 2, "GB of RAM to reserve for system")
+	gamingCmd.Flags().Bool("suspend-apps", false, "Suspend browsers, Electron apps and sync clients until gaming mode is disabled")
+	gamingCmd.Flags().Bool("focus", false, "Suppress notifications, sticky-keys prompts and focus stealing")
+	gamingCmd.Flags().String("game", "", "Game about to be played (name or exe); applies its game_overrides entry")
+	gamingCmd.Flags().Bool("watch", false, "Stay running, enabling gaming mode while a known game runs")
+	gamingCmd.Flags().Bool("latency", false, "Measure latency, jitter and loss to the --game's server_endpoints until Ctrl+C")
+	gamingCmd.Flags().Bool("benchmark", false, "Enable gaming mode, comparing free RAM, standby memory, processes, disk space and timer latency before and after")
+	gamingCmd.Flags().Bool("library", false, "List games installed through supported launchers")
+	gamingCmd.Flags().Bool("discrete-gpu", false, "Set detected games to run on the high-performance GPU")
+	gamingCmd.Flags().String("power-plan", gaming.PowerPlanHigh, "Power plan while gaming: high, ultimate (created if hidden) or none")
+	gamingCmd.Flags().Bool("restore-gpu", false, "Restore the GPU preferences changed by --discrete-gpu")
 	rootCmd.AddCommand(gamingCmd)
 }
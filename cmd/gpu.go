@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/monitor"
+
+	"github.com/spf13/cobra"
+)
+
+var gpuCmd = &cobra.Command{
+	Use:   "gpu",
+	Short: "Show the GPU power state and apps keeping it awake",
+	Long: `Samples the GPU's performance state, clocks, video memory, temperature
+(NVIDIA only) and per-process load. When the system is idle, apps still
+using the GPU (hardware-accelerated browsers, animated wallpapers,
+overlays) keep it out of its low-power state. While a game runs, a GPU
+at full load means the game is GPU-bound, and nearly full video memory
+often explains stutter.
+
+Apps listed with --suspend are added to the active profile and suspended
+while gaming mode is on.
+
+Examples:
+  syscleaner gpu
+  syscleaner gpu --suspend wallpaper64.exe`,
+	Run: func(cmd *cobra.Command, args []string) {
+		suspend, _ := cmd.Flags().GetStringSlice("suspend")
+
+		if len(suspend) > 0 {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				fmt.Printf("Error loading config: %v\n", err)
+				return
+			}
+			for _, exe := range suspend {
+				if err := config.AddSuspendWhileGaming(cfg.ActiveProfile, exe); err != nil {
+					fmt.Printf("Error saving profile: %v\n", err)
+					return
+				}
+				fmt.Printf("%s will be suspended while gaming mode is on (profile %q).\n", exe, cfg.ActiveProfile)
+			}
+			return
+		}
+
+		fmt.Println("Sampling GPU usage...")
+		status := monitor.CheckGPU()
+		fmt.Println()
+
+		if status.Name != "" {
+			fmt.Printf("  GPU:          %s\n", status.Name)
+		}
+		if status.PowerState != "" {
+			fmt.Printf("  Power state:  %s\n", status.PowerState)
+			fmt.Printf("  Clocks:       %d MHz core, %d MHz memory\n", status.GraphicsClockMHz, status.MemoryClockMHz)
+		}
+		fmt.Printf("  Utilization:  %.1f%%", status.Utilization)
+		if status.GPUBound() {
+			fmt.Print(" (GPU-bound)")
+		}
+		fmt.Println()
+		if status.VRAMTotal > 0 {
+			fmt.Printf("  VRAM:         %s / %s (%.0f%%)", cleaner.FormatBytes(int64(status.VRAMUsed)),
+				cleaner.FormatBytes(int64(status.VRAMTotal)), status.VRAMPercent())
+			if status.VRAMPressure() {
+				fmt.Print(" - nearly full, expect stutter")
+			}
+			fmt.Println()
+		} else if status.VRAMUsed > 0 {
+			fmt.Printf("  VRAM:         %s in use\n", cleaner.FormatBytes(int64(status.VRAMUsed)))
+		}
+		if status.TemperatureC > 0 {
+			fmt.Printf("  Temperature:  %d°C\n", status.TemperatureC)
+		}
+		fmt.Println()
+
+		if len(status.Processes) == 0 {
+			fmt.Println("No apps are keeping the GPU busy.")
+			return
+		}
+		if status.Awake {
+			fmt.Println("The GPU is being kept awake by:")
+		} else {
+			fmt.Println("Apps using the GPU:")
+		}
+		for _, p := range status.Processes {
+			name := p.Name
+			if name == "" {
+				name = "(unknown)"
+			}
+			fmt.Printf("  %-28s PID %-7d %.1f%%\n", name, p.PID, p.Utilization)
+		}
+		fmt.Println("\nUse --suspend <name> to suspend an app while gaming mode is on.")
+	},
+}
+
+func init() {
+	gpuCmd.Flags().StringSlice("suspend", nil, "Suspend these executables while gaming mode is on")
+
+	rootCmd.AddCommand(gpuCmd)
+}
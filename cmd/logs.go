@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"time"
+
+	"syscleaner/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the end of the log file, optionally filtered",
+	Long: `Prints the last lines of the SysCleaner log file, so problems can be
+reported without finding it in AppData.
+
+--level drops lines below a level (debug, info, warn, error). --since takes
+a duration such as 2h, or a time such as "2024-03-01" or "2024-03-01 14:00".
+--grep takes a regular expression; add (?i) to ignore case. With --follow,
+new lines are printed as they are written until Ctrl+C.
+
+Lines written during a clean, optimize or boost run are tagged with its
+operation ID, which is also shown in the run's summary and report; grep
+for it to see just that run.
+
+If SysCleaner crashes, the stack trace and the last log lines are saved to
+a crash report in the crashes folder next to the log. --crash prints the
+newest one.
+
+Examples:
+  syscleaner logs
+  syscleaner logs --level warn --since 24h
+  syscleaner logs --grep "(?i)access denied" --lines 0
+  syscleaner logs --grep clean-20240301-101500-3f9a --lines 0
+  syscleaner logs --follow`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, _ := cmd.Flags().GetString("file")
+		lines, _ := cmd.Flags().GetInt("lines")
+		level, _ := cmd.Flags().GetString("level")
+		since, _ := cmd.Flags().GetString("since")
+		grep, _ := cmd.Flags().GetString("grep")
+		follow, _ := cmd.Flags().GetBool("follow")
+		crash, _ := cmd.Flags().GetBool("crash")
+
+		if crash {
+			reports, err := logger.CrashReports()
+			if err != nil || len(reports) == 0 {
+				fmt.Println("No crash reports found.")
+				return
+			}
+			data, err := os.ReadFile(reports[0])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("%s:\n\n%s", reports[0], data)
+			logger.AcknowledgeCrashReport()
+			return
+		}
+
+		filter := logger.Filter{MinLevel: logger.LevelDebug}
+		var err error
+		if level != "" {
+			if filter.MinLevel, err = logger.ParseLevel(level); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+		if since != "" {
+			if filter.Since, err = parseSince(since, time.Now()); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+		if grep != "" {
+			if filter.Grep, err = regexp.Compile(grep); err != nil {
+				fmt.Printf("Error: invalid --grep pattern: %v\n", err)
+				return
+			}
+		}
+		if path == "" {
+			path = logger.DefaultLogPath()
+		}
+
+		entries, offset, err := logger.Tail(path, lines, filter)
+		// When following, a log file that does not exist yet is waited for.
+		if err != nil && !(follow && errors.Is(err, os.ErrNotExist)) {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		for _, e := range entries {
+			fmt.Println(e.Line)
+		}
+		if !follow {
+			return
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if err := logger.Follow(ctx, path, offset, filter, func(e logger.Entry) {
+			fmt.Println(e.Line)
+		}); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	},
+}
+
+// parseSince turns a --since value, either a duration before now or a
+// local date and time, into a time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want a duration such as 2h or a time such as 2024-03-01 14:00", s)
+}
+
+func init() {
+	logsCmd.Flags().String("file", "", "Log file to read (default: the SysCleaner log in the user config directory)")
+	logsCmd.Flags().IntP("lines", "n", 50, "Number of lines to show; 0 shows all")
+	logsCmd.Flags().String("level", "", "Minimum level to show: debug, info, warn or error")
+	logsCmd.Flags().String("since", "", "Only show lines after this duration ago or time")
+	logsCmd.Flags().String("grep", "", "Only show lines matching this regular expression")
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep printing new lines as they are written")
+	logsCmd.Flags().Bool("crash", false, "Print the newest crash report instead")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/optimizer"
+
+	"github.com/spf13/cobra"
+)
+
+var mtuCmd = &cobra.Command{
+	Use:   "mtu",
+	Short: "Probe the path MTU and set interface MTUs",
+	Long: `Show the MTU of each connected interface, find the largest packet that
+reaches a server unfragmented, and set an interface's MTU to it.
+
+An MTU larger than the path allows is a common cause of game disconnects
+and stalled downloads on PPPoE connections (1492) and VPNs (often 1400 or
+less), where packets too big for the tunnel are dropped. --probe pings
+--endpoint (default ` + optimizer.DefaultMTUEndpoint + `) with Don't Fragment set at
+different sizes; the server must answer ping. Use a game server's address
+to test the path games take.
+
+--set changes the MTU of the interface named with --interface. The MTU each
+interface had first is kept, and --restore puts them all back.
+
+Changing an MTU needs administrator rights.
+
+Examples:
+  syscleaner mtu --probe
+  syscleaner mtu --probe --endpoint 8.8.8.8
+  syscleaner mtu --set 1492 --interface Ethernet`,
+	Run: func(cmd *cobra.Command, args []string) {
+		probe, _ := cmd.Flags().GetBool("probe")
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		set, _ := cmd.Flags().GetInt("set")
+		iface, _ := cmd.Flags().GetString("interface")
+		restore, _ := cmd.Flags().GetBool("restore")
+
+		if restore || set != 0 {
+			if err := admin.RequireElevation("Changing the MTU"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		switch {
+		case restore:
+			n, err := optimizer.RestoreMTU()
+			fmt.Printf("Restored the MTU of %d interface(s)\n", n)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		case set != 0:
+			if iface == "" {
+				fmt.Println("Error: --set needs --interface; list them with: syscleaner mtu")
+				return
+			}
+			if err := optimizer.SetMTU(iface, set); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Set the MTU of %s to %d\n", iface, set)
+			fmt.Println("Restore with: syscleaner mtu --restore")
+		case probe:
+			probePathMTU(endpoint)
+		default:
+			printMTUs()
+		}
+	},
+}
+
+func probePathMTU(endpoint string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if endpoint == "" {
+		endpoint = optimizer.DefaultMTUEndpoint
+	}
+	fmt.Printf("Probing the path MTU to %s...\n", endpoint)
+	p, err := optimizer.ProbePathMTU(ctx, endpoint)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Path MTU to %s (%s): %d\n", p.Endpoint, p.Address, p.MTU)
+
+	interfaces, err := optimizer.ListIPInterfaces()
+	if err != nil {
+		return
+	}
+	for _, i := range interfaces {
+		if i.MTU > p.MTU {
+			fmt.Printf("  %s has MTU %d, larger than the path allows.\n", i.Alias, i.MTU)
+			fmt.Printf("  If it carries this traffic, set it with: syscleaner mtu --set %d --interface \"%s\"\n", p.MTU, i.Alias)
+		}
+	}
+}
+
+func printMTUs() {
+	interfaces, err := optimizer.ListIPInterfaces()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(interfaces) == 0 {
+		fmt.Println("No connected network interfaces found.")
+		return
+	}
+	original, _ := optimizer.OriginalMTUs()
+	for _, i := range interfaces {
+		fmt.Printf("  %-32s MTU %d", i.Alias, i.MTU)
+		if was, ok := original[i.Alias]; ok {
+			fmt.Printf(" (was %d before SysCleaner)", was)
+		}
+		fmt.Println()
+	}
+	fmt.Println("\nFind the path MTU with: syscleaner mtu --probe")
+}
+
+func init() {
+	mtuCmd.Flags().Bool("probe", false, "Find the largest packet that reaches --endpoint unfragmented")
+	mtuCmd.Flags().String("endpoint", "", "Host or IPv4 address to probe (default "+optimizer.DefaultMTUEndpoint+")")
+	mtuCmd.Flags().Int("set", 0, "Set the MTU of the interface named with --interface")
+	mtuCmd.Flags().String("interface", "", "Interface for --set, by name")
+	mtuCmd.Flags().Bool("restore", false, "Put back the MTU of every interface SysCleaner changed")
+	rootCmd.AddCommand(mtuCmd)
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 
+	"syscleaner/pkg/admin"
 	"syscleaner/pkg/optimizer"
+	"syscleaner/pkg/reglog"
 
 	"github.com/spf13/cobra"
 )
@@ -11,54 +13,256 @@ import (
 var optimizeCmd = &cobra.Command{
 	Use:   "optimize",
 	Short: "Optimize system performance",
-	Long:  `Optimize startup programs, network settings, and disk performance.`,
+	Long: `Optimize startup programs, network settings, and disk performance.
+
+Every registry value an optimization sets or deletes is journaled with its
+previous data, as are the TCP auto-tuning level and ECN setting changed
+with netsh. --undo-last puts back the values of the newest run that has not
+been undone, --undo puts back those of the run with the given operation ID
+and --journal lists the runs. The other netsh TCP settings and the disk
+schedule are not undone.
+
+--network also applies the MMCSS gaming profile: network throttling off,
+10% of CPU time reserved for background work instead of 20%, and the Games
+task's GPU priority, CPU priority and scheduling category raised. Its
+registry values are journaled and undone like the others.
+
+--disable-nagle, with --network, sets TcpNoDelay and TcpAckFrequency on
+every interface with an address, so interactive games' small packets are
+sent and acknowledged at once instead of batched. It adds traffic, so it is
+off unless asked for; its values are journaled and undone like the others.
+
+--privacy, which --all leaves out, sets diagnostic data to the minimum
+level the Windows edition supports (Security on Enterprise and Education,
+Required elsewhere) and turns off tailored experiences and the advertising
+ID. Its registry values are journaled and undone like the others.
+
+--visual, also left out of --all, switches Windows visual effects to
+"Adjust for best performance"; with --keep-font-smoothing text stays
+smoothed. Undone effects show fully after signing out and back in.
+
+--power, left out of --all too, switches to the High Performance plan (or
+Ultimate Performance with --ultimate) and turns off USB selective suspend
+on AC power. Power plans are not registry values; the run prints the plan
+to switch back to with "syscleaner power --activate".
+
+Before a run first writes to a registry key it exports the key, subkeys
+included, to a timestamped .reg file, kept apart from the journal.
+--backups lists those files and --restore-backup imports one, putting the
+keys back as they were before that run. Values the run added to a key that
+already existed are left; --undo is the exact way back.
+
+--dry-run lists the startup entries, registry values, netsh settings and
+disk maintenance a run would change, with each value's current data,
+without changing anything.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		all, _ := cmd.Flags().GetBool("all")
 		startup, _ := cmd.Flags().GetBool("startup")
 		network, _ := cmd.Flags().GetBool("network")
 		disk, _ := cmd.Flags().GetBool("disk")
+		privacy, _ := cmd.Flags().GetBool("privacy")
+		visual, _ := cmd.Flags().GetBool("visual")
+		keepFonts, _ := cmd.Flags().GetBool("keep-font-smoothing")
+		powerPlan, _ := cmd.Flags().GetBool("power")
+		ultimate, _ := cmd.Flags().GetBool("ultimate")
+		noNagle, _ := cmd.Flags().GetBool("disable-nagle")
+		undoLast, _ := cmd.Flags().GetBool("undo-last")
+		undoID, _ := cmd.Flags().GetString("undo")
+		journal, _ := cmd.Flags().GetBool("journal")
+		backups, _ := cmd.Flags().GetBool("backups")
+		restoreBackup, _ := cmd.Flags().GetString("restore-backup")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		dryRun = dryRun || admin.AuditMode()
+
+		switch {
+		case journal:
+			printOptimizerJournals()
+			return
+		case undoLast:
+			j, err := optimizer.LastJournal()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			undoOptimization(j.ID)
+			return
+		case undoID != "":
+			undoOptimization(undoID)
+			return
+		case backups:
+			printRegistryBackups()
+			return
+		case restoreBackup != "":
+			if err := admin.RequireElevation("Restoring a registry backup"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if err := optimizer.RestoreRegistryBackup(restoreBackup); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Imported registry backup %s.\n", restoreBackup)
+			return
+		}
 
 		if all {
 			startup, network, disk = true, true, true
 		}
 
-		if !startup && !network && !disk {
-			fmt.Println("No optimization targets specified. Use --all or specify targets (--startup, --network, --disk)")
+		if !startup && !network && !disk && !privacy && !visual && !powerPlan {
+			fmt.Println("No optimization targets specified. Use --all or specify targets (--startup, --network, --disk, --privacy, --visual, --power)")
 			return
 		}
 
-		fmt.Println("Starting system optimization...")
+		// A preview only reads, so it needs no administrator rights.
+		if !dryRun {
+			var skips []admin.Skip
+			startup, network, disk, skips = optimizer.PlanForPrivileges(startup, network, disk, admin.IsElevated())
+			if offerElevation(skips, true) {
+				return
+			}
+			if !startup && !network && !disk && !privacy && !visual && !powerPlan {
+				fmt.Println("Nothing left to run without administrator rights.")
+				return
+			}
+		}
+
+		opts := optimizer.OptimizeOptions{
+			DryRun:            dryRun,
+			KeepFontSmoothing: keepFonts,
+			UltimatePower:     ultimate,
+			DisableNagle:      noNagle,
+		}
+		if dryRun {
+			fmt.Println("Previewing system optimization (dry run, nothing will be changed)...")
+		} else {
+			fmt.Println("Starting system optimization...")
+		}
 		fmt.Println()
 
 		if startup {
 			fmt.Println("--- Startup Optimization ---")
-			result := optimizer.OptimizeStartup()
+			result := optimizer.OptimizeStartup(opts)
 			optimizer.PrintStartupResult(result)
 			fmt.Println()
 		}
 
 		if network {
 			fmt.Println("--- Network Optimization ---")
-			result := optimizer.OptimizeNetwork()
+			result := optimizer.OptimizeNetwork(opts)
 			optimizer.PrintNetworkResult(result)
 			fmt.Println()
 		}
 
 		if disk {
 			fmt.Println("--- Disk Optimization ---")
-			result := optimizer.OptimizeDisk()
+			result := optimizer.OptimizeDisk(opts)
 			optimizer.PrintDiskResult(result)
 			fmt.Println()
 		}
 
+		if privacy {
+			fmt.Println("--- Privacy Optimization ---")
+			result := optimizer.OptimizePrivacy(opts)
+			optimizer.PrintPrivacyResult(result)
+			fmt.Println()
+		}
+
+		if visual {
+			fmt.Println("--- Visual Effects Optimization ---")
+			result := optimizer.OptimizeVisualEffects(opts)
+			optimizer.PrintVisualEffectsResult(result)
+			fmt.Println()
+		}
+
+		if powerPlan {
+			fmt.Println("--- Power Plan Optimization ---")
+			result := optimizer.OptimizePower(opts)
+			optimizer.PrintPowerResult(result)
+			fmt.Println()
+		}
+
+		if dryRun {
+			fmt.Println("Run without --dry-run to apply these changes.")
+			return
+		}
 		fmt.Println("Optimization complete!")
 	},
 }
 
+// undoOptimization puts back the registry values and TCP settings an
+// optimizer run changed.
+func undoOptimization(id string) {
+	fmt.Printf("Undoing optimizer run %s...\n", id)
+	n, err := optimizer.Undo(id)
+	fmt.Printf("  Restored %d setting(s)\n", n)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// printOptimizerJournals lists the journaled optimizer runs.
+func printOptimizerJournals() {
+	journals, err := optimizer.Journals()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(journals) == 0 {
+		fmt.Println("No optimizer changes recorded.")
+		return
+	}
+	for _, j := range journals {
+		state := "can be undone"
+		if !j.Undone.IsZero() {
+			state = "undone " + j.Undone.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%s  %s  %d change(s), %s\n", j.Started.Local().Format("2006-01-02 15:04"), j.ID, j.Count(), state)
+		if j.Backup != "" {
+			fmt.Printf("    Registry backup: %s\n", j.Backup)
+		}
+		for _, c := range j.Changes {
+			fmt.Printf("    %s\n", reglog.FormatChangeLine(c))
+		}
+		for _, c := range j.TCPChanges {
+			fmt.Printf("    %s\n", c)
+		}
+	}
+}
+
+// printRegistryBackups lists the registry backups optimizer runs exported.
+func printRegistryBackups() {
+	backups, err := optimizer.RegistryBackups()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(backups) == 0 {
+		fmt.Println("No registry backups.")
+		return
+	}
+	for _, b := range backups {
+		fmt.Printf("%s  %s  (%d KB)\n", b.Created.Format("2006-01-02 15:04"), b.Name, (b.Size+1023)/1024)
+	}
+	fmt.Println("\nRestore one with: syscleaner optimize --restore-backup NAME")
+}
+
 func init() {
 	optimizeCmd.Flags().Bool("all", false, "Run all optimizations")
 	optimizeCmd.Flags().Bool("startup", false, "Optimize startup programs")
 	optimizeCmd.Flags().Bool("network", false, "Optimize network settings")
 	optimizeCmd.Flags().Bool("disk", false, "Optimize disk performance")
+	optimizeCmd.Flags().Bool("privacy", false, "Minimize diagnostic data and turn off tailored experiences and the advertising ID")
+	optimizeCmd.Flags().Bool("visual", false, "Switch Windows visual effects to best performance")
+	optimizeCmd.Flags().Bool("keep-font-smoothing", false, "With --visual, keep font smoothing on")
+	optimizeCmd.Flags().Bool("power", false, "Switch to the High Performance power plan and keep USB devices awake on AC power")
+	optimizeCmd.Flags().Bool("ultimate", false, "With --power, use the Ultimate Performance plan")
+	optimizeCmd.Flags().Bool("disable-nagle", false, "With --network, turn off Nagle's algorithm on every interface for interactive games")
+	optimizeCmd.Flags().Bool("dry-run", false, "Show what would be changed without changing anything")
+	optimizeCmd.Flags().Bool("undo-last", false, "Put back the registry values and TCP settings changed by the last optimizer run")
+	optimizeCmd.Flags().String("undo", "", "Put back the registry values and TCP settings changed by the optimizer run with this operation ID")
+	optimizeCmd.Flags().Bool("journal", false, "List the optimizer runs and the settings each changed")
+	optimizeCmd.Flags().Bool("backups", false, "List the .reg backups of the registry keys optimizer runs changed")
+	optimizeCmd.Flags().String("restore-backup", "", "Import the registry backup with this name, as --backups lists it")
 	rootCmd.AddCommand(optimizeCmd)
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/optimizer"
+
+	"github.com/spf13/cobra"
+)
+
+var pagefileCmd = &cobra.Command{
+	Use:   "pagefile",
+	Short: "Show and configure the page file",
+	Long: `Show the page file settings and usage, and replace them with one
+fixed-size page file on the fastest drive with room.
+
+A system managed page file on a nearly full system drive grows and shrinks
+while games run, a common cause of stutter. The recommended size is one and
+a half times RAM up to 8 GB of RAM and as much as RAM above that, between
+4 and 16 GB. SSDs are preferred, then the system drive, which Windows needs
+a page file on to save a memory dump after a crash.
+
+Without flags, shows the current settings and the recommendation. --apply
+sets it up (with --dry-run, only shows it); --drive and --size choose the
+drive and size in MB instead. The settings from before SysCleaner first
+changed them are kept, and --restore puts them back. Page file changes take
+effect after a restart.
+
+Changing the page file needs administrator rights.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		drive, _ := cmd.Flags().GetString("drive")
+		size, _ := cmd.Flags().GetInt("size")
+		restore, _ := cmd.Flags().GetBool("restore")
+		dryRun = dryRun || admin.AuditMode()
+
+		if restore || apply && !dryRun {
+			if err := admin.RequireElevation("Changing the page file"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		switch {
+		case restore:
+			if err := optimizer.RestorePageFile(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Println("Restored the page file settings; restart to apply them.")
+		case apply:
+			if dryRun {
+				fmt.Println("Previewing page file configuration (dry run, nothing will be changed)...")
+			}
+			optimizer.PrintPageFileResult(optimizer.ConfigurePageFile(drive, size, optimizer.OptimizeOptions{DryRun: dryRun}))
+		default:
+			status, err := optimizer.PageFileInfo()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Println("Current page file settings:")
+			optimizer.PrintPageFileStatus(status)
+			plan, err := optimizer.PlanPageFile(drive, size)
+			if err != nil {
+				fmt.Printf("\nNo recommendation: %v\n", err)
+				return
+			}
+			fmt.Printf("\nRecommended: a fixed %d MB page file on %s\n", plan.SizeMB, plan.Drive)
+			for _, w := range plan.Warnings {
+				fmt.Printf("  Warning: %s\n", w)
+			}
+			fmt.Println("Apply with: syscleaner pagefile --apply")
+		}
+	},
+}
+
+func init() {
+	pagefileCmd.Flags().Bool("apply", false, "Replace the page files with one fixed-size page file")
+	pagefileCmd.Flags().Bool("dry-run", false, "With --apply, show the page file that would be set up without changing anything")
+	pagefileCmd.Flags().String("drive", "", "Drive for the page file, e.g. D: (default: the fastest drive with room)")
+	pagefileCmd.Flags().Int("size", 0, "Page file size in MB (default: sized from RAM)")
+	pagefileCmd.Flags().Bool("restore", false, "Put back the page file settings from before SysCleaner changed them")
+	rootCmd.AddCommand(pagefileCmd)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/power"
+
+	"github.com/spf13/cobra"
+)
+
+var powerCmd = &cobra.Command{
+	Use:   "power",
+	Short: "List, create, and switch power plans and tune their settings",
+	Long: `Manage Windows power plans.
+
+Without flags, lists the installed plans and the tuned settings of the
+active one. --activate switches to a plan, by name or GUID. --ultimate
+switches to Ultimate Performance, first creating a copy of it when Windows
+hides the plan.
+
+--processor-min, --processor-max and --usb-suspend change the active plan's
+settings on AC power; battery settings are left alone.
+
+In audit mode (--audit) the changes are only shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		activate, _ := cmd.Flags().GetString("activate")
+		ultimate, _ := cmd.Flags().GetBool("ultimate")
+
+		switch {
+		case ultimate && admin.AuditMode():
+			fmt.Println("Audit mode: would activate Ultimate Performance, creating it if Windows hides it")
+		case ultimate:
+			guid, err := power.EnsureUltimate()
+			if err == nil {
+				err = power.Activate(guid)
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Activated Ultimate Performance (%s)\n", guid)
+		case activate != "":
+			plan, err := power.Find(activate)
+			if err == nil && admin.AuditMode() {
+				fmt.Printf("Audit mode: would activate %s (%s)\n", plan.Name, plan.GUID)
+				break
+			}
+			if err == nil {
+				err = power.Activate(plan.GUID)
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Activated %s (%s)\n", plan.Name, plan.GUID)
+		}
+
+		changed, ok := tunePowerSettings(cmd)
+		if !ok || changed || ultimate || activate != "" {
+			return
+		}
+		printPowerPlans()
+	},
+}
+
+// tunePowerSettings applies the setting flags given to the active plan. It
+// reports whether any were given and whether all of them succeeded.
+func tunePowerSettings(cmd *cobra.Command) (changed, ok bool) {
+	type tune struct {
+		flag    string
+		setting power.Setting
+		value   uint32
+	}
+	var tunes []tune
+	for _, f := range []struct {
+		flag    string
+		setting power.Setting
+	}{{"processor-min", power.ProcessorMinState}, {"processor-max", power.ProcessorMaxState}} {
+		if !cmd.Flags().Changed(f.flag) {
+			continue
+		}
+		pct, _ := cmd.Flags().GetInt(f.flag)
+		if pct < 0 || pct > 100 {
+			fmt.Printf("Error: --%s takes a percentage from 0 to 100\n", f.flag)
+			return true, false
+		}
+		tunes = append(tunes, tune{f.flag, f.setting, uint32(pct)})
+	}
+	if cmd.Flags().Changed("usb-suspend") {
+		val, _ := cmd.Flags().GetString("usb-suspend")
+		switch strings.ToLower(val) {
+		case "on":
+			tunes = append(tunes, tune{"usb-suspend", power.USBSelectiveSuspend, 1})
+		case "off":
+			tunes = append(tunes, tune{"usb-suspend", power.USBSelectiveSuspend, 0})
+		default:
+			fmt.Println("Error: --usb-suspend takes on or off")
+			return true, false
+		}
+	}
+	if len(tunes) == 0 {
+		return false, true
+	}
+
+	plan, err := power.Active()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return true, false
+	}
+	ok = true
+	for _, t := range tunes {
+		ac, dc, err := power.Read(plan, t.setting)
+		if err == nil && admin.AuditMode() {
+			fmt.Printf("Audit mode: would set %s to %d on AC power (now %d)\n", t.flag, t.value, ac)
+			continue
+		}
+		if err == nil {
+			err = power.Write(plan, t.setting, t.value, dc)
+		}
+		if err != nil {
+			fmt.Printf("Error: --%s: %v\n", t.flag, err)
+			ok = false
+			continue
+		}
+		fmt.Printf("Set %s to %d on AC power\n", t.flag, t.value)
+	}
+	return true, ok
+}
+
+func printPowerPlans() {
+	plans, err := power.List()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("%-6s %-38s %s\n", "Active", "GUID", "Name")
+	fmt.Println(strings.Repeat("-", 80))
+	var active string
+	for _, p := range plans {
+		mark := ""
+		if p.Active {
+			mark = "*"
+			active = p.GUID
+		}
+		fmt.Printf("%-6s %-38s %s\n", mark, p.GUID, p.Name)
+	}
+	if active == "" {
+		return
+	}
+
+	fmt.Println("\nActive plan settings (AC / battery):")
+	for _, s := range []struct {
+		name    string
+		setting power.Setting
+		unit    string
+	}{
+		{"Minimum processor state", power.ProcessorMinState, "%"},
+		{"Maximum processor state", power.ProcessorMaxState, "%"},
+		{"USB selective suspend", power.USBSelectiveSuspend, ""},
+	} {
+		ac, dc, err := power.Read(active, s.setting)
+		if err != nil {
+			fmt.Printf("  %-24s unknown (%v)\n", s.name, err)
+			continue
+		}
+		if s.unit == "" {
+			fmt.Printf("  %-24s %s / %s\n", s.name, onOff(ac), onOff(dc))
+		} else {
+			fmt.Printf("  %-24s %d%s / %d%s\n", s.name, ac, s.unit, dc, s.unit)
+		}
+	}
+}
+
+func onOff(v uint32) string {
+	if v != 0 {
+		return "on"
+	}
+	return "off"
+}
+
+func init() {
+	powerCmd.Flags().String("activate", "", "Switch to a power plan, by name or GUID")
+	powerCmd.Flags().Bool("ultimate", false, "Switch to Ultimate Performance, creating it if Windows hides it")
+	powerCmd.Flags().Int("processor-min", 0, "Set the active plan's minimum processor state on AC power, in percent")
+	powerCmd.Flags().Int("processor-max", 0, "Set the active plan's maximum processor state on AC power, in percent")
+	powerCmd.Flags().String("usb-suspend", "", "Turn the active plan's USB selective suspend on AC power on or off")
+	rootCmd.AddCommand(powerCmd)
+}
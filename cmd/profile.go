@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Create, copy, delete, and switch configuration profiles",
+	Long: `Profiles bundle clean options, a process whitelist, RAM thresholds, and
+gaming settings under a name. The active profile is used by clean, gaming,
+and extreme mode.
+
+Ready-made presets: gaming, streamer, work, deep-clean. Creating or
+switching to a preset name starts from that preset. The streamer preset is
+a streamer profile ("type": "streamer"), which keeps streaming apps
+boosted and capture and audio running while gaming.
+
+A profile created with --extends inherits every setting from its base
+profile and stores only the settings changed afterwards, so edits to the
+base carry over.
+
+Examples:
+  syscleaner profile --list
+  syscleaner profile --create gaming
+  syscleaner profile --duplicate gaming --as streaming
+  syscleaner profile --create laptop --extends default
+  syscleaner profile --switch streaming
+  syscleaner profile --show
+  syscleaner profile --delete streaming`,
+	Run: func(cmd *cobra.Command, args []string) {
+		listFlag, _ := cmd.Flags().GetBool("list")
+		showFlag, _ := cmd.Flags().GetBool("show")
+		create, _ := cmd.Flags().GetString("create")
+		duplicate, _ := cmd.Flags().GetString("duplicate")
+		as, _ := cmd.Flags().GetString("as")
+		extends, _ := cmd.Flags().GetString("extends")
+		deleteName, _ := cmd.Flags().GetString("delete")
+		switchName, _ := cmd.Flags().GetString("switch")
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		switch {
+		case create != "" && extends != "":
+			if _, err := config.ExtendProfile(create, extends); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Created profile %q extending %q.\n", create, extends)
+
+		case create != "":
+			if _, err := config.CreateProfile(create); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Created profile %q.\n", create)
+
+		case duplicate != "":
+			if as == "" {
+				fmt.Println("Error: --duplicate needs --as <new name>")
+				return
+			}
+			if _, err := config.DuplicateProfile(duplicate, as); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Copied profile %q to %q.\n", duplicate, as)
+
+		case deleteName != "":
+			if deleteName == cfg.ActiveProfile {
+				fmt.Printf("Error: %q is the active profile; switch to another profile first.\n", deleteName)
+				return
+			}
+			if err := config.DeleteProfile(deleteName); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Deleted profile %q.\n", deleteName)
+
+		case switchName != "":
+			if err := config.SwitchProfile(switchName); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Active profile is now %q.\n", switchName)
+
+		case showFlag:
+			printProfile(cfg)
+
+		case listFlag:
+			printProfileList(cfg)
+
+		default:
+			printProfileList(cfg)
+		}
+	},
+}
+
+func printProfileList(cfg *config.Config) {
+	names, err := config.ListProfiles()
+	if err != nil {
+		fmt.Printf("Error listing profiles: %v\n", err)
+		return
+	}
+	saved := make(map[string]bool, len(names))
+	fmt.Println("Profiles:")
+	for _, name := range names {
+		saved[name] = true
+		marker := " "
+		if name == cfg.ActiveProfile {
+			marker = "*"
+		}
+		fmt.Printf("  %s %s\n", marker, name)
+	}
+	if len(names) == 0 {
+		fmt.Printf("  * %s (not saved yet)\n", cfg.ActiveProfile)
+	}
+
+	var presets []string
+	for _, name := range config.PresetProfileNames() {
+		if !saved[name] {
+			presets = append(presets, name)
+		}
+	}
+	if len(presets) > 0 {
+		fmt.Printf("\nPresets available: %s\n", strings.Join(presets, ", "))
+	}
+}
+
+func printProfile(cfg *config.Config) {
+	p := cfg.ActiveProfileSettings()
+	ram := cfg.EffectiveRAMMonitor()
+
+	fmt.Printf("Active profile: %s\n", cfg.ActiveProfile)
+	if p.Extends != "" {
+		fmt.Printf("Extends:        %s\n", p.Extends)
+	}
+	fmt.Println()
+	fmt.Printf("  Whitelist:          %s\n", strings.Join(cfg.EffectiveWhitelist(), ", "))
+	fmt.Printf("  RAM free threshold: %.0f%%\n", ram.FreeThresholdPercent)
+	fmt.Printf("  RAM standby limit:  %.0f%%\n", ram.StandbyThresholdPercent)
+	fmt.Printf("  Gaming CPU boost:   %d%%\n", p.GamingConfig.CPUBoost)
+	fmt.Printf("  Gaming RAM reserve: %d GB\n", p.GamingConfig.RAMReserveGB)
+	fmt.Printf("  Extreme mode:       %v\n", p.GamingConfig.UseExtremeMode)
+	if s := p.StreamingConfig(); s != nil {
+		apps := s.Apps
+		if len(apps) == 0 {
+			apps = gaming.StreamingApps
+		}
+		fmt.Printf("  Streamer mode:      %s\n", strings.Join(apps, ", "))
+		if s.EncoderCores != "" {
+			fmt.Printf("  Encoder cores:      %s\n", s.EncoderCores)
+		}
+	}
+
+	fmt.Println("\n  Clean categories:")
+	for _, task := range p.CleanOptions.ToCleanOptions().Categories() {
+		fmt.Printf("    - %s\n", task)
+	}
+}
+
+func init() {
+	profileCmd.Flags().Bool("list", false, "List saved profiles")
+	profileCmd.Flags().Bool("show", false, "Show the active profile's settings")
+	profileCmd.Flags().String("create", "", "Create a profile (from a preset if the name matches one)")
+	profileCmd.Flags().String("duplicate", "", "Copy an existing profile (use with --as)")
+	profileCmd.Flags().String("extends", "", "Base profile for --create; only changed settings are stored")
+	profileCmd.Flags().String("as", "", "Name for the copy made by --duplicate")
+	profileCmd.Flags().String("delete", "", "Delete a profile")
+	profileCmd.Flags().String("switch", "", "Make a profile the active one")
+
+	rootCmd.AddCommand(profileCmd)
+}
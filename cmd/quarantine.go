@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var quarantineCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "List, restore, and purge quarantined files",
+	Long: `Manage files the cleaner moved to quarantine (enable with "quarantine": true
+in the cleaner section of the config).
+
+Restoring a file records it; files or folders restored repeatedly are suggested
+as cleaner exclusions so they are not deleted again. With
+"auto_learn_exclusions" enabled the suggestions are added automatically.
+
+Examples:
+  syscleaner quarantine --list
+  syscleaner quarantine --restore <id>
+  syscleaner quarantine --suggest
+  syscleaner quarantine --suggest --apply
+  syscleaner quarantine --purge 30`,
+	Run: func(cmd *cobra.Command, args []string) {
+		listFlag, _ := cmd.Flags().GetBool("list")
+		restoreID, _ := cmd.Flags().GetString("restore")
+		suggestFlag, _ := cmd.Flags().GetBool("suggest")
+		applyFlag, _ := cmd.Flags().GetBool("apply")
+		purgeDays, _ := cmd.Flags().GetInt("purge")
+
+		dir := cleaner.DefaultQuarantineDir()
+
+		if listFlag {
+			entries, err := cleaner.ListQuarantine(dir)
+			if err != nil {
+				fmt.Printf("Error reading quarantine: %v\n", err)
+				return
+			}
+			if len(entries) == 0 {
+				fmt.Println("Quarantine is empty.")
+				return
+			}
+			fmt.Printf("%-24s %-19s %10s  %s\n", "ID", "Quarantined", "Size", "Original Path")
+			fmt.Println(strings.Repeat("-", 100))
+			for _, e := range entries {
+				fmt.Printf("%-24s %-19s %10s  %s\n", e.ID, e.Time.Format("2006-01-02 15:04:05"),
+					cleaner.FormatBytes(e.Size), e.OriginalPath)
+			}
+			return
+		}
+
+		if restoreID != "" {
+			entry, err := cleaner.RestoreQuarantined(dir, restoreID)
+			if err != nil {
+				fmt.Printf("Error restoring %s: %v\n", restoreID, err)
+				return
+			}
+			fmt.Printf("Restored %s\n", entry.OriginalPath)
+			learnExclusions(dir, false, false)
+			return
+		}
+
+		if suggestFlag {
+			learnExclusions(dir, applyFlag, true)
+			return
+		}
+
+		if purgeDays > 0 {
+			count, freed, err := cleaner.PurgeQuarantine(dir, time.Duration(purgeDays)*24*time.Hour)
+			if err != nil {
+				fmt.Printf("Error purging quarantine: %v\n", err)
+				return
+			}
+			fmt.Printf("Purged %d files (%s)\n", count, cleaner.FormatBytes(freed))
+			return
+		}
+
+		cmd.Help()
+	},
+}
+
+// learnExclusions prints exclusion suggestions from the restore history and
+// saves them to the config when apply is set or auto-learning is enabled.
+// asked is set when the user ran --suggest, so finding none is reported
+// rather than passed over as it is after a restore.
+func learnExclusions(dir string, apply, asked bool) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		return
+	}
+	history, err := cleaner.RestoreHistory(dir)
+	if err != nil {
+		fmt.Printf("Error reading restore history: %v\n", err)
+		return
+	}
+
+	suggestions := cleaner.SuggestExclusions(history, cfg.Cleaner.Exclusions)
+	if len(suggestions) == 0 {
+		if asked || apply {
+			fmt.Println("No exclusion suggestions.")
+		}
+		return
+	}
+
+	apply = apply || cfg.Cleaner.AutoLearnExclusions
+	fmt.Println("Suggested exclusions (files you keep restoring):")
+	patterns := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		fmt.Printf("  %-60s %d restores\n", s.Pattern, s.Restores)
+		patterns = append(patterns, s.Pattern)
+	}
+
+	if !apply {
+		fmt.Println("Run 'syscleaner quarantine --suggest --apply' to add them.")
+		return
+	}
+	added := cfg.Cleaner.AddExclusions(patterns...)
+	if len(added) == 0 {
+		return
+	}
+	if err := config.SaveConfig(cfg); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+		return
+	}
+	fmt.Printf("Added %d exclusions to the config.\n", len(added))
+}
+
+func init() {
+	quarantineCmd.Flags().Bool("list", false, "List quarantined files")
+	quarantineCmd.Flags().String("restore", "", "Restore the quarantined file with this ID")
+	quarantineCmd.Flags().Bool("suggest", false, "Show exclusion suggestions from restore history")
+	quarantineCmd.Flags().Bool("apply", false, "With --suggest, add the suggestions to the config")
+	quarantineCmd.Flags().Int("purge", 0, "Permanently delete quarantined files older than N days")
+
+	rootCmd.AddCommand(quarantineCmd)
+}
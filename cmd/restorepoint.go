@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/sysrestore"
+
+	"github.com/spf13/cobra"
+)
+
+var restorePointCmd = &cobra.Command{
+	Use:   "restore-point",
+	Short: "Show System Restore status or create a restore point",
+	Long: `SysCleaner creates a System Restore point before extreme mode and the
+startup and network optimizations, once per run, when System Restore is
+enabled and it runs as administrator. Set skip_restore_points in the config
+to turn this off.
+
+Without flags, shows whether System Restore is enabled. --create makes a
+restore point now. Windows keeps at most one a day by default and skips
+the rest without an error.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		create, _ := cmd.Flags().GetBool("create")
+
+		enabled, err := sysrestore.Enabled()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if !create {
+			status := "disabled"
+			if enabled {
+				status = "enabled"
+			}
+			fmt.Printf("  System Restore:           %s\n", status)
+			auto := "on"
+			if !sysrestore.Automatic() {
+				auto = "off (skip_restore_points)"
+			}
+			fmt.Printf("  Automatic restore points: %s\n", auto)
+			return
+		}
+
+		if !enabled {
+			fmt.Println("Error: System Restore is disabled; turn it on in System Properties > System Protection")
+			return
+		}
+		if err := admin.RequireElevation("Creating a restore point"); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if err := admin.RequireWriteAccess("creating a restore point"); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Println("Creating a restore point...")
+		if err := sysrestore.Create("SysCleaner: manual restore point"); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Println("Restore point created.")
+	},
+}
+
+func init() {
+	restorePointCmd.Flags().Bool("create", false, "Create a restore point now")
+	rootCmd.AddCommand(restorePointCmd)
+}
@@ -3,6 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/agent"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/reglog"
 
 	"github.com/spf13/cobra"
 )
@@ -17,11 +25,94 @@ Features:
   - Gaming mode (auto-detects games, boosts CPU/RAM priority)
   - Extreme mode (stops Explorer shell, maximum performance)
   - System optimizer (startup, network, disk optimizations)
-  - CPU priority manager (permanent per-process priority settings)`,
+  - CPU priority manager (permanent per-process priority settings)
+
+SYSCLEANER_* environment variables override config values for scripted
+runs, e.g. SYSCLEANER_DRY_RUN=1 or SYSCLEANER_LOG_LEVEL=debug.
+Administrators can set and lock the same settings through Group Policy
+under HKLM\SOFTWARE\Policies\SysCleaner (DryRun, LogLevel,
+DisabledCategories, ...); policy values win over both.
+
+The config file is taken from --config, then SYSCLEANER_CONFIG, then a
+config.yaml next to the executable (portable installs), then the user
+config directory.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if path, _ := cmd.Flags().GetString("config"); path != "" {
+			config.SetConfigPath(path)
+		}
+		if path, ok := logger.PendingCrashReport(); ok {
+			fmt.Printf("SysCleaner crashed last time it ran. The crash report is in %s\n", path)
+			fmt.Println("Show it with: syscleaner logs --crash")
+			fmt.Println()
+			logger.AcknowledgeCrashReport()
+		}
+		if gaming.StaleSession() {
+			fmt.Println("A gaming mode session did not end cleanly; restoring the settings it changed...")
+			if _, err := gaming.RecoverSession(); err != nil {
+				fmt.Printf("  Error: %v\n", err)
+			}
+			fmt.Println()
+		}
+		audit, _ := cmd.Flags().GetBool("audit")
+		if cfg, err := config.LoadConfig(); err == nil {
+			cfg.ApplyLogLevel()
+			cfg.ApplyLogRedaction()
+			cfg.ApplyRestorePoints()
+			if err := cfg.ApplyLogShipping(); err != nil {
+				fmt.Printf("Log shipping disabled: %v\n", err)
+			}
+			cfg.GameDatabase.Apply()
+			if cfg.RegistryLogging {
+				if _, err := reglog.Start(reglog.DefaultDir()); err != nil {
+					fmt.Printf("Registry logging unavailable: %v\n", err)
+				}
+			}
+			audit = audit || cfg.AuditMode
+			if policy := cfg.PolicyOverrides(); len(policy) > 0 {
+				fmt.Printf("Managed by policy: %s\n", strings.Join(policy, ", "))
+			}
+		}
+		if audit {
+			admin.SetAuditMode(true)
+			fmt.Println("Audit mode: analysing only, no changes will be made.")
+			fmt.Println()
+		}
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("config", "", "Use this config file instead of searching for one")
+	rootCmd.PersistentFlags().Bool("audit", false, "Read-only audit mode: analyse and preview without changing anything")
+}
+
+// stopRegistryLog ends the run's registry log, if registry logging is on,
+// and says where its files are when the run changed the registry.
+func stopRegistryLog() {
+	rec := reglog.Current()
+	reglog.Stop()
+	if rec == nil || len(rec.Changes()) == 0 {
+		return
+	}
+	fmt.Printf("\n%d registry change(s) logged to %s\n", len(rec.Changes()), rec.LogPath())
+	fmt.Printf("Undo them with: reg import \"%s\"\n", rec.UndoPath())
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	defer logger.Recover()
+	logger.CaptureStandardLog(os.Stderr)
+	if gaming.RunWatchdog(os.Args[1:]) {
+		return
+	}
+	if agent.Run(os.Args[1:]) {
+		return
+	}
+	err := rootCmd.Execute()
+	stopRegistryLog()
+	// Send any log entries still waiting to be shipped.
+	if err := logger.SetShipper(nil); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List cleaning rules loaded from rule packs",
+	Long: `Rule packs are signed JSON files in the SysCleaner "rules" config folder that
+add cleaning definitions for applications without rebuilding SysCleaner.
+Trusted signing keys are listed under "rule_pack_keys" in the cleaner
+section of the config.
+
+Examples:
+  syscleaner rules
+  syscleaner clean --rules all --dry-run
+  syscleaner clean --rules obs-logs,notepadpp-backups`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+		cfg.Cleaner.Apply()
+
+		rules := cleaner.Rules()
+		if len(rules) == 0 {
+			fmt.Printf("No rule packs loaded from %s\n", cleaner.DefaultRulesDir())
+			return
+		}
+
+		fmt.Printf("%-28s %-30s %s\n", "Rule ID", "Application", "Detected")
+		fmt.Println(strings.Repeat("-", 70))
+		for _, r := range rules {
+			detected := "no"
+			if r.Detected() {
+				detected = "yes"
+			}
+			fmt.Printf("%-28s %-30s %s\n", r.ID, r.App, detected)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/optimizer"
+
+	"github.com/spf13/cobra"
+)
+
+var servicesCmd = &cobra.Command{
+	Use:   "services",
+	Short: "List, trim, and restore commonly unnecessary services",
+	Long: `Manage a curated list of Windows services most PCs can do without: Fax,
+telemetry, retail demo, offline maps and media sharing, the Xbox services
+when no Xbox app is installed, and SysMain (Superfetch) on an SSD.
+
+Without flags, lists the installed ones with their start type and the
+recommended one. --apply sets every service that applies to its
+recommended start type (with --dry-run, only shows what would change).
+--set changes one service, e.g. --set SysMain=disabled. The start type each
+service had first is kept, and --restore puts them all back.
+
+Changing services needs administrator rights.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		set, _ := cmd.Flags().GetString("set")
+		restore, _ := cmd.Flags().GetBool("restore")
+		dryRun = dryRun || admin.AuditMode()
+
+		if restore || set != "" || apply && !dryRun {
+			if err := admin.RequireElevation("Changing services"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		switch {
+		case restore:
+			n, err := optimizer.RestoreServices()
+			fmt.Printf("Restored %d service(s)\n", n)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		case set != "":
+			name, startType, ok := strings.Cut(set, "=")
+			if !ok {
+				fmt.Println("Error: --set takes NAME=TYPE, e.g. SysMain=manual")
+				return
+			}
+			if err := optimizer.SetServiceStartType(name, strings.ToLower(startType)); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Set %s to %s\n", name, strings.ToLower(startType))
+		case apply:
+			if dryRun {
+				fmt.Println("Previewing services optimization (dry run, nothing will be changed)...")
+			}
+			optimizer.PrintServicesResult(optimizer.OptimizeServices(optimizer.OptimizeOptions{DryRun: dryRun}))
+		default:
+			printServices()
+		}
+	},
+}
+
+func printServices() {
+	services, err := optimizer.ListServices()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(services) == 0 {
+		fmt.Println("None of the listed services are installed.")
+		return
+	}
+	fmt.Printf("%-16s %-18s %-12s %s\n", "Service", "Start type", "Recommended", "Reason")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, s := range services {
+		recommended := s.Recommended
+		if !s.Applies {
+			recommended = "keep"
+		}
+		fmt.Printf("%-16s %-18s %-12s %s\n", s.Name, s.StartType, recommended, s.Reason)
+		if s.Original != "" {
+			fmt.Printf("%-16s %-18s %-12s was %s before SysCleaner\n", "", "", "", s.Original)
+		}
+	}
+}
+
+func init() {
+	servicesCmd.Flags().Bool("apply", false, "Set every service that applies to its recommended start type")
+	servicesCmd.Flags().Bool("dry-run", false, "With --apply, show what would change without changing anything")
+	servicesCmd.Flags().String("set", "", "Set one service's start type, as NAME=automatic|automatic-delayed|manual|disabled")
+	servicesCmd.Flags().Bool("restore", false, "Put back the start types of every service SysCleaner changed")
+	rootCmd.AddCommand(servicesCmd)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/gaming"
+
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Show the history of gaming sessions",
+	Long: `Lists recorded gaming sessions, newest first: the game, how long it ran,
+average and peak CPU and RAM load, the cleans run for it (pre-launch cleans
+and standby memory purges) and the optimizations gaming mode applied.
+Where the GPU could be sampled, a second line shows its average and peak
+load, peak video memory use and temperature, and how much of the session
+the game was GPU-bound or short of video memory.
+
+--summary totals the sessions per game instead, to show trends such as a
+game's RAM use creeping up.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		summary, _ := cmd.Flags().GetBool("summary")
+
+		recs, err := gaming.SessionHistory()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(recs) == 0 {
+			fmt.Println("No gaming sessions recorded yet.")
+			return
+		}
+
+		if summary {
+			fmt.Printf("%-24s %8s %10s %8s %8s %9s  %s\n", "Game", "Sessions", "Time", "Avg CPU", "Avg RAM", "Peak RAM", "Last played")
+			fmt.Println(strings.Repeat("-", 92))
+			for _, s := range gaming.SummarizeSessions(recs) {
+				fmt.Printf("%-24s %8d %10s %7.1f%% %7.1f%% %8.1f%%  %s\n",
+					s.Game, s.Sessions, formatDuration(s.TotalTime), s.AvgCPU, s.AvgRAM, s.PeakRAM,
+					s.LastPlay.Local().Format("2006-01-02"))
+			}
+			return
+		}
+
+		if limit > 0 && len(recs) > limit {
+			recs = recs[:limit]
+		}
+		fmt.Printf("%-16s %-24s %8s %15s %15s %6s\n", "Started", "Game", "Length", "CPU avg/peak", "RAM avg/peak", "Cleans")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, r := range recs {
+			game := r.Game
+			if game == "" {
+				game = "(unknown)"
+			}
+			fmt.Printf("%-16s %-24s %8s %6.1f%%/%5.1f%% %6.1f%%/%5.1f%% %6d\n",
+				r.Started.Local().Format("2006-01-02 15:04"), game, formatDuration(r.Duration),
+				r.AvgCPU, r.PeakCPU, r.AvgRAM, r.PeakRAM, r.Cleans)
+			if gpu := formatSessionGPU(r); gpu != "" {
+				fmt.Printf("%16s %s\n", "", gpu)
+			}
+			if r.WorkingSetTrimmed > 0 {
+				fmt.Printf("%16s Reclaimed %d MB from background working sets\n", "", r.WorkingSetTrimmed>>20)
+			}
+			if len(r.Optimizations) > 0 {
+				fmt.Printf("%16s %s\n", "", strings.Join(r.Optimizations, ", "))
+			}
+		}
+	},
+}
+
+// formatSessionGPU describes a session's GPU statistics, or returns "" if
+// the GPU was not sampled.
+func formatSessionGPU(r gaming.SessionRecord) string {
+	if r.PeakGPU == 0 && r.PeakVRAM == 0 && r.PeakGPUTemp == 0 {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("GPU %.1f%%/%.1f%%", r.AvgGPU, r.PeakGPU)}
+	if r.PeakVRAM > 0 {
+		parts = append(parts, fmt.Sprintf("VRAM peak %.0f%%", r.PeakVRAM))
+	}
+	if r.PeakGPUTemp > 0 {
+		parts = append(parts, fmt.Sprintf("peak %d°C", r.PeakGPUTemp))
+	}
+	if r.GPUBound > 0 {
+		parts = append(parts, fmt.Sprintf("GPU-bound %.0f%% of the time", r.GPUBound))
+	}
+	if r.VRAMPressure > 0 {
+		parts = append(parts, fmt.Sprintf("VRAM nearly full %.0f%% of the time", r.VRAMPressure))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDuration formats d as hours and minutes, e.g. "2h05m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func init() {
+	sessionsCmd.Flags().Int("limit", 20, "Number of sessions to show (0 for all)")
+	sessionsCmd.Flags().Bool("summary", false, "Total the sessions per game")
+	rootCmd.AddCommand(sessionsCmd)
+}
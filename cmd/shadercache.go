@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+
+	"github.com/spf13/cobra"
+)
+
+var shaderCacheCmd = &cobra.Command{
+	Use:   "shader-cache <game>",
+	Short: "Back up, restore or clear one game's shader caches",
+	Long: `Manage the shader cache folders a game override declares under
+shader_caches, keyed by vendor (nvidia, amd, intel, directx or game). This
+helps after a GPU driver update corrupts the cache of a single title: clear
+it to have it rebuilt, or restore the copy taken before the update.
+
+Without an action, lists the game's shader caches and whether each has a
+backup. Close the game first; caches in use cannot be replaced.
+
+Examples:
+  syscleaner shader-cache cs2.exe
+  syscleaner shader-cache cs2.exe --backup
+  syscleaner shader-cache "Counter-Strike 2" --clear --vendor nvidia
+  syscleaner shader-cache cs2.exe --restore`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		game := args[0]
+		vendor, _ := cmd.Flags().GetString("vendor")
+		backup, _ := cmd.Flags().GetBool("backup")
+		restore, _ := cmd.Flags().GetBool("restore")
+		clear, _ := cmd.Flags().GetBool("clear")
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		gaming.SetGameOverrides(cfg.GameOverrides)
+		gaming.ScanLibraries()
+
+		var n int64
+		switch {
+		case backup:
+			n, err = gaming.BackupShaderCaches(game, vendor)
+			if err == nil {
+				fmt.Printf("Backed up %s of shader cache for %s\n", cleaner.FormatBytes(n), game)
+			}
+		case restore:
+			n, err = gaming.RestoreShaderCaches(game, vendor)
+			if err == nil {
+				fmt.Printf("Restored %s of shader cache for %s\n", cleaner.FormatBytes(n), game)
+			}
+		case clear:
+			n, err = gaming.ClearShaderCaches(game, vendor)
+			if err == nil {
+				fmt.Printf("Cleared %s of shader cache for %s\n", cleaner.FormatBytes(n), game)
+			}
+		default:
+			var caches []gaming.ShaderCache
+			caches, err = gaming.ShaderCachesFor(game, vendor)
+			for _, c := range caches {
+				state := "no backup"
+				if gaming.HasShaderCacheBackup(game, c) {
+					state = "backed up"
+				}
+				fmt.Printf("  %-8s %s (%s)\n", c.Vendor, c.Path, state)
+			}
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	shaderCacheCmd.Flags().String("vendor", "", "Only the caches of this vendor (nvidia, amd, intel, directx, game)")
+	shaderCacheCmd.Flags().Bool("backup", false, "Copy the shader caches, replacing any earlier backup")
+	shaderCacheCmd.Flags().Bool("restore", false, "Replace the shader caches with their backups")
+	shaderCacheCmd.Flags().Bool("clear", false, "Delete the shader caches so they are rebuilt")
+	rootCmd.AddCommand(shaderCacheCmd)
+}
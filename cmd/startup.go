@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"syscleaner/pkg/optimizer"
+
+	"github.com/spf13/cobra"
+)
+
+var startupCmd = &cobra.Command{
+	Use:   "startup",
+	Short: "List, disable, and enable startup programs",
+	Long: `Manage the programs Windows starts at logon: the Run and RunOnce keys of
+the current user and the machine, the user's and the common Startup
+folders, third-party Task Scheduler tasks with a logon trigger, and Store
+apps' startup tasks.
+
+Disabling works like Task Manager's Startup tab: the entry is kept and
+marked disabled, so --enable turns it back on. Logon tasks are disabled in
+Task Scheduler. RunOnce entries cannot be disabled and are deleted instead.
+Machine-wide entries need administrator rights. Registry changes are
+journaled and can also be undone with "syscleaner optimize --undo <id>".
+
+Impact is measured, not guessed: it is each program's average start time
+in the boots Windows recorded as slowed down (the Diagnostics-Performance
+event log, readable as administrator). --recommend lists the enabled
+programs worth disabling, costliest first.
+
+Examples:
+  syscleaner startup
+  syscleaner startup --recommend
+  syscleaner startup --disable Spotify
+  syscleaner startup --enable Spotify`,
+	Run: func(cmd *cobra.Command, args []string) {
+		disable, _ := cmd.Flags().GetString("disable")
+		enable, _ := cmd.Flags().GetString("enable")
+		recommend, _ := cmd.Flags().GetBool("recommend")
+
+		programs, err := optimizer.ListStartupPrograms()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		switch {
+		case disable != "":
+			setStartupPrograms(programs, disable, false)
+		case enable != "":
+			setStartupPrograms(programs, enable, true)
+		case recommend:
+			recommended := optimizer.RecommendStartup(programs)
+			if len(recommended) == 0 {
+				fmt.Println("No startup programs to recommend disabling.")
+				return
+			}
+			printStartupPrograms(recommended)
+		default:
+			printStartupPrograms(programs)
+		}
+	},
+}
+
+// setStartupPrograms enables or disables every entry called name. Startup
+// folder entries also match without their extension.
+func setStartupPrograms(programs []optimizer.StartupProgram, name string, enable bool) {
+	found := false
+	for _, p := range programs {
+		base := p.Name
+		if p.Source == optimizer.StartupFolder {
+			base = strings.TrimSuffix(p.Name, filepath.Ext(p.Name))
+		}
+		if !strings.EqualFold(p.Name, name) && !strings.EqualFold(base, name) {
+			continue
+		}
+		found = true
+		var err error
+		if enable {
+			err = optimizer.EnableStartup(p)
+		} else {
+			err = optimizer.DisableStartup(p)
+		}
+		if err != nil {
+			fmt.Printf("Error: %s (%s): %v\n", p.Name, p.Location, err)
+			continue
+		}
+		state := "Disabled"
+		if enable {
+			state = "Enabled"
+		}
+		fmt.Printf("%s %s (%s)\n", state, p.Name, p.Location)
+	}
+	if !found {
+		fmt.Printf("Error: no startup program named %q; run \"syscleaner startup\" to list them\n", name)
+	}
+}
+
+func printStartupPrograms(programs []optimizer.StartupProgram) {
+	if len(programs) == 0 {
+		fmt.Println("No startup programs found.")
+		return
+	}
+	fmt.Printf("%-9s %-12s %7s %-8s %-28s %s\n", "State", "Impact", "Cost", "Source", "Name", "Command")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, p := range programs {
+		state := "enabled"
+		if p.Disabled {
+			state = "disabled"
+		}
+		cost := "-"
+		if p.StartupCost > 0 {
+			cost = fmt.Sprintf("%.1fs", p.StartupCost.Seconds())
+		}
+		fmt.Printf("%-9s %-12s %7s %-8s %-28s %s\n", state, p.Impact, cost, p.Source, p.Name, p.Path)
+		if !p.DisabledAt.IsZero() {
+			fmt.Printf("%-9s %-12s %7s %-8s %-28s disabled %s\n", "", "", "", "", "", p.DisabledAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+func init() {
+	startupCmd.Flags().String("disable", "", "Disable the startup program with this name")
+	startupCmd.Flags().String("enable", "", "Re-enable the startup program with this name")
+	startupCmd.Flags().Bool("recommend", false, "List the enabled programs worth disabling, costliest first")
+	rootCmd.AddCommand(startupCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/conflicts"
+
+	"github.com/spf13/cobra"
+)
+
+var storageSenseCmd = &cobra.Command{
+	Use:   "storagesense",
+	Short: "Show and configure Windows Storage Sense",
+	Long: `Show and change the current user's Storage Sense settings: whether it is
+on, how often it runs, and whether it deletes temporary files and old files
+in Downloads and the Recycle Bin.
+
+Storage Sense and SysCleaner's scheduled cleaning and low disk trigger can
+do the same job; after a change, any overlap is listed (see "syscleaner
+conflicts") so one of the two can be turned off. Changes are recorded in
+the registry change log and can be undone from it.
+
+Examples:
+  syscleaner storagesense
+  syscleaner storagesense --enable --cadence weekly --clean-temp on --downloads 30
+  syscleaner storagesense --disable`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		s, err := conflicts.ReadStorageSense()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if !flags.Changed("enable") && !flags.Changed("disable") && !flags.Changed("cadence") &&
+			!flags.Changed("clean-temp") && !flags.Changed("downloads") && !flags.Changed("recycle-bin") {
+			printStorageSense(s)
+			return
+		}
+
+		if enable, _ := flags.GetBool("enable"); enable {
+			s.Enabled = true
+		}
+		if disable, _ := flags.GetBool("disable"); disable {
+			s.Enabled = false
+		}
+		if flags.Changed("cadence") {
+			name, _ := flags.GetString("cadence")
+			if s.Cadence, err = conflicts.ParseCadence(name); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+		if flags.Changed("clean-temp") {
+			val, _ := flags.GetString("clean-temp")
+			switch strings.ToLower(val) {
+			case "on":
+				s.CleanTemp = true
+			case "off":
+				s.CleanTemp = false
+			default:
+				fmt.Println("Error: --clean-temp takes on or off")
+				return
+			}
+		}
+		if flags.Changed("downloads") {
+			s.DownloadsDays, _ = flags.GetInt("downloads")
+		}
+		if flags.Changed("recycle-bin") {
+			s.RecycleBinDays, _ = flags.GetInt("recycle-bin")
+		}
+		if err := conflicts.ConfigureStorageSense(s); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Println("Storage Sense settings saved.")
+		printStorageSense(s)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return
+		}
+		if found := conflicts.Analyze(conflicts.Detect(), cfg.LowDisk.Enabled); len(found) > 0 {
+			fmt.Println("\nOverlaps with SysCleaner:")
+			for _, c := range found {
+				fmt.Printf("  - %s\n", c)
+			}
+		}
+	},
+}
+
+func printStorageSense(s conflicts.StorageSense) {
+	state := "off"
+	if s.Enabled {
+		state = "on"
+	}
+	days := func(d int) string {
+		if d == 0 {
+			return "never"
+		}
+		return fmt.Sprintf("after %d days", d)
+	}
+	fmt.Printf("  Storage Sense:           %s\n", state)
+	fmt.Printf("  Runs:                    %s\n", conflicts.CadenceName(s.Cadence))
+	fmt.Printf("  Deletes temporary files: %v\n", s.CleanTemp)
+	fmt.Printf("  Cleans Downloads:        %s\n", days(s.DownloadsDays))
+	fmt.Printf("  Cleans Recycle Bin:      %s\n", days(s.RecycleBinDays))
+	if s.ManagedByPolicy {
+		fmt.Println("  Managed by Group Policy; these settings may not apply.")
+	}
+}
+
+func init() {
+	storageSenseCmd.Flags().Bool("enable", false, "Turn Storage Sense on")
+	storageSenseCmd.Flags().Bool("disable", false, "Turn Storage Sense off")
+	storageSenseCmd.Flags().String("cadence", "", "When Storage Sense runs: low-disk, daily, weekly or monthly")
+	storageSenseCmd.Flags().String("clean-temp", "", "Delete temporary files apps aren't using: on or off")
+	storageSenseCmd.Flags().Int("downloads", 0, "Delete files in Downloads older than this many days: 0 (never), 1, 14, 30 or 60")
+	storageSenseCmd.Flags().Int("recycle-bin", 0, "Delete files in the Recycle Bin older than this many days: 0 (never), 1, 14, 30 or 60")
+	rootCmd.AddCommand(storageSenseCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/support"
+
+	"github.com/spf13/cobra"
+)
+
+var supportCmd = &cobra.Command{
+	Use:   "support",
+	Short: "Collect diagnostics for bug reports",
+}
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create a zip with config, logs and system info to attach to an issue",
+	Long: `Creates a zip with the configuration and profiles, the end of the log file,
+recent registry change logs, system information and the last clean reports.
+
+By default the user name, profile folder and computer name are replaced with
+placeholders. Use --redact hash to also replace every path with a short hash,
+or --redact none to keep files unchanged.
+
+Examples:
+  syscleaner support bundle
+  syscleaner support bundle --redact hash --output issue.zip`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		redact, _ := cmd.Flags().GetString("redact")
+
+		mode, err := support.ParseRedactMode(redact)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if output == "" {
+			output = support.DefaultBundleName()
+		}
+
+		names, err := support.CreateBundle(output, support.DefaultSources(), mode)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Support bundle written to %s\n", output)
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+		fmt.Println("\nReview the contents before attaching the bundle to an issue.")
+	},
+}
+
+func init() {
+	supportBundleCmd.Flags().StringP("output", "o", "", "Bundle file to write (default syscleaner-support-<time>.zip)")
+	supportBundleCmd.Flags().String("redact", string(support.RedactUser), "Redaction mode: none, redact, or hash")
+
+	supportCmd.AddCommand(supportBundleCmd)
+	rootCmd.AddCommand(supportCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/optimizer"
+
+	"github.com/spf13/cobra"
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Disable and re-enable telemetry and maintenance scheduled tasks",
+	Long: `Manage Windows' telemetry and maintenance scheduled tasks (Customer
+Experience Improvement Program, Application Experience, error reporting,
+...). Each is classed by how far disabling it reaches:
+
+  safe         telemetry uploads nothing depends on
+  recommended  also feedback, error reporting and background data collection
+  aggressive   also maintenance such as the scheduled defrag and WinSAT
+
+Without flags, lists the installed tasks. --apply disables every task at
+--level or below (with --dry-run, only shows which). --disable and
+--enable change one task, by full name or last part. SysCleaner remembers
+what it disabled, and --restore re-enables all of it, leaving tasks you
+disabled yourself alone.
+
+Changing tasks needs administrator rights.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		level, _ := cmd.Flags().GetString("level")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		disable, _ := cmd.Flags().GetString("disable")
+		enable, _ := cmd.Flags().GetString("enable")
+		restore, _ := cmd.Flags().GetBool("restore")
+		dryRun = dryRun || admin.AuditMode()
+
+		if restore || disable != "" || enable != "" || apply && !dryRun {
+			if err := admin.RequireElevation("Changing scheduled tasks"); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		switch {
+		case restore:
+			n, err := optimizer.RestoreTasks()
+			fmt.Printf("Re-enabled %d scheduled task(s)\n", n)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		case disable != "" || enable != "":
+			name, err := optimizer.FindDebloatTask(disable + enable)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if disable != "" {
+				err = optimizer.DisableTask(name)
+			} else {
+				err = optimizer.EnableTask(name)
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			state := "Disabled"
+			if enable != "" {
+				state = "Enabled"
+			}
+			fmt.Printf("%s %s\n", state, name)
+		case apply:
+			if err := optimizer.ValidateTaskLevel(level); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			if dryRun {
+				fmt.Println("Previewing task debloat (dry run, nothing will be changed)...")
+			}
+			optimizer.PrintTasksResult(optimizer.DebloatTasks(level, optimizer.OptimizeOptions{DryRun: dryRun}))
+		default:
+			printDebloatTasks()
+		}
+	},
+}
+
+func printDebloatTasks() {
+	tasks, err := optimizer.ListDebloatTasks()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(tasks) == 0 {
+		fmt.Println("None of the listed scheduled tasks are installed.")
+		return
+	}
+	fmt.Printf("%-12s %-9s %s\n", "Level", "State", "Task")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, t := range tasks {
+		state := "enabled"
+		if !t.Enabled && t.DisabledBySysCleaner {
+			state = "disabled*"
+		} else if !t.Enabled {
+			state = "disabled"
+		}
+		fmt.Printf("%-12s %-9s %s\n", t.Level, state, t.Name)
+		fmt.Printf("%-12s %-9s   %s\n", "", "", t.Reason)
+	}
+	fmt.Println("\n* disabled by SysCleaner; re-enable with --restore")
+}
+
+func init() {
+	tasksCmd.Flags().Bool("apply", false, "Disable every task at --level or below")
+	tasksCmd.Flags().String("level", optimizer.TaskLevelSafe, "Task level for --apply: safe, recommended, or aggressive")
+	tasksCmd.Flags().Bool("dry-run", false, "With --apply, show which tasks would be disabled without changing anything")
+	tasksCmd.Flags().String("disable", "", "Disable one task, by full name or last part")
+	tasksCmd.Flags().String("enable", "", "Re-enable one task, by full name or last part")
+	tasksCmd.Flags().Bool("restore", false, "Re-enable every task SysCleaner disabled")
+	rootCmd.AddCommand(tasksCmd)
+}
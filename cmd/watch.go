@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"syscleaner/pkg/autoclean"
+	"syscleaner/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch free disk space and run scheduled jobs",
+	Long: `Runs in the foreground and polls the drives listed in the "low_disk" config
+section. When free space drops below the configured threshold, the clean
+options from the configured profile are run and the result is printed.
+
+With "power_profiles" enabled, the active profile follows the power source
+and dock state (on_ac, on_battery, docked, undocked).
+
+Jobs in the "schedules" config section run while watching, e.g.
+
+  "schedules": [
+    {"name": "weekly", "enabled": true, "cron": "0 3 * * 0",
+     "profile": "default", "clean": true, "optimize": ["disk"]}
+  ]
+
+Press Ctrl+C to stop.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+		if !cfg.LowDisk.Enabled && !hasEnabledSchedule(cfg) && !cfg.PowerProfiles.Enabled {
+			fmt.Println("Nothing to watch: the low disk trigger, schedules and power profiles are all disabled. Set \"enabled\": true in the low_disk or power_profiles config section or a schedule.")
+			return
+		}
+		notify := func(title, msg string) {
+			fmt.Printf("[%s] %s: %s\n", time.Now().Format("15:04:05"), title, msg)
+		}
+		start := func(cfg *config.Config) {
+			cfg.Performance.Apply()
+			cfg.Cleaner.Apply()
+			autoclean.StartLowDiskTrigger(cfg.LowDisk, cfg.Performance.DiskMonitorInterval(), notify)
+			autoclean.StartSchedules(cfg.Schedules, notify)
+			autoclean.StartPowerProfiles(cfg.PowerProfiles, notify)
+			for _, e := range cfg.Schedules {
+				if next, ok := autoclean.NextScheduledRun(e.Name); ok {
+					fmt.Printf("Schedule %q next runs at %s\n", e.Name, next.Format("Mon Jan 2 15:04"))
+				}
+			}
+		}
+		start(cfg)
+		defer autoclean.StopLowDiskTrigger()
+		defer autoclean.StopSchedules()
+		defer autoclean.StopPowerProfiles()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		updates, err := config.Watch(ctx)
+		if err != nil {
+			fmt.Printf("Config changes will not be picked up: %v\n", err)
+		}
+
+		fmt.Println("Watching disk space and schedules. Press Ctrl+C to stop.")
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		for {
+			select {
+			case cfg, ok := <-updates:
+				if !ok {
+					updates = nil
+					continue
+				}
+				notify("Config reloaded", fmt.Sprintf("low disk trigger enabled: %v, %d schedule(s)",
+					cfg.LowDisk.Enabled, len(cfg.Schedules)))
+				start(cfg)
+			case <-sig:
+				fmt.Println("\nStopped.")
+				return
+			}
+		}
+	},
+}
+
+// hasEnabledSchedule reports whether cfg has a schedule to run.
+func hasEnabledSchedule(cfg *config.Config) bool {
+	for _, e := range cfg.Schedules {
+		if e.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
@@ -3,17 +3,30 @@
 package gui
 
 import (
+	"context"
+	"fmt"
 	"image/color"
+	"log"
+	"net/url"
+	"os"
 	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"syscleaner/gui/views"
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/autoclean"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/conflicts"
 	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/reglog"
 )
 
 // modernTheme implements a sleek dark theme with flame-orange accents.
@@ -81,18 +94,162 @@ func (m *modernTheme) Size(name fyne.ThemeSizeName) float32 {
 
 // Run launches the GUI application.
 func Run() {
+	logger.CaptureStandardLog(os.Stderr)
 	a := app.NewWithID("com.syscleaner.app")
 	customTheme := &modernTheme{}
 	a.Settings().SetTheme(customTheme)
 
-	w := a.NewWindow("SysCleaner - Ultimate Performance")
+	title := "SysCleaner - Ultimate Performance"
+	if cfg, err := config.LoadConfig(); err == nil {
+		if cfg.AuditMode {
+			admin.SetAuditMode(true)
+			title += " [Audit Mode - read-only]"
+		}
+		applyConfig(a, cfg)
+		if cfg.RegistryLogging {
+			if _, err := reglog.Start(reglog.DefaultDir()); err != nil {
+				log.Printf("[SysCleaner] Registry logging unavailable: %v", err)
+			}
+		}
+	}
+	// Pick up edits to the config file and profiles without a restart.
+	if updates, err := config.Watch(context.Background()); err == nil {
+		logger.Go(func() {
+			for cfg := range updates {
+				applyConfig(a, cfg)
+				log.Println("[SysCleaner] Config reloaded")
+			}
+		})
+	} else {
+		log.Printf("[SysCleaner] Config hot-reload unavailable: %v", err)
+	}
+
+	// Live views stop sampling while the window is minimized or a game
+	// has focus.
+	a.Lifecycle().SetOnEnteredForeground(func() { views.SetForeground(true) })
+	a.Lifecycle().SetOnExitedForeground(func() { views.SetForeground(false) })
+
+	w := a.NewWindow(title)
 	w.Resize(fyne.NewSize(1200, 800))
 	w.CenterOnScreen()
 	w.SetMaster()
 
 	mainContainer := createMainInterface(w)
 	w.SetContent(mainContainer)
+	logger.Go(func() { warnCleanerConflicts(a, w) })
+	logger.Go(func() { offerCrashReport(a, w) })
+	logger.Go(recoverGamingSession)
 	w.ShowAndRun()
+	reglog.Stop()
+	logger.SetShipper(nil)
+}
+
+// applyConfig pushes cfg into the packages and views that cache settings.
+// Refresh intervals take effect for views built afterwards.
+func applyConfig(a fyne.App, cfg *config.Config) {
+	cfg.ApplyLogLevel()
+	cfg.ApplyLogRedaction()
+	cfg.ApplyRestorePoints()
+	if err := cfg.ApplyLogShipping(); err != nil {
+		views.RecordError(err)
+	}
+	cfg.Performance.Apply()
+	cfg.Cleaner.Apply()
+	gaming.SetExtremeStopList(cfg.ExtremeStopList)
+	cfg.GameDatabase.Apply()
+	cfg.EffectiveRAMMonitor().Apply()
+	views.DashboardRefresh = cfg.Performance.DashboardRefreshInterval()
+	views.MonitorRefresh = cfg.Performance.MonitorRefreshInterval()
+	views.GPURefresh = cfg.Performance.GPURefreshInterval()
+	views.StatusBarRefresh = cfg.Performance.StatusBarRefreshInterval()
+	views.SetActiveProfile(cfg.ActiveProfile)
+	views.SetRefreshInBackground(cfg.Performance.GUIRefreshInBackground)
+	notify := func(title, msg string) {
+		a.SendNotification(fyne.NewNotification(title, msg))
+	}
+	autoclean.StartLowDiskTrigger(cfg.LowDisk, cfg.Performance.DiskMonitorInterval(), notify)
+	autoclean.StartSchedules(cfg.Schedules, notify)
+	autoclean.StartPowerProfiles(cfg.PowerProfiles, notify)
+	applyHotkeys(a, cfg)
+}
+
+// offerCrashReport tells the user about a crash report left by the last
+// run and offers to open it.
+func offerCrashReport(a fyne.App, w fyne.Window) {
+	path, ok := logger.PendingCrashReport()
+	if !ok {
+		return
+	}
+	logger.AcknowledgeCrashReport()
+	dialog.ShowConfirm("SysCleaner Crashed",
+		fmt.Sprintf("SysCleaner crashed the last time it ran. A crash report was saved to\n%s\n\nOpen it now?", path),
+		func(open bool) {
+			if !open {
+				return
+			}
+			u, err := url.Parse(storage.NewFileURI(path).String())
+			if err == nil {
+				err = a.OpenURL(u)
+			}
+			if err != nil {
+				views.RecordError(err)
+			}
+		}, w)
+}
+
+// recoverGamingSession restores the settings a gaming mode session left
+// changed when the last run exited without ending it.
+func recoverGamingSession() {
+	if !gaming.StaleSession() {
+		return
+	}
+	if _, err := gaming.RecoverSession(); err != nil {
+		views.RecordError(err)
+	}
+}
+
+// warnCleanerConflicts notifies the user when Storage Sense or another
+// cleaner overlaps with SysCleaner and offers to turn one of them off.
+func warnCleanerConflicts(a fyne.App, w fyne.Window) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return
+	}
+	found := conflicts.Analyze(conflicts.Detect(), cfg.LowDisk.Enabled)
+	if len(found) == 0 {
+		return
+	}
+
+	a.SendNotification(fyne.NewNotification("SysCleaner: overlapping cleaners",
+		found[0].String()))
+
+	var msg string
+	senseConflict := false
+	for _, c := range found {
+		msg += "- " + c.String() + "\n"
+		if c.Source.Kind == conflicts.KindStorageSense {
+			senseConflict = true
+		}
+	}
+	if !senseConflict {
+		dialog.ShowInformation("Other Cleaners Detected", msg, w)
+		return
+	}
+	dialog.ShowCustomConfirm("Other Cleaners Detected", "Disable Storage Sense", "Disable low disk trigger",
+		widget.NewLabel(msg), func(disableSense bool) {
+			if disableSense {
+				if err := conflicts.DisableStorageSense(); err != nil {
+					views.RecordError(err)
+				}
+				return
+			}
+			cfg.LowDisk.Enabled = false
+			if err := config.SaveConfig(cfg); err != nil {
+				views.RecordError(err)
+				return
+			}
+			autoclean.StopLowDiskTrigger()
+		}, w)
 }
 
 // lazyTab creates a tab whose content is built on first selection.
@@ -133,24 +290,30 @@ func (l *lazyContainer) CreateRenderer() fyne.WidgetRenderer {
 
 func createMainInterface(w fyne.Window) fyne.CanvasObject {
 	// Dashboard loads eagerly since it's the first visible tab
-	dashTab := container.NewTabItemWithIcon("Dashboard", theme.HomeIcon(), views.NewDashboard())
+	dashTab := container.NewTabItemWithIcon(views.TabDashboard, theme.HomeIcon(), views.NewDashboard())
 
 	// Other tabs load lazily on first selection
-	extremeTab := lazyTab("Extreme Mode", theme.WarningIcon(), func() fyne.CanvasObject {
+	extremeTab := lazyTab(views.TabExtremeMode, theme.WarningIcon(), func() fyne.CanvasObject {
 		return views.NewExtremeModePanel(w)
 	})
-	cleanTab := lazyTab("Clean", theme.DeleteIcon(), views.NewCleanPanel)
-	optimizeTab := lazyTab("Optimize", theme.SettingsIcon(), views.NewOptimizePanel)
+	cleanTab := lazyTab(views.TabClean, theme.DeleteIcon(), func() fyne.CanvasObject {
+		return views.NewCleanPanel(w)
+	})
+	optimizeTab := lazyTab("Optimize", theme.SettingsIcon(), func() fyne.CanvasObject {
+		return views.NewOptimizePanel(w)
+	})
 	cpuTab := lazyTab("CPU Priority", theme.MediaPlayIcon(), func() fyne.CanvasObject {
 		return views.NewPriorityPanel(w)
 	})
-	monitorTab := lazyTab("Monitor", theme.InfoIcon(), views.NewMonitorPanel)
+	monitorTab := lazyTab(views.TabMonitor, theme.InfoIcon(), views.NewMonitorPanel)
+	sessionsTab := lazyTab("Sessions", theme.HistoryIcon(), views.NewSessionsPanel)
 
-	tabs := container.NewAppTabs(dashTab, extremeTab, cleanTab, optimizeTab, cpuTab, monitorTab)
+	tabs := container.NewAppTabs(dashTab, extremeTab, cleanTab, optimizeTab, cpuTab, monitorTab, sessionsTab)
 	tabs.SetTabLocation(container.TabLocationLeading)
 
 	// Trigger lazy content initialization when a tab is selected
 	tabs.OnSelected = func(item *container.TabItem) {
+		views.SetActiveTab(item.Text)
 		if lc, ok := item.Content.(*lazyContainer); ok {
 			lc.once.Do(func() {
 				lc.real = lc.builder()
@@ -162,5 +325,16 @@ func createMainInterface(w fyne.Window) fyne.CanvasObject {
 		}
 	}
 
-	return tabs
+	// The status bar stays visible across tabs; its segments jump to the
+	// tab holding the relevant details.
+	statusBar := views.NewStatusBar(w, func(name string) {
+		for _, item := range tabs.Items {
+			if item.Text == name {
+				tabs.Select(item)
+				return
+			}
+		}
+	})
+
+	return container.NewBorder(nil, statusBar, nil, nil, tabs)
 }
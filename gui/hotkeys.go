@@ -0,0 +1,80 @@
+//go:build gui
+
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+
+	"syscleaner/gui/views"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/hotkey"
+	sysmem "syscleaner/pkg/memory"
+)
+
+// applyHotkeys registers the configured global hotkeys. Their results are
+// shown as notifications, since the game usually has focus.
+func applyHotkeys(a fyne.App, cfg *config.Config) {
+	notify := func(title, msg string) {
+		a.SendNotification(fyne.NewNotification(title, msg))
+	}
+	report := func(title string, err error) {
+		if err != nil {
+			views.RecordError(err)
+			notify(title+" failed", err.Error())
+			return
+		}
+		notify(title, "Done")
+	}
+
+	bindings, err := cfg.Hotkeys.Bindings(config.HotkeyActions{
+		ToggleGaming: func() {
+			if gaming.IsEnabled() {
+				report("Gaming Mode Off", gaming.Disable())
+				return
+			}
+			report("Gaming Mode On", gaming.Enable(gamingConfig(cfg)))
+		},
+		ToggleExtreme: func() {
+			if gaming.IsExtremeModeActive() {
+				report("Extreme Mode Off", gaming.DisableExtremeMode())
+				return
+			}
+			report("Extreme Mode On", gaming.EnableExtremeMode(cfg.ActiveProfileSettings().GamingConfig.ExtremeLevel))
+		},
+		PurgeRAM: func() {
+			report("RAM Purged", sysmem.TrimNow())
+		},
+	})
+	if err != nil {
+		views.RecordError(err)
+	}
+	if err := hotkey.Set(bindings); err != nil {
+		views.RecordError(err)
+	}
+}
+
+// gamingConfig builds the gaming mode settings for cfg's active profile.
+func gamingConfig(cfg *config.Config) gaming.Config {
+	profile := cfg.ActiveProfileSettings()
+	gaming.SetGameOverrides(cfg.GameOverrides)
+	gaming.SetQoSOverrides(profile.GamingConfig.QoSDSCP)
+	gaming.SetLatencyTargets(profile.GamingConfig.ServerEndpoints)
+	return gaming.Config{
+		AutoDetectGames:       true,
+		CPUBoost:              profile.GamingConfig.CPUBoost,
+		RAMReserveGB:          profile.GamingConfig.RAMReserveGB,
+		SuspendProcesses:      profile.GamingConfig.SuspendWhileGaming,
+		SuspendBackgroundApps: profile.GamingConfig.SuspendBackgroundApps,
+		Whitelist:             cfg.EffectiveWhitelist(),
+		FocusMode:             profile.GamingConfig.FocusMode,
+		DiscreteGPU:           profile.GamingConfig.DiscreteGPU,
+		GameSettings:          profile.GamingConfig.WindowsGameSettings,
+		HighResTimer:          profile.GamingConfig.HighResTimer,
+		PowerPlan:             profile.GamingConfig.PowerPlan,
+		PurgeStandby:          profile.GamingConfig.PurgeStandby,
+		USBLatency:            profile.GamingConfig.USBLatency,
+		TrimWorkingSets:       profile.GamingConfig.TrimWorkingSets,
+		Streaming:             profile.StreamingConfig(),
+	}
+}
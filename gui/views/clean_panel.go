@@ -4,16 +4,20 @@ package views
 
 import (
 	"fmt"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
+	"syscleaner/pkg/admin"
 	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
 )
 
 // NewCleanPanel creates the cleaning interface with granular category options.
-func NewCleanPanel() fyne.CanvasObject {
+func NewCleanPanel(w fyne.Window) fyne.CanvasObject {
 	statusLabel := widget.NewLabel("Ready to clean.")
 	statusLabel.Wrapping = fyne.TextWrapWord
 
@@ -26,6 +30,38 @@ func NewCleanPanel() fyne.CanvasObject {
 	progressBar.Stop()
 	progressBar.Hide()
 
+	// Most recent result, kept for report export
+	var lastResult *cleaner.CleanResult
+	exportBtn := widget.NewButton("Export Report...", func() {
+		if lastResult == nil {
+			return
+		}
+		result := *lastResult
+		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				showError(err, w)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			path := writer.URI().Path()
+			writer.Close()
+
+			format, err := cleaner.ReportFormatFromPath(path)
+			if err != nil {
+				showError(err, w)
+				return
+			}
+			if err := result.Export(format, path); err != nil {
+				showError(err, w)
+				return
+			}
+			dialog.ShowInformation("Report Exported", fmt.Sprintf("Report written to %s", path), w)
+		}, w)
+	})
+	exportBtn.Disable()
+
 	// System categories
 	winTempCheck := widget.NewCheck("Windows Temp", nil)
 	winTempCheck.SetChecked(true)
@@ -51,6 +87,7 @@ func NewCleanPanel() fyne.CanvasObject {
 	winUpdateCheck := widget.NewCheck("Windows Update Cache", nil)
 	winInstallerCheck := widget.NewCheck("Windows Installer Cache", nil)
 	fontCacheCheck := widget.NewCheck("Font Cache", nil)
+	fontIconRebuildCheck := widget.NewCheck("Rebuild Font/Icon Cache", nil)
 
 	// Browser categories
 	chromeCheck := widget.NewCheck("Chrome", nil)
@@ -77,15 +114,25 @@ func NewCleanPanel() fyne.CanvasObject {
 	vscodeCheck.SetChecked(true)
 	javaCheck := widget.NewCheck("Java", nil)
 	javaCheck.SetChecked(true)
+	storeAppsCheck := widget.NewCheck("Store Apps", nil)
+	storeAppsCheck.SetChecked(true)
+
+	// Privacy categories (unchecked by default)
+	recentCheck := widget.NewCheck("Recent Documents", nil)
+	jumpListsCheck := widget.NewCheck("Jump Lists", nil)
+	clipboardCheck := widget.NewCheck("Clipboard History", nil)
+	mruCheck := widget.NewCheck("Explorer MRU", nil)
 
 	systemChecks := []*widget.Check{
 		winTempCheck, userTempCheck, prefetchCheck, crashDumpCheck,
 		errorReportsCheck, thumbCacheCheck, iconCacheCheck, shaderCacheCheck,
 		dnsCacheCheck, winLogsCheck, eventLogsCheck, deliveryOptCheck,
 		recycleBinCheck, winUpdateCheck, winInstallerCheck, fontCacheCheck,
+		fontIconRebuildCheck,
 	}
 	browserChecks := []*widget.Check{chromeCheck, firefoxCheck, edgeCheck, braveCheck, operaCheck}
-	appChecks := []*widget.Check{discordCheck, spotifyCheck, steamCheck, teamsCheck, vscodeCheck, javaCheck}
+	appChecks := []*widget.Check{discordCheck, spotifyCheck, steamCheck, teamsCheck, vscodeCheck, javaCheck, storeAppsCheck}
+	privacyChecks := []*widget.Check{recentCheck, jumpListsCheck, clipboardCheck, mruCheck}
 
 	makeSelectAll := func(checks []*widget.Check, val bool) func() {
 		return func() {
@@ -111,6 +158,7 @@ func NewCleanPanel() fyne.CanvasObject {
 			EventLogs:            eventLogsCheck.Checked,
 			DeliveryOptimization: deliveryOptCheck.Checked,
 			RecycleBin:           recycleBinCheck.Checked,
+			FontIconRebuild:      fontIconRebuildCheck.Checked,
 			WindowsUpdate:        winUpdateCheck.Checked,
 			WindowsInstaller:     winInstallerCheck.Checked,
 			FontCache:            fontCacheCheck.Checked,
@@ -125,6 +173,11 @@ func NewCleanPanel() fyne.CanvasObject {
 			TeamsCache:           teamsCheck.Checked,
 			VSCodeCache:          vscodeCheck.Checked,
 			JavaCache:            javaCheck.Checked,
+			StoreAppCache:        storeAppsCheck.Checked,
+			RecentDocuments:      recentCheck.Checked,
+			JumpLists:            jumpListsCheck.Checked,
+			ClipboardHistory:     clipboardCheck.Checked,
+			ExplorerMRU:          mruCheck.Checked,
 			DryRun:               dryRun,
 		}
 	}
@@ -137,54 +190,90 @@ func NewCleanPanel() fyne.CanvasObject {
 
 		go func() {
 			opts := buildOpts(true)
-			result := cleaner.PerformClean(opts)
+			result := cleaner.Estimate(opts)
 			progressBar.Stop()
 			progressBar.Hide()
+			lastResult = &result
+			exportBtn.Enable()
 
 			statusLabel.SetText("Analysis complete.")
 			resultText.SetText(fmt.Sprintf(
-				"Files found: %d\nSpace reclaimable: %s\nDuration: %s\n\nRun 'Clean Now' to remove these files.",
+				"Files found: %d\nSpace reclaimable: %s\nDuration: %s%s\n\nRun 'Clean Now' to remove these files.",
 				result.FilesDeleted,
 				cleaner.FormatBytes(result.SpaceFreed),
-				result.Duration))
+				result.Duration,
+				categoryBreakdown(result)))
 		}()
 	})
 
+	closeHoldersCheck := widget.NewCheck("Close apps holding locked files and retry", nil)
+
 	// Clean button
 	cleanBtn := widget.NewButton("Clean Now", func() {
-		progressBar.Show()
-		progressBar.Start()
-		statusLabel.SetText("Cleaning system...")
+		var whitelist []string
+		var volumes cleaner.VolumePolicy
+		if cfg, err := config.LoadConfig(); err == nil {
+			whitelist = cfg.EffectiveWhitelist()
+			volumes = cfg.ActiveVolumePolicy()
+		}
+		cleaner.SetCloseLockHolders(closeHoldersCheck.Checked, whitelist)
 
-		go func() {
-			opts := buildOpts(false)
-			result := cleaner.PerformClean(opts)
-			progressBar.Stop()
-			progressBar.Hide()
+		selected := buildOpts(false)
+		selected.Volumes = volumes
+		opts, skips := cleaner.PlanForPrivileges(selected, admin.IsElevated())
+		confirmDegraded(w, skips, func() {
+			progressBar.Show()
+			progressBar.Start()
+			statusLabel.SetText("Cleaning system...")
 
-			statusLabel.SetText("Cleaning complete!")
-			text := fmt.Sprintf("Files removed: %d\nSpace freed: %s\nDuration: %s",
-				result.FilesDeleted,
-				cleaner.FormatBytes(result.SpaceFreed),
-				result.Duration)
-			if result.LockedFiles > 0 || result.PermissionFiles > 0 || len(result.Errors) > 0 {
-				text += "\n"
-				if result.LockedFiles > 0 {
-					text += fmt.Sprintf("\nSkipped (in use): %d", result.LockedFiles)
+			go func() {
+				result := cleaner.PerformClean(opts)
+				progressBar.Stop()
+				progressBar.Hide()
+				lastResult = &result
+				exportBtn.Enable()
+				if result.DryRun {
+					// Audit mode turned the clean into a preview.
+					statusLabel.SetText("Audit mode: nothing was deleted")
+					resultText.SetText(fmt.Sprintf("Would remove: %d files\nWould free: %s",
+						result.FilesDeleted, cleaner.FormatBytes(result.SpaceFreed)) + categoryBreakdown(result))
+					return
 				}
-				if result.PermissionFiles > 0 {
-					text += fmt.Sprintf("\nPermission errors: %d", result.PermissionFiles)
+				RecordClean(result)
+				if err := cleaner.SaveRunReport(cleaner.DefaultReportsDir(), result); err != nil {
+					RecordError(err)
 				}
-				if len(result.Errors) > 0 {
-					text += fmt.Sprintf("\nOther errors: %d", len(result.Errors))
+
+				statusLabel.SetText("Cleaning complete!")
+				text := fmt.Sprintf("Files removed: %d\nSpace freed: %s\nDuration: %s",
+					result.FilesDeleted,
+					cleaner.FormatBytes(result.SpaceFreed),
+					result.Duration)
+				text += categoryBreakdown(result)
+				if result.LockedFiles > 0 || result.PermissionFiles > 0 || len(result.Errors) > 0 {
+					text += "\n"
+					if result.LockedFiles > 0 {
+						text += fmt.Sprintf("\nSkipped (in use): %d", result.LockedFiles)
+						for _, ce := range result.Locked {
+							if len(ce.Holders) > 0 {
+								text += "\n  " + ce.Error()
+							}
+						}
+					}
+					if result.PermissionFiles > 0 {
+						text += fmt.Sprintf("\nPermission errors: %d", result.PermissionFiles)
+					}
+					if len(result.Errors) > 0 {
+						text += fmt.Sprintf("\nOther errors: %d", len(result.Errors))
+					}
 				}
-			}
-			resultText.SetText(text)
-		}()
+				resultText.SetText(text)
+			}()
+		})
 	})
 	cleanBtn.Importance = widget.HighImportance
 
-	buttonRow := container.NewGridWithColumns(2, analyzeBtn, cleanBtn)
+	buttonRow := container.NewGridWithColumns(3, analyzeBtn, cleanBtn, exportBtn)
 
 	// System section with select all/deselect all
 	sysSelectAll := widget.NewButton("Select All", makeSelectAll(systemChecks, true))
@@ -198,6 +287,7 @@ func NewCleanPanel() fyne.CanvasObject {
 		errorReportsCheck, thumbCacheCheck, iconCacheCheck, shaderCacheCheck,
 		dnsCacheCheck, winLogsCheck, eventLogsCheck, deliveryOptCheck,
 		recycleBinCheck, winUpdateCheck, winInstallerCheck, fontCacheCheck,
+		fontIconRebuildCheck,
 	)
 
 	// Browser section
@@ -221,6 +311,18 @@ func NewCleanPanel() fyne.CanvasObject {
 	appGrid := container.NewGridWithColumns(3,
 		discordCheck, spotifyCheck, steamCheck,
 		teamsCheck, vscodeCheck, javaCheck,
+		storeAppsCheck,
+	)
+
+	// Privacy section
+	privacySelectAll := widget.NewButton("Select All", makeSelectAll(privacyChecks, true))
+	privacyDeselectAll := widget.NewButton("Deselect All", makeSelectAll(privacyChecks, false))
+	privacyHeader := container.NewHBox(
+		widget.NewLabelWithStyle("Privacy", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		privacySelectAll, privacyDeselectAll,
+	)
+	privacyGrid := container.NewGridWithColumns(4,
+		recentCheck, jumpListsCheck, clipboardCheck, mruCheck,
 	)
 
 	content := container.NewVBox(
@@ -235,6 +337,12 @@ func NewCleanPanel() fyne.CanvasObject {
 		appHeader,
 		appGrid,
 		widget.NewSeparator(),
+		privacyHeader,
+		privacyGrid,
+		widget.NewSeparator(),
+		newStorageSenseSection(w),
+		widget.NewSeparator(),
+		closeHoldersCheck,
 		buttonRow,
 		widget.NewSeparator(),
 		statusLabel,
@@ -244,3 +352,20 @@ func NewCleanPanel() fyne.CanvasObject {
 
 	return container.NewScroll(container.NewPadded(content))
 }
+
+// categoryBreakdown formats the per-category space totals, largest first,
+// skipping categories that found nothing.
+func categoryBreakdown(result cleaner.CleanResult) string {
+	var b strings.Builder
+	for _, cat := range result.CategoriesBySpace() {
+		c := result.Categories[cat]
+		if c.SpaceFreed == 0 && c.FilesDeleted == 0 {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\n  %s: %s (%d files)", cat, cleaner.FormatBytes(c.SpaceFreed), c.FilesDeleted)
+	}
+	return b.String()
+}
@@ -54,10 +54,12 @@ func NewDashboard() fyne.CanvasObject {
 
 	// Real-time update goroutine with smooth animations
 	go func() {
-		ticker := time.NewTicker(2 * time.Second)
+		ticker := time.NewTicker(DashboardRefresh)
 		defer ticker.Stop()
 
 		for range ticker.C {
+			waitVisible(TabDashboard)
+
 			// CPU with smooth transition
 			if cpuPercent, err := cpu.Percent(500*time.Millisecond, false); err == nil && len(cpuPercent) > 0 {
 				targetCPU := cpuPercent[0] / 100.0
@@ -0,0 +1,38 @@
+//go:build gui
+
+package views
+
+import (
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"syscleaner/pkg/admin"
+)
+
+// confirmDegraded shows what will be skipped without administrator rights
+// and lets the user either restart elevated or run the rest. run is called
+// immediately when nothing is skipped.
+func confirmDegraded(w fyne.Window, skips []admin.Skip, run func()) {
+	if len(skips) == 0 {
+		run()
+		return
+	}
+	msg := widget.NewLabel("SysCleaner is not running as administrator. These will be skipped:\n\n" +
+		admin.FormatSkips(skips))
+	msg.Wrapping = fyne.TextWrapWord
+	dialog.ShowCustomConfirm("Limited Permissions", "Restart as Administrator", "Run the Rest", msg,
+		func(elevate bool) {
+			if !elevate {
+				run()
+				return
+			}
+			if err := admin.Relaunch(os.Args[1:]); err != nil {
+				showError(err, w)
+				return
+			}
+			fyne.CurrentApp().Quit()
+		}, w)
+}
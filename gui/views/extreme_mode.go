@@ -13,6 +13,8 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
+	"syscleaner/pkg/agent"
+	"syscleaner/pkg/config"
 	"syscleaner/pkg/gaming"
 )
 
@@ -21,6 +23,7 @@ type extremeModePanel struct {
 	statusLabel *widget.Label
 	toggleBtn   *widget.Button
 	isActive    bool
+	level       string
 }
 
 // NewExtremeModePanel creates the extreme performance mode panel.
@@ -29,7 +32,17 @@ func NewExtremeModePanel(w fyne.Window) fyne.CanvasObject {
 		window:      w,
 		statusLabel: widget.NewLabelWithStyle("Extreme Mode: Inactive", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
 		isActive:    gaming.IsExtremeModeActive(),
+		level:       gaming.ExtremeFull,
 	}
+	if cfg, err := config.LoadConfig(); err == nil {
+		if level := cfg.ActiveProfileSettings().GamingConfig.ExtremeLevel; level != "" {
+			panel.level = strings.ToLower(level)
+		}
+	}
+	levelSelect := widget.NewSelect(gaming.ExtremeLevels, func(level string) {
+		panel.level = level
+	})
+	levelSelect.SetSelected(panel.level)
 
 	panel.toggleBtn = widget.NewButton("ACTIVATE EXTREME PERFORMANCE MODE", func() {
 		panel.toggleExtremeMode()
@@ -41,12 +54,14 @@ func NewExtremeModePanel(w fyne.Window) fyne.CanvasObject {
 	flameLabel := widget.NewLabelWithStyle("EXTREME PERFORMANCE", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
 	// Warning
-	warningText := widget.NewLabel("WARNING: Extreme Mode stops Windows Explorer shell.\nOnly use when launching games from this panel.\nYour desktop and taskbar will be unavailable.")
+	warningText := widget.NewLabel("Light closes background apps, Medium also stops non-essential services.\n" +
+		"WARNING: the Extreme level also stops the Windows Explorer shell.\nOnly use it when launching games from this panel.\nYour desktop and taskbar will be unavailable.")
 	warningText.Wrapping = fyne.TextWrapWord
 	warningText.Alignment = fyne.TextAlignCenter
 
 	// Process whitelist section
 	whitelistSection := createWhitelistSection()
+	whitelistWarnings := createWhitelistWarnings()
 
 	// Game profile selector
 	gameProfileSection := createGameProfileSection()
@@ -59,6 +74,7 @@ func NewExtremeModePanel(w fyne.Window) fyne.CanvasObject {
 		ticker := time.NewTicker(2 * time.Second)
 		defer ticker.Stop()
 		for range ticker.C {
+			waitVisible(TabExtremeMode)
 			active := gaming.IsExtremeModeActive()
 			if active != panel.isActive {
 				panel.isActive = active
@@ -71,6 +87,7 @@ func NewExtremeModePanel(w fyne.Window) fyne.CanvasObject {
 		flameLabel,
 		widget.NewSeparator(),
 		container.NewCenter(panel.statusLabel),
+		container.NewCenter(container.NewHBox(widget.NewLabel("Level:"), levelSelect)),
 		container.NewCenter(panel.toggleBtn),
 		widget.NewSeparator(),
 		warningText,
@@ -78,10 +95,14 @@ func NewExtremeModePanel(w fyne.Window) fyne.CanvasObject {
 		widget.NewLabelWithStyle("Process Whitelist", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		widget.NewLabel("These processes will NOT be killed when Extreme Mode activates:"),
 		whitelistSection,
+		whitelistWarnings,
 		widget.NewSeparator(),
 		widget.NewLabelWithStyle("Game Optimization Profile", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		gameProfileSection,
 		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Background Agent", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		createAgentSection(w),
+		widget.NewSeparator(),
 		widget.NewLabelWithStyle("Game Launchers", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		launcherSection,
 	)
@@ -90,26 +111,30 @@ func NewExtremeModePanel(w fyne.Window) fyne.CanvasObject {
 }
 
 func (p *extremeModePanel) toggleExtremeMode() {
+	// A hotkey may have changed the mode since the panel last looked.
+	p.isActive = gaming.IsExtremeModeActive()
 	if p.isActive {
 		if err := gaming.DisableExtremeMode(); err != nil {
-			dialog.ShowError(err, p.window)
+			showError(err, p.window)
 			return
 		}
 		p.isActive = false
 		dialog.ShowInformation("Extreme Mode Disabled", "System restored to normal mode.", p.window)
 	} else {
-		dialog.ShowConfirm(
-			"Activate Extreme Performance Mode?",
-			"This will:\n\n"+
-				"  - Stop Windows Explorer (no desktop/taskbar)\n"+
-				"  - Stop all non-essential services\n"+
-				"  - Close background apps (respecting whitelist)\n"+
-				"  - Maximize game performance\n\n"+
-				"You can only launch games from this window.\nContinue?",
+		plan := gaming.PlanExtremeMode(p.level)
+		msg := widget.NewLabel(plan.String())
+		msg.Wrapping = fyne.TextWrapWord
+		note := "Continue?"
+		if plan.StopsShell {
+			note = "You can only launch games from this window.\nContinue?"
+		}
+		content := container.NewBorder(nil, widget.NewLabel(note), nil, nil, container.NewVScroll(msg))
+		confirm := dialog.NewCustomConfirm(
+			"Activate Extreme Performance Mode?", "Activate", "Cancel", content,
 			func(confirmed bool) {
 				if confirmed {
-					if err := gaming.EnableExtremeMode(); err != nil {
-						dialog.ShowError(err, p.window)
+					if err := gaming.EnableExtremeMode(plan.Level); err != nil {
+						showError(err, p.window)
 						return
 					}
 					p.isActive = true
@@ -119,6 +144,8 @@ func (p *extremeModePanel) toggleExtremeMode() {
 			},
 			p.window,
 		)
+		confirm.Resize(fyne.NewSize(560, 420))
+		confirm.Show()
 		return // updateUI will be called in the callback
 	}
 	p.updateUI()
@@ -126,7 +153,7 @@ func (p *extremeModePanel) toggleExtremeMode() {
 
 func (p *extremeModePanel) updateUI() {
 	if p.isActive {
-		p.statusLabel.SetText("EXTREME MODE ACTIVE")
+		p.statusLabel.SetText(fmt.Sprintf("EXTREME MODE ACTIVE (%s)", gaming.ExtremeModeLevel()))
 		p.toggleBtn.SetText("Disable Extreme Mode & Restore System")
 		p.toggleBtn.Importance = widget.DangerImportance
 	} else {
@@ -167,6 +194,33 @@ func createWhitelistSection() fyne.CanvasObject {
 	return container.NewGridWithColumns(4, grid...)
 }
 
+// createWhitelistWarnings returns a label listing configured whitelist
+// entries that match no known or running process, filled in the background
+// because listing processes takes a moment. It stays hidden when there are
+// none.
+func createWhitelistWarnings() fyne.CanvasObject {
+	label := widget.NewLabel("")
+	label.Wrapping = fyne.TextWrapWord
+	label.Hide()
+	go func() {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return
+		}
+		warnings := cfg.WhitelistWarnings()
+		if len(warnings) == 0 {
+			return
+		}
+		lines := []string{"Check the process whitelist in your config:"}
+		for _, w := range warnings {
+			lines = append(lines, "  - "+w.String())
+		}
+		label.SetText(strings.Join(lines, "\n"))
+		label.Show()
+	}()
+	return label
+}
+
 func updateWhitelist(checks map[string]*widget.Check) {
 	var whitelist []string
 	for name, check := range checks {
@@ -183,7 +237,7 @@ func createGameProfileSection() fyne.CanvasObject {
 	infoLabel.Wrapping = fyne.TextWrapWord
 
 	options := []string{"None (Manual)"}
-	for _, g := range gaming.PredefinedGames {
+	for _, g := range gaming.Games() {
 		options = append(options, g.Name)
 	}
 
@@ -222,6 +276,33 @@ func createGameProfileSection() fyne.CanvasObject {
 	return container.NewVBox(profileLabel, selector, infoLabel)
 }
 
+// createAgentSection offers to install the background agent, which turns
+// gaming mode on for games started while SysCleaner is closed.
+func createAgentSection(w fyne.Window) fyne.CanvasObject {
+	check := widget.NewCheck("Auto-boost games in the background, even with SysCleaner closed (starts at logon)", nil)
+	if installed, err := agent.Installed(); err == nil {
+		check.SetChecked(installed)
+	}
+	var toggle func(bool)
+	toggle = func(on bool) {
+		var err error
+		if on {
+			err = agent.Install()
+		} else {
+			err = agent.Uninstall()
+		}
+		if err != nil {
+			showError(err, w)
+			// Put the box back without running the toggle again.
+			check.OnChanged = nil
+			check.SetChecked(!on)
+			check.OnChanged = toggle
+		}
+	}
+	check.OnChanged = toggle
+	return check
+}
+
 func createGameLaunchers(w fyne.Window) fyne.CanvasObject {
 	launchers := []struct {
 		name string
@@ -245,7 +326,7 @@ func createGameLaunchers(w fyne.Window) fyne.CanvasObject {
 		btn := widget.NewButton(fmt.Sprintf("Launch %s", name), func() {
 			cmd := exec.Command(exe)
 			if err := cmd.Start(); err != nil {
-				dialog.ShowError(fmt.Errorf("failed to launch %s: %v", name, err), w)
+				showError(fmt.Errorf("failed to launch %s: %v", name, err), w)
 			} else {
 				dialog.ShowInformation("Launched", fmt.Sprintf("%s started successfully!", name), w)
 			}
@@ -4,6 +4,7 @@ package views
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,8 +16,10 @@ import (
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/net"
 
+	"syscleaner/pkg/config"
 	"syscleaner/pkg/gaming"
 	sysmem "syscleaner/pkg/memory"
+	"syscleaner/pkg/monitor"
 )
 
 // NewMonitorPanel creates the real-time monitoring view with RAM monitoring
@@ -24,6 +27,8 @@ func NewMonitorPanel() fyne.CanvasObject {
 	cpuLabel := widget.NewLabel("CPU: --")
 	ramLabel := widget.NewLabel("RAM: --")
 	netLabel := widget.NewLabel("Network: --")
+	timerLabel := widget.NewLabel("Timer resolution: --")
+	latencyLabel := widget.NewLabel("")
 
 	cpuProgress := widget.NewProgressBar()
 	ramProgress := widget.NewProgressBar()
@@ -71,19 +76,168 @@ func NewMonitorPanel() fyne.CanvasObject {
 		logText.SetText(entry + current)
 	}
 
+	// GPU section: sampled less often since each sample takes a second
+	gpuLabel := widget.NewLabel("GPU: --")
+	gpuStateLabel := widget.NewLabel("Power state: --")
+	vramLabel := widget.NewLabel("VRAM: --")
+	gpuTempLabel := widget.NewLabel("")
+	gpuProcs := container.NewVBox()
+
+	updateGPU := func(status monitor.GPUStatus) {
+		name := status.Name
+		if name == "" {
+			name = "GPU"
+		}
+		if status.GPUBound() {
+			gpuLabel.SetText(fmt.Sprintf("%s: %.1f%% (GPU-bound)", name, status.Utilization))
+		} else {
+			gpuLabel.SetText(fmt.Sprintf("%s: %.1f%%", name, status.Utilization))
+		}
+		switch {
+		case status.VRAMTotal > 0:
+			vramLabel.SetText(fmt.Sprintf("VRAM: %.1f / %.1f GB (%.0f%%)",
+				float64(status.VRAMUsed)/1024/1024/1024, float64(status.VRAMTotal)/1024/1024/1024, status.VRAMPercent()))
+		case status.VRAMUsed > 0:
+			vramLabel.SetText(fmt.Sprintf("VRAM: %.1f GB in use", float64(status.VRAMUsed)/1024/1024/1024))
+		default:
+			vramLabel.SetText("VRAM: --")
+		}
+		if status.TemperatureC > 0 {
+			gpuTempLabel.SetText(fmt.Sprintf("Temperature: %d°C", status.TemperatureC))
+		} else {
+			gpuTempLabel.SetText("")
+		}
+		if status.PowerState != "" {
+			gpuStateLabel.SetText(fmt.Sprintf("Power state: %s (%d / %d MHz)",
+				status.PowerState, status.GraphicsClockMHz, status.MemoryClockMHz))
+		} else if status.Awake {
+			gpuStateLabel.SetText("Power state: awake")
+		} else {
+			gpuStateLabel.SetText("Power state: idle")
+		}
+
+		gpuProcs.RemoveAll()
+		for _, p := range status.Processes {
+			if p.Name == "" {
+				continue
+			}
+			exe := p.Name
+			label := widget.NewLabel(fmt.Sprintf("%s (PID %d): %.1f%%", exe, p.PID, p.Utilization))
+			var btn *widget.Button
+			btn = widget.NewButton("Suspend while gaming", func() {
+				cfg, err := config.LoadConfig()
+				if err == nil {
+					err = config.AddSuspendWhileGaming(cfg.ActiveProfile, exe)
+				}
+				if err != nil {
+					addLog(fmt.Sprintf("Could not add %s to suspend list: %v", exe, err), true)
+					return
+				}
+				addLog(fmt.Sprintf("%s will be suspended while gaming", exe), false)
+				btn.Disable()
+			})
+			gpuProcs.Add(container.NewBorder(nil, nil, nil, btn, label))
+		}
+	}
+
+	go func() {
+		wasAwake, wasPressure := false, false
+		for {
+			waitVisible(TabMonitor)
+			status := monitor.CheckGPU()
+			updateGPU(status)
+			if status.Awake && !wasAwake && !gaming.IsEnabled() && len(status.Processes) > 0 {
+				addLog(fmt.Sprintf("GPU kept awake by %s", status.Processes[0].Name), true)
+			}
+			if status.VRAMPressure() && !wasPressure && gaming.IsEnabled() {
+				addLog(fmt.Sprintf("VRAM nearly full (%.0f%%) - textures may spill into RAM and stutter", status.VRAMPercent()), true)
+			}
+			wasAwake, wasPressure = status.Awake, status.VRAMPressure()
+			time.Sleep(GPURefresh)
+		}
+	}()
+
+	// Frame rate section: an ETW capture of DXGI presents, run only while
+	// the user asks for it since it needs its own trace session.
+	fpsLabel := widget.NewLabel("FPS: capture off")
+	var overlay *fpsOverlay
+	var capture *monitor.FrameCapture
+	var captureMu sync.Mutex
+	overlayCheck := widget.NewCheck("Show overlay", func(on bool) {
+		captureMu.Lock()
+		defer captureMu.Unlock()
+		if on && overlay == nil {
+			overlay = newFPSOverlay()
+		} else if !on && overlay != nil {
+			overlay.Close()
+			overlay = nil
+		}
+	})
+	overlayCheck.Disable()
+	captureCheck := widget.NewCheck("Capture FPS of the focused game", func(on bool) {
+		if !on {
+			captureMu.Lock()
+			c := capture
+			capture = nil
+			captureMu.Unlock()
+			if c != nil {
+				c.Stop()
+			}
+			overlayCheck.SetChecked(false)
+			overlayCheck.Disable()
+			fpsLabel.SetText("FPS: capture off")
+			return
+		}
+		c, err := monitor.StartFrameCapture()
+		if err != nil {
+			addLog(fmt.Sprintf("Could not start frame capture: %v", err), true)
+			fpsLabel.SetText("FPS: unavailable")
+			return
+		}
+		captureMu.Lock()
+		capture = c
+		captureMu.Unlock()
+		overlayCheck.Enable()
+	})
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			captureMu.Lock()
+			c, o := capture, overlay
+			captureMu.Unlock()
+			if c == nil {
+				continue
+			}
+			text := "FPS: no frames from the focused window"
+			if s, ok := c.FocusedStats(); ok && s.Frames > 0 {
+				text = fmt.Sprintf("FPS: %.0f | 1%% low: %.0f | frame time: %.1f ms (max %.1f ms)",
+					s.FPS, s.Low1, float64(s.AvgFrameTime)/float64(time.Millisecond),
+					float64(s.MaxFrameTime)/float64(time.Millisecond))
+				if o != nil {
+					o.Update(s)
+				}
+			}
+			fpsLabel.SetText(text)
+		}
+	}()
+
 	// Track previous network counters for rate calculation
 	var prevBytesRecv, prevBytesSent uint64
 	var prevTime time.Time
 
 	// Start monitoring
 	go func() {
-		ticker := time.NewTicker(1 * time.Second)
+		ticker := time.NewTicker(MonitorRefresh)
 		defer ticker.Stop()
 
 		lastGameModeCheck := false
 		lastExtremeModeCheck := false
 
 		for range ticker.C {
+			waitVisible(TabMonitor)
+
 			// CPU usage
 			if cpuPercent, err := cpu.Percent(500*time.Millisecond, false); err == nil && len(cpuPercent) > 0 {
 				cpuProgress.SetValue(cpuPercent[0] / 100.0)
@@ -125,6 +279,21 @@ func NewMonitorPanel() fyne.CanvasObject {
 				}
 			}
 
+			if res, err := monitor.CheckTimerResolution(); err == nil {
+				timerLabel.SetText(fmt.Sprintf("Timer resolution: %s (finest %.2f ms)",
+					res, float64(res.Finest)/float64(time.Millisecond)))
+			}
+
+			if game, stats := gaming.LatencyStats(); game != "" {
+				lines := []string{fmt.Sprintf("%s servers:", game)}
+				for _, s := range stats {
+					lines = append(lines, s.String())
+				}
+				latencyLabel.SetText(strings.Join(lines, "\n"))
+			} else {
+				latencyLabel.SetText("")
+			}
+
 			// Network usage (calculate rate)
 			if netIO, err := net.IOCounters(false); err == nil && len(netIO) > 0 {
 				now := time.Now()
@@ -168,6 +337,7 @@ func NewMonitorPanel() fyne.CanvasObject {
 		widget.NewLabelWithStyle("CPU Usage", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		cpuLabel,
 		cpuProgress,
+		timerLabel,
 	)
 
 	// RAM section
@@ -181,6 +351,7 @@ func NewMonitorPanel() fyne.CanvasObject {
 	netSection := container.NewVBox(
 		widget.NewLabelWithStyle("Network", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		netLabel,
+		latencyLabel,
 	)
 
 	metrics := container.NewGridWithColumns(3,
@@ -189,6 +360,21 @@ func NewMonitorPanel() fyne.CanvasObject {
 		netSection,
 	)
 
+	gpuSection := container.NewVBox(
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("GPU", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewGridWithColumns(2, gpuLabel, gpuStateLabel),
+		container.NewGridWithColumns(2, vramLabel, gpuTempLabel),
+		gpuProcs,
+	)
+
+	fpsSection := container.NewVBox(
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Frame Rate", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewHBox(captureCheck, overlayCheck),
+		fpsLabel,
+	)
+
 	// RAM Monitor Section (visible only when Extreme Mode is active)
 	ramMonitorSection := container.NewVBox(
 		widget.NewSeparator(),
@@ -232,6 +418,8 @@ func NewMonitorPanel() fyne.CanvasObject {
 		widget.NewLabelWithStyle("Real-Time Monitor", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		widget.NewSeparator(),
 		metrics,
+		gpuSection,
+		fpsSection,
 		ramMonitorSection,
 		widget.NewSeparator(),
 		logsHeader,
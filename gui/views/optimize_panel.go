@@ -4,16 +4,20 @@ package views
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
+	"syscleaner/pkg/admin"
 	"syscleaner/pkg/optimizer"
+	"syscleaner/pkg/reglog"
 )
 
 // NewOptimizePanel creates the optimization controls view.
-func NewOptimizePanel() fyne.CanvasObject {
+func NewOptimizePanel(w fyne.Window) fyne.CanvasObject {
 	resultText := widget.NewMultiLineEntry()
 	resultText.SetPlaceHolder("Optimization results will appear here...")
 	resultText.Disable()
@@ -31,7 +35,7 @@ func NewOptimizePanel() fyne.CanvasObject {
 		statusLabel.SetText("Optimizing startup programs...")
 
 		go func() {
-			result := optimizer.OptimizeStartup()
+			result := optimizer.OptimizeStartup(optimizer.OptimizeOptions{})
 			progressBar.Stop()
 			progressBar.Hide()
 			statusLabel.SetText("Startup optimization complete.")
@@ -56,7 +60,7 @@ func NewOptimizePanel() fyne.CanvasObject {
 		statusLabel.SetText("Optimizing network settings...")
 
 		go func() {
-			result := optimizer.OptimizeNetwork()
+			result := optimizer.OptimizeNetwork(optimizer.OptimizeOptions{})
 			progressBar.Stop()
 			progressBar.Hide()
 			statusLabel.SetText("Network optimization complete.")
@@ -65,6 +69,9 @@ func NewOptimizePanel() fyne.CanvasObject {
 			for _, opt := range result.Optimizations {
 				text += fmt.Sprintf("  - %s\n", opt)
 			}
+			for _, e := range result.Errors {
+				text += fmt.Sprintf("  Error: %s\n", e)
+			}
 			resultText.SetText(text)
 		}()
 	})
@@ -77,62 +84,188 @@ func NewOptimizePanel() fyne.CanvasObject {
 		statusLabel.SetText("Optimizing disk...")
 
 		go func() {
-			result := optimizer.OptimizeDisk()
+			result := optimizer.OptimizeDisk(optimizer.OptimizeOptions{})
 			progressBar.Stop()
 			progressBar.Hide()
 			statusLabel.SetText("Disk optimization complete.")
 
-			diskType := "HDD"
-			if result.IsSSD {
-				diskType = "SSD"
-			}
-			text := fmt.Sprintf("Disk Optimization:\n  Disk type: %s\n", diskType)
-			if result.Scheduled {
-				if result.IsSSD {
-					text += "  TRIM enabled for optimal SSD performance\n"
-				} else {
-					text += "  Weekly defragmentation scheduled\n"
+			text := "Disk Optimization:\n"
+			for _, v := range result.Volumes {
+				status := "left alone"
+				switch {
+				case v.Error != "":
+					status = "failed: " + v.Error
+				case v.Action == optimizer.DiskRetrim:
+					status = "retrimmed"
+				case v.Action == optimizer.DiskDefrag:
+					status = "weekly defragmentation scheduled"
 				}
+				text += fmt.Sprintf("  %s (%s): %s\n", v.Drive, v.MediaType, status)
+			}
+			for _, e := range result.Errors {
+				text += fmt.Sprintf("  Error: %s\n", e)
 			}
 			resultText.SetText(text)
 		}()
 	})
 	diskBtn.Importance = widget.HighImportance
 
-	// Run all
-	allBtn := widget.NewButton("Run All Optimizations", func() {
+	// Visual effects
+	keepFontsCheck := widget.NewCheck("Keep font smoothing", nil)
+	keepFontsCheck.SetChecked(true)
+	visualBtn := widget.NewButton("Optimize Visual Effects", func() {
 		progressBar.Show()
 		progressBar.Start()
-		statusLabel.SetText("Running all optimizations...")
+		statusLabel.SetText("Adjusting visual effects for best performance...")
 
 		go func() {
-			text := ""
-
-			// Startup
-			startupResult := optimizer.OptimizeStartup()
-			text += fmt.Sprintf("Startup: %d programs disabled\n", startupResult.Disabled)
-
-			// Network
-			netResult := optimizer.OptimizeNetwork()
-			text += fmt.Sprintf("Network: %dms latency reduction, %d optimizations\n",
-				netResult.LatencyReduction, len(netResult.Optimizations))
-
-			// Disk
-			diskResult := optimizer.OptimizeDisk()
-			diskType := "HDD"
-			if diskResult.IsSSD {
-				diskType = "SSD"
-			}
-			text += fmt.Sprintf("Disk: %s optimized\n", diskType)
-
+			result := optimizer.OptimizeVisualEffects(optimizer.OptimizeOptions{KeepFontSmoothing: keepFontsCheck.Checked})
 			progressBar.Stop()
 			progressBar.Hide()
-			statusLabel.SetText("All optimizations complete!")
+			statusLabel.SetText("Visual effects optimization complete.")
+
+			text := "Visual Effects Optimization:\n"
+			for _, opt := range result.Optimizations {
+				text += fmt.Sprintf("  %s\n", opt)
+			}
+			for _, e := range result.Errors {
+				text += fmt.Sprintf("  Error: %s\n", e)
+			}
 			resultText.SetText(text)
 		}()
 	})
+
+	// Run all
+	allBtn := widget.NewButton("Run All Optimizations", func() {
+		startup, network, disk, skips := optimizer.PlanForPrivileges(true, true, true, admin.IsElevated())
+		confirmDegraded(w, skips, func() {
+			progressBar.Show()
+			progressBar.Start()
+			statusLabel.SetText("Running all optimizations...")
+
+			go func() {
+				text := ""
+
+				if startup {
+					startupResult := optimizer.OptimizeStartup(optimizer.OptimizeOptions{})
+					text += fmt.Sprintf("Startup: %d programs disabled\n", startupResult.Disabled)
+				}
+
+				if network {
+					netResult := optimizer.OptimizeNetwork(optimizer.OptimizeOptions{})
+					text += fmt.Sprintf("Network: %dms latency reduction, %d optimizations\n",
+						netResult.LatencyReduction, len(netResult.Optimizations))
+				}
+
+				if disk {
+					diskResult := optimizer.OptimizeDisk(optimizer.OptimizeOptions{})
+					diskType := "HDD"
+					if diskResult.IsSSD {
+						diskType = "SSD"
+					}
+					text += fmt.Sprintf("Disk: %s optimized\n", diskType)
+				}
+
+				for _, s := range skips {
+					text += fmt.Sprintf("%s: skipped (needs administrator)\n", s.Item)
+				}
+
+				progressBar.Stop()
+				progressBar.Hide()
+				statusLabel.SetText("All optimizations complete!")
+				resultText.SetText(text)
+			}()
+		})
+	})
 	allBtn.Importance = widget.WarningImportance
 
+	// Registry change log export
+	exportRegBtn := widget.NewButton("Export Registry Change Log...", func() {
+		rec := reglog.Current()
+		if rec == nil {
+			dialog.ShowInformation("Registry Logging Disabled",
+				"Enable registry logging in the configuration to record changes.", w)
+			return
+		}
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				showError(err, w)
+				return
+			}
+			if dir == nil {
+				return
+			}
+			undoPath := filepath.Join(dir.Path(), filepath.Base(rec.UndoPath()))
+			logPath := filepath.Join(dir.Path(), filepath.Base(rec.LogPath()))
+			if err := rec.ExportUndo(undoPath); err != nil {
+				showError(err, w)
+				return
+			}
+			if err := rec.ExportLog(logPath); err != nil {
+				showError(err, w)
+				return
+			}
+			dialog.ShowInformation("Registry Log Exported",
+				fmt.Sprintf("%d change(s) exported:\n%s\n%s", len(rec.Changes()), undoPath, logPath), w)
+		}, w)
+	})
+
+	// Undo of the newest journaled optimizer run
+	undoBtn := widget.NewButton("Undo Last Optimization", func() {
+		j, err := optimizer.LastJournal()
+		if err != nil {
+			dialog.ShowInformation("Nothing to Undo", err.Error(), w)
+			return
+		}
+		dialog.ShowConfirm("Undo Last Optimization?",
+			fmt.Sprintf("Put back the %d setting(s) changed by %s?", j.Count(), j.ID),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				n, err := optimizer.Undo(j.ID)
+				if err != nil {
+					showError(err, w)
+				}
+				statusLabel.SetText(fmt.Sprintf("Restored %d setting(s).", n))
+			}, w)
+	})
+
+	// Import of a registry backup exported before an optimizer run
+	restoreBackupBtn := widget.NewButton("Restore Registry Backup...", func() {
+		backups, err := optimizer.RegistryBackups()
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		if len(backups) == 0 {
+			dialog.ShowInformation("No Registry Backups",
+				"Optimizer runs back up the registry keys they change before changing them.", w)
+			return
+		}
+		names := make([]string, len(backups))
+		for i, b := range backups {
+			names[i] = b.Name
+		}
+		selector := widget.NewSelect(names, nil)
+		selector.SetSelectedIndex(0)
+		dialog.ShowCustomConfirm("Restore Registry Backup", "Import", "Cancel",
+			container.NewVBox(
+				widget.NewLabel("Put the keys in this backup back as they were before that run?"),
+				selector,
+			),
+			func(ok bool) {
+				if !ok || selector.Selected == "" {
+					return
+				}
+				if err := optimizer.RestoreRegistryBackup(selector.Selected); err != nil {
+					showError(err, w)
+					return
+				}
+				statusLabel.SetText("Imported registry backup " + selector.Selected + ".")
+			}, w)
+	})
+
 	buttonGrid := container.NewGridWithColumns(3,
 		startupBtn,
 		networkBtn,
@@ -146,6 +279,10 @@ func NewOptimizePanel() fyne.CanvasObject {
 		buttonGrid,
 		widget.NewSeparator(),
 		allBtn,
+		container.NewHBox(visualBtn, keepFontsCheck),
+		undoBtn,
+		restoreBackupBtn,
+		exportRegBtn,
 		widget.NewSeparator(),
 		statusLabel,
 		progressBar,
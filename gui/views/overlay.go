@@ -0,0 +1,53 @@
+//go:build gui
+
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+
+	"syscleaner/pkg/monitor"
+)
+
+// overlayTitle identifies the overlay window, so it can be kept on top.
+const overlayTitle = "SysCleaner FPS"
+
+// fpsOverlay is a small borderless window showing the frame rate above
+// the game. Games running in exclusive fullscreen draw over it; borderless
+// windowed mode is needed to see it.
+type fpsOverlay struct {
+	win   fyne.Window
+	label *widget.Label
+}
+
+func newFPSOverlay() *fpsOverlay {
+	app := fyne.CurrentApp()
+	var win fyne.Window
+	if drv, ok := app.Driver().(desktop.Driver); ok {
+		win = drv.CreateSplashWindow()
+		win.SetTitle(overlayTitle)
+	} else {
+		win = app.NewWindow(overlayTitle)
+	}
+	label := widget.NewLabel("FPS: --")
+	win.SetContent(label)
+	win.Resize(fyne.NewSize(180, 40))
+	win.Show()
+	// The window only exists once Show has been handled.
+	time.AfterFunc(200*time.Millisecond, func() { keepOnTop(overlayTitle) })
+	return &fpsOverlay{win: win, label: label}
+}
+
+// Update shows s in the overlay.
+func (o *fpsOverlay) Update(s monitor.FrameStats) {
+	o.label.SetText(fmt.Sprintf("%.0f FPS  1%% %.0f", s.FPS, s.Low1))
+}
+
+// Close closes the overlay window.
+func (o *fpsOverlay) Close() {
+	o.win.Close()
+}
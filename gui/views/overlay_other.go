@@ -0,0 +1,6 @@
+//go:build gui && !windows
+
+package views
+
+// keepOnTop is only available on Windows.
+func keepOnTop(title string) {}
@@ -0,0 +1,35 @@
+//go:build gui && windows
+
+package views
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32           = windows.NewLazySystemDLL("user32.dll")
+	procFindWindowW  = user32.NewProc("FindWindowW")
+	procSetWindowPos = user32.NewProc("SetWindowPos")
+)
+
+// keepOnTop makes the window titled title topmost, which Fyne has no API
+// for.
+func keepOnTop(title string) {
+	const (
+		swpNoSize     = 0x0001
+		swpNoMove     = 0x0002
+		swpNoActivate = 0x0010
+	)
+	hwndTopmost := ^uintptr(0) // HWND_TOPMOST, (HWND)-1
+	name, err := windows.UTF16PtrFromString(title)
+	if err != nil {
+		return
+	}
+	hwnd, _, _ := procFindWindowW.Call(0, uintptr(unsafe.Pointer(name)))
+	if hwnd == 0 {
+		return
+	}
+	procSetWindowPos.Call(hwnd, hwndTopmost, 0, 0, 0, 0, swpNoSize|swpNoMove|swpNoActivate)
+}
@@ -75,7 +75,7 @@ func NewPriorityPanel(w fyne.Window) fyne.CanvasObject {
 	refreshTable := func() {
 		entries, err := priority.ListConfiguredPriorities()
 		if err != nil {
-			dialog.ShowError(err, w)
+			showError(err, w)
 			return
 		}
 		tableData.entries = entries
@@ -95,7 +95,7 @@ func NewPriorityPanel(w fyne.Window) fyne.CanvasObject {
 			func(confirmed bool) {
 				if confirmed {
 					if err := priority.RemoveProcessPriority(processName); err != nil {
-						dialog.ShowError(err, w)
+						showError(err, w)
 					} else {
 						dialog.ShowInformation("Success",
 							fmt.Sprintf("Priority settings removed for %s", processName), w)
@@ -129,7 +129,7 @@ func NewPriorityPanel(w fyne.Window) fyne.CanvasObject {
 	browseBtn := widget.NewButton("Browse...", func() {
 		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				showError(err, w)
 				return
 			}
 			if reader == nil {
@@ -150,7 +150,7 @@ func NewPriorityPanel(w fyne.Window) fyne.CanvasObject {
 	applyBtn := widget.NewButton("Apply Priority", func() {
 		processName := strings.TrimSpace(processNameEntry.Text)
 		if processName == "" {
-			dialog.ShowError(fmt.Errorf("process name cannot be empty"), w)
+			showError(fmt.Errorf("process name cannot be empty"), w)
 			return
 		}
 
@@ -163,7 +163,7 @@ func NewPriorityPanel(w fyne.Window) fyne.CanvasObject {
 		pageVal := priority.ParsePagePriorityName(pagePriorityName)
 
 		if err := priority.SetProcessPriority(processName, cpuVal, ioVal, pageVal); err != nil {
-			dialog.ShowError(err, w)
+			showError(err, w)
 			return
 		}
 
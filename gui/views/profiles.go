@@ -0,0 +1,208 @@
+//go:build gui
+
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+)
+
+// showProfileManager opens a dialog to switch, create, duplicate, and
+// delete configuration profiles.
+func showProfileManager(w fyne.Window) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		showError(err, w)
+		return
+	}
+
+	selector := widget.NewSelect(nil, nil)
+	activeLabel := widget.NewLabel("")
+	reload := func() {
+		selector.Options = profileChoices(cfg.ActiveProfile)
+		selector.SetSelected(cfg.ActiveProfile)
+		activeLabel.SetText(fmt.Sprintf("Active profile: %s", cfg.ActiveProfile))
+	}
+	reload()
+
+	askName := func(title string, done func(name string)) {
+		entry := widget.NewEntry()
+		entry.Validator = config.ValidateProfileName
+		dialog.ShowForm(title, "OK", "Cancel",
+			[]*widget.FormItem{widget.NewFormItem("Name", entry)},
+			func(ok bool) {
+				if ok {
+					done(entry.Text)
+				}
+			}, w)
+	}
+
+	switchBtn := widget.NewButton("Switch", func() {
+		if err := config.SwitchProfile(selector.Selected); err != nil {
+			showError(err, w)
+			return
+		}
+		cfg.ActiveProfile = selector.Selected
+		SetActiveProfile(selector.Selected)
+		if fresh, err := config.LoadConfig(); err == nil {
+			fresh.EffectiveRAMMonitor().Apply()
+		}
+		reload()
+	})
+	newBtn := widget.NewButton("New...", func() {
+		askName("New Profile", func(name string) {
+			if _, err := config.CreateProfile(name); err != nil {
+				showError(err, w)
+				return
+			}
+			reload()
+			selector.SetSelected(name)
+		})
+	})
+	duplicateBtn := widget.NewButton("Duplicate...", func() {
+		src := selector.Selected
+		askName("Duplicate "+src, func(name string) {
+			if !config.ProfileExists(src) {
+				if _, err := config.CreateProfile(src); err != nil {
+					showError(err, w)
+					return
+				}
+			}
+			if _, err := config.DuplicateProfile(src, name); err != nil {
+				showError(err, w)
+				return
+			}
+			reload()
+			selector.SetSelected(name)
+		})
+	})
+	deleteBtn := widget.NewButton("Delete", func() {
+		name := selector.Selected
+		if name == cfg.ActiveProfile {
+			dialog.ShowInformation("Delete Profile", "Switch to another profile before deleting this one.", w)
+			return
+		}
+		dialog.ShowConfirm("Delete Profile", fmt.Sprintf("Delete profile %q?", name), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := config.DeleteProfile(name); err != nil {
+				showError(err, w)
+				return
+			}
+			reload()
+		}, w)
+	})
+
+	exportBtn := widget.NewButton("Export Settings...", func() {
+		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				showError(err, w)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			path := writer.URI().Path()
+			writer.Close()
+			if err := config.ExportBundle(path); err != nil {
+				showError(err, w)
+				return
+			}
+			dialog.ShowInformation("Settings Exported", fmt.Sprintf("Settings written to %s", path), w)
+		}, w)
+	})
+	importBtn := widget.NewButton("Import Settings...", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				showError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+			dialog.ShowConfirm("Import Settings",
+				"Replace your config and overwrite profiles with the ones in this bundle?", func(ok bool) {
+					if !ok {
+						return
+					}
+					imported := func() {
+						if fresh, err := config.LoadConfig(); err == nil {
+							cfg = fresh
+						}
+						reload()
+					}
+					risks, err := config.ImportBundle(path, false)
+					if err == nil {
+						imported()
+						return
+					}
+					// Tools and hooks are refused until the user has read
+					// them; critical risks are refused outright.
+					if len(risks) == 0 || len(gaming.Blocking(risks, true)) > 0 {
+						showError(err, w)
+						return
+					}
+					lines := make([]string, len(risks))
+					for i, r := range risks {
+						lines[i] = r.String()
+					}
+					dialog.ShowConfirm("Allow Tools?",
+						"This bundle runs tools as administrator:\n\n"+strings.Join(lines, "\n")+"\n\nImport it anyway?", func(ok bool) {
+							if !ok {
+								return
+							}
+							if _, err := config.ImportBundle(path, true); err != nil {
+								showError(err, w)
+								return
+							}
+							imported()
+						}, w)
+				}, w)
+		}, w)
+	})
+
+	content := container.NewVBox(
+		activeLabel,
+		selector,
+		container.NewHBox(switchBtn, newBtn, duplicateBtn, deleteBtn),
+		container.NewHBox(exportBtn, importBtn),
+		widget.NewLabel("Presets (gaming, streamer, work, deep-clean) are saved the first time you use them."),
+	)
+	dialog.ShowCustom("Profiles", "Close", content, w)
+}
+
+// profileChoices lists saved profiles plus unsaved presets and the active
+// profile, sorted.
+func profileChoices(active string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	saved, _ := config.ListProfiles()
+	for _, name := range saved {
+		add(name)
+	}
+	add(active)
+	add("default")
+	for _, name := range config.PresetProfileNames() {
+		add(name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,77 @@
+//go:build gui
+
+package views
+
+import (
+	"sync"
+	"time"
+)
+
+// Tab names of views with live refresh loops. They must match the tab
+// titles in gui.createMainInterface.
+const (
+	TabDashboard = "Dashboard"
+	TabMonitor   = "Monitor"
+)
+
+// Refresh intervals of the live views, set from the performance config
+// before the window is built.
+var (
+	DashboardRefresh = 2 * time.Second
+	MonitorRefresh   = 1 * time.Second
+	GPURefresh       = 10 * time.Second
+	StatusBarRefresh = 2 * time.Second
+)
+
+// visibility tracks whether a view can currently be seen, so refresh loops
+// can sleep instead of sampling while their tab is hidden or the window is
+// in the background.
+var visibility = struct {
+	sync.Mutex
+	cond         *sync.Cond
+	tab          string
+	foreground   bool
+	inBackground bool // keep refreshing while the window is unfocused
+}{tab: TabDashboard, foreground: true}
+
+func init() {
+	visibility.cond = sync.NewCond(&visibility.Mutex)
+}
+
+// SetActiveTab records the selected tab and wakes its refresh loops.
+func SetActiveTab(name string) {
+	visibility.Lock()
+	visibility.tab = name
+	visibility.Unlock()
+	visibility.cond.Broadcast()
+}
+
+// SetForeground records whether the window is focused. Fyne reports
+// leaving the foreground when the window is minimized or another app, such
+// as a game, takes focus.
+func SetForeground(foreground bool) {
+	visibility.Lock()
+	visibility.foreground = foreground
+	visibility.Unlock()
+	visibility.cond.Broadcast()
+}
+
+// SetRefreshInBackground keeps refresh loops running while the window is
+// unfocused, for users watching the monitor on a second screen.
+func SetRefreshInBackground(enabled bool) {
+	visibility.Lock()
+	visibility.inBackground = enabled
+	visibility.Unlock()
+	visibility.cond.Broadcast()
+}
+
+// waitVisible blocks until tab is selected and the window is in the
+// foreground. An empty tab matches any tab, for always-visible widgets such
+// as the status bar.
+func waitVisible(tab string) {
+	visibility.Lock()
+	defer visibility.Unlock()
+	for !((tab == "" || visibility.tab == tab) && (visibility.foreground || visibility.inBackground)) {
+		visibility.cond.Wait()
+	}
+}
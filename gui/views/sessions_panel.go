@@ -0,0 +1,100 @@
+//go:build gui
+
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"syscleaner/pkg/gaming"
+)
+
+// NewSessionsPanel creates the gaming session history tab: per-game totals
+// above the most recent sessions.
+func NewSessionsPanel() fyne.CanvasObject {
+	var recs []gaming.SessionRecord
+	var summaries []gaming.GameSummary
+	status := widget.NewLabel("")
+
+	summaryTable := widget.NewTable(
+		func() (int, int) { return len(summaries) + 1, 5 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			if id.Row == 0 {
+				label.SetText([]string{"Game", "Sessions", "Time", "Avg CPU / RAM", "Peak RAM"}[id.Col])
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			s := summaries[id.Row-1]
+			label.SetText([]string{
+				s.Game,
+				fmt.Sprint(s.Sessions),
+				formatHours(s.TotalTime),
+				fmt.Sprintf("%.0f%% / %.0f%%", s.AvgCPU, s.AvgRAM),
+				fmt.Sprintf("%.0f%%", s.PeakRAM),
+			}[id.Col])
+		},
+	)
+	for col, width := range []float32{220, 80, 80, 130, 90} {
+		summaryTable.SetColumnWidth(col, width)
+	}
+
+	sessionList := widget.NewList(
+		func() int { return len(recs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			r := recs[id]
+			game := r.Game
+			if game == "" {
+				game = "(unknown)"
+			}
+			text := fmt.Sprintf("%s  %s  %s  CPU %.0f%%/%.0f%%  RAM %.0f%%/%.0f%%  cleans %d",
+				r.Started.Local().Format("2006-01-02 15:04"), game, formatHours(r.Duration),
+				r.AvgCPU, r.PeakCPU, r.AvgRAM, r.PeakRAM, r.Cleans)
+			if len(r.Optimizations) > 0 {
+				text += "  (" + strings.Join(r.Optimizations, ", ") + ")"
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+
+	reload := func() {
+		var err error
+		recs, err = gaming.SessionHistory()
+		if err != nil {
+			status.SetText(fmt.Sprintf("Could not read session history: %v", err))
+		} else if len(recs) == 0 {
+			status.SetText("No gaming sessions recorded yet.")
+		} else {
+			status.SetText(fmt.Sprintf("%d session(s) recorded", len(recs)))
+		}
+		summaries = gaming.SummarizeSessions(recs)
+		summaryTable.Refresh()
+		sessionList.Refresh()
+	}
+	reload()
+
+	header := container.NewBorder(nil, nil,
+		widget.NewLabelWithStyle("Gaming Sessions", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewButton("Refresh", reload))
+
+	summaryBox := container.NewGridWrap(fyne.NewSize(620, 180), summaryTable)
+	return container.NewBorder(
+		container.NewVBox(header, status, widget.NewLabel("By game"), summaryBox, widget.NewLabel("Recent sessions")),
+		nil, nil, nil,
+		sessionList,
+	)
+}
+
+// formatHours formats d as hours and minutes, e.g. "2h05m".
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,182 @@
+//go:build gui
+
+package views
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/sysinfo"
+)
+
+// Tab names the status bar can jump to. They must match the tab titles in
+// gui.createMainInterface.
+const (
+	TabExtremeMode = "Extreme Mode"
+	TabClean       = "Clean"
+)
+
+// maxStatusErrors caps how many recent errors the status bar remembers.
+const maxStatusErrors = 50
+
+// rebootCheckInterval is how often the status bar asks Windows whether a
+// restart is pending; the answer rarely changes and takes registry reads.
+const rebootCheckInterval = time.Minute
+
+var (
+	statusMu        sync.Mutex
+	activeProfile   = "default"
+	lastCleanTime   time.Time
+	lastCleanResult cleaner.CleanResult
+	statusErrors    []string
+	unseenErrors    int
+)
+
+// SetActiveProfile records the active profile for the status bar. It is
+// called whenever the config is loaded or reloaded, so the bar does not
+// have to load the config itself.
+func SetActiveProfile(name string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	if name == "" {
+		name = "default"
+	}
+	activeProfile = name
+}
+
+// RecordClean stores the outcome of the most recent clean for the status bar.
+func RecordClean(result cleaner.CleanResult) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	lastCleanTime = time.Now()
+	lastCleanResult = result
+}
+
+// RecordError adds an error to the status bar's error list and puts the bar
+// into its alert state until the user views the errors.
+func RecordError(err error) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	statusErrors = append(statusErrors, fmt.Sprintf("[%s] %v", time.Now().Format("15:04:05"), err))
+	if len(statusErrors) > maxStatusErrors {
+		statusErrors = statusErrors[len(statusErrors)-maxStatusErrors:]
+	}
+	unseenErrors++
+}
+
+// showError records err for the status bar and shows the usual error dialog.
+func showError(err error, w fyne.Window) {
+	RecordError(err)
+	dialog.ShowError(err, w)
+}
+
+// NewStatusBar creates the persistent status bar shown below every tab.
+// Clicking a segment calls onJump with the tab that holds its details, or
+// opens a dialog when there is no dedicated tab.
+func NewStatusBar(w fyne.Window, onJump func(tab string)) fyne.CanvasObject {
+	normalBg := color.RGBA{R: 30, G: 30, B: 33, A: 255}
+	alertBg := color.RGBA{R: 120, G: 20, B: 20, A: 255}
+	background := canvas.NewRectangle(normalBg)
+
+	segment := func(tapped func()) *widget.Button {
+		b := widget.NewButton("", tapped)
+		b.Importance = widget.LowImportance
+		return b
+	}
+
+	profileBtn := segment(func() { showProfileManager(w) })
+	gamingBtn := segment(func() { onJump(TabExtremeMode) })
+	cleanBtn := segment(func() { onJump(TabClean) })
+	rebootBtn := segment(func() {
+		dialog.ShowInformation("Restart Pending",
+			"Windows has pending updates or file operations that finish on the next restart.\n"+
+				"Some cleanup and optimization changes may not take full effect until then.", w)
+	})
+	errorsBtn := segment(func() {
+		statusMu.Lock()
+		text := "No errors recorded this session."
+		if len(statusErrors) > 0 {
+			text = strings.Join(statusErrors, "\n")
+		}
+		unseenErrors = 0
+		statusMu.Unlock()
+
+		details := widget.NewLabel(text)
+		details.Wrapping = fyne.TextWrapWord
+		scroll := container.NewVScroll(details)
+		scroll.SetMinSize(fyne.NewSize(600, 300))
+		dialog.ShowCustom("Recent Errors", "Close", scroll, w)
+	})
+
+	var pending bool
+	var rebootChecked time.Time
+	refresh := func() {
+		statusMu.Lock()
+		profile := activeProfile
+		statusMu.Unlock()
+		profileBtn.SetText("Profile: " + profile)
+
+		switch {
+		case gaming.IsExtremeModeActive():
+			gamingBtn.SetText("Gaming: EXTREME")
+		case gaming.IsEnabled():
+			gamingBtn.SetText("Gaming: Active")
+		default:
+			gamingBtn.SetText("Gaming: Off")
+		}
+
+		statusMu.Lock()
+		if lastCleanTime.IsZero() {
+			cleanBtn.SetText("Last clean: never")
+		} else {
+			cleanBtn.SetText(fmt.Sprintf("Last clean: %s (%s)",
+				lastCleanTime.Format("15:04"), cleaner.FormatBytes(lastCleanResult.SpaceFreed)))
+		}
+		errCount, unseen := len(statusErrors), unseenErrors
+		statusMu.Unlock()
+
+		errorsBtn.SetText(fmt.Sprintf("Errors: %d", errCount))
+
+		if time.Since(rebootChecked) >= rebootCheckInterval {
+			pending = sysinfo.PendingReboot()
+			rebootChecked = time.Now()
+		}
+		if pending {
+			rebootBtn.SetText("Restart pending")
+			rebootBtn.Show()
+		} else {
+			rebootBtn.Hide()
+		}
+
+		if unseen > 0 || pending {
+			background.FillColor = alertBg
+		} else {
+			background.FillColor = normalBg
+		}
+		background.Refresh()
+	}
+	refresh()
+
+	go func() {
+		ticker := time.NewTicker(StatusBarRefresh)
+		defer ticker.Stop()
+		for range ticker.C {
+			waitVisible("")
+			refresh()
+		}
+	}()
+
+	bar := container.NewHBox(profileBtn, gamingBtn, cleanBtn, rebootBtn, errorsBtn)
+	return container.NewStack(background, bar)
+}
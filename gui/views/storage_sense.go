@@ -0,0 +1,108 @@
+//go:build gui
+
+package views
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/conflicts"
+)
+
+// Storage Sense cadences in the order the select lists them.
+var storageSenseCadences = []int{
+	conflicts.CadenceLowDisk, conflicts.CadenceDaily, conflicts.CadenceWeekly, conflicts.CadenceMonthly,
+}
+
+func retentionName(days int) string {
+	if days == 0 {
+		return "Never"
+	}
+	return strconv.Itoa(days) + " days"
+}
+
+// newStorageSenseSection shows the current user's Storage Sense settings,
+// read back from Windows, and applies changes to them, so Windows' own
+// cleanup can be chosen alongside or instead of SysCleaner's.
+func newStorageSenseSection(w fyne.Window) fyne.CanvasObject {
+	var cadenceNames, retentionNames []string
+	for _, c := range storageSenseCadences {
+		cadenceNames = append(cadenceNames, conflicts.CadenceName(c))
+	}
+	for _, d := range conflicts.RetentionDays {
+		retentionNames = append(retentionNames, retentionName(d))
+	}
+
+	enabledCheck := widget.NewCheck("Turn on Storage Sense", nil)
+	tempCheck := widget.NewCheck("Delete temporary files apps aren't using", nil)
+	cadenceSelect := widget.NewSelect(cadenceNames, nil)
+	downloadsSelect := widget.NewSelect(retentionNames, nil)
+	recycleSelect := widget.NewSelect(retentionNames, nil)
+	statusLabel := widget.NewLabel("")
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	load := func() {
+		s, err := conflicts.ReadStorageSense()
+		if err != nil {
+			statusLabel.SetText(err.Error())
+			return
+		}
+		enabledCheck.SetChecked(s.Enabled)
+		tempCheck.SetChecked(s.CleanTemp)
+		cadenceSelect.SetSelected(conflicts.CadenceName(s.Cadence))
+		downloadsSelect.SetSelected(retentionName(s.DownloadsDays))
+		recycleSelect.SetSelected(retentionName(s.RecycleBinDays))
+		if s.ManagedByPolicy {
+			statusLabel.SetText("Storage Sense is managed by Group Policy; these settings may not apply.")
+		} else {
+			statusLabel.SetText("")
+		}
+	}
+
+	applyBtn := widget.NewButton("Apply Storage Sense Settings", func() {
+		s := conflicts.StorageSense{
+			Enabled:        enabledCheck.Checked,
+			CleanTemp:      tempCheck.Checked,
+			Cadence:        storageSenseCadences[max(cadenceSelect.SelectedIndex(), 0)],
+			DownloadsDays:  conflicts.RetentionDays[max(downloadsSelect.SelectedIndex(), 0)],
+			RecycleBinDays: conflicts.RetentionDays[max(recycleSelect.SelectedIndex(), 0)],
+		}
+		if err := conflicts.ConfigureStorageSense(s); err != nil {
+			showError(err, w)
+			return
+		}
+		load()
+		if cfg, err := config.LoadConfig(); err == nil {
+			found := conflicts.Analyze(conflicts.Detect(), cfg.LowDisk.Enabled)
+			if len(found) > 0 {
+				msg := ""
+				for _, c := range found {
+					msg += "- " + c.String() + "\n"
+				}
+				dialog.ShowInformation("Overlapping Cleaners", msg, w)
+				return
+			}
+		}
+		statusLabel.SetText(fmt.Sprintf("Storage Sense settings saved (runs %s).", conflicts.CadenceName(s.Cadence)))
+	})
+	load()
+
+	form := container.NewGridWithColumns(2,
+		widget.NewLabel("Run Storage Sense"), cadenceSelect,
+		widget.NewLabel("Delete Downloads files older than"), downloadsSelect,
+		widget.NewLabel("Delete Recycle Bin files older than"), recycleSelect,
+	)
+	return container.NewVBox(
+		widget.NewLabelWithStyle("Windows Storage Sense", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewHBox(enabledCheck, tempCheck),
+		form,
+		applyBtn,
+		statusLabel,
+	)
+}
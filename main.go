@@ -3,10 +3,22 @@
 package main
 
 import (
+	"os"
+
 	"syscleaner/gui"
+	"syscleaner/pkg/agent"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/logger"
 )
 
 func main() {
+	defer logger.Recover()
+	if gaming.RunWatchdog(os.Args[1:]) {
+		return
+	}
+	if agent.Run(os.Args[1:]) {
+		return
+	}
 	// Always launch GUI - this is a GUI-only application
 	gui.Run()
 }
@@ -2,7 +2,13 @@
 
 package admin
 
+import "fmt"
+
 func isElevatedPlatform() bool {
 	// On non-Windows, handled by os.Geteuid() in admin.go
 	return false
 }
+
+func relaunchPlatform(args []string) error {
+	return fmt.Errorf("relaunching as administrator is only available on Windows")
+}
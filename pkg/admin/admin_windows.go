@@ -3,6 +3,9 @@
 package admin
 
 import (
+	"os"
+	"strings"
+
 	"golang.org/x/sys/windows"
 )
 
@@ -26,3 +29,21 @@ func isElevatedPlatform() bool {
 	}
 	return member
 }
+
+func relaunchPlatform(args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = windows.EscapeArg(a)
+	}
+	cwd, _ := os.Getwd()
+
+	verbPtr, _ := windows.UTF16PtrFromString("runas")
+	exePtr, _ := windows.UTF16PtrFromString(exe)
+	argsPtr, _ := windows.UTF16PtrFromString(strings.Join(quoted, " "))
+	cwdPtr, _ := windows.UTF16PtrFromString(cwd)
+	return windows.ShellExecute(0, verbPtr, exePtr, argsPtr, cwdPtr, windows.SW_NORMAL)
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// ErrAuditMode is returned (wrapped) by operations skipped in audit mode.
+var ErrAuditMode = errors.New("read-only audit mode is on")
+
+var auditMode atomic.Bool
+
+// SetAuditMode turns read-only audit mode on or off. In audit mode every
+// subsystem only analyses: cleans run as dry runs and registry, service,
+// process and config changes are refused.
+func SetAuditMode(on bool) {
+	auditMode.Store(on)
+}
+
+// AuditMode reports whether read-only audit mode is on.
+func AuditMode() bool {
+	return auditMode.Load()
+}
+
+// RequireWriteAccess returns an error if audit mode is on. Call it before
+// any change to the system, alongside RequireElevation, so audit mode can
+// show what would have happened without doing it.
+func RequireWriteAccess(operation string) error {
+	if !AuditMode() {
+		return nil
+	}
+	log.Printf("[SysCleaner] Audit mode: skipped %s", operation)
+	return fmt.Errorf("%s: %w", operation, ErrAuditMode)
+}
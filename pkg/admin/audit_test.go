@@ -0,0 +1,20 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequireWriteAccess(t *testing.T) {
+	defer SetAuditMode(false)
+
+	if err := RequireWriteAccess("saving config"); err != nil {
+		t.Fatalf("RequireWriteAccess with audit mode off = %v, want nil", err)
+	}
+
+	SetAuditMode(true)
+	err := RequireWriteAccess("saving config")
+	if !errors.Is(err, ErrAuditMode) {
+		t.Fatalf("RequireWriteAccess in audit mode = %v, want ErrAuditMode", err)
+	}
+}
@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Skip is an item that will not run without administrator privileges.
+type Skip struct {
+	Item   string
+	Reason string
+}
+
+// FormatSkips renders skips as an indented list, one per line.
+func FormatSkips(skips []Skip) string {
+	var b strings.Builder
+	for _, s := range skips {
+		fmt.Fprintf(&b, "  - %s: %s\n", s.Item, s.Reason)
+	}
+	return b.String()
+}
+
+// Relaunch starts a new elevated copy of the running executable with args,
+// showing the Windows UAC prompt. The caller should exit once it returns
+// nil so the two copies don't run the same work.
+func Relaunch(args []string) error {
+	return relaunchPlatform(args)
+}
@@ -0,0 +1,235 @@
+// Package agent runs the game watcher in the background, started by a
+// scheduled task at logon, so the active profile's gaming mode settings
+// apply when a game starts whether or not the SysCleaner window is open.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/scheduler"
+)
+
+// Arg starts the executable as the background agent (see Run).
+const Arg = "--agent"
+
+// TaskName is the scheduled task that starts the agent at logon.
+const TaskName = "SysCleanerAgent"
+
+// reloadCheckInterval is how often the agent retries applying a config
+// change that arrived while a game was running.
+const reloadCheckInterval = 30 * time.Second
+
+// lockPath is where the running agent records itself, so a second copy
+// started by hand or by a second logon exits instead of racing it.
+var lockPath = func() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", "agent.lock")
+}
+
+// agentLock identifies the agent process like a gaming session owner.
+type agentLock struct {
+	PID     int32 `json:"pid"`
+	Created int64 `json:"created"`
+}
+
+// Install registers the agent to start at every logon and starts it now.
+func Install() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to determine executable path: %w", err)
+	}
+	if err := scheduler.CreateLogonTask(TaskName, `"`+exe+`" `+Arg); err != nil {
+		return err
+	}
+	if _, ok := Running(); ok {
+		return nil
+	}
+	return scheduler.RunTask(TaskName)
+}
+
+// Uninstall removes the logon task. A running agent keeps running until
+// the user logs off.
+func Uninstall() error {
+	return scheduler.RemoveTask(TaskName)
+}
+
+// Installed reports whether the logon task exists.
+func Installed() (bool, error) {
+	return scheduler.TaskExists(TaskName)
+}
+
+// Running returns the PID of the running agent, if there is one.
+func Running() (int32, bool) {
+	data, err := os.ReadFile(lockPath())
+	if err != nil {
+		return 0, false
+	}
+	var l agentLock
+	if json.Unmarshal(data, &l) != nil {
+		return 0, false
+	}
+	p, err := process.NewProcess(l.PID)
+	if err != nil {
+		return 0, false
+	}
+	if created, err := p.CreateTime(); err != nil || created != l.Created {
+		return 0, false
+	}
+	return l.PID, true
+}
+
+// acquire records this process as the running agent. It fails if another
+// agent is running.
+func acquire() (func(), error) {
+	if pid, ok := Running(); ok {
+		return nil, fmt.Errorf("the agent is already running (PID %d)", pid)
+	}
+	l := agentLock{PID: int32(os.Getpid())}
+	if p, err := process.NewProcess(l.PID); err == nil {
+		l.Created, _ = p.CreateTime()
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	path := lockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+	return func() { os.Remove(path) }, nil
+}
+
+// Run runs the agent if args, the command-line arguments after the
+// program name, ask for it, and reports whether they did. The agent logs
+// to agent.log next to the SysCleaner log and runs until the user logs
+// off; config and profile changes are picked up once no game is running.
+func Run(args []string) bool {
+	if len(args) != 1 || args[0] != Arg {
+		return false
+	}
+	logPath := filepath.Join(filepath.Dir(logger.DefaultLogPath()), "agent.log")
+	if f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+		defer f.Close()
+		logger.CaptureStandardLog(f)
+	}
+	release, err := acquire()
+	if err != nil {
+		log.Printf("[SysCleaner] Agent: %v", err)
+		return true
+	}
+	defer release()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	log.Printf("[SysCleaner] Agent started (PID %d)", os.Getpid())
+	serve(ctx)
+	log.Println("[SysCleaner] Agent stopped")
+	return true
+}
+
+// serve runs a game watcher for the current config until ctx is done,
+// replacing it when the config changes and no game is running.
+func serve(ctx context.Context) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Printf("[SysCleaner] Agent: %v; using the default config", err)
+		cfg = config.DefaultConfig()
+	}
+	updates, err := config.Watch(ctx)
+	if err != nil {
+		log.Printf("[SysCleaner] Agent: config changes will not be picked up: %v", err)
+	}
+	ticker := time.NewTicker(reloadCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		w := newWatcher(cfg)
+		wctx, stop := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if err := w.Run(wctx); err != nil {
+				log.Printf("[SysCleaner] Agent: %v", err)
+			}
+		}()
+
+		pending := false
+		for !pending || w.Game() != "" {
+			select {
+			case <-ctx.Done():
+				stop()
+				<-done
+				return
+			case next, ok := <-updates:
+				if !ok {
+					updates = nil
+					continue
+				}
+				cfg, pending = next, true
+			case <-ticker.C:
+			}
+		}
+		log.Println("[SysCleaner] Agent: config changed, restarting the game watcher")
+		stop()
+		<-done
+	}
+}
+
+// newWatcher applies cfg and returns a game watcher using its active
+// profile's gaming mode settings and hooks.
+func newWatcher(cfg *config.Config) *gaming.Watcher {
+	cfg.ApplyLogLevel()
+	cfg.ApplyLogRedaction()
+	cfg.GameDatabase.Apply()
+	cfg.EffectiveRAMMonitor().Apply()
+
+	profile := cfg.ActiveProfileSettings()
+	gaming.SetGameOverrides(cfg.GameOverrides)
+	gaming.SetQoSOverrides(profile.GamingConfig.QoSDSCP)
+	gaming.SetLatencyTargets(profile.GamingConfig.ServerEndpoints)
+	w := gaming.NewWatcher(gaming.Config{
+		CPUBoost:              profile.GamingConfig.CPUBoost,
+		RAMReserveGB:          profile.GamingConfig.RAMReserveGB,
+		SuspendProcesses:      profile.GamingConfig.SuspendWhileGaming,
+		SuspendBackgroundApps: profile.GamingConfig.SuspendBackgroundApps,
+		Whitelist:             cfg.EffectiveWhitelist(),
+		FocusMode:             profile.GamingConfig.FocusMode,
+		DiscreteGPU:           profile.GamingConfig.DiscreteGPU,
+		GameSettings:          profile.GamingConfig.WindowsGameSettings,
+		HighResTimer:          profile.GamingConfig.HighResTimer,
+		PowerPlan:             profile.GamingConfig.PowerPlan,
+		PurgeStandby:          profile.GamingConfig.PurgeStandby,
+		USBLatency:            profile.GamingConfig.USBLatency,
+		TrimWorkingSets:       profile.GamingConfig.TrimWorkingSets,
+		Streaming:             profile.StreamingConfig(),
+	})
+	w.OnChange = func(game string, started bool, err error) {
+		if err != nil {
+			return
+		}
+		hooks := profile.Hooks.GamingDisable
+		if started {
+			hooks = profile.Hooks.GamingEnable
+		}
+		profile.RunHooks(hooks)
+	}
+	return w
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.lock")
+	orig := lockPath
+	lockPath = func() string { return path }
+	defer func() { lockPath = orig }()
+
+	if _, ok := Running(); ok {
+		t.Fatal("Running with no lock file")
+	}
+	release, err := acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid, ok := Running(); !ok || pid != int32(os.Getpid()) {
+		t.Errorf("Running = %d, %v; want this process", pid, ok)
+	}
+	if _, err := acquire(); err == nil {
+		t.Error("second agent acquired the lock")
+	}
+	release()
+	if _, ok := Running(); ok {
+		t.Error("Running after release")
+	}
+
+	// A lock left by a process that has exited does not count.
+	if err := os.WriteFile(path, []byte(`{"pid": 999999, "created": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Running(); ok {
+		t.Error("stale lock counted as a running agent")
+	}
+}
+
+func TestRun_OtherArgs(t *testing.T) {
+	for _, args := range [][]string{nil, {"gaming"}, {Arg, "extra"}} {
+		if Run(args) {
+			t.Errorf("Run(%q) ran the agent", args)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package autoclean runs cleans and profile switches in response to system
+// conditions and schedules rather than user action.
+package autoclean
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/monitor"
+	"syscleaner/pkg/sysinfo"
+)
+
+// NotifyFunc delivers a user-facing message about an automatic clean.
+type NotifyFunc func(title, message string)
+
+var (
+	watcherMu sync.Mutex
+	watcher   *monitor.LowDiskWatcher
+	cleanMu   sync.Mutex
+)
+
+// StartLowDiskTrigger starts watching free space according to settings and
+// runs the configured profile's clean when a drive runs low. interval is
+// how often drives are polled. It replaces any trigger already running and
+// does nothing if the trigger is disabled.
+func StartLowDiskTrigger(settings config.LowDiskSettings, interval time.Duration, notify NotifyFunc) {
+	StopLowDiskTrigger()
+	if !settings.Enabled {
+		return
+	}
+
+	drives := settings.Drives
+	if len(drives) == 0 {
+		drives = []string{sysinfo.SystemDrive()}
+	}
+
+	w := &monitor.LowDiskWatcher{
+		Drives:           drives,
+		ThresholdPercent: settings.ThresholdPercent,
+		ThresholdGB:      settings.ThresholdGB,
+		Interval:         interval,
+		Cooldown:         time.Duration(settings.CooldownMinutes) * time.Minute,
+		OnLow: func(status monitor.DiskStatus) {
+			runLowDiskClean(settings.Profile, status, notify)
+		},
+	}
+
+	watcherMu.Lock()
+	watcher = w
+	watcherMu.Unlock()
+	w.Start()
+	log.Printf("[SysCleaner] Low disk trigger watching %v", drives)
+}
+
+// StopLowDiskTrigger stops the low disk trigger if it is running.
+func StopLowDiskTrigger() {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+	if watcher != nil {
+		watcher.Stop()
+		watcher = nil
+	}
+}
+
+func runLowDiskClean(profileName string, status monitor.DiskStatus, notify NotifyFunc) {
+	// Only one automatic clean at a time, even if several drives trip at once.
+	cleanMu.Lock()
+	defer cleanMu.Unlock()
+
+	log.Printf("[SysCleaner] %s is low on space (%.1f GB, %.1f%% free); running profile %q",
+		status.DriveLetter, status.FreeGB, status.FreePercent, profileName)
+
+	profile, err := config.LoadProfile(profileName)
+	if err != nil {
+		profile = config.DefaultProfile()
+		log.Printf("[SysCleaner] Low disk trigger: %v; using default clean options", err)
+	}
+	whitelist := profile.ProcessWhitelist
+	if cfg, err := config.LoadConfig(); err == nil {
+		whitelist = append(whitelist, cfg.ProcessWhitelist...)
+	}
+	cleaner.SetCloseLockHolders(false, whitelist)
+
+	// A profile set to dry_run only previews, even when triggered here.
+	opts := profile.CleanOptions.ToCleanOptions()
+	opts.Background = true
+
+	result := cleaner.PerformClean(opts)
+	if err := cleaner.SaveRunReport(cleaner.DefaultReportsDir(), result); err != nil {
+		log.Printf("[SysCleaner] Failed to save run report: %v", err)
+	}
+	after := monitor.CheckDrive(status.DriveLetter)
+
+	if notify != nil && opts.DryRun {
+		notify("Low disk space cleanup",
+			fmt.Sprintf("%s is at %.1f GB free. Profile %q is a dry run; a clean would free %s.",
+				status.DriveLetter, status.FreeGB, profileName, cleaner.FormatBytes(result.SpaceFreed)))
+	} else if notify != nil {
+		notify("Low disk space cleanup",
+			fmt.Sprintf("%s was at %.1f GB free. Freed %s; now %.1f GB free.",
+				status.DriveLetter, status.FreeGB, cleaner.FormatBytes(result.SpaceFreed), after.FreeGB))
+	}
+}
@@ -0,0 +1,93 @@
+package autoclean
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/monitor"
+	"syscleaner/pkg/sysinfo"
+)
+
+var (
+	powerWatcher *monitor.PowerWatcher
+	// lastPowerProfile is the profile last chosen for the power state.
+	// It outlives restarts of the watcher (e.g. on config reload) so a
+	// profile the user picked by hand is only replaced when the power
+	// state actually changes.
+	lastPowerProfile string
+)
+
+// StartPowerProfiles switches the active profile according to settings as
+// the machine moves between AC and battery or is docked or undocked. It
+// replaces any switcher already running and does nothing if switching is
+// disabled.
+func StartPowerProfiles(settings config.PowerProfileSettings, notify NotifyFunc) {
+	StopPowerProfiles()
+	if !settings.Enabled {
+		return
+	}
+
+	w := &monitor.PowerWatcher{
+		Interval: 10 * time.Second,
+		OnChange: func(status sysinfo.PowerStatus) {
+			switchPowerProfile(settings, status, notify)
+		},
+	}
+	watcherMu.Lock()
+	powerWatcher = w
+	watcherMu.Unlock()
+	w.Start()
+}
+
+// StopPowerProfiles stops power-based profile switching if it is running.
+func StopPowerProfiles() {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+	if powerWatcher != nil {
+		powerWatcher.Stop()
+		powerWatcher = nil
+	}
+}
+
+func switchPowerProfile(settings config.PowerProfileSettings, status sysinfo.PowerStatus, notify NotifyFunc) {
+	name := settings.ProfileFor(status)
+	watcherMu.Lock()
+	unchanged := name == "" || name == lastPowerProfile
+	lastPowerProfile = name
+	watcherMu.Unlock()
+	if unchanged {
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err == nil && cfg.ActiveProfile == name {
+		return
+	}
+	if err := config.SwitchProfile(name); err != nil {
+		log.Printf("[SysCleaner] Could not switch to profile %q for %s: %v", name, describePower(status), err)
+		return
+	}
+	log.Printf("[SysCleaner] Switched to profile %q (%s)", name, describePower(status))
+	if notify != nil {
+		notify("Profile switched", fmt.Sprintf("Now using profile %q (%s).", name, describePower(status)))
+	}
+}
+
+func describePower(status sysinfo.PowerStatus) string {
+	source := "on AC power"
+	if status.OnBattery {
+		source = "on battery"
+		if status.BatteryPercent >= 0 {
+			source = fmt.Sprintf("on battery, %d%%", status.BatteryPercent)
+		}
+	}
+	if status.DockKnown {
+		if status.Docked {
+			return source + ", docked"
+		}
+		return source + ", undocked"
+	}
+	return source
+}
@@ -0,0 +1,133 @@
+package autoclean
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/optimizer"
+	"syscleaner/pkg/scheduler"
+)
+
+var runner *scheduler.Runner
+
+// StartSchedules runs the enabled entries of schedules as they fall due.
+// It replaces any schedules already running.
+func StartSchedules(schedules []scheduler.ScheduleEntry, notify NotifyFunc) {
+	StopSchedules()
+
+	enabled := 0
+	for _, e := range schedules {
+		if e.Enabled {
+			enabled++
+		}
+	}
+	if enabled == 0 {
+		return
+	}
+
+	r := &scheduler.Runner{
+		Entries: schedules,
+		OnDue: func(e scheduler.ScheduleEntry) {
+			runScheduledJob(e, notify)
+		},
+	}
+	watcherMu.Lock()
+	runner = r
+	watcherMu.Unlock()
+	r.Start()
+	log.Printf("[SysCleaner] %d scheduled job(s) active", enabled)
+}
+
+// StopSchedules stops the scheduled jobs if they are running.
+func StopSchedules() {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+	if runner != nil {
+		runner.Stop()
+		runner = nil
+	}
+}
+
+// NextScheduledRun returns when the named scheduled job is next due.
+func NextScheduledRun(name string) (time.Time, bool) {
+	watcherMu.Lock()
+	r := runner
+	watcherMu.Unlock()
+	if r == nil {
+		return time.Time{}, false
+	}
+	return r.NextRun(name)
+}
+
+func runScheduledJob(e scheduler.ScheduleEntry, notify NotifyFunc) {
+	// Shares the lock with the low disk trigger so two cleans never overlap.
+	cleanMu.Lock()
+	defer cleanMu.Unlock()
+
+	log.Printf("[SysCleaner] Running scheduled job %q", e.Name)
+	var summary []string
+	var skips []admin.Skip
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Printf("[SysCleaner] Scheduled job %q: %v; using default config", e.Name, err)
+		cfg = config.DefaultConfig()
+	}
+
+	if e.Clean {
+		profileName := e.Profile
+		if profileName == "" {
+			profileName = cfg.ActiveProfile
+		}
+		profile, err := config.LoadProfile(profileName)
+		if err != nil {
+			profile = config.DefaultProfile()
+			log.Printf("[SysCleaner] Scheduled job %q: %v; using default clean options", e.Name, err)
+		}
+		cleaner.SetCloseLockHolders(false, append(profile.ProcessWhitelist, cfg.ProcessWhitelist...))
+
+		// A profile set to dry_run only previews, even when scheduled.
+		opts := profile.CleanOptions.ToCleanOptions()
+		opts.Background = true
+		opts, skipped := cleaner.PlanForPrivileges(opts, admin.IsElevated())
+		skips = append(skips, skipped...)
+
+		result := cleaner.PerformClean(opts)
+		if err := cleaner.SaveRunReport(cleaner.DefaultReportsDir(), result); err != nil {
+			log.Printf("[SysCleaner] Failed to save run report: %v", err)
+		}
+		if opts.DryRun {
+			summary = append(summary, "would free "+cleaner.FormatBytes(result.SpaceFreed)+" (dry run)")
+		} else {
+			summary = append(summary, "freed "+cleaner.FormatBytes(result.SpaceFreed))
+		}
+	}
+
+	startup, network, disk, skipped := optimizer.PlanForPrivileges(
+		e.Runs("startup"), e.Runs("network"), e.Runs("disk"), admin.IsElevated())
+	skips = append(skips, skipped...)
+	for _, s := range skips {
+		log.Printf("[SysCleaner] Scheduled job %q skipped %s: %s", e.Name, s.Item, s.Reason)
+	}
+	if startup {
+		r := optimizer.OptimizeStartup(optimizer.OptimizeOptions{})
+		summary = append(summary, fmt.Sprintf("disabled %d startup item(s)", r.Disabled))
+	}
+	if network {
+		r := optimizer.OptimizeNetwork(optimizer.OptimizeOptions{})
+		summary = append(summary, fmt.Sprintf("applied %d network setting(s)", len(r.Optimizations)))
+	}
+	if disk {
+		optimizer.OptimizeDisk(optimizer.OptimizeOptions{})
+		summary = append(summary, "ran disk optimization")
+	}
+
+	if notify != nil && len(summary) > 0 {
+		notify("Scheduled job: "+e.Name, strings.Join(summary, "; ")+".")
+	}
+}
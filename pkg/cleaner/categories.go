@@ -0,0 +1,119 @@
+package cleaner
+
+import (
+	"fmt"
+	"sync"
+)
+
+// categoryFlags maps each built-in category to its CleanOptions switch, in
+// cleaning order.
+var categoryFlags = []struct {
+	category Category
+	flag     func(*CleanOptions) *bool
+}{
+	{CategoryWindowsTemp, func(o *CleanOptions) *bool { return &o.WindowsTemp }},
+	{CategoryUserTemp, func(o *CleanOptions) *bool { return &o.UserTemp }},
+	{CategoryWindowsUpdateCache, func(o *CleanOptions) *bool { return &o.WindowsUpdate }},
+	{CategoryWindowsInstallerCache, func(o *CleanOptions) *bool { return &o.WindowsInstaller }},
+	{CategoryPrefetch, func(o *CleanOptions) *bool { return &o.Prefetch }},
+	{CategoryCrashDumps, func(o *CleanOptions) *bool { return &o.CrashDumps }},
+	{CategoryErrorReports, func(o *CleanOptions) *bool { return &o.ErrorReports }},
+	{CategoryThumbnailCache, func(o *CleanOptions) *bool { return &o.ThumbnailCache }},
+	{CategoryIconCache, func(o *CleanOptions) *bool { return &o.IconCache }},
+	{CategoryFontCache, func(o *CleanOptions) *bool { return &o.FontCache }},
+	{CategoryShaderCache, func(o *CleanOptions) *bool { return &o.ShaderCache }},
+	{CategoryDNSCache, func(o *CleanOptions) *bool { return &o.DNSCache }},
+	{CategoryWindowsLogFiles, func(o *CleanOptions) *bool { return &o.WindowsLogs }},
+	{CategoryEventLogs, func(o *CleanOptions) *bool { return &o.EventLogs }},
+	{CategoryDeliveryOptimization, func(o *CleanOptions) *bool { return &o.DeliveryOptimization }},
+	{CategoryRecycleBin, func(o *CleanOptions) *bool { return &o.RecycleBin }},
+	{CategoryFontIconRebuild, func(o *CleanOptions) *bool { return &o.FontIconRebuild }},
+	{CategoryChromeCache, func(o *CleanOptions) *bool { return &o.ChromeCache }},
+	{CategoryFirefoxCache, func(o *CleanOptions) *bool { return &o.FirefoxCache }},
+	{CategoryEdgeCache, func(o *CleanOptions) *bool { return &o.EdgeCache }},
+	{CategoryBraveCache, func(o *CleanOptions) *bool { return &o.BraveCache }},
+	{CategoryOperaCache, func(o *CleanOptions) *bool { return &o.OperaCache }},
+	{CategoryDiscordCache, func(o *CleanOptions) *bool { return &o.DiscordCache }},
+	{CategorySpotifyCache, func(o *CleanOptions) *bool { return &o.SpotifyCache }},
+	{CategorySteamCache, func(o *CleanOptions) *bool { return &o.SteamCache }},
+	{CategoryTeamsCache, func(o *CleanOptions) *bool { return &o.TeamsCache }},
+	{CategoryVSCodeCache, func(o *CleanOptions) *bool { return &o.VSCodeCache }},
+	{CategoryJavaCache, func(o *CleanOptions) *bool { return &o.JavaCache }},
+	{CategoryStoreAppCache, func(o *CleanOptions) *bool { return &o.StoreAppCache }},
+	{CategoryRecentDocuments, func(o *CleanOptions) *bool { return &o.RecentDocuments }},
+	{CategoryJumpLists, func(o *CleanOptions) *bool { return &o.JumpLists }},
+	{CategoryClipboardHistory, func(o *CleanOptions) *bool { return &o.ClipboardHistory }},
+	{CategoryExplorerMRU, func(o *CleanOptions) *bool { return &o.ExplorerMRU }},
+}
+
+// AllCategories returns every built-in category in cleaning order.
+// Rule-pack rules are selected through CleanOptions.Rules instead.
+func AllCategories() []Category {
+	cats := make([]Category, len(categoryFlags))
+	for i, cf := range categoryFlags {
+		cats[i] = cf.category
+	}
+	return cats
+}
+
+// Select turns on the given built-in categories. It returns an error
+// naming the first unknown category and leaves opts unchanged in that case.
+func (opts *CleanOptions) Select(categories ...Category) error {
+	sel := *opts
+	for _, c := range categories {
+		found := false
+		for _, cf := range categoryFlags {
+			if cf.category == c {
+				*cf.flag(&sel) = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown cleaning category %q", c)
+		}
+	}
+	*opts = sel
+	return nil
+}
+
+var (
+	disabledMu sync.RWMutex
+	// disabledCategories are never cleaned, e.g. because an administrator
+	// policy forbids them.
+	disabledCategories = make(map[Category]bool)
+)
+
+// SetDisabledCategories replaces the categories PerformClean refuses to
+// run. Unknown names are ignored.
+func SetDisabledCategories(categories []Category) {
+	disabledMu.Lock()
+	defer disabledMu.Unlock()
+	disabledCategories = make(map[Category]bool, len(categories))
+	for _, c := range categories {
+		disabledCategories[c] = true
+	}
+	InvalidateEstimates()
+}
+
+// CategoryDisabled reports whether c is disabled by SetDisabledCategories.
+func CategoryDisabled(c Category) bool {
+	disabledMu.RLock()
+	defer disabledMu.RUnlock()
+	return disabledCategories[c]
+}
+
+// withoutDisabled returns opts with disabled categories turned off, along
+// with the ones that were selected.
+func withoutDisabled(opts CleanOptions) (CleanOptions, []Category) {
+	disabledMu.RLock()
+	defer disabledMu.RUnlock()
+	var dropped []Category
+	for _, cf := range categoryFlags {
+		if flag := cf.flag(&opts); *flag && disabledCategories[cf.category] {
+			*flag = false
+			dropped = append(dropped, cf.category)
+		}
+	}
+	return opts, dropped
+}
@@ -2,15 +2,20 @@ package cleaner
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
 )
 
 // CleanOptions specifies what to clean with fine-grained control
@@ -32,6 +37,7 @@ type CleanOptions struct {
 	EventLogs           bool
 	DeliveryOptimization bool
 	RecycleBin          bool
+	FontIconRebuild     bool
 
 	// Application categories
 	ChromeCache   bool
@@ -45,10 +51,35 @@ type CleanOptions struct {
 	TeamsCache    bool
 	VSCodeCache   bool
 	JavaCache     bool
+	StoreAppCache bool
+
+	// Privacy categories
+	RecentDocuments  bool
+	JumpLists        bool
+	ClipboardHistory bool
+	ExplorerMRU      bool
+
+	// Rules lists rule-pack rule IDs to run (see SetRulePacks)
+	Rules []string
 
 	// Execution options
 	DryRun   bool
 	Progress ProgressFunc
+
+	// Volumes restricts which drives each category may clean.
+	Volumes VolumePolicy
+
+	// Background marks scheduled or automatic cleans. They always run at
+	// low I/O priority and are paced by BackgroundRateLimit.
+	Background bool
+
+	// category is the category being cleaned, set by cleanCategory for
+	// volume checks.
+	category Category
+	// throttle paces this clean's deletes, set by PerformCleanContext for
+	// background cleans. Each clean has its own, so a throttled scheduled
+	// clean does not slow a clean started at the same time from the GUI.
+	throttle *throttle
 }
 
 // ProgressFunc is called to report progress during cleaning
@@ -70,9 +101,18 @@ type CleanError struct {
 	Path string
 	Type ErrorType
 	Err  error
+	// Holders lists the processes holding a locked file, when known.
+	Holders []LockHolder
 }
 
 func (e *CleanError) Error() string {
+	if len(e.Holders) > 0 {
+		names := make([]string, len(e.Holders))
+		for i, h := range e.Holders {
+			names[i] = h.String()
+		}
+		return fmt.Sprintf("%s: %v (held by %s)", e.Path, e.Err, strings.Join(names, ", "))
+	}
 	return fmt.Sprintf("%s: %v", e.Path, e.Err)
 }
 
@@ -97,6 +137,57 @@ func classifyError(path string, err error) *CleanError {
 	return ce
 }
 
+// Category identifies a cleaning category. The value is the display name
+// used in logs, reports and the GUI.
+type Category string
+
+const (
+	CategoryWindowsTemp           Category = "Windows Temp"
+	CategoryUserTemp              Category = "User Temp"
+	CategoryWindowsUpdateCache    Category = "Windows Update Cache"
+	CategoryWindowsInstallerCache Category = "Windows Installer Cache"
+	CategoryPrefetch              Category = "Prefetch"
+	CategoryCrashDumps            Category = "Crash Dumps"
+	CategoryErrorReports          Category = "Error Reports"
+	CategoryThumbnailCache        Category = "Thumbnail Cache"
+	CategoryIconCache             Category = "Icon Cache"
+	CategoryFontCache             Category = "Font Cache"
+	CategoryShaderCache           Category = "Shader Cache"
+	CategoryDNSCache              Category = "DNS Cache"
+	CategoryWindowsLogFiles       Category = "Windows Log Files"
+	CategoryEventLogs             Category = "Event Logs"
+	CategoryDeliveryOptimization  Category = "Delivery Optimization"
+	CategoryRecycleBin            Category = "Recycle Bin"
+	CategoryFontIconRebuild       Category = "Font & Icon Cache Rebuild"
+	CategoryChromeCache           Category = "Chrome Cache"
+	CategoryFirefoxCache          Category = "Firefox Cache"
+	CategoryEdgeCache             Category = "Edge Cache"
+	CategoryBraveCache            Category = "Brave Cache"
+	CategoryOperaCache            Category = "Opera Cache"
+	CategoryDiscordCache          Category = "Discord Cache"
+	CategorySpotifyCache          Category = "Spotify Cache"
+	CategorySteamCache            Category = "Steam Cache"
+	CategoryTeamsCache            Category = "Teams Cache"
+	CategoryVSCodeCache           Category = "VS Code Cache"
+	CategoryJavaCache             Category = "Java Cache"
+	CategoryStoreAppCache         Category = "Store App Cache"
+	CategoryRecentDocuments       Category = "Recent Documents"
+	CategoryJumpLists             Category = "Jump Lists"
+	CategoryClipboardHistory      Category = "Clipboard History"
+	CategoryExplorerMRU           Category = "Explorer MRU"
+)
+
+// CategoryResult holds the outcome of a single category.
+type CategoryResult struct {
+	FilesDeleted    int64
+	SkippedFiles    int64
+	SpaceFreed      int64
+	LockedFiles     int64
+	PermissionFiles int64
+	Duration        time.Duration
+	Errors          []error
+}
+
 // CleanResult holds the result of a cleaning operation
 type CleanResult struct {
 	FilesDeleted    int64
@@ -106,6 +197,19 @@ type CleanResult struct {
 	PermissionFiles int64
 	Duration        time.Duration
 	Errors          []error
+
+	// Locked holds details for files skipped because another process had
+	// them open, including the holding processes when they could be found.
+	Locked []*CleanError
+
+	// DryRun reports whether the result came from a preview run.
+	DryRun bool
+	// OperationID identifies the run in the log (see
+	// logger.StartOperation). Only set on the result of PerformClean.
+	OperationID string
+	// Categories holds the per-category breakdown. Only populated on the
+	// merged result returned by PerformClean.
+	Categories map[Category]CategoryResult
 }
 
 const (
@@ -116,151 +220,363 @@ const (
 
 // cleanTask represents a single cleaning category to execute
 type cleanTask struct {
-	name string
+	name Category
 	fn   func(CleanOptions) CleanResult
 }
 
-// maxCleanWorkers is the number of concurrent cleaning goroutines.
+// categoryResult pairs a category's result with its name for collection
+// from the worker pool.
+type categoryResult struct {
+	name   Category
+	result CleanResult
+}
+
+// maxCleanWorkers is the default number of concurrent cleaning goroutines.
 // Limited to 4 to avoid excessive disk I/O contention on spinning drives.
 const maxCleanWorkers = 4
 
+// Performance knobs. These are package-level so the config layer can tune
+// them once at startup, mirroring the memory package's thresholds.
+var (
+	// Workers is the number of concurrent cleaning goroutines.
+	Workers = maxCleanWorkers
+
+	// IOPriority is applied to the process for the duration of a clean.
+	IOPriority = IOPriorityNormal
+
+	// EstimateCacheTTL is how long Estimate reuses a previous dry-run result
+	// for the same category selection. Zero disables caching.
+	EstimateCacheTTL = 5 * time.Minute
+
+	// ForceDryRun turns every clean into a preview, for unattended runs
+	// that must never delete (e.g. SYSCLEANER_DRY_RUN=1).
+	ForceDryRun = false
+)
+
+// IOPriorityLevel controls how aggressively a clean competes for disk I/O.
+type IOPriorityLevel int
+
+const (
+	IOPriorityNormal IOPriorityLevel = iota
+	IOPriorityLow                    // Background processing mode
+)
+
+// ParseIOPriority converts a config name ("normal", "low") to a level.
+// Unknown names fall back to normal.
+func ParseIOPriority(name string) IOPriorityLevel {
+	switch strings.ToLower(name) {
+	case "low", "background", "very-low":
+		return IOPriorityLow
+	default:
+		return IOPriorityNormal
+	}
+}
+
+// String returns the config name for an IOPriorityLevel.
+func (l IOPriorityLevel) String() string {
+	if l == IOPriorityLow {
+		return "low"
+	}
+	return "normal"
+}
+
 // PerformClean orchestrates all cleaning operations based on options.
 // Independent categories run concurrently via a worker pool for faster execution.
 func PerformClean(opts CleanOptions) CleanResult {
+	return PerformCleanContext(context.Background(), opts)
+}
+
+// PerformCleanContext is PerformClean bounded by ctx. Categories still
+// queued when ctx is done are skipped, and a running category is abandoned
+// with a "canceled" error; either way the result covers what finished.
+func PerformCleanContext(ctx context.Context, opts CleanOptions) CleanResult {
 	start := time.Now()
-	result := CleanResult{}
+	if admin.AuditMode() || ForceDryRun {
+		opts.DryRun = true
+	}
+	kind := "clean"
+	if opts.DryRun {
+		kind = "analyze"
+	}
+	op := logger.StartOperation(kind)
+	defer op.End()
+	result := CleanResult{DryRun: opts.DryRun, OperationID: op.ID, Categories: make(map[Category]CategoryResult)}
+	opts, disabled := withoutDisabled(opts)
+	for _, c := range disabled {
+		log.Printf("[SysCleaner] Skipping %s: disabled by configuration", c)
+		result.Errors = append(result.Errors, fmt.Errorf("%s is disabled by configuration", c))
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultOpTimeout)
 	defer cancel()
 
-	// Build list of enabled categories
+	tasks := buildTasks(opts)
+
+	if len(tasks) == 0 {
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Run categories concurrently via worker pool
+	taskCh := make(chan cleanTask, len(tasks))
+	resultCh := make(chan categoryResult, len(tasks))
+
+	if IOPriority == IOPriorityLow || opts.Background {
+		if restore, err := enterBackgroundIO(); err != nil {
+			log.Printf("[SysCleaner] Could not lower I/O priority: %v", err)
+		} else {
+			defer restore()
+		}
+	}
+	if opts.Background && !opts.DryRun {
+		opts.throttle = newThrottle(BackgroundRateLimit)
+	}
+
+	workers := Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if len(tasks) < workers {
+		workers = len(tasks)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range taskCh {
+				resultCh <- categoryResult{task.name, cleanCategory(ctx, task.name, task.fn, opts)}
+			}
+		}()
+	}
+
+	for _, t := range tasks {
+		taskCh <- t
+	}
+	close(taskCh)
+
+	// Close results channel once all workers finish
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	for r := range resultCh {
+		result.merge(r.result)
+		result.Categories[r.name] = r.result.categoryResult()
+	}
+
+	result.Duration = time.Since(start)
+	if !opts.DryRun {
+		InvalidateEstimates()
+	}
+	log.Printf("[SysCleaner] Cleanup complete: %d files deleted, %d skipped, %s freed in %s",
+		result.FilesDeleted, result.SkippedFiles, FormatBytes(result.SpaceFreed), result.Duration.Round(time.Millisecond))
+	return result
+}
+
+type estimateEntry struct {
+	at     time.Time
+	result CleanResult
+}
+
+var (
+	estimateMu    sync.Mutex
+	estimateCache = make(map[string]estimateEntry)
+)
+
+// Estimate returns a dry-run result for opts, reusing a cached scan of the
+// same options if it is younger than EstimateCacheTTL. Real cleans, and
+// changes to the exclusions, rule packs, disabled categories or Store
+// package filter, invalidate the cache.
+func Estimate(opts CleanOptions) CleanResult {
+	opts.DryRun = true
+	key := estimateKey(opts)
+
+	estimateMu.Lock()
+	entry, ok := estimateCache[key]
+	estimateMu.Unlock()
+	if ok && EstimateCacheTTL > 0 && time.Since(entry.at) < EstimateCacheTTL {
+		return entry.result
+	}
+
+	result := PerformClean(opts)
+	estimateMu.Lock()
+	estimateCache[key] = estimateEntry{at: time.Now(), result: result}
+	estimateMu.Unlock()
+	return result
+}
+
+// estimateKey identifies what a dry run of opts scans: the categories and
+// rules it runs and the volumes they may clean.
+func estimateKey(opts CleanOptions) string {
+	var names []string
+	for _, t := range buildTasks(opts) {
+		names = append(names, string(t.name))
+	}
+	volumes, _ := json.Marshal(opts.Volumes)
+	return strings.Join(names, "|") + "\x00" + strings.Join(opts.Rules, "|") + "\x00" + string(volumes)
+}
+
+// InvalidateEstimates discards all cached dry-run results.
+func InvalidateEstimates() {
+	estimateMu.Lock()
+	estimateCache = make(map[string]estimateEntry)
+	estimateMu.Unlock()
+}
+
+// Categories returns the categories opts selects, in cleaning order.
+func (opts CleanOptions) Categories() []Category {
+	tasks := buildTasks(opts)
+	names := make([]Category, 0, len(tasks))
+	for _, t := range tasks {
+		names = append(names, t.name)
+	}
+	return names
+}
+
+// buildTasks returns the enabled cleaning categories in a stable order.
+func buildTasks(opts CleanOptions) []cleanTask {
 	var tasks []cleanTask
 	if opts.WindowsTemp {
-		tasks = append(tasks, cleanTask{"Windows Temp", cleanWindowsTemp})
+		tasks = append(tasks, cleanTask{CategoryWindowsTemp, cleanWindowsTemp})
 	}
 	if opts.UserTemp {
-		tasks = append(tasks, cleanTask{"User Temp", cleanUserTemp})
+		tasks = append(tasks, cleanTask{CategoryUserTemp, cleanUserTemp})
 	}
 	if opts.WindowsUpdate {
-		tasks = append(tasks, cleanTask{"Windows Update Cache", cleanWindowsUpdate})
+		tasks = append(tasks, cleanTask{CategoryWindowsUpdateCache, cleanWindowsUpdate})
 	}
 	if opts.WindowsInstaller {
-		tasks = append(tasks, cleanTask{"Windows Installer Cache", cleanWindowsInstaller})
+		tasks = append(tasks, cleanTask{CategoryWindowsInstallerCache, cleanWindowsInstaller})
 	}
 	if opts.Prefetch {
-		tasks = append(tasks, cleanTask{"Prefetch", cleanPrefetch})
+		tasks = append(tasks, cleanTask{CategoryPrefetch, cleanPrefetch})
 	}
 	if opts.CrashDumps {
-		tasks = append(tasks, cleanTask{"Crash Dumps", cleanCrashDumps})
+		tasks = append(tasks, cleanTask{CategoryCrashDumps, cleanCrashDumps})
 	}
 	if opts.ErrorReports {
-		tasks = append(tasks, cleanTask{"Error Reports", cleanErrorReports})
+		tasks = append(tasks, cleanTask{CategoryErrorReports, cleanErrorReports})
 	}
 	if opts.ThumbnailCache {
-		tasks = append(tasks, cleanTask{"Thumbnail Cache", cleanThumbnailCache})
+		tasks = append(tasks, cleanTask{CategoryThumbnailCache, cleanThumbnailCache})
 	}
 	if opts.IconCache {
-		tasks = append(tasks, cleanTask{"Icon Cache", cleanIconCache})
+		tasks = append(tasks, cleanTask{CategoryIconCache, cleanIconCache})
 	}
 	if opts.FontCache {
-		tasks = append(tasks, cleanTask{"Font Cache", cleanFontCache})
+		tasks = append(tasks, cleanTask{CategoryFontCache, cleanFontCache})
 	}
 	if opts.ShaderCache {
-		tasks = append(tasks, cleanTask{"Shader Cache", cleanShaderCache})
+		tasks = append(tasks, cleanTask{CategoryShaderCache, cleanShaderCache})
 	}
 	if opts.DNSCache {
-		tasks = append(tasks, cleanTask{"DNS Cache", cleanDNSCache})
+		tasks = append(tasks, cleanTask{CategoryDNSCache, cleanDNSCache})
 	}
 	if opts.WindowsLogs {
-		tasks = append(tasks, cleanTask{"Windows Log Files", cleanWindowsLogs})
+		tasks = append(tasks, cleanTask{CategoryWindowsLogFiles, cleanWindowsLogs})
 	}
 	if opts.EventLogs {
-		tasks = append(tasks, cleanTask{"Event Logs", cleanEventLogs})
+		tasks = append(tasks, cleanTask{CategoryEventLogs, cleanEventLogs})
 	}
 	if opts.DeliveryOptimization {
-		tasks = append(tasks, cleanTask{"Delivery Optimization", cleanDeliveryOptimization})
+		tasks = append(tasks, cleanTask{CategoryDeliveryOptimization, cleanDeliveryOptimization})
 	}
 	if opts.RecycleBin {
-		tasks = append(tasks, cleanTask{"Recycle Bin", cleanRecycleBin})
+		tasks = append(tasks, cleanTask{CategoryRecycleBin, cleanRecycleBin})
+	}
+	if opts.FontIconRebuild {
+		tasks = append(tasks, cleanTask{CategoryFontIconRebuild, cleanFontIconRebuild})
 	}
 	if opts.ChromeCache {
-		tasks = append(tasks, cleanTask{"Chrome Cache", cleanChromeCache})
+		tasks = append(tasks, cleanTask{CategoryChromeCache, cleanChromeCache})
 	}
 	if opts.FirefoxCache {
-		tasks = append(tasks, cleanTask{"Firefox Cache", cleanFirefoxCache})
+		tasks = append(tasks, cleanTask{CategoryFirefoxCache, cleanFirefoxCache})
 	}
 	if opts.EdgeCache {
-		tasks = append(tasks, cleanTask{"Edge Cache", cleanEdgeCache})
+		tasks = append(tasks, cleanTask{CategoryEdgeCache, cleanEdgeCache})
 	}
 	if opts.BraveCache {
-		tasks = append(tasks, cleanTask{"Brave Cache", cleanBraveCache})
+		tasks = append(tasks, cleanTask{CategoryBraveCache, cleanBraveCache})
 	}
 	if opts.OperaCache {
-		tasks = append(tasks, cleanTask{"Opera Cache", cleanOperaCache})
+		tasks = append(tasks, cleanTask{CategoryOperaCache, cleanOperaCache})
 	}
 	if opts.DiscordCache {
-		tasks = append(tasks, cleanTask{"Discord Cache", cleanDiscordCache})
+		tasks = append(tasks, cleanTask{CategoryDiscordCache, cleanDiscordCache})
 	}
 	if opts.SpotifyCache {
-		tasks = append(tasks, cleanTask{"Spotify Cache", cleanSpotifyCache})
+		tasks = append(tasks, cleanTask{CategorySpotifyCache, cleanSpotifyCache})
 	}
 	if opts.SteamCache {
-		tasks = append(tasks, cleanTask{"Steam Cache", cleanSteamCache})
+		tasks = append(tasks, cleanTask{CategorySteamCache, cleanSteamCache})
 	}
 	if opts.TeamsCache {
-		tasks = append(tasks, cleanTask{"Teams Cache", cleanTeamsCache})
+		tasks = append(tasks, cleanTask{CategoryTeamsCache, cleanTeamsCache})
 	}
 	if opts.VSCodeCache {
-		tasks = append(tasks, cleanTask{"VS Code Cache", cleanVSCodeCache})
+		tasks = append(tasks, cleanTask{CategoryVSCodeCache, cleanVSCodeCache})
 	}
 	if opts.JavaCache {
-		tasks = append(tasks, cleanTask{"Java Cache", cleanJavaCache})
+		tasks = append(tasks, cleanTask{CategoryJavaCache, cleanJavaCache})
 	}
-
-	if len(tasks) == 0 {
-		result.Duration = time.Since(start)
-		return result
+	if opts.StoreAppCache {
+		tasks = append(tasks, cleanTask{CategoryStoreAppCache, cleanStoreAppCache})
 	}
-
-	// Run categories concurrently via worker pool
-	taskCh := make(chan cleanTask, len(tasks))
-	resultCh := make(chan CleanResult, len(tasks))
-
-	workers := maxCleanWorkers
-	if len(tasks) < workers {
-		workers = len(tasks)
+	if opts.RecentDocuments {
+		tasks = append(tasks, cleanTask{CategoryRecentDocuments, cleanRecentDocuments})
 	}
-
-	var wg sync.WaitGroup
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for task := range taskCh {
-				resultCh <- cleanCategory(ctx, task.name, task.fn, opts)
-			}
-		}()
+	if opts.JumpLists {
+		tasks = append(tasks, cleanTask{CategoryJumpLists, cleanJumpLists})
 	}
-
-	for _, t := range tasks {
-		taskCh <- t
+	if opts.ClipboardHistory {
+		tasks = append(tasks, cleanTask{CategoryClipboardHistory, cleanClipboardHistory})
+	}
+	if opts.ExplorerMRU {
+		tasks = append(tasks, cleanTask{CategoryExplorerMRU, cleanExplorerMRU})
+	}
+	for _, id := range opts.Rules {
+		if r, ok := lookupRule(id); ok {
+			tasks = append(tasks, cleanTask{Category(r.App), ruleTask(r)})
+		} else {
+			log.Printf("[SysCleaner] Unknown cleaning rule: %s", id)
+		}
 	}
-	close(taskCh)
 
-	// Close results channel once all workers finish
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+	return tasks
+}
 
-	for r := range resultCh {
-		result.merge(r)
-	}
+// CategoriesBySpace returns the categories in r.Categories ordered by space
+// freed, largest first, with ties broken by name.
+func (r CleanResult) CategoriesBySpace() []Category {
+	cats := make([]Category, 0, len(r.Categories))
+	for c := range r.Categories {
+		cats = append(cats, c)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		a, b := r.Categories[cats[i]], r.Categories[cats[j]]
+		if a.SpaceFreed != b.SpaceFreed {
+			return a.SpaceFreed > b.SpaceFreed
+		}
+		return cats[i] < cats[j]
+	})
+	return cats
+}
 
-	result.Duration = time.Since(start)
-	log.Printf("[SysCleaner] Cleanup complete: %d files deleted, %d skipped, %s freed in %s",
-		result.FilesDeleted, result.SkippedFiles, FormatBytes(result.SpaceFreed), result.Duration.Round(time.Millisecond))
-	return result
+// categoryResult returns the per-category view of a result.
+func (r CleanResult) categoryResult() CategoryResult {
+	return CategoryResult{
+		FilesDeleted:    r.FilesDeleted,
+		SkippedFiles:    r.SkippedFiles,
+		SpaceFreed:      r.SpaceFreed,
+		LockedFiles:     r.LockedFiles,
+		PermissionFiles: r.PermissionFiles,
+		Duration:        r.Duration,
+		Errors:          r.Errors,
+	}
 }
 
 func (r *CleanResult) merge(other CleanResult) {
@@ -270,16 +586,19 @@ func (r *CleanResult) merge(other CleanResult) {
 	r.LockedFiles += other.LockedFiles
 	r.PermissionFiles += other.PermissionFiles
 	r.Errors = append(r.Errors, other.Errors...)
+	r.Locked = append(r.Locked, other.Locked...)
 }
 
 // cleanCategory runs a category cleaning function with timeout and progress reporting
-func cleanCategory(ctx context.Context, category string, fn func(CleanOptions) CleanResult, opts CleanOptions) CleanResult {
+func cleanCategory(ctx context.Context, category Category, fn func(CleanOptions) CleanResult, opts CleanOptions) CleanResult {
 	log.Printf("[SysCleaner] Cleaning %s...", category)
+	start := time.Now()
 
 	if opts.Progress != nil {
-		opts.Progress(category, 0, 100)
+		opts.Progress(string(category), 0, 100)
 	}
 
+	opts.category = category
 	done := make(chan CleanResult, 1)
 	go func() {
 		done <- fn(opts)
@@ -288,13 +607,37 @@ func cleanCategory(ctx context.Context, category string, fn func(CleanOptions) C
 	select {
 	case result := <-done:
 		if opts.Progress != nil {
-			opts.Progress(category, 100, 100)
+			opts.Progress(string(category), 100, 100)
+		}
+		result.Duration = time.Since(start)
+		if !opts.DryRun && result.FilesDeleted > 0 {
+			auditDeletion(category, result)
 		}
 		return result
 	case <-ctx.Done():
-		log.Printf("[SysCleaner] %s cleaning timed out", category)
-		return CleanResult{Errors: []error{fmt.Errorf("%s cleaning timed out", category)}}
+		return interruptedResult(ctx, category)
+	}
+}
+
+// auditDeletion records a category's deletions in the audit log.
+func auditDeletion(category Category, result CleanResult) {
+	undo := ""
+	if dir := currentQuarantineDir(); dir != "" {
+		undo = "quarantine:" + dir
 	}
+	logger.Audit(logger.AuditDelete, string(category),
+		fmt.Sprintf("%d files, %s", result.FilesDeleted, FormatBytes(result.SpaceFreed)), undo)
+}
+
+// interruptedResult is the result of a category abandoned because ctx
+// finished, distinguishing the operation timeout from caller cancellation.
+func interruptedResult(ctx context.Context, category Category) CleanResult {
+	reason := "canceled"
+	if ctx.Err() == context.DeadlineExceeded {
+		reason = "timed out"
+	}
+	log.Printf("[SysCleaner] %s cleaning %s", category, reason)
+	return CleanResult{Errors: []error{fmt.Errorf("%s cleaning %s: %w", category, reason, ctx.Err())}}
 }
 
 // removeWithTimeout attempts to remove a file with a timeout
@@ -312,8 +655,19 @@ func removeWithTimeout(path string, timeout time.Duration) error {
 	}
 }
 
+// fileMatcher decides whether a file found while walking a directory should
+// be cleaned. A nil matcher accepts every file.
+type fileMatcher func(path string, d os.DirEntry) bool
+
 // cleanDirectory removes files in a directory with timeouts and proper error handling
 func cleanDirectory(dir string, maxAge time.Duration, dryRun bool) CleanResult {
+	return cleanDirectoryMatching(dir, maxAge, dryRun, nil, nil)
+}
+
+// cleanDirectoryMatching is cleanDirectory restricted to files accepted by
+// match and paced by th. match is only consulted for files, never
+// directories; a nil th does not pace.
+func cleanDirectoryMatching(dir string, maxAge time.Duration, dryRun bool, match fileMatcher, th *throttle) CleanResult {
 	result := CleanResult{}
 
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -325,7 +679,7 @@ func cleanDirectory(dir string, maxAge time.Duration, dryRun bool) CleanResult {
 
 	done := make(chan CleanResult, 1)
 	go func() {
-		r := cleanDirectoryInternal(dir, maxAge, dryRun)
+		r := cleanDirectoryInternal(dir, maxAge, dryRun, match, th)
 		done <- r
 	}()
 
@@ -339,9 +693,12 @@ func cleanDirectory(dir string, maxAge time.Duration, dryRun bool) CleanResult {
 	}
 }
 
-func cleanDirectoryInternal(dir string, maxAge time.Duration, dryRun bool) CleanResult {
+func cleanDirectoryInternal(dir string, maxAge time.Duration, dryRun bool, match fileMatcher, th *throttle) CleanResult {
 	result := CleanResult{}
 	now := time.Now()
+	// Case sensitivity is a per-directory setting on NTFS and ReFS (Dev
+	// Drives, WSL-shared folders), so it is checked as each one is entered.
+	caseSensitive := make(map[string]bool)
 
 	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -355,6 +712,12 @@ func cleanDirectoryInternal(dir string, maxAge time.Duration, dryRun bool) Clean
 		}
 
 		if d.IsDir() {
+			if caseSensitiveDir(path) {
+				caseSensitive[path] = true
+			}
+			return nil
+		}
+		if match != nil && !match(path, d) {
 			return nil
 		}
 
@@ -369,14 +732,32 @@ func cleanDirectoryInternal(dir string, maxAge time.Duration, dryRun bool) Clean
 			return nil
 		}
 
+		if isExcludedCase(path, caseSensitive[filepath.Dir(path)]) {
+			return nil
+		}
+
 		if dryRun {
 			result.FilesDeleted++
 			result.SpaceFreed += info.Size()
+		} else if ce := heldByWhitelisted(path); ce != nil {
+			result.SkippedFiles++
+			result.LockedFiles++
+			result.Locked = append(result.Locked, ce)
 		} else {
-			if err := removeWithTimeout(path, fileTimeout); err != nil {
+			th.wait(info.Size())
+			if err := deleteFile(path, info.Size()); err != nil {
 				ce := classifyError(path, err)
+				if ce.Type == ErrorLocked && resolveLockedFile(ce, info.Size()) {
+					result.FilesDeleted++
+					result.SpaceFreed += info.Size()
+					return nil
+				}
 				switch ce.Type {
-				case ErrorLocked, ErrorTimeout:
+				case ErrorLocked:
+					result.SkippedFiles++
+					result.LockedFiles++
+					result.Locked = append(result.Locked, ce)
+				case ErrorTimeout:
 					result.SkippedFiles++
 					result.LockedFiles++
 				case ErrorPermissionDenied:
@@ -408,7 +789,7 @@ func cleanWindowsTemp(opts CleanOptions) CleanResult {
 	if winDir == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(winDir, "Temp"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(winDir, "Temp"), 0, opts)
 }
 
 func cleanUserTemp(opts CleanOptions) CleanResult {
@@ -422,7 +803,7 @@ func cleanUserTemp(opts CleanOptions) CleanResult {
 	}
 	for _, dir := range dedup(tempDirs) {
 		if dir != "" {
-			result.merge(cleanDirectory(dir, 0, opts.DryRun))
+			result.merge(cleanTarget(dir, 0, opts))
 		}
 	}
 	return result
@@ -436,7 +817,7 @@ func cleanWindowsUpdate(opts CleanOptions) CleanResult {
 	if winDir == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(winDir, "SoftwareDistribution", "Download"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(winDir, "SoftwareDistribution", "Download"), 0, opts)
 }
 
 func cleanWindowsInstaller(opts CleanOptions) CleanResult {
@@ -447,7 +828,7 @@ func cleanWindowsInstaller(opts CleanOptions) CleanResult {
 	if winDir == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(winDir, "Installer", "$PatchCache$"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(winDir, "Installer", "$PatchCache$"), 0, opts)
 }
 
 func cleanPrefetch(opts CleanOptions) CleanResult {
@@ -459,7 +840,7 @@ func cleanPrefetch(opts CleanOptions) CleanResult {
 		return CleanResult{}
 	}
 	// Only clean prefetch files older than 30 days
-	return cleanDirectory(filepath.Join(winDir, "Prefetch"), 30*24*time.Hour, opts.DryRun)
+	return cleanTarget(filepath.Join(winDir, "Prefetch"), 30*24*time.Hour, opts)
 }
 
 func cleanCrashDumps(opts CleanOptions) CleanResult {
@@ -478,21 +859,23 @@ func cleanCrashDumps(opts CleanOptions) CleanResult {
 		dirs = append(dirs, filepath.Join(winDir, "Minidump"))
 		// Windows memory dump file
 		memoryDump := filepath.Join(winDir, "MEMORY.DMP")
-		if info, err := os.Stat(memoryDump); err == nil {
-			if opts.DryRun {
-				result.FilesDeleted++
-				result.SpaceFreed += info.Size()
-			} else {
-				if err := removeWithTimeout(memoryDump, fileTimeout); err == nil {
+		if ok, _ := opts.Volumes.allows(opts.category, winDir); ok {
+			if info, err := os.Stat(memoryDump); err == nil {
+				if opts.DryRun {
 					result.FilesDeleted++
 					result.SpaceFreed += info.Size()
+				} else {
+					if err := removeWithTimeout(memoryDump, fileTimeout); err == nil {
+						result.FilesDeleted++
+						result.SpaceFreed += info.Size()
+					}
 				}
 			}
 		}
 	}
 
 	for _, dir := range dirs {
-		result.merge(cleanDirectory(dir, 0, opts.DryRun))
+		result.merge(cleanTarget(dir, 0, opts))
 	}
 	return result
 }
@@ -514,7 +897,7 @@ func cleanErrorReports(opts CleanOptions) CleanResult {
 	}
 
 	for _, dir := range dirs {
-		result.merge(cleanDirectory(dir, 0, opts.DryRun))
+		result.merge(cleanTarget(dir, 0, opts))
 	}
 	return result
 }
@@ -530,6 +913,9 @@ func cleanThumbnailCache(opts CleanOptions) CleanResult {
 	}
 
 	thumbDir := filepath.Join(localAppData, "Microsoft", "Windows", "Explorer")
+	if ok, _ := opts.Volumes.allows(opts.category, thumbDir); !ok {
+		return result
+	}
 	entries, err := os.ReadDir(thumbDir)
 	if err != nil {
 		return result
@@ -574,6 +960,9 @@ func cleanIconCache(opts CleanOptions) CleanResult {
 	}
 
 	iconCacheFile := filepath.Join(localAppData, "IconCache.db")
+	if ok, _ := opts.Volumes.allows(opts.category, localAppData); !ok {
+		return result
+	}
 	if info, err := os.Stat(iconCacheFile); err == nil {
 		if opts.DryRun {
 			result.FilesDeleted++
@@ -596,9 +985,14 @@ func cleanFontCache(opts CleanOptions) CleanResult {
 	if winDir == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(winDir, "ServiceProfiles", "LocalService", "AppData", "Local", "FontCache"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(winDir, "ServiceProfiles", "LocalService", "AppData", "Local", "FontCache"), 0, opts)
 }
 
+// GameShaderCacheDirs, if set, returns shader cache folders inside
+// installed games, cleaned with the Shader Cache category. pkg/gaming sets
+// it from the launcher libraries it finds.
+var GameShaderCacheDirs func() []string
+
 func cleanShaderCache(opts CleanOptions) CleanResult {
 	result := CleanResult{}
 	if runtime.GOOS != "windows" {
@@ -615,9 +1009,12 @@ func cleanShaderCache(opts CleanOptions) CleanResult {
 		filepath.Join(localAppData, "NVIDIA", "GLCache"),
 		filepath.Join(localAppData, "AMD", "DxCache"),
 	}
+	if GameShaderCacheDirs != nil {
+		shaderDirs = append(shaderDirs, GameShaderCacheDirs()...)
+	}
 
 	for _, dir := range shaderDirs {
-		result.merge(cleanDirectory(dir, 0, opts.DryRun))
+		result.merge(cleanTarget(dir, 0, opts))
 	}
 	return result
 }
@@ -654,7 +1051,7 @@ func cleanWindowsLogs(opts CleanOptions) CleanResult {
 	}
 
 	for _, dir := range logDirs {
-		result.merge(cleanDirectory(dir, 30*24*time.Hour, opts.DryRun))
+		result.merge(cleanTarget(dir, 30*24*time.Hour, opts))
 	}
 	return result
 }
@@ -687,7 +1084,7 @@ func cleanDeliveryOptimization(opts CleanOptions) CleanResult {
 	if winDir == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(winDir, "SoftwareDistribution", "DeliveryOptimization"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(winDir, "SoftwareDistribution", "DeliveryOptimization"), 0, opts)
 }
 
 func cleanRecycleBin(opts CleanOptions) CleanResult {
@@ -707,7 +1104,7 @@ func cleanRecycleBin(opts CleanOptions) CleanResult {
 }
 
 // Application category cleaners
-func cleanChromiumProfiles(userDataDir string, dryRun bool) CleanResult {
+func cleanChromiumProfiles(userDataDir string, opts CleanOptions) CleanResult {
 	result := CleanResult{}
 	if _, err := os.Stat(userDataDir); os.IsNotExist(err) {
 		return result
@@ -728,7 +1125,7 @@ func cleanChromiumProfiles(userDataDir string, dryRun bool) CleanResult {
 		if name == "Default" || strings.HasPrefix(name, "Profile ") {
 			for _, sub := range cacheSubdirs {
 				cacheDir := filepath.Join(userDataDir, name, sub)
-				result.merge(cleanDirectory(cacheDir, 0, dryRun))
+				result.merge(cleanTarget(cacheDir, 0, opts))
 			}
 		}
 	}
@@ -743,7 +1140,7 @@ func cleanChromeCache(opts CleanOptions) CleanResult {
 	if localAppData == "" {
 		return CleanResult{}
 	}
-	return cleanChromiumProfiles(filepath.Join(localAppData, "Google", "Chrome", "User Data"), opts.DryRun)
+	return cleanChromiumProfiles(filepath.Join(localAppData, "Google", "Chrome", "User Data"), opts)
 }
 
 func cleanFirefoxCache(opts CleanOptions) CleanResult {
@@ -764,8 +1161,8 @@ func cleanFirefoxCache(opts CleanOptions) CleanResult {
 
 	for _, entry := range entries {
 		if entry.IsDir() {
-			result.merge(cleanDirectory(filepath.Join(profilesDir, entry.Name(), "cache2"), 0, opts.DryRun))
-			result.merge(cleanDirectory(filepath.Join(profilesDir, entry.Name(), "startupCache"), 0, opts.DryRun))
+			result.merge(cleanTarget(filepath.Join(profilesDir, entry.Name(), "cache2"), 0, opts))
+			result.merge(cleanTarget(filepath.Join(profilesDir, entry.Name(), "startupCache"), 0, opts))
 		}
 	}
 	return result
@@ -779,7 +1176,7 @@ func cleanEdgeCache(opts CleanOptions) CleanResult {
 	if localAppData == "" {
 		return CleanResult{}
 	}
-	return cleanChromiumProfiles(filepath.Join(localAppData, "Microsoft", "Edge", "User Data"), opts.DryRun)
+	return cleanChromiumProfiles(filepath.Join(localAppData, "Microsoft", "Edge", "User Data"), opts)
 }
 
 func cleanBraveCache(opts CleanOptions) CleanResult {
@@ -790,7 +1187,7 @@ func cleanBraveCache(opts CleanOptions) CleanResult {
 	if localAppData == "" {
 		return CleanResult{}
 	}
-	return cleanChromiumProfiles(filepath.Join(localAppData, "BraveSoftware", "Brave-Browser", "User Data"), opts.DryRun)
+	return cleanChromiumProfiles(filepath.Join(localAppData, "BraveSoftware", "Brave-Browser", "User Data"), opts)
 }
 
 func cleanOperaCache(opts CleanOptions) CleanResult {
@@ -809,7 +1206,7 @@ func cleanOperaCache(opts CleanOptions) CleanResult {
 	}
 
 	for _, dir := range operaDirs {
-		result.merge(cleanChromiumProfiles(dir, opts.DryRun))
+		result.merge(cleanChromiumProfiles(dir, opts))
 	}
 	return result
 }
@@ -831,7 +1228,7 @@ func cleanDiscordCache(opts CleanOptions) CleanResult {
 	}
 
 	for _, dir := range discordDirs {
-		result.merge(cleanDirectory(dir, 0, opts.DryRun))
+		result.merge(cleanTarget(dir, 0, opts))
 	}
 	return result
 }
@@ -844,7 +1241,7 @@ func cleanSpotifyCache(opts CleanOptions) CleanResult {
 	if localAppData == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(localAppData, "Spotify", "Storage"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(localAppData, "Spotify", "Storage"), 0, opts)
 }
 
 func cleanSteamCache(opts CleanOptions) CleanResult {
@@ -855,7 +1252,7 @@ func cleanSteamCache(opts CleanOptions) CleanResult {
 	if localAppData == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(localAppData, "Steam", "htmlcache"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(localAppData, "Steam", "htmlcache"), 0, opts)
 }
 
 func cleanTeamsCache(opts CleanOptions) CleanResult {
@@ -875,7 +1272,7 @@ func cleanTeamsCache(opts CleanOptions) CleanResult {
 	}
 
 	for _, dir := range teamsDirs {
-		result.merge(cleanDirectory(dir, 0, opts.DryRun))
+		result.merge(cleanTarget(dir, 0, opts))
 	}
 	return result
 }
@@ -897,7 +1294,7 @@ func cleanVSCodeCache(opts CleanOptions) CleanResult {
 	}
 
 	for _, dir := range vscodeDirs {
-		result.merge(cleanDirectory(dir, 0, opts.DryRun))
+		result.merge(cleanTarget(dir, 0, opts))
 	}
 	return result
 }
@@ -910,7 +1307,7 @@ func cleanJavaCache(opts CleanOptions) CleanResult {
 	if userProfile == "" {
 		return CleanResult{}
 	}
-	return cleanDirectory(filepath.Join(userProfile, "AppData", "LocalLow", "Sun", "Java", "Deployment", "cache"), 0, opts.DryRun)
+	return cleanTarget(filepath.Join(userProfile, "AppData", "LocalLow", "Sun", "Java", "Deployment", "cache"), 0, opts)
 }
 
 // FormatBytes formats a byte count into a human-readable string
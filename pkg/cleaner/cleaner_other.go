@@ -1,3 +1,18 @@
 //go:build !windows
 
 package cleaner
+
+// setBackgroundModeNative is a no-op outside Windows.
+func setBackgroundModeNative(on bool) error {
+	return nil
+}
+
+// stopServiceIfRunning is a no-op outside Windows.
+func stopServiceIfRunning(name string) (bool, error) {
+	return false, nil
+}
+
+// startService is a no-op outside Windows.
+func startService(name string) error {
+	return nil
+}
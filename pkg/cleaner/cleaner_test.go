@@ -1,6 +1,7 @@
 package cleaner
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -188,6 +189,98 @@ func TestCleanError_ErrorString(t *testing.T) {
 	}
 }
 
+func TestCleanError_ErrorStringWithHolders(t *testing.T) {
+	ce := &CleanError{
+		Path:    "/test/path",
+		Type:    ErrorLocked,
+		Err:     errors.New("in use"),
+		Holders: []LockHolder{{PID: 42, Name: "chrome.exe"}},
+	}
+	expected := "/test/path: in use (held by chrome.exe (PID 42))"
+	if ce.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, ce.Error())
+	}
+}
+
+func TestCanCloseHolder_RespectsWhitelist(t *testing.T) {
+	SetCloseLockHolders(true, []string{"Discord.exe"})
+	t.Cleanup(func() { SetCloseLockHolders(false, nil) })
+
+	if canCloseHolder(LockHolder{PID: 10, Name: "discord.exe"}) {
+		t.Error("whitelisted process should not be closable")
+	}
+	if canCloseHolder(LockHolder{PID: 11, Name: "explorer.exe"}) {
+		t.Error("critical process should not be closable")
+	}
+	if !canCloseHolder(LockHolder{PID: 12, Name: "notepad.exe"}) {
+		t.Error("non-whitelisted process should be closable")
+	}
+
+	SetCloseLockHolders(false, nil)
+	if canCloseHolder(LockHolder{PID: 12, Name: "notepad.exe"}) {
+		t.Error("closing should be disabled")
+	}
+}
+
+func TestCleanDirectory_SkipsFilesHeldByWhitelisted(t *testing.T) {
+	dir := t.TempDir()
+	held := filepath.Join(dir, "voice.log")
+	free := filepath.Join(dir, "old.tmp")
+	os.WriteFile(held, []byte("held"), 0644)
+	os.WriteFile(free, []byte("free"), 0644)
+
+	origInUse, origLookup := inUseCheck, holderLookup
+	inUseCheck = func(path string) bool { return path == held }
+	holderLookup = func(path string) ([]LockHolder, error) {
+		return []LockHolder{{PID: 7, Name: "Discord.exe"}}, nil
+	}
+	SetCloseLockHolders(false, []string{"discord.exe"})
+	t.Cleanup(func() {
+		inUseCheck, holderLookup = origInUse, origLookup
+		SetCloseLockHolders(false, nil)
+	})
+
+	result := cleanDirectory(dir, 0, false)
+	if result.FilesDeleted != 1 || result.SkippedFiles != 1 || result.LockedFiles != 1 {
+		t.Errorf("expected 1 deleted and 1 locked skip, got %+v", result)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("whitelisted holder should not produce errors, got %v", result.Errors)
+	}
+	if _, err := os.Stat(held); err != nil {
+		t.Error("file held by a whitelisted process was deleted")
+	}
+}
+
+func TestCleanDirectory_CaseSensitiveExclusions(t *testing.T) {
+	dir := t.TempDir()
+	sensitive := filepath.Join(dir, "repo")
+	insensitive := filepath.Join(dir, "temp")
+	for _, d := range []string{sensitive, insensitive} {
+		os.MkdirAll(d, 0755)
+		os.WriteFile(filepath.Join(d, "build.log"), []byte("x"), 0644)
+	}
+
+	orig := caseSensitiveDir
+	caseSensitiveDir = func(path string) bool { return path == sensitive }
+	SetExclusions([]string{filepath.Join(dir, "*", "BUILD.LOG")})
+	t.Cleanup(func() {
+		caseSensitiveDir = orig
+		SetExclusions(nil)
+	})
+
+	result := cleanDirectory(dir, 0, false)
+	if result.FilesDeleted != 1 {
+		t.Errorf("expected 1 file deleted, got %d", result.FilesDeleted)
+	}
+	if _, err := os.Stat(filepath.Join(sensitive, "build.log")); !os.IsNotExist(err) {
+		t.Error("BUILD.LOG should not exclude build.log in a case-sensitive directory")
+	}
+	if _, err := os.Stat(filepath.Join(insensitive, "build.log")); err != nil {
+		t.Error("BUILD.LOG should exclude build.log in a case-insensitive directory")
+	}
+}
+
 // ---------- FormatBytes tests ----------
 
 func TestFormatBytes(t *testing.T) {
@@ -252,3 +345,143 @@ func TestCleanResult_Merge(t *testing.T) {
 		t.Errorf("expected 2 errors, got %d", len(a.Errors))
 	}
 }
+
+func TestPerformClean_PopulatesCategories(t *testing.T) {
+	result := PerformClean(CleanOptions{WindowsTemp: true, ChromeCache: true, DryRun: true})
+
+	for _, cat := range []Category{CategoryWindowsTemp, CategoryChromeCache} {
+		if _, ok := result.Categories[cat]; !ok {
+			t.Errorf("expected category %q in result", cat)
+		}
+	}
+	if len(result.Categories) != 2 {
+		t.Errorf("expected 2 categories, got %d", len(result.Categories))
+	}
+}
+
+func TestCleanResult_CategoriesBySpace(t *testing.T) {
+	r := CleanResult{Categories: map[Category]CategoryResult{
+		CategoryWindowsTemp: {SpaceFreed: 640},
+		CategoryChromeCache: {SpaceFreed: 1200},
+		CategoryPrefetch:    {SpaceFreed: 640},
+	}}
+
+	got := r.CategoriesBySpace()
+	want := []Category{CategoryChromeCache, CategoryPrefetch, CategoryWindowsTemp}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, got)
+		}
+	}
+}
+
+func TestBuildTasks_PrivacyCategoriesAreIndependent(t *testing.T) {
+	tasks := buildTasks(CleanOptions{JumpLists: true, ExplorerMRU: true})
+	if len(tasks) != 2 || tasks[0].name != CategoryJumpLists || tasks[1].name != CategoryExplorerMRU {
+		names := make([]Category, len(tasks))
+		for i, task := range tasks {
+			names[i] = task.name
+		}
+		t.Errorf("expected only Jump Lists and Explorer MRU, got %v", names)
+	}
+}
+
+func TestPerformCleanContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := PerformCleanContext(ctx, CleanOptions{UserTemp: true, ChromeCache: true, DryRun: true})
+	if result.FilesDeleted != 0 {
+		t.Errorf("canceled clean should not touch files, got %d", result.FilesDeleted)
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("expected one error per category, got %v", result.Errors)
+	}
+	for _, err := range result.Errors {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	}
+}
+
+func TestSelect_CoversEveryCategory(t *testing.T) {
+	for _, c := range AllCategories() {
+		var opts CleanOptions
+		if err := opts.Select(c); err != nil {
+			t.Fatalf("Select(%q): %v", c, err)
+		}
+		if got := opts.Categories(); len(got) != 1 || got[0] != c {
+			t.Errorf("Select(%q) enabled %v", c, got)
+		}
+	}
+}
+
+func TestPerformClean_SkipsDisabledCategories(t *testing.T) {
+	SetDisabledCategories([]Category{CategoryEventLogs})
+	defer SetDisabledCategories(nil)
+
+	result := PerformClean(CleanOptions{EventLogs: true, ChromeCache: true, DryRun: true})
+	if _, ok := result.Categories[CategoryEventLogs]; ok {
+		t.Error("disabled category was cleaned")
+	}
+	if _, ok := result.Categories[CategoryChromeCache]; !ok {
+		t.Error("enabled category was not cleaned")
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected the skipped category to be reported")
+	}
+}
+
+func TestSelect_UnknownCategory(t *testing.T) {
+	opts := CleanOptions{UserTemp: true}
+	if err := opts.Select(CategoryChromeCache, "Nope"); err == nil {
+		t.Fatal("expected an error for an unknown category")
+	}
+	if opts.ChromeCache {
+		t.Error("opts should be unchanged after an error")
+	}
+}
+
+func TestCleanFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "IconCache.db")
+	os.WriteFile(path, []byte("icons"), 0644)
+
+	if r := cleanFile(path, CleanOptions{DryRun: true}); r.FilesDeleted != 1 || r.SpaceFreed != 5 {
+		t.Errorf("dry run should count the file, got %+v", r)
+	}
+	if r := cleanFile(path, CleanOptions{}); r.FilesDeleted != 1 {
+		t.Errorf("expected the file to be deleted, got %+v", r)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file still exists")
+	}
+	if r := cleanFile(path, CleanOptions{}); r.FilesDeleted != 0 || len(r.Errors) != 0 {
+		t.Errorf("missing file should be a no-op, got %+v", r)
+	}
+}
+
+func TestEstimateKey(t *testing.T) {
+	base := CleanOptions{UserTemp: true}
+	onlyD := CleanOptions{UserTemp: true, Volumes: VolumePolicy{Never: []string{"D:"}}}
+	if estimateKey(base) == estimateKey(onlyD) {
+		t.Error("a volume policy change should not reuse the cached estimate")
+	}
+	if estimateKey(base) != estimateKey(CleanOptions{UserTemp: true, DryRun: true}) {
+		t.Error("the same selection should share an estimate")
+	}
+}
+
+func TestEstimate_InvalidatedBySettings(t *testing.T) {
+	t.Cleanup(func() { SetExclusions(nil); InvalidateEstimates() })
+	estimateMu.Lock()
+	estimateCache[estimateKey(CleanOptions{})] = estimateEntry{at: time.Now()}
+	estimateMu.Unlock()
+
+	SetExclusions([]string{`C:\Keep\`})
+	estimateMu.Lock()
+	n := len(estimateCache)
+	estimateMu.Unlock()
+	if n != 0 {
+		t.Error("changing the exclusions should discard cached estimates")
+	}
+}
@@ -1,3 +1,78 @@
 //go:build windows
 
 package cleaner
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// setBackgroundModeNative begins or ends the process's background
+// processing mode.
+func setBackgroundModeNative(on bool) error {
+	mode := uint32(windows.PROCESS_MODE_BACKGROUND_END)
+	if on {
+		mode = windows.PROCESS_MODE_BACKGROUND_BEGIN
+	}
+	return windows.SetPriorityClass(windows.CurrentProcess(), mode)
+}
+
+// stopServiceIfRunning stops the named service through the Service Control
+// Manager and reports whether it was running, so the caller knows whether
+// to start it again afterwards.
+func stopServiceIfRunning(name string) (bool, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to SCM: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to open service %s: %w", name, err)
+	}
+	defer s.Close()
+
+	status, err := s.Query()
+	if err != nil {
+		return false, fmt.Errorf("failed to query service %s: %w", name, err)
+	}
+	if status.State == svc.Stopped {
+		return false, nil
+	}
+	if status, err = s.Control(svc.Stop); err != nil {
+		return false, fmt.Errorf("failed to stop service %s: %w", name, err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for status.State != svc.Stopped && time.Now().Before(deadline) {
+		time.Sleep(300 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return true, fmt.Errorf("failed to query service %s: %w", name, err)
+		}
+	}
+	if status.State != svc.Stopped {
+		return true, fmt.Errorf("service %s did not stop within timeout", name)
+	}
+	return true, nil
+}
+
+// startService starts the named service through the Service Control Manager.
+func startService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to SCM: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("failed to open service %s: %w", name, err)
+	}
+	defer s.Close()
+	return s.Start()
+}
@@ -0,0 +1,98 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	exclusionMu sync.RWMutex
+	exclusions  []string
+)
+
+// SetExclusions replaces the list of path patterns the cleaner never
+// touches. Patterns use filepath.Match syntax and are matched against the
+// full file path, case-insensitively except inside case-sensitive
+// directories; a pattern ending in a path separator excludes everything
+// below that directory.
+func SetExclusions(patterns []string) {
+	exclusionMu.Lock()
+	defer exclusionMu.Unlock()
+	exclusions = normalizePatterns(patterns)
+	InvalidateEstimates()
+}
+
+// Exclusions returns the active exclusion patterns.
+func Exclusions() []string {
+	exclusionMu.RLock()
+	defer exclusionMu.RUnlock()
+	return append([]string(nil), exclusions...)
+}
+
+// isExcluded reports whether path matches any exclusion pattern, ignoring
+// case.
+func isExcluded(path string) bool {
+	return isExcludedCase(path, false)
+}
+
+// isExcludedCase is isExcluded for a file whose directory may compare
+// names case sensitively.
+func isExcludedCase(path string, caseSensitive bool) bool {
+	exclusionMu.RLock()
+	defer exclusionMu.RUnlock()
+	return matchPatternsCase(exclusions, path, caseSensitive)
+}
+
+// normalizePatterns cleans patterns for matchPatterns, keeping a trailing
+// separator on directory patterns.
+func normalizePatterns(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, filepath.Clean(p)+trailingSep(p))
+		}
+	}
+	return out
+}
+
+// matchPatterns reports whether path matches any normalized pattern,
+// ignoring case.
+func matchPatterns(patterns []string, path string) bool {
+	return matchPatternsCase(patterns, path, false)
+}
+
+// matchPatternsCase is matchPatterns with optional case-sensitive
+// comparison, for paths inside case-sensitive directories where "Build"
+// and "build" are different folders.
+func matchPatternsCase(patterns []string, path string, caseSensitive bool) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	path = filepath.Clean(path)
+	if !caseSensitive {
+		path = strings.ToLower(path)
+	}
+	for _, p := range patterns {
+		if !caseSensitive {
+			p = strings.ToLower(p)
+		}
+		if strings.HasSuffix(p, string(filepath.Separator)) {
+			if strings.HasPrefix(path, p) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(p, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func trailingSep(p string) string {
+	if strings.HasSuffix(p, `\`) || strings.HasSuffix(p, "/") {
+		return string(filepath.Separator)
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package cleaner
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"syscleaner/pkg/logger"
+)
+
+// fontCacheService holds the font cache files open while it runs.
+const fontCacheService = "FontCache"
+
+// cleanFontIconRebuild stops the Windows Font Cache service, deletes the
+// font and icon cache databases so Windows rebuilds them, and restarts the
+// service. This is the usual fix for corrupted fonts or blank icons.
+func cleanFontIconRebuild(opts CleanOptions) (result CleanResult) {
+	if runtime.GOOS != "windows" {
+		return result
+	}
+	winDir := os.Getenv("WINDIR")
+	localAppData := os.Getenv("LOCALAPPDATA")
+
+	if !opts.DryRun {
+		wasRunning, err := stopServiceIfRunning(fontCacheService)
+		if err != nil {
+			log.Printf("[SysCleaner] Could not stop %s service: %v", fontCacheService, err)
+			result.Errors = append(result.Errors, err)
+		}
+		if wasRunning {
+			logger.Audit(logger.AuditServiceStop, fontCacheService, "restarted after cleaning", "service-start:"+fontCacheService)
+			defer func() {
+				if err := startService(fontCacheService); err != nil {
+					log.Printf("[SysCleaner] Could not restart %s service: %v", fontCacheService, err)
+					result.Errors = append(result.Errors, err)
+				}
+			}()
+		}
+	}
+
+	if winDir != "" {
+		result.merge(cleanTarget(filepath.Join(winDir, "ServiceProfiles", "LocalService", "AppData", "Local", "FontCache"), 0, opts))
+		result.merge(cleanFile(filepath.Join(winDir, "System32", "FNTCACHE.DAT"), opts))
+	}
+	if localAppData != "" {
+		result.merge(cleanFile(filepath.Join(localAppData, "IconCache.db"), opts))
+		explorer := filepath.Join(localAppData, "Microsoft", "Windows", "Explorer")
+		result.merge(cleanTargetMatching(explorer, 0, opts, func(path string, d os.DirEntry) bool {
+			return filepath.Dir(path) == explorer && strings.HasPrefix(strings.ToLower(d.Name()), "iconcache_")
+		}))
+	}
+	return result
+}
+
+// cleanFile deletes a single file with the same volume, exclusion and
+// locked-file handling as a directory clean.
+func cleanFile(path string, opts CleanOptions) CleanResult {
+	result := CleanResult{}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || isExcluded(path) {
+		return result
+	}
+	if ok, _ := opts.Volumes.allows(opts.category, path); !ok {
+		return result
+	}
+	if opts.DryRun {
+		result.FilesDeleted++
+		result.SpaceFreed += info.Size()
+		return result
+	}
+	if err := deleteFile(path, info.Size()); err != nil {
+		ce := classifyError(path, err)
+		switch ce.Type {
+		case ErrorLocked, ErrorTimeout:
+			result.SkippedFiles++
+			result.LockedFiles++
+			result.Locked = append(result.Locked, ce)
+		case ErrorPermissionDenied:
+			result.SkippedFiles++
+			result.PermissionFiles++
+		default:
+			result.Errors = append(result.Errors, ce)
+		}
+		return result
+	}
+	result.FilesDeleted++
+	result.SpaceFreed += info.Size()
+	return result
+}
@@ -0,0 +1,143 @@
+package cleaner
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LockHolder identifies a process that has a file open.
+type LockHolder struct {
+	PID  uint32
+	Name string
+}
+
+func (h LockHolder) String() string {
+	return fmt.Sprintf("%s (PID %d)", h.Name, h.PID)
+}
+
+var (
+	lockMu sync.RWMutex
+
+	// closeLockHolders enables the "close holder and retry" flow.
+	closeLockHolders bool
+	// lockHolderWhitelist lists process names that must never be closed to
+	// free a file, in addition to criticalProcesses. Files they hold are
+	// skipped without attempting deletion.
+	lockHolderWhitelist []string
+)
+
+// criticalProcesses are never terminated to release a lock, regardless of
+// the user's whitelist.
+var criticalProcesses = []string{
+	"system", "smss.exe", "csrss.exe", "wininit.exe", "winlogon.exe",
+	"services.exe", "lsass.exe", "svchost.exe", "explorer.exe", "dwm.exe",
+	"syscleaner.exe",
+}
+
+// SetCloseLockHolders enables or disables closing the process holding a
+// locked file and retrying the delete. Processes in whitelist (matched
+// case-insensitively by executable name) are never closed, and files they
+// hold are skipped whether or not closing is enabled.
+func SetCloseLockHolders(enabled bool, whitelist []string) {
+	lockMu.Lock()
+	defer lockMu.Unlock()
+	closeLockHolders = enabled
+	lockHolderWhitelist = append([]string(nil), whitelist...)
+}
+
+// canCloseHolder reports whether a holder may be terminated to free a file.
+func canCloseHolder(h LockHolder) bool {
+	lockMu.RLock()
+	defer lockMu.RUnlock()
+	if !closeLockHolders || h.PID == 0 {
+		return false
+	}
+	name := strings.ToLower(h.Name)
+	for _, p := range criticalProcesses {
+		if name == p {
+			return false
+		}
+	}
+	return !isWhitelistedLocked(name)
+}
+
+// isWhitelistedLocked reports whether name is in lockHolderWhitelist. The
+// caller must hold lockMu.
+func isWhitelistedLocked(name string) bool {
+	for _, p := range lockHolderWhitelist {
+		if strings.EqualFold(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lock probes, replaceable in tests.
+var (
+	inUseCheck   = fileInUse
+	holderLookup = FindLockHolders
+)
+
+// heldByWhitelisted checks, before a delete, whether path is open in a
+// whitelisted process. It returns a locked CleanError naming the holders if
+// so, and nil otherwise. The Restart Manager is only queried for files that
+// are actually in use.
+func heldByWhitelisted(path string) *CleanError {
+	lockMu.RLock()
+	enabled := len(lockHolderWhitelist) > 0
+	lockMu.RUnlock()
+	if !enabled || !inUseCheck(path) {
+		return nil
+	}
+
+	holders, err := holderLookup(path)
+	if err != nil {
+		return nil
+	}
+	lockMu.RLock()
+	defer lockMu.RUnlock()
+	for _, h := range holders {
+		if isWhitelistedLocked(h.Name) {
+			return &CleanError{
+				Path:    path,
+				Type:    ErrorLocked,
+				Err:     fmt.Errorf("in use by whitelisted process %s", h.Name),
+				Holders: holders,
+			}
+		}
+	}
+	return nil
+}
+
+// resolveLockedFile fills in the holders of a locked file and, if allowed,
+// closes them and retries the delete. It returns true if the retry succeeded.
+func resolveLockedFile(ce *CleanError, size int64) bool {
+	holders, err := FindLockHolders(ce.Path)
+	if err != nil {
+		return false
+	}
+	ce.Holders = holders
+	if len(holders) == 0 {
+		return false
+	}
+
+	for _, h := range holders {
+		if !canCloseHolder(h) {
+			return false
+		}
+	}
+	for _, h := range holders {
+		if err := terminateHolder(h.PID); err != nil {
+			log.Printf("[SysCleaner] Could not close %s holding %s: %v", h, ce.Path, err)
+			return false
+		}
+		log.Printf("[SysCleaner] Closed %s to release %s", h, ce.Path)
+	}
+
+	// Give the OS a moment to release handles before retrying
+	time.Sleep(250 * time.Millisecond)
+	return deleteFile(ce.Path, size) == nil
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package cleaner
+
+import "fmt"
+
+// FindLockHolders is only available on Windows.
+func FindLockHolders(path string) ([]LockHolder, error) {
+	return nil, fmt.Errorf("lock holder detection is only available on Windows")
+}
+
+func terminateHolder(pid uint32) error {
+	return fmt.Errorf("terminating lock holders is only available on Windows")
+}
+
+// fileInUse always reports false outside Windows, where files can be
+// deleted while open.
+func fileInUse(path string) bool {
+	return false
+}
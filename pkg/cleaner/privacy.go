@@ -0,0 +1,54 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// recentDir returns %APPDATA%\Microsoft\Windows\Recent, or "" when APPDATA
+// is not set.
+func recentDir() string {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return ""
+	}
+	return filepath.Join(appData, "Microsoft", "Windows", "Recent")
+}
+
+// cleanRecentDocuments removes the shortcuts in the Recent folder itself.
+// Jump lists live in subfolders and are cleaned separately.
+func cleanRecentDocuments(opts CleanOptions) CleanResult {
+	dir := recentDir()
+	if runtime.GOOS != "windows" || dir == "" {
+		return CleanResult{}
+	}
+	top := filepath.Clean(dir)
+	return cleanTargetMatching(dir, 0, opts, func(path string, d os.DirEntry) bool {
+		return filepath.Dir(path) == top
+	})
+}
+
+// cleanJumpLists removes the taskbar and Start menu jump list data.
+func cleanJumpLists(opts CleanOptions) CleanResult {
+	dir := recentDir()
+	if runtime.GOOS != "windows" || dir == "" {
+		return CleanResult{}
+	}
+	result := CleanResult{}
+	for _, sub := range []string{"AutomaticDestinations", "CustomDestinations"} {
+		result.merge(cleanTarget(filepath.Join(dir, sub), 0, opts))
+	}
+	return result
+}
+
+// explorerMRUKeys are the HKCU keys whose values hold Explorer's
+// most-recently-used lists. RecentDocs also has one subkey per extension.
+var explorerMRUKeys = []string{
+	`Software\Microsoft\Windows\CurrentVersion\Explorer\RunMRU`,
+	`Software\Microsoft\Windows\CurrentVersion\Explorer\TypedPaths`,
+	`Software\Microsoft\Windows\CurrentVersion\Explorer\WordWheelQuery`,
+	`Software\Microsoft\Windows\CurrentVersion\Explorer\RecentDocs`,
+	`Software\Microsoft\Windows\CurrentVersion\Explorer\ComDlg32\OpenSavePidlMRU`,
+	`Software\Microsoft\Windows\CurrentVersion\Explorer\ComDlg32\LastVisitedPidlMRU`,
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package cleaner
+
+// cleanClipboardHistory is a no-op outside Windows.
+func cleanClipboardHistory(opts CleanOptions) CleanResult {
+	return CleanResult{}
+}
+
+// cleanExplorerMRU is a no-op outside Windows.
+func cleanExplorerMRU(opts CleanOptions) CleanResult {
+	return CleanResult{}
+}
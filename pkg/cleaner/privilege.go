@@ -0,0 +1,44 @@
+package cleaner
+
+import "syscleaner/pkg/admin"
+
+// adminCategories lists categories that cannot do anything useful without
+// administrator rights, with the reason shown to the user.
+var adminCategories = map[Category]string{
+	CategoryWindowsTemp:           `C:\Windows\Temp is only writable by administrators`,
+	CategoryWindowsUpdateCache:    "the Windows Update download cache is owned by SYSTEM",
+	CategoryWindowsInstallerCache: "the Windows Installer patch cache is owned by SYSTEM",
+	CategoryPrefetch:              "the Prefetch folder is only writable by administrators",
+	CategoryFontCache:             "the font cache belongs to the LocalService account",
+	CategoryWindowsLogFiles:       "Windows log folders are only writable by administrators",
+	CategoryEventLogs:             "clearing event logs requires administrator rights",
+	CategoryDeliveryOptimization:  "the Delivery Optimization cache is owned by NetworkService",
+	CategoryFontIconRebuild:       "stopping the Font Cache service requires administrator rights",
+}
+
+// PlanForPrivileges returns opts with the categories that need
+// administrator rights removed when elevated is false, along with what was
+// removed and why. Callers show the skips up front and run the rest, rather
+// than letting each category fail on its own.
+func PlanForPrivileges(opts CleanOptions, elevated bool) (CleanOptions, []admin.Skip) {
+	if elevated {
+		return opts, nil
+	}
+	var skips []admin.Skip
+	drop := func(enabled *bool, c Category) {
+		if *enabled {
+			*enabled = false
+			skips = append(skips, admin.Skip{Item: string(c), Reason: adminCategories[c]})
+		}
+	}
+	drop(&opts.WindowsTemp, CategoryWindowsTemp)
+	drop(&opts.WindowsUpdate, CategoryWindowsUpdateCache)
+	drop(&opts.WindowsInstaller, CategoryWindowsInstallerCache)
+	drop(&opts.Prefetch, CategoryPrefetch)
+	drop(&opts.FontCache, CategoryFontCache)
+	drop(&opts.WindowsLogs, CategoryWindowsLogFiles)
+	drop(&opts.EventLogs, CategoryEventLogs)
+	drop(&opts.DeliveryOptimization, CategoryDeliveryOptimization)
+	drop(&opts.FontIconRebuild, CategoryFontIconRebuild)
+	return opts, skips
+}
@@ -0,0 +1,28 @@
+package cleaner
+
+import "testing"
+
+func TestPlanForPrivileges(t *testing.T) {
+	opts := CleanOptions{WindowsTemp: true, UserTemp: true, EventLogs: true, ChromeCache: true}
+
+	got, skips := PlanForPrivileges(opts, true)
+	if len(skips) != 0 || !got.WindowsTemp || !got.EventLogs {
+		t.Fatalf("elevated plan should keep everything, got %+v skips %v", got, skips)
+	}
+
+	got, skips = PlanForPrivileges(opts, false)
+	if got.WindowsTemp || got.EventLogs {
+		t.Error("expected admin-only categories to be dropped")
+	}
+	if !got.UserTemp || !got.ChromeCache {
+		t.Error("expected per-user categories to be kept")
+	}
+	if len(skips) != 2 {
+		t.Fatalf("expected 2 skips, got %v", skips)
+	}
+	for _, s := range skips {
+		if s.Reason == "" {
+			t.Errorf("skip %q has no reason", s.Item)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package cleaner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"syscleaner/pkg/admin"
+)
+
+// QuarantineEntry describes a file moved to quarantine instead of deleted.
+type QuarantineEntry struct {
+	ID           string    `json:"id"`
+	OriginalPath string    `json:"original_path"`
+	StoredPath   string    `json:"stored_path"`
+	Size         int64     `json:"size"`
+	Time         time.Time `json:"time"`
+}
+
+// RestoreRecord is logged every time a quarantined file is restored. The
+// restore history drives exclusion suggestions.
+type RestoreRecord struct {
+	OriginalPath string    `json:"original_path"`
+	Time         time.Time `json:"time"`
+}
+
+const (
+	quarantineManifest = "manifest.jsonl"
+	restoreLog         = "restores.jsonl"
+)
+
+var (
+	quarantineMu  sync.Mutex
+	quarantineDir string
+	quarantineSeq uint64
+)
+
+// DefaultQuarantineDir returns <user config dir>/SysCleaner/quarantine.
+func DefaultQuarantineDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "SysCleaner", "quarantine")
+}
+
+// SetQuarantineDir makes the cleaner move files into dir instead of deleting
+// them. An empty dir restores normal deletion.
+func SetQuarantineDir(dir string) {
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+	quarantineDir = dir
+}
+
+func currentQuarantineDir() string {
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+	return quarantineDir
+}
+
+// deleteFile removes path, or moves it to quarantine when one is configured.
+func deleteFile(path string, size int64) error {
+	dir := currentQuarantineDir()
+	if dir == "" {
+		return removeWithTimeout(path, fileTimeout)
+	}
+	return quarantineFile(dir, path, size)
+}
+
+func quarantineFile(dir, path string, size int64) error {
+	filesDir := filepath.Join(dir, "files")
+	if err := os.MkdirAll(filesDir, 0o755); err != nil {
+		return fmt.Errorf("creating quarantine directory: %w", err)
+	}
+
+	id := strconv.FormatInt(time.Now().UnixNano(), 36) + "-" +
+		strconv.FormatUint(atomic.AddUint64(&quarantineSeq, 1), 36)
+	stored := filepath.Join(filesDir, id+filepath.Ext(path))
+	if err := moveFile(path, stored); err != nil {
+		return err
+	}
+
+	entry := QuarantineEntry{ID: id, OriginalPath: path, StoredPath: stored, Size: size, Time: time.Now()}
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+	return appendJSONLine(filepath.Join(dir, quarantineManifest), entry)
+}
+
+// moveFile renames src to dst, falling back to copy-and-delete when they are
+// on different volumes.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		in.Close()
+		return err
+	}
+	_, copyErr := io.Copy(out, in)
+	in.Close()
+	if closeErr := out.Close(); copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		os.Remove(dst)
+		return copyErr
+	}
+	if err := os.Remove(src); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// ListQuarantine returns the files currently held in the quarantine at dir,
+// newest first.
+func ListQuarantine(dir string) ([]QuarantineEntry, error) {
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+	entries, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	return entries, nil
+}
+
+// RestoreQuarantined moves a quarantined file back to its original location
+// and records the restore so future cleans can learn from it.
+func RestoreQuarantined(dir, id string) (QuarantineEntry, error) {
+	if err := admin.RequireWriteAccess("restoring from quarantine"); err != nil {
+		return QuarantineEntry{}, err
+	}
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+
+	entries, err := readManifest(dir)
+	if err != nil {
+		return QuarantineEntry{}, err
+	}
+	idx := -1
+	for i, e := range entries {
+		if e.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return QuarantineEntry{}, fmt.Errorf("no quarantined file with ID %s", id)
+	}
+	entry := entries[idx]
+
+	if _, err := os.Stat(entry.OriginalPath); err == nil {
+		return entry, fmt.Errorf("%s already exists; not overwriting", entry.OriginalPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0o755); err != nil {
+		return entry, fmt.Errorf("recreating %s: %w", filepath.Dir(entry.OriginalPath), err)
+	}
+	if err := moveFile(entry.StoredPath, entry.OriginalPath); err != nil {
+		return entry, fmt.Errorf("restoring %s: %w", entry.OriginalPath, err)
+	}
+
+	entries = append(entries[:idx], entries[idx+1:]...)
+	if err := writeManifest(dir, entries); err != nil {
+		return entry, err
+	}
+	rec := RestoreRecord{OriginalPath: entry.OriginalPath, Time: time.Now()}
+	if err := appendJSONLine(filepath.Join(dir, restoreLog), rec); err != nil {
+		return entry, fmt.Errorf("recording restore: %w", err)
+	}
+	return entry, nil
+}
+
+// PurgeQuarantine permanently deletes quarantined files older than maxAge
+// and returns how many were removed and the space they used.
+func PurgeQuarantine(dir string, maxAge time.Duration) (int, int64, error) {
+	if err := admin.RequireWriteAccess("purging quarantine"); err != nil {
+		return 0, 0, err
+	}
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+
+	entries, err := readManifest(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	var kept []QuarantineEntry
+	var count int
+	var freed int64
+	for _, e := range entries {
+		if time.Since(e.Time) < maxAge {
+			kept = append(kept, e)
+			continue
+		}
+		if err := os.Remove(e.StoredPath); err != nil && !os.IsNotExist(err) {
+			kept = append(kept, e)
+			continue
+		}
+		count++
+		freed += e.Size
+	}
+	return count, freed, writeManifest(dir, kept)
+}
+
+// RestoreHistory returns every restore recorded in the quarantine at dir.
+func RestoreHistory(dir string) ([]RestoreRecord, error) {
+	var recs []RestoreRecord
+	err := readJSONLines(filepath.Join(dir, restoreLog), func(line []byte) error {
+		var r RestoreRecord
+		if err := json.Unmarshal(line, &r); err != nil {
+			return err
+		}
+		recs = append(recs, r)
+		return nil
+	})
+	return recs, err
+}
+
+func readManifest(dir string) ([]QuarantineEntry, error) {
+	var entries []QuarantineEntry
+	err := readJSONLines(filepath.Join(dir, quarantineManifest), func(line []byte) error {
+		var e QuarantineEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+func writeManifest(dir string, entries []QuarantineEntry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(dir, quarantineManifest), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("writing quarantine manifest: %w", err)
+	}
+	return nil
+}
+
+func appendJSONLine(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+func readJSONLines(path string, fn func([]byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+		}
+	}
+	return scanner.Err()
+}
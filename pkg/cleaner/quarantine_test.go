@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuarantine_CleanAndRestore(t *testing.T) {
+	qdir := t.TempDir()
+	SetQuarantineDir(qdir)
+	t.Cleanup(func() { SetQuarantineDir("") })
+
+	dir := t.TempDir()
+	paths := createTempFiles(t, dir, 2)
+
+	result := cleanDirectory(dir, 0, false)
+	if result.FilesDeleted != 2 {
+		t.Fatalf("expected 2 files quarantined, got %d", result.FilesDeleted)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be moved to quarantine", p)
+		}
+	}
+
+	entries, err := ListQuarantine(qdir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 quarantine entries, got %d (err %v)", len(entries), err)
+	}
+
+	restored, err := RestoreQuarantined(qdir, entries[0].ID)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if _, err := os.Stat(restored.OriginalPath); err != nil {
+		t.Errorf("restored file missing: %v", err)
+	}
+
+	history, err := RestoreHistory(qdir)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("expected 1 restore record, got %d (err %v)", len(history), err)
+	}
+	if remaining, _ := ListQuarantine(qdir); len(remaining) != 1 {
+		t.Errorf("expected 1 entry left in quarantine, got %d", len(remaining))
+	}
+}
+
+func TestCleanDirectory_SkipsExclusions(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.cfg")
+	if err := os.WriteFile(keep, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	createTempFiles(t, dir, 1)
+
+	SetExclusions([]string{filepath.Join(dir, "*.cfg")})
+	t.Cleanup(func() { SetExclusions(nil) })
+
+	result := cleanDirectory(dir, 0, false)
+	if result.FilesDeleted != 1 {
+		t.Errorf("expected 1 file deleted, got %d", result.FilesDeleted)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("excluded file was deleted: %v", err)
+	}
+}
+
+func TestSuggestExclusions(t *testing.T) {
+	dir := filepath.Join("C:", "Users", "me", "AppData", "Local", "Tool")
+	now := time.Now()
+	history := []RestoreRecord{
+		{OriginalPath: filepath.Join(dir, "a.db"), Time: now},
+		{OriginalPath: filepath.Join(dir, "b.db"), Time: now},
+		{OriginalPath: filepath.Join("C:", "Temp", "session.lock"), Time: now},
+		{OriginalPath: filepath.Join("C:", "Temp", "session.lock"), Time: now},
+		{OriginalPath: filepath.Join("C:", "Temp", "once.tmp"), Time: now},
+	}
+
+	got := SuggestExclusions(history, nil)
+	want := map[string]bool{
+		filepath.Join(dir, "*.db"):                  true,
+		filepath.Join("C:", "Temp", "session.lock"): true,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d suggestions, got %+v", len(want), got)
+	}
+	for _, s := range got {
+		if !want[s.Pattern] {
+			t.Errorf("unexpected suggestion %q", s.Pattern)
+		}
+	}
+
+	// Patterns already excluded are not suggested again.
+	got = SuggestExclusions(history, []string{filepath.Join(dir, "*.db")})
+	if len(got) != 1 {
+		t.Errorf("expected 1 suggestion with existing exclusion, got %+v", got)
+	}
+}
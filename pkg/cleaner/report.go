@@ -0,0 +1,252 @@
+package cleaner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/logger"
+)
+
+// ReportFormat selects the output format for CleanResult.Export.
+type ReportFormat string
+
+const (
+	ReportJSON ReportFormat = "json"
+	ReportCSV  ReportFormat = "csv"
+	ReportHTML ReportFormat = "html"
+)
+
+// ReportFormatFromPath infers the report format from a file extension
+// (.json, .csv, .html/.htm).
+func ReportFormatFromPath(path string) (ReportFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReportJSON, nil
+	case ".csv":
+		return ReportCSV, nil
+	case ".html", ".htm":
+		return ReportHTML, nil
+	default:
+		return "", fmt.Errorf("unsupported report extension %q (use .json, .csv, or .html)", filepath.Ext(path))
+	}
+}
+
+// CategoryReport is the per-category section of a clean report.
+type CategoryReport struct {
+	Name         string   `json:"name"`
+	FilesDeleted int64    `json:"files_deleted"`
+	SkippedFiles int64    `json:"skipped_files"`
+	SpaceFreed   int64    `json:"space_freed_bytes"`
+	Errors       []string `json:"errors"`
+}
+
+// Report is the serializable form of a CleanResult.
+type Report struct {
+	GeneratedAt     time.Time        `json:"generated_at"`
+	OperationID     string           `json:"operation_id,omitempty"`
+	DryRun          bool             `json:"dry_run"`
+	FilesDeleted    int64            `json:"files_deleted"`
+	SkippedFiles    int64            `json:"skipped_files"`
+	LockedFiles     int64            `json:"locked_files"`
+	PermissionFiles int64            `json:"permission_files"`
+	SpaceFreed      int64            `json:"space_freed_bytes"`
+	SpaceFreedHuman string           `json:"space_freed"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Errors          []string         `json:"errors"`
+	Categories      []CategoryReport `json:"categories"`
+}
+
+// Report builds the serializable report for this result. Categories are
+// sorted by space freed, largest first.
+func (r CleanResult) Report() Report {
+	rep := Report{
+		GeneratedAt:     time.Now(),
+		OperationID:     r.OperationID,
+		DryRun:          r.DryRun,
+		FilesDeleted:    r.FilesDeleted,
+		SkippedFiles:    r.SkippedFiles,
+		LockedFiles:     r.LockedFiles,
+		PermissionFiles: r.PermissionFiles,
+		SpaceFreed:      r.SpaceFreed,
+		SpaceFreedHuman: FormatBytes(r.SpaceFreed),
+		DurationSeconds: r.Duration.Seconds(),
+		Errors:          errorStrings(r.Errors),
+		Categories:      []CategoryReport{},
+	}
+
+	for _, name := range r.CategoriesBySpace() {
+		c := r.Categories[name]
+		rep.Categories = append(rep.Categories, CategoryReport{
+			Name:         string(name),
+			FilesDeleted: c.FilesDeleted,
+			SkippedFiles: c.SkippedFiles,
+			SpaceFreed:   c.SpaceFreed,
+			Errors:       errorStrings(c.Errors),
+		})
+	}
+	return rep
+}
+
+func errorStrings(errs []error) []string {
+	out := make([]string, 0, len(errs))
+	for _, err := range errs {
+		out = append(out, logger.Redact(err.Error()))
+	}
+	return out
+}
+
+// Export writes the result as a JSON, CSV, or HTML report to path.
+func (r CleanResult) Export(format ReportFormat, path string) error {
+	switch format {
+	case ReportJSON, ReportCSV, ReportHTML:
+	default:
+		// Checked before the file is created so an unknown format leaves
+		// whatever is at path untouched.
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating report file: %w", err)
+	}
+	defer f.Close()
+
+	rep := r.Report()
+	switch format {
+	case ReportJSON:
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rep)
+	case ReportCSV:
+		err = writeCSVReport(f, rep)
+	case ReportHTML:
+		err = htmlReportTemplate.Execute(f, rep)
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s report: %w", format, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s report: %w", format, err)
+	}
+	return nil
+}
+
+// maxRunReports is how many run reports SaveRunReport keeps.
+const maxRunReports = 10
+
+// DefaultReportsDir returns <user config dir>/SysCleaner/reports, where a
+// JSON report of each clean is kept for support bundles.
+func DefaultReportsDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "SysCleaner", "reports")
+}
+
+// SaveRunReport writes the result as a timestamped JSON report in dir and
+// removes all but the newest maxRunReports reports.
+func SaveRunReport(dir string, r CleanResult) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating reports directory: %w", err)
+	}
+	name := "clean-" + time.Now().Format("20060102-150405") + ".json"
+	if err := r.Export(ReportJSON, filepath.Join(dir, name)); err != nil {
+		return err
+	}
+
+	reports, err := ListRunReports(dir)
+	if err != nil {
+		return err
+	}
+	for len(reports) > maxRunReports {
+		_ = os.Remove(reports[len(reports)-1])
+		reports = reports[:len(reports)-1]
+	}
+	return nil
+}
+
+// ListRunReports returns the paths of the saved run reports in dir, newest
+// first.
+func ListRunReports(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "clean-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	// The timestamp in the name sorts chronologically.
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
+
+func writeCSVReport(f *os.File, rep Report) error {
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"category", "files_deleted", "skipped_files", "space_freed_bytes", "errors"})
+	for _, c := range rep.Categories {
+		_ = w.Write([]string{
+			c.Name,
+			strconv.FormatInt(c.FilesDeleted, 10),
+			strconv.FormatInt(c.SkippedFiles, 10),
+			strconv.FormatInt(c.SpaceFreed, 10),
+			strconv.Itoa(len(c.Errors)),
+		})
+	}
+	_ = w.Write([]string{
+		"TOTAL",
+		strconv.FormatInt(rep.FilesDeleted, 10),
+		strconv.FormatInt(rep.SkippedFiles, 10),
+		strconv.FormatInt(rep.SpaceFreed, 10),
+		strconv.Itoa(len(rep.Errors)),
+	})
+	w.Flush()
+	return w.Error()
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"bytes": FormatBytes,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>SysCleaner Report</title>
+<style>
+body { background: #121212; color: #e6e6e6; font-family: Segoe UI, sans-serif; margin: 2em; }
+h1 { color: #ff5500; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #37373a; padding: 6px 12px; text-align: left; }
+th { background: #2d2d30; }
+td.num { text-align: right; }
+.errors li { color: #dc1e1e; }
+</style>
+</head>
+<body>
+<h1>SysCleaner {{if .DryRun}}Analysis{{else}}Cleanup{{end}} Report</h1>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04:05"}}{{if .DryRun}} &mdash; dry run, no files were deleted{{end}}</p>
+{{if .OperationID}}<p>Operation {{.OperationID}}</p>
+{{end}}
+<table>
+<tr><th>Files {{if .DryRun}}found{{else}}deleted{{end}}</th><td class="num">{{.FilesDeleted}}</td></tr>
+<tr><th>Space {{if .DryRun}}reclaimable{{else}}freed{{end}}</th><td class="num">{{.SpaceFreedHuman}}</td></tr>
+<tr><th>Skipped (in use)</th><td class="num">{{.LockedFiles}}</td></tr>
+<tr><th>Permission errors</th><td class="num">{{.PermissionFiles}}</td></tr>
+<tr><th>Duration</th><td class="num">{{printf "%.1f" .DurationSeconds}}s</td></tr>
+</table>
+<h2>Categories</h2>
+<table>
+<tr><th>Category</th><th>Files</th><th>Skipped</th><th>Space</th><th>Errors</th></tr>
+{{range .Categories}}<tr><td>{{.Name}}</td><td class="num">{{.FilesDeleted}}</td><td class="num">{{.SkippedFiles}}</td><td class="num">{{bytes .SpaceFreed}}</td><td class="num">{{len .Errors}}</td></tr>
+{{end}}</table>
+{{if .Errors}}<h2>Errors</h2>
+<ul class="errors">
+{{range .Errors}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,166 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleResult() CleanResult {
+	return CleanResult{
+		FilesDeleted: 7,
+		SkippedFiles: 1,
+		SpaceFreed:   3 * 1024 * 1024,
+		LockedFiles:  1,
+		Duration:     1500 * time.Millisecond,
+		Errors:       []error{errors.New("boom")},
+		Categories: map[Category]CategoryResult{
+			CategoryWindowsTemp: {FilesDeleted: 2, SpaceFreed: 1024 * 1024},
+			CategoryChromeCache: {FilesDeleted: 5, SkippedFiles: 1, SpaceFreed: 2 * 1024 * 1024, Errors: []error{errors.New("boom")}},
+		},
+	}
+}
+
+func TestReportFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected ReportFormat
+		wantErr  bool
+	}{
+		{"out.json", ReportJSON, false},
+		{"OUT.CSV", ReportCSV, false},
+		{"report.html", ReportHTML, false},
+		{"report.htm", ReportHTML, false},
+		{"report.txt", "", true},
+	}
+	for _, tc := range tests {
+		got, err := ReportFormatFromPath(tc.path)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ReportFormatFromPath(%q) error = %v, wantErr %v", tc.path, err, tc.wantErr)
+		}
+		if got != tc.expected {
+			t.Errorf("ReportFormatFromPath(%q) = %q, want %q", tc.path, got, tc.expected)
+		}
+	}
+}
+
+func TestReport_CategoriesSortedBySpace(t *testing.T) {
+	rep := sampleResult().Report()
+
+	if len(rep.Categories) != 2 {
+		t.Fatalf("expected 2 categories, got %d", len(rep.Categories))
+	}
+	if rep.Categories[0].Name != "Chrome Cache" {
+		t.Errorf("expected largest category first, got %s", rep.Categories[0].Name)
+	}
+	if rep.SpaceFreedHuman != "3.00 MB" {
+		t.Errorf("expected SpaceFreedHuman=3.00 MB, got %s", rep.SpaceFreedHuman)
+	}
+}
+
+func TestExport_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := sampleResult().Export(ReportJSON, path); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	var rep Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if rep.FilesDeleted != 7 {
+		t.Errorf("expected FilesDeleted=7, got %d", rep.FilesDeleted)
+	}
+	if len(rep.Errors) != 1 || rep.Errors[0] != "boom" {
+		t.Errorf("expected errors to round-trip, got %v", rep.Errors)
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	if err := sampleResult().Export(ReportCSV, path); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header + 2 categories + total, got %d lines:\n%s", len(lines), data)
+	}
+	if !strings.HasPrefix(lines[3], "TOTAL,7,1,3145728,1") {
+		t.Errorf("unexpected total row: %s", lines[3])
+	}
+}
+
+func TestExport_HTMLEscapesErrors(t *testing.T) {
+	result := sampleResult()
+	result.Errors = []error{errors.New("<script>alert(1)</script>")}
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := result.Export(ReportHTML, path); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	if strings.Contains(html, "<script>") {
+		t.Error("expected error text to be HTML-escaped")
+	}
+	if !strings.Contains(html, "Chrome Cache") {
+		t.Error("expected per-category breakdown in HTML report")
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.xml")
+	if err := sampleResult().Export(ReportFormat("xml"), path); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unsupported format created %s", path)
+	}
+
+	existing := filepath.Join(dir, "existing.xml")
+	os.WriteFile(existing, []byte("<keep/>"), 0o644)
+	if err := sampleResult().Export(ReportFormat("xml"), existing); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "<keep/>" {
+		t.Errorf("unsupported format changed %s to %q", existing, data)
+	}
+}
+
+func TestSaveRunReport_KeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxRunReports+2; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("clean-20240101-%06d.json", i))
+		os.WriteFile(name, []byte("{}"), 0o644)
+	}
+	if err := SaveRunReport(dir, sampleResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := ListRunReports(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != maxRunReports {
+		t.Fatalf("expected %d reports, got %d", maxRunReports, len(reports))
+	}
+	// The report just saved has today's timestamp and sorts first.
+	data, _ := os.ReadFile(reports[0])
+	var rep Report
+	if err := json.Unmarshal(data, &rep); err != nil || rep.FilesDeleted != 7 {
+		t.Errorf("expected the new report to be kept first, got %s", reports[0])
+	}
+}
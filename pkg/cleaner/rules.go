@@ -0,0 +1,283 @@
+package cleaner
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RulePack is a set of cleaning definitions loaded from disk, in the spirit
+// of winapp2.ini, so new applications can be supported without a rebuild.
+type RulePack struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Rule describes how to detect and clean one application.
+type Rule struct {
+	ID  string `json:"id"`
+	App string `json:"app"`
+
+	// Detect lists paths and registry keys (e.g.
+	// "HKCU\Software\Vendor\App"); the rule applies if any one exists.
+	// A rule with no detection entries always applies.
+	Detect RuleDetect `json:"detect"`
+
+	Patterns []RulePattern `json:"patterns"`
+
+	// Exclusions are path patterns this rule never deletes, matched like
+	// the global cleaner exclusions.
+	Exclusions []string `json:"exclusions"`
+}
+
+// RuleDetect lists the conditions under which a rule applies.
+type RuleDetect struct {
+	Paths        []string `json:"paths"`
+	RegistryKeys []string `json:"registry_keys"`
+}
+
+// RulePattern selects files below a directory. Dir may contain %VAR%
+// environment references. Glob is matched against file names and defaults
+// to "*".
+type RulePattern struct {
+	Dir        string `json:"dir"`
+	Glob       string `json:"glob"`
+	Recursive  bool   `json:"recursive"`
+	MaxAgeDays int    `json:"max_age_days"`
+}
+
+// signedRulePack is the on-disk envelope. Signature is the base64 Ed25519
+// signature of the raw bytes of Pack exactly as they appear in the file.
+type signedRulePack struct {
+	Pack      json.RawMessage `json:"pack"`
+	Signature string          `json:"signature"`
+}
+
+var (
+	rulesMu     sync.RWMutex
+	loadedRules = make(map[string]Rule)
+)
+
+// DefaultRulesDir returns <user config dir>/SysCleaner/rules.
+func DefaultRulesDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "SysCleaner", "rules")
+}
+
+// ParseRulePack verifies a signed rule pack envelope against the trusted
+// keys and decodes it. When allowUnsigned is set, a pack without a
+// signature is accepted; a pack with a bad signature never is.
+func ParseRulePack(data []byte, trusted []ed25519.PublicKey, allowUnsigned bool) (*RulePack, error) {
+	var env signedRulePack
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing rule pack: %w", err)
+	}
+	if len(env.Pack) == 0 {
+		return nil, fmt.Errorf("rule pack has no \"pack\" section")
+	}
+
+	if env.Signature == "" {
+		if !allowUnsigned {
+			return nil, fmt.Errorf("rule pack is not signed")
+		}
+	} else {
+		sig, err := base64.StdEncoding.DecodeString(env.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("decoding rule pack signature: %w", err)
+		}
+		verified := false
+		for _, key := range trusted {
+			if ed25519.Verify(key, env.Pack, sig) {
+				verified = true
+				break
+			}
+		}
+		if !verified {
+			return nil, fmt.Errorf("rule pack signature does not match any trusted key")
+		}
+	}
+
+	var pack RulePack
+	if err := json.Unmarshal(env.Pack, &pack); err != nil {
+		return nil, fmt.Errorf("parsing rule pack contents: %w", err)
+	}
+	for i, r := range pack.Rules {
+		if r.ID == "" || r.App == "" {
+			return nil, fmt.Errorf("rule %d in pack %q needs an id and app", i, pack.Name)
+		}
+		for _, p := range r.Patterns {
+			if p.Dir == "" {
+				return nil, fmt.Errorf("rule %s has a pattern with no dir", r.ID)
+			}
+			if _, err := filepath.Match(p.Glob, ""); err != nil {
+				return nil, fmt.Errorf("rule %s: bad glob %q: %w", r.ID, p.Glob, err)
+			}
+		}
+	}
+	return &pack, nil
+}
+
+// SignRulePack wraps pack in a signed envelope. It is used by tooling that
+// publishes rule packs. The envelope is written by hand because re-encoding
+// would reformat pack and invalidate the signature.
+func SignRulePack(pack []byte, key ed25519.PrivateKey) ([]byte, error) {
+	if !json.Valid(pack) {
+		return nil, fmt.Errorf("rule pack is not valid JSON")
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, pack))
+	out := []byte("{\n  \"pack\": ")
+	out = append(out, pack...)
+	out = append(out, ",\n  \"signature\": \""+sig+"\"\n}\n"...)
+	return out, nil
+}
+
+// LoadRulePacks reads every *.json rule pack in dir. Packs that fail to
+// parse or verify are skipped and reported in the returned errors.
+func LoadRulePacks(dir string, trusted []ed25519.PublicKey, allowUnsigned bool) ([]*RulePack, []error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	sort.Strings(files)
+
+	var packs []*RulePack
+	var errs []error
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pack, err := ParseRulePack(data, trusted, allowUnsigned)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(f), err))
+			continue
+		}
+		packs = append(packs, pack)
+	}
+	return packs, errs
+}
+
+// SetRulePacks makes the rules in packs available to PerformClean via
+// CleanOptions.Rules. Later packs override earlier rules with the same ID.
+func SetRulePacks(packs []*RulePack) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	loadedRules = make(map[string]Rule)
+	for _, p := range packs {
+		for _, r := range p.Rules {
+			loadedRules[r.ID] = r
+		}
+	}
+	InvalidateEstimates()
+}
+
+// Rules returns the loaded rules sorted by ID.
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	out := make([]Rule, 0, len(loadedRules))
+	for _, r := range loadedRules {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func lookupRule(id string) (Rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	r, ok := loadedRules[id]
+	return r, ok
+}
+
+// Detected reports whether the rule's application is present.
+func (r Rule) Detected() bool {
+	if len(r.Detect.Paths) == 0 && len(r.Detect.RegistryKeys) == 0 {
+		return true
+	}
+	for _, p := range r.Detect.Paths {
+		if _, err := os.Stat(expandRulePath(p)); err == nil {
+			return true
+		}
+	}
+	for _, k := range r.Detect.RegistryKeys {
+		if registryKeyExists(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleTask returns the cleaning function for a rule.
+func ruleTask(r Rule) func(CleanOptions) CleanResult {
+	return func(opts CleanOptions) CleanResult {
+		result := CleanResult{}
+		if !r.Detected() {
+			return result
+		}
+		excl := normalizePatterns(r.Exclusions)
+		for _, p := range r.Patterns {
+			dir := expandRulePath(p.Dir)
+			if ok, vol := opts.Volumes.allows(opts.category, dir); !ok {
+				log.Printf("[SysCleaner] Skipping %s: %s is not allowed to clean volume %s", dir, opts.category, vol)
+				continue
+			}
+			glob := strings.ToLower(p.Glob)
+			if glob == "" {
+				glob = "*"
+			}
+			recursive := p.Recursive
+			match := func(path string, d os.DirEntry) bool {
+				if !recursive && filepath.Dir(path) != filepath.Clean(dir) {
+					return false
+				}
+				if ok, _ := filepath.Match(glob, strings.ToLower(d.Name())); !ok {
+					return false
+				}
+				return !matchPatterns(excl, path)
+			}
+			maxAge := time.Duration(p.MaxAgeDays) * 24 * time.Hour
+			result.merge(cleanDirectoryMatching(dir, maxAge, opts.DryRun, match, opts.throttle))
+		}
+		return result
+	}
+}
+
+// expandRulePath expands Windows-style %VAR% references using the
+// environment. Unknown variables are left as-is.
+func expandRulePath(p string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(p, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(p[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		name := p[start+1 : start+1+end]
+		b.WriteString(p[:start])
+		if val, ok := os.LookupEnv(name); ok && name != "" {
+			b.WriteString(val)
+		} else {
+			b.WriteString(p[start : start+end+2])
+		}
+		p = p[start+end+2:]
+	}
+	b.WriteString(p)
+	return b.String()
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package cleaner
+
+// registryKeyExists always reports false outside Windows.
+func registryKeyExists(path string) bool {
+	return false
+}
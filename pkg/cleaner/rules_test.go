@@ -0,0 +1,74 @@
+package cleaner
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRulePack_Signatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	pack := []byte(`{"name":"test","version":"1","rules":[{"id":"app-logs","app":"App Logs","patterns":[{"dir":"/tmp","glob":"*.log"}]}]}`)
+	signed, err := SignRulePack(pack, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseRulePack(signed, []ed25519.PublicKey{pub}, false); err != nil {
+		t.Errorf("expected signed pack to verify: %v", err)
+	}
+	if _, err := ParseRulePack(signed, []ed25519.PublicKey{otherPub}, true); err == nil {
+		t.Error("expected pack signed by an untrusted key to be rejected")
+	}
+
+	unsigned := []byte(`{"pack":` + string(pack) + `}`)
+	if _, err := ParseRulePack(unsigned, nil, false); err == nil {
+		t.Error("expected unsigned pack to be rejected")
+	}
+	if _, err := ParseRulePack(unsigned, nil, true); err != nil {
+		t.Errorf("expected unsigned pack to load when allowed: %v", err)
+	}
+}
+
+func TestRuleTask_CleansMatchingFiles(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.log", "b.log", "keep.txt", "important.log", filepath.Join("sub", "c.log")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("SYSCLEANER_RULE_TEST", dir)
+	SetRulePacks([]*RulePack{{Name: "test", Rules: []Rule{{
+		ID:         "test-logs",
+		App:        "Test Logs",
+		Detect:     RuleDetect{Paths: []string{"%SYSCLEANER_RULE_TEST%"}},
+		Patterns:   []RulePattern{{Dir: "%SYSCLEANER_RULE_TEST%", Glob: "*.log"}},
+		Exclusions: []string{filepath.Join(dir, "important.log")},
+	}}}})
+	t.Cleanup(func() { SetRulePacks(nil) })
+
+	result := PerformClean(CleanOptions{Rules: []string{"test-logs"}})
+	if result.FilesDeleted != 2 {
+		t.Errorf("expected 2 files deleted, got %d", result.FilesDeleted)
+	}
+	if _, ok := result.Categories[Category("Test Logs")]; !ok {
+		t.Error("expected rule to report under its app name")
+	}
+	for _, name := range []string{"keep.txt", "important.log", filepath.Join("sub", "c.log")} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s should not have been deleted", name)
+		}
+	}
+}
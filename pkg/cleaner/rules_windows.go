@@ -0,0 +1,34 @@
+//go:build windows
+
+package cleaner
+
+import (
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// registryKeyExists reports whether a key such as "HKCU\Software\Vendor"
+// exists. Both short (HKCU) and long (HKEY_CURRENT_USER) root names work.
+func registryKeyExists(path string) bool {
+	rootName, sub, _ := strings.Cut(path, `\`)
+	var root registry.Key
+	switch strings.ToUpper(rootName) {
+	case "HKCU", "HKEY_CURRENT_USER":
+		root = registry.CURRENT_USER
+	case "HKLM", "HKEY_LOCAL_MACHINE":
+		root = registry.LOCAL_MACHINE
+	case "HKCR", "HKEY_CLASSES_ROOT":
+		root = registry.CLASSES_ROOT
+	case "HKU", "HKEY_USERS":
+		root = registry.USERS
+	default:
+		return false
+	}
+	k, err := registry.OpenKey(root, sub, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	k.Close()
+	return true
+}
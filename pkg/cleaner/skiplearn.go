@@ -0,0 +1,79 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// learnThreshold is how many restores it takes before a path or pattern is
+// suggested as an exclusion.
+const learnThreshold = 2
+
+// ExclusionSuggestion is a pattern proposed from the restore history.
+type ExclusionSuggestion struct {
+	Pattern  string
+	Restores int
+	Paths    []string
+}
+
+// SuggestExclusions turns a restore history into exclusion suggestions. A
+// file restored repeatedly is suggested by exact path; several distinct
+// files with the same extension restored from one directory are collapsed
+// into a "dir\*.ext" pattern. Paths already covered by existing patterns
+// are ignored.
+func SuggestExclusions(history []RestoreRecord, existing []string) []ExclusionSuggestion {
+	type group struct {
+		pattern  string
+		restores int
+		paths    map[string]bool
+	}
+	byPattern := make(map[string]*group)
+	byPath := make(map[string]int)
+	covered := normalizePatterns(existing)
+
+	for _, rec := range history {
+		path := filepath.Clean(rec.OriginalPath)
+		if matchPatterns(covered, path) {
+			continue
+		}
+		byPath[strings.ToLower(path)]++
+
+		pattern := filepath.Join(filepath.Dir(path), "*"+filepath.Ext(path))
+		key := strings.ToLower(pattern)
+		g := byPattern[key]
+		if g == nil {
+			g = &group{pattern: pattern, paths: make(map[string]bool)}
+			byPattern[key] = g
+		}
+		g.restores++
+		g.paths[path] = true
+	}
+
+	var out []ExclusionSuggestion
+	for _, g := range byPattern {
+		paths := make([]string, 0, len(g.paths))
+		for p := range g.paths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+
+		if len(paths) >= learnThreshold {
+			out = append(out, ExclusionSuggestion{Pattern: g.pattern, Restores: g.restores, Paths: paths})
+			continue
+		}
+		for _, p := range paths {
+			if n := byPath[strings.ToLower(p)]; n >= learnThreshold {
+				out = append(out, ExclusionSuggestion{Pattern: p, Restores: n, Paths: []string{p}})
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Restores != out[j].Restores {
+			return out[i].Restores > out[j].Restores
+		}
+		return out[i].Pattern < out[j].Pattern
+	})
+	return out
+}
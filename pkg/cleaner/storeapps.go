@@ -0,0 +1,121 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// StorePackageFilter selects which Microsoft Store packages have their
+// caches cleaned. Patterns use filepath.Match syntax and match either the
+// full package folder name ("Microsoft.MinecraftUWP_8wekyb3d8bbwe") or the
+// name without the publisher suffix ("Microsoft.MinecraftUWP").
+type StorePackageFilter struct {
+	// Allow, when non-empty, limits cleaning to matching packages.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists packages that are never cleaned, even if allowed.
+	Deny []string `json:"deny,omitempty"`
+}
+
+var (
+	storeFilterMu sync.RWMutex
+	storeFilter   StorePackageFilter
+)
+
+// SetStorePackageFilter replaces the allow/deny list used by the Store App
+// Cache category.
+func SetStorePackageFilter(f StorePackageFilter) {
+	storeFilterMu.Lock()
+	defer storeFilterMu.Unlock()
+	storeFilter = f
+	InvalidateEstimates()
+}
+
+func currentStoreFilter() StorePackageFilter {
+	storeFilterMu.RLock()
+	defer storeFilterMu.RUnlock()
+	return storeFilter
+}
+
+// Allows reports whether the package folder name passes the filter.
+func (f StorePackageFilter) Allows(pkg string) bool {
+	if matchPackage(f.Deny, pkg) {
+		return false
+	}
+	return len(f.Allow) == 0 || matchPackage(f.Allow, pkg)
+}
+
+func matchPackage(patterns []string, pkg string) bool {
+	full := strings.ToLower(pkg)
+	short := full
+	if i := strings.LastIndexByte(full, '_'); i > 0 {
+		short = full[:i]
+	}
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if ok, _ := filepath.Match(p, full); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, short); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// storePackagesDir returns %LOCALAPPDATA%\Packages, or "" when LOCALAPPDATA
+// is not set.
+func storePackagesDir() string {
+	localAppData := os.Getenv("LOCALAPPDATA")
+	if localAppData == "" {
+		return ""
+	}
+	return filepath.Join(localAppData, "Packages")
+}
+
+// StorePackages lists the installed Store package folders for the current
+// user.
+func StorePackages() ([]string, error) {
+	dir := storePackagesDir()
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			pkgs = append(pkgs, e.Name())
+		}
+	}
+	return pkgs, nil
+}
+
+// cleanStoreAppCache empties each Store package's AC\Temp and LocalCache
+// folders, honouring the package filter. LocalState, RoamingState and
+// Settings hold app data and are never touched.
+func cleanStoreAppCache(opts CleanOptions) CleanResult {
+	result := CleanResult{}
+	dir := storePackagesDir()
+	if runtime.GOOS != "windows" || dir == "" {
+		return result
+	}
+	pkgs, err := StorePackages()
+	if err != nil {
+		return result
+	}
+
+	filter := currentStoreFilter()
+	for _, pkg := range pkgs {
+		if !filter.Allows(pkg) {
+			continue
+		}
+		result.merge(cleanTarget(filepath.Join(dir, pkg, "AC", "Temp"), 0, opts))
+		result.merge(cleanTarget(filepath.Join(dir, pkg, "LocalCache"), 0, opts))
+	}
+	return result
+}
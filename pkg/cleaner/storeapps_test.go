@@ -0,0 +1,50 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStorePackageFilter(t *testing.T) {
+	f := StorePackageFilter{
+		Allow: []string{"Microsoft.Minecraft*", "SpotifyAB.SpotifyMusic"},
+		Deny:  []string{"microsoft.minecraftedu*"},
+	}
+	tests := []struct {
+		pkg  string
+		want bool
+	}{
+		{"Microsoft.MinecraftUWP_8wekyb3d8bbwe", true},
+		{"SpotifyAB.SpotifyMusic_zpdnekdrzrea0", true},
+		{"Microsoft.MinecraftEducationEdition_8wekyb3d8bbwe", false}, // denied
+		{"Microsoft.WindowsStore_8wekyb3d8bbwe", false},              // not allowed
+	}
+	for _, tt := range tests {
+		if got := f.Allows(tt.pkg); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.pkg, got, tt.want)
+		}
+	}
+
+	if !(StorePackageFilter{}).Allows("Anything_123") {
+		t.Error("empty filter should allow every package")
+	}
+}
+
+func TestStorePackages(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("LOCALAPPDATA", dir)
+	for _, pkg := range []string{"A.App_1", "B.App_2"} {
+		os.MkdirAll(filepath.Join(dir, "Packages", pkg, "LocalCache"), 0o755)
+	}
+	os.WriteFile(filepath.Join(dir, "Packages", "stray.txt"), nil, 0o644)
+
+	got, err := StorePackages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"A.App_1", "B.App_2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StorePackages() = %v, want %v", got, want)
+	}
+}
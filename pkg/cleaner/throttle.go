@@ -0,0 +1,106 @@
+package cleaner
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit caps how fast a clean deletes files. Zero fields are unlimited.
+type RateLimit struct {
+	FilesPerSecond int
+	BytesPerSecond int64
+}
+
+// IsZero reports whether the limit imposes no restriction.
+func (r RateLimit) IsZero() bool {
+	return r.FilesPerSecond <= 0 && r.BytesPerSecond <= 0
+}
+
+// BackgroundRateLimit is applied to cleans run with CleanOptions.Background
+// so scheduled and automatic cleans don't cause stutter in a running game.
+var BackgroundRateLimit = RateLimit{}
+
+// throttle paces deletes across all workers of a clean. Each delete reserves
+// a slot on a shared timeline and sleeps until its slot comes up, so the
+// limit holds no matter how many workers are running.
+type throttle struct {
+	limit RateLimit
+	mu    sync.Mutex
+	next  time.Time
+	sleep func(time.Duration)
+}
+
+func newThrottle(limit RateLimit) *throttle {
+	if limit.IsZero() {
+		return nil
+	}
+	return &throttle{limit: limit, sleep: time.Sleep}
+}
+
+// wait blocks until a file of the given size may be deleted. A nil throttle
+// never blocks.
+func (t *throttle) wait(size int64) {
+	if t == nil {
+		return
+	}
+	var cost time.Duration
+	if t.limit.FilesPerSecond > 0 {
+		cost = time.Second / time.Duration(t.limit.FilesPerSecond)
+	}
+	if t.limit.BytesPerSecond > 0 && size > 0 {
+		if c := time.Duration(float64(size) / float64(t.limit.BytesPerSecond) * float64(time.Second)); c > cost {
+			cost = c
+		}
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(cost)
+	t.mu.Unlock()
+
+	if delay > 0 {
+		t.sleep(delay)
+	}
+}
+
+// backgroundRefs counts the cleans running in background processing mode.
+// The mode applies to the whole process, so it is entered by the first of
+// them and left by the last, whichever cleans those are.
+var (
+	backgroundMu   sync.Mutex
+	backgroundRefs int
+)
+
+// setBackgroundMode enters or leaves background processing mode. It is a
+// variable so tests can fake it.
+var setBackgroundMode = setBackgroundModeNative
+
+// enterBackgroundIO puts the process into background processing mode, which
+// lowers its I/O and memory priority so a clean does not starve foreground
+// apps, unless another clean already has. The returned func releases it,
+// restoring normal mode once no clean needs it.
+func enterBackgroundIO() (func(), error) {
+	backgroundMu.Lock()
+	defer backgroundMu.Unlock()
+	if backgroundRefs == 0 {
+		if err := setBackgroundMode(true); err != nil {
+			return nil, err
+		}
+	}
+	backgroundRefs++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			backgroundMu.Lock()
+			defer backgroundMu.Unlock()
+			backgroundRefs--
+			if backgroundRefs == 0 {
+				_ = setBackgroundMode(false)
+			}
+		})
+	}, nil
+}
@@ -0,0 +1,87 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThrottle_PacesFilesAndBytes(t *testing.T) {
+	var sleeps []time.Duration
+	th := newThrottle(RateLimit{FilesPerSecond: 10, BytesPerSecond: 1000})
+	th.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	// Small files are limited by the file rate: 100ms each. The first
+	// delete goes immediately; the next two wait for their slots.
+	th.wait(1)
+	th.wait(1)
+	th.wait(1)
+	if len(sleeps) != 2 || sleeps[1] < 190*time.Millisecond || sleeps[1] > 200*time.Millisecond {
+		t.Errorf("expected the third small file to wait ~200ms, got %v", sleeps)
+	}
+
+	// A 2000-byte file costs 2s at 1000 B/s, more than the file rate.
+	before := th.next
+	th.wait(2000)
+	if got := th.next.Sub(before); got != 2*time.Second {
+		t.Errorf("expected a 2000-byte file to reserve 2s, got %v", got)
+	}
+}
+
+func TestThrottle_ZeroLimitNeverBlocks(t *testing.T) {
+	if th := newThrottle(RateLimit{}); th != nil {
+		t.Fatal("expected no throttle for a zero limit")
+	}
+	var th *throttle
+	th.wait(1 << 30) // must not panic or block
+}
+
+func TestEnterBackgroundIO_RefCounted(t *testing.T) {
+	orig := setBackgroundMode
+	t.Cleanup(func() { setBackgroundMode = orig })
+	var modes []bool
+	setBackgroundMode = func(on bool) error {
+		modes = append(modes, on)
+		return nil
+	}
+
+	// A scheduled clean and a GUI clean overlap; the first to finish must
+	// not end background mode for the other.
+	scheduled, err := enterBackgroundIO()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gui, _ := enterBackgroundIO()
+	scheduled()
+	scheduled() // releasing twice counts once
+	if len(modes) != 1 || !modes[0] {
+		t.Fatalf("modes = %v; background mode should be entered once and still be on", modes)
+	}
+	gui()
+	if len(modes) != 2 || modes[1] {
+		t.Errorf("modes = %v; the last clean should end background mode", modes)
+	}
+}
+
+func TestCleanDirectory_ThrottlePerClean(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.tmp", "b.tmp", "c.tmp"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var sleeps int
+	th := newThrottle(RateLimit{FilesPerSecond: 1})
+	th.sleep = func(time.Duration) { sleeps++ }
+
+	// An unthrottled clean running alongside is not paced.
+	other := t.TempDir()
+	os.WriteFile(filepath.Join(other, "d.tmp"), []byte("x"), 0644)
+	if r := cleanDirectory(other, 0, false); r.FilesDeleted != 1 || sleeps != 0 {
+		t.Fatalf("unthrottled clean deleted %d, slept %d times", r.FilesDeleted, sleeps)
+	}
+	if r := cleanDirectoryMatching(dir, 0, false, nil, th); r.FilesDeleted != 3 || sleeps != 2 {
+		t.Errorf("throttled clean deleted %d, slept %d times; want 3 and 2", r.FilesDeleted, sleeps)
+	}
+}
@@ -0,0 +1,86 @@
+package cleaner
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/sysinfo"
+)
+
+// VolumePolicy restricts which volumes a clean may touch. Paths are
+// resolved through symlinks and junctions before the check, so a temp
+// folder redirected to a scratch drive is still caught.
+type VolumePolicy struct {
+	// Never lists volumes (e.g. "D:") that are never cleaned.
+	Never []string `json:"never,omitempty"`
+	// Only restricts a category to the listed volumes. Categories not
+	// listed may clean any volume not in Never.
+	Only map[Category][]string `json:"only,omitempty"`
+}
+
+// volumeOf returns the normalized volume ("C:") holding path. It is a
+// variable so tests can run on systems without drive letters.
+var volumeOf = func(path string) string {
+	return normalizeVolume(filepath.VolumeName(path))
+}
+
+// caseSensitiveDir reports whether a directory compares names case
+// sensitively. It is a variable so tests can simulate such directories.
+var caseSensitiveDir = sysinfo.IsCaseSensitiveDir
+
+// normalizeVolume turns "d", "D:" or `D:\` into "D:".
+func normalizeVolume(v string) string {
+	v = strings.ToUpper(strings.TrimRight(strings.TrimSpace(v), `\/`))
+	if len(v) == 1 {
+		v += ":"
+	}
+	return v
+}
+
+// allows reports whether category may clean dir, and the volume dir
+// resolves to.
+func (p VolumePolicy) allows(category Category, dir string) (bool, string) {
+	if len(p.Never) == 0 && len(p.Only) == 0 {
+		return true, ""
+	}
+	resolved := dir
+	if r, err := filepath.EvalSymlinks(dir); err == nil {
+		resolved = r
+	}
+	vol := volumeOf(resolved)
+	if vol == "" {
+		return true, vol
+	}
+	for _, v := range p.Never {
+		if normalizeVolume(v) == vol {
+			return false, vol
+		}
+	}
+	only, ok := p.Only[category]
+	if !ok {
+		return true, vol
+	}
+	for _, v := range only {
+		if normalizeVolume(v) == vol {
+			return true, vol
+		}
+	}
+	return false, vol
+}
+
+// cleanTarget cleans dir for the category in opts, unless the volume
+// policy keeps that category off dir's volume.
+func cleanTarget(dir string, maxAge time.Duration, opts CleanOptions) CleanResult {
+	return cleanTargetMatching(dir, maxAge, opts, nil)
+}
+
+// cleanTargetMatching is cleanTarget restricted to files accepted by match.
+func cleanTargetMatching(dir string, maxAge time.Duration, opts CleanOptions, match fileMatcher) CleanResult {
+	if ok, vol := opts.Volumes.allows(opts.category, dir); !ok {
+		log.Printf("[SysCleaner] Skipping %s: %s is not allowed to clean volume %s", dir, opts.category, vol)
+		return CleanResult{}
+	}
+	return cleanDirectoryMatching(dir, maxAge, opts.DryRun, match, opts.throttle)
+}
@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVolumePolicy_FollowsJunctions(t *testing.T) {
+	root := t.TempDir()
+	cDir := filepath.Join(root, "c")
+	dDir := filepath.Join(root, "d")
+	for _, d := range []string{cDir, dDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	realD, _ := filepath.EvalSymlinks(dDir)
+
+	orig := volumeOf
+	volumeOf = func(path string) string {
+		if strings.HasPrefix(path, realD) {
+			return "D:"
+		}
+		return "C:"
+	}
+	defer func() { volumeOf = orig }()
+
+	// A temp folder on "C:" that is really a link to the "D:" scratch drive.
+	link := filepath.Join(cDir, "Temp")
+	if err := os.Symlink(dDir, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dDir, "scratch.tmp"), []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := CleanOptions{
+		Volumes:  VolumePolicy{Never: []string{"d"}},
+		category: CategoryUserTemp,
+	}
+	result := cleanTarget(link, 0, opts)
+	if result.FilesDeleted != 0 {
+		t.Errorf("expected nothing deleted through the link, got %d", result.FilesDeleted)
+	}
+	if _, err := os.Stat(filepath.Join(dDir, "scratch.tmp")); err != nil {
+		t.Error("file on the protected volume was removed")
+	}
+}
+
+func TestVolumePolicy_Only(t *testing.T) {
+	orig := volumeOf
+	volumeOf = func(path string) string { return "D:" }
+	defer func() { volumeOf = orig }()
+
+	p := VolumePolicy{Only: map[Category][]string{CategoryWindowsTemp: {`C:\`}}}
+	if ok, _ := p.allows(CategoryWindowsTemp, t.TempDir()); ok {
+		t.Error("Windows Temp is restricted to C: and should not clean D:")
+	}
+	if ok, _ := p.allows(CategoryChromeCache, t.TempDir()); !ok {
+		t.Error("categories without an Only entry should clean any volume")
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"syscleaner/pkg/admin"
+)
+
+// ConfigBackupCount is how many earlier versions of the config file
+// SaveConfig keeps, as config.yaml.bak1 (newest) to config.yaml.bakN.
+var ConfigBackupCount = 5
+
+// ConfigBackup is a rotating backup of the config file.
+type ConfigBackup struct {
+	N       int // 1 is the newest
+	Path    string
+	ModTime time.Time
+}
+
+// backupPath returns the path of backup n of the config file at path.
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak%d", path, n)
+}
+
+// rotateConfigBackups shifts the existing backups of the config file at
+// path down one slot, dropping the oldest, and copies the current file to
+// slot 1. Nothing happens when the file is missing or already holds data,
+// so saving an unchanged config does not push out older backups.
+func rotateConfigBackups(path string, data []byte) error {
+	if ConfigBackupCount <= 0 {
+		return nil
+	}
+	current, err := os.ReadFile(path)
+	if err != nil || bytes.Equal(current, data) {
+		return nil
+	}
+	os.Remove(backupPath(path, ConfigBackupCount))
+	for n := ConfigBackupCount - 1; n >= 1; n-- {
+		if err := os.Rename(backupPath(path, n), backupPath(path, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating config backups: %w", err)
+		}
+	}
+	if err := os.WriteFile(backupPath(path, 1), current, 0644); err != nil {
+		return fmt.Errorf("backing up config file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames
+// it into place, so a crash mid-save leaves the old file intact.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// ListConfigBackups returns the backups of the config file in use, newest
+// first.
+func ListConfigBackups() ([]ConfigBackup, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+	var backups []ConfigBackup
+	for n := 1; n <= ConfigBackupCount; n++ {
+		info, err := os.Stat(backupPath(path, n))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, ConfigBackup{N: n, Path: backupPath(path, n), ModTime: info.ModTime()})
+	}
+	return backups, nil
+}
+
+// RestoreConfigBackup replaces the config file with backup n (1 is the
+// newest). The backup must parse as a config file. The config being
+// replaced becomes backup 1, so a restore can itself be undone.
+func RestoreConfigBackup(n int) error {
+	if err := admin.RequireWriteAccess("restoring config backup"); err != nil {
+		return err
+	}
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(backupPath(path, n))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("config backup %d not found", n)
+		}
+		return fmt.Errorf("reading config backup %d: %w", n, err)
+	}
+
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		return fmt.Errorf("config backup %d: %w", n, err)
+	}
+	var d configData
+	if err := json.Unmarshal(migrated, &d); err != nil {
+		return fmt.Errorf("config backup %d: parsing config file: %w", n, err)
+	}
+
+	if err := rotateConfigBackups(path, data); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	return nil
+}
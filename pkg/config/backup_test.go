@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSaveConfig_RotatesBackups(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	orig := ConfigBackupCount
+	defer func() { ConfigBackupCount = orig }()
+	ConfigBackupCount = 2
+
+	cfg := DefaultConfig()
+	for _, tab := range []string{"one", "two", "three", "four"} {
+		cfg.UIPreferences.LastActiveTab = tab
+		if err := SaveConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Saving unchanged data does not rotate.
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := ListConfigBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].N != 1 || backups[1].N != 2 {
+		t.Fatalf("backups = %+v, want slots 1 and 2", backups)
+	}
+
+	if err := RestoreConfigBackup(2); err != nil {
+		t.Fatalf("RestoreConfigBackup: %v", err)
+	}
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UIPreferences.LastActiveTab != "two" {
+		t.Errorf("restored tab = %q, want two", got.UIPreferences.LastActiveTab)
+	}
+
+	// The replaced config became backup 1.
+	if err := RestoreConfigBackup(1); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := LoadConfig(); got.UIPreferences.LastActiveTab != "four" {
+		t.Errorf("undo restore: tab = %q, want four", got.UIPreferences.LastActiveTab)
+	}
+
+	if err := RestoreConfigBackup(5); err == nil {
+		t.Error("restoring a missing backup should fail")
+	}
+}
+
+func TestRestoreConfigBackup_RejectsCorruptBackup(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := SaveConfig(DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := configFilePath()
+	if err := os.WriteFile(backupPath(path, 1), []byte("{truncated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreConfigBackup(1); err == nil {
+		t.Error("restoring a corrupt backup should fail")
+	}
+	if _, err := LoadConfig(); err != nil {
+		t.Errorf("config damaged by failed restore: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/launcher"
+)
+
+// bundleFormat marks a file as a SysCleaner settings bundle.
+const bundleFormat = "syscleaner-settings-bundle"
+
+// bundleVersion is the bundle layout version written by ExportBundle.
+const bundleVersion = 1
+
+// settingsBundle is the on-disk form of a settings bundle: the config file
+// (including game overrides), every saved profile and the rule packs that
+// define custom clean targets.
+type settingsBundle struct {
+	Format   string          `json:"format"`
+	Version  int             `json:"version"`
+	Created  time.Time       `json:"created"`
+	Config   json.RawMessage `json:"config"`
+	Profiles []*Profile      `json:"profiles"`
+	// RulePacks maps a rule pack file name to its contents. The bytes are
+	// kept verbatim (base64 in JSON) so pack signatures stay valid.
+	RulePacks map[string][]byte `json:"rule_packs"`
+}
+
+// ExportBundle writes the config, saved profiles and rule packs to a single
+// file at path, for moving settings to another machine or sharing them.
+// Values set by SYSCLEANER_* environment variables are not exported, and
+// neither are secrets: they are tied to this user account and should not
+// travel in a file meant for sharing.
+func ExportBundle(path string) error {
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	cfg.Secrets = nil
+	configJSON, err := json.MarshalIndent(toConfigData(cfg), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+
+	b := settingsBundle{
+		Format:    bundleFormat,
+		Version:   bundleVersion,
+		Created:   time.Now().UTC(),
+		Config:    configJSON,
+		Profiles:  []*Profile{},
+		RulePacks: map[string][]byte{},
+	}
+
+	names, err := ListProfiles()
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p, err := LoadProfile(name)
+		if err != nil {
+			return err
+		}
+		b.Profiles = append(b.Profiles, p)
+	}
+
+	packs, err := filepath.Glob(filepath.Join(cleaner.DefaultRulesDir(), "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range packs {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("reading rule pack: %w", err)
+		}
+		b.RulePacks[filepath.Base(f)] = data
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling bundle: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	return nil
+}
+
+// ImportBundle replaces the config with the one in the bundle at path and
+// saves its profiles and rule packs, overwriting any with the same name.
+// Profiles and rule packs that are not in the bundle are left alone. The
+// whole bundle is checked before anything is written, and the current
+// config file is kept as config.yaml.pre-import.bak. Secrets are not in
+// bundles (see ExportBundle), so the importing user's own are kept.
+//
+// Game overrides and profile hooks are checked like a shared game profile
+// (see ImportGameProfile): a bundle whose overrides stop anti-cheat, audio
+// or input services is refused, as is one with pre-launch tools or hooks,
+// which run with administrator rights, unless allowHigh is set. The risks
+// found are returned either way.
+func ImportBundle(path string, allowHigh bool) ([]gaming.ProfileRisk, error) {
+	if err := admin.RequireWriteAccess("importing settings bundle"); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	var b settingsBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing bundle: %w", err)
+	}
+	if b.Format != bundleFormat {
+		return nil, fmt.Errorf("%s is not a SysCleaner settings bundle", filepath.Base(path))
+	}
+	if b.Version > bundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than supported version %d", b.Version, bundleVersion)
+	}
+
+	// Bundles from older releases carry older config files; migrate them
+	// like a config file on disk.
+	migrated, _, err := migrateConfig(b.Config)
+	if err != nil {
+		return nil, fmt.Errorf("bundle config: %w", err)
+	}
+	var d configData
+	if err := json.Unmarshal(migrated, &d); err != nil {
+		return nil, fmt.Errorf("parsing bundle config: %w", err)
+	}
+	cfg := fromConfigData(d)
+
+	for _, p := range b.Profiles {
+		if p == nil {
+			return nil, fmt.Errorf("bundle contains an empty profile")
+		}
+		if err := ValidateProfileName(p.Name); err != nil {
+			return nil, fmt.Errorf("bundle profile: %w", err)
+		}
+	}
+	for name := range b.RulePacks {
+		if name != filepath.Base(name) || !strings.HasSuffix(strings.ToLower(name), ".json") {
+			return nil, fmt.Errorf("bundle rule pack has an invalid file name %q", name)
+		}
+	}
+	risks := bundleRisks(cfg, b.Profiles)
+	if blocking := gaming.Blocking(risks, allowHigh); len(blocking) > 0 {
+		reasons := make([]string, len(blocking))
+		for i, r := range blocking {
+			reasons[i] = r.Reason
+		}
+		return risks, fmt.Errorf("settings bundle was not imported: %s", strings.Join(reasons, "; "))
+	}
+	current, err := loadConfigFile()
+	if err != nil {
+		return risks, err
+	}
+	cfg.Secrets = current.Secrets
+	// Saved over this machine's config, so only the bundle's own changes
+	// are written and machine defaults are not pinned in the user file.
+	cfg.machine = current.machine
+
+	for _, p := range b.Profiles {
+		if err := SaveProfile(p); err != nil {
+			return risks, err
+		}
+	}
+	if len(b.RulePacks) > 0 {
+		dir := cleaner.DefaultRulesDir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return risks, fmt.Errorf("creating rules directory: %w", err)
+		}
+		for name, pack := range b.RulePacks {
+			if err := os.WriteFile(filepath.Join(dir, name), pack, 0644); err != nil {
+				return risks, fmt.Errorf("writing rule pack %s: %w", name, err)
+			}
+		}
+	}
+
+	if current, err := configFilePath(); err == nil {
+		if old, err := os.ReadFile(current); err == nil {
+			if err := os.WriteFile(current+".pre-import.bak", old, 0644); err != nil {
+				return risks, fmt.Errorf("backing up config file: %w", err)
+			}
+		}
+	}
+	return risks, SaveConfig(cfg)
+}
+
+// bundleRisks lists what a bundle's config and profiles would set up that
+// needs the user's attention: each game override is rated as a shared game
+// profile would be, and each profile hook is high risk, since hooks run
+// their tools with administrator rights just like pre-launch tools.
+func bundleRisks(cfg *Config, profiles []*Profile) []gaming.ProfileRisk {
+	var risks []gaming.ProfileRisk
+	games := make([]string, 0, len(cfg.GameOverrides))
+	for game := range cfg.GameOverrides {
+		games = append(games, game)
+	}
+	sort.Strings(games)
+	for _, game := range games {
+		for _, r := range gaming.NewSharedProfile(game, cfg.GameOverrides[game]).Risks() {
+			r.Reason = fmt.Sprintf("the game override for %s %s", game, r.Reason)
+			risks = append(risks, r)
+		}
+	}
+	for _, p := range profiles {
+		hook := func(actions []launcher.Action, when string) {
+			for _, a := range actions {
+				cmd := strings.TrimSpace(a.Command + " " + strings.Join(a.Args, " "))
+				risks = append(risks, gaming.ProfileRisk{Risk: gaming.RiskHigh,
+					Reason: fmt.Sprintf("profile %s runs %s as administrator when gaming mode is %s", p.Name, cmd, when)})
+			}
+		}
+		hook(p.Hooks.GamingEnable, "enabled")
+		hook(p.Hooks.GamingDisable, "disabled")
+	}
+	return risks
+}
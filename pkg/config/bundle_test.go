@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/launcher"
+)
+
+func TestBundle_RoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.ActiveProfile = "streaming"
+	cfg.GameOverrides = map[string]gaming.GameOverride{"cs2.exe": {Priority: "high", Affinity: 0xF}}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	p := DefaultProfile()
+	p.Name = "streaming"
+	p.ProcessWhitelist = []string{"obs64.exe"}
+	if err := SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	pack := []byte("{\n  \"pack\": {\"name\": \"x\", \"rules\": []},\n  \"signature\": \"abc\"\n}\n")
+	if err := os.MkdirAll(cleaner.DefaultRulesDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cleaner.DefaultRulesDir(), "custom.json"), pack, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "settings.scbundle")
+	if err := ExportBundle(bundle); err != nil {
+		t.Fatalf("ExportBundle: %v", err)
+	}
+
+	// Import on a "new machine" with an existing config.
+	fakeSecretStore(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	existing := DefaultConfig()
+	existing.Secrets = map[string]Secret{"webhook_url": "https://hooks.example.com/abc"}
+	if err := SaveConfig(existing); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportBundle(bundle, false); err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ActiveProfile != "streaming" || got.GameOverrides["cs2.exe"].Affinity != 0xF {
+		t.Errorf("config not imported: active=%q overrides=%v", got.ActiveProfile, got.GameOverrides)
+	}
+	if got.Secrets["webhook_url"].Reveal() != "https://hooks.example.com/abc" {
+		t.Errorf("importing dropped the user's secrets: %v", got.Secrets)
+	}
+	if gp, err := LoadProfile("streaming"); err != nil || len(gp.ProcessWhitelist) != 1 {
+		t.Errorf("profile not imported: %+v, %v", gp, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(cleaner.DefaultRulesDir(), "custom.json")); err != nil || string(data) != string(pack) {
+		t.Errorf("rule pack not imported byte-for-byte: %q, %v", data, err)
+	}
+	path, _ := configFilePath()
+	if _, err := os.Stat(path + ".pre-import.bak"); err != nil {
+		t.Errorf("expected the previous config to be backed up: %v", err)
+	}
+}
+
+func TestImportBundle_RejectsBadRulePackName(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	bundle := filepath.Join(t.TempDir(), "bad.scbundle")
+	data := []byte(`{"format": "syscleaner-settings-bundle", "version": 1, "config": {"version": 1},
+		"rule_packs": {"../evil.json": "e30="}}`)
+	if err := os.WriteFile(bundle, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportBundle(bundle, false); err == nil {
+		t.Fatal("expected an error for a rule pack outside the rules directory")
+	}
+	if _, err := os.Stat(filepath.Join(cleaner.DefaultRulesDir(), "..", "evil.json")); !os.IsNotExist(err) {
+		t.Error("rule pack written outside the rules directory")
+	}
+}
+
+func TestImportBundle_RejectsOtherFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportBundle(path, false); err == nil {
+		t.Fatal("expected an error for a file that is not a bundle")
+	}
+}
+
+func TestImportBundle_Risky(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.GameOverrides = map[string]gaming.GameOverride{
+		"game.exe": {PreLaunch: []launcher.Action{{Command: "tweak.exe"}}},
+	}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	p := DefaultProfile()
+	p.Name = "hooked"
+	p.Hooks.GamingEnable = []launcher.Action{{Command: "overlay.exe"}}
+	if err := SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(t.TempDir(), "settings.scbundle")
+	if err := ExportBundle(bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := SaveConfig(DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if risks, err := ImportBundle(bundle, false); err == nil || len(risks) != 2 {
+		t.Fatalf("tools and hooks imported without allowHigh: risks %v, err %v", risks, err)
+	}
+	if got, _ := LoadConfig(); len(got.GameOverrides) != 0 {
+		t.Errorf("refused bundle's config was saved: %+v", got.GameOverrides)
+	}
+	if _, err := LoadProfile("hooked"); err == nil {
+		t.Error("refused bundle's profile was saved")
+	}
+	if _, err := ImportBundle(bundle, true); err != nil {
+		t.Errorf("ImportBundle with allowHigh: %v", err)
+	}
+}
+
+func TestImportBundle_MachineConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ProgramData", "")
+	cfg := DefaultConfig()
+	cfg.ActiveProfile = "streaming"
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(t.TempDir(), "settings.scbundle")
+	if err := ExportBundle(bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	writeMachineConfig(t, `{"version": 1, "log_level": "warn"}`)
+	if _, err := ImportBundle(bundle, false); err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	path, _ := configFilePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "streaming") || strings.Contains(string(data), "dry_run") {
+		t.Errorf("user file should hold only the bundle's changes:\n%s", data)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"log"
+	"strings"
+
+	"syscleaner/pkg/cleaner"
+)
+
+// CleanerSettings controls how the cleaner disposes of files.
+type CleanerSettings struct {
+	// Exclusions are path patterns the cleaner never touches.
+	Exclusions []string `json:"exclusions"`
+	// Quarantine moves files to a quarantine folder instead of deleting
+	// them, so they can be restored.
+	Quarantine bool `json:"quarantine"`
+	// AutoLearnExclusions adds suggested exclusions automatically when
+	// quarantined files are restored repeatedly.
+	AutoLearnExclusions bool `json:"auto_learn_exclusions"`
+
+	// RulePackKeys are base64 Ed25519 public keys trusted to sign rule
+	// packs in the rules directory.
+	RulePackKeys []string `json:"rule_pack_keys"`
+	// AllowUnsignedRulePacks loads rule packs that carry no signature.
+	// Packs with an invalid signature are always rejected.
+	AllowUnsignedRulePacks bool `json:"allow_unsigned_rule_packs"`
+
+	// StorePackages limits which Microsoft Store packages the Store App
+	// Cache category cleans.
+	StorePackages cleaner.StorePackageFilter `json:"store_packages"`
+
+	// DryRun makes every clean a preview that deletes nothing.
+	DryRun bool `json:"dry_run"`
+
+	// DisabledCategories lists categories (e.g. "Event Logs") that are
+	// never cleaned, even when selected.
+	DisabledCategories []string `json:"disabled_categories"`
+}
+
+// Apply pushes the settings into the cleaner package.
+func (s CleanerSettings) Apply() {
+	cleaner.SetExclusions(s.Exclusions)
+	cleaner.SetStorePackageFilter(s.StorePackages)
+	cleaner.ForceDryRun = s.DryRun
+	disabled := make([]cleaner.Category, len(s.DisabledCategories))
+	for i, c := range s.DisabledCategories {
+		disabled[i] = cleaner.Category(c)
+	}
+	cleaner.SetDisabledCategories(disabled)
+	if s.Quarantine {
+		cleaner.SetQuarantineDir(cleaner.DefaultQuarantineDir())
+	} else {
+		cleaner.SetQuarantineDir("")
+	}
+
+	keys := publicKeys(s.RulePackKeys, "rule pack")
+	packs, errs := cleaner.LoadRulePacks(cleaner.DefaultRulesDir(), keys, s.AllowUnsignedRulePacks)
+	for _, err := range errs {
+		log.Printf("[SysCleaner] Skipping rule pack: %v", err)
+	}
+	cleaner.SetRulePacks(packs)
+}
+
+// AddExclusions appends patterns not already present (case-insensitively)
+// and returns the ones that were added.
+func (s *CleanerSettings) AddExclusions(patterns ...string) []string {
+	seen := make(map[string]bool, len(s.Exclusions))
+	for _, e := range s.Exclusions {
+		seen[strings.ToLower(e)] = true
+	}
+	var added []string
+	for _, p := range patterns {
+		if p == "" || seen[strings.ToLower(p)] {
+			continue
+		}
+		seen[strings.ToLower(p)] = true
+		s.Exclusions = append(s.Exclusions, p)
+		added = append(added, p)
+	}
+	return added
+}
+
+// publicKeys decodes base64 Ed25519 public keys, logging and skipping
+// invalid ones. what names the keys' use in the log.
+func publicKeys(encoded []string, what string) []ed25519.PublicKey {
+	var keys []ed25519.PublicKey
+	for _, k := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(k)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			log.Printf("[SysCleaner] Ignoring invalid %s key %q", what, k)
+			continue
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	return keys
+}
@@ -3,10 +3,16 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 
+	"syscleaner/pkg/admin"
 	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/memory"
+	"syscleaner/pkg/scheduler"
+	"syscleaner/pkg/sysinfo"
 )
 
 // RAMMonitorSettings holds threshold configuration for RAM monitoring.
@@ -15,6 +21,61 @@ type RAMMonitorSettings struct {
 	StandbyThresholdPercent float64 `json:"standby_threshold_percent"`
 }
 
+// Apply pushes the thresholds into the memory package.
+func (r RAMMonitorSettings) Apply() {
+	if r.FreeThresholdPercent > 0 {
+		memory.FreeMemoryThresholdPercent = r.FreeThresholdPercent
+	}
+	if r.StandbyThresholdPercent > 0 {
+		memory.StandbyThresholdPercent = r.StandbyThresholdPercent
+	}
+}
+
+// LowDiskSettings configures the automatic clean that runs when a drive
+// runs low on free space.
+type LowDiskSettings struct {
+	Enabled          bool     `json:"enabled"`
+	Drives           []string `json:"drives"`            // empty means the system drive
+	ThresholdPercent float64  `json:"threshold_percent"` // free % below which to clean
+	ThresholdGB      float64  `json:"threshold_gb"`      // free GB below which to clean
+	Profile          string   `json:"profile"`           // profile whose clean options to run
+	CooldownMinutes  int      `json:"cooldown_minutes"`  // minimum gap between automatic cleans
+}
+
+// PowerProfileSettings switches the active profile when the machine moves
+// between AC and battery power, or is docked or undocked. An empty profile
+// name leaves the active profile alone for that state.
+type PowerProfileSettings struct {
+	Enabled   bool   `json:"enabled"`
+	OnAC      string `json:"on_ac"`
+	OnBattery string `json:"on_battery"` // takes precedence over the dock profiles
+	Docked    string `json:"docked"`
+	Undocked  string `json:"undocked"`
+}
+
+// ProfileFor returns the profile to use in the given power state, or ""
+// if the settings don't name one.
+func (s PowerProfileSettings) ProfileFor(status sysinfo.PowerStatus) string {
+	if !status.Known {
+		return ""
+	}
+	if status.OnBattery && s.OnBattery != "" {
+		return s.OnBattery
+	}
+	if status.DockKnown {
+		if status.Docked && s.Docked != "" {
+			return s.Docked
+		}
+		if !status.Docked && s.Undocked != "" {
+			return s.Undocked
+		}
+	}
+	if !status.OnBattery {
+		return s.OnAC
+	}
+	return ""
+}
+
 // UIPreferences stores persistent UI state.
 type UIPreferences struct {
 	LastActiveTab string `json:"last_active_tab"`
@@ -27,73 +88,281 @@ type Config struct {
 	RAMMonitor          RAMMonitorSettings
 	UIPreferences       UIPreferences
 	ActiveProfile       string
+
+	// GameOverrides customizes gaming mode per game, keyed by game name or
+	// executable (see gaming.GameOverride).
+	GameOverrides map[string]gaming.GameOverride
+
+	// ExtremeStopList replaces the services and apps extreme mode stops,
+	// each tagged with a risk level (see gaming.StopEntry). nil uses
+	// gaming.DefaultStopList.
+	ExtremeStopList []gaming.StopEntry
+
+	// GameDatabase says where newer game databases come from and who may
+	// sign them.
+	GameDatabase GameDatabaseSettings
+
+	// RegistryLogging mirrors every registry write to a per-run .reg undo
+	// file and change log (see pkg/reglog).
+	RegistryLogging bool
+
+	// SkipRestorePoints stops SysCleaner creating a System Restore point
+	// before extreme mode and risky optimizations (see pkg/sysrestore).
+	SkipRestorePoints bool
+
+	// Performance holds worker counts, I/O priority and sampling intervals.
+	Performance PerformanceSettings
+
+	// Cleaner holds exclusions and quarantine behaviour.
+	Cleaner CleanerSettings
+
+	// LowDisk triggers an automatic clean when free space runs low.
+	LowDisk LowDiskSettings
+
+	// PowerProfiles switches profiles on AC/battery and dock changes.
+	PowerProfiles PowerProfileSettings
+
+	// Schedules are cleans and optimizations run on a cron spec or
+	// interval while SysCleaner is running (see scheduler.Runner).
+	Schedules []scheduler.ScheduleEntry
+
+	// Hotkeys are system-wide shortcuts for gaming mode, extreme mode and
+	// a RAM purge, registered by the GUI.
+	Hotkeys HotkeySettings
+
+	// LogLevel is the minimum level written by pkg/logger: "debug",
+	// "info", "warn" or "error".
+	LogLevel string
+
+	// LogShipping forwards log output to a syslog server or HTTPS
+	// endpoint (see ApplyLogShipping).
+	LogShipping LogShippingSettings
+
+	// RedactLogs removes user names and profile paths from log lines and
+	// exported reports (see logger.SetRedaction).
+	RedactLogs bool
+
+	// AuditMode locks the application into read-only audit mode (see
+	// admin.SetAuditMode). While it is set the config cannot be saved, so
+	// it can only be turned off by editing the file.
+	AuditMode bool
+
+	// Secrets holds sensitive values such as webhook URLs and API tokens,
+	// keyed by name. They are encrypted for the current user in the file.
+	Secrets map[string]Secret
+
+	// machine is the machine-wide config this one was layered over (see
+	// MachineConfigPath), or nil if there is none.
+	machine map[string]any
+
+	// envOverrides records the SYSCLEANER_* variables and policy values
+	// applied by LoadConfig so SaveConfig writes the file's own values back.
+	envOverrides []appliedOverride
 }
 
-// ConfigDir returns the path to the SysCleaner configuration directory.
-// The directory is derived from os.UserConfigDir() with "SysCleaner" appended.
+// ConfigDir returns the directory holding the config file in use (see
+// ResolveConfigPath), normally os.UserConfigDir() with "SysCleaner"
+// appended.
 func ConfigDir() (string, error) {
-	base, err := os.UserConfigDir()
+	path, err := configFilePath()
 	if err != nil {
-		return "", fmt.Errorf("unable to determine user config directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(base, "SysCleaner"), nil
+	return filepath.Dir(path), nil
 }
 
 // configFilePath returns the full path to the configuration file.
 func configFilePath() (string, error) {
-	dir, err := ConfigDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, "config.yaml"), nil
+	path, _, err := ResolveConfigPath()
+	return path, err
 }
 
 // LoadConfig reads the configuration from disk. If the file does not exist,
-// a default configuration is returned without error.
+// a default configuration is returned without error. The per-user file is
+// laid over the machine-wide config when there is one (see
+// MachineConfigPath), SYSCLEANER_*
+// environment variables are layered on top (see EnvPrefix), and policy
+// values (see PolicyKey) on top of those.
 func LoadConfig() (*Config, error) {
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	applyEnvOverrides(cfg, os.LookupEnv)
+	applyPolicy(cfg, readPolicyValues())
+	return cfg, nil
+}
+
+// loadConfigFile reads config.yaml without environment or policy overrides.
+func loadConfigFile() (*Config, error) {
 	path, err := configFilePath()
 	if err != nil {
 		return nil, err
 	}
 
+	machine, err := readMachineConfig()
+	if err != nil {
+		// A broken machine config should not lock users out.
+		log.Printf("[SysCleaner] Ignoring machine config: %v", err)
+		machine = nil
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		if _, err := os.Stat(backupPath(path, 1)); err == nil {
+			log.Printf("[SysCleaner] Config file %s is missing but backups exist; using defaults (restore with: syscleaner config --restore-backup 1)", path)
+		}
+		if machine == nil {
 			return DefaultConfig(), nil
 		}
-		return nil, fmt.Errorf("reading config file: %w", err)
+		layered, err := layerMachineConfig(machine, nil)
+		if err != nil {
+			return nil, err
+		}
+		var d configData
+		if err := json.Unmarshal(layered, &d); err != nil {
+			return nil, fmt.Errorf("parsing machine config file: %w", err)
+		}
+		cfg := fromConfigData(d)
+		cfg.machine = machine
+		return cfg, nil
+	}
+
+	migrated, version, err := migrateConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentConfigVersion {
+		log.Printf("[SysCleaner] Config file version %d is newer than supported version %d; unknown settings will be ignored",
+			version, CurrentConfigVersion)
 	}
 
+	layered := migrated
+	if machine != nil {
+		if layered, err = layerMachineConfig(machine, migrated); err != nil {
+			return nil, err
+		}
+	}
 	var d configData
-	if err := json.Unmarshal(data, &d); err != nil {
+	if err := json.Unmarshal(layered, &d); err != nil {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
-	return fromConfigData(d), nil
+	cfg := fromConfigData(d)
+	cfg.machine = machine
+
+	// Upgrade the file in place so the migration only runs once, keeping
+	// the original alongside it. Secrets typed in as plain text are
+	// encrypted the same way.
+	if version == CurrentConfigVersion && !admin.AuditMode() && hasPlaintextSecrets(migrated) {
+		if err := SaveConfig(cfg); err != nil {
+			log.Printf("[SysCleaner] Could not encrypt plain-text secrets in the config file: %v", err)
+		}
+	}
+	if version < CurrentConfigVersion {
+		backup, err := backupConfigFile(path, data, version)
+		if err == nil {
+			err = SaveConfig(cfg)
+		}
+		if err != nil {
+			log.Printf("[SysCleaner] Config migrated from version %d but not saved: %v", version, err)
+		} else {
+			log.Printf("[SysCleaner] Config migrated from version %d to %d (backup: %s)", version, CurrentConfigVersion, backup)
+		}
+	}
+	return cfg, nil
 }
 
 // SaveConfig writes the configuration to disk, creating the config directory
-// if it does not already exist.
+// if it does not already exist. The previous file is kept as a rotating
+// backup (see ConfigBackupCount) and replaced atomically.
 func SaveConfig(cfg *Config) error {
-	dir, err := ConfigDir()
+	if err := admin.RequireWriteAccess("saving config"); err != nil {
+		return err
+	}
+	path, err := configFilePath()
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(toConfigData(cfg), "", "  ")
+	// Values from SYSCLEANER_* variables and policy are not written to the
+	// file.
+	var file any = toConfigData(withoutOverrides(cfg))
+	if cfg.machine != nil {
+		// Only the user's own changes are kept, so later edits to the
+		// machine config still reach this user.
+		if file, err = machineRelativeFields(withoutOverrides(cfg)); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
 
-	path := filepath.Join(dir, "config.yaml")
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := rotateConfigBackups(path, data); err != nil {
+		log.Printf("[SysCleaner] %v", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
 		return fmt.Errorf("writing config file: %w", err)
 	}
 	return nil
 }
 
+// validGameOverrides drops game overrides that fail validation, logging
+// each one, so a typo disables one game's override rather than the config.
+func validGameOverrides(overrides map[string]gaming.GameOverride) map[string]gaming.GameOverride {
+	for name, o := range overrides {
+		if err := o.Validate(); err != nil {
+			log.Printf("[SysCleaner] Ignoring game override for %s: %v", name, err)
+			delete(overrides, name)
+		}
+	}
+	return overrides
+}
+
+// validStopList drops extreme mode stop list entries that fail
+// validation, logging each one.
+func validStopList(list []gaming.StopEntry) []gaming.StopEntry {
+	if list == nil {
+		return nil
+	}
+	out := make([]gaming.StopEntry, 0, len(list))
+	for _, e := range list {
+		if err := e.Validate(); err != nil {
+			log.Printf("[SysCleaner] Ignoring extreme_stop_list entry: %v", err)
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// validSchedules drops schedule entries that fail validation or repeat an
+// earlier entry's name, logging each one.
+func validSchedules(entries []scheduler.ScheduleEntry) []scheduler.ScheduleEntry {
+	var out []scheduler.ScheduleEntry
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if err := e.Validate(); err != nil {
+			log.Printf("[SysCleaner] Ignoring schedule: %v", err)
+			continue
+		}
+		if seen[e.Name] {
+			log.Printf("[SysCleaner] Ignoring duplicate schedule %q", e.Name)
+			continue
+		}
+		seen[e.Name] = true
+		out = append(out, e)
+	}
+	return out
+}
+
 // DefaultConfig returns a Config populated with sensible default values.
 func DefaultConfig() *Config {
 	return &Config{
@@ -115,7 +384,19 @@ func DefaultConfig() *Config {
 		UIPreferences: UIPreferences{
 			LastActiveTab: "dashboard",
 		},
-		ActiveProfile: "default",
+		ActiveProfile:   "default",
+		RegistryLogging: false,
+		LogLevel:        "info",
+		Performance:     DefaultPerformanceSettings(hardwareInfo()),
+		Cleaner:         CleanerSettings{Exclusions: []string{}},
+		LowDisk: LowDiskSettings{
+			Enabled:          false,
+			Drives:           []string{},
+			ThresholdPercent: 10.0,
+			Profile:          "default",
+			CooldownMinutes:  360,
+		},
+		Schedules: []scheduler.ScheduleEntry{},
 	}
 }
 
@@ -146,19 +427,33 @@ type cleanOptionsData struct {
 	EventLogs            bool `json:"event_logs"`
 	DeliveryOptimization bool `json:"delivery_optimization"`
 	RecycleBin           bool `json:"recycle_bin"`
+	FontIconRebuild      bool `json:"font_icon_rebuild"`
 
 	// Application categories
-	ChromeCache  bool `json:"chrome_cache"`
-	FirefoxCache bool `json:"firefox_cache"`
-	EdgeCache    bool `json:"edge_cache"`
-	BraveCache   bool `json:"brave_cache"`
-	OperaCache   bool `json:"opera_cache"`
-	DiscordCache bool `json:"discord_cache"`
-	SpotifyCache bool `json:"spotify_cache"`
-	SteamCache   bool `json:"steam_cache"`
-	TeamsCache   bool `json:"teams_cache"`
-	VSCodeCache  bool `json:"vscode_cache"`
-	JavaCache    bool `json:"java_cache"`
+	ChromeCache   bool `json:"chrome_cache"`
+	FirefoxCache  bool `json:"firefox_cache"`
+	EdgeCache     bool `json:"edge_cache"`
+	BraveCache    bool `json:"brave_cache"`
+	OperaCache    bool `json:"opera_cache"`
+	DiscordCache  bool `json:"discord_cache"`
+	SpotifyCache  bool `json:"spotify_cache"`
+	SteamCache    bool `json:"steam_cache"`
+	TeamsCache    bool `json:"teams_cache"`
+	VSCodeCache   bool `json:"vscode_cache"`
+	JavaCache     bool `json:"java_cache"`
+	StoreAppCache bool `json:"store_app_cache"`
+
+	// Privacy categories
+	RecentDocuments  bool `json:"recent_documents"`
+	JumpLists        bool `json:"jump_lists"`
+	ClipboardHistory bool `json:"clipboard_history"`
+	ExplorerMRU      bool `json:"explorer_mru"`
+
+	// Rule-pack rule IDs
+	Rules []string `json:"rules,omitempty"`
+
+	// Volumes restricts which drives each category may clean
+	Volumes cleaner.VolumePolicy `json:"volumes"`
 
 	// Execution options
 	DryRun bool `json:"dry_run"`
@@ -166,11 +461,28 @@ type cleanOptionsData struct {
 
 // configData is the JSON-serializable representation of Config.
 type configData struct {
-	ProcessWhitelist    []string           `json:"process_whitelist"`
-	DefaultCleanOptions cleanOptionsData   `json:"default_clean_options"`
-	RAMMonitor          RAMMonitorSettings `json:"ram_monitor"`
-	UIPreferences       UIPreferences      `json:"ui_preferences"`
-	ActiveProfile       string             `json:"active_profile"`
+	Version             int                            `json:"version"`
+	ProcessWhitelist    []string                       `json:"process_whitelist"`
+	DefaultCleanOptions cleanOptionsData               `json:"default_clean_options"`
+	RAMMonitor          RAMMonitorSettings             `json:"ram_monitor"`
+	UIPreferences       UIPreferences                  `json:"ui_preferences"`
+	ActiveProfile       string                         `json:"active_profile"`
+	GameOverrides       map[string]gaming.GameOverride `json:"game_overrides"`
+	ExtremeStopList     []gaming.StopEntry             `json:"extreme_stop_list,omitempty"`
+	GameDatabase        GameDatabaseSettings           `json:"game_database"`
+	RegistryLogging     bool                           `json:"registry_logging"`
+	SkipRestorePoints   bool                           `json:"skip_restore_points,omitempty"`
+	Performance         PerformanceSettings            `json:"performance"`
+	Cleaner             CleanerSettings                `json:"cleaner"`
+	LowDisk             LowDiskSettings                `json:"low_disk"`
+	PowerProfiles       PowerProfileSettings           `json:"power_profiles"`
+	Schedules           []scheduler.ScheduleEntry      `json:"schedules"`
+	Hotkeys             HotkeySettings                 `json:"hotkeys"`
+	LogLevel            string                         `json:"log_level"`
+	LogShipping         LogShippingSettings            `json:"log_shipping"`
+	RedactLogs          bool                           `json:"redact_logs"`
+	AuditMode           bool                           `json:"audit_mode"`
+	Secrets             map[string]Secret              `json:"secrets"`
 }
 
 func toCleanOptionsData(o cleaner.CleanOptions) cleanOptionsData {
@@ -191,6 +503,7 @@ func toCleanOptionsData(o cleaner.CleanOptions) cleanOptionsData {
 		EventLogs:            o.EventLogs,
 		DeliveryOptimization: o.DeliveryOptimization,
 		RecycleBin:           o.RecycleBin,
+		FontIconRebuild:      o.FontIconRebuild,
 		ChromeCache:          o.ChromeCache,
 		FirefoxCache:         o.FirefoxCache,
 		EdgeCache:            o.EdgeCache,
@@ -202,6 +515,13 @@ func toCleanOptionsData(o cleaner.CleanOptions) cleanOptionsData {
 		TeamsCache:           o.TeamsCache,
 		VSCodeCache:          o.VSCodeCache,
 		JavaCache:            o.JavaCache,
+		StoreAppCache:        o.StoreAppCache,
+		RecentDocuments:      o.RecentDocuments,
+		JumpLists:            o.JumpLists,
+		ClipboardHistory:     o.ClipboardHistory,
+		ExplorerMRU:          o.ExplorerMRU,
+		Rules:                o.Rules,
+		Volumes:              o.Volumes,
 		DryRun:               o.DryRun,
 	}
 }
@@ -224,6 +544,7 @@ func fromCleanOptionsData(d cleanOptionsData) cleaner.CleanOptions {
 		EventLogs:            d.EventLogs,
 		DeliveryOptimization: d.DeliveryOptimization,
 		RecycleBin:           d.RecycleBin,
+		FontIconRebuild:      d.FontIconRebuild,
 		ChromeCache:          d.ChromeCache,
 		FirefoxCache:         d.FirefoxCache,
 		EdgeCache:            d.EdgeCache,
@@ -235,17 +556,41 @@ func fromCleanOptionsData(d cleanOptionsData) cleaner.CleanOptions {
 		TeamsCache:           d.TeamsCache,
 		VSCodeCache:          d.VSCodeCache,
 		JavaCache:            d.JavaCache,
+		StoreAppCache:        d.StoreAppCache,
+		RecentDocuments:      d.RecentDocuments,
+		JumpLists:            d.JumpLists,
+		ClipboardHistory:     d.ClipboardHistory,
+		ExplorerMRU:          d.ExplorerMRU,
+		Rules:                d.Rules,
+		Volumes:              d.Volumes,
 		DryRun:               d.DryRun,
 	}
 }
 
 func toConfigData(c *Config) configData {
 	return configData{
+		Version:             CurrentConfigVersion,
 		ProcessWhitelist:    c.ProcessWhitelist,
 		DefaultCleanOptions: toCleanOptionsData(c.DefaultCleanOptions),
 		RAMMonitor:          c.RAMMonitor,
 		UIPreferences:       c.UIPreferences,
 		ActiveProfile:       c.ActiveProfile,
+		GameOverrides:       c.GameOverrides,
+		ExtremeStopList:     c.ExtremeStopList,
+		GameDatabase:        c.GameDatabase,
+		RegistryLogging:     c.RegistryLogging,
+		SkipRestorePoints:   c.SkipRestorePoints,
+		Performance:         c.Performance,
+		Cleaner:             c.Cleaner,
+		LowDisk:             c.LowDisk,
+		PowerProfiles:       c.PowerProfiles,
+		Schedules:           c.Schedules,
+		Hotkeys:             c.Hotkeys,
+		LogLevel:            c.LogLevel,
+		LogShipping:         c.LogShipping,
+		RedactLogs:          c.RedactLogs,
+		AuditMode:           c.AuditMode,
+		Secrets:             c.Secrets,
 	}
 }
 
@@ -256,5 +601,23 @@ func fromConfigData(d configData) *Config {
 		RAMMonitor:          d.RAMMonitor,
 		UIPreferences:       d.UIPreferences,
 		ActiveProfile:       d.ActiveProfile,
+		GameOverrides:       validGameOverrides(d.GameOverrides),
+		ExtremeStopList:     validStopList(d.ExtremeStopList),
+		GameDatabase:        d.GameDatabase,
+		RegistryLogging:     d.RegistryLogging,
+		SkipRestorePoints:   d.SkipRestorePoints,
+		// Config files written before the performance section existed get
+		// hardware-derived defaults for every unset knob.
+		Performance:   d.Performance.withDefaults(DefaultPerformanceSettings(hardwareInfo())),
+		Cleaner:       d.Cleaner,
+		LowDisk:       d.LowDisk,
+		PowerProfiles: d.PowerProfiles,
+		Schedules:     validSchedules(d.Schedules),
+		Hotkeys:       d.Hotkeys,
+		LogLevel:      d.LogLevel,
+		LogShipping:   d.LogShipping,
+		RedactLogs:    d.RedactLogs,
+		AuditMode:     d.AuditMode,
+		Secrets:       d.Secrets,
 	}
 }
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"syscleaner/pkg/admin"
 	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/sysinfo"
 )
 
 func TestDefaultConfig_ReturnsReasonableDefaults(t *testing.T) {
@@ -200,3 +205,208 @@ func defaultCleanOptionsForTest() cleaner.CleanOptions {
 		DryRun:      true,
 	}
 }
+
+func TestDefaultPerformanceSettings_ByDriveType(t *testing.T) {
+	ssd := DefaultPerformanceSettings(sysinfo.Info{LogicalCPUs: 16, SystemDriveSSD: true, SSDKnown: true})
+	if ssd.CleanerWorkers != 8 {
+		t.Errorf("expected SSD workers capped at 8, got %d", ssd.CleanerWorkers)
+	}
+
+	hdd := DefaultPerformanceSettings(sysinfo.Info{LogicalCPUs: 16, SystemDriveSSD: false, SSDKnown: true})
+	if hdd.CleanerWorkers != 2 {
+		t.Errorf("expected HDD workers=2, got %d", hdd.CleanerWorkers)
+	}
+}
+
+func TestLoadConfig_FillsMissingPerformanceSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalXDG := os.Getenv("XDG_CONFIG_HOME")
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Cleanup(func() {
+		if originalXDG == "" {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		} else {
+			os.Setenv("XDG_CONFIG_HOME", originalXDG)
+		}
+	})
+
+	// An older config file with only one performance knob set.
+	dir := filepath.Join(tmpDir, "SysCleaner")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"active_profile": "default", "performance": {"cleaner_workers": 3}}`)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Performance.CleanerWorkers != 3 {
+		t.Errorf("expected CleanerWorkers=3, got %d", cfg.Performance.CleanerWorkers)
+	}
+	if cfg.Performance.RAMMonitorIntervalSeconds != 5 {
+		t.Errorf("expected default RAM interval 5, got %d", cfg.Performance.RAMMonitorIntervalSeconds)
+	}
+	if limit := cfg.Performance.BackgroundRateLimit(); limit.FilesPerSecond != 200 || limit.BytesPerSecond != 20*1024*1024 {
+		t.Errorf("expected default background limit 200 files/s, 20 MB/s, got %+v", limit)
+	}
+	if cfg.Performance.CleanerIOPriority != "normal" {
+		t.Errorf("expected default IO priority normal, got %q", cfg.Performance.CleanerIOPriority)
+	}
+	if ttl := cfg.Performance.EstimateCacheTTLSeconds; ttl == nil || *ttl != 300 {
+		t.Errorf("expected default estimate cache TTL 300, got %v", ttl)
+	}
+	if cfg.Performance.DashboardRefreshInterval() != 2*time.Second || cfg.Performance.GPURefreshInterval() != 10*time.Second {
+		t.Errorf("expected default GUI refresh 2s/10s, got %v/%v",
+			cfg.Performance.DashboardRefreshInterval(), cfg.Performance.GPURefreshInterval())
+	}
+	if cfg.Performance.StatusBarRefreshInterval() != 2*time.Second {
+		t.Errorf("expected default status bar refresh 2s, got %v", cfg.Performance.StatusBarRefreshInterval())
+	}
+}
+
+func TestLoadConfig_EstimateCacheDisabled(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "SysCleaner")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"active_profile": "default", "performance": {"estimate_cache_ttl_seconds": 0}}`)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if ttl := cfg.Performance.EstimateCacheTTLSeconds; ttl == nil || *ttl != 0 {
+		t.Fatalf("estimate cache TTL 0 should stay 0 (disabled), got %v", ttl)
+	}
+	orig := cleaner.EstimateCacheTTL
+	defer func() { cleaner.EstimateCacheTTL = orig }()
+	cfg.Performance.Apply()
+	if cleaner.EstimateCacheTTL != 0 {
+		t.Errorf("cleaner.EstimateCacheTTL = %v, want 0", cleaner.EstimateCacheTTL)
+	}
+}
+
+func TestSaveConfig_RefusedInAuditMode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	admin.SetAuditMode(true)
+	defer admin.SetAuditMode(false)
+
+	if err := SaveConfig(DefaultConfig()); !errors.Is(err, admin.ErrAuditMode) {
+		t.Fatalf("SaveConfig in audit mode = %v, want ErrAuditMode", err)
+	}
+	path, _ := configFilePath()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("config file written in audit mode")
+	}
+}
+
+func TestLoadConfig_GameOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	dir := filepath.Join(tmpDir, "SysCleaner")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"version": 1, "game_overrides": {
+		"cs2.exe": {"priority": "above normal", "affinity": 12, "clean_categories": ["Shader Cache"]},
+		"Valorant": {"priority": "realtime"}
+	}}`)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	o, ok := cfg.GameOverrides["cs2.exe"]
+	if !ok || o.Priority != "above normal" || o.Affinity != 12 || len(o.CleanCategories) != 1 {
+		t.Errorf("unexpected cs2.exe override %+v", o)
+	}
+	if _, ok := cfg.GameOverrides["Valorant"]; ok {
+		t.Error("invalid override should be dropped")
+	}
+
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.GameOverrides["cs2.exe"].Affinity != 12 {
+		t.Errorf("override not round-tripped: %+v", again.GameOverrides)
+	}
+}
+
+func TestLoadConfig_Schedules(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	dir := filepath.Join(tmpDir, "SysCleaner")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"version": 1, "schedules": [
+		{"name": "weekly", "enabled": true, "cron": "0 3 * * 0", "profile": "gaming", "clean": true, "optimize": ["disk"]},
+		{"name": "broken", "enabled": true, "cron": "every sunday", "clean": true},
+		{"name": "weekly", "enabled": true, "interval": "1h", "clean": true}
+	]}`)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.Schedules) != 1 {
+		t.Fatalf("got %d schedules, want 1 (invalid and duplicate dropped): %+v", len(cfg.Schedules), cfg.Schedules)
+	}
+	s := cfg.Schedules[0]
+	if s.Cron != "0 3 * * 0" || s.Profile != "gaming" || !s.Clean || !s.Runs("disk") {
+		t.Errorf("unexpected schedule %+v", s)
+	}
+
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Schedules) != 1 || again.Schedules[0].Name != "weekly" {
+		t.Errorf("schedules not round-tripped: %+v", again.Schedules)
+	}
+}
+
+func TestPowerProfileSettings_ProfileFor(t *testing.T) {
+	s := PowerProfileSettings{OnAC: "gaming", OnBattery: "battery", Docked: "work"}
+	tests := []struct {
+		status sysinfo.PowerStatus
+		want   string
+	}{
+		{sysinfo.PowerStatus{Known: true}, "gaming"},
+		{sysinfo.PowerStatus{Known: true, OnBattery: true, DockKnown: true, Docked: true}, "battery"},
+		{sysinfo.PowerStatus{Known: true, DockKnown: true, Docked: true}, "work"},
+		{sysinfo.PowerStatus{Known: true, DockKnown: true}, "gaming"},
+		{sysinfo.PowerStatus{}, ""},
+	}
+	for _, tt := range tests {
+		if got := s.ProfileFor(tt.status); got != tt.want {
+			t.Errorf("ProfileFor(%+v) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+	if got := (PowerProfileSettings{OnAC: "gaming"}).ProfileFor(sysinfo.PowerStatus{Known: true, OnBattery: true}); got != "" {
+		t.Errorf("no battery profile: got %q, want no switch", got)
+	}
+}
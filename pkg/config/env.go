@@ -0,0 +1,229 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/sysrestore"
+)
+
+// EnvPrefix starts the names of environment variables that override config
+// values, e.g. SYSCLEANER_DRY_RUN=1 or SYSCLEANER_LOG_LEVEL=debug.
+const EnvPrefix = "SYSCLEANER_"
+
+// envOverride maps one environment variable onto a config field. get and
+// set use the same string form so the file's value can be put back.
+type envOverride struct {
+	name string // without EnvPrefix
+	get  func(c *Config) string
+	set  func(c *Config, v string) error
+}
+
+// appliedOverride is an override LoadConfig applied: the value before it
+// and the value the environment or policy replaced it with.
+type appliedOverride struct {
+	override  *envOverride
+	fileValue string
+	envValue  string
+	// policy marks a value from the policy key (see PolicyKey) rather
+	// than the environment.
+	policy bool
+}
+
+var envOverrides = []envOverride{
+	{"DRY_RUN",
+		func(c *Config) string { return strconv.FormatBool(c.Cleaner.DryRun) },
+		func(c *Config, v string) error { return setEnvBool(&c.Cleaner.DryRun, v) }},
+	{"AUDIT",
+		func(c *Config) string { return strconv.FormatBool(c.AuditMode) },
+		func(c *Config, v string) error { return setEnvBool(&c.AuditMode, v) }},
+	{"LOG_LEVEL",
+		func(c *Config) string { return c.LogLevel },
+		func(c *Config, v string) error {
+			if _, err := logger.ParseLevel(v); err != nil {
+				return err
+			}
+			c.LogLevel = strings.ToLower(v)
+			return nil
+		}},
+	{"REDACT_LOGS",
+		func(c *Config) string { return strconv.FormatBool(c.RedactLogs) },
+		func(c *Config, v string) error { return setEnvBool(&c.RedactLogs, v) }},
+	{"ACTIVE_PROFILE",
+		func(c *Config) string { return c.ActiveProfile },
+		func(c *Config, v string) error {
+			if err := ValidateProfileName(v); err != nil {
+				return err
+			}
+			c.ActiveProfile = v
+			return nil
+		}},
+	{"REGISTRY_LOGGING",
+		func(c *Config) string { return strconv.FormatBool(c.RegistryLogging) },
+		func(c *Config, v string) error { return setEnvBool(&c.RegistryLogging, v) }},
+	{"QUARANTINE",
+		func(c *Config) string { return strconv.FormatBool(c.Cleaner.Quarantine) },
+		func(c *Config, v string) error { return setEnvBool(&c.Cleaner.Quarantine, v) }},
+	{"CLEANER_WORKERS",
+		func(c *Config) string { return strconv.Itoa(c.Performance.CleanerWorkers) },
+		func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("want a positive number, got %q", v)
+			}
+			c.Performance.CleanerWorkers = n
+			return nil
+		}},
+	{"CLEANER_IO_PRIORITY",
+		func(c *Config) string { return c.Performance.CleanerIOPriority },
+		func(c *Config, v string) error {
+			v = strings.ToLower(v)
+			if v != "normal" && v != "low" {
+				return fmt.Errorf("want normal or low, got %q", v)
+			}
+			c.Performance.CleanerIOPriority = v
+			return nil
+		}},
+	{"RAM_FREE_THRESHOLD",
+		func(c *Config) string { return formatEnvFloat(c.RAMMonitor.FreeThresholdPercent) },
+		func(c *Config, v string) error { return setEnvPercent(&c.RAMMonitor.FreeThresholdPercent, v) }},
+	{"RAM_STANDBY_THRESHOLD",
+		func(c *Config) string { return formatEnvFloat(c.RAMMonitor.StandbyThresholdPercent) },
+		func(c *Config, v string) error { return setEnvPercent(&c.RAMMonitor.StandbyThresholdPercent, v) }},
+	{"LOW_DISK",
+		func(c *Config) string { return strconv.FormatBool(c.LowDisk.Enabled) },
+		func(c *Config, v string) error { return setEnvBool(&c.LowDisk.Enabled, v) }},
+	{"EXCLUSIONS",
+		func(c *Config) string { return strings.Join(c.Cleaner.Exclusions, ";") },
+		func(c *Config, v string) error {
+			c.Cleaner.Exclusions = splitEnvList(v)
+			return nil
+		}},
+	{"DISABLED_CATEGORIES",
+		func(c *Config) string { return strings.Join(c.Cleaner.DisabledCategories, ";") },
+		func(c *Config, v string) error {
+			list := splitEnvList(v)
+			var opts cleaner.CleanOptions
+			for _, name := range list {
+				if err := opts.Select(cleaner.Category(name)); err != nil {
+					return err
+				}
+			}
+			c.Cleaner.DisabledCategories = list
+			return nil
+		}},
+}
+
+// applyEnvOverrides layers SYSCLEANER_* variables over cfg. Invalid values
+// are logged and ignored.
+func applyEnvOverrides(cfg *Config, lookup func(string) (string, bool)) {
+	for i := range envOverrides {
+		o := &envOverrides[i]
+		if v, ok := lookup(EnvPrefix + o.name); ok {
+			applyOverride(cfg, o, v, false)
+		}
+	}
+}
+
+// applyOverride sets o's field to v and records the previous value.
+func applyOverride(cfg *Config, o *envOverride, v string, policy bool) {
+	before := o.get(cfg)
+	if err := o.set(cfg, strings.TrimSpace(v)); err != nil {
+		if policy {
+			log.Printf("[SysCleaner] Ignoring policy %s: %v", policyValueName(o.name), err)
+		} else {
+			log.Printf("[SysCleaner] Ignoring %s%s: %v", EnvPrefix, o.name, err)
+		}
+		return
+	}
+	cfg.envOverrides = append(cfg.envOverrides, appliedOverride{override: o, fileValue: before, envValue: o.get(cfg), policy: policy})
+}
+
+// withoutOverrides returns a copy of cfg with each overridden field put
+// back to its file value. Environment values are kept if the field was
+// changed since loading; policy values never reach the file.
+func withoutOverrides(cfg *Config) *Config {
+	if len(cfg.envOverrides) == 0 {
+		return cfg
+	}
+	c := *cfg
+	// Undo in reverse so a field overridden by both the environment and
+	// policy ends up back at the file's value.
+	for i := len(cfg.envOverrides) - 1; i >= 0; i-- {
+		a := cfg.envOverrides[i]
+		if a.policy || a.override.get(&c) == a.envValue {
+			_ = a.override.set(&c, a.fileValue)
+		}
+	}
+	return &c
+}
+
+// EnvOverrides returns the SYSCLEANER_* variables applied to c, as
+// NAME=value.
+func (c *Config) EnvOverrides() []string {
+	out := make([]string, 0, len(c.envOverrides))
+	for _, a := range c.envOverrides {
+		if !a.policy {
+			out = append(out, EnvPrefix+a.override.name+"="+a.envValue)
+		}
+	}
+	return out
+}
+
+// ApplyLogLevel sets the minimum level written by pkg/logger.
+func (c *Config) ApplyLogLevel() {
+	if level, err := logger.ParseLevel(c.LogLevel); err == nil {
+		logger.SetLevel(level)
+	}
+}
+
+// ApplyLogRedaction turns redaction of user names and profile paths in
+// logs and reports on or off as RedactLogs says.
+func (c *Config) ApplyLogRedaction() {
+	logger.SetRedaction(c.RedactLogs)
+}
+
+// ApplyRestorePoints turns the System Restore points made before extreme
+// mode and risky optimizations on or off as SkipRestorePoints says.
+func (c *Config) ApplyRestorePoints() {
+	sysrestore.SetAutomatic(!c.SkipRestorePoints)
+}
+
+func setEnvBool(dst *bool, v string) error {
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "on":
+		*dst = true
+	case "0", "false", "no", "off", "":
+		*dst = false
+	default:
+		return fmt.Errorf("want 1/0 or true/false, got %q", v)
+	}
+	return nil
+}
+
+func setEnvPercent(dst *float64, v string) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 || f > 100 {
+		return fmt.Errorf("want a percentage between 0 and 100, got %q", v)
+	}
+	*dst = f
+	return nil
+}
+
+func formatEnvFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func splitEnvList(v string) []string {
+	list := []string{}
+	for _, item := range strings.Split(v, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
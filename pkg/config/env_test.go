@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"SYSCLEANER_DRY_RUN":             "1",
+		"SYSCLEANER_LOG_LEVEL":           "DEBUG",
+		"SYSCLEANER_CLEANER_WORKERS":     "3",
+		"SYSCLEANER_RAM_FREE_THRESHOLD":  "25",
+		"SYSCLEANER_EXCLUSIONS":          `C:\Keep\*; D:\Games\*`,
+		"SYSCLEANER_CLEANER_IO_PRIORITY": "turbo", // invalid, ignored
+	}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	cfg := DefaultConfig()
+	applyEnvOverrides(cfg, lookup)
+
+	if !cfg.Cleaner.DryRun {
+		t.Error("DryRun not set from SYSCLEANER_DRY_RUN")
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", cfg.LogLevel)
+	}
+	if cfg.Performance.CleanerWorkers != 3 {
+		t.Errorf("CleanerWorkers = %d, want 3", cfg.Performance.CleanerWorkers)
+	}
+	if cfg.RAMMonitor.FreeThresholdPercent != 25 {
+		t.Errorf("FreeThresholdPercent = %v, want 25", cfg.RAMMonitor.FreeThresholdPercent)
+	}
+	if len(cfg.Cleaner.Exclusions) != 2 || cfg.Cleaner.Exclusions[1] != `D:\Games\*` {
+		t.Errorf("Exclusions = %q", cfg.Cleaner.Exclusions)
+	}
+	if cfg.Performance.CleanerIOPriority != "normal" {
+		t.Errorf("CleanerIOPriority = %q, want invalid override ignored", cfg.Performance.CleanerIOPriority)
+	}
+	if got := len(cfg.EnvOverrides()); got != 5 {
+		t.Errorf("EnvOverrides() = %v, want 5 entries", cfg.EnvOverrides())
+	}
+}
+
+func TestSaveConfig_DoesNotPersistEnvOverrides(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SYSCLEANER_DRY_RUN", "1")
+	t.Setenv("SYSCLEANER_ACTIVE_PROFILE", "gaming")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Cleaner.DryRun || cfg.ActiveProfile != "gaming" {
+		t.Fatalf("overrides not applied: dry run %v, profile %q", cfg.Cleaner.DryRun, cfg.ActiveProfile)
+	}
+
+	// A field the user changes after loading is saved; untouched
+	// overridden fields keep their file values.
+	cfg.ActiveProfile = "work"
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	path, _ := configFilePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d configData
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Cleaner.DryRun {
+		t.Error("SYSCLEANER_DRY_RUN was written to the config file")
+	}
+	if d.ActiveProfile != "work" {
+		t.Errorf("active_profile = %q, want the user's change saved", d.ActiveProfile)
+	}
+}
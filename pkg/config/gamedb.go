@@ -0,0 +1,30 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"log"
+
+	"syscleaner/pkg/gaming"
+)
+
+// GameDatabaseSettings controls where the game database comes from.
+type GameDatabaseSettings struct {
+	// Keys are base64 Ed25519 public keys trusted to sign game databases.
+	// With none, only the built-in database is used.
+	Keys []string `json:"keys"`
+	// URL is where "syscleaner games --update" downloads newer databases.
+	URL string `json:"url,omitempty"`
+}
+
+// TrustedKeys returns the valid keys in s.Keys.
+func (s GameDatabaseSettings) TrustedKeys() []ed25519.PublicKey {
+	return publicKeys(s.Keys, "game database")
+}
+
+// Apply switches the gaming package to a newer signed game database in the
+// config directory, if there is one.
+func (s GameDatabaseSettings) Apply() {
+	if err := gaming.LoadGameDatabase(gaming.GameDatabasePath(), s.TrustedKeys()); err != nil {
+		log.Printf("[SysCleaner] Ignoring game database: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/gaming"
+)
+
+// ExportGameProfile writes the game override for game to path as a shared
+// game profile (see gaming.SharedProfile). author and notes are included
+// for whoever imports it.
+func ExportGameProfile(game, author, notes, path string) error {
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	key, o, ok := findGameOverride(cfg, game)
+	if !ok {
+		return fmt.Errorf("no game override for %s", game)
+	}
+	p := gaming.NewSharedProfile(key, o)
+	p.Author = author
+	p.Notes = notes
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling game profile: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing game profile: %w", err)
+	}
+	return nil
+}
+
+// ImportGameProfile checks the shared game profile at path and saves its
+// settings as the game's override, replacing any the game already has.
+// Profiles that stop anti-cheat, audio or input services are refused, as
+// are ones with high-risk settings such as pre-launch tools unless
+// allowHigh is set. The profile and its risks are returned either way.
+func ImportGameProfile(path string, allowHigh bool) (gaming.SharedProfile, []gaming.ProfileRisk, error) {
+	if err := admin.RequireWriteAccess("importing a game profile"); err != nil {
+		return gaming.SharedProfile{}, nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return gaming.SharedProfile{}, nil, fmt.Errorf("reading game profile: %w", err)
+	}
+	p, err := gaming.ParseSharedProfile(data)
+	if err != nil {
+		return p, nil, err
+	}
+	risks := p.Risks()
+	if blocking := gaming.Blocking(risks, allowHigh); len(blocking) > 0 {
+		reasons := make([]string, len(blocking))
+		for i, r := range blocking {
+			reasons[i] = r.Reason
+		}
+		return p, risks, fmt.Errorf("game profile for %s was not imported: it %s", p.Game, strings.Join(reasons, "; "))
+	}
+
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return p, risks, err
+	}
+	if key, _, ok := findGameOverride(cfg, p.Game); ok {
+		delete(cfg.GameOverrides, key)
+	}
+	if cfg.GameOverrides == nil {
+		cfg.GameOverrides = make(map[string]gaming.GameOverride)
+	}
+	cfg.GameOverrides[p.Game] = p.Settings
+	return p, risks, SaveConfig(cfg)
+}
+
+// findGameOverride looks up a game override by key, ignoring case as
+// gaming.OverrideFor does.
+func findGameOverride(cfg *Config, game string) (string, gaming.GameOverride, bool) {
+	for key, o := range cfg.GameOverrides {
+		if strings.EqualFold(key, game) {
+			return key, o, true
+		}
+	}
+	return "", gaming.GameOverride{}, false
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/launcher"
+)
+
+func TestGameProfile_RoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.GameOverrides = map[string]gaming.GameOverride{
+		"cs2.exe": {Priority: "high", Affinity: 0xF, StopServices: []string{"DiagTrack"}},
+	}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cs2.json")
+	if err := ExportGameProfile("CS2.EXE", "someone", "tuned for 240 Hz", path); err != nil {
+		t.Fatalf("ExportGameProfile: %v", err)
+	}
+	if err := ExportGameProfile("missing.exe", "", "", path+".2"); err == nil {
+		t.Error("exporting a game without an override should fail")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	p, _, err := ImportGameProfile(path, false)
+	if err != nil {
+		t.Fatalf("ImportGameProfile: %v", err)
+	}
+	if p.Author != "someone" {
+		t.Errorf("author = %q", p.Author)
+	}
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := got.GameOverrides["cs2.exe"]; o.Affinity != 0xF || len(o.StopServices) != 1 {
+		t.Errorf("override not imported: %+v", got.GameOverrides)
+	}
+}
+
+func TestImportGameProfile_Risky(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.GameOverrides = map[string]gaming.GameOverride{
+		"game.exe": {PreLaunch: []launcher.Action{{Command: "tweak.exe"}}},
+	}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "game.json")
+	if err := ExportGameProfile("game.exe", "", "", path); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveConfig(DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, risks, err := ImportGameProfile(path, false); err == nil || len(risks) != 1 {
+		t.Fatalf("pre-launch tools imported without allowHigh: risks %v, err %v", risks, err)
+	}
+	if got, _ := LoadConfig(); len(got.GameOverrides) != 0 {
+		t.Errorf("refused profile was saved: %+v", got.GameOverrides)
+	}
+	if _, _, err := ImportGameProfile(path, true); err != nil {
+		t.Errorf("ImportGameProfile with allowHigh: %v", err)
+	}
+}
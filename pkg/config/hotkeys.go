@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"syscleaner/pkg/hotkey"
+)
+
+// HotkeySettings assigns system-wide shortcuts such as "Ctrl+Alt+G" to
+// actions, so they can be run without leaving a game. An empty string
+// leaves an action without a hotkey.
+type HotkeySettings struct {
+	ToggleGaming  string `json:"toggle_gaming,omitempty"`
+	ToggleExtreme string `json:"toggle_extreme,omitempty"`
+	PurgeRAM      string `json:"purge_ram,omitempty"`
+}
+
+// HotkeyActions are the functions the hotkeys in HotkeySettings run.
+type HotkeyActions struct {
+	ToggleGaming  func()
+	ToggleExtreme func()
+	PurgeRAM      func()
+}
+
+// Bindings pairs each configured hotkey with its action. Hotkeys that do
+// not parse are reported together in the error and left out.
+func (s HotkeySettings) Bindings(actions HotkeyActions) ([]hotkey.Binding, error) {
+	var bindings []hotkey.Binding
+	var errs []error
+	for _, h := range []struct {
+		keys, name string
+		action     func()
+	}{
+		{s.ToggleGaming, "toggle gaming mode", actions.ToggleGaming},
+		{s.ToggleExtreme, "toggle extreme mode", actions.ToggleExtreme},
+		{s.PurgeRAM, "purge RAM", actions.PurgeRAM},
+	} {
+		if h.keys == "" || h.action == nil {
+			continue
+		}
+		keys, err := hotkey.Parse(h.keys)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hotkeys: %w", err))
+			continue
+		}
+		bindings = append(bindings, hotkey.Binding{Keys: keys, Name: h.name, Action: h.action})
+	}
+	return bindings, errors.Join(errs...)
+}
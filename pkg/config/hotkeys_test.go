@@ -0,0 +1,15 @@
+package config
+
+import "testing"
+
+func TestHotkeySettings_Bindings(t *testing.T) {
+	s := HotkeySettings{ToggleGaming: "Ctrl+Alt+G", ToggleExtreme: "G", PurgeRAM: "Ctrl+Alt+R"}
+	noop := func() {}
+	bindings, err := s.Bindings(HotkeyActions{ToggleGaming: noop, ToggleExtreme: noop, PurgeRAM: noop})
+	if err == nil {
+		t.Error("expected an error for the hotkey without Ctrl, Alt or Win")
+	}
+	if len(bindings) != 2 || bindings[1].Name != "purge RAM" {
+		t.Errorf("bindings = %+v, want the two valid hotkeys", bindings)
+	}
+}
@@ -0,0 +1,370 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Setting is one config value addressed by a dotted key of JSON names,
+// e.g. ram_monitor.free_threshold_percent.
+type Setting struct {
+	Key string
+	// Value is the JSON encoding of the value. Secrets are masked.
+	Value string
+	// Source is where the value came from: "file", "environment" or
+	// "policy".
+	Source string
+}
+
+// overrideKeys maps the dotted key of each setting that SYSCLEANER_*
+// variables and policy can override to the override's name, so values set
+// by key get the same checks.
+var overrideKeys = map[string]string{
+	"cleaner.dry_run":                       "DRY_RUN",
+	"audit_mode":                            "AUDIT",
+	"log_level":                             "LOG_LEVEL",
+	"redact_logs":                           "REDACT_LOGS",
+	"active_profile":                        "ACTIVE_PROFILE",
+	"registry_logging":                      "REGISTRY_LOGGING",
+	"cleaner.quarantine":                    "QUARANTINE",
+	"performance.cleaner_workers":           "CLEANER_WORKERS",
+	"performance.cleaner_io_priority":       "CLEANER_IO_PRIORITY",
+	"ram_monitor.free_threshold_percent":    "RAM_FREE_THRESHOLD",
+	"ram_monitor.standby_threshold_percent": "RAM_STANDBY_THRESHOLD",
+	"low_disk.enabled":                      "LOW_DISK",
+	"cleaner.exclusions":                    "EXCLUSIONS",
+	"cleaner.disabled_categories":           "DISABLED_CATEGORIES",
+}
+
+// Settings returns every value in c as dotted keys, sorted. Lists and
+// empty objects are single values.
+func (c *Config) Settings() ([]Setting, error) {
+	raw, err := configFields(c)
+	if err != nil {
+		return nil, err
+	}
+	var out []Setting
+	flattenFields("", raw, func(key string, v any) {
+		out = append(out, c.newSetting(key, v))
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Setting returns the value at key. Keys match case-insensitively and
+// ignoring underscores, so ramMonitor.freeThresholdPercent also works. A key
+// naming an object returns the whole object.
+func (c *Config) Setting(key string) (Setting, error) {
+	raw, err := configFields(c)
+	if err != nil {
+		return Setting{}, err
+	}
+	path, err := resolveKey(raw, key)
+	if err != nil {
+		return Setting{}, err
+	}
+	return c.newSetting(strings.Join(path, "."), lookupPath(raw, path)), nil
+}
+
+// SetSetting changes the value at key in the config file and saves it.
+// value is JSON, except that strings may be given bare and lists of
+// strings as comma-separated items. The result is checked as LoadConfig
+// would check the file; values LoadConfig would drop or replace are
+// refused. SYSCLEANER_* variables and policy still take precedence over
+// the saved value.
+func SetSetting(key, value string) (Setting, error) {
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return Setting{}, err
+	}
+	raw, err := configFields(cfg)
+	if err != nil {
+		return Setting{}, err
+	}
+	path, err := resolveKey(raw, key)
+	if err != nil {
+		return Setting{}, err
+	}
+	canonical := strings.Join(path, ".")
+
+	var v any = value
+	if path[0] != "secrets" || len(path) != 2 {
+		if v, err = parseSettingValue(lookupPath(raw, path), value); err != nil {
+			return Setting{}, fmt.Errorf("%s: %w", canonical, err)
+		}
+	}
+	if name, ok := overrideKeys[canonical]; ok {
+		if err := checkOverrideValue(name, v); err != nil {
+			return Setting{}, fmt.Errorf("%s: %w", canonical, err)
+		}
+	}
+	setPath(raw, path, v)
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return Setting{}, fmt.Errorf("marshaling config: %w", err)
+	}
+	var d configData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return Setting{}, fmt.Errorf("%s: invalid value: %w", canonical, err)
+	}
+	updated := fromConfigData(d)
+	updated.machine = cfg.machine
+
+	if path[0] != "secrets" {
+		check, err := configFields(updated)
+		if err != nil {
+			return Setting{}, err
+		}
+		want, _ := json.Marshal(v)
+		got, _ := json.Marshal(lookupPath(check, path))
+		if !bytes.Equal(want, got) {
+			return Setting{}, fmt.Errorf("%s: value %s was not accepted (it would load as %s; see the log for details)", canonical, want, got)
+		}
+	}
+
+	if err := SaveConfig(updated); err != nil {
+		return Setting{}, err
+	}
+	return updated.newSetting(canonical, v), nil
+}
+
+// newSetting builds the Setting for key, masking secrets and noting
+// environment and policy overrides.
+func (c *Config) newSetting(key string, v any) Setting {
+	s := Setting{Key: key, Source: "file"}
+	if key == "secrets" || strings.HasPrefix(key, "secrets.") {
+		v = maskSecrets(v)
+	}
+	data, _ := json.Marshal(v)
+	s.Value = string(data)
+	if name, ok := overrideKeys[key]; ok {
+		for _, a := range c.envOverrides {
+			if a.override.name != name {
+				continue
+			}
+			if a.policy {
+				s.Source = "policy"
+			} else {
+				s.Source = "environment"
+			}
+		}
+	}
+	return s
+}
+
+func maskSecrets(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, sv := range t {
+			out[k] = maskSecrets(sv)
+		}
+		return out
+	case string:
+		return Secret(t).String()
+	}
+	return v
+}
+
+// configFields returns c as it would be written to the file, as a generic
+// JSON object. Secrets are kept in plain text.
+func configFields(c *Config) (map[string]any, error) {
+	d := toConfigData(c)
+	secrets := d.Secrets
+	d.Secrets = nil
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling config: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("marshaling config: %w", err)
+	}
+	plain := make(map[string]any, len(secrets))
+	for name, s := range secrets {
+		plain[name] = s.Reveal()
+	}
+	raw["secrets"] = plain
+	return raw, nil
+}
+
+func flattenFields(prefix string, v any, emit func(string, any)) {
+	obj, ok := v.(map[string]any)
+	if !ok || (len(obj) == 0 && prefix != "") {
+		emit(prefix, v)
+		return
+	}
+	for k, sub := range obj {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		flattenFields(key, sub, emit)
+	}
+}
+
+// normalizeKey folds case and drops underscores and dashes so camelCase
+// and snake_case spellings of a key match.
+func normalizeKey(s string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+}
+
+// openSections are the top-level objects keyed by user-chosen names, where
+// SetSetting may add entries.
+var openSections = map[string]bool{"game_overrides": true, "secrets": true}
+
+// resolveKey returns the path of actual JSON names that key refers to.
+// Map keys containing dots, such as "cs2.exe", are matched by trying the
+// longest run of segments first.
+func resolveKey(raw map[string]any, key string) ([]string, error) {
+	segs := strings.Split(key, ".")
+	var path []string
+	var cur any = raw
+	for i := 0; i < len(segs); {
+		obj, ok := cur.(map[string]any)
+		if !ok && cur != nil {
+			return nil, fmt.Errorf("unknown config key %q: %s is not an object", key, strings.Join(path, "."))
+		}
+		found := false
+		for j := len(segs); j > i && !found; j-- {
+			want := normalizeKey(strings.Join(segs[i:j], "."))
+			for name, v := range obj {
+				if normalizeKey(name) == want {
+					path = append(path, name)
+					cur, i, found = v, j, true
+					break
+				}
+			}
+		}
+		if !found && len(path) == 1 && openSections[path[0]] {
+			// A new entry in a section keyed by name.
+			path = append(path, strings.Join(segs[i:], "."))
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("config key is empty")
+	}
+	return path, nil
+}
+
+func lookupPath(raw map[string]any, path []string) any {
+	var cur any = raw
+	for _, name := range path {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = obj[name]
+	}
+	return cur
+}
+
+func setPath(raw map[string]any, path []string, v any) {
+	obj := raw
+	for _, name := range path[:len(path)-1] {
+		next, ok := obj[name].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			obj[name] = next
+		}
+		obj = next
+	}
+	obj[path[len(path)-1]] = v
+}
+
+// parseSettingValue decodes value for a key whose current value is old,
+// requiring the same JSON type.
+func parseSettingValue(old any, value string) (any, error) {
+	var v any
+	jsonErr := json.Unmarshal([]byte(value), &v)
+
+	switch old.(type) {
+	case string:
+		if s, ok := v.(string); ok && jsonErr == nil {
+			return s, nil
+		}
+		return value, nil
+	case nil:
+		if jsonErr == nil {
+			return v, nil
+		}
+		return splitSettingList(value), nil
+	case []any:
+		if _, ok := v.([]any); jsonErr == nil && (ok || v == nil) {
+			return v, nil
+		}
+		return splitSettingList(value), nil
+	}
+
+	if jsonErr != nil {
+		return nil, fmt.Errorf("invalid value %q: %w", value, jsonErr)
+	}
+	if !sameJSONType(old, v) {
+		return nil, fmt.Errorf("want a %s, got %q", jsonTypeName(old), value)
+	}
+	return v, nil
+}
+
+// splitSettingList turns "a.exe, b.exe" into a list of strings.
+func splitSettingList(value string) []any {
+	items := []any{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func sameJSONType(a, b any) bool {
+	return jsonTypeName(a) == jsonTypeName(b)
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case bool:
+		return "true/false value"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "JSON object"
+	}
+	return "value"
+}
+
+// checkOverrideValue runs v through the checks the SYSCLEANER_* variable
+// name applies.
+func checkOverrideValue(name string, v any) error {
+	var text string
+	switch t := v.(type) {
+	case string:
+		text = t
+	case []any:
+		items := make([]string, len(t))
+		for i, item := range t {
+			items[i] = fmt.Sprint(item)
+		}
+		text = strings.Join(items, ";")
+	default:
+		data, _ := json.Marshal(t)
+		text = string(data)
+	}
+	for i := range envOverrides {
+		if envOverrides[i].name == name {
+			return envOverrides[i].set(&Config{}, text)
+		}
+	}
+	return nil
+}
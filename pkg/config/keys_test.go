@@ -0,0 +1,94 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetSetting(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	s, err := SetSetting("ramMonitor.freeThresholdPercent", "20")
+	if err != nil {
+		t.Fatalf("SetSetting: %v", err)
+	}
+	if s.Key != "ram_monitor.free_threshold_percent" || s.Value != "20" {
+		t.Errorf("setting = %+v", s)
+	}
+	if _, err := SetSetting("log_level", "debug"); err != nil {
+		t.Fatalf("SetSetting(log_level): %v", err)
+	}
+	if _, err := SetSetting("process_whitelist", "obs64.exe, Discord.exe"); err != nil {
+		t.Fatalf("SetSetting(process_whitelist): %v", err)
+	}
+	if _, err := SetSetting("game_overrides.cs2.exe", `{"priority": "above normal"}`); err != nil {
+		t.Fatalf("SetSetting(game_overrides): %v", err)
+	}
+	if _, err := SetSetting("secrets.webhook", "https://example.invalid/hook"); err != nil {
+		t.Fatalf("SetSetting(secrets): %v", err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.RAMMonitor.FreeThresholdPercent != 20 || cfg.LogLevel != "debug" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if len(cfg.ProcessWhitelist) != 2 || cfg.ProcessWhitelist[1] != "Discord.exe" {
+		t.Errorf("whitelist = %v", cfg.ProcessWhitelist)
+	}
+	if cfg.GameOverrides["cs2.exe"].Priority != "above normal" {
+		t.Errorf("game overrides = %+v", cfg.GameOverrides)
+	}
+	if cfg.Secrets["webhook"].Reveal() != "https://example.invalid/hook" {
+		t.Error("secret not saved")
+	}
+
+	got, err := cfg.Setting("game_overrides.cs2.exe.priority")
+	if err != nil || got.Value != `"above normal"` {
+		t.Errorf("Setting(game_overrides.cs2.exe.priority) = %+v, %v", got, err)
+	}
+	if got, _ := cfg.Setting("secrets.webhook"); strings.Contains(got.Value, "example") {
+		t.Errorf("secret not masked: %s", got.Value)
+	}
+
+	for key, value := range map[string]string{
+		"ram_monitor.free_threshold_percent": "250",
+		"log_level":                          "loud",
+		"low_disk.enabled":                   "maybe",
+		"game_overrides.cs2.exe":             `{"priority": "realtime"}`,
+		"version":                            "99",
+		"no_such_key":                        "1",
+	} {
+		if _, err := SetSetting(key, value); err == nil {
+			t.Errorf("SetSetting(%s, %s) succeeded, want error", key, value)
+		}
+	}
+}
+
+func TestSettings_ReportsOverrideSource(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SYSCLEANER_LOG_LEVEL", "warn")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := cfg.Settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range settings {
+		if s.Key == "log_level" {
+			found = true
+			if s.Value != `"warn"` || s.Source != "environment" {
+				t.Errorf("log_level = %+v, want warn from environment", s)
+			}
+		}
+	}
+	if !found {
+		t.Error("log_level not listed")
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"syscleaner/pkg/logger"
+)
+
+// LogShippingSettings forwards log output to a central syslog server or
+// HTTPS collector, for operators who gather logs from many machines.
+type LogShippingSettings struct {
+	Enabled bool   `json:"enabled"`
+	Kind    string `json:"kind"`    // "syslog" or "http"
+	Address string `json:"address"` // host:port for syslog, an https URL for http
+	Network string `json:"network"` // syslog only: "udp" (default), "tcp" or "tls"
+	// TokenSecret names the entry in Secrets sent as the bearer token to
+	// an http endpoint.
+	TokenSecret  string `json:"token_secret,omitempty"`
+	BatchSize    int    `json:"batch_size"`    // entries per request; 0 uses the default
+	FlushSeconds int    `json:"flush_seconds"` // longest wait before sending; 0 uses the default
+}
+
+// Sink builds the logger.Sink the settings describe, taking the bearer
+// token from secrets.
+func (s LogShippingSettings) Sink(secrets map[string]Secret) (logger.Sink, error) {
+	switch s.Kind {
+	case "syslog":
+		network := s.Network
+		if network == "" {
+			network = "udp"
+		}
+		if network != "udp" && network != "tcp" && network != "tls" {
+			return nil, fmt.Errorf("log_shipping: unknown network %q (want udp, tcp or tls)", s.Network)
+		}
+		if s.Address == "" {
+			return nil, fmt.Errorf("log_shipping: address is empty")
+		}
+		return logger.SyslogSink{Network: network, Addr: s.Address}, nil
+	case "http":
+		var token string
+		if s.TokenSecret != "" {
+			secret, ok := secrets[s.TokenSecret]
+			if !ok {
+				return nil, fmt.Errorf("log_shipping: secret %q not found", s.TokenSecret)
+			}
+			token = secret.Reveal()
+		}
+		sink, err := logger.NewHTTPSink(s.Address, token)
+		if err != nil {
+			return nil, fmt.Errorf("log_shipping: %w", err)
+		}
+		return sink, nil
+	}
+	return nil, fmt.Errorf("log_shipping: unknown kind %q (want syslog or http)", s.Kind)
+}
+
+// ApplyLogShipping starts or stops forwarding log output as LogShipping
+// says, replacing any shipping set up before. Both pkg/logger and the
+// standard log package are forwarded; the standard log keeps writing to
+// stderr as well. Call logger.SetShipper(nil) before exiting to send what
+// is still buffered.
+func (c *Config) ApplyLogShipping() error {
+	if !c.LogShipping.Enabled {
+		logger.CaptureStandardLog(os.Stderr)
+		return logger.SetShipper(nil)
+	}
+	sink, err := c.LogShipping.Sink(c.Secrets)
+	if err != nil {
+		return err
+	}
+	s := logger.NewShipper(sink)
+	if c.LogShipping.BatchSize > 0 {
+		s.BatchSize = c.LogShipping.BatchSize
+	}
+	if c.LogShipping.FlushSeconds > 0 {
+		s.FlushInterval = time.Duration(c.LogShipping.FlushSeconds) * time.Second
+	}
+	s.Start()
+	logger.CaptureStandardLog(io.MultiWriter(os.Stderr, s))
+	return logger.SetShipper(s)
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// MachineConfigPath returns the machine-wide config file,
+// %ProgramData%\SysCleaner\config.yaml, or "" if ProgramData is not set.
+//
+// The machine config has the same format as the per-user file and is
+// usually written by an administrator. Its values are the defaults for
+// every user: a user's own file only overrides the settings it changes,
+// and SaveConfig writes back only what differs from the machine config.
+// The lists in enforcedLists are combined instead, so machine entries
+// (e.g. a whitelisted process or a disabled category) cannot be removed by
+// users. To lock a setting entirely, use policy (see PolicyKey).
+func MachineConfigPath() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		return ""
+	}
+	return filepath.Join(programData, "SysCleaner", configFileName)
+}
+
+// enforcedLists are the list settings where machine entries are always
+// kept; users can add entries but not remove the machine's.
+var enforcedLists = [][]string{
+	{"process_whitelist"},
+	{"cleaner", "exclusions"},
+	{"cleaner", "disabled_categories"},
+}
+
+// readMachineConfig returns the machine config as a JSON object, migrated
+// to the current version, or nil if there is none.
+func readMachineConfig() (map[string]any, error) {
+	path := MachineConfigPath()
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading machine config file: %w", err)
+	}
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("machine config: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(migrated, &raw); err != nil {
+		return nil, fmt.Errorf("parsing machine config file: %w", err)
+	}
+	delete(raw, "version")
+	return raw, nil
+}
+
+// layerMachineConfig returns the user config data laid over the machine
+// config. user is nil when the user has no config file, in which case the
+// machine config is laid over the defaults.
+func layerMachineConfig(machine map[string]any, user []byte) ([]byte, error) {
+	base, err := defaultFields()
+	if err != nil {
+		return nil, err
+	}
+	merged := mergeFields(base, machine)
+	if user != nil {
+		var raw map[string]any
+		if err := json.Unmarshal(user, &raw); err != nil {
+			return nil, fmt.Errorf("parsing config file: %w", err)
+		}
+		merged = mergeFields(merged, raw)
+	}
+	for _, path := range enforcedLists {
+		list := unionLists(asList(lookupPath(merged, path)), asList(lookupPath(machine, path)))
+		setPath(merged, path, list)
+	}
+	return json.Marshal(merged)
+}
+
+// machineRelativeFields returns the file contents for c when a machine
+// config is present: only the settings that differ from the defaults laid
+// over the machine config, with the machine's entries left out of the
+// enforced lists.
+func machineRelativeFields(c *Config) (map[string]any, error) {
+	data, err := json.Marshal(toConfigData(c))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling config: %w", err)
+	}
+	var user map[string]any
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("marshaling config: %w", err)
+	}
+	for _, path := range enforcedLists {
+		if lookupPath(user, path) != nil {
+			setPath(user, path, subtractList(asList(lookupPath(user, path)), asList(lookupPath(c.machine, path))))
+		}
+	}
+
+	base, err := defaultFields()
+	if err != nil {
+		return nil, err
+	}
+	base = mergeFields(base, c.machine)
+	for _, path := range enforcedLists {
+		setPath(base, path, []any{})
+	}
+	out := diffFields(base, user)
+	out["version"] = CurrentConfigVersion
+	return out, nil
+}
+
+// defaultFields returns DefaultConfig as a JSON object.
+func defaultFields() (map[string]any, error) {
+	data, err := json.Marshal(toConfigData(DefaultConfig()))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling default config: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("marshaling default config: %w", err)
+	}
+	return raw, nil
+}
+
+// mergeFields returns base with over laid on top. Objects are merged key
+// by key; any other value in over replaces the base's.
+func mergeFields(base, over map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(over))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range over {
+		sub, isObj := v.(map[string]any)
+		baseSub, baseIsObj := out[k].(map[string]any)
+		if isObj && baseIsObj {
+			out[k] = mergeFields(baseSub, sub)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func asList(v any) []any {
+	list, _ := v.([]any)
+	return list
+}
+
+// unionLists returns a followed by the entries of b not in it, compared
+// case-insensitively.
+func unionLists(a, b []any) []any {
+	out := append([]any{}, a...)
+	for _, v := range b {
+		if !containsFold(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// subtractList returns the entries of a not in b, compared
+// case-insensitively.
+func subtractList(a, b []any) []any {
+	out := []any{}
+	for _, v := range a {
+		if !containsFold(b, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func containsFold(list []any, v any) bool {
+	s, isString := v.(string)
+	for _, item := range list {
+		if t, ok := item.(string); ok && isString {
+			if strings.EqualFold(s, t) {
+				return true
+			}
+		} else if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
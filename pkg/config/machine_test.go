@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMachineConfig(t *testing.T, data string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("ProgramData", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "SysCleaner"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(MachineConfigPath(), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfig_MachineDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	writeMachineConfig(t, `{
+  "version": 1,
+  "log_level": "warn",
+  "process_whitelist": ["cafe-client.exe"],
+  "cleaner": {"disabled_categories": ["browser"]}
+}`)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Errorf("log level = %q, want machine default warn", cfg.LogLevel)
+	}
+	if len(cfg.ProcessWhitelist) != 1 || cfg.ProcessWhitelist[0] != "cafe-client.exe" {
+		t.Errorf("whitelist = %v", cfg.ProcessWhitelist)
+	}
+
+	// The user changes a preference and tries to drop the machine entries.
+	cfg.LogLevel = "debug"
+	cfg.ProcessWhitelist = []string{"Discord.exe"}
+	cfg.Cleaner.DisabledCategories = nil
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	path, _ := configFilePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "cafe-client") || strings.Contains(string(data), "dry_run") {
+		t.Errorf("user file holds machine or default values:\n%s", data)
+	}
+
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LogLevel != "debug" {
+		t.Errorf("log level = %q, want user value debug", got.LogLevel)
+	}
+	if len(got.ProcessWhitelist) != 2 || got.ProcessWhitelist[1] != "cafe-client.exe" {
+		t.Errorf("whitelist = %v, want user and machine entries", got.ProcessWhitelist)
+	}
+	if len(got.Cleaner.DisabledCategories) != 1 || got.Cleaner.DisabledCategories[0] != "browser" {
+		t.Errorf("disabled categories = %v, want machine entry kept", got.Cleaner.DisabledCategories)
+	}
+
+	// Later machine changes reach settings the user has not changed.
+	writeMachineConfig(t, `{"version": 1, "log_level": "error", "ui_preferences": {"last_active_tab": "gaming"}}`)
+	got, err = LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LogLevel != "debug" || got.UIPreferences.LastActiveTab != "gaming" {
+		t.Errorf("log level = %q, tab = %q; want debug, gaming", got.LogLevel, got.UIPreferences.LastActiveTab)
+	}
+}
+
+func TestLoadConfig_BrokenMachineConfigIgnored(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	writeMachineConfig(t, "{truncated")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.LogLevel != DefaultConfig().LogLevel {
+		t.Errorf("log level = %q, want default", cfg.LogLevel)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CurrentConfigVersion is the config file format written by SaveConfig.
+// Bump it and append to migrations whenever a field is renamed or moved.
+const CurrentConfigVersion = 1
+
+// migration upgrades a decoded config file from version from to from+1.
+// It works on the raw JSON object so fields can be renamed or restructured
+// before the file is decoded into configData.
+type migration struct {
+	from     int
+	describe string
+	apply    func(raw map[string]any) error
+}
+
+// migrations must stay ordered by from, with no gaps.
+var migrations = []migration{
+	{
+		from:     0,
+		describe: "add version field",
+		// Files written before versioning already match the version 1
+		// layout; they only need stamping.
+		apply: func(raw map[string]any) error { return nil },
+	},
+}
+
+// migrateConfig upgrades data to CurrentConfigVersion. It returns the
+// upgraded JSON and the version the file was at. Files from a newer
+// release are returned unchanged.
+func migrateConfig(data []byte) ([]byte, int, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("parsing config file: %w", err)
+	}
+
+	version := 0
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	if version >= CurrentConfigVersion {
+		return data, version, nil
+	}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(raw); err != nil {
+			return nil, version, fmt.Errorf("migrating config from version %d (%s): %w", m.from, m.describe, err)
+		}
+	}
+	raw["version"] = CurrentConfigVersion
+
+	out, err := json.Marshal(raw)
+	if err != nil {
+		return nil, version, fmt.Errorf("marshaling migrated config: %w", err)
+	}
+	return out, version, nil
+}
+
+// backupConfigFile copies the config file at path to path.v<version>.bak
+// before it is rewritten by a migration.
+func backupConfigFile(path string, data []byte, version int) (string, error) {
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return "", fmt.Errorf("backing up config file: %w", err)
+	}
+	return backup, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateConfig_RunsStepsInOrder(t *testing.T) {
+	orig := migrations
+	defer func() { migrations = orig }()
+	migrations = []migration{
+		{from: 0, describe: "stamp", apply: func(raw map[string]any) error { return nil }},
+		{from: 1, describe: "rename whitelist", apply: func(raw map[string]any) error {
+			if v, ok := raw["whitelist"]; ok {
+				raw["process_whitelist"] = v
+				delete(raw, "whitelist")
+			}
+			return nil
+		}},
+	}
+
+	out, version, err := migrateConfig([]byte(`{"whitelist": ["steam.exe"]}`))
+	if err != nil {
+		t.Fatalf("migrateConfig: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("version = %d, want 0", version)
+	}
+	var d configData
+	if err := json.Unmarshal(out, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(d.ProcessWhitelist) != 1 || d.ProcessWhitelist[0] != "steam.exe" {
+		t.Errorf("ProcessWhitelist = %v, want renamed field carried over", d.ProcessWhitelist)
+	}
+	if d.Version != CurrentConfigVersion {
+		t.Errorf("Version = %d, want %d", d.Version, CurrentConfigVersion)
+	}
+}
+
+func TestMigrateConfig_LeavesCurrentAndNewerFilesAlone(t *testing.T) {
+	for _, in := range []string{`{"version": 1}`, `{"version": 99, "future": true}`} {
+		out, _, err := migrateConfig([]byte(in))
+		if err != nil {
+			t.Fatalf("migrateConfig(%s): %v", in, err)
+		}
+		if string(out) != in {
+			t.Errorf("migrateConfig(%s) = %s, want unchanged", in, out)
+		}
+	}
+}
+
+func TestLoadConfig_MigratesUnversionedFileWithBackup(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	legacy := []byte(`{"process_whitelist": ["obs64.exe"], "active_profile": "work"}`)
+	if err := os.WriteFile(path, legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.ActiveProfile != "work" || len(cfg.ProcessWhitelist) != 1 {
+		t.Errorf("settings lost in migration: %+v", cfg)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	if string(backup) != string(legacy) {
+		t.Errorf("backup = %s, want original file", backup)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d configData
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Version != CurrentConfigVersion {
+		t.Errorf("saved Version = %d, want %d", d.Version, CurrentConfigVersion)
+	}
+}
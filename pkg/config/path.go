@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ConfigSource says where the config file path came from.
+type ConfigSource string
+
+const (
+	// SourceFlag is a path set with SetConfigPath, i.e. --config.
+	SourceFlag ConfigSource = "flag"
+	// SourceEnv is a path from the SYSCLEANER_CONFIG variable.
+	SourceEnv ConfigSource = "environment"
+	// SourcePortable is a config.yaml next to the executable.
+	SourcePortable ConfigSource = "portable"
+	// SourceUser is config.yaml in the user's config directory.
+	SourceUser ConfigSource = "user"
+)
+
+// EnvConfigPath names the environment variable holding an explicit config
+// file path.
+const EnvConfigPath = EnvPrefix + "CONFIG"
+
+// configFileName is the config file's name in the portable and user
+// locations.
+const configFileName = "config.yaml"
+
+var (
+	pathMu       sync.RWMutex
+	explicitPath string
+)
+
+// executablePath is os.Executable, replaceable in tests.
+var executablePath = os.Executable
+
+// SetConfigPath makes every later load and save use the config file at
+// path, e.g. from the --config flag. An empty path restores the search.
+// Profiles are kept in a "profiles" folder beside the file.
+func SetConfigPath(path string) {
+	pathMu.Lock()
+	defer pathMu.Unlock()
+	explicitPath = path
+}
+
+// ResolveConfigPath returns the config file in use and where it was found.
+// The search order is SetConfigPath, SYSCLEANER_CONFIG, a config.yaml next
+// to the executable (portable installs), then the user config directory.
+// Explicit paths are returned even if the file doesn't exist yet; it is
+// created on the first save.
+func ResolveConfigPath() (string, ConfigSource, error) {
+	pathMu.RLock()
+	flagPath := explicitPath
+	pathMu.RUnlock()
+	if flagPath != "" {
+		abs, err := filepath.Abs(flagPath)
+		return abs, SourceFlag, err
+	}
+	if envPath := os.Getenv(EnvConfigPath); envPath != "" {
+		abs, err := filepath.Abs(envPath)
+		return abs, SourceEnv, err
+	}
+	if exe, err := executablePath(); err == nil {
+		portable := filepath.Join(filepath.Dir(exe), configFileName)
+		if info, err := os.Stat(portable); err == nil && !info.IsDir() {
+			return portable, SourcePortable, nil
+		}
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to determine user config directory: %w", err)
+	}
+	return filepath.Join(base, "SysCleaner", configFileName), SourceUser, nil
+}
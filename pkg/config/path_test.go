@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveConfigPath_SearchOrder(t *testing.T) {
+	userDir := t.TempDir()
+	exeDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	t.Setenv(EnvConfigPath, "")
+	defer SetConfigPath("")
+	orig := executablePath
+	defer func() { executablePath = orig }()
+	executablePath = func() (string, error) { return filepath.Join(exeDir, "syscleaner.exe"), nil }
+
+	check := func(wantPath string, wantSource ConfigSource) {
+		t.Helper()
+		path, source, err := ResolveConfigPath()
+		if err != nil {
+			t.Fatalf("ResolveConfigPath: %v", err)
+		}
+		if path != wantPath || source != wantSource {
+			t.Errorf("got %s (%s), want %s (%s)", path, source, wantPath, wantSource)
+		}
+	}
+
+	check(filepath.Join(userDir, "SysCleaner", "config.yaml"), SourceUser)
+
+	portable := filepath.Join(exeDir, "config.yaml")
+	if err := os.WriteFile(portable, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check(portable, SourcePortable)
+
+	envPath := filepath.Join(t.TempDir(), "env.yaml")
+	t.Setenv(EnvConfigPath, envPath)
+	check(envPath, SourceEnv)
+
+	flagPath := filepath.Join(t.TempDir(), "flag.yaml")
+	SetConfigPath(flagPath)
+	check(flagPath, SourceFlag)
+}
+
+func TestSetConfigPath_IsolatesLoadAndSave(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer SetConfigPath("")
+	path := filepath.Join(t.TempDir(), "ci", "ci.yaml")
+	SetConfigPath(path)
+
+	cfg := DefaultConfig()
+	cfg.ProcessWhitelist = []string{"agent.exe"}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("config not written to explicit path: %v", err)
+	}
+
+	loaded, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.ProcessWhitelist) != 1 || loaded.ProcessWhitelist[0] != "agent.exe" {
+		t.Errorf("whitelist = %v, want [agent.exe]", loaded.ProcessWhitelist)
+	}
+
+	if dir, _ := profilesDir(); dir != filepath.Join(filepath.Dir(path), "profiles") {
+		t.Errorf("profilesDir = %s, want beside %s", dir, path)
+	}
+}
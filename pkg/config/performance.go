@@ -0,0 +1,159 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/memory"
+	"syscleaner/pkg/sysinfo"
+)
+
+// PerformanceSettings holds the concurrency and sampling knobs. Zero values
+// are treated as "use the hardware default" when a config file is loaded.
+type PerformanceSettings struct {
+	CleanerWorkers             int    `json:"cleaner_workers"`
+	CleanerIOPriority          string `json:"cleaner_io_priority"` // "normal" or "low"
+	RAMMonitorIntervalSeconds  int    `json:"ram_monitor_interval_seconds"`
+	DiskMonitorIntervalSeconds int    `json:"disk_monitor_interval_seconds"`
+	// EstimateCacheTTLSeconds is how long a clean estimate is reused. Zero
+	// disables caching, so only a missing value gets the default.
+	EstimateCacheTTLSeconds *int `json:"estimate_cache_ttl_seconds"`
+
+	// Background cleans (scheduled and automatic) are paced to these
+	// limits so they don't cause stutter while a game is running. A
+	// negative value removes the limit.
+	BackgroundFilesPerSecond int     `json:"background_files_per_second"`
+	BackgroundMBPerSecond    float64 `json:"background_mb_per_second"`
+
+	// GUI refresh intervals. Live views pause while their tab is hidden or
+	// the window is in the background unless GUIRefreshInBackground is set.
+	DashboardRefreshSeconds int  `json:"dashboard_refresh_seconds"`
+	MonitorRefreshSeconds   int  `json:"monitor_refresh_seconds"`
+	GPURefreshSeconds       int  `json:"gpu_refresh_seconds"`
+	StatusBarRefreshSeconds int  `json:"status_bar_refresh_seconds"`
+	GUIRefreshInBackground  bool `json:"gui_refresh_in_background"`
+}
+
+// hardwareInfo detects the hardware once per process: detection probes the
+// system drive, and every LoadConfig needs the result.
+var hardwareInfo = sync.OnceValue(sysinfo.Detect)
+
+// DefaultPerformanceSettings derives performance defaults from the hardware.
+// SSDs handle parallel I/O well, so the worker count scales with cores (capped
+// at 8); spinning disks thrash under concurrent deletes and get 2 workers.
+func DefaultPerformanceSettings(info sysinfo.Info) PerformanceSettings {
+	workers := 2
+	if !info.SSDKnown || info.SystemDriveSSD {
+		workers = info.LogicalCPUs
+		if workers > 8 {
+			workers = 8
+		}
+		if workers < 2 {
+			workers = 2
+		}
+	}
+	estimateTTL := 300
+	return PerformanceSettings{
+		CleanerWorkers:             workers,
+		CleanerIOPriority:          "normal",
+		RAMMonitorIntervalSeconds:  5,
+		DiskMonitorIntervalSeconds: 60,
+		EstimateCacheTTLSeconds:    &estimateTTL,
+		BackgroundFilesPerSecond:   200,
+		BackgroundMBPerSecond:      20,
+		DashboardRefreshSeconds:    2,
+		MonitorRefreshSeconds:      1,
+		GPURefreshSeconds:          10,
+		StatusBarRefreshSeconds:    2,
+	}
+}
+
+// withDefaults fills any zero-valued fields from def, and the estimate
+// cache TTL when it is missing.
+func (p PerformanceSettings) withDefaults(def PerformanceSettings) PerformanceSettings {
+	if p.CleanerWorkers <= 0 {
+		p.CleanerWorkers = def.CleanerWorkers
+	}
+	if p.CleanerIOPriority == "" {
+		p.CleanerIOPriority = def.CleanerIOPriority
+	}
+	if p.RAMMonitorIntervalSeconds <= 0 {
+		p.RAMMonitorIntervalSeconds = def.RAMMonitorIntervalSeconds
+	}
+	if p.DiskMonitorIntervalSeconds <= 0 {
+		p.DiskMonitorIntervalSeconds = def.DiskMonitorIntervalSeconds
+	}
+	if p.EstimateCacheTTLSeconds == nil && def.EstimateCacheTTLSeconds != nil {
+		ttl := *def.EstimateCacheTTLSeconds
+		p.EstimateCacheTTLSeconds = &ttl
+	}
+	if p.BackgroundFilesPerSecond == 0 {
+		p.BackgroundFilesPerSecond = def.BackgroundFilesPerSecond
+	}
+	if p.BackgroundMBPerSecond == 0 {
+		p.BackgroundMBPerSecond = def.BackgroundMBPerSecond
+	}
+	if p.DashboardRefreshSeconds <= 0 {
+		p.DashboardRefreshSeconds = def.DashboardRefreshSeconds
+	}
+	if p.MonitorRefreshSeconds <= 0 {
+		p.MonitorRefreshSeconds = def.MonitorRefreshSeconds
+	}
+	if p.GPURefreshSeconds <= 0 {
+		p.GPURefreshSeconds = def.GPURefreshSeconds
+	}
+	if p.StatusBarRefreshSeconds <= 0 {
+		p.StatusBarRefreshSeconds = def.StatusBarRefreshSeconds
+	}
+	return p
+}
+
+// DiskMonitorInterval returns the disk-space sampling interval.
+func (p PerformanceSettings) DiskMonitorInterval() time.Duration {
+	return time.Duration(p.DiskMonitorIntervalSeconds) * time.Second
+}
+
+// DashboardRefreshInterval returns how often the GUI dashboard updates.
+func (p PerformanceSettings) DashboardRefreshInterval() time.Duration {
+	return time.Duration(p.DashboardRefreshSeconds) * time.Second
+}
+
+// MonitorRefreshInterval returns how often the GUI monitor tab updates.
+func (p PerformanceSettings) MonitorRefreshInterval() time.Duration {
+	return time.Duration(p.MonitorRefreshSeconds) * time.Second
+}
+
+// GPURefreshInterval returns how often the GUI samples GPU load. Each
+// sample takes about a second.
+func (p PerformanceSettings) GPURefreshInterval() time.Duration {
+	return time.Duration(p.GPURefreshSeconds) * time.Second
+}
+
+// StatusBarRefreshInterval returns how often the GUI status bar updates.
+func (p PerformanceSettings) StatusBarRefreshInterval() time.Duration {
+	return time.Duration(p.StatusBarRefreshSeconds) * time.Second
+}
+
+// Apply pushes the settings into the cleaner and memory packages.
+func (p PerformanceSettings) Apply() {
+	cleaner.Workers = p.CleanerWorkers
+	cleaner.IOPriority = cleaner.ParseIOPriority(p.CleanerIOPriority)
+	if p.EstimateCacheTTLSeconds != nil {
+		cleaner.EstimateCacheTTL = time.Duration(*p.EstimateCacheTTLSeconds) * time.Second
+	}
+	memory.MonitorInterval = time.Duration(p.RAMMonitorIntervalSeconds) * time.Second
+	cleaner.BackgroundRateLimit = p.BackgroundRateLimit()
+}
+
+// BackgroundRateLimit converts the background limits for the cleaner.
+func (p PerformanceSettings) BackgroundRateLimit() cleaner.RateLimit {
+	var limit cleaner.RateLimit
+	if p.BackgroundFilesPerSecond > 0 {
+		limit.FilesPerSecond = p.BackgroundFilesPerSecond
+	}
+	if p.BackgroundMBPerSecond > 0 {
+		limit.BytesPerSecond = int64(p.BackgroundMBPerSecond * 1024 * 1024)
+	}
+	return limit
+}
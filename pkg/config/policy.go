@@ -0,0 +1,52 @@
+package config
+
+import "strings"
+
+// PolicyKey is the HKLM key fleet administrators use, typically through
+// Group Policy, to set and lock settings. Each value is named after the
+// matching SYSCLEANER_* variable in CamelCase (DRY_RUN becomes DryRun,
+// DISABLED_CATEGORIES becomes DisabledCategories). DWORDs are read as
+// numbers or 0/1 switches, and multi-string values as lists.
+//
+// Policy values take precedence over the config file and the environment,
+// and are never written back to the file, so they cannot be changed from
+// the application.
+const PolicyKey = `SOFTWARE\Policies\SysCleaner`
+
+// policyValueName returns the policy value name for an override name.
+func policyValueName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(word[:1])
+		b.WriteString(strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// applyPolicy layers policy values, keyed by lower-cased value name, over
+// cfg. Invalid values are logged and ignored.
+func applyPolicy(cfg *Config, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	for i := range envOverrides {
+		o := &envOverrides[i]
+		if v, ok := values[strings.ToLower(policyValueName(o.name))]; ok {
+			applyOverride(cfg, o, v, true)
+		}
+	}
+}
+
+// PolicyOverrides returns the policy values applied to c, as Name=value.
+func (c *Config) PolicyOverrides() []string {
+	var out []string
+	for _, a := range c.envOverrides {
+		if a.policy {
+			out = append(out, policyValueName(a.override.name)+"="+a.envValue)
+		}
+	}
+	return out
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package config
+
+var readPolicyValues = func() map[string]string {
+	return nil
+}
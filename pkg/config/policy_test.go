@@ -0,0 +1,84 @@
+package config
+
+import "testing"
+
+func TestPolicyValueName(t *testing.T) {
+	for name, want := range map[string]string{
+		"DRY_RUN":             "DryRun",
+		"DISABLED_CATEGORIES": "DisabledCategories",
+		"AUDIT":               "Audit",
+	} {
+		if got := policyValueName(name); got != want {
+			t.Errorf("policyValueName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestApplyPolicy_WinsOverEnvironment(t *testing.T) {
+	cfg := DefaultConfig()
+	applyEnvOverrides(cfg, func(k string) (string, bool) {
+		if k == "SYSCLEANER_LOG_LEVEL" {
+			return "debug", true
+		}
+		return "", false
+	})
+	applyPolicy(cfg, map[string]string{
+		"loglevel":           "warn",
+		"dryrun":             "1",
+		"disabledcategories": "Event Logs;Recycle Bin",
+		"cleanerworkers":     "lots", // invalid, ignored
+	})
+
+	if cfg.LogLevel != "warn" {
+		t.Errorf("LogLevel = %q, want the policy value", cfg.LogLevel)
+	}
+	if !cfg.Cleaner.DryRun {
+		t.Error("policy DryRun not applied")
+	}
+	if len(cfg.Cleaner.DisabledCategories) != 2 {
+		t.Errorf("DisabledCategories = %q", cfg.Cleaner.DisabledCategories)
+	}
+	if got := cfg.PolicyOverrides(); len(got) != 3 {
+		t.Errorf("PolicyOverrides() = %v, want 3 entries", got)
+	}
+	if got := cfg.EnvOverrides(); len(got) != 1 {
+		t.Errorf("EnvOverrides() = %v, want only the environment entry", got)
+	}
+}
+
+func TestSaveConfig_DoesNotPersistPolicy(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SYSCLEANER_LOG_LEVEL", "debug")
+	orig := readPolicyValues
+	readPolicyValues = func() map[string]string {
+		return map[string]string{"loglevel": "error", "dryrun": "1"}
+	}
+	t.Cleanup(func() { readPolicyValues = orig })
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Changing a locked field in the app has no effect on the file.
+	cfg.Cleaner.DryRun = false
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := loadConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Cleaner.DryRun || file.LogLevel != "info" {
+		t.Errorf("policy or environment values written to the file: dry run %v, log level %q",
+			file.Cleaner.DryRun, file.LogLevel)
+	}
+}
+
+func TestApplyPolicy_RejectsUnknownCategory(t *testing.T) {
+	cfg := DefaultConfig()
+	applyPolicy(cfg, map[string]string{"disabledcategories": "Event Logs;Everything"})
+	if len(cfg.Cleaner.DisabledCategories) != 0 {
+		t.Errorf("invalid policy list should be ignored, got %q", cfg.Cleaner.DisabledCategories)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// readPolicyValues reads every value under HKLM\PolicyKey, keyed by
+// lower-cased name. It returns nil when no policy is set.
+var readPolicyValues = func() map[string]string {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, PolicyKey, registry.QUERY_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer k.Close()
+
+	names, err := k.ReadValueNames(-1)
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if s, _, err := k.GetStringValue(name); err == nil {
+			values[key] = s
+		} else if n, _, err := k.GetIntegerValue(name); err == nil {
+			values[key] = strconv.FormatUint(n, 10)
+		} else if list, _, err := k.GetStringsValue(name); err == nil {
+			values[key] = strings.Join(list, ";")
+		}
+	}
+	return values
+}
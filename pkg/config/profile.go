@@ -5,7 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/gaming"
+	"syscleaner/pkg/launcher"
 )
 
 // ProfileCleanOptions mirrors cleaner.CleanOptions with only the
@@ -30,38 +37,192 @@ type ProfileCleanOptions struct {
 	EventLogs            bool `json:"event_logs"`
 	DeliveryOptimization bool `json:"delivery_optimization"`
 	RecycleBin           bool `json:"recycle_bin"`
+	FontIconRebuild      bool `json:"font_icon_rebuild"`
 
 	// Application categories
-	ChromeCache  bool `json:"chrome_cache"`
-	FirefoxCache bool `json:"firefox_cache"`
-	EdgeCache    bool `json:"edge_cache"`
-	BraveCache   bool `json:"brave_cache"`
-	OperaCache   bool `json:"opera_cache"`
-	DiscordCache bool `json:"discord_cache"`
-	SpotifyCache bool `json:"spotify_cache"`
-	SteamCache   bool `json:"steam_cache"`
-	TeamsCache   bool `json:"teams_cache"`
-	VSCodeCache  bool `json:"vscode_cache"`
-	JavaCache    bool `json:"java_cache"`
+	ChromeCache   bool `json:"chrome_cache"`
+	FirefoxCache  bool `json:"firefox_cache"`
+	EdgeCache     bool `json:"edge_cache"`
+	BraveCache    bool `json:"brave_cache"`
+	OperaCache    bool `json:"opera_cache"`
+	DiscordCache  bool `json:"discord_cache"`
+	SpotifyCache  bool `json:"spotify_cache"`
+	SteamCache    bool `json:"steam_cache"`
+	TeamsCache    bool `json:"teams_cache"`
+	VSCodeCache   bool `json:"vscode_cache"`
+	JavaCache     bool `json:"java_cache"`
+	StoreAppCache bool `json:"store_app_cache"`
+
+	// Privacy categories
+	RecentDocuments  bool `json:"recent_documents"`
+	JumpLists        bool `json:"jump_lists"`
+	ClipboardHistory bool `json:"clipboard_history"`
+	ExplorerMRU      bool `json:"explorer_mru"`
+
+	// Rule-pack rule IDs
+	Rules []string `json:"rules,omitempty"`
+
+	// Volumes restricts which drives each category may clean
+	Volumes cleaner.VolumePolicy `json:"volumes"`
 
 	// Execution options
 	DryRun bool `json:"dry_run"`
 }
 
+// ToCleanOptions converts the profile's options for use with the cleaner.
+func (o ProfileCleanOptions) ToCleanOptions() cleaner.CleanOptions {
+	return fromCleanOptionsData(cleanOptionsData(o))
+}
+
 // GamingConfig holds gaming-mode specific settings for a profile.
 type GamingConfig struct {
 	UseExtremeMode bool `json:"use_extreme_mode"`
 	CPUBoost       int  `json:"cpu_boost"`
 	RAMReserveGB   int  `json:"ram_reserve_gb"`
+
+	// QoSDSCP maps game names or executables to the DSCP value their
+	// traffic is tagged with while they run. 0 disables tagging for a game.
+	QoSDSCP map[string]int `json:"qos_dscp,omitempty"`
+
+	// ServerEndpoints maps game names or executables to "host:port"
+	// servers whose latency, jitter and loss are measured while the game
+	// runs.
+	ServerEndpoints map[string][]string `json:"server_endpoints,omitempty"`
+
+	// SuspendWhileGaming lists executables suspended while gaming mode is
+	// on, typically apps found keeping the GPU awake.
+	SuspendWhileGaming []string `json:"suspend_while_gaming,omitempty"`
+
+	// SuspendBackgroundApps also suspends the browsers, Electron apps and
+	// sync clients in gaming.BackgroundApps. Whitelisted apps are skipped.
+	SuspendBackgroundApps bool `json:"suspend_background_apps,omitempty"`
+
+	// FocusMode suppresses notifications, sticky-keys prompts and focus
+	// stealing while gaming mode is on.
+	FocusMode bool `json:"focus_mode,omitempty"`
+
+	// DiscreteGPU sets the graphics preference of detected games to the
+	// high-performance GPU, for laptops with hybrid graphics.
+	DiscreteGPU bool `json:"discrete_gpu,omitempty"`
+
+	// WindowsGameSettings switches Windows Game Mode, Game DVR and the
+	// Xbox Game Bar while gaming mode is on.
+	WindowsGameSettings gaming.GameSettings `json:"windows_game_settings"`
+
+	// HighResTimer raises the system timer resolution to 0.5 ms while
+	// gaming mode is on.
+	HighResTimer bool `json:"high_res_timer,omitempty"`
+
+	// PowerPlan is the power plan used while gaming: "high" (the
+	// default), "ultimate" or "none" to leave the plan alone.
+	PowerPlan string `json:"power_plan,omitempty"`
+
+	// PurgeStandby empties the standby memory list when gaming mode
+	// starts and keeps the RAM monitor running while it is on.
+	PurgeStandby bool `json:"purge_standby,omitempty"`
+
+	// USBLatency turns off USB selective suspend and mouse and keyboard
+	// power saving while gaming mode is on.
+	USBLatency bool `json:"usb_latency,omitempty"`
+
+	// TrimWorkingSets empties the working sets of background processes
+	// that are not whitelisted when a game starts.
+	TrimWorkingSets bool `json:"trim_working_sets,omitempty"`
+
+	// ExtremeLevel is how far extreme mode goes: "light" closes background
+	// apps, "medium" also stops non-essential services and "extreme" (the
+	// default) also stops the Explorer shell.
+	ExtremeLevel string `json:"extreme_level,omitempty"`
+
+	// Streaming holds the streamer mode settings, used when the profile's
+	// type is ProfileTypeStreamer.
+	Streaming StreamingSettings `json:"streaming"`
+}
+
+// StreamingSettings configure streamer mode (see gaming.StreamingConfig).
+type StreamingSettings struct {
+	// Apps are the streaming apps kept at high priority alongside the
+	// game. Empty means gaming.StreamingApps (OBS, Streamlabs, XSplit...).
+	Apps []string `json:"apps,omitempty"`
+
+	// EncoderCores pins the streaming apps, and their encoder threads, to
+	// these cores, e.g. "ccd1", "e-cores" or "6-7".
+	EncoderCores string `json:"encoder_cores,omitempty"`
+
+	// Protect lists further capture, camera or audio processes never
+	// suspended or closed while gaming.
+	Protect []string `json:"protect,omitempty"`
 }
 
+// ProfileTypeStreamer marks a profile for gaming while streaming: instead
+// of suspending or closing everything but the game, gaming mode keeps the
+// streaming apps at high priority and leaves capture and audio alone.
+const ProfileTypeStreamer = "streamer"
+
 // Profile represents a named collection of settings that can be
 // switched between at runtime.
 type Profile struct {
-	Name             string              `json:"name"`
+	Name string `json:"name"`
+	// Extends names a base profile (a saved profile, a preset or
+	// "default"). The file then holds only the settings that differ from
+	// the base; see LoadProfile for how they are merged.
+	Extends string `json:"extends,omitempty"`
+	// Type is "" for a regular profile or ProfileTypeStreamer.
+	Type             string              `json:"type,omitempty"`
 	ProcessWhitelist []string            `json:"process_whitelist"`
 	CleanOptions     ProfileCleanOptions `json:"clean_options"`
 	GamingConfig     GamingConfig        `json:"gaming_config"`
+	// RAMMonitor overrides the global RAM thresholds while the profile is
+	// active. Zero fields fall back to the global settings.
+	RAMMonitor RAMMonitorSettings `json:"ram_monitor"`
+
+	// Environment is applied to every tool the profile's hooks launch;
+	// an action's own Env takes precedence.
+	Environment map[string]string `json:"environment,omitempty"`
+	Hooks       ProfileHooks      `json:"hooks"`
+}
+
+// ProfileHooks lists external tools launched at points in SysCleaner's
+// workflow while the profile is active.
+type ProfileHooks struct {
+	GamingEnable  []launcher.Action `json:"gaming_enable,omitempty"`
+	GamingDisable []launcher.Action `json:"gaming_disable,omitempty"`
+}
+
+// ValidateType reports an unknown profile type or extreme mode level, or
+// invalid streamer mode settings.
+func (p *Profile) ValidateType() error {
+	if err := gaming.ValidateExtremeLevel(p.GamingConfig.ExtremeLevel); err != nil {
+		return fmt.Errorf("profile %q: extreme_level: %w", p.Name, err)
+	}
+	switch p.Type {
+	case "":
+		return nil
+	case ProfileTypeStreamer:
+		if spec := p.GamingConfig.Streaming.EncoderCores; spec != "" {
+			if err := gaming.ValidateAffinity(spec); err != nil {
+				return fmt.Errorf("profile %q: encoder_cores: %w", p.Name, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("profile %q: unknown type %q (valid: %s)", p.Name, p.Type, ProfileTypeStreamer)
+}
+
+// StreamingConfig returns the streamer mode settings for gaming mode, or
+// nil unless p is a streamer profile.
+func (p *Profile) StreamingConfig() *gaming.StreamingConfig {
+	if p.Type != ProfileTypeStreamer {
+		return nil
+	}
+	s := p.GamingConfig.Streaming
+	return &gaming.StreamingConfig{Apps: s.Apps, EncoderCores: s.EncoderCores, Protect: s.Protect}
+}
+
+// RunHooks launches actions with the profile's environment, logging and
+// returning any failures.
+func (p *Profile) RunHooks(actions []launcher.Action) []error {
+	return launcher.RunAll(actions, p.Environment)
 }
 
 // profilesDir returns the path to the profiles directory, which is
@@ -74,6 +235,18 @@ func profilesDir() (string, error) {
 	return filepath.Join(dir, "profiles"), nil
 }
 
+// ValidateProfileName rejects names that cannot be used as a profile file
+// name.
+func ValidateProfileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if strings.ContainsAny(name, `/\:*?"<>|`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid profile name: %q", name)
+	}
+	return nil
+}
+
 // profilePath returns the file path for the given profile name.
 // The name is sanitised to prevent directory traversal.
 func profilePath(name string) (string, error) {
@@ -88,8 +261,18 @@ func profilePath(name string) (string, error) {
 	return filepath.Join(dir, safe+".json"), nil
 }
 
-// LoadProfile reads a profile by name from the profiles directory.
+// LoadProfile reads a profile by name from the profiles directory. A
+// profile that extends another is resolved at load time: the settings in
+// its file are applied on top of the fully resolved base. Objects are
+// merged field by field, while lists such as process_whitelist replace the
+// base's list.
 func LoadProfile(name string) (*Profile, error) {
+	return loadProfile(name, nil)
+}
+
+// loadProfile reads and resolves a profile. chain holds the profiles that
+// extend it, to catch cycles.
+func loadProfile(name string, chain []string) (*Profile, error) {
 	path, err := profilePath(name)
 	if err != nil {
 		return nil, err
@@ -103,17 +286,244 @@ func LoadProfile(name string) (*Profile, error) {
 		return nil, fmt.Errorf("reading profile %q: %w", name, err)
 	}
 
+	var head struct {
+		Extends string `json:"extends"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("parsing profile %q: %w", name, err)
+	}
+
 	p := &Profile{}
+	if head.Extends != "" {
+		if p, err = resolveBaseProfile(head.Extends, append(chain, name)); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		p.Name = name
+	}
 	if err := json.Unmarshal(data, p); err != nil {
 		return nil, fmt.Errorf("parsing profile %q: %w", name, err)
 	}
+	if err := p.ValidateType(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// maxExtendsDepth bounds how many profiles an inheritance chain may hold.
+const maxExtendsDepth = 8
+
+// resolveBaseProfile returns the resolved profile named base for a profile
+// extending it. A base that has not been saved may be a preset or
+// "default". chain lists the extending profiles, innermost last.
+func resolveBaseProfile(base string, chain []string) (*Profile, error) {
+	for _, n := range chain {
+		if strings.EqualFold(n, base) {
+			return nil, fmt.Errorf("profile inheritance cycle: %s -> %s", strings.Join(chain, " -> "), base)
+		}
+	}
+	if len(chain) >= maxExtendsDepth {
+		return nil, fmt.Errorf("profile inheritance deeper than %d levels", maxExtendsDepth)
+	}
+	if ProfileExists(base) {
+		return loadProfile(base, chain)
+	}
+	if p := PresetProfile(base); p != nil {
+		return p, nil
+	}
+	if base == "default" {
+		return DefaultProfile(), nil
+	}
+	return nil, fmt.Errorf("base profile %q not found", base)
+}
+
+// ProfileExists reports whether a profile with the given name is saved.
+func ProfileExists(name string) bool {
+	path, err := profilePath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// CreateProfile saves a new profile. Names matching a preset ("gaming",
+// "work", "deep-clean") start from that preset; any other name starts from
+// the default profile.
+func CreateProfile(name string) (*Profile, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if ProfileExists(name) {
+		return nil, fmt.Errorf("profile %q already exists", name)
+	}
+	p := PresetProfile(name)
+	if p == nil {
+		p = DefaultProfile()
+		p.Name = name
+	}
+	if err := SaveProfile(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ExtendProfile saves a new profile that inherits every setting from base
+// until it is edited.
+func ExtendProfile(name, base string) (*Profile, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if ProfileExists(name) {
+		return nil, fmt.Errorf("profile %q already exists", name)
+	}
+	p, err := resolveBaseProfile(base, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	p.Name = name
+	p.Extends = base
+	if err := SaveProfile(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DuplicateProfile saves a copy of the src profile under the name dst.
+func DuplicateProfile(src, dst string) (*Profile, error) {
+	if err := ValidateProfileName(dst); err != nil {
+		return nil, err
+	}
+	if ProfileExists(dst) {
+		return nil, fmt.Errorf("profile %q already exists", dst)
+	}
+	p, err := LoadProfile(src)
+	if err != nil {
+		return nil, err
+	}
+	p.Name = dst
+	if err := SaveProfile(p); err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
+// SwitchProfile makes name the active profile and saves the config. The
+// default profile and the presets are created on first use.
+func SwitchProfile(name string) error {
+	if !ProfileExists(name) {
+		p := PresetProfile(name)
+		if p == nil && name == "default" {
+			p = DefaultProfile()
+		}
+		if p == nil {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		if err := SaveProfile(p); err != nil {
+			return err
+		}
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	cfg.ActiveProfile = name
+	return SaveConfig(cfg)
+}
+
+// ActiveProfileSettings returns the active profile, or the default profile
+// if it has not been saved or cannot be read.
+func (c *Config) ActiveProfileSettings() *Profile {
+	if c.ActiveProfile != "" {
+		if p, err := LoadProfile(c.ActiveProfile); err == nil {
+			return p
+		}
+	}
+	return DefaultProfile()
+}
+
+// EffectiveRAMMonitor returns the RAM thresholds of the active profile,
+// falling back to the global thresholds for fields the profile leaves unset.
+func (c *Config) EffectiveRAMMonitor() RAMMonitorSettings {
+	ram := c.RAMMonitor
+	p := c.ActiveProfileSettings().RAMMonitor
+	if p.FreeThresholdPercent > 0 {
+		ram.FreeThresholdPercent = p.FreeThresholdPercent
+	}
+	if p.StandbyThresholdPercent > 0 {
+		ram.StandbyThresholdPercent = p.StandbyThresholdPercent
+	}
+	return ram
+}
+
+// EffectiveWhitelist returns the global process whitelist plus the active
+// profile's, without duplicates.
+func (c *Config) EffectiveWhitelist() []string {
+	return mergeWhitelists(c.ProcessWhitelist, c.ActiveProfileSettings().ProcessWhitelist)
+}
+
+// WhitelistWarnings checks EffectiveWhitelist against the known and running
+// process names and returns the entries that look like typos, with
+// suggestions. It lists processes, so it is not run by LoadConfig.
+func (c *Config) WhitelistWarnings() []gaming.WhitelistWarning {
+	return gaming.ValidateWhitelist(c.EffectiveWhitelist())
+}
+
+func mergeWhitelists(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range lists {
+		for _, name := range list {
+			if key := strings.ToLower(name); !seen[key] {
+				seen[key] = true
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
+
+// ActiveVolumePolicy returns the volume restrictions of the active profile,
+// or an empty policy if the profile cannot be loaded.
+func (c *Config) ActiveVolumePolicy() cleaner.VolumePolicy {
+	if c.ActiveProfile == "" {
+		return cleaner.VolumePolicy{}
+	}
+	p, err := LoadProfile(c.ActiveProfile)
+	if err != nil {
+		return cleaner.VolumePolicy{}
+	}
+	return p.CleanOptions.Volumes
+}
+
+// AddSuspendWhileGaming adds exe to the named profile's list of apps
+// suspended during gaming mode, creating the profile from the defaults if it
+// does not exist yet. Adding a name already on the list is a no-op.
+func AddSuspendWhileGaming(profileName, exe string) error {
+	p, err := LoadProfile(profileName)
+	if err != nil {
+		p = DefaultProfile()
+		p.Name = profileName
+	}
+	for _, existing := range p.GamingConfig.SuspendWhileGaming {
+		if strings.EqualFold(existing, exe) {
+			return nil
+		}
+	}
+	p.GamingConfig.SuspendWhileGaming = append(p.GamingConfig.SuspendWhileGaming, exe)
+	return SaveProfile(p)
+}
+
 // SaveProfile writes a profile to the profiles directory, creating
 // the directory if it does not already exist. The profile name is
-// used to derive the file name.
+// used to derive the file name. A profile that extends another is
+// written as only the settings that differ from its base.
 func SaveProfile(p *Profile) error {
+	if err := admin.RequireWriteAccess("saving profile " + p.Name); err != nil {
+		return err
+	}
+	if err := p.ValidateType(); err != nil {
+		return err
+	}
 	dir, err := profilesDir()
 	if err != nil {
 		return err
@@ -127,9 +537,9 @@ func SaveProfile(p *Profile) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(p, "", "  ")
+	data, err := marshalProfile(p)
 	if err != nil {
-		return fmt.Errorf("marshaling profile %q: %w", p.Name, err)
+		return err
 	}
 
 	if err := os.WriteFile(path, data, 0644); err != nil {
@@ -138,6 +548,79 @@ func SaveProfile(p *Profile) error {
 	return nil
 }
 
+// marshalProfile encodes p for its file, reduced to the differences from
+// its base profile when it has one.
+func marshalProfile(p *Profile) ([]byte, error) {
+	if p.Extends == "" {
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshaling profile %q: %w", p.Name, err)
+		}
+		return data, nil
+	}
+
+	base, err := resolveBaseProfile(p.Extends, []string{p.Name})
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", p.Name, err)
+	}
+	baseFields, err := profileFields(base)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := profileFields(p)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffFields(baseFields, fields)
+	diff["name"] = p.Name
+	diff["extends"] = p.Extends
+
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling profile %q: %w", p.Name, err)
+	}
+	return data, nil
+}
+
+// profileFields returns p as a generic JSON object.
+func profileFields(p *Profile) (map[string]any, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling profile %q: %w", p.Name, err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("marshaling profile %q: %w", p.Name, err)
+	}
+	return fields, nil
+}
+
+// diffFields returns the entries of fields that differ from base,
+// descending into nested objects. Entries only in base are not recorded,
+// so a map key set by the base cannot be removed by an extending profile.
+func diffFields(base, fields map[string]any) map[string]any {
+	out := make(map[string]any)
+	for key, v := range fields {
+		bv, ok := base[key]
+		if !ok {
+			out[key] = v
+			continue
+		}
+		obj, isObj := v.(map[string]any)
+		baseObj, baseIsObj := bv.(map[string]any)
+		if isObj && baseIsObj {
+			if sub := diffFields(baseObj, obj); len(sub) > 0 {
+				out[key] = sub
+			}
+			continue
+		}
+		if !reflect.DeepEqual(v, bv) {
+			out[key] = v
+		}
+	}
+	return out
+}
+
 // ListProfiles returns the names of all saved profiles by scanning the
 // profiles directory for JSON files.
 func ListProfiles() ([]string, error) {
@@ -169,6 +652,9 @@ func ListProfiles() ([]string, error) {
 
 // DeleteProfile removes a saved profile by name.
 func DeleteProfile(name string) error {
+	if err := admin.RequireWriteAccess("deleting profile " + name); err != nil {
+		return err
+	}
 	path, err := profilePath(name)
 	if err != nil {
 		return err
@@ -183,6 +669,113 @@ func DeleteProfile(name string) error {
 	return nil
 }
 
+// presetProfiles are the ready-made profiles offered alongside "default".
+var presetProfiles = map[string]func() *Profile{
+	"gaming": func() *Profile {
+		return &Profile{
+			Name:             "gaming",
+			ProcessWhitelist: []string{"Discord.exe", "steam.exe", "obs64.exe"},
+			CleanOptions: ProfileCleanOptions{
+				WindowsTemp: true,
+				UserTemp:    true,
+				DNSCache:    true,
+			},
+			GamingConfig: GamingConfig{
+				CPUBoost: 80, RAMReserveGB: 2, FocusMode: true, HighResTimer: true, PurgeStandby: true, USBLatency: true,
+				WindowsGameSettings: gaming.GameSettings{GameMode: "on", DisableGameDVR: true},
+			},
+			// Trim earlier and clear standby sooner to keep RAM free for games.
+			RAMMonitor: RAMMonitorSettings{FreeThresholdPercent: 20, StandbyThresholdPercent: 30},
+		}
+	},
+	"streamer": func() *Profile {
+		return &Profile{
+			Name:             "streamer",
+			Type:             ProfileTypeStreamer,
+			ProcessWhitelist: []string{"Discord.exe", "steam.exe", "obs64.exe", "Spotify.exe"},
+			CleanOptions: ProfileCleanOptions{
+				WindowsTemp: true,
+				UserTemp:    true,
+				DNSCache:    true,
+			},
+			// No suspending or standby purges: both can drop frames from
+			// capture, and the encoder needs the memory it has.
+			GamingConfig: GamingConfig{
+				CPUBoost: 60, RAMReserveGB: 2, FocusMode: true, HighResTimer: true,
+				WindowsGameSettings: gaming.GameSettings{GameMode: "on", DisableGameDVR: true},
+			},
+		}
+	},
+	"work": func() *Profile {
+		return &Profile{
+			Name:             "work",
+			ProcessWhitelist: []string{"Teams.exe", "ms-teams.exe", "OUTLOOK.EXE", "Code.exe"},
+			CleanOptions: ProfileCleanOptions{
+				WindowsTemp:    true,
+				UserTemp:       true,
+				ThumbnailCache: true,
+				CrashDumps:     true,
+				ErrorReports:   true,
+			},
+		}
+	},
+	"deep-clean": func() *Profile {
+		return &Profile{
+			Name:             "deep-clean",
+			ProcessWhitelist: []string{},
+			CleanOptions: ProfileCleanOptions{
+				WindowsTemp:          true,
+				UserTemp:             true,
+				WindowsUpdate:        true,
+				WindowsInstaller:     true,
+				Prefetch:             true,
+				CrashDumps:           true,
+				ErrorReports:         true,
+				ThumbnailCache:       true,
+				IconCache:            true,
+				FontCache:            true,
+				ShaderCache:          true,
+				DNSCache:             true,
+				WindowsLogs:          true,
+				EventLogs:            true,
+				DeliveryOptimization: true,
+				RecycleBin:           true,
+				ChromeCache:          true,
+				FirefoxCache:         true,
+				EdgeCache:            true,
+				BraveCache:           true,
+				OperaCache:           true,
+				DiscordCache:         true,
+				SpotifyCache:         true,
+				SteamCache:           true,
+				TeamsCache:           true,
+				VSCodeCache:          true,
+				JavaCache:            true,
+				StoreAppCache:        true,
+			},
+		}
+	},
+}
+
+// PresetProfile returns a fresh copy of the named preset, or nil if name
+// is not a preset.
+func PresetProfile(name string) *Profile {
+	if preset, ok := presetProfiles[name]; ok {
+		return preset()
+	}
+	return nil
+}
+
+// PresetProfileNames returns the names of the built-in presets, sorted.
+func PresetProfileNames() []string {
+	names := make([]string, 0, len(presetProfiles))
+	for name := range presetProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // DefaultProfile returns a Profile populated with sensible default values.
 func DefaultProfile() *Profile {
 	return &Profile{
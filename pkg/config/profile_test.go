@@ -0,0 +1,222 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"gaming", "my profile", "deep-clean"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "  ", "..", "a/b", `a\b`, "c:x"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestProfileCRUD(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	p, err := CreateProfile("gaming")
+	if err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	if p.RAMMonitor.FreeThresholdPercent != 20 {
+		t.Errorf("gaming preset free threshold = %v, want 20", p.RAMMonitor.FreeThresholdPercent)
+	}
+	if _, err := CreateProfile("gaming"); err == nil {
+		t.Error("CreateProfile on an existing name should fail")
+	}
+
+	if _, err := DuplicateProfile("gaming", "gaming-2"); err != nil {
+		t.Fatalf("DuplicateProfile: %v", err)
+	}
+	dup, err := LoadProfile("gaming-2")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if dup.Name != "gaming-2" || len(dup.ProcessWhitelist) == 0 {
+		t.Errorf("duplicate = %+v, want copy of gaming named gaming-2", dup)
+	}
+
+	if err := SwitchProfile("gaming-2"); err != nil {
+		t.Fatalf("SwitchProfile: %v", err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.ActiveProfile != "gaming-2" {
+		t.Errorf("ActiveProfile = %q, want gaming-2", cfg.ActiveProfile)
+	}
+
+	if err := SwitchProfile("missing"); err == nil {
+		t.Error("SwitchProfile to an unknown profile should fail")
+	}
+	if err := SwitchProfile("work"); err != nil {
+		t.Fatalf("SwitchProfile(work): %v", err)
+	}
+	if !ProfileExists("work") {
+		t.Error("switching to a preset should save it")
+	}
+
+	if err := DeleteProfile("gaming-2"); err != nil {
+		t.Fatalf("DeleteProfile: %v", err)
+	}
+	if ProfileExists("gaming-2") {
+		t.Error("profile still exists after delete")
+	}
+}
+
+func TestEffectiveSettings_MergeActiveProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if _, err := CreateProfile("gaming"); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.ActiveProfile = "gaming"
+	cfg.RAMMonitor.StandbyThresholdPercent = 50
+	cfg.ProcessWhitelist = []string{"discord.exe", "explorer.exe"}
+
+	ram := cfg.EffectiveRAMMonitor()
+	if ram.FreeThresholdPercent != 20 || ram.StandbyThresholdPercent != 30 {
+		t.Errorf("EffectiveRAMMonitor = %+v, want profile thresholds 20/30", ram)
+	}
+
+	wl := cfg.EffectiveWhitelist()
+	want := []string{"discord.exe", "explorer.exe", "steam.exe", "obs64.exe"}
+	if len(wl) != len(want) {
+		t.Fatalf("EffectiveWhitelist = %v, want %v", wl, want)
+	}
+	for i := range want {
+		if wl[i] != want[i] {
+			t.Errorf("EffectiveWhitelist[%d] = %q, want %q", i, wl[i], want[i])
+		}
+	}
+}
+
+func TestProfileInheritance(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// "default" is not saved, so the built-in default profile is the base.
+	p, err := ExtendProfile("laptop", "default")
+	if err != nil {
+		t.Fatalf("ExtendProfile: %v", err)
+	}
+	p.RAMMonitor.FreeThresholdPercent = 25
+	p.CleanOptions.ChromeCache = false
+	if err := SaveProfile(p); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+
+	dir, _ := profilesDir()
+	data, err := os.ReadFile(filepath.Join(dir, "laptop.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored map[string]any
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored["process_whitelist"]; ok {
+		t.Errorf("unchanged settings were stored: %s", data)
+	}
+	clean, _ := stored["clean_options"].(map[string]any)
+	if len(clean) != 1 || clean["chrome_cache"] != false {
+		t.Errorf("clean_options = %v, want only chrome_cache: false", clean)
+	}
+
+	// A change to the base carries over to the extending profile.
+	base := DefaultProfile()
+	base.ProcessWhitelist = []string{"obs64.exe"}
+	if err := SaveProfile(base); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadProfile("laptop")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if got.Name != "laptop" || got.Extends != "default" {
+		t.Errorf("name/extends = %q/%q", got.Name, got.Extends)
+	}
+	if len(got.ProcessWhitelist) != 1 || got.ProcessWhitelist[0] != "obs64.exe" {
+		t.Errorf("whitelist = %v, want inherited [obs64.exe]", got.ProcessWhitelist)
+	}
+	if got.RAMMonitor.FreeThresholdPercent != 25 || got.CleanOptions.ChromeCache || !got.CleanOptions.UserTemp {
+		t.Errorf("merged profile = %+v", got)
+	}
+}
+
+func TestProfileInheritance_Cycle(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir, _ := profilesDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, base := range map[string]string{"a": "b", "b": "a"} {
+		data := []byte(`{"name": "` + name + `", "extends": "` + base + `"}`)
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := LoadProfile("a"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("LoadProfile(a) = %v, want cycle error", err)
+	}
+	if _, err := ExtendProfile("c", "missing"); err == nil {
+		t.Error("ExtendProfile with an unknown base should fail")
+	}
+}
+
+func TestStreamerProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if DefaultProfile().StreamingConfig() != nil {
+		t.Error("the default profile is not a streamer profile")
+	}
+	p := PresetProfile("streamer")
+	p.GamingConfig.Streaming.EncoderCores = "ccd1"
+	if err := SaveProfile(p); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+	got, err := LoadProfile("streamer")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if s := got.StreamingConfig(); s == nil || s.EncoderCores != "ccd1" {
+		t.Errorf("StreamingConfig = %+v", s)
+	}
+
+	p.GamingConfig.Streaming.EncoderCores = "half"
+	if err := SaveProfile(p); err == nil {
+		t.Error("invalid encoder_cores should be rejected")
+	}
+	p.Type = "recording"
+	if err := SaveProfile(p); err == nil {
+		t.Error("an unknown profile type should be rejected")
+	}
+}
+
+func TestProfileExtremeLevel(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	p := &Profile{Name: "lan", GamingConfig: GamingConfig{ExtremeLevel: "medium"}}
+	if err := SaveProfile(p); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+	if got, err := LoadProfile("lan"); err != nil || got.GamingConfig.ExtremeLevel != "medium" {
+		t.Fatalf("LoadProfile = %+v, %v", got, err)
+	}
+	p.GamingConfig.ExtremeLevel = "max"
+	if err := SaveProfile(p); err == nil {
+		t.Error("an unknown extreme_level should be rejected")
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// secretPrefix marks an encrypted value in the config file.
+const secretPrefix = "dpapi:"
+
+// errSecretsUnsupported is returned by protectSecret on platforms without
+// DPAPI. Secrets are then written as plain text.
+var errSecretsUnsupported = errors.New("secret encryption is not available on this platform")
+
+// Secret is a sensitive config value, such as a webhook URL or an API
+// token. In the config file it is encrypted with DPAPI for the current
+// user account; in memory it holds the plain text. A value typed into the
+// file by hand is read as-is and encrypted the next time the config is
+// saved.
+type Secret string
+
+// String masks the value so secrets don't end up in logs or error
+// messages. Use Reveal to get the plain text.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "********"
+}
+
+// Reveal returns the plain text of the secret.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// MarshalJSON encrypts the secret for the current user.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return json.Marshal("")
+	}
+	sealed, err := protectSecret([]byte(s))
+	if errors.Is(err, errSecretsUnsupported) {
+		return json.Marshal(string(s))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encrypting secret: %w", err)
+	}
+	return json.Marshal(secretPrefix + base64.StdEncoding.EncodeToString(sealed))
+}
+
+// UnmarshalJSON decrypts an encrypted secret or accepts plain text. A
+// secret that cannot be decrypted, e.g. because the config was copied from
+// another user account, is logged and left empty rather than failing the
+// whole config.
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(text, secretPrefix) {
+		*s = Secret(text)
+		return nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(text, secretPrefix))
+	if err == nil {
+		var plain []byte
+		if plain, err = unprotectSecret(sealed); err == nil {
+			*s = Secret(plain)
+			return nil
+		}
+	}
+	log.Printf("[SysCleaner] Could not decrypt a saved secret (it must be entered again): %v", err)
+	*s = ""
+	return nil
+}
+
+// hasPlaintextSecrets reports whether the config file data holds secrets
+// that are not encrypted yet.
+func hasPlaintextSecrets(data []byte) bool {
+	var d struct {
+		Secrets map[string]string `json:"secrets"`
+	}
+	if json.Unmarshal(data, &d) != nil {
+		return false
+	}
+	for _, v := range d.Secrets {
+		if v != "" && !strings.HasPrefix(v, secretPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package config
+
+var protectSecret = func(plain []byte) ([]byte, error) {
+	return nil, errSecretsUnsupported
+}
+
+var unprotectSecret = func(sealed []byte) ([]byte, error) {
+	return nil, errSecretsUnsupported
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSecretStore swaps DPAPI for a reversible XOR so the encryption path
+// can be tested on every platform.
+func fakeSecretStore(t *testing.T) {
+	t.Helper()
+	origProtect, origUnprotect := protectSecret, unprotectSecret
+	xor := func(b []byte) []byte {
+		out := make([]byte, len(b))
+		for i := range b {
+			out[i] = b[i] ^ 0x5a
+		}
+		return out
+	}
+	protectSecret = func(plain []byte) ([]byte, error) { return append([]byte("sealed"), xor(plain)...), nil }
+	unprotectSecret = func(sealed []byte) ([]byte, error) {
+		if !bytes.HasPrefix(sealed, []byte("sealed")) {
+			return nil, errors.New("not sealed by this user")
+		}
+		return xor(sealed[len("sealed"):]), nil
+	}
+	t.Cleanup(func() { protectSecret, unprotectSecret = origProtect, origUnprotect })
+}
+
+func TestSecret_EncryptedOnDisk(t *testing.T) {
+	fakeSecretStore(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.Secrets = map[string]Secret{"webhook_url": "https://hooks.example.com/abc"}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := configFilePath()
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "hooks.example.com") {
+		t.Fatalf("secret written in plain text:\n%s", data)
+	}
+	if !strings.Contains(string(data), secretPrefix) {
+		t.Errorf("expected an encrypted value in the file:\n%s", data)
+	}
+
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Secrets["webhook_url"].Reveal() != "https://hooks.example.com/abc" {
+		t.Errorf("secret not decrypted, got %q", got.Secrets["webhook_url"].Reveal())
+	}
+}
+
+func TestSecret_PlaintextIsEncryptedOnLoad(t *testing.T) {
+	fakeSecretStore(t)
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	dir := filepath.Join(tmpDir, "SysCleaner")
+	os.MkdirAll(dir, 0755)
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte(`{"version": 1, "secrets": {"token": "hunter2"}}`), 0644)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Secrets["token"].Reveal() != "hunter2" {
+		t.Errorf("plain-text secret not read, got %q", cfg.Secrets["token"].Reveal())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("plain-text secret left in the file:\n%s", data)
+	}
+}
+
+func TestSecret_UndecryptableIsDropped(t *testing.T) {
+	fakeSecretStore(t)
+	var s Secret
+	if err := s.UnmarshalJSON([]byte(`"dpapi:b3RoZXI="`)); err != nil {
+		t.Fatalf("undecryptable secret should not fail the config: %v", err)
+	}
+	if s != "" {
+		t.Errorf("expected an empty secret, got %q", s.Reveal())
+	}
+}
+
+func TestSecret_StringIsMasked(t *testing.T) {
+	s := Secret("hunter2")
+	if out := fmt.Sprintf("%v %s", s, s); strings.Contains(out, "hunter2") {
+		t.Errorf("secret leaked through fmt: %q", out)
+	}
+}
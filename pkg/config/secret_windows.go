@@ -0,0 +1,48 @@
+//go:build windows
+
+package config
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// secretEntropy is mixed into every DPAPI blob so other applications
+// running as the same user can't decrypt SysCleaner's secrets by accident.
+var secretEntropy = []byte("SysCleaner config secret")
+
+// protectSecret encrypts plain with DPAPI for the current user.
+var protectSecret = func(plain []byte) ([]byte, error) {
+	in := newBlob(plain)
+	entropy := newBlob(secretEntropy)
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(in, nil, entropy, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+// unprotectSecret decrypts a blob made by protectSecret.
+var unprotectSecret = func(sealed []byte) ([]byte, error) {
+	in := newBlob(sealed)
+	entropy := newBlob(secretEntropy)
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(in, nil, entropy, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+func newBlob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+}
+
+// takeBlob copies a DPAPI output blob into Go memory and frees it.
+func takeBlob(b *windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(b.Data)))
+	return append([]byte(nil), unsafe.Slice(b.Data, b.Size)...)
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce groups the bursts of events editors produce for a single
+// save (truncate, write, rename) into one reload.
+var watchDebounce = 250 * time.Millisecond
+
+// Watch reloads the config whenever the config file, the machine config or a
+// profile is changed on disk and sends the result on the returned channel, which is closed when
+// ctx is done. Edits that fail to parse are logged and skipped, so a
+// half-written file never replaces a working config.
+//
+// The channel holds only the latest config: a slow reader skips
+// intermediate versions rather than blocking the watcher.
+func Watch(ctx context.Context) (<-chan *Config, error) {
+	configPath, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(configPath)
+	profiles, err := profilesDir()
+	if err != nil {
+		return nil, err
+	}
+	// Watch the directories rather than the files: editors often save by
+	// renaming a temp file over the original, which drops a file watch.
+	if err := os.MkdirAll(profiles, 0755); err != nil {
+		return nil, fmt.Errorf("creating profiles directory: %w", err)
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating config watcher: %w", err)
+	}
+	for _, d := range []string{dir, profiles} {
+		if err := w.Add(d); err != nil {
+			w.Close()
+			return nil, fmt.Errorf("watching %s: %w", d, err)
+		}
+	}
+	// The machine config is optional and its directory may not exist yet.
+	machinePath := MachineConfigPath()
+	if machinePath != "" {
+		if err := w.Add(filepath.Dir(machinePath)); err != nil {
+			machinePath = ""
+		}
+	}
+
+	out := make(chan *Config, 1)
+	go func() {
+		defer close(out)
+		defer w.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if isConfigEvent(ev, configPath, profiles) || (machinePath != "" && isConfigEvent(ev, machinePath, "")) {
+					debounce = time.After(watchDebounce)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[SysCleaner] Config watcher: %v", err)
+			case <-debounce:
+				debounce = nil
+				cfg, err := LoadConfig()
+				if err != nil {
+					log.Printf("[SysCleaner] Ignoring config change: %v", err)
+					continue
+				}
+				// Replace any config the reader has not picked up yet.
+				select {
+				case <-out:
+				default:
+				}
+				out <- cfg
+			}
+		}
+	}()
+	return out, nil
+}
+
+// isConfigEvent reports whether ev changes config.yaml or a profile.
+// Backups and editor temp files are ignored.
+func isConfigEvent(ev fsnotify.Event, configPath, profiles string) bool {
+	if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Rename) && !ev.Has(fsnotify.Remove) {
+		return false
+	}
+	name := filepath.Clean(ev.Name)
+	if name == filepath.Clean(configPath) {
+		return true
+	}
+	return profiles != "" && filepath.Dir(name) == filepath.Clean(profiles) && filepath.Ext(name) == ".json"
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch_ReloadsOnEdit(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	orig := watchDebounce
+	defer func() { watchDebounce = orig }()
+	watchDebounce = 20 * time.Millisecond
+
+	cfg := DefaultConfig()
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// A file that fails to parse is skipped.
+	dir, _ := ConfigDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	cfg.ProcessWhitelist = []string{"obs64.exe"}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-updates:
+		if len(got.ProcessWhitelist) != 1 || got.ProcessWhitelist[0] != "obs64.exe" {
+			t.Errorf("reloaded whitelist = %v, want [obs64.exe]", got.ProcessWhitelist)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no config update received")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-updates:
+		if ok {
+			// A late duplicate reload is fine; the channel must still close.
+			<-updates
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
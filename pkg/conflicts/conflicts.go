@@ -0,0 +1,102 @@
+// Package conflicts detects other disk cleaners on the machine whose
+// schedules overlap with SysCleaner's automatic cleaning.
+package conflicts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SourceKind classifies an external cleaner.
+type SourceKind string
+
+const (
+	KindStorageSense  SourceKind = "storage-sense"
+	KindScheduledTask SourceKind = "scheduled-task"
+)
+
+// Source is an external cleaner found on the machine.
+type Source struct {
+	Name string
+	Kind SourceKind
+	// Schedule describes when it runs, e.g. "daily" or "when disk space is low".
+	Schedule string
+	// OnLowDisk reports whether it runs when free space is low, which
+	// overlaps directly with the low disk trigger.
+	OnLowDisk bool
+	// CleansTemp reports whether it deletes temporary files, overlapping
+	// with SysCleaner's temp categories.
+	CleansTemp bool
+	// Path is the task file or registry key it was found at.
+	Path string
+}
+
+// Conflict is an overlap between an external cleaner and SysCleaner.
+type Conflict struct {
+	Source Source
+	Reason string
+	Fix    string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s (%s): %s. %s", c.Source.Name, c.Source.Schedule, c.Reason, c.Fix)
+}
+
+// knownCleaners maps lower-case task name fragments to product names.
+var knownCleaners = map[string]string{
+	"ccleaner":            "CCleaner",
+	"wise disk cleaner":   "Wise Disk Cleaner",
+	"wisedisk":            "Wise Disk Cleaner",
+	"glary":               "Glary Utilities",
+	"advanced systemcare": "Advanced SystemCare",
+	"bleachbit":           "BleachBit",
+	"avast cleanup":       "Avast Cleanup",
+	"avg tuneup":          "AVG TuneUp",
+}
+
+// matchCleaner returns the product name for a scheduled task name, or "".
+func matchCleaner(taskName string) string {
+	lower := strings.ToLower(taskName)
+	for fragment, product := range knownCleaners {
+		if strings.Contains(lower, fragment) {
+			return product
+		}
+	}
+	return ""
+}
+
+// Detect returns the external cleaners found on this machine.
+func Detect() []Source {
+	var sources []Source
+	if s, ok := detectStorageSense(); ok {
+		sources = append(sources, s)
+	}
+	return append(sources, detectCleanerTasks()...)
+}
+
+// Analyze reports how sources overlap with SysCleaner. lowDiskTrigger is
+// whether SysCleaner's own low disk trigger is enabled.
+func Analyze(sources []Source, lowDiskTrigger bool) []Conflict {
+	var out []Conflict
+	for _, s := range sources {
+		switch {
+		case s.OnLowDisk && lowDiskTrigger:
+			c := Conflict{Source: s, Reason: "runs when disk space is low, same as the SysCleaner low disk trigger"}
+			if s.Kind == KindStorageSense {
+				c.Fix = "Disable Storage Sense, or disable the SysCleaner low disk trigger"
+			} else {
+				c.Fix = "Disable its scheduled task, or disable the SysCleaner low disk trigger"
+			}
+			out = append(out, c)
+		case s.CleansTemp:
+			c := Conflict{Source: s, Reason: "also deletes temporary files on a schedule"}
+			if s.Kind == KindStorageSense {
+				c.Fix = "Disable Storage Sense so the two do not clean the same folders"
+			} else {
+				c.Fix = "Disable its scheduled task so the two do not clean the same folders"
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
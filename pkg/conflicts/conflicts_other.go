@@ -0,0 +1,22 @@
+//go:build !windows
+
+package conflicts
+
+import "fmt"
+
+func readStorageSense() (StorageSense, error) {
+	return StorageSense{}, fmt.Errorf("storage sense is only available on Windows")
+}
+
+func writeStorageSense(s StorageSense) error {
+	return fmt.Errorf("storage sense is only available on Windows")
+}
+
+// DisableStorageSense is only available on Windows.
+func DisableStorageSense() error {
+	return fmt.Errorf("storage sense is only available on Windows")
+}
+
+func detectCleanerTasks() []Source {
+	return nil
+}
@@ -0,0 +1,51 @@
+package conflicts
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestAnalyze(t *testing.T) {
+	sense := Source{Name: "Storage Sense", Kind: KindStorageSense, Schedule: "when disk space is low", OnLowDisk: true}
+	ccleaner := Source{Name: "CCleaner", Kind: KindScheduledTask, Schedule: "daily", CleansTemp: true}
+	quiet := Source{Name: "Storage Sense", Kind: KindStorageSense, Schedule: "monthly"}
+
+	if got := Analyze([]Source{sense, ccleaner, quiet}, true); len(got) != 2 {
+		t.Errorf("expected 2 conflicts with low disk trigger on, got %d", len(got))
+	}
+	if got := Analyze([]Source{sense}, false); len(got) != 0 {
+		t.Errorf("low-disk Storage Sense should not conflict when our trigger is off, got %v", got)
+	}
+}
+
+func TestMatchCleaner(t *testing.T) {
+	if got := matchCleaner("CCleanerSkipUAC - user"); got != "CCleaner" {
+		t.Errorf("expected CCleaner, got %q", got)
+	}
+	if got := matchCleaner("GoogleUpdateTaskMachineCore"); got != "" {
+		t.Errorf("expected no match, got %q", got)
+	}
+}
+
+func TestParseTaskXML_UTF16(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-16"?>
+<Task><Settings><Enabled>false</Enabled></Settings>
+<Triggers><CalendarTrigger><ScheduleByWeek/></CalendarTrigger><LogonTrigger/></Triggers></Task>`
+	units := utf16.Encode([]rune(doc))
+	data := []byte{0xFF, 0xFE}
+	for _, u := range units {
+		data = binary.LittleEndian.AppendUint16(data, u)
+	}
+
+	task, err := parseTaskXML(data)
+	if err != nil {
+		t.Fatalf("parseTaskXML failed: %v", err)
+	}
+	if task.Settings.Enabled == nil || *task.Settings.Enabled {
+		t.Error("expected task to be parsed as disabled")
+	}
+	if got := taskSchedule(task); got != "weekly, at logon" {
+		t.Errorf("expected schedule %q, got %q", "weekly, at logon", got)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build windows
+
+package conflicts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+// readStorageSense reads the StoragePolicy key. Without the key, Storage
+// Sense has never been turned on.
+func readStorageSense() (StorageSense, error) {
+	values := make(map[string]uint32)
+	k, err := registry.OpenKey(registry.CURRENT_USER, storagePolicyPath, registry.QUERY_VALUE)
+	if err == nil {
+		names, _ := k.ReadValueNames(-1)
+		for _, name := range names {
+			if v, _, err := k.GetIntegerValue(name); err == nil {
+				values[name] = uint32(v)
+			}
+		}
+		k.Close()
+	} else if err != registry.ErrNotExist {
+		return StorageSense{}, err
+	}
+	s := storageSenseFromValues(values)
+	if k, err := registry.OpenKey(registry.LOCAL_MACHINE, storageSensePolicyPath, registry.QUERY_VALUE); err == nil {
+		_, _, err := k.GetIntegerValue("AllowStorageSenseGlobal")
+		s.ManagedByPolicy = err == nil
+		k.Close()
+	}
+	return s, nil
+}
+
+// writeStorageSense writes s to the StoragePolicy key through reglog.
+func writeStorageSense(s StorageSense) error {
+	values := storageSenseValues(s)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := reglog.SetDWordValue(registry.CURRENT_USER, storagePolicyPath, name, values[name]); err != nil {
+			return fmt.Errorf("setting Storage Sense value %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// DisableStorageSense turns Storage Sense off for the current user. The
+// change goes through reglog so it can be undone.
+func DisableStorageSense() error {
+	return reglog.SetDWordValue(registry.CURRENT_USER, storagePolicyPath, policyEnabled, 0)
+}
+
+// detectCleanerTasks scans the Task Scheduler store directly (rather than
+// spawning schtasks.exe) for enabled tasks belonging to known cleaners.
+func detectCleanerTasks() []Source {
+	winDir := os.Getenv("WINDIR")
+	if winDir == "" {
+		return nil
+	}
+	root := filepath.Join(winDir, "System32", "Tasks")
+
+	var sources []Source
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		product := matchCleaner(d.Name())
+		if product == "" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		task, err := parseTaskXML(data)
+		if err != nil || (task.Settings.Enabled != nil && !*task.Settings.Enabled) {
+			return nil
+		}
+		sources = append(sources, Source{
+			Name:       product,
+			Kind:       KindScheduledTask,
+			Schedule:   taskSchedule(task),
+			CleansTemp: true,
+			Path:       path,
+		})
+		return nil
+	})
+	return sources
+}
@@ -0,0 +1,187 @@
+package conflicts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Storage Sense cadences: how often it runs, in days, or on low disk space.
+const (
+	CadenceLowDisk = 0
+	CadenceDaily   = 1
+	CadenceWeekly  = 7
+	CadenceMonthly = 30
+)
+
+// RetentionDays are the ages, in days, Storage Sense offers for deleting
+// files in Downloads and the Recycle Bin; 0 is "Never".
+var RetentionDays = []int{0, 1, 14, 30, 60}
+
+const storagePolicyPath = `Software\Microsoft\Windows\CurrentVersion\StorageSense\Parameters\StoragePolicy`
+
+// storageSensePolicyPath holds the Group Policy settings for Storage Sense.
+const storageSensePolicyPath = `SOFTWARE\Policies\Microsoft\Windows\StorageSense`
+
+// Value names in the StoragePolicy key.
+const (
+	policyEnabled        = "01"
+	policyCleanTemp      = "04"
+	policyRecycleBin     = "08"
+	policyDownloads      = "32"
+	policyRecycleBinDays = "256"
+	policyDownloadsDays  = "512"
+	policyCadence        = "2048"
+)
+
+// StorageSense is the current user's Storage Sense configuration.
+type StorageSense struct {
+	Enabled bool
+	// Cadence is CadenceLowDisk, CadenceDaily, CadenceWeekly or
+	// CadenceMonthly.
+	Cadence int
+	// CleanTemp deletes temporary files apps aren't using.
+	CleanTemp bool
+	// DownloadsDays and RecycleBinDays delete files in Downloads and the
+	// Recycle Bin older than that many days; 0 never does.
+	DownloadsDays  int
+	RecycleBinDays int
+	// ManagedByPolicy reports whether Group Policy sets Storage Sense,
+	// overriding these settings.
+	ManagedByPolicy bool
+}
+
+// CadenceName describes a cadence as Settings does.
+func CadenceName(cadence int) string {
+	switch cadence {
+	case CadenceLowDisk:
+		return "when disk space is low"
+	case CadenceDaily:
+		return "daily"
+	case CadenceWeekly:
+		return "weekly"
+	default:
+		return "monthly"
+	}
+}
+
+// ParseCadence parses "low-disk", "daily", "weekly" or "monthly".
+func ParseCadence(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "low-disk":
+		return CadenceLowDisk, nil
+	case "daily":
+		return CadenceDaily, nil
+	case "weekly":
+		return CadenceWeekly, nil
+	case "monthly":
+		return CadenceMonthly, nil
+	}
+	return 0, fmt.Errorf("unknown cadence %q (valid: low-disk, daily, weekly, monthly)", s)
+}
+
+// Validate reports whether s holds values Settings offers.
+func (s StorageSense) Validate() error {
+	switch s.Cadence {
+	case CadenceLowDisk, CadenceDaily, CadenceWeekly, CadenceMonthly:
+	default:
+		return fmt.Errorf("invalid Storage Sense cadence %d days", s.Cadence)
+	}
+	for _, r := range []struct {
+		what string
+		days int
+	}{{"Downloads", s.DownloadsDays}, {"Recycle Bin", s.RecycleBinDays}} {
+		if !validRetention(r.days) {
+			return fmt.Errorf("invalid %s retention %d days (valid: 0 for never, 1, 14, 30, 60)", r.what, r.days)
+		}
+	}
+	return nil
+}
+
+func validRetention(days int) bool {
+	for _, d := range RetentionDays {
+		if d == days {
+			return true
+		}
+	}
+	return false
+}
+
+// storageSenseFromValues reads a StorageSense from the StoragePolicy
+// key's DWORD values. A missing cadence means on low disk space, as it
+// does to Windows.
+func storageSenseFromValues(values map[string]uint32) StorageSense {
+	s := StorageSense{
+		Enabled:   values[policyEnabled] != 0,
+		Cadence:   int(values[policyCadence]),
+		CleanTemp: values[policyCleanTemp] != 0,
+	}
+	if values[policyDownloads] != 0 {
+		s.DownloadsDays = int(values[policyDownloadsDays])
+	}
+	if values[policyRecycleBin] != 0 {
+		s.RecycleBinDays = int(values[policyRecycleBinDays])
+	}
+	return s
+}
+
+// storageSenseValues returns the StoragePolicy values for s. A retention
+// of "Never" turns its cleanup off and leaves the age as it was.
+func storageSenseValues(s StorageSense) map[string]uint32 {
+	values := map[string]uint32{
+		policyEnabled:    boolDWord(s.Enabled),
+		policyCadence:    uint32(s.Cadence),
+		policyCleanTemp:  boolDWord(s.CleanTemp),
+		policyDownloads:  boolDWord(s.DownloadsDays != 0),
+		policyRecycleBin: boolDWord(s.RecycleBinDays != 0),
+	}
+	if s.DownloadsDays != 0 {
+		values[policyDownloadsDays] = uint32(s.DownloadsDays)
+	}
+	if s.RecycleBinDays != 0 {
+		values[policyRecycleBinDays] = uint32(s.RecycleBinDays)
+	}
+	return values
+}
+
+func boolDWord(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ReadStorageSense returns the current user's Storage Sense settings.
+func ReadStorageSense() (StorageSense, error) {
+	return readStorageSense()
+}
+
+// ConfigureStorageSense sets the current user's Storage Sense settings.
+// The values go through reglog so they can be undone.
+func ConfigureStorageSense(s StorageSense) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	return writeStorageSense(s)
+}
+
+// storageSenseSource describes enabled Storage Sense as a Source.
+func storageSenseSource(s StorageSense) Source {
+	return Source{
+		Name:       "Storage Sense",
+		Kind:       KindStorageSense,
+		Schedule:   CadenceName(s.Cadence),
+		OnLowDisk:  s.Cadence == CadenceLowDisk,
+		CleansTemp: s.CleanTemp,
+		Path:       `HKCU\` + storagePolicyPath,
+	}
+}
+
+// detectStorageSense reports Storage Sense if it is on for the current
+// user.
+func detectStorageSense() (Source, bool) {
+	s, err := readStorageSense()
+	if err != nil || !s.Enabled {
+		return Source{}, false
+	}
+	return storageSenseSource(s), true
+}
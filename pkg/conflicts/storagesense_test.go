@@ -0,0 +1,44 @@
+package conflicts
+
+import "testing"
+
+func TestStorageSenseValues_RoundTrip(t *testing.T) {
+	s := StorageSense{Enabled: true, Cadence: CadenceWeekly, CleanTemp: true, DownloadsDays: 30}
+	values := storageSenseValues(s)
+	if values[policyDownloads] != 1 || values[policyRecycleBin] != 0 {
+		t.Errorf("values = %v; want Downloads cleanup on and Recycle Bin off", values)
+	}
+	if _, ok := values[policyRecycleBinDays]; ok {
+		t.Errorf("values = %v; a retention of never should keep the old age", values)
+	}
+	if got := storageSenseFromValues(values); got != s {
+		t.Errorf("round trip = %+v, want %+v", got, s)
+	}
+
+	// A Recycle Bin age left from before is ignored while cleanup is off.
+	values[policyRecycleBinDays] = 14
+	if got := storageSenseFromValues(values); got.RecycleBinDays != 0 {
+		t.Errorf("RecycleBinDays = %d with cleanup off, want 0", got.RecycleBinDays)
+	}
+}
+
+func TestStorageSenseValidate(t *testing.T) {
+	if err := (StorageSense{Cadence: CadenceMonthly, DownloadsDays: 60, RecycleBinDays: 1}).Validate(); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+	for _, s := range []StorageSense{{Cadence: 3}, {DownloadsDays: 7}, {RecycleBinDays: -1}} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil", s)
+		}
+	}
+	if c, err := ParseCadence("Low-Disk"); err != nil || c != CadenceLowDisk {
+		t.Errorf("ParseCadence = %d, %v", c, err)
+	}
+}
+
+func TestStorageSenseSource(t *testing.T) {
+	src := storageSenseSource(StorageSense{Enabled: true, CleanTemp: true})
+	if !src.OnLowDisk || !src.CleansTemp || src.Schedule != "when disk space is low" {
+		t.Errorf("storageSenseSource = %+v", src)
+	}
+}
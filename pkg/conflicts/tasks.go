@@ -0,0 +1,73 @@
+package conflicts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// taskXML holds the parts of a Task Scheduler definition we inspect.
+type taskXML struct {
+	Settings struct {
+		Enabled *bool `xml:"Enabled"`
+	} `xml:"Settings"`
+	Triggers struct {
+		Calendar []struct {
+			ByDay   *struct{} `xml:"ScheduleByDay"`
+			ByWeek  *struct{} `xml:"ScheduleByWeek"`
+			ByMonth *struct{} `xml:"ScheduleByMonth"`
+		} `xml:"CalendarTrigger"`
+		Logon []struct{} `xml:"LogonTrigger"`
+		Boot  []struct{} `xml:"BootTrigger"`
+		Idle  []struct{} `xml:"IdleTrigger"`
+	} `xml:"Triggers"`
+}
+
+func taskSchedule(t *taskXML) string {
+	var parts []string
+	for _, c := range t.Triggers.Calendar {
+		switch {
+		case c.ByDay != nil:
+			parts = append(parts, "daily")
+		case c.ByWeek != nil:
+			parts = append(parts, "weekly")
+		case c.ByMonth != nil:
+			parts = append(parts, "monthly")
+		}
+	}
+	if len(t.Triggers.Logon) > 0 {
+		parts = append(parts, "at logon")
+	}
+	if len(t.Triggers.Boot) > 0 {
+		parts = append(parts, "at startup")
+	}
+	if len(t.Triggers.Idle) > 0 {
+		parts = append(parts, "when idle")
+	}
+	if len(parts) == 0 {
+		return "scheduled task"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseTaskXML decodes a task file, which Windows stores as UTF-16LE.
+func parseTaskXML(data []byte) (*taskXML, error) {
+	if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+		u := make([]uint16, (len(data)-2)/2)
+		for i := range u {
+			u[i] = binary.LittleEndian.Uint16(data[2+i*2:])
+		}
+		data = []byte(string(utf16.Decode(u)))
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	// The content is already UTF-8; ignore the declared UTF-16 charset.
+	dec.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+	var t taskXML
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
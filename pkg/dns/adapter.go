@@ -0,0 +1,120 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"syscleaner/pkg/admin"
+)
+
+// Adapter is a connected network adapter and its IPv4 DNS servers.
+type Adapter struct {
+	Alias   string   `json:"alias"`
+	Servers []string `json:"servers"`
+	// Static reports whether the servers were set by hand rather than
+	// assigned by DHCP.
+	Static bool `json:"static"`
+}
+
+// These are variables so tests can fake the adapters.
+var (
+	listAdapters = listAdaptersNative
+	runScript    = runScriptNative
+)
+
+// adaptersScript prints the connected physical adapters as Adapter in
+// JSON. Servers set by hand are in the interface's NameServer value, which
+// is empty when they come from DHCP.
+const adaptersScript = `ConvertTo-Json -Compress -Depth 3 -InputObject @(Get-NetAdapter -Physical | Where-Object Status -eq 'Up' | ForEach-Object { ` +
+	`$d = Get-DnsClientServerAddress -InterfaceIndex $_.ifIndex -AddressFamily IPv4 -ErrorAction SilentlyContinue; ` +
+	`$ns = (Get-ItemProperty -Path "HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters\Interfaces\$($_.InterfaceGuid)" -Name NameServer -ErrorAction SilentlyContinue).NameServer; ` +
+	`[pscustomobject]@{ alias = $_.Name; servers = @($d.ServerAddresses); static = [bool]$ns } })`
+
+// parseAdapters parses the output of adaptersScript.
+func parseAdapters(data []byte) ([]Adapter, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var adapters []Adapter
+	if err := json.Unmarshal(data, &adapters); err != nil {
+		return nil, fmt.Errorf("parsing network adapters: %w", err)
+	}
+	return adapters, nil
+}
+
+// Adapters returns the connected network adapters and their DNS servers.
+func Adapters() ([]Adapter, error) {
+	return listAdapters()
+}
+
+// Apply sets the DNS servers of the adapter named alias, or of every
+// connected adapter when alias is "", to r's, and clears the DNS cache.
+func Apply(alias string, r Resolver) error {
+	if err := admin.RequireWriteAccess("setting DNS servers"); err != nil {
+		return err
+	}
+	quoted := make([]string, 0, 2)
+	for _, s := range r.Servers() {
+		quoted = append(quoted, psQuote(s))
+	}
+	return forAdapters(alias, func(a Adapter) string {
+		log.Printf("[SysCleaner] Setting DNS servers of %s to %s (was %s)", a.Alias,
+			strings.Join(r.Servers(), ", "), strings.Join(a.Servers, ", "))
+		return fmt.Sprintf("Set-DnsClientServerAddress -InterfaceAlias %s -ServerAddresses @(%s)",
+			psQuote(a.Alias), strings.Join(quoted, ","))
+	})
+}
+
+// ResetToDHCP makes the adapter named alias, or every connected adapter
+// when alias is "", take its DNS servers from DHCP again, and clears the
+// DNS cache.
+func ResetToDHCP(alias string) error {
+	if err := admin.RequireWriteAccess("resetting DNS servers"); err != nil {
+		return err
+	}
+	return forAdapters(alias, func(a Adapter) string {
+		log.Printf("[SysCleaner] Resetting DNS servers of %s to DHCP (was %s)", a.Alias, strings.Join(a.Servers, ", "))
+		return fmt.Sprintf("Set-DnsClientServerAddress -InterfaceAlias %s -ResetServerAddresses", psQuote(a.Alias))
+	})
+}
+
+// forAdapters runs the command script returns for the adapter named alias,
+// matched case-insensitively, or for every connected adapter when alias is
+// "", then clears the DNS cache so the change applies at once.
+func forAdapters(alias string, script func(Adapter) string) error {
+	adapters, err := listAdapters()
+	if err != nil {
+		return err
+	}
+	var targets []Adapter
+	for _, a := range adapters {
+		if alias == "" || strings.EqualFold(a.Alias, alias) {
+			targets = append(targets, a)
+		}
+	}
+	if len(targets) == 0 {
+		if alias == "" {
+			return errors.New("no connected network adapters")
+		}
+		return fmt.Errorf("no connected network adapter named %q", alias)
+	}
+	var errs []error
+	for _, a := range targets {
+		if err := runScript("$ErrorActionPreference = 'Stop'; " + script(a)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.Alias, err))
+		}
+	}
+	if err := runScript("Clear-DnsClientCache"); err != nil {
+		errs = append(errs, fmt.Errorf("clearing the DNS cache: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
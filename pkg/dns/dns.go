@@ -0,0 +1,240 @@
+// Package dns benchmarks public DNS resolvers and sets the DNS servers of
+// network adapters. The benchmark sends its own queries straight to each
+// resolver, so neither the Windows DNS cache nor the adapter's settings
+// affect the timings.
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver is a DNS service and its IPv4 servers.
+type Resolver struct {
+	Name      string
+	Primary   string
+	Secondary string
+}
+
+// Servers returns the resolver's addresses, primary first.
+func (r Resolver) Servers() []string {
+	if r.Secondary == "" {
+		return []string{r.Primary}
+	}
+	return []string{r.Primary, r.Secondary}
+}
+
+// PublicResolvers are the resolvers Benchmark compares by default.
+var PublicResolvers = []Resolver{
+	{"Cloudflare", "1.1.1.1", "1.0.0.1"},
+	{"Google", "8.8.8.8", "8.8.4.4"},
+	{"Quad9", "9.9.9.9", "149.112.112.112"},
+	{"OpenDNS", "208.67.222.222", "208.67.220.220"},
+	{"AdGuard", "94.140.14.14", "94.140.15.15"},
+}
+
+// SampleDomains are looked up by Benchmark: popular sites and the game
+// stores and chat services gamers use, which resolvers are likely to have
+// cached, as they are in everyday use.
+var SampleDomains = []string{
+	"www.google.com",
+	"www.youtube.com",
+	"www.microsoft.com",
+	"www.wikipedia.org",
+	"store.steampowered.com",
+	"store.epicgames.com",
+	"discord.com",
+	"www.twitch.tv",
+}
+
+// Find returns the public resolver named name, ignoring case.
+func Find(name string) (Resolver, error) {
+	for _, r := range PublicResolvers {
+		if strings.EqualFold(r.Name, name) {
+			return r, nil
+		}
+	}
+	names := make([]string, len(PublicResolvers))
+	for i, r := range PublicResolvers {
+		names[i] = r.Name
+	}
+	return Resolver{}, fmt.Errorf("unknown resolver %q (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// Result is how a resolver did in Benchmark.
+type Result struct {
+	Resolver Resolver
+	// Median and Average are the times of the answered lookups.
+	Median  time.Duration
+	Average time.Duration
+	Queries int
+	Failed  int
+}
+
+// Reliable reports whether the resolver answered at least 90% of the
+// lookups.
+func (r Result) Reliable() bool {
+	return r.Queries > 0 && r.Failed*10 <= r.Queries
+}
+
+// queryTimeout is how long Benchmark waits for an answer.
+const queryTimeout = 2 * time.Second
+
+// exchange sends one query for domain to server and returns how long the
+// answer took. It is a variable so tests can fake the network.
+var exchange = exchangeUDP
+
+// Benchmark looks up each domain rounds times (once when rounds is 0) on
+// each resolver's primary server, and returns the results fastest first.
+// Resolvers are measured at the same time, a domain at a time each, and
+// ones that failed more than one lookup in ten come last.
+func Benchmark(ctx context.Context, resolvers []Resolver, domains []string, rounds int) []Result {
+	if rounds <= 0 {
+		rounds = 1
+	}
+	results := make([]Result, len(resolvers))
+	var wg sync.WaitGroup
+	for i, r := range resolvers {
+		wg.Add(1)
+		go func(i int, r Resolver) {
+			defer wg.Done()
+			results[i] = benchmarkResolver(ctx, r, domains, rounds)
+		}(i, r)
+	}
+	wg.Wait()
+	rank := func(r Result) int {
+		switch {
+		case r.Reliable():
+			return 0
+		case r.Failed < r.Queries:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(results, func(a, b int) bool {
+		if ra, rb := rank(results[a]), rank(results[b]); ra != rb {
+			return ra < rb
+		}
+		return results[a].Median < results[b].Median
+	})
+	return results
+}
+
+func benchmarkResolver(ctx context.Context, r Resolver, domains []string, rounds int) Result {
+	result := Result{Resolver: r}
+	var times []time.Duration
+	for round := 0; round < rounds; round++ {
+		for _, domain := range domains {
+			if ctx.Err() != nil {
+				break
+			}
+			result.Queries++
+			qctx, cancel := context.WithTimeout(ctx, queryTimeout)
+			d, err := exchange(qctx, r.Primary, domain)
+			cancel()
+			if err != nil {
+				result.Failed++
+				continue
+			}
+			times = append(times, d)
+		}
+	}
+	if len(times) == 0 {
+		return result
+	}
+	var total time.Duration
+	for _, d := range times {
+		total += d
+	}
+	sort.Slice(times, func(a, b int) bool { return times[a] < times[b] })
+	result.Median = times[len(times)/2]
+	result.Average = total / time.Duration(len(times))
+	return result
+}
+
+// Recommend returns the fastest reliable result of a Benchmark, if any.
+func Recommend(results []Result) (Result, bool) {
+	for _, r := range results {
+		if r.Reliable() {
+			return r, true
+		}
+	}
+	return Result{}, false
+}
+
+// buildQuery returns a recursive query for domain's A records.
+func buildQuery(id uint16, domain string) ([]byte, error) {
+	msg := []byte{byte(id >> 8), byte(id), 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+	for _, label := range strings.Split(strings.TrimSuffix(domain, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid domain %q", domain)
+		}
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	return append(msg, 0, 0, 1, 0, 1), nil
+}
+
+// checkResponse reports whether resp answers query id with at least one
+// record.
+func checkResponse(id uint16, resp []byte) error {
+	if len(resp) < 12 {
+		return errors.New("short DNS response")
+	}
+	if binary.BigEndian.Uint16(resp) != id || resp[2]&0x80 == 0 {
+		return errors.New("not a response to the query")
+	}
+	if rcode := resp[3] & 0x0f; rcode != 0 {
+		return fmt.Errorf("DNS error code %d", rcode)
+	}
+	if binary.BigEndian.Uint16(resp[6:]) == 0 {
+		return errors.New("no answer")
+	}
+	return nil
+}
+
+func exchangeUDP(ctx context.Context, server, domain string) (time.Duration, error) {
+	id := uint16(rand.Intn(1 << 16))
+	query, err := buildQuery(id, domain)
+	if err != nil {
+		return 0, err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(server, "53"))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	start := time.Now()
+	if _, err := conn.Write(query); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 1232)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		// A stray datagram with another ID is not the answer; keep
+		// waiting for it.
+		if n >= 2 && binary.BigEndian.Uint16(buf) != id {
+			continue
+		}
+		if err := checkResponse(id, buf[:n]); err != nil {
+			return 0, fmt.Errorf("%s: %w", domain, err)
+		}
+		return time.Since(start), nil
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package dns
+
+import "fmt"
+
+var errNoAdapters = fmt.Errorf("setting DNS servers is only available on Windows")
+
+func listAdaptersNative() ([]Adapter, error) {
+	return nil, errNoAdapters
+}
+
+func runScriptNative(script string) error {
+	return errNoAdapters
+}
@@ -0,0 +1,134 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildQuery(t *testing.T) {
+	q, err := buildQuery(0xbeef, "discord.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{0xbe, 0xef, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, 7}, "discord\x03com\x00\x00\x01\x00\x01"...)
+	if string(q) != string(want) {
+		t.Errorf("query = %x, want %x", q, want)
+	}
+	if _, err := buildQuery(1, "bad..domain"); err == nil {
+		t.Error("an empty label should be rejected")
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	resp := make([]byte, 12)
+	binary.BigEndian.PutUint16(resp, 42)
+	resp[2] = 0x81
+	resp[3] = 0x80
+	binary.BigEndian.PutUint16(resp[6:], 1)
+	if err := checkResponse(42, resp); err != nil {
+		t.Errorf("valid response: %v", err)
+	}
+	if err := checkResponse(43, resp); err == nil {
+		t.Error("a response to another query should be rejected")
+	}
+	resp[3] = 0x83 // NXDOMAIN
+	if err := checkResponse(42, resp); err == nil {
+		t.Error("NXDOMAIN should be an error")
+	}
+	if err := checkResponse(42, resp[:8]); err == nil {
+		t.Error("a short response should be rejected")
+	}
+}
+
+func TestBenchmark(t *testing.T) {
+	orig := exchange
+	t.Cleanup(func() { exchange = orig })
+	delays := map[string]time.Duration{"1.1.1.1": 12 * time.Millisecond, "8.8.8.8": 9 * time.Millisecond, "9.9.9.9": 5 * time.Millisecond}
+	exchange = func(ctx context.Context, server, domain string) (time.Duration, error) {
+		// Quad9 is fastest but drops one lookup in four.
+		if server == "9.9.9.9" && strings.HasPrefix(domain, "a") {
+			return 0, errors.New("timeout")
+		}
+		if server == "10.0.0.1" {
+			return 0, errors.New("unreachable")
+		}
+		return delays[server], nil
+	}
+
+	resolvers := []Resolver{
+		{"Dead", "10.0.0.1", ""},
+		{"Quad9", "9.9.9.9", ""},
+		{"Cloudflare", "1.1.1.1", ""},
+		{"Google", "8.8.8.8", ""},
+	}
+	results := Benchmark(context.Background(), resolvers, []string{"a.example", "b.example", "c.example", "d.example"}, 2)
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Resolver.Name)
+	}
+	if got := strings.Join(order, ","); got != "Google,Cloudflare,Quad9,Dead" {
+		t.Errorf("order = %s, want Google,Cloudflare,Quad9,Dead", got)
+	}
+	if results[0].Queries != 8 || results[0].Median != 9*time.Millisecond {
+		t.Errorf("Google = %+v, want 8 queries with a 9ms median", results[0])
+	}
+	if best, ok := Recommend(results); !ok || best.Resolver.Name != "Google" {
+		t.Errorf("Recommend = %+v, %v; want Google", best, ok)
+	}
+	if _, ok := Recommend(results[3:]); ok {
+		t.Error("a resolver that never answered should not be recommended")
+	}
+}
+
+func TestApplyAndReset(t *testing.T) {
+	origList, origRun := listAdapters, runScript
+	t.Cleanup(func() { listAdapters, runScript = origList, origRun })
+	listAdapters = func() ([]Adapter, error) {
+		return []Adapter{{Alias: "Ethernet", Servers: []string{"192.168.1.1"}}, {Alias: "Bob's Wi-Fi"}}, nil
+	}
+	var scripts []string
+	runScript = func(script string) error {
+		scripts = append(scripts, script)
+		return nil
+	}
+
+	cloudflare, err := Find("cloudflare")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply("ethernet", cloudflare); err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 2 || !strings.HasSuffix(scripts[0], "-InterfaceAlias 'Ethernet' -ServerAddresses @('1.1.1.1','1.0.0.1')") ||
+		scripts[1] != "Clear-DnsClientCache" {
+		t.Errorf("Apply ran %q", scripts)
+	}
+
+	scripts = nil
+	if err := ResetToDHCP(""); err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 3 || !strings.HasSuffix(scripts[1], "-InterfaceAlias 'Bob''s Wi-Fi' -ResetServerAddresses") {
+		t.Errorf("ResetToDHCP ran %q", scripts)
+	}
+	if err := Apply("VPN", cloudflare); err == nil {
+		t.Error("an unknown adapter should be an error")
+	}
+	if _, err := Find("Level3"); err == nil {
+		t.Error("an unknown resolver should be an error")
+	}
+}
+
+func TestParseAdapters(t *testing.T) {
+	adapters, err := parseAdapters([]byte(`[{"alias":"Ethernet","servers":["1.1.1.1","1.0.0.1"],"static":true}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adapters) != 1 || !adapters[0].Static || len(adapters[0].Servers) != 2 {
+		t.Errorf("adapters = %+v", adapters)
+	}
+}
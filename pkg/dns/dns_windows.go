@@ -0,0 +1,35 @@
+//go:build windows
+
+package dns
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// powershell runs script and returns its output.
+func powershell(script string) ([]byte, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	// NOTE: Do NOT set HideWindow: true — it triggers AV heuristics.
+	cmd.SysProcAttr = &syscall.SysProcAttr{}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+func listAdaptersNative() ([]Adapter, error) {
+	out, err := powershell(adaptersScript)
+	if err != nil {
+		return nil, fmt.Errorf("listing network adapters: %w", err)
+	}
+	return parseAdapters(out)
+}
+
+func runScriptNative(script string) error {
+	_, err := powershell(script)
+	return err
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/cleaner"
+)
+
+// Category names a cleaning category, e.g. "User Temp" or "Chrome Cache".
+// Categories lists the built-in ones.
+type Category string
+
+// Categories returns every built-in cleaning category in cleaning order.
+func Categories() []Category {
+	all := cleaner.AllCategories()
+	out := make([]Category, len(all))
+	for i, c := range all {
+		out[i] = Category(c)
+	}
+	return out
+}
+
+// CleanRequest selects what Clean removes.
+type CleanRequest struct {
+	// Categories to clean. When both Categories and Rules are empty, the
+	// config's default clean selection is used.
+	Categories []Category
+
+	// Rules lists rule-pack rule IDs to run as well.
+	Rules []string
+
+	// DryRun reports what would be removed without deleting anything.
+	DryRun bool
+
+	// Background runs at low I/O priority and paces deletions, for
+	// unattended cleans.
+	Background bool
+}
+
+// CleanReport is the outcome of a clean or estimate.
+type CleanReport struct {
+	// OperationID identifies the run in the SysCleaner log.
+	OperationID     string
+	DryRun          bool
+	FilesDeleted    int64
+	SkippedFiles    int64
+	LockedFiles     int64
+	PermissionFiles int64
+	SpaceFreed      int64
+	Duration        time.Duration
+
+	// Categories holds the per-category breakdown, largest first.
+	Categories []CategoryReport
+
+	// Skipped lists categories left out because the process is not
+	// elevated.
+	Skipped []Skip
+
+	Errors []error
+}
+
+// CategoryReport is one category's share of a CleanReport.
+type CategoryReport struct {
+	Category     Category
+	FilesDeleted int64
+	SkippedFiles int64
+	SpaceFreed   int64
+	Duration     time.Duration
+	Errors       []error
+}
+
+// Clean removes the files selected by req. Categories that need
+// administrator rights are skipped when the process is not elevated. If ctx
+// ends first, the report covers the categories that finished and the
+// context's error is returned with it.
+func (e *Engine) Clean(ctx context.Context, req CleanRequest) (CleanReport, error) {
+	opts, err := e.cleanOptions(req)
+	if err != nil {
+		return CleanReport{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return CleanReport{}, err
+	}
+	opts, skips := cleaner.PlanForPrivileges(opts, admin.IsElevated())
+	result := cleaner.PerformCleanContext(ctx, opts)
+	return newCleanReport(result, skips), ctx.Err()
+}
+
+// Estimate reports what Clean would remove for req without deleting
+// anything.
+func (e *Engine) Estimate(ctx context.Context, req CleanRequest) (CleanReport, error) {
+	req.DryRun = true
+	return e.Clean(ctx, req)
+}
+
+func (e *Engine) cleanOptions(req CleanRequest) (cleaner.CleanOptions, error) {
+	var opts cleaner.CleanOptions
+	if len(req.Categories) == 0 && len(req.Rules) == 0 {
+		opts = e.cfg.DefaultCleanOptions
+	} else {
+		if err := opts.Select(toCleanerCategories(req.Categories)...); err != nil {
+			return cleaner.CleanOptions{}, err
+		}
+		opts.Rules = req.Rules
+	}
+	opts.DryRun = req.DryRun || e.dryRun
+	opts.Background = req.Background
+	if e.volumes != nil {
+		opts.Volumes = *e.volumes
+	} else {
+		opts.Volumes = e.cfg.ActiveVolumePolicy()
+	}
+	opts.Progress = nil
+	if e.progress != nil {
+		progress := e.progress
+		opts.Progress = func(category string, current, total int64) {
+			progress(Category(category), current, total)
+		}
+	}
+	return opts, nil
+}
+
+func toCleanerCategories(cats []Category) []cleaner.Category {
+	out := make([]cleaner.Category, len(cats))
+	for i, c := range cats {
+		out[i] = cleaner.Category(c)
+	}
+	return out
+}
+
+func newCleanReport(r cleaner.CleanResult, skips []admin.Skip) CleanReport {
+	rep := CleanReport{
+		OperationID:     r.OperationID,
+		DryRun:          r.DryRun,
+		FilesDeleted:    r.FilesDeleted,
+		SkippedFiles:    r.SkippedFiles,
+		LockedFiles:     r.LockedFiles,
+		PermissionFiles: r.PermissionFiles,
+		SpaceFreed:      r.SpaceFreed,
+		Duration:        r.Duration,
+		Skipped:         toSkips(skips),
+		Errors:          r.Errors,
+	}
+	for _, name := range r.CategoriesBySpace() {
+		c := r.Categories[name]
+		rep.Categories = append(rep.Categories, CategoryReport{
+			Category:     Category(name),
+			FilesDeleted: c.FilesDeleted,
+			SkippedFiles: c.SkippedFiles,
+			SpaceFreed:   c.SpaceFreed,
+			Duration:     c.Duration,
+			Errors:       c.Errors,
+		})
+	}
+	return rep
+}
@@ -0,0 +1,188 @@
+// Package engine exposes SysCleaner's cleaning, optimization, monitoring
+// and gaming mode as a Go library, for tools that want to embed them
+// rather than run the syscleaner executable.
+//
+// # Compatibility
+//
+// The exported API of this package follows semantic versioning, tracked by
+// Version. Within a major version, exported names are not removed or
+// changed incompatibly; new fields and methods may be added. The packages
+// it wraps (cleaner, optimizer, monitor, gaming, ...) are implementation
+// details of the application and may change in any release, so embedders
+// should depend on engine alone.
+//
+// # Process-wide state
+//
+// The underlying packages keep their tuning knobs, audit mode and gaming
+// mode state in package-level variables. New applies the given settings to
+// them, so a process should create a single Engine; later Engines replace
+// the settings of earlier ones.
+//
+// # Example
+//
+//	eng, err := engine.New(engine.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	report, err := eng.Estimate(ctx, engine.CleanRequest{
+//		Categories: []engine.Category{"User Temp", "Chrome Cache"},
+//	})
+package engine
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+)
+
+// Version is the semantic version of the engine API.
+const Version = "1.0.0"
+
+// ErrReadOnly is returned (wrapped) by operations refused because the
+// engine is in read-only audit mode.
+var ErrReadOnly = admin.ErrAuditMode
+
+// Options configures New.
+type Options struct {
+	// Settings is what to run with instead of the user's SysCleaner
+	// config. When nil, the user's config is loaded, including
+	// SYSCLEANER_* overrides.
+	Settings *Settings
+
+	// DryRun turns every Clean into a preview that deletes nothing.
+	DryRun bool
+
+	// ReadOnly puts the process into audit mode: operations that would
+	// change the system are refused with ErrReadOnly and cleans become
+	// previews. Audit mode is also on when the config requests it.
+	ReadOnly bool
+
+	// Progress, when set, is called as each clean category starts and
+	// finishes.
+	Progress func(category Category, done, total int64)
+}
+
+// Settings configures an Engine independently of the user's SysCleaner
+// config. The zero value runs with SysCleaner's defaults.
+type Settings struct {
+	// DefaultCategories is what a CleanRequest with no categories or
+	// rules cleans. When empty, SysCleaner's default selection is used.
+	DefaultCategories []Category
+
+	// DisabledCategories are never cleaned, even when requested.
+	DisabledCategories []Category
+
+	// Exclusions are path patterns cleans never touch, in filepath.Match
+	// syntax. A pattern ending in a path separator excludes everything
+	// below that directory.
+	Exclusions []string
+
+	// NeverCleanVolumes lists drives, e.g. "D:", that cleans never touch.
+	NeverCleanVolumes []string
+
+	// Quarantine moves cleaned files to SysCleaner's quarantine folder
+	// instead of deleting them.
+	Quarantine bool
+
+	// ProcessWhitelist lists executables gaming mode never suspends or
+	// closes.
+	ProcessWhitelist []string
+
+	// Workers is how many categories are cleaned at once. Zero picks a
+	// number suited to the hardware.
+	Workers int
+}
+
+// config maps s onto SysCleaner's default config. The user's profiles are
+// not consulted.
+func (s Settings) config() (*config.Config, error) {
+	cfg := config.DefaultConfig()
+	cfg.ActiveProfile = ""
+	if len(s.DefaultCategories) > 0 {
+		var opts cleaner.CleanOptions
+		if err := opts.Select(toCleanerCategories(s.DefaultCategories)...); err != nil {
+			return nil, fmt.Errorf("default categories: %w", err)
+		}
+		cfg.DefaultCleanOptions = opts
+	}
+	for _, c := range s.DisabledCategories {
+		cfg.Cleaner.DisabledCategories = append(cfg.Cleaner.DisabledCategories, string(c))
+	}
+	cfg.Cleaner.Exclusions = append([]string(nil), s.Exclusions...)
+	cfg.Cleaner.Quarantine = s.Quarantine
+	cfg.ProcessWhitelist = append([]string(nil), s.ProcessWhitelist...)
+	if s.Workers > 0 {
+		cfg.Performance.CleanerWorkers = s.Workers
+	}
+	return cfg, nil
+}
+
+// Engine runs SysCleaner operations. Its methods are safe for concurrent
+// use, although running two cleans at once gains nothing.
+type Engine struct {
+	cfg *config.Config
+	// volumes is the volume policy from Settings; when nil, the active
+	// profile's is used.
+	volumes  *cleaner.VolumePolicy
+	dryRun   bool
+	progress func(Category, int64, int64)
+}
+
+// Skip is an item left out of an operation because the process is not
+// elevated, with the reason.
+type Skip struct {
+	Item   string
+	Reason string
+}
+
+// New returns an Engine using opts, applying its settings' performance and
+// cleaner options.
+func New(opts Options) (*Engine, error) {
+	e := &Engine{dryRun: opts.DryRun, progress: opts.Progress}
+	if opts.Settings != nil {
+		cfg, err := opts.Settings.config()
+		if err != nil {
+			return nil, err
+		}
+		e.cfg = cfg
+		e.volumes = &cleaner.VolumePolicy{Never: append([]string(nil), opts.Settings.NeverCleanVolumes...)}
+	} else {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		e.cfg = cfg
+	}
+	e.cfg.Performance.Apply()
+	e.cfg.Cleaner.Apply()
+	if opts.ReadOnly || e.cfg.AuditMode {
+		admin.SetAuditMode(true)
+	}
+	return e, nil
+}
+
+// Elevated reports whether the process has administrator rights. Without
+// them, operations leave out what needs them and list it in Skipped.
+func (e *Engine) Elevated() bool {
+	return admin.IsElevated()
+}
+
+// ReadOnly reports whether audit mode is on.
+func (e *Engine) ReadOnly() bool {
+	return admin.AuditMode()
+}
+
+func toSkips(skips []admin.Skip) []Skip {
+	if len(skips) == 0 {
+		return nil
+	}
+	out := make([]Skip, len(skips))
+	for i, s := range skips {
+		out[i] = Skip{Item: s.Item, Reason: s.Reason}
+	}
+	return out
+}
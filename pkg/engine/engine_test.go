@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"syscleaner/pkg/admin"
+)
+
+func newTestEngine(t *testing.T, opts Options) *Engine {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { admin.SetAuditMode(false) })
+	if opts.Settings == nil {
+		opts.Settings = &Settings{}
+	}
+	eng, err := New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return eng
+}
+
+func TestCategoriesNonEmpty(t *testing.T) {
+	cats := Categories()
+	if len(cats) == 0 || cats[0] != "Windows Temp" {
+		t.Errorf("unexpected categories %v", cats)
+	}
+}
+
+func TestCleanOptionsFromCategories(t *testing.T) {
+	eng := newTestEngine(t, Options{DryRun: true})
+	opts, err := eng.cleanOptions(CleanRequest{Categories: []Category{"User Temp", "Chrome Cache"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.UserTemp || !opts.ChromeCache || opts.WindowsTemp {
+		t.Errorf("wrong selection: %v", opts.Categories())
+	}
+	if !opts.DryRun {
+		t.Error("engine DryRun should force a dry run")
+	}
+}
+
+func TestCleanOptionsDefaultSelection(t *testing.T) {
+	eng := newTestEngine(t, Options{Settings: &Settings{
+		DefaultCategories: []Category{"Steam Cache"},
+		NeverCleanVolumes: []string{"D:"},
+	}})
+	opts, err := eng.cleanOptions(CleanRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.SteamCache || opts.UserTemp {
+		t.Errorf("empty request should use the settings' default selection, got %v", opts.Categories())
+	}
+	if len(opts.Volumes.Never) != 1 || opts.Volumes.Never[0] != "D:" {
+		t.Errorf("volume policy = %+v, want the settings' NeverCleanVolumes", opts.Volumes)
+	}
+}
+
+func TestNewUnknownDefaultCategory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if _, err := New(Options{Settings: &Settings{DefaultCategories: []Category{"Nope"}}}); err == nil {
+		t.Error("expected an error for an unknown default category")
+	}
+}
+
+func TestCleanUnknownCategory(t *testing.T) {
+	eng := newTestEngine(t, Options{})
+	if _, err := eng.Clean(context.Background(), CleanRequest{Categories: []Category{"Nope"}}); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+}
+
+func TestCleanCanceledContext(t *testing.T) {
+	eng := newTestEngine(t, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := eng.Clean(ctx, CleanRequest{Categories: []Category{"User Temp"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestOptimizeCanceledContext(t *testing.T) {
+	eng := newTestEngine(t, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rep, err := eng.Optimize(ctx, OptimizeRequest{Startup: true})
+	if !errors.Is(err, context.Canceled) || rep.Startup != nil {
+		t.Errorf("expected nothing to run, got %+v, %v", rep, err)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	eng := newTestEngine(t, Options{ReadOnly: true})
+	if !eng.ReadOnly() {
+		t.Fatal("ReadOnly option should turn on audit mode")
+	}
+	if err := admin.RequireWriteAccess("test"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"syscleaner/pkg/gaming"
+)
+
+// GamingRequest configures EnableGaming. The active profile's suspend
+// list, focus mode, GPU preference, Windows game settings, timer
+// resolution, power plan, standby purge and QoS tags are applied on top of it.
+type GamingRequest struct {
+	// AutoDetectGames raises the priority of known games as they start.
+	AutoDetectGames bool
+
+	// SuspendProcesses lists executables to suspend while gaming mode is
+	// on, in addition to the profile's.
+	SuspendProcesses []string
+
+	// FocusMode suppresses notifications and focus stealing.
+	FocusMode bool
+
+	// Game names the game about to be played, by name or executable. Its
+	// entry in the config's game overrides, if any, is applied.
+	Game string
+
+	// RunHooks launches the active profile's gaming hooks.
+	RunHooks bool
+}
+
+// GamingStatus describes gaming mode and the games it can see.
+type GamingStatus struct {
+	Enabled         bool
+	Games           []Game
+	CPUPercent      float64
+	RAMPercent      float64
+	StoppedServices []string
+}
+
+// Game is a running game process.
+type Game struct {
+	Name       string
+	PID        int32
+	CPUPercent float64
+	RAMBytes   uint64
+}
+
+// EnableGaming turns gaming mode on. Hook failures are returned together
+// after gaming mode is enabled; gaming mode stays on in that case.
+func (e *Engine) EnableGaming(ctx context.Context, req GamingRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	profile := e.cfg.ActiveProfileSettings()
+	gaming.SetQoSOverrides(profile.GamingConfig.QoSDSCP)
+	gaming.SetLatencyTargets(profile.GamingConfig.ServerEndpoints)
+	gaming.SetGameOverrides(e.cfg.GameOverrides)
+	cfg := gaming.Config{
+		AutoDetectGames:       req.AutoDetectGames,
+		SuspendProcesses:      append(append([]string(nil), profile.GamingConfig.SuspendWhileGaming...), req.SuspendProcesses...),
+		SuspendBackgroundApps: profile.GamingConfig.SuspendBackgroundApps,
+		Whitelist:             e.cfg.EffectiveWhitelist(),
+		FocusMode:             req.FocusMode || profile.GamingConfig.FocusMode,
+		DiscreteGPU:           profile.GamingConfig.DiscreteGPU,
+		GameSettings:          profile.GamingConfig.WindowsGameSettings,
+		HighResTimer:          profile.GamingConfig.HighResTimer,
+		PowerPlan:             profile.GamingConfig.PowerPlan,
+		PurgeStandby:          profile.GamingConfig.PurgeStandby,
+		USBLatency:            profile.GamingConfig.USBLatency,
+		TrimWorkingSets:       profile.GamingConfig.TrimWorkingSets,
+		Streaming:             profile.StreamingConfig(),
+		Game:                  req.Game,
+	}
+	if err := gaming.Enable(cfg); err != nil {
+		return err
+	}
+	if req.RunHooks {
+		if errs := profile.RunHooks(profile.Hooks.GamingEnable); len(errs) > 0 {
+			return fmt.Errorf("gaming hooks: %w", errors.Join(errs...))
+		}
+	}
+	return nil
+}
+
+// DisableGaming turns gaming mode off and restores what EnableGaming
+// changed. runHooks launches the active profile's gaming-disable hooks.
+func (e *Engine) DisableGaming(ctx context.Context, runHooks bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := gaming.Disable(); err != nil {
+		return err
+	}
+	if runHooks {
+		profile := e.cfg.ActiveProfileSettings()
+		if errs := profile.RunHooks(profile.Hooks.GamingDisable); len(errs) > 0 {
+			return fmt.Errorf("gaming hooks: %w", errors.Join(errs...))
+		}
+	}
+	return nil
+}
+
+// GamingState reports whether gaming mode is on, along with system load
+// and running games.
+func (e *Engine) GamingState(ctx context.Context) (GamingStatus, error) {
+	done := make(chan gaming.Status, 1)
+	go func() { done <- gaming.GetStatus() }()
+	var s gaming.Status
+	select {
+	case s = <-done:
+	case <-ctx.Done():
+		return GamingStatus{}, ctx.Err()
+	}
+
+	status := GamingStatus{
+		Enabled:         s.Enabled,
+		CPUPercent:      s.CPUUsage,
+		RAMPercent:      s.RAMUsagePercent,
+		StoppedServices: append([]string(nil), s.StoppedServices...),
+	}
+	for _, g := range s.ActiveGames {
+		status.Games = append(status.Games, Game{Name: g.Name, PID: g.PID, CPUPercent: g.CPUUsage, RAMBytes: g.RAMUsage})
+	}
+	return status, nil
+}
+
+// AntiCheatReport lists the anti-cheats running and the processes gaming
+// mode leaves alone because of them.
+type AntiCheatReport struct {
+	AntiCheats []AntiCheat
+	Protected  []ProtectedProcess
+}
+
+// AntiCheat is a running anti-cheat, with the processes, services and
+// drivers it was recognized by.
+type AntiCheat struct {
+	Name  string
+	Found []string
+}
+
+// ProtectedProcess is a process gaming mode will not boost, suspend or
+// close, with the reason.
+type ProtectedProcess struct {
+	Name   string
+	PID    int32
+	Reason string
+}
+
+// AntiCheatCheck reports the anti-cheats running and the processes the
+// anti-cheat guard protects.
+func (e *Engine) AntiCheatCheck(ctx context.Context) (AntiCheatReport, error) {
+	done := make(chan gaming.AntiCheatReport, 1)
+	go func() { done <- gaming.CheckAntiCheat() }()
+	var r gaming.AntiCheatReport
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		return AntiCheatReport{}, ctx.Err()
+	}
+
+	var report AntiCheatReport
+	for _, ac := range r.Active {
+		report.AntiCheats = append(report.AntiCheats, AntiCheat{Name: ac.Name, Found: append([]string(nil), ac.Found...)})
+	}
+	for _, p := range r.Protected {
+		report.Protected = append(report.Protected, ProtectedProcess{Name: p.Name, PID: p.PID, Reason: p.Reason})
+	}
+	return report, nil
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"syscleaner/pkg/monitor"
+)
+
+// cpuSampleWindow is how long Snapshot measures CPU load over.
+const cpuSampleWindow = 500 * time.Millisecond
+
+// SnapshotRequest selects what Snapshot samples beyond CPU and memory.
+type SnapshotRequest struct {
+	// Drives to report, e.g. "C:" or "D:". When empty, the system drive is
+	// reported.
+	Drives []string
+
+	// GPU samples the GPU's power state and load, which can take a few
+	// seconds.
+	GPU bool
+}
+
+// Snapshot is a point-in-time view of system load.
+type Snapshot struct {
+	Taken      time.Time
+	CPUPercent float64
+	RAMUsed    uint64
+	RAMTotal   uint64
+	RAMPercent float64
+	Drives     []DriveStatus
+
+	// TimerResolution is the system timer resolution, or 0 where it can't
+	// be read.
+	TimerResolution time.Duration
+
+	// GPU is nil unless requested.
+	GPU *GPUStatus
+}
+
+// DriveStatus is the free space on one drive. Low is set when free space
+// is below the low-disk warning threshold.
+type DriveStatus struct {
+	Drive       string
+	TotalGB     float64
+	FreeGB      float64
+	FreePercent float64
+	Low         bool
+}
+
+// GPUStatus is the GPU's power state and load. Fields the driver doesn't
+// report are left empty.
+type GPUStatus struct {
+	Name             string
+	PowerState       string
+	GraphicsClockMHz int
+	MemoryClockMHz   int
+	Utilization      float64
+	// VRAMUsed and VRAMTotal are dedicated video memory in bytes, and
+	// TemperatureC the core temperature (NVIDIA only).
+	VRAMUsed     uint64
+	VRAMTotal    uint64
+	TemperatureC int
+	// Awake is set when the GPU is not in a low-power state.
+	Awake bool
+	// GPUBound is set when the GPU is at full load, and VRAMPressure when
+	// video memory is nearly full.
+	GPUBound     bool
+	VRAMPressure bool
+}
+
+// Snapshot samples CPU, memory, drive and optionally GPU usage.
+func (e *Engine) Snapshot(ctx context.Context, req SnapshotRequest) (Snapshot, error) {
+	snap := Snapshot{Taken: time.Now()}
+
+	percent, err := cpu.PercentWithContext(ctx, cpuSampleWindow, false)
+	if err != nil {
+		return snap, fmt.Errorf("sampling CPU: %w", err)
+	}
+	if len(percent) > 0 {
+		snap.CPUPercent = percent[0]
+	}
+	vmem, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return snap, fmt.Errorf("reading memory: %w", err)
+	}
+	snap.RAMUsed, snap.RAMTotal, snap.RAMPercent = vmem.Used, vmem.Total, vmem.UsedPercent
+
+	statuses := []monitor.DiskStatus{}
+	if len(req.Drives) == 0 {
+		statuses = append(statuses, monitor.CheckDiskSpace())
+	}
+	for _, d := range req.Drives {
+		statuses = append(statuses, monitor.CheckDrive(d))
+	}
+	for _, s := range statuses {
+		snap.Drives = append(snap.Drives, DriveStatus{
+			Drive:       s.DriveLetter,
+			TotalGB:     s.TotalGB,
+			FreeGB:      s.FreeGB,
+			FreePercent: s.FreePercent,
+			Low:         s.Warning,
+		})
+	}
+
+	if res, err := monitor.CheckTimerResolution(); err == nil {
+		snap.TimerResolution = res.Current
+	}
+
+	if req.GPU {
+		done := make(chan monitor.GPUStatus, 1)
+		go func() { done <- monitor.CheckGPU() }()
+		select {
+		case g := <-done:
+			snap.GPU = &GPUStatus{
+				Name:             g.Name,
+				PowerState:       g.PowerState,
+				GraphicsClockMHz: g.GraphicsClockMHz,
+				MemoryClockMHz:   g.MemoryClockMHz,
+				Utilization:      g.Utilization,
+				VRAMUsed:         g.VRAMUsed,
+				VRAMTotal:        g.VRAMTotal,
+				TemperatureC:     g.TemperatureC,
+				Awake:            g.Awake,
+				GPUBound:         g.GPUBound(),
+				VRAMPressure:     g.VRAMPressure(),
+			}
+		case <-ctx.Done():
+			return snap, ctx.Err()
+		}
+	}
+	return snap, nil
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/optimizer"
+)
+
+// OptimizeRequest selects which optimizations Optimize runs.
+type OptimizeRequest struct {
+	Startup bool
+	Network bool
+	Disk    bool
+	// DryRun reports what would change without changing it (see
+	// optimizer.OptimizeOptions).
+	DryRun bool
+}
+
+// OptimizeReport is the outcome of Optimize. A section is nil when it was
+// not requested, was skipped for lack of rights, or ctx ended first.
+type OptimizeReport struct {
+	Startup *StartupReport
+	Network *NetworkReport
+	Disk    *DiskReport
+
+	// Skipped lists optimizations left out because the process is not
+	// elevated.
+	Skipped []Skip
+}
+
+// StartupReport lists the startup entries that were examined.
+type StartupReport struct {
+	Disabled int
+	Programs []StartupProgram
+	// OperationID identifies the run in the SysCleaner log, as in the
+	// other sections.
+	OperationID string
+}
+
+// StartupProgram is a startup entry and whether it is disabled.
+type StartupProgram struct {
+	Name        string
+	Path        string
+	Impact      string
+	StartupCost time.Duration
+	Source      string
+	Location    string
+	Disabled    bool
+	DisabledAt  time.Time
+}
+
+// NetworkReport lists the network settings changed (or, in read-only
+// mode, the ones that would be).
+type NetworkReport struct {
+	Changes     []string
+	OperationID string
+}
+
+// DiskReport describes the system drive, whether any maintenance was run
+// or scheduled, and what was done for each volume.
+type DiskReport struct {
+	IsSSD       bool
+	Scheduled   bool
+	Volumes     []DiskVolume
+	OperationID string
+}
+
+// DiskVolume is a volume, the media type of its disk and the maintenance
+// chosen for it (see optimizer.VolumeAction).
+type DiskVolume struct {
+	Drive     string
+	MediaType string
+	Action    string
+	Done      bool
+	Error     string
+}
+
+// Optimize runs the optimizations selected by req in the order startup,
+// network, disk, stopping before the next one once ctx is done.
+// Optimizations that need administrator rights are skipped when the
+// process is not elevated.
+func (e *Engine) Optimize(ctx context.Context, req OptimizeRequest) (OptimizeReport, error) {
+	var rep OptimizeReport
+	startup, network, disk, skips := optimizer.PlanForPrivileges(req.Startup, req.Network, req.Disk, admin.IsElevated())
+	rep.Skipped = toSkips(skips)
+	opts := optimizer.OptimizeOptions{DryRun: req.DryRun}
+
+	if startup {
+		if err := ctx.Err(); err != nil {
+			return rep, err
+		}
+		r := optimizer.OptimizeStartup(opts)
+		rep.Startup = &StartupReport{Disabled: r.Disabled, OperationID: r.OperationID}
+		for _, p := range r.Programs {
+			rep.Startup.Programs = append(rep.Startup.Programs, StartupProgram(p))
+		}
+	}
+	if network {
+		if err := ctx.Err(); err != nil {
+			return rep, err
+		}
+		r := optimizer.OptimizeNetwork(opts)
+		rep.Network = &NetworkReport{Changes: r.Optimizations, OperationID: r.OperationID}
+	}
+	if disk {
+		if err := ctx.Err(); err != nil {
+			return rep, err
+		}
+		r := optimizer.OptimizeDisk(opts)
+		rep.Disk = &DiskReport{IsSSD: r.IsSSD, Scheduled: r.Scheduled, OperationID: r.OperationID}
+		for _, v := range r.Volumes {
+			rep.Disk.Volumes = append(rep.Disk.Volumes, DiskVolume(v))
+		}
+	}
+	return rep, nil
+}
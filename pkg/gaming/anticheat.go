@@ -0,0 +1,288 @@
+package gaming
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// AntiCheat describes a kernel anti-cheat by the processes, services and
+// drivers that show it is installed and running.
+type AntiCheat struct {
+	Name      string
+	Processes []string
+	Services  []string
+	// Drivers are kernel driver services, queried like services.
+	Drivers []string
+}
+
+// AntiCheats are the anti-cheats the guard recognizes. While one is
+// running, the guard never boosts, suspends, closes or stops its
+// processes and services; never suspends games, their children or the
+// other processes their launcher started; and leaves out the tweaks
+// anti-cheats treat as tampering with a game: changing its CPU affinity
+// and raising it above the above-normal priority class.
+var AntiCheats = []AntiCheat{
+	{
+		Name:      "Easy Anti-Cheat",
+		Processes: []string{"EasyAntiCheat.exe", "EasyAntiCheat_EOS.exe", "start_protected_game.exe"},
+		Services:  []string{"EasyAntiCheat", "EasyAntiCheat_EOS"},
+		Drivers:   []string{"EasyAntiCheatSys"},
+	},
+	{
+		Name:      "BattlEye",
+		Processes: []string{"BEService.exe", "BEService_x64.exe"},
+		Services:  []string{"BEService"},
+		Drivers:   []string{"BEDaisy"},
+	},
+	{
+		Name:      "Riot Vanguard",
+		Processes: []string{"vgc.exe", "vgtray.exe"},
+		Services:  []string{"vgc"},
+		Drivers:   []string{"vgk"},
+	},
+	{
+		Name:    "Ricochet",
+		Drivers: []string{"atvi-randgrid_sr", "randgrid"},
+	},
+}
+
+// guardPriorityCap is ABOVE_NORMAL_PRIORITY_CLASS, the highest class the
+// guard lets a game have while an anti-cheat runs.
+const guardPriorityCap = 0x8000
+
+// antiCheatCacheTTL is how long a detection result is reused; detecting
+// queries the service manager for every known service and driver.
+const antiCheatCacheTTL = 30 * time.Second
+
+// serviceRunning reports whether a service or driver is running. It is a
+// variable so tests can fake the service manager.
+var serviceRunning = serviceRunningNative
+
+// listProcesses returns the running processes. It is a variable so tests
+// can fake the process list.
+var listProcesses = func() []procInfo {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil
+	}
+	out := make([]procInfo, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		ppid, _ := p.Ppid()
+		out = append(out, procInfo{PID: p.Pid, PPID: ppid, Name: name})
+	}
+	return out
+}
+
+// procInfo is the part of a process the guard looks at.
+type procInfo struct {
+	PID  int32
+	PPID int32
+	Name string
+}
+
+// DetectedAntiCheat is an anti-cheat found running, with what gave it away.
+type DetectedAntiCheat struct {
+	Name  string
+	Found []string
+}
+
+// ProtectedProcess is a running process the guard will not touch.
+type ProtectedProcess struct {
+	PID    int32
+	Name   string
+	Reason string
+}
+
+// AntiCheatReport is the result of CheckAntiCheat.
+type AntiCheatReport struct {
+	Active    []DetectedAntiCheat
+	Protected []ProtectedProcess
+}
+
+var (
+	antiCheatMu      sync.Mutex
+	antiCheatChecked time.Time
+	antiCheatActive  []DetectedAntiCheat
+)
+
+// CheckAntiCheat reports the anti-cheats running now and the processes the
+// guard protects because of them.
+func CheckAntiCheat() AntiCheatReport {
+	procs := listProcesses()
+	active := detectAntiCheats(procs)
+	cacheAntiCheats(active)
+	return AntiCheatReport{Active: active, Protected: protectedProcesses(procs, len(active) > 0)}
+}
+
+// activeAntiCheats returns the running anti-cheats, detecting them again
+// once the cached result is older than antiCheatCacheTTL.
+func activeAntiCheats() []DetectedAntiCheat {
+	antiCheatMu.Lock()
+	if time.Since(antiCheatChecked) < antiCheatCacheTTL {
+		defer antiCheatMu.Unlock()
+		return antiCheatActive
+	}
+	antiCheatMu.Unlock()
+	active := detectAntiCheats(listProcesses())
+	cacheAntiCheats(active)
+	return active
+}
+
+func cacheAntiCheats(active []DetectedAntiCheat) {
+	antiCheatMu.Lock()
+	defer antiCheatMu.Unlock()
+	antiCheatActive = active
+	antiCheatChecked = time.Now()
+}
+
+// detectAntiCheats returns the anti-cheats with a process in procs or a
+// running service or driver.
+func detectAntiCheats(procs []procInfo) []DetectedAntiCheat {
+	running := make(map[string]bool, len(procs))
+	for _, p := range procs {
+		running[strings.ToLower(p.Name)] = true
+	}
+	var active []DetectedAntiCheat
+	for _, ac := range AntiCheats {
+		var found []string
+		for _, exe := range ac.Processes {
+			if running[strings.ToLower(exe)] {
+				found = append(found, exe)
+			}
+		}
+		for _, svc := range append(append([]string(nil), ac.Services...), ac.Drivers...) {
+			if serviceRunning(svc) {
+				found = append(found, svc)
+			}
+		}
+		if len(found) > 0 {
+			active = append(active, DetectedAntiCheat{Name: ac.Name, Found: found})
+		}
+	}
+	return active
+}
+
+// antiCheatFor returns the anti-cheat a process or service name belongs
+// to, or "".
+func antiCheatFor(name string) string {
+	for _, ac := range AntiCheats {
+		for _, list := range [][]string{ac.Processes, ac.Services, ac.Drivers} {
+			for _, n := range list {
+				if strings.EqualFold(n, name) {
+					return ac.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// protectedProcesses returns the processes in procs the guard protects:
+// anti-cheat processes always, and while an anti-cheat is active, game
+// processes, their children and their siblings. Siblings started by the
+// Windows shell are not protected, since that is nearly everything the
+// user opened.
+func protectedProcesses(procs []procInfo, antiCheatActive bool) []ProtectedProcess {
+	byPID := make(map[int32]procInfo, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+	reasons := make(map[int32]string)
+	launchers := make(map[int32]string)
+	for _, p := range procs {
+		if ac := antiCheatFor(p.Name); ac != "" {
+			reasons[p.PID] = ac
+			continue
+		}
+		if antiCheatActive && isGameProcess(p.Name) {
+			reasons[p.PID] = "game protected by anti-cheat"
+			if parent, ok := byPID[p.PPID]; ok && p.PPID != 0 && !isShellProcess(parent.Name) {
+				launchers[p.PPID] = p.Name
+			}
+		}
+	}
+	direct := make(map[int32]string, len(reasons))
+	for pid, reason := range reasons {
+		direct[pid] = reason
+	}
+	for _, p := range procs {
+		if _, ok := reasons[p.PID]; ok {
+			continue
+		}
+		if game, ok := launchers[p.PPID]; ok {
+			reasons[p.PID] = "started alongside " + game
+		} else if parent, ok := direct[p.PPID]; ok && p.PPID != 0 {
+			reasons[p.PID] = "child of a protected process (" + parent + ")"
+		}
+	}
+
+	out := make([]ProtectedProcess, 0, len(reasons))
+	for pid, reason := range reasons {
+		out = append(out, ProtectedProcess{PID: pid, Name: byPID[pid].Name, Reason: reason})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PID < out[j].PID })
+	return out
+}
+
+func isShellProcess(name string) bool {
+	for _, s := range shellProcesses {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// guardLog records a guard decision.
+func guardLog(format string, args ...any) {
+	log.Printf("[SysCleaner] Anti-cheat guard: "+format, args...)
+}
+
+// guardedPIDs returns the PIDs the guard protects right now, for callers
+// about to suspend or close processes.
+func guardedPIDs() map[uint32]ProtectedProcess {
+	procs := listProcesses()
+	protected := protectedProcesses(procs, len(activeAntiCheats()) > 0)
+	out := make(map[uint32]ProtectedProcess, len(protected))
+	for _, p := range protected {
+		out[uint32(p.PID)] = p
+	}
+	return out
+}
+
+// guardProcessName reports whether the guard refuses action on the
+// process named name, logging the decision.
+func guardProcessName(action, name string) bool {
+	if ac := antiCheatFor(name); ac != "" {
+		guardLog("not %s %s (%s)", action, name, ac)
+		return true
+	}
+	return false
+}
+
+// guardGameTweaks returns the priority class and affinity mask to apply
+// to a game, with tweaks anti-cheats treat as tampering removed while one
+// is active.
+func guardGameTweaks(name string, class uint32, mask uint64) (uint32, uint64) {
+	if len(activeAntiCheats()) == 0 {
+		return class, mask
+	}
+	if class == highPriorityClass {
+		guardLog("capping %s at above normal priority", name)
+		class = guardPriorityCap
+	}
+	if mask != 0 {
+		guardLog("not changing the CPU affinity of %s", name)
+		mask = 0
+	}
+	return class, mask
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package gaming
+
+func serviceRunningNative(name string) bool {
+	return false
+}
@@ -0,0 +1,96 @@
+package gaming
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeAntiCheats makes the guard see the given services running, with
+// detection results cached as if just taken.
+func fakeAntiCheats(t *testing.T, services ...string) {
+	t.Helper()
+	origRunning, origList := serviceRunning, listProcesses
+	running := make(map[string]bool)
+	for _, s := range services {
+		running[s] = true
+	}
+	serviceRunning = func(name string) bool { return running[name] }
+	listProcesses = func() []procInfo { return nil }
+	cacheAntiCheats(detectAntiCheats(nil))
+	t.Cleanup(func() {
+		serviceRunning, listProcesses = origRunning, origList
+		cacheAntiCheats(nil)
+		antiCheatChecked = time.Time{}
+	})
+}
+
+func TestDetectAntiCheats(t *testing.T) {
+	fakeAntiCheats(t, "vgk")
+	active := detectAntiCheats([]procInfo{{PID: 10, Name: "BEService.exe"}, {PID: 11, Name: "notepad.exe"}})
+	if len(active) != 2 || active[0].Name != "BattlEye" || active[1].Name != "Riot Vanguard" {
+		t.Errorf("active = %+v", active)
+	}
+}
+
+func TestProtectedProcesses(t *testing.T) {
+	procs := []procInfo{
+		{PID: 1, PPID: 0, Name: "explorer.exe"},
+		{PID: 2, PPID: 1, Name: "EpicGamesLauncher.exe"},
+		{PID: 3, PPID: 2, Name: "FortniteClient-Win64-Shipping.exe"},
+		{PID: 4, PPID: 2, Name: "EpicWebHelper.exe"},
+		{PID: 5, PPID: 3, Name: "CrashReportClient.exe"},
+		{PID: 6, PPID: 1, Name: "chrome.exe"},
+		{PID: 7, PPID: 1, Name: "EasyAntiCheat_EOS.exe"},
+	}
+	got := make(map[int32]string)
+	for _, p := range protectedProcesses(procs, true) {
+		got[p.PID] = p.Reason
+	}
+	for _, pid := range []int32{3, 4, 5, 7} {
+		if got[pid] == "" {
+			t.Errorf("PID %d not protected", pid)
+		}
+	}
+	for _, pid := range []int32{1, 2, 6} {
+		if r, ok := got[pid]; ok {
+			t.Errorf("PID %d protected: %s", pid, r)
+		}
+	}
+
+	// Without an active anti-cheat only anti-cheat processes are kept.
+	inactive := protectedProcesses(procs, false)
+	if len(inactive) != 1 || inactive[0].PID != 7 {
+		t.Errorf("inactive = %+v", inactive)
+	}
+}
+
+func TestGuardGameTweaks(t *testing.T) {
+	fakeAntiCheats(t)
+	if class, mask := guardGameTweaks("game.exe", highPriorityClass, 0xF); class != highPriorityClass || mask != 0xF {
+		t.Errorf("no anti-cheat: class 0x%x mask 0x%x", class, mask)
+	}
+
+	fakeAntiCheats(t, "EasyAntiCheat")
+	if class, mask := guardGameTweaks("game.exe", highPriorityClass, 0xF); class != guardPriorityCap || mask != 0 {
+		t.Errorf("anti-cheat running: class 0x%x mask 0x%x", class, mask)
+	}
+	if class, _ := guardGameTweaks("game.exe", normalPriorityClass, 0); class != normalPriorityClass {
+		t.Errorf("normal priority changed to 0x%x", class)
+	}
+}
+
+func TestGuardedNames(t *testing.T) {
+	if !guardProcessName("stopping", "BEService") || !guardProcessName("suspending", "vgtray.exe") {
+		t.Error("anti-cheat names not guarded")
+	}
+	if guardProcessName("suspending", "chrome.exe") {
+		t.Error("chrome.exe guarded")
+	}
+	list := suspendList(Config{SuspendProcesses: []string{"vgtray.exe", "Spotify.exe"}})
+	if len(list) != 1 || list[0] != "Spotify.exe" {
+		t.Errorf("suspendList = %v", list)
+	}
+	if err := stopService("vgc"); err == nil {
+		t.Error("stopService stopped an anti-cheat service")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package gaming
+
+import (
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceRunningNative reports whether the service or kernel driver name
+// is running, asking the Service Control Manager.
+func serviceRunningNative(name string) bool {
+	m, err := mgr.Connect()
+	if err != nil {
+		return false
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return false
+	}
+	defer s.Close()
+
+	status, err := s.Query()
+	return err == nil && status.State == svc.Running
+}
@@ -0,0 +1,218 @@
+package gaming
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// battleNetProduct names a Battle.net product code and gives the game's
+// executable relative to its install folder.
+type battleNetProduct struct {
+	name string
+	exe  string
+}
+
+// battleNetProducts lists the games whose product codes and executables
+// are known. Other installed products are reported under their code, with
+// the executables found in their install folder.
+var battleNetProducts = map[string]battleNetProduct{
+	"wow":         {"World of Warcraft", `_retail_\Wow.exe`},
+	"wow_classic": {"World of Warcraft Classic", `_classic_\WowClassic.exe`},
+	"pro":         {"Overwatch 2", `_retail_\Overwatch.exe`},
+	"fenris":      {"Diablo IV", "Diablo IV.exe"},
+	"osi":         {"Diablo II: Resurrected", "D2R.exe"},
+	"d3":          {"Diablo III", `x64\Diablo III64.exe`},
+	"anbs":        {"Diablo Immortal", "DiabloImmortal.exe"},
+	"hs_beta":     {"Hearthstone", "Hearthstone.exe"},
+	"w3":          {"Warcraft III", `_retail_\x86_64\Warcraft III.exe`},
+	"s2":          {"StarCraft II", `Support64\SC2Switcher_x64.exe`},
+}
+
+// battleNetDBPath returns the Battle.net agent's product database:
+// %ProgramData%\Battle.net\Agent\product.db.
+func battleNetDBPath() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		return ""
+	}
+	return filepath.Join(programData, "Battle.net", "Agent", "product.db")
+}
+
+// scanBattleNet reads the installed games from the Battle.net product
+// database at path, a protobuf-encoded list of product installs. A missing
+// file means Battle.net is not installed.
+func scanBattleNet(path string) ([]InstalledGame, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	installs, err := protoFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var games []InstalledGame
+	for _, f := range installs {
+		if f.num != 1 || f.wire != 2 { // Database.product_install
+			continue
+		}
+		g, ok, err := battleNetInstall(f.bytes)
+		if err != nil {
+			return games, err
+		}
+		if ok {
+			games = append(games, g)
+		}
+	}
+	return games, nil
+}
+
+// battleNetInstall decodes one ProductInstall message: uid (1),
+// product_code (2), settings (3) whose install_path is field 1, and
+// cached_product_state (4) whose base_product_state (1) has installed (1).
+func battleNetInstall(msg []byte) (InstalledGame, bool, error) {
+	fields, err := protoFields(msg)
+	if err != nil {
+		return InstalledGame{}, false, err
+	}
+	var uid, code, installPath string
+	installed := false
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.wire == 2:
+			uid = string(f.bytes)
+		case f.num == 2 && f.wire == 2:
+			code = string(f.bytes)
+		case f.num == 3 && f.wire == 2:
+			installPath = string(protoField1(f.bytes))
+		case f.num == 4 && f.wire == 2:
+			if base := protoField1(f.bytes); base != nil {
+				if state, err := protoFields(base); err == nil {
+					for _, s := range state {
+						if s.num == 1 && s.wire == 0 {
+							installed = s.varint != 0
+						}
+					}
+				}
+			}
+		}
+	}
+	// The agent and the launcher itself are listed as products too.
+	if !installed || installPath == "" || code == "" || code == "agent" || code == "bna" || uid == "battle.net" {
+		return InstalledGame{}, false, nil
+	}
+
+	g := InstalledGame{Name: code, Launcher: "Battle.net", ID: code, InstallDir: filepath.FromSlash(installPath)}
+	if p, ok := battleNetProducts[code]; ok {
+		g.Name = p.name
+		g.Executables = []string{filepath.Join(g.InstallDir, filepath.FromSlash(strings.ReplaceAll(p.exe, `\`, "/")))}
+	} else {
+		g.Executables = gameExecutablesIn(g.InstallDir)
+	}
+	return g, true, nil
+}
+
+// helperExeWords mark executables in a game folder that are not the game.
+var helperExeWords = []string{"launcher", "crash", "error", "uninstall", "unins", "setup", "redist", "updater", "helper", "report"}
+
+// gameExecutablesIn returns the executables directly in dir, leaving out
+// launchers, crash reporters, installers and the like.
+func gameExecutablesIn(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var exes []string
+	for _, e := range entries {
+		name := strings.ToLower(e.Name())
+		if e.IsDir() || filepath.Ext(name) != ".exe" {
+			continue
+		}
+		helper := false
+		for _, w := range helperExeWords {
+			if strings.Contains(name, w) {
+				helper = true
+				break
+			}
+		}
+		if !helper {
+			exes = append(exes, filepath.Join(dir, e.Name()))
+		}
+	}
+	return exes
+}
+
+// protoField is one field of an encoded protobuf message. varint holds
+// varint and fixed-size values; bytes holds length-delimited ones.
+type protoField struct {
+	num    int
+	wire   int
+	varint uint64
+	bytes  []byte
+}
+
+var errBadProtobuf = errors.New("malformed protobuf data")
+
+// protoFields splits an encoded protobuf message into its fields.
+func protoFields(data []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errBadProtobuf
+		}
+		data = data[n:]
+		f := protoField{num: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case 0:
+			f.varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, errBadProtobuf
+			}
+			data = data[n:]
+		case 1:
+			if len(data) < 8 {
+				return nil, errBadProtobuf
+			}
+			f.varint, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return nil, errBadProtobuf
+			}
+			f.bytes, data = data[n:n+int(size)], data[n+int(size):]
+		case 5:
+			if len(data) < 4 {
+				return nil, errBadProtobuf
+			}
+			f.varint, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return nil, errBadProtobuf
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// protoField1 returns the length-delimited field 1 of msg, or nil.
+func protoField1(msg []byte) []byte {
+	fields, err := protoFields(msg)
+	if err != nil {
+		return nil
+	}
+	for _, f := range fields {
+		if f.num == 1 && f.wire == 2 {
+			return f.bytes
+		}
+	}
+	return nil
+}
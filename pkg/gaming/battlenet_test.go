@@ -0,0 +1,81 @@
+package gaming
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// protoBytes encodes a length-delimited protobuf field.
+func protoBytes(num int, data []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(num<<3|2))
+	out = binary.AppendUvarint(out, uint64(len(data)))
+	return append(out, data...)
+}
+
+// protoBool encodes a varint protobuf field.
+func protoBool(num int, v bool) []byte {
+	out := binary.AppendUvarint(nil, uint64(num<<3))
+	if v {
+		return append(out, 1)
+	}
+	return append(out, 0)
+}
+
+func battleNetInstallMsg(uid, code, path string, installed bool) []byte {
+	var msg []byte
+	msg = append(msg, protoBytes(1, []byte(uid))...)
+	msg = append(msg, protoBytes(2, []byte(code))...)
+	msg = append(msg, protoBytes(3, protoBytes(1, []byte(path)))...)
+	state := protoBytes(1, append(protoBool(1, installed), protoBool(2, installed)...))
+	msg = append(msg, protoBytes(4, state)...)
+	return protoBytes(1, msg)
+}
+
+func TestScanBattleNet(t *testing.T) {
+	dir := t.TempDir()
+	unknownDir := filepath.Join(dir, "Mystery")
+	if err := os.MkdirAll(unknownDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Mystery.exe", "Mystery Launcher.exe", "BlizzardError.exe"} {
+		if err := os.WriteFile(filepath.Join(unknownDir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var db []byte
+	db = append(db, battleNetInstallMsg("diablo4", "fenris", "C:/Program Files (x86)/Diablo IV", true)...)
+	db = append(db, battleNetInstallMsg("mystery", "myst", unknownDir, true)...)
+	db = append(db, battleNetInstallMsg("wow", "wow", "C:/Games/WoW", false)...)
+	db = append(db, battleNetInstallMsg("battle.net", "bna", "C:/Battle.net", true)...)
+	path := filepath.Join(dir, "product.db")
+	if err := os.WriteFile(path, db, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	games, err := scanBattleNet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("got %d games, want 2: %+v", len(games), games)
+	}
+	if games[0].Name != "Diablo IV" || filepath.Base(games[0].Executables[0]) != "Diablo IV.exe" {
+		t.Errorf("games[0] = %+v", games[0])
+	}
+	if games[1].Name != "myst" || len(games[1].Executables) != 1 || filepath.Base(games[1].Executables[0]) != "Mystery.exe" {
+		t.Errorf("games[1] = %+v", games[1])
+	}
+
+	if games, err := scanBattleNet(filepath.Join(dir, "missing.db")); err != nil || games != nil {
+		t.Errorf("missing database = %v, %v", games, err)
+	}
+	if err := os.WriteFile(path, []byte{0x0a, 0xff}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scanBattleNet(path); err == nil {
+		t.Error("truncated database: want error")
+	}
+}
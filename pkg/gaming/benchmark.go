@@ -0,0 +1,125 @@
+package gaming
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"syscleaner/pkg/memory"
+	"syscleaner/pkg/monitor"
+)
+
+// BenchmarkMetrics is one set of measurements taken by Benchmark.
+type BenchmarkMetrics struct {
+	Taken      time.Time
+	FreeRAM    uint64 // bytes available
+	StandbyRAM uint64 // bytes on the standby list; 0 where unknown
+	Processes  int
+	DiskFreeGB float64 // on the system drive
+	// TimerLatencyAvg and TimerLatencyMax are how late 1 ms sleeps wake
+	// up. Long DPCs and interrupts delay wake-ups, so this is a rough,
+	// unprivileged stand-in for a DPC latency measurement.
+	TimerLatencyAvg time.Duration
+	TimerLatencyMax time.Duration
+}
+
+// BenchmarkReport compares measurements before and after optimizing.
+type BenchmarkReport struct {
+	Before BenchmarkMetrics
+	After  BenchmarkMetrics
+}
+
+// benchmarkSettle is how long Benchmark waits after optimizing before it
+// measures again, so stopped services and purged memory have settled.
+var benchmarkSettle = 5 * time.Second
+
+// timerSamples is how many sleeps a timer latency sample takes.
+const timerSamples = 200
+
+// MeasureBenchmark takes one set of measurements.
+func MeasureBenchmark() BenchmarkMetrics {
+	m := BenchmarkMetrics{Taken: time.Now()}
+	if vmem, err := mem.VirtualMemory(); err == nil {
+		m.FreeRAM = vmem.Available
+	}
+	m.StandbyRAM = uint64(memory.GetCurrentStats().StandbyGB * 1024 * 1024 * 1024)
+	if pids, err := process.Pids(); err == nil {
+		m.Processes = len(pids)
+	}
+	m.DiskFreeGB = monitor.CheckDiskSpace().FreeGB
+	m.TimerLatencyAvg, m.TimerLatencyMax = sampleTimerLatency(timerSamples)
+	return m
+}
+
+// sampleTimerLatency sleeps for 1 ms n times and returns the average and
+// worst oversleep.
+func sampleTimerLatency(n int) (avg, worst time.Duration) {
+	var total time.Duration
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		time.Sleep(time.Millisecond)
+		late := time.Since(start) - time.Millisecond
+		if late < 0 {
+			late = 0
+		}
+		total += late
+		worst = max(worst, late)
+	}
+	return total / time.Duration(n), worst
+}
+
+// Benchmark measures the system, runs optimize, waits for things to
+// settle and measures again. The report is returned along with optimize's
+// error, if any.
+func Benchmark(optimize func() error) (BenchmarkReport, error) {
+	r := BenchmarkReport{Before: MeasureBenchmark()}
+	err := optimize()
+	time.Sleep(benchmarkSettle)
+	r.After = MeasureBenchmark()
+	return r, err
+}
+
+// String formats the report as a before/after table.
+func (r BenchmarkReport) String() string {
+	const gb = 1024 * 1024 * 1024
+	var b strings.Builder
+	row := func(name, before, after, change string) {
+		fmt.Fprintf(&b, "%-22s %12s %12s %-18s\n", name, before, after, change)
+	}
+	gbRow := func(name string, before, after float64, moreIsBetter bool) {
+		row(name, fmt.Sprintf("%.2f GB", before), fmt.Sprintf("%.2f GB", after), signed(after-before, "%+.2f GB", moreIsBetter))
+	}
+	msRow := func(name string, before, after time.Duration) {
+		ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+		row(name, fmt.Sprintf("%.3f ms", ms(before)), fmt.Sprintf("%.3f ms", ms(after)),
+			signed(ms(after)-ms(before), "%+.3f ms", false))
+	}
+
+	row("Metric", "Before", "After", "Change")
+	b.WriteString(strings.Repeat("-", 66) + "\n")
+	gbRow("Free RAM", float64(r.Before.FreeRAM)/gb, float64(r.After.FreeRAM)/gb, true)
+	gbRow("Standby list", float64(r.Before.StandbyRAM)/gb, float64(r.After.StandbyRAM)/gb, false)
+	row("Processes", fmt.Sprint(r.Before.Processes), fmt.Sprint(r.After.Processes),
+		signed(float64(r.After.Processes-r.Before.Processes), "%+.0f", false))
+	gbRow("Disk free", r.Before.DiskFreeGB, r.After.DiskFreeGB, true)
+	msRow("Timer latency (avg)", r.Before.TimerLatencyAvg, r.After.TimerLatencyAvg)
+	msRow("Timer latency (max)", r.Before.TimerLatencyMax, r.After.TimerLatencyMax)
+	return b.String()
+}
+
+// signed formats a change and says whether it is better or worse;
+// changes too small to show are left unmarked.
+func signed(delta float64, format string, moreIsBetter bool) string {
+	s := fmt.Sprintf(format, delta)
+	if fmt.Sprintf(format, math.Abs(delta)) == fmt.Sprintf(format, 0.0) {
+		return s
+	}
+	if (delta > 0) == moreIsBetter {
+		return s + " better"
+	}
+	return s + " worse"
+}
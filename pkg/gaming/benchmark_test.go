@@ -0,0 +1,46 @@
+package gaming
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigned(t *testing.T) {
+	for _, c := range []struct {
+		delta        float64
+		moreIsBetter bool
+		want         string
+	}{
+		{1.5, true, "+1.50 GB better"},
+		{-1.5, true, "-1.50 GB worse"},
+		{-1.5, false, "-1.50 GB better"},
+		{0.001, true, "+0.00 GB"},
+		{-0.001, true, "-0.00 GB"},
+	} {
+		if got := signed(c.delta, "%+.2f GB", c.moreIsBetter); got != c.want {
+			t.Errorf("signed(%v, %v) = %q, want %q", c.delta, c.moreIsBetter, got, c.want)
+		}
+	}
+}
+
+func TestBenchmarkReport_String(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+	r := BenchmarkReport{
+		Before: BenchmarkMetrics{FreeRAM: 4 * gb, Processes: 250, TimerLatencyMax: 2 * time.Millisecond},
+		After:  BenchmarkMetrics{FreeRAM: 6 * gb, Processes: 230, TimerLatencyMax: time.Millisecond},
+	}
+	out := r.String()
+	for _, want := range []string{"+2.00 GB better", "-20 better", "-1.000 ms better"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSampleTimerLatency(t *testing.T) {
+	avg, worst := sampleTimerLatency(5)
+	if avg < 0 || worst < avg {
+		t.Errorf("avg %v, worst %v", avg, worst)
+	}
+}
@@ -0,0 +1,131 @@
+package gaming
+
+import (
+	"encoding/xml"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// eaInstallerData holds the fields SysCleaner uses from the
+// __Installer\installerdata.xml file in an EA app (or Origin) game folder.
+type eaInstallerData struct {
+	Titles []struct {
+		Locale string `xml:"locale,attr"`
+		Title  string `xml:",chardata"`
+	} `xml:"gameTitles>gameTitle"`
+	// Older manifests put the title under metadata.
+	LocaleTitles []struct {
+		Locale string `xml:"locale,attr"`
+		Title  string `xml:"title"`
+	} `xml:"metadata>localeInfo"`
+	Launchers []struct {
+		FilePath string `xml:"filePath"`
+		Trial    bool   `xml:"trial"`
+	} `xml:"runtime>launcher"`
+}
+
+// eaManifestDir returns where the EA app, like Origin before it, keeps a
+// .mfst manifest for each installed game: %ProgramData%\Origin\LocalContent.
+func eaManifestDir() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		return ""
+	}
+	return filepath.Join(programData, "Origin", "LocalContent")
+}
+
+// scanEAManifests reads the .mfst manifests in the game folders under dir.
+// Each is a URL query string whose dipinstallpath is the game's install
+// folder; the game's title and executable come from the installerdata.xml
+// in that folder. Games whose install folder is gone are skipped.
+func scanEAManifests(dir string) ([]InstalledGame, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*.mfst"))
+	if err != nil {
+		return nil, err
+	}
+	var games []InstalledGame
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		q, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(string(data)), "?"))
+		if err != nil {
+			continue
+		}
+		installDir := filepath.FromSlash(strings.ReplaceAll(q.Get("dipinstallpath"), `\`, "/"))
+		if installDir == "" {
+			continue
+		}
+		if _, err := os.Stat(installDir); err != nil {
+			continue
+		}
+		g := InstalledGame{
+			Name:       filepath.Base(filepath.Dir(path)),
+			Launcher:   "EA app",
+			ID:         q.Get("id"),
+			InstallDir: installDir,
+		}
+		if data, err := os.ReadFile(filepath.Join(installDir, "__Installer", "installerdata.xml")); err == nil {
+			var inst eaInstallerData
+			if xml.Unmarshal(data, &inst) == nil {
+				if title := inst.title(); title != "" {
+					g.Name = title
+				}
+				g.Executables = inst.executables(installDir)
+			}
+		}
+		if len(g.Executables) == 0 {
+			g.Executables = gameExecutablesIn(installDir)
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+// title returns the game's English title, or its first one.
+func (d eaInstallerData) title() string {
+	var titles []string
+	for _, t := range d.Titles {
+		if strings.EqualFold(t.Locale, "en_US") {
+			return strings.TrimSpace(t.Title)
+		}
+		titles = append(titles, t.Title)
+	}
+	for _, t := range d.LocaleTitles {
+		if strings.EqualFold(t.Locale, "en_US") {
+			return strings.TrimSpace(t.Title)
+		}
+		titles = append(titles, t.Title)
+	}
+	if len(titles) > 0 {
+		return strings.TrimSpace(titles[0])
+	}
+	return ""
+}
+
+// executables returns the launch executables under installDir. Launcher
+// paths start with a registry reference to the install folder, such as
+// [HKEY_LOCAL_MACHINE\SOFTWARE\Respawn\Apex\Install Dir]r5apex.exe.
+func (d eaInstallerData) executables(installDir string) []string {
+	var exes []string
+	for _, l := range d.Launchers {
+		if l.Trial {
+			continue
+		}
+		p := strings.TrimSpace(l.FilePath)
+		if i := strings.Index(p, "]"); strings.HasPrefix(p, "[") && i >= 0 {
+			p = p[i+1:]
+		}
+		if p == "" || !strings.EqualFold(filepath.Ext(p), ".exe") {
+			continue
+		}
+		exes = append(exes, filepath.Join(installDir, filepath.FromSlash(strings.ReplaceAll(p, `\`, "/"))))
+	}
+	return exes
+}
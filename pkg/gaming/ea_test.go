@@ -0,0 +1,66 @@
+package gaming
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanEAManifests(t *testing.T) {
+	manifests := t.TempDir()
+	install := filepath.Join(t.TempDir(), "Apex")
+	if err := os.MkdirAll(filepath.Join(install, "__Installer"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	installerData := `<DiPManifest version="4.0">
+  <gameTitles><gameTitle locale="de_DE">Apex Legends DE</gameTitle><gameTitle locale="en_US">Apex Legends</gameTitle></gameTitles>
+  <runtime>
+    <launcher><filePath>[HKEY_LOCAL_MACHINE\SOFTWARE\Respawn\Apex\Install Dir]r5apex.exe</filePath><trial>0</trial></launcher>
+  </runtime>
+</DiPManifest>`
+	if err := os.WriteFile(filepath.Join(install, "__Installer", "installerdata.xml"), []byte(installerData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(game, query string) {
+		dir := filepath.Join(manifests, game)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, game+".mfst"), []byte("?"+query), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Apex", "currentstate=kReadyToStart&dipinstallpath="+url.QueryEscape(install)+"&id=Origin.OFR.50.0002694")
+	write("Gone", "dipinstallpath="+url.QueryEscape(filepath.Join(install, "..", "Gone"))+"&id=x")
+
+	games, err := scanEAManifests(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 {
+		t.Fatalf("got %d games, want 1: %+v", len(games), games)
+	}
+	g := games[0]
+	if g.Name != "Apex Legends" || g.ID != "Origin.OFR.50.0002694" || g.Launcher != "EA app" {
+		t.Errorf("game = %+v", g)
+	}
+	if len(g.Executables) != 1 || g.Executables[0] != filepath.Join(install, "r5apex.exe") {
+		t.Errorf("executables = %v", g.Executables)
+	}
+}
+
+func TestGOGGame(t *testing.T) {
+	g, ok := gogGame("1207658924", map[string]string{
+		"gameName": "The Witcher 3",
+		"path":     `C:\GOG Games\The Witcher 3`,
+		"exe":      `C:\GOG Games\The Witcher 3\bin\x64\witcher3.exe`,
+	})
+	if !ok || g.Name != "The Witcher 3" || g.ID != "1207658924" || len(g.Executables) != 1 {
+		t.Errorf("gogGame = %+v, %v", g, ok)
+	}
+	if _, ok := gogGame("1", map[string]string{"gameName": "No Path"}); ok {
+		t.Error("gogGame accepted an install without a path")
+	}
+}
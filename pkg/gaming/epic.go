@@ -0,0 +1,74 @@
+package gaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// epicManifest holds the fields SysCleaner uses from an Epic Games
+// Launcher .item manifest.
+type epicManifest struct {
+	DisplayName         string `json:"DisplayName"`
+	AppName             string `json:"AppName"`
+	InstallLocation     string `json:"InstallLocation"`
+	LaunchExecutable    string `json:"LaunchExecutable"`
+	IncompleteInstall   bool   `json:"bIsIncompleteInstall"`
+	CatalogItemID       string `json:"CatalogItemId"`
+	MainGameCatalogItem string `json:"MainGameCatalogItemId"`
+}
+
+// epicManifestDir returns where the Epic Games Launcher keeps its install
+// manifests: %ProgramData%\Epic\EpicGamesLauncher\Data\Manifests.
+func epicManifestDir() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		return ""
+	}
+	return filepath.Join(programData, "Epic", "EpicGamesLauncher", "Data", "Manifests")
+}
+
+// scanEpicManifests reads the *.item manifests in dir. Unfinished
+// installs, DLC and add-ons without an executable of their own, and files
+// that do not parse are skipped. A missing dir means Epic is not installed.
+func scanEpicManifests(dir string) ([]InstalledGame, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.item"))
+	if err != nil {
+		return nil, err
+	}
+	var games []InstalledGame
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return games, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+		}
+		var m epicManifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			continue
+		}
+		if m.IncompleteInstall || m.LaunchExecutable == "" || m.InstallLocation == "" {
+			continue
+		}
+		if m.MainGameCatalogItem != "" && m.CatalogItemID != "" && m.MainGameCatalogItem != m.CatalogItemID {
+			continue // DLC installed into its game's folder
+		}
+		name := m.DisplayName
+		if name == "" {
+			name = m.AppName
+		}
+		exe := filepath.Join(m.InstallLocation, filepath.FromSlash(strings.ReplaceAll(m.LaunchExecutable, `\`, "/")))
+		games = append(games, InstalledGame{
+			Name:        name,
+			Launcher:    "Epic Games",
+			ID:          m.AppName,
+			InstallDir:  m.InstallLocation,
+			Executables: []string{exe},
+		})
+	}
+	return games, nil
+}
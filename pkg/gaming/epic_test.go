@@ -0,0 +1,62 @@
+package gaming
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanEpicManifests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("A1.item", `{"DisplayName": "Rocket League", "AppName": "Sugar",
+		"InstallLocation": "C:\\Games\\rocketleague", "LaunchExecutable": "Binaries/Win64/RocketLeague.exe",
+		"CatalogItemId": "c1", "MainGameCatalogItemId": "c1"}`)
+	write("B2.item", `{"DisplayName": "Half Done", "InstallLocation": "C:\\Games\\half",
+		"LaunchExecutable": "half.exe", "bIsIncompleteInstall": true}`)
+	write("C3.item", `{"DisplayName": "Some DLC", "InstallLocation": "C:\\Games\\rocketleague",
+		"LaunchExecutable": "Binaries/Win64/RocketLeague.exe", "CatalogItemId": "d1", "MainGameCatalogItemId": "c1"}`)
+	write("D4.item", `not json`)
+	write("notes.txt", `{}`)
+
+	games, err := scanEpicManifests(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 {
+		t.Fatalf("got %d games, want 1: %+v", len(games), games)
+	}
+	g := games[0]
+	if g.Name != "Rocket League" || g.ID != "Sugar" || g.Launcher != "Epic Games" {
+		t.Errorf("game = %+v", g)
+	}
+	if len(g.Executables) != 1 || filepath.Base(g.Executables[0]) != "RocketLeague.exe" {
+		t.Errorf("executables = %v", g.Executables)
+	}
+
+	if games, err := scanEpicManifests(filepath.Join(dir, "missing")); err != nil || len(games) != 0 {
+		t.Errorf("missing dir = %v, %v; want no games", games, err)
+	}
+}
+
+func TestInstalledGameByExe(t *testing.T) {
+	libraryMu.Lock()
+	library = []InstalledGame{{Name: "Rocket League", Executables: []string{filepath.Join("games", "RocketLeague.exe")}}}
+	libraryMu.Unlock()
+	defer func() {
+		libraryMu.Lock()
+		library = nil
+		libraryMu.Unlock()
+	}()
+
+	if g, ok := InstalledGameByExe("rocketleague.EXE"); !ok || g.Name != "Rocket League" {
+		t.Errorf("InstalledGameByExe = %+v, %v", g, ok)
+	}
+	if !isGameProcess("RocketLeague.exe") {
+		t.Error("installed game not treated as a game process")
+	}
+}
@@ -10,10 +10,12 @@ import (
 
 	"syscleaner/pkg/admin"
 	"syscleaner/pkg/memory"
+	"syscleaner/pkg/sysrestore"
 )
 
 // ExtremeMode holds state for extreme performance mode.
 type ExtremeMode struct {
+	Level             string
 	ShellStopped      bool
 	AntiCheatServices []string
 	ClosedProcesses   []string
@@ -39,7 +41,8 @@ var (
 	}
 
 	// Comprehensive list of non-essential services to stop in Extreme Gaming Mode
-	// These have been verified safe to temporarily stop while gaming
+	// These have been verified safe to temporarily stop while gaming. They
+	// make up DefaultStopList with processesToKill; see defaultRisks.
 	extremeServicesToStop = []string{
 		// === Windows Update & Delivery ===
 		"wuauserv",     // Windows Update
@@ -120,7 +123,7 @@ var (
 		"BcastDVRUserService",  // GameDVR and Broadcast User Service (if not recording)
 	}
 
-	// Background applications to close in Extreme Mode
+	// Background applications to close in Extreme Mode by default
 	processesToKill = []string{
 		"OneDrive.exe",
 		"Teams.exe",
@@ -161,16 +164,29 @@ var (
 	}
 )
 
-// GetProcessesToKill returns the list of processes that would be terminated.
+// GetProcessesToKill returns the list of processes that would be terminated:
+// the process entries of the stop list that are not refused as critical.
 func GetProcessesToKill() []string {
-	return processesToKill
+	_, processes, _ := stopPlan(ExtremeStopList())
+	return processes
 }
 
-// EnableExtremeMode stops Windows Explorer and non-essential services.
-func EnableExtremeMode() error {
+// EnableExtremeMode closes background apps and, depending on level (see
+// ExtremeLevels), stops non-essential services and Windows Explorer.
+func EnableExtremeMode(level string) error {
+	if err := ValidateExtremeLevel(level); err != nil {
+		return err
+	}
+	level = normalizeExtremeLevel(level)
 	if err := admin.RequireElevation("Extreme Performance Mode"); err != nil {
 		return err
 	}
+	if err := admin.RequireWriteAccess("enabling Extreme Performance Mode"); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" && !IsExtremeModeActive() {
+		sysrestore.Before("extreme mode")
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -194,19 +210,36 @@ func EnableExtremeMode() error {
 	}
 
 	extremeMode = ExtremeMode{
+		Level:             level,
 		AntiCheatServices: antiCheatServices,
 	}
+	current.Extreme = true
+	current.ExtremeLevel = level
+	if stats != nil {
+		stats.noteOptimization("extreme mode (" + level + ")")
+	}
+	current.save()
+
+	services, _ := extremeStopPlan()
+	if level == ExtremeLight {
+		services = nil
+	}
 
 	// Close non-essential background applications using native API
 	log.Println("[SysCleaner] Closing background applications for extreme performance...")
-	closedCount, closedApps := CloseBackgroundApps(ProcessWhitelist)
+	closedCount, closedApps := CloseBackgroundApps(append(append([]string(nil), ProcessWhitelist...), streaming.protected()...))
 	extremeMode.ClosedProcesses = closedApps
 	log.Printf("[SysCleaner] Closed %d background applications", closedCount)
 
 	// Stop additional services for extreme mode.
-	log.Println("[SysCleaner] Stopping non-essential services for extreme performance...")
-	for i, svc := range extremeServicesToStop {
-		stopService(svc)
+	if len(services) > 0 {
+		log.Println("[SysCleaner] Stopping non-essential services for extreme performance...")
+	}
+	for i, svc := range services {
+		if stopService(svc) == nil {
+			current.ExtremeServices = append(current.ExtremeServices, svc)
+			current.save()
+		}
 		if i > 0 && i%3 == 0 {
 			time.Sleep(500 * time.Millisecond)
 		}
@@ -218,21 +251,33 @@ func EnableExtremeMode() error {
 		startService(svc)
 	}
 
-	// Stop Windows Explorer (Desktop Experience)
-	// Uses taskkill for explorer.exe — native API is inappropriate for shell
-	// processes that auto-restart via Windows Session Manager
-	log.Println("[SysCleaner] Stopping Windows Explorer shell...")
-	if err := stopWindowsExplorer(); err != nil {
-		return fmt.Errorf("failed to stop explorer: %w", err)
+	// Start the watchdog before the desktop goes away, so a crash from
+	// here on still gets Explorer back.
+	if err := startWatchdog(); err != nil {
+		log.Printf("[SysCleaner] Warning: Failed to start extreme mode watchdog: %v", err)
+	}
+
+	if level == ExtremeFull {
+		// Stop Windows Explorer (Desktop Experience)
+		// Uses taskkill for explorer.exe — native API is inappropriate for shell
+		// processes that auto-restart via Windows Session Manager
+		log.Println("[SysCleaner] Stopping Windows Explorer shell...")
+		if err := stopWindowsExplorer(); err != nil {
+			return fmt.Errorf("failed to stop explorer: %w", err)
+		}
+		extremeMode.ShellStopped = true
+		current.ShellStopped = true
+		current.save()
 	}
-	extremeMode.ShellStopped = true
 
 	// Set ultimate performance power plan
 	// Uses powercfg — no native API equivalent exists
-	runCmd("powercfg", "/setactive", "8c5e7fda-e8bf-4a96-9a85-a6e23a8c635c")
+	setGamingPowerPlan(PowerPlanUltimate)
 
 	// Disable visual effects for maximum performance using native registry API
-	disableVisualEffects()
+	if level == ExtremeFull {
+		disableVisualEffects()
+	}
 
 	// Start RAM monitoring for automatic standby trimming
 	log.Println("[SysCleaner] Starting continuous RAM monitoring...")
@@ -244,7 +289,7 @@ func EnableExtremeMode() error {
 	extremeMode.ramMonitorActive = true
 
 	extremeModeActive = true
-	log.Println("[SysCleaner] Extreme Mode ACTIVATED - Maximum performance enabled")
+	log.Printf("[SysCleaner] Extreme Mode ACTIVATED (%s) - Maximum performance enabled", level)
 	return nil
 }
 
@@ -254,6 +299,12 @@ func DisableExtremeMode() error {
 	defer mu.Unlock()
 
 	if !extremeModeActive {
+		// Extreme mode left on by an earlier run (see DetachSession) is
+		// ended here, together with the gaming mode under it.
+		if s, err := loadSession(); err == nil && s.Extreme && s.Detached {
+			_, err := recoverSession(true)
+			return err
+		}
 		return fmt.Errorf("extreme mode not active")
 	}
 
@@ -272,7 +323,7 @@ func DisableExtremeMode() error {
 	}
 
 	// Restore services with pacing
-	for i, svc := range extremeServicesToStop {
+	for i, svc := range current.ExtremeServices {
 		startService(svc)
 		if i > 0 && i%3 == 0 {
 			time.Sleep(500 * time.Millisecond)
@@ -280,8 +331,15 @@ func DisableExtremeMode() error {
 	}
 
 	// Re-enable visual effects
-	enableVisualEffects()
+	if extremeMode.Level == ExtremeFull {
+		enableVisualEffects()
+	}
 
+	// Everything extreme mode changed is restored, so the gaming mode
+	// session no longer needs to.
+	current.Extreme, current.ShellStopped, current.ExtremeServices = false, false, nil
+	current.ExtremeLevel = ""
+	current.save()
 	extremeModeActive = false
 
 	// Disable regular gaming mode
@@ -300,25 +358,43 @@ func IsExtremeModeActive() bool {
 	return extremeModeActive
 }
 
+// ExtremeModeLevel returns the level extreme mode is on at, or "" when it
+// is off.
+func ExtremeModeLevel() string {
+	mu.Lock()
+	defer mu.Unlock()
+	if !extremeModeActive {
+		return ""
+	}
+	return extremeMode.Level
+}
+
 // CloseBackgroundApps closes non-essential background applications.
 // Returns the count of closed apps and a list of closed process names.
 // Uses native TerminateProcess API instead of taskkill.exe to avoid
 // triggering AV heuristics from rapid child process spawning.
-// Processes in the whitelist are skipped.
+// The apps come from the extreme mode stop list (see GetProcessesToKill);
+// processes in the whitelist are skipped.
 func CloseBackgroundApps(whitelist []string) (int, []string) {
 	closed := 0
 	closedApps := []string{}
+	if admin.RequireWriteAccess("closing background apps") != nil {
+		return closed, closedApps
+	}
 
 	whitelistMap := make(map[string]bool)
 	for _, name := range whitelist {
 		whitelistMap[strings.ToLower(name)] = true
 	}
 
-	for i, processName := range processesToKill {
+	for i, processName := range GetProcessesToKill() {
 		if whitelistMap[strings.ToLower(processName)] {
 			log.Printf("[SysCleaner] Skipping whitelisted process: %s", processName)
 			continue
 		}
+		if guardProcessName("closing", processName) {
+			continue
+		}
 
 		if err := terminateProcessByName(processName); err == nil {
 			closed++
@@ -0,0 +1,84 @@
+package gaming
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Extreme mode levels, from least to most disruptive. Each stops what the
+// one before it does and more.
+const (
+	// ExtremeLight closes background apps.
+	ExtremeLight = "light"
+	// ExtremeMedium also stops non-essential services.
+	ExtremeMedium = "medium"
+	// ExtremeFull also stops the Explorer shell and visual effects. It is
+	// the level used when none is given.
+	ExtremeFull = "extreme"
+)
+
+// ExtremeLevels lists the extreme mode levels in order.
+var ExtremeLevels = []string{ExtremeLight, ExtremeMedium, ExtremeFull}
+
+// ValidateExtremeLevel reports whether level is a known extreme mode level.
+func ValidateExtremeLevel(level string) error {
+	switch strings.ToLower(level) {
+	case "", ExtremeLight, ExtremeMedium, ExtremeFull:
+		return nil
+	}
+	return fmt.Errorf("unknown extreme mode level %q (valid: %s)", level, strings.Join(ExtremeLevels, ", "))
+}
+
+// normalizeExtremeLevel returns level in lower case, with "" meaning
+// ExtremeFull.
+func normalizeExtremeLevel(level string) string {
+	if level == "" {
+		return ExtremeFull
+	}
+	return strings.ToLower(level)
+}
+
+// ExtremePlan is what extreme mode stops at a level.
+type ExtremePlan struct {
+	Level string
+	// Apps are the background apps closed, leaving out whitelisted ones.
+	Apps []string
+	// Services are the services stopped on top of gaming mode's.
+	Services []string
+	// StopsShell reports whether Explorer and visual effects are stopped.
+	StopsShell bool
+}
+
+// PlanExtremeMode returns what EnableExtremeMode would stop at level,
+// from the extreme stop list and ProcessWhitelist, so it can be shown
+// before activation.
+func PlanExtremeMode(level string) ExtremePlan {
+	plan := ExtremePlan{Level: normalizeExtremeLevel(level)}
+	services, processes, _ := stopPlan(ExtremeStopList())
+	for _, name := range processes {
+		if !containsFold(ProcessWhitelist, name) {
+			plan.Apps = append(plan.Apps, name)
+		}
+	}
+	if plan.Level != ExtremeLight {
+		plan.Services = services
+	}
+	plan.StopsShell = plan.Level == ExtremeFull
+	return plan
+}
+
+// String lists everything in p, one kind per line.
+func (p ExtremePlan) String() string {
+	list := func(names []string) string {
+		if len(names) == 0 {
+			return "none"
+		}
+		return strings.Join(names, ", ")
+	}
+	shell := "left running"
+	if p.StopsShell {
+		shell = "stopped (no desktop or taskbar), visual effects off"
+	}
+	return fmt.Sprintf("Level: %s\nBackground apps closed (%d): %s\nServices stopped (%d): %s\nWindows Explorer: %s",
+		p.Level, len(p.Apps), list(p.Apps), len(p.Services), list(p.Services), shell)
+}
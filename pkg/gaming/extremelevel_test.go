@@ -0,0 +1,51 @@
+package gaming
+
+import "testing"
+
+func TestPlanExtremeMode_Levels(t *testing.T) {
+	SetExtremeStopList([]StopEntry{
+		{Name: "WSearch"},
+		{Name: "Audiosrv"}, // critical, never planned
+		{Name: "Spotify.exe", Kind: StopProcess},
+		{Name: "Teams.exe", Kind: StopProcess},
+	})
+	t.Cleanup(func() { SetExtremeStopList(nil) })
+	orig := ProcessWhitelist
+	ProcessWhitelist = []string{"spotify.exe"}
+	t.Cleanup(func() { ProcessWhitelist = orig })
+
+	tests := []struct {
+		level    string
+		services int
+		shell    bool
+	}{
+		{ExtremeLight, 0, false},
+		{"Medium", 1, false},
+		{"", 1, true},
+		{ExtremeFull, 1, true},
+	}
+	for _, tt := range tests {
+		p := PlanExtremeMode(tt.level)
+		if len(p.Apps) != 1 || p.Apps[0] != "Teams.exe" {
+			t.Errorf("%q: apps = %v, want only the unwhitelisted Teams.exe", tt.level, p.Apps)
+		}
+		if len(p.Services) != tt.services || p.StopsShell != tt.shell {
+			t.Errorf("%q: services = %v, shell = %v; want %d service(s), shell %v",
+				tt.level, p.Services, p.StopsShell, tt.services, tt.shell)
+		}
+	}
+	if got := PlanExtremeMode("").Level; got != ExtremeFull {
+		t.Errorf("default level = %q, want %q", got, ExtremeFull)
+	}
+}
+
+func TestValidateExtremeLevel(t *testing.T) {
+	for _, level := range []string{"", "light", "MEDIUM", "extreme"} {
+		if err := ValidateExtremeLevel(level); err != nil {
+			t.Errorf("%q: %v", level, err)
+		}
+	}
+	if err := ValidateExtremeLevel("ludicrous"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,124 @@
+package gaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Accessibility flag bits shared by STICKYKEYS, TOGGLEKEYS and FILTERKEYS.
+const (
+	accessOn             = 0x1 // SKF_STICKYKEYSON / TKF_TOGGLEKEYSON / FKF_FILTERKEYSON
+	accessHotkeyActive   = 0x4 // shortcut (e.g. Shift x5) turns the feature on
+	accessConfirmHotkey  = 0x8 // shortcut shows the "Turn on ...?" prompt
+	accessShortcutFlags  = accessHotkeyActive | accessConfirmHotkey
+	focusLockTimeoutMsec = 200000 // Windows' default ForegroundLockTimeout
+)
+
+// focusSettings is the desktop state changed by focus mode.
+type focusSettings struct {
+	// ToastsEnabled is the PushNotifications ToastEnabled value; ToastsSet
+	// is false when the value did not exist.
+	ToastsEnabled bool `json:"toasts_enabled"`
+	ToastsSet     bool `json:"toasts_set"`
+
+	StickyKeysFlags uint32 `json:"sticky_keys_flags"`
+	ToggleKeysFlags uint32 `json:"toggle_keys_flags"`
+	FilterKeysFlags uint32 `json:"filter_keys_flags"`
+
+	// ForegroundLockTimeout is how long (ms) after user input other apps
+	// are prevented from taking focus.
+	ForegroundLockTimeout uint32 `json:"foreground_lock_timeout"`
+}
+
+// focusedSettings returns orig with notification banners off, the
+// accessibility shortcut prompts disabled, and focus stealing blocked.
+// Shortcuts for a feature the user already has on are left alone so it can
+// still be turned off.
+func focusedSettings(orig focusSettings) focusSettings {
+	s := orig
+	s.ToastsEnabled = false
+	s.ToastsSet = true
+	s.StickyKeysFlags = withoutShortcut(orig.StickyKeysFlags)
+	s.ToggleKeysFlags = withoutShortcut(orig.ToggleKeysFlags)
+	s.FilterKeysFlags = withoutShortcut(orig.FilterKeysFlags)
+	if s.ForegroundLockTimeout < focusLockTimeoutMsec {
+		s.ForegroundLockTimeout = focusLockTimeoutMsec
+	}
+	return s
+}
+
+func withoutShortcut(flags uint32) uint32 {
+	if flags&accessOn != 0 {
+		return flags
+	}
+	return flags &^ accessShortcutFlags
+}
+
+// focusStatePath is where the pre-session settings are saved so a later
+// "gaming --disable" run (or the next run after a crash) can restore them.
+var focusStatePath = func() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", "focus-state.json")
+}
+
+// enableFocusMode saves the current desktop settings and applies the
+// focused ones. If a previous session was never restored, its saved
+// settings are kept as the originals.
+func enableFocusMode() error {
+	orig, err := loadFocusState()
+	if err != nil {
+		if orig, err = readFocusSettings(); err != nil {
+			return err
+		}
+		if err := saveFocusState(orig); err != nil {
+			return err
+		}
+	}
+	return applyFocusSettings(focusedSettings(orig))
+}
+
+// restoreFocusMode puts back the settings saved by enableFocusMode. It is
+// a no-op when focus mode was not enabled.
+func restoreFocusMode() error {
+	orig, err := loadFocusState()
+	if err != nil {
+		return nil
+	}
+	if err := applyFocusSettings(orig); err != nil {
+		return err
+	}
+	if err := os.Remove(focusStatePath()); err != nil && !os.IsNotExist(err) {
+		log.Printf("[SysCleaner] Failed to remove focus state: %v", err)
+	}
+	return nil
+}
+
+func loadFocusState() (focusSettings, error) {
+	var s focusSettings
+	data, err := os.ReadFile(focusStatePath())
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parsing focus state: %w", err)
+	}
+	return s, nil
+}
+
+func saveFocusState(s focusSettings) error {
+	path := focusStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
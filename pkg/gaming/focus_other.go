@@ -0,0 +1,15 @@
+//go:build !windows
+
+package gaming
+
+import "fmt"
+
+// readFocusSettings is only available on Windows.
+var readFocusSettings = func() (focusSettings, error) {
+	return focusSettings{}, fmt.Errorf("focus mode is only available on Windows")
+}
+
+// applyFocusSettings is only available on Windows.
+var applyFocusSettings = func(s focusSettings) error {
+	return fmt.Errorf("focus mode is only available on Windows")
+}
@@ -0,0 +1,81 @@
+package gaming
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFocusedSettings(t *testing.T) {
+	orig := focusSettings{
+		ToastsEnabled:         true,
+		StickyKeysFlags:       0x1FE,                // shortcut + prompt, sticky keys off
+		ToggleKeysFlags:       accessOn | 0x4 | 0x8, // user has toggle keys on
+		FilterKeysFlags:       0x7E,
+		ForegroundLockTimeout: 0,
+	}
+	got := focusedSettings(orig)
+
+	if got.ToastsEnabled || !got.ToastsSet {
+		t.Errorf("toasts = %v (set %v), want disabled", got.ToastsEnabled, got.ToastsSet)
+	}
+	if got.StickyKeysFlags&accessShortcutFlags != 0 {
+		t.Errorf("sticky keys flags = %#x, want shortcut cleared", got.StickyKeysFlags)
+	}
+	if got.StickyKeysFlags != 0x1F2 {
+		t.Errorf("sticky keys flags = %#x, want other bits kept (0x1f2)", got.StickyKeysFlags)
+	}
+	if got.ToggleKeysFlags != orig.ToggleKeysFlags {
+		t.Errorf("toggle keys flags = %#x, want unchanged while the feature is on", got.ToggleKeysFlags)
+	}
+	if got.ForegroundLockTimeout != focusLockTimeoutMsec {
+		t.Errorf("ForegroundLockTimeout = %d, want %d", got.ForegroundLockTimeout, focusLockTimeoutMsec)
+	}
+
+	orig.ForegroundLockTimeout = 500000
+	if got := focusedSettings(orig); got.ForegroundLockTimeout != 500000 {
+		t.Errorf("ForegroundLockTimeout = %d, want a longer user value kept", got.ForegroundLockTimeout)
+	}
+}
+
+func TestFocusMode_RestoresOriginalSettings(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "focus-state.json")
+	current := focusSettings{ToastsEnabled: true, StickyKeysFlags: 0x1FE, ForegroundLockTimeout: 0}
+
+	origPath, origRead, origApply := focusStatePath, readFocusSettings, applyFocusSettings
+	defer func() {
+		focusStatePath, readFocusSettings, applyFocusSettings = origPath, origRead, origApply
+	}()
+	focusStatePath = func() string { return statePath }
+	readFocusSettings = func() (focusSettings, error) { return current, nil }
+	applyFocusSettings = func(s focusSettings) error { current = s; return nil }
+
+	want := current
+	if err := enableFocusMode(); err != nil {
+		t.Fatalf("enableFocusMode: %v", err)
+	}
+	if current.ToastsEnabled {
+		t.Fatal("toasts still enabled after enableFocusMode")
+	}
+
+	// A second enable (e.g. after a crash) must not save the focused
+	// settings as the originals.
+	if err := enableFocusMode(); err != nil {
+		t.Fatalf("enableFocusMode again: %v", err)
+	}
+
+	if err := restoreFocusMode(); err != nil {
+		t.Fatalf("restoreFocusMode: %v", err)
+	}
+	if current != want {
+		t.Errorf("restored settings = %+v, want %+v", current, want)
+	}
+
+	// Restoring again is a no-op.
+	current.StickyKeysFlags = 0
+	if err := restoreFocusMode(); err != nil {
+		t.Fatalf("restoreFocusMode again: %v", err)
+	}
+	if current.StickyKeysFlags != 0 {
+		t.Error("second restore changed settings")
+	}
+}
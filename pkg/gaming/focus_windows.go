@@ -0,0 +1,144 @@
+//go:build windows
+
+package gaming
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+var (
+	user32                   = windows.NewLazySystemDLL("user32.dll")
+	procSystemParametersInfo = user32.NewProc("SystemParametersInfoW")
+)
+
+const (
+	spiGetFilterKeys            = 0x0032
+	spiSetFilterKeys            = 0x0033
+	spiGetToggleKeys            = 0x0034
+	spiSetToggleKeys            = 0x0035
+	spiGetStickyKeys            = 0x003A
+	spiSetStickyKeys            = 0x003B
+	spiGetForegroundLockTimeout = 0x2000
+	spiSetForegroundLockTimeout = 0x2001
+
+	// spifSendChange broadcasts the change without persisting it to the
+	// user profile, so a crash is undone at the next sign-in.
+	spifSendChange = 0x2
+
+	pushNotificationsPath = `Software\Microsoft\Windows\CurrentVersion\PushNotifications`
+)
+
+// accessStruct covers STICKYKEYS and TOGGLEKEYS (cbSize, dwFlags) as well as
+// FILTERKEYS, which adds four timing fields.
+type accessStruct struct {
+	Size   uint32
+	Flags  uint32
+	Timing [4]uint32
+}
+
+func systemParametersInfo(action, param uint32, pv unsafe.Pointer, winIni uint32) error {
+	r, _, err := procSystemParametersInfo.Call(uintptr(action), uintptr(param), uintptr(pv), uintptr(winIni))
+	if r == 0 {
+		return fmt.Errorf("SystemParametersInfo(0x%X): %w", action, err)
+	}
+	return nil
+}
+
+func accessSize(get uint32) uint32 {
+	if get == spiGetFilterKeys {
+		return uint32(unsafe.Sizeof(accessStruct{}))
+	}
+	return 8
+}
+
+func getAccessFlags(get uint32) (accessStruct, error) {
+	s := accessStruct{Size: accessSize(get)}
+	err := systemParametersInfo(get, s.Size, unsafe.Pointer(&s), 0)
+	return s, err
+}
+
+func setAccessFlags(get, set, flags uint32) error {
+	s, err := getAccessFlags(get)
+	if err != nil {
+		return err
+	}
+	if s.Flags == flags {
+		return nil
+	}
+	s.Flags = flags
+	return systemParametersInfo(set, s.Size, unsafe.Pointer(&s), spifSendChange)
+}
+
+// readFocusSettings reads the notification, accessibility shortcut and
+// foreground lock settings for the current user.
+var readFocusSettings = func() (focusSettings, error) {
+	var s focusSettings
+
+	s.ToastsEnabled = true
+	if k, err := registry.OpenKey(registry.CURRENT_USER, pushNotificationsPath, registry.QUERY_VALUE); err == nil {
+		if v, _, err := k.GetIntegerValue("ToastEnabled"); err == nil {
+			s.ToastsEnabled = v != 0
+			s.ToastsSet = true
+		}
+		k.Close()
+	}
+
+	for _, a := range []struct {
+		get   uint32
+		flags *uint32
+	}{
+		{spiGetStickyKeys, &s.StickyKeysFlags},
+		{spiGetToggleKeys, &s.ToggleKeysFlags},
+		{spiGetFilterKeys, &s.FilterKeysFlags},
+	} {
+		as, err := getAccessFlags(a.get)
+		if err != nil {
+			return s, err
+		}
+		*a.flags = as.Flags
+	}
+
+	if err := systemParametersInfo(spiGetForegroundLockTimeout, 0, unsafe.Pointer(&s.ForegroundLockTimeout), 0); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// applyFocusSettings writes s. The notification setting goes through reglog
+// so it appears in the registry change log; the rest are session-only.
+var applyFocusSettings = func(s focusSettings) error {
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if s.ToastsSet {
+		val := uint32(0)
+		if s.ToastsEnabled {
+			val = 1
+		}
+		keep(reglog.SetDWordValue(registry.CURRENT_USER, pushNotificationsPath, "ToastEnabled", val))
+	} else if err := reglog.DeleteValue(registry.CURRENT_USER, pushNotificationsPath, "ToastEnabled"); err != registry.ErrNotExist {
+		keep(err)
+	}
+
+	keep(setAccessFlags(spiGetStickyKeys, spiSetStickyKeys, s.StickyKeysFlags))
+	keep(setAccessFlags(spiGetToggleKeys, spiSetToggleKeys, s.ToggleKeysFlags))
+	keep(setAccessFlags(spiGetFilterKeys, spiSetFilterKeys, s.FilterKeysFlags))
+
+	// SPI_SETFOREGROUNDLOCKTIMEOUT takes the value itself in pvParam.
+	if r, _, err := procSystemParametersInfo.Call(spiSetForegroundLockTimeout, 0,
+		uintptr(s.ForegroundLockTimeout), spifSendChange); r == 0 {
+		keep(fmt.Errorf("SystemParametersInfo(0x%X): %w", spiSetForegroundLockTimeout, err))
+	}
+
+	return firstErr
+}
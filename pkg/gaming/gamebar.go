@@ -0,0 +1,117 @@
+package gaming
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// GameSettings are Windows' own gaming features: Game Mode, Game DVR
+// background recording and the Xbox Game Bar. Gaming mode applies them
+// for the session and puts the previous values back when it ends.
+type GameSettings struct {
+	// GameMode is "on", "off" or "" to leave Windows Game Mode alone.
+	GameMode string `json:"game_mode,omitempty"`
+	// DisableGameDVR turns off Game DVR capture and background recording.
+	DisableGameDVR bool `json:"disable_game_dvr,omitempty"`
+	// DisableGameBar stops Win+G and controller buttons opening the Xbox
+	// Game Bar.
+	DisableGameBar bool `json:"disable_game_bar,omitempty"`
+}
+
+// Registry keys under HKEY_CURRENT_USER holding the game settings.
+const (
+	gameBarPath         = `Software\Microsoft\GameBar`
+	gameDVRPath         = `Software\Microsoft\Windows\CurrentVersion\GameDVR`
+	gameConfigStorePath = `System\GameConfigStore`
+)
+
+// registryDWord is a DWORD value under HKEY_CURRENT_USER.
+type registryDWord struct {
+	Path  string `json:"path"`
+	Name  string `json:"name"`
+	Value uint32 `json:"value"`
+}
+
+// savedDWord is a value's data before gaming mode changed it; Old is nil
+// when the value did not exist.
+type savedDWord struct {
+	Path string  `json:"path"`
+	Name string  `json:"name"`
+	Old  *uint32 `json:"old"`
+}
+
+// Validate reports the first invalid setting in s.
+func (s GameSettings) Validate() error {
+	switch strings.ToLower(s.GameMode) {
+	case "", "on", "off":
+		return nil
+	}
+	return fmt.Errorf("unknown game_mode %q (valid: on, off)", s.GameMode)
+}
+
+// values returns the registry values s sets, using the documented values
+// behind the Settings > Gaming switches.
+func (s GameSettings) values() []registryDWord {
+	var v []registryDWord
+	switch strings.ToLower(s.GameMode) {
+	case "on":
+		v = append(v, registryDWord{gameBarPath, "AutoGameModeEnabled", 1}, registryDWord{gameBarPath, "AllowAutoGameMode", 1})
+	case "off":
+		v = append(v, registryDWord{gameBarPath, "AutoGameModeEnabled", 0}, registryDWord{gameBarPath, "AllowAutoGameMode", 0})
+	}
+	if s.DisableGameDVR {
+		v = append(v,
+			registryDWord{gameConfigStorePath, "GameDVR_Enabled", 0},
+			registryDWord{gameDVRPath, "AppCaptureEnabled", 0},
+			registryDWord{gameDVRPath, "HistoricalCaptureEnabled", 0})
+	}
+	if s.DisableGameBar {
+		v = append(v, registryDWord{gameBarPath, "UseNexusForGameBarEnabled", 0})
+	}
+	return v
+}
+
+// applyGameSettings writes the values s sets, passing the original of each
+// value it changes to record before the write, so the original is saved
+// even if SysCleaner dies straight after.
+func applyGameSettings(s GameSettings, record func(savedDWord)) {
+	if err := s.Validate(); err != nil {
+		log.Printf("[SysCleaner] Windows game settings: %v", err)
+	}
+	for _, v := range s.values() {
+		old, exists, err := readUserDWord(v.Path, v.Name)
+		if err != nil {
+			log.Printf("[SysCleaner] Failed to read %s\\%s: %v", v.Path, v.Name, err)
+			continue
+		}
+		if exists && old == v.Value {
+			continue
+		}
+		saved := savedDWord{Path: v.Path, Name: v.Name}
+		if exists {
+			saved.Old = &old
+		}
+		record(saved)
+		if err := writeUserDWord(v.Path, v.Name, v.Value); err != nil {
+			log.Printf("[SysCleaner] Failed to set %s\\%s: %v", v.Path, v.Name, err)
+		}
+	}
+}
+
+// restoreGameSettings puts back values saved by applyGameSettings, most
+// recent first.
+func restoreGameSettings(saved []savedDWord) {
+	for i := len(saved) - 1; i >= 0; i-- {
+		v := saved[i]
+		var err error
+		if v.Old != nil {
+			err = writeUserDWord(v.Path, v.Name, *v.Old)
+		} else {
+			err = deleteUserDWord(v.Path, v.Name)
+		}
+		if err != nil {
+			log.Printf("[SysCleaner] Failed to restore %s\\%s: %v", v.Path, v.Name, err)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package gaming
+
+import "fmt"
+
+var errNoGameSettings = fmt.Errorf("Windows game settings are only available on Windows")
+
+// readUserDWord is only available on Windows.
+var readUserDWord = func(path, name string) (uint32, bool, error) {
+	return 0, false, errNoGameSettings
+}
+
+// writeUserDWord is only available on Windows.
+var writeUserDWord = func(path, name string, value uint32) error {
+	return errNoGameSettings
+}
+
+// deleteUserDWord is only available on Windows.
+var deleteUserDWord = func(path, name string) error {
+	return errNoGameSettings
+}
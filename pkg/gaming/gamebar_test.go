@@ -0,0 +1,56 @@
+package gaming
+
+import "testing"
+
+func TestGameSettings_Validate(t *testing.T) {
+	for _, mode := range []string{"", "on", "Off"} {
+		if err := (GameSettings{GameMode: mode}).Validate(); err != nil {
+			t.Errorf("GameMode %q: %v", mode, err)
+		}
+	}
+	if err := (GameSettings{GameMode: "auto"}).Validate(); err == nil {
+		t.Error("GameMode \"auto\" validated")
+	}
+}
+
+func TestApplyGameSettings_Restores(t *testing.T) {
+	type key struct{ path, name string }
+	reg := map[key]uint32{
+		{gameBarPath, "AutoGameModeEnabled"}: 0,
+		{gameDVRPath, "AppCaptureEnabled"}:   0,
+	}
+	before := make(map[key]uint32)
+	for k, v := range reg {
+		before[k] = v
+	}
+
+	origRead, origWrite, origDelete := readUserDWord, writeUserDWord, deleteUserDWord
+	defer func() { readUserDWord, writeUserDWord, deleteUserDWord = origRead, origWrite, origDelete }()
+	readUserDWord = func(path, name string) (uint32, bool, error) {
+		v, ok := reg[key{path, name}]
+		return v, ok, nil
+	}
+	writeUserDWord = func(path, name string, value uint32) error { reg[key{path, name}] = value; return nil }
+	deleteUserDWord = func(path, name string) error { delete(reg, key{path, name}); return nil }
+
+	var saved []savedDWord
+	applyGameSettings(GameSettings{GameMode: "on", DisableGameDVR: true}, func(v savedDWord) { saved = append(saved, v) })
+
+	if reg[key{gameBarPath, "AutoGameModeEnabled"}] != 1 || reg[key{gameConfigStorePath, "GameDVR_Enabled"}] != 0 {
+		t.Fatalf("settings not applied: %v", reg)
+	}
+	// AppCaptureEnabled was already 0, so it is left out of the saved values.
+	if len(saved) != 4 {
+		t.Fatalf("saved %d values, want 4: %+v", len(saved), saved)
+	}
+
+	restoreGameSettings(saved)
+	if len(reg) != len(before) {
+		t.Errorf("after restore registry = %v, want %v", reg, before)
+	}
+	for k, v := range before {
+		if got, ok := reg[k]; !ok || got != v {
+			t.Errorf("%s\\%s = %d (exists %v), want %d", k.path, k.name, got, ok, v)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package gaming
+
+import (
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+// readUserDWord reads a DWORD under HKEY_CURRENT_USER, reporting whether
+// it exists.
+var readUserDWord = func(path, name string) (uint32, bool, error) {
+	k, err := registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE)
+	if err == registry.ErrNotExist {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	defer k.Close()
+	v, _, err := k.GetIntegerValue(name)
+	if err == registry.ErrNotExist {
+		return 0, false, nil
+	}
+	return uint32(v), err == nil, err
+}
+
+// writeUserDWord sets a DWORD under HKEY_CURRENT_USER through reglog.
+var writeUserDWord = func(path, name string, value uint32) error {
+	return reglog.SetDWordValue(registry.CURRENT_USER, path, name, value)
+}
+
+// deleteUserDWord removes a value under HKEY_CURRENT_USER through reglog.
+var deleteUserDWord = func(path, name string) error {
+	if err := reglog.DeleteValue(registry.CURRENT_USER, path, name); err != registry.ErrNotExist {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package gaming
+
+import (
+	"crypto/ed25519"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GameDatabase is the list of games SysCleaner recognizes. A built-in copy
+// is compiled in; a newer signed copy in the config directory (see
+// GameDatabasePath) supersedes it, so new releases can be detected
+// without a new SysCleaner build.
+type GameDatabase struct {
+	// Version increases with every published database. A database on
+	// disk is only used when it is newer than the built-in one.
+	Version int           `json:"version"`
+	Games   []GameProfile `json:"games"`
+}
+
+// signedGameDatabase is the on-disk envelope, laid out like a rule pack:
+// Signature is the base64 Ed25519 signature of the raw bytes of Database.
+type signedGameDatabase struct {
+	Database  json.RawMessage `json:"database"`
+	Signature string          `json:"signature"`
+}
+
+//go:embed games.json
+var builtinGameDatabase []byte
+
+// builtinGames is the compiled-in database.
+var builtinGames = mustParseBuiltinGames()
+
+// PredefinedGames is the built-in list of supported game profiles. Use
+// Games for the list in effect, which may come from a newer database.
+var PredefinedGames = builtinGames.Games
+
+var (
+	gamesMu sync.RWMutex
+	games   = builtinGames
+	// gamesSource is the file the database in use was loaded from, or ""
+	// for the built-in one.
+	gamesSource string
+)
+
+func mustParseBuiltinGames() GameDatabase {
+	var db GameDatabase
+	if err := json.Unmarshal(builtinGameDatabase, &db); err != nil {
+		panic(fmt.Sprintf("built-in game database: %v", err))
+	}
+	if err := db.Validate(); err != nil {
+		panic(fmt.Sprintf("built-in game database: %v", err))
+	}
+	return db
+}
+
+// Games returns the games in the database in use.
+func Games() []GameProfile {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	return games.Games
+}
+
+// GameDatabaseInfo returns the version of the database in use and the
+// file it came from, or "" for the built-in database.
+func GameDatabaseInfo() (version int, source string) {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	return games.Version, gamesSource
+}
+
+// GameDatabasePath returns <user config dir>/SysCleaner/games.json.
+func GameDatabasePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "SysCleaner", "games.json")
+}
+
+// Validate reports the first game in db that could not be detected or
+// boosted.
+func (db GameDatabase) Validate() error {
+	if len(db.Games) == 0 {
+		return fmt.Errorf("game database lists no games")
+	}
+	for i, g := range db.Games {
+		if strings.TrimSpace(g.Name) == "" || len(g.Executables) == 0 {
+			return fmt.Errorf("game %d needs a name and executables", i)
+		}
+		if _, err := priorityClass(g.CPUPriority); err != nil {
+			return fmt.Errorf("game %s: %w", g.Name, err)
+		}
+		if g.Affinity != "" {
+			if err := ValidateAffinity(g.Affinity); err != nil {
+				return fmt.Errorf("game %s: %w", g.Name, err)
+			}
+		}
+		if g.DSCP < 0 || g.DSCP > 63 {
+			return fmt.Errorf("game %s: invalid dscp %d (valid: 0-63)", g.Name, g.DSCP)
+		}
+	}
+	return nil
+}
+
+// ParseGameDatabase verifies a signed game database against the trusted
+// keys and decodes it. Unlike rule packs, game databases must be signed:
+// they decide which processes are boosted and which services are kept.
+func ParseGameDatabase(data []byte, trusted []ed25519.PublicKey) (*GameDatabase, error) {
+	var env signedGameDatabase
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing game database: %w", err)
+	}
+	if len(env.Database) == 0 {
+		return nil, fmt.Errorf("game database has no \"database\" section")
+	}
+	if env.Signature == "" {
+		return nil, fmt.Errorf("game database is not signed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(env.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decoding game database signature: %w", err)
+	}
+	verified := false
+	for _, key := range trusted {
+		if ed25519.Verify(key, env.Database, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("game database signature does not match any trusted key")
+	}
+
+	var db GameDatabase
+	if err := json.Unmarshal(env.Database, &db); err != nil {
+		return nil, fmt.Errorf("parsing game database contents: %w", err)
+	}
+	if err := db.Validate(); err != nil {
+		return nil, err
+	}
+	return &db, nil
+}
+
+// SignGameDatabase wraps db in a signed envelope. It is used by tooling
+// that publishes game databases.
+func SignGameDatabase(db []byte, key ed25519.PrivateKey) ([]byte, error) {
+	if !json.Valid(db) {
+		return nil, fmt.Errorf("game database is not valid JSON")
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, db))
+	out := []byte("{\n  \"database\": ")
+	out = append(out, db...)
+	out = append(out, ",\n  \"signature\": \""+sig+"\"\n}\n"...)
+	return out, nil
+}
+
+// LoadGameDatabase switches to the signed database at path if it is newer
+// than the built-in one, and back to the built-in database otherwise. A
+// missing file is not an error; one that fails to verify is reported and
+// the built-in database is used.
+func LoadGameDatabase(path string, trusted []ed25519.PublicKey) error {
+	db, err := readGameDatabase(path, trusted)
+	if err != nil || db == nil || db.Version <= builtinGames.Version {
+		setGameDatabase(builtinGames, "")
+		return err
+	}
+	setGameDatabase(*db, path)
+	return nil
+}
+
+func readGameDatabase(path string, trusted []ed25519.PublicKey) (*GameDatabase, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading game database: %w", err)
+	}
+	db, err := ParseGameDatabase(data, trusted)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return db, nil
+}
+
+func setGameDatabase(db GameDatabase, source string) {
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+	if db.Version != games.Version || source != gamesSource {
+		if source == "" {
+			log.Printf("[SysCleaner] Using the built-in game database (version %d)", db.Version)
+		} else {
+			log.Printf("[SysCleaner] Using game database version %d from %s", db.Version, source)
+		}
+	}
+	games = db
+	gamesSource = source
+}
+
+// maxGameDatabaseSize caps downloads; real databases are a few hundred KB.
+const maxGameDatabaseSize = 8 << 20
+
+// UpdateGameDatabase downloads a signed game database from url and, if it
+// verifies and is newer than the database in use, saves it to
+// GameDatabasePath and switches to it. It returns the version in use
+// afterwards.
+func UpdateGameDatabase(url string, trusted []ed25519.PublicKey) (int, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, fmt.Errorf("downloading game database: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("downloading game database: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxGameDatabaseSize+1))
+	if err != nil {
+		return 0, fmt.Errorf("downloading game database: %w", err)
+	}
+	if len(data) > maxGameDatabaseSize {
+		return 0, fmt.Errorf("game database is larger than %d MB", maxGameDatabaseSize>>20)
+	}
+	db, err := ParseGameDatabase(data, trusted)
+	if err != nil {
+		return 0, err
+	}
+	current, _ := GameDatabaseInfo()
+	if db.Version <= current {
+		return current, nil
+	}
+
+	path := GameDatabasePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("creating config directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return 0, fmt.Errorf("writing game database: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("writing game database: %w", err)
+	}
+	setGameDatabase(*db, path)
+	return db.Version, nil
+}
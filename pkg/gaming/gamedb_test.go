@@ -0,0 +1,110 @@
+package gaming
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func signedTestDatabase(t *testing.T, key ed25519.PrivateKey, db string) []byte {
+	t.Helper()
+	data, err := SignGameDatabase([]byte(db), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+const newerTestDatabase = `{"version": 99, "games": [
+	{"name": "New Game", "executables": ["newgame.exe"], "cpu_priority": "High"}]}`
+
+func TestBuiltinGameDatabase(t *testing.T) {
+	if err := builtinGames.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if version, source := GameDatabaseInfo(); source != "" || version != builtinGames.Version {
+		t.Errorf("in use: version %d from %q, want the built-in database", version, source)
+	}
+}
+
+func TestParseGameDatabase(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	trusted := []ed25519.PublicKey{pub}
+
+	if _, err := ParseGameDatabase(signedTestDatabase(t, priv, newerTestDatabase), trusted); err != nil {
+		t.Errorf("signed database: %v", err)
+	}
+	if _, err := ParseGameDatabase(signedTestDatabase(t, other, newerTestDatabase), trusted); err == nil {
+		t.Error("database signed by an untrusted key was accepted")
+	}
+	unsigned := []byte(`{"database": ` + newerTestDatabase + `}`)
+	if _, err := ParseGameDatabase(unsigned, trusted); err == nil {
+		t.Error("unsigned database was accepted")
+	}
+	bad := `{"version": 99, "games": [{"name": "X", "executables": ["x.exe"], "cpu_priority": "realtime"}]}`
+	if _, err := ParseGameDatabase(signedTestDatabase(t, priv, bad), trusted); err == nil {
+		t.Error("database with an invalid priority was accepted")
+	}
+}
+
+func TestLoadGameDatabase(t *testing.T) {
+	defer setGameDatabase(builtinGames, "")
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	trusted := []ed25519.PublicKey{pub}
+	path := filepath.Join(t.TempDir(), "games.json")
+
+	if err := LoadGameDatabase(path, trusted); err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if err := os.WriteFile(path, signedTestDatabase(t, priv, newerTestDatabase), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadGameDatabase(path, trusted); err != nil {
+		t.Fatal(err)
+	}
+	if GetGameProfileByExe("newgame.exe") == nil || GetGameProfile("Valorant") != nil {
+		t.Errorf("newer database not in use: %+v", Games())
+	}
+
+	older := `{"version": 0, "games": [{"name": "Old", "executables": ["old.exe"], "cpu_priority": "High"}]}`
+	if err := os.WriteFile(path, signedTestDatabase(t, priv, older), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadGameDatabase(path, trusted); err != nil {
+		t.Fatal(err)
+	}
+	if _, source := GameDatabaseInfo(); source != "" {
+		t.Errorf("older database replaced the built-in one (source %q)", source)
+	}
+
+	if err := LoadGameDatabase(path, nil); err == nil {
+		t.Error("database that fails to verify was not reported")
+	}
+}
+
+func TestUpdateGameDatabase(t *testing.T) {
+	defer setGameDatabase(builtinGames, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	data := signedTestDatabase(t, priv, newerTestDatabase)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	version, err := UpdateGameDatabase(srv.URL, []ed25519.PublicKey{pub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 99 {
+		t.Errorf("version = %d, want 99", version)
+	}
+	if saved, err := os.ReadFile(GameDatabasePath()); err != nil || string(saved) != string(data) {
+		t.Errorf("database not saved: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package gaming
 import (
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -14,6 +15,10 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 
 	"syscleaner/pkg/admin"
+	"syscleaner/pkg/launcher"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/memory"
+	"syscleaner/pkg/power"
 )
 
 // Config holds gaming mode configuration.
@@ -21,17 +26,74 @@ type Config struct {
 	AutoDetectGames bool
 	CPUBoost        int
 	RAMReserveGB    int
+
+	// SuspendProcesses lists executables (e.g. "wallpaper64.exe") that are
+	// suspended while gaming mode is on and resumed when it is turned off.
+	SuspendProcesses []string
+
+	// SuspendBackgroundApps suspends BackgroundApps as well.
+	SuspendBackgroundApps bool
+
+	// Whitelist lists executables that are never suspended.
+	Whitelist []string
+
+	// FocusMode suppresses notification banners and accessibility shortcut
+	// prompts and stops other windows stealing focus for the session.
+	FocusMode bool
+
+	// DiscreteGPU sets the Windows graphics preference of detected games
+	// to the high-performance GPU (see PreferDiscreteGPU). It is not undone
+	// by Disable, since it only applies from the game's next launch.
+	DiscreteGPU bool
+
+	// GameSettings switches Windows Game Mode, Game DVR and the Game Bar
+	// for the session.
+	GameSettings GameSettings
+
+	// HighResTimer requests a 0.5 ms system timer resolution for as long
+	// as this process keeps gaming mode on.
+	HighResTimer bool
+
+	// PowerPlan is the power plan to switch to: PowerPlanHigh (the
+	// default when empty), PowerPlanUltimate or PowerPlanNone. The
+	// previous plan is restored by Disable.
+	PowerPlan string
+
+	// USBLatency turns off USB selective suspend and power saving on mice
+	// and keyboards for the session, so input devices never wake with a
+	// delay. Disable restores both.
+	USBLatency bool
+
+	// TrimWorkingSets empties the working sets of background processes
+	// that are not whitelisted whenever a game is detected, so the game
+	// gets their physical memory. Only with AutoDetectGames.
+	TrimWorkingSets bool
+
+	// PurgeStandby empties the standby memory list as gaming mode starts
+	// and runs the RAM monitor, which purges it again when memory runs
+	// low, until gaming mode is disabled.
+	PurgeStandby bool
+
+	// Streaming, if set, turns on streamer mode (see StreamingConfig).
+	Streaming *StreamingConfig
+
+	// Game names the game about to be played. Its GameOverride, if any,
+	// adjusts which services are stopped and runs its pre-launch clean.
+	Game string
 }
 
 // Status holds current gaming mode state.
 type Status struct {
-	Enabled          bool
-	ActiveGames      []GameProcess
-	CPUUsage         float64
-	RAMUsagePercent  float64
-	RAMUsed          uint64
-	RAMTotal         uint64
-	StoppedServices  []string
+	Enabled         bool
+	ActiveGames     []GameProcess
+	CPUUsage        float64
+	RAMUsagePercent float64
+	RAMUsed         uint64
+	RAMTotal        uint64
+	StoppedServices []string
+	// OperationID identifies, in the log, the run that enabled gaming
+	// mode (see logger.StartOperation).
+	OperationID string
 }
 
 // GameProcess represents a detected game process.
@@ -44,10 +106,20 @@ type GameProcess struct {
 
 var (
 	gamingModeEnabled bool
-	stoppedServices   []string
-	originalPriority  = make(map[int32]int32)
-	mu                sync.Mutex
-	monitorDone       chan struct{}
+	// current records what the running session changed.
+	current          session
+	mu               sync.Mutex
+	monitorDone      chan struct{}
+	boostOperationID string
+	discreteGPU      bool
+	// trimWorkingSets is set while the session trims background working
+	// sets (see Config.TrimWorkingSets); trimWhitelist is its whitelist
+	// and trimmedGames the games it has trimmed for.
+	trimWorkingSets bool
+	trimWhitelist   []string
+	trimmedGames    map[string]bool
+	// standbyMonitor is set while Enable's RAM monitor runs.
+	standbyMonitor bool
 )
 
 var gameExecutables = []string{
@@ -74,6 +146,9 @@ func Enable(config Config) error {
 	if err := admin.RequireElevation("Gaming Mode"); err != nil {
 		return err
 	}
+	if err := admin.RequireWriteAccess("enabling Gaming Mode"); err != nil {
+		return err
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -81,31 +156,118 @@ func Enable(config Config) error {
 	if gamingModeEnabled {
 		return fmt.Errorf("gaming mode is already enabled")
 	}
+	// Undo a session a crashed run left behind first, so the settings
+	// saved below are the real originals.
+	if _, err := recoverSession(false); err != nil {
+		return err
+	}
+	// A session still on disk belongs to another live SysCleaner, such as
+	// the background agent, or was left on by an earlier run; starting a
+	// second one would lose its record.
+	if s, err := loadSession(); err == nil && s.Detached {
+		return fmt.Errorf("gaming mode is already on (enabled %s); disable it first", s.Started.Format("2006-01-02 15:04"))
+	} else if err == nil && s.OwnerPID != int32(os.Getpid()) {
+		return fmt.Errorf("gaming mode is already on in another SysCleaner process (PID %d)", s.OwnerPID)
+	}
+
+	op := logger.StartOperation("boost")
+	defer op.End()
+	current = newSession()
+	stats = startSessionStats(config.Game, optimizationsFor(config))
+
+	services := servicesToStop
+	if config.Game != "" {
+		if o, ok := OverrideFor(config.Game); ok {
+			if ran, freed := preLaunchClean(config.Game, o); ran {
+				stats.noteClean(freed)
+			}
+			launcher.RunAll(o.PreLaunch, nil)
+			services = servicesFor(config.Game, o)
+		}
+	}
 
 	if runtime.GOOS == "windows" {
+		if scheme, err := power.Active(); err == nil {
+			current.PowerScheme = scheme
+		} else {
+			log.Printf("[SysCleaner] Could not read the active power plan: %v", err)
+		}
+		current.save()
+
 		// Stop non-essential services
 		log.Println("[SysCleaner] Stopping background services for gaming...")
-		for _, svc := range servicesToStop {
+		for _, svc := range services {
 			log.Printf("[SysCleaner] Stopping service: %s", svc)
 			if err := stopService(svc); err == nil {
-				stoppedServices = append(stoppedServices, svc)
+				current.StoppedServices = append(current.StoppedServices, svc)
+				current.save()
 			}
 		}
 
-		// Set high performance power plan
-		log.Println("[SysCleaner] Setting high performance power plan...")
-		runCmd("powercfg", "/setactive", "8c5e7fda-e8bf-4a96-9a85-a6e23a8c635c")
+		setGamingPowerPlan(config.PowerPlan)
+
+		if config.USBLatency {
+			applyUSBTweaks(func(t usbTweaks) {
+				current.USBSuspend = t.Suspend
+				current.InputDevices = t.Devices
+				current.save()
+			})
+		}
 
 		// Optimize network
 		log.Println("[SysCleaner] Optimizing network settings...")
 		runCmd("netsh", "int", "tcp", "set", "global", "autotuninglevel=normal")
 		runCmd("netsh", "int", "tcp", "set", "global", "chimney=enabled")
 		runCmd("netsh", "int", "tcp", "set", "global", "dca=enabled")
+
+		if suspend := suspendList(config); len(suspend) > 0 {
+			for _, pid := range suspendProcessesByName(suspend) {
+				current.Suspended = append(current.Suspended, suspendedProcess{PID: pid, Created: processCreated(int32(pid))})
+			}
+			current.save()
+			log.Printf("[SysCleaner] Suspended %d background process(es)", len(current.Suspended))
+		}
+
+		if config.PurgeStandby {
+			log.Println("[SysCleaner] Purging standby memory...")
+			if err := memory.TrimNow(); err != nil {
+				log.Printf("[SysCleaner] Standby purge failed: %v", err)
+			}
+			memory.StartContinuousMonitor(nil)
+			standbyMonitor = true
+		}
+
+		if config.GameSettings != (GameSettings{}) {
+			log.Println("[SysCleaner] Applying Windows game settings...")
+			applyGameSettings(config.GameSettings, func(v savedDWord) {
+				current.GameSettings = append(current.GameSettings, v)
+				current.save()
+			})
+		}
+
+		if config.HighResTimer {
+			requestTimerResolution()
+		}
+
+		if config.FocusMode {
+			log.Println("[SysCleaner] Enabling focus mode...")
+			if err := enableFocusMode(); err != nil {
+				log.Printf("[SysCleaner] Focus mode: %v", err)
+			}
+		}
 	}
 
 	gamingModeEnabled = true
+	boostOperationID = op.ID
+	discreteGPU = config.DiscreteGPU
+	streaming = config.Streaming
+	trimWorkingSets, trimWhitelist, trimmedGames = config.TrimWorkingSets, config.Whitelist, make(map[string]bool)
 	log.Println("[SysCleaner] Gaming mode enabled.")
 
+	if config.Game != "" {
+		startLatencyProbe(config.Game)
+	}
+
 	if config.AutoDetectGames {
 		monitorDone = make(chan struct{})
 		go monitorGameProcesses(monitorDone)
@@ -114,12 +276,18 @@ func Enable(config Config) error {
 	return nil
 }
 
-// Disable deactivates gaming mode and restores settings.
+// Disable deactivates gaming mode and restores the settings it changed.
+// When this process has no session, it restores one left by a run that
+// exited without disabling gaming mode, whether it crashed or detached
+// the session.
 func Disable() error {
 	mu.Lock()
 	defer mu.Unlock()
 
 	if !gamingModeEnabled {
+		if found, err := recoverSession(true); found {
+			return err
+		}
 		return fmt.Errorf("gaming mode is not enabled")
 	}
 
@@ -129,33 +297,25 @@ func Disable() error {
 		monitorDone = nil
 	}
 
-	if runtime.GOOS == "windows" {
-		// Restart stopped services
-		log.Println("[SysCleaner] Restoring background services...")
-		for _, svc := range stoppedServices {
-			log.Printf("[SysCleaner] Starting service: %s", svc)
-			startService(svc)
-		}
-		stoppedServices = nil
-
-		// Restore balanced power plan
-		log.Println("[SysCleaner] Restoring balanced power plan...")
-		runCmd("powercfg", "/setactive", "381b4222-f694-41f0-9685-ff5bb260df2e")
+	releaseTimerResolution()
+	stopLatencyProbe()
+	if standbyMonitor {
+		memory.StopContinuousMonitor()
+		standbyMonitor = false
 	}
-
-	// Restore process priorities using native Windows API
-	log.Println("[SysCleaner] Restoring process priorities...")
-	for pid := range originalPriority {
-		if runtime.GOOS == "windows" {
-			// NORMAL_PRIORITY_CLASS = 0x20
-			if err := setProcessPriorityNative(uint32(pid), 0x20); err != nil {
-				log.Printf("[SysCleaner] Failed to restore priority for PID %d: %v", pid, err)
-			}
-		}
+	current.restore()
+	resetQoSSessions()
+	current = session{}
+	if stats != nil {
+		recordSession(stats)
+		stats = nil
 	}
-	originalPriority = make(map[int32]int32)
+	removeSession()
 
 	gamingModeEnabled = false
+	discreteGPU = false
+	streaming = nil
+	trimWorkingSets, trimWhitelist, trimmedGames = false, nil, nil
 	log.Println("[SysCleaner] Gaming mode disabled.")
 	return nil
 }
@@ -167,7 +327,10 @@ func GetStatus() Status {
 
 	status := Status{
 		Enabled:         gamingModeEnabled,
-		StoppedServices: stoppedServices,
+		StoppedServices: current.StoppedServices,
+	}
+	if gamingModeEnabled {
+		status.OperationID = boostOperationID
 	}
 
 	// CPU usage
@@ -217,6 +380,9 @@ func IsEnabled() bool {
 }
 
 func monitorGameProcesses(done chan struct{}) {
+	if len(InstalledGames()) == 0 {
+		ScanLibraries()
+	}
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
@@ -229,6 +395,7 @@ func monitorGameProcesses(done chan struct{}) {
 			if err != nil {
 				continue
 			}
+			var running []string
 			for _, p := range procs {
 				name, err := p.Name()
 				if err != nil {
@@ -236,8 +403,13 @@ func monitorGameProcesses(done chan struct{}) {
 				}
 				if isGameProcess(name) {
 					boostProcessPriority(p)
+					startLatencyProbe(name)
+					running = append(running, name)
+				} else if streamingApp(name) {
+					boostStreamingApp(p)
 				}
 			}
+			syncQoSSessions(running)
 		}
 	}
 }
@@ -249,38 +421,147 @@ func isGameProcess(name string) bool {
 			return true
 		}
 	}
-	return false
+	if _, ok := InstalledGameByExe(name); ok {
+		return true
+	}
+	return hasOverride(name)
 }
 
 func boostProcessPriority(p *process.Process) {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if _, exists := originalPriority[p.Pid]; exists {
+	if !gamingModeEnabled {
+		return
+	}
+	if _, exists := current.Processes[p.Pid]; exists {
 		return // already boosted
 	}
+	name, _ := p.Name()
+	if guardProcessName("boosting", name) {
+		return
+	}
 
+	if stats != nil {
+		stats.noteGame(gameName(name))
+	}
+	nice := recordSessionProcess(p, name)
+
+	if runtime.GOOS == "windows" {
+		override, _ := OverrideFor(name)
+		class, err := priorityClass(override.Priority)
+		if err != nil {
+			log.Printf("[SysCleaner] Game override for %s: %v", name, err)
+			class = highPriorityClass
+		}
+		class, mask := guardGameTweaks(name, class, gameAffinity(name, override))
+		log.Printf("[SysCleaner] Boosting priority for game process: %s (PID: %d)", name, p.Pid)
+		tweakProcess(p.Pid, name, nice, class, mask)
+		if trimWorkingSets && !trimmedGames[strings.ToLower(name)] {
+			trimmedGames[strings.ToLower(name)] = true
+			go func(whitelist []string, streaming *StreamingConfig, s *sessionStats) {
+				if _, freed := trimBackgroundWorkingSets(whitelist, streaming); s != nil {
+					s.noteTrim(freed)
+				}
+			}(trimWhitelist, streaming, stats)
+		}
+		if discreteGPU {
+			if exe, err := p.Exe(); err == nil {
+				if _, err := PreferDiscreteGPU([]string{exe}); err != nil {
+					log.Printf("[SysCleaner] Failed to set GPU preference for %s: %v", name, err)
+				}
+			}
+		}
+	}
+}
+
+// recordSessionProcess adds p to the session before its priority is
+// changed, and returns its current priority. mu must be held.
+func recordSessionProcess(p *process.Process, name string) int32 {
 	nice, err := p.Nice()
 	if err != nil {
 		nice = 0
 	}
-	originalPriority[p.Pid] = nice
+	created, _ := p.CreateTime()
+	current.Processes[p.Pid] = sessionProcess{Name: name, Created: created, Priority: uint32(nice)}
+	current.save()
+	return nice
+}
 
-	if runtime.GOOS == "windows" {
-		name, _ := p.Name()
-		log.Printf("[SysCleaner] Boosting priority for game process: %s (PID: %d)", name, p.Pid)
-		// HIGH_PRIORITY_CLASS = 0x80 — use native API instead of wmic to avoid AV heuristics
-		if err := setProcessPriorityNative(uint32(p.Pid), 0x80); err != nil {
-			log.Printf("[SysCleaner] Failed to boost priority for %s: %v", name, err)
+// tweakProcess sets the priority class of a process recorded by
+// recordSessionProcess and, unless mask is zero, its affinity, saving the
+// original affinity first. mu must be held.
+func tweakProcess(pid int32, name string, nice int32, class uint32, mask uint64) {
+	// Use native API instead of wmic to avoid AV heuristics
+	if err := setProcessPriorityNative(uint32(pid), class); err != nil {
+		log.Printf("[SysCleaner] Failed to boost priority for %s: %v", name, err)
+	} else {
+		logger.Audit(logger.AuditPriority, fmt.Sprintf("%s (PID %d)", name, pid),
+			fmt.Sprintf("priority class 0x%x", class), fmt.Sprintf("priority:%d=%d", pid, nice))
+	}
+	if mask == 0 {
+		return
+	}
+	if orig, err := processAffinityNative(uint32(pid)); err == nil {
+		sp := current.Processes[pid]
+		sp.Affinity = orig
+		current.Processes[pid] = sp
+		current.save()
+		if err := setProcessAffinityNative(uint32(pid), mask); err != nil {
+			log.Printf("[SysCleaner] Failed to set affinity for %s: %v", name, err)
+		} else {
+			log.Printf("[SysCleaner] Restricted %s to CPUs %s", name, describeMask(mask))
+		}
+	}
+}
+
+// gameAffinity returns the affinity mask for the game running as exe: the
+// override's mask, else the cores its override or profile names. Zero
+// leaves affinity alone.
+func gameAffinity(exe string, override GameOverride) uint64 {
+	if override.Affinity != 0 {
+		return override.Affinity
+	}
+	spec := override.Cores
+	if spec == "" {
+		if profile := GetGameProfileByExe(exe); profile != nil {
+			spec = profile.Affinity
 		}
 	}
+	if spec == "" {
+		return 0
+	}
+	return resolveAffinity(exe, spec)
+}
+
+// resolveAffinity returns the affinity mask for the cores spec names on
+// this machine, or zero, logged, if it cannot be resolved.
+func resolveAffinity(exe, spec string) uint64 {
+	topo, err := Topology()
+	if err != nil {
+		log.Printf("[SysCleaner] Cannot apply affinity %q for %s: %v", spec, exe, err)
+		return 0
+	}
+	mask, err := AffinityMask(spec, topo)
+	if err != nil {
+		log.Printf("[SysCleaner] Affinity for %s: %v", exe, err)
+		return 0
+	}
+	return mask
 }
 
 func stopService(name string) error {
+	if guardProcessName("stopping", name) {
+		return fmt.Errorf("%s belongs to an anti-cheat and is never stopped", name)
+	}
 	log.Printf("[SysCleaner] Requesting service stop: %s", name)
 	// Use native SCM API instead of "net stop" to avoid spawning child
 	// processes that trigger AV heuristics.
-	return stopServiceNative(name)
+	if err := stopServiceNative(name); err != nil {
+		return err
+	}
+	logger.Audit(logger.AuditServiceStop, name, "", "service-start:"+name)
+	return nil
 }
 
 func startService(name string) error {
@@ -0,0 +1,36 @@
+package gaming
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// gogGame builds an installed game from the values GOG Galaxy records for
+// each install under HKLM\SOFTWARE\WOW6432Node\GOG.com\Games\<id>. Galaxy's
+// own library database is SQLite, which SysCleaner has no reader for; the
+// registry holds the same installs. exe is a full path to the game's
+// executable.
+func gogGame(id string, values map[string]string) (InstalledGame, bool) {
+	path := values["path"]
+	if path == "" {
+		return InstalledGame{}, false
+	}
+	g := InstalledGame{
+		Name:       values["gameName"],
+		Launcher:   "GOG Galaxy",
+		ID:         id,
+		InstallDir: path,
+	}
+	if values["gameID"] != "" {
+		g.ID = values["gameID"]
+	}
+	if g.Name == "" {
+		g.Name = filepath.Base(strings.ReplaceAll(path, `\`, "/"))
+	}
+	if exe := values["exe"]; exe != "" {
+		g.Executables = []string{exe}
+	} else {
+		g.Executables = gameExecutablesIn(path)
+	}
+	return g, true
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package gaming
+
+func scanGOG() ([]InstalledGame, error) {
+	return nil, nil
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package gaming
+
+import "golang.org/x/sys/windows/registry"
+
+// gogGamesKey is where GOG Galaxy records installed games.
+const gogGamesKey = `SOFTWARE\WOW6432Node\GOG.com\Games`
+
+// scanGOG reads the games GOG Galaxy has installed. A missing key means
+// Galaxy is not installed.
+func scanGOG() ([]InstalledGame, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, gogGamesKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer key.Close()
+	ids, err := key.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var games []InstalledGame
+	for _, id := range ids {
+		k, err := registry.OpenKey(key, id, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		values := make(map[string]string)
+		for _, name := range []string{"gameName", "gameID", "path", "exe"} {
+			if v, _, err := k.GetStringValue(name); err == nil {
+				values[name] = v
+			}
+		}
+		k.Close()
+		if g, ok := gogGame(id, values); ok {
+			games = append(games, g)
+		}
+	}
+	return games, nil
+}
@@ -0,0 +1,161 @@
+package gaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gpuPreferenceHigh is the GpuPreference value that makes Windows run an
+// app on the high-performance (discrete) GPU; 1 is power saving and 0 lets
+// Windows decide.
+const gpuPreferenceHigh = "2"
+
+// gpuPreference is an app's UserGpuPreferences value before SysCleaner
+// changed it. Set is false when the app had no value.
+type gpuPreference struct {
+	Value string `json:"value,omitempty"`
+	Set   bool   `json:"set"`
+}
+
+// gpuPreferenceStatePath is where the original preferences are saved so
+// RestoreGPUPreferences can put them back in a later run.
+var gpuPreferenceStatePath = func() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", "gpu-preference-state.json")
+}
+
+// withHighPerformanceGPU returns a UserGpuPreferences value such as
+// "SwapEffectUpgradeEnable=1;" with GpuPreference set to high performance
+// and the other settings kept.
+func withHighPerformanceGPU(value string) string {
+	var b strings.Builder
+	found := false
+	for _, setting := range strings.Split(value, ";") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		if name, _, _ := strings.Cut(setting, "="); strings.EqualFold(name, "GpuPreference") {
+			setting, found = "GpuPreference="+gpuPreferenceHigh, true
+		}
+		b.WriteString(setting + ";")
+	}
+	if !found {
+		b.WriteString("GpuPreference=" + gpuPreferenceHigh + ";")
+	}
+	return b.String()
+}
+
+// PreferDiscreteGPU sets the Windows graphics preference of each exe, a
+// full path, to high performance so laptops run it on the discrete GPU.
+// It takes effect the next time the game starts. Original preferences are
+// saved for RestoreGPUPreferences. It returns the exes that were changed.
+func PreferDiscreteGPU(exes []string) ([]string, error) {
+	state, err := loadGPUPreferenceState()
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	var firstErr error
+	for _, exe := range exes {
+		if !strings.ContainsAny(exe, `\/`) {
+			continue // preferences are keyed by full path
+		}
+		old, set, err := readGPUPreference(exe)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		value := withHighPerformanceGPU(old)
+		if set && value == old {
+			continue
+		}
+		if _, saved := state[exe]; !saved {
+			state[exe] = gpuPreference{Value: old, Set: set}
+			// Save before writing so a crash cannot lose the original.
+			if err := saveGPUPreferenceState(state); err != nil {
+				return changed, err
+			}
+		}
+		if err := writeGPUPreference(exe, value); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		log.Printf("[SysCleaner] Set high-performance GPU preference for %s", exe)
+		changed = append(changed, exe)
+	}
+	return changed, firstErr
+}
+
+// RestoreGPUPreferences puts back every graphics preference changed by
+// PreferDiscreteGPU and returns how many were restored. Preferences that
+// cannot be restored are kept for the next attempt.
+func RestoreGPUPreferences() (int, error) {
+	state, err := loadGPUPreferenceState()
+	if err != nil || len(state) == 0 {
+		return 0, err
+	}
+	restored := 0
+	var firstErr error
+	for exe, orig := range state {
+		if orig.Set {
+			err = writeGPUPreference(exe, orig.Value)
+		} else {
+			err = deleteGPUPreference(exe)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("restoring GPU preference for %s: %w", exe, err)
+			}
+			continue
+		}
+		delete(state, exe)
+		restored++
+	}
+	if len(state) == 0 {
+		if err := os.Remove(gpuPreferenceStatePath()); err != nil && !os.IsNotExist(err) {
+			log.Printf("[SysCleaner] Failed to remove GPU preference state: %v", err)
+		}
+	} else if err := saveGPUPreferenceState(state); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return restored, firstErr
+}
+
+func loadGPUPreferenceState() (map[string]gpuPreference, error) {
+	state := make(map[string]gpuPreference)
+	data, err := os.ReadFile(gpuPreferenceStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing GPU preference state: %w", err)
+	}
+	return state, nil
+}
+
+func saveGPUPreferenceState(state map[string]gpuPreference) error {
+	path := gpuPreferenceStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package gaming
+
+import "fmt"
+
+var errNoGPUPreference = fmt.Errorf("GPU preferences are only available on Windows")
+
+// readGPUPreference is only available on Windows.
+var readGPUPreference = func(exe string) (string, bool, error) {
+	return "", false, errNoGPUPreference
+}
+
+// writeGPUPreference is only available on Windows.
+var writeGPUPreference = func(exe, value string) error {
+	return errNoGPUPreference
+}
+
+// deleteGPUPreference is only available on Windows.
+var deleteGPUPreference = func(exe string) error {
+	return errNoGPUPreference
+}
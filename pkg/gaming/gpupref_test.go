@@ -0,0 +1,60 @@
+package gaming
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWithHighPerformanceGPU(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                                     "GpuPreference=2;",
+		"GpuPreference=1;":                     "GpuPreference=2;",
+		"SwapEffectUpgradeEnable=1;":           "SwapEffectUpgradeEnable=1;GpuPreference=2;",
+		"GpuPreference=0;VRROptimizeEnable=1;": "GpuPreference=2;VRROptimizeEnable=1;",
+	} {
+		if got := withHighPerformanceGPU(in); got != want {
+			t.Errorf("withHighPerformanceGPU(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPreferDiscreteGPU_Restores(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "gpu-preference-state.json")
+	prefs := map[string]string{`C:\Games\B\b.exe`: "SwapEffectUpgradeEnable=1;"}
+
+	origPath, origRead, origWrite, origDelete := gpuPreferenceStatePath, readGPUPreference, writeGPUPreference, deleteGPUPreference
+	defer func() {
+		gpuPreferenceStatePath, readGPUPreference, writeGPUPreference, deleteGPUPreference = origPath, origRead, origWrite, origDelete
+	}()
+	gpuPreferenceStatePath = func() string { return statePath }
+	readGPUPreference = func(exe string) (string, bool, error) { v, ok := prefs[exe]; return v, ok, nil }
+	writeGPUPreference = func(exe, value string) error { prefs[exe] = value; return nil }
+	deleteGPUPreference = func(exe string) error { delete(prefs, exe); return nil }
+
+	exes := []string{`C:\Games\A\a.exe`, `C:\Games\B\b.exe`}
+	changed, err := PreferDiscreteGPU(exes)
+	if err != nil || len(changed) != 2 {
+		t.Fatalf("PreferDiscreteGPU = %v, %v; want both exes changed", changed, err)
+	}
+	if prefs[`C:\Games\B\b.exe`] != "SwapEffectUpgradeEnable=1;GpuPreference=2;" {
+		t.Errorf("b.exe preference = %q", prefs[`C:\Games\B\b.exe`])
+	}
+	// Applying again changes nothing and keeps the saved originals.
+	if changed, _ := PreferDiscreteGPU(exes); len(changed) != 0 {
+		t.Errorf("second PreferDiscreteGPU changed %v", changed)
+	}
+
+	n, err := RestoreGPUPreferences()
+	if err != nil || n != 2 {
+		t.Fatalf("RestoreGPUPreferences = %d, %v; want 2", n, err)
+	}
+	if _, ok := prefs[`C:\Games\A\a.exe`]; ok {
+		t.Error("a.exe had no preference before and should have none now")
+	}
+	if prefs[`C:\Games\B\b.exe`] != "SwapEffectUpgradeEnable=1;" {
+		t.Errorf("b.exe preference = %q, want the original", prefs[`C:\Games\B\b.exe`])
+	}
+	if n, _ := RestoreGPUPreferences(); n != 0 {
+		t.Errorf("nothing should be left to restore, restored %d", n)
+	}
+}
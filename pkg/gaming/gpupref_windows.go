@@ -0,0 +1,44 @@
+//go:build windows
+
+package gaming
+
+import (
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+// userGpuPreferencesPath holds the per-app graphics preferences set in
+// Settings > Display > Graphics, one REG_SZ value per exe path.
+const userGpuPreferencesPath = `Software\Microsoft\DirectX\UserGpuPreferences`
+
+// readGPUPreference returns exe's graphics preference value, if any.
+var readGPUPreference = func(exe string) (string, bool, error) {
+	k, err := registry.OpenKey(registry.CURRENT_USER, userGpuPreferencesPath, registry.QUERY_VALUE)
+	if err == registry.ErrNotExist {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	defer k.Close()
+	v, _, err := k.GetStringValue(exe)
+	if err == registry.ErrNotExist {
+		return "", false, nil
+	}
+	return v, err == nil, err
+}
+
+// writeGPUPreference sets exe's graphics preference value through reglog,
+// so the change lands in the registry undo file and the audit log.
+var writeGPUPreference = func(exe, value string) error {
+	return reglog.SetStringValue(registry.CURRENT_USER, userGpuPreferencesPath, exe, value)
+}
+
+// deleteGPUPreference removes exe's graphics preference value.
+var deleteGPUPreference = func(exe string) error {
+	err := reglog.DeleteValue(registry.CURRENT_USER, userGpuPreferencesPath, exe)
+	if err == registry.ErrNotExist {
+		return nil
+	}
+	return err
+}
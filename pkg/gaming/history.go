@@ -0,0 +1,356 @@
+package gaming
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"syscleaner/pkg/memory"
+	"syscleaner/pkg/monitor"
+)
+
+// SessionRecord describes a finished gaming session.
+type SessionRecord struct {
+	// Game is the game played, or "" when none was named or detected.
+	Game     string        `json:"game,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+
+	// CPU and RAM load over the session, in percent.
+	AvgCPU  float64 `json:"avg_cpu"`
+	PeakCPU float64 `json:"peak_cpu"`
+	AvgRAM  float64 `json:"avg_ram"`
+	PeakRAM float64 `json:"peak_ram"`
+
+	// GPU load and video memory in use over the session, in percent, and
+	// the hottest the GPU got. GPUBound and VRAMPressure are the shares of
+	// samples in which the GPU was the bottleneck and video memory was
+	// nearly full (see monitor.GPUStatus). All are zero when the GPU could
+	// not be sampled.
+	AvgGPU       float64 `json:"avg_gpu,omitempty"`
+	PeakGPU      float64 `json:"peak_gpu,omitempty"`
+	PeakVRAM     float64 `json:"peak_vram,omitempty"`
+	PeakGPUTemp  int     `json:"peak_gpu_temp,omitempty"`
+	GPUBound     float64 `json:"gpu_bound,omitempty"`
+	VRAMPressure float64 `json:"vram_pressure,omitempty"`
+
+	// Cleans counts the pre-launch cleans and standby memory purges run
+	// for the session, and SpaceFreed what the cleans freed.
+	Cleans     int   `json:"cleans"`
+	SpaceFreed int64 `json:"space_freed,omitempty"`
+
+	// WorkingSetTrimmed is the memory, in bytes, reclaimed by trimming the
+	// working sets of background processes (see Config.TrimWorkingSets).
+	WorkingSetTrimmed int64 `json:"working_set_trimmed,omitempty"`
+
+	// Optimizations names what gaming mode changed, e.g. "power plan".
+	Optimizations []string `json:"optimizations,omitempty"`
+}
+
+// maxSessionHistory is how many sessions the history keeps.
+const maxSessionHistory = 500
+
+// sessionStatsInterval is how often CPU, RAM and GPU load are sampled.
+var sessionStatsInterval = 10 * time.Second
+
+// historyPath is where finished sessions are recorded, one JSON object
+// per line.
+var historyPath = func() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", "gaming-history.jsonl")
+}
+
+// SessionHistory returns the recorded sessions, newest first.
+func SessionHistory() ([]SessionRecord, error) {
+	data, err := os.ReadFile(historyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var recs []SessionRecord
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var r SessionRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			continue // a line cut short by a crash
+		}
+		recs = append(recs, r)
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Started.After(recs[j].Started) })
+	return recs, sc.Err()
+}
+
+// appendSessionHistory adds rec to the history, dropping the oldest
+// sessions beyond maxSessionHistory.
+func appendSessionHistory(rec SessionRecord) error {
+	recs, err := SessionHistory()
+	if err != nil {
+		return err
+	}
+	recs = append([]SessionRecord{rec}, recs...)
+	if len(recs) > maxSessionHistory {
+		recs = recs[:maxSessionHistory]
+	}
+	var buf bytes.Buffer
+	for i := len(recs) - 1; i >= 0; i-- {
+		line, err := json.Marshal(recs[i])
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	path := historyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// GameSummary totals the recorded sessions of one game.
+type GameSummary struct {
+	Game      string
+	Sessions  int
+	TotalTime time.Duration
+	AvgCPU    float64
+	AvgRAM    float64
+	PeakRAM   float64
+	LastPlay  time.Time
+}
+
+// SummarizeSessions totals recs per game, most played first.
+func SummarizeSessions(recs []SessionRecord) []GameSummary {
+	byGame := make(map[string]*GameSummary)
+	for _, r := range recs {
+		game := r.Game
+		if game == "" {
+			game = "(unknown)"
+		}
+		s := byGame[game]
+		if s == nil {
+			s = &GameSummary{Game: game}
+			byGame[game] = s
+		}
+		s.Sessions++
+		s.TotalTime += r.Duration
+		s.AvgCPU += r.AvgCPU
+		s.AvgRAM += r.AvgRAM
+		s.PeakRAM = max(s.PeakRAM, r.PeakRAM)
+		if r.Started.After(s.LastPlay) {
+			s.LastPlay = r.Started
+		}
+	}
+	summaries := make([]GameSummary, 0, len(byGame))
+	for _, s := range byGame {
+		s.AvgCPU /= float64(s.Sessions)
+		s.AvgRAM /= float64(s.Sessions)
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].TotalTime != summaries[j].TotalTime {
+			return summaries[i].TotalTime > summaries[j].TotalTime
+		}
+		return summaries[i].Game < summaries[j].Game
+	})
+	return summaries
+}
+
+// sessionStats gathers the record of the running session.
+type sessionStats struct {
+	mu         sync.Mutex
+	rec        SessionRecord
+	samples    int
+	cpuTotal   float64
+	ramTotal   float64
+	trimsStart int64
+
+	gpuSamples   int
+	gpuTotal     float64
+	gpuBound     int
+	vramPressure int
+
+	done chan struct{}
+}
+
+// stats is the running session's statistics, guarded by mu.
+var stats *sessionStats
+
+// startSessionStats begins sampling load for a session of game.
+func startSessionStats(game string, optimizations []string) *sessionStats {
+	s := &sessionStats{
+		rec:        SessionRecord{Game: game, Started: time.Now(), Optimizations: optimizations},
+		trimsStart: memory.GetCurrentStats().TrimCount,
+		done:       make(chan struct{}),
+	}
+	cpu.Percent(0, false) // start the CPU measurement interval
+	go func() {
+		ticker := time.NewTicker(sessionStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+	return s
+}
+
+// sample records the CPU load since the last sample, RAM in use and the
+// GPU's load, video memory and temperature.
+func (s *sessionStats) sample() {
+	percent, err := cpu.Percent(0, false)
+	if err != nil || len(percent) == 0 {
+		return
+	}
+	vmem, err := mem.VirtualMemory()
+	if err != nil {
+		return
+	}
+	s.add(percent[0], vmem.UsedPercent)
+	s.addGPU(monitor.SampleGPU())
+}
+
+func (s *sessionStats) add(cpuPercent, ramPercent float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples++
+	s.cpuTotal += cpuPercent
+	s.ramTotal += ramPercent
+	s.rec.PeakCPU = max(s.rec.PeakCPU, cpuPercent)
+	s.rec.PeakRAM = max(s.rec.PeakRAM, ramPercent)
+}
+
+// addGPU records a GPU sample. Samples with nothing measured, as on GPUs
+// without load counters, are skipped so they do not drag the average down.
+func (s *sessionStats) addGPU(g monitor.GPUStatus) {
+	if g.Utilization == 0 && g.VRAMUsed == 0 && g.TemperatureC == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gpuSamples++
+	s.gpuTotal += g.Utilization
+	if g.GPUBound() {
+		s.gpuBound++
+	}
+	if g.VRAMPressure() {
+		s.vramPressure++
+	}
+	s.rec.PeakGPU = max(s.rec.PeakGPU, g.Utilization)
+	s.rec.PeakVRAM = max(s.rec.PeakVRAM, g.VRAMPercent())
+	s.rec.PeakGPUTemp = max(s.rec.PeakGPUTemp, g.TemperatureC)
+}
+
+// noteGame names the session's game if none was named yet.
+func (s *sessionStats) noteGame(game string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rec.Game == "" {
+		s.rec.Game = game
+	}
+}
+
+// gameName returns the name of the game exe belongs to, or exe itself.
+func gameName(exe string) string {
+	if p := GetGameProfileByExe(exe); p != nil {
+		return p.Name
+	}
+	if g, ok := InstalledGameByExe(exe); ok {
+		return g.Name
+	}
+	return exe
+}
+
+// noteClean counts a clean run for the session.
+func (s *sessionStats) noteClean(freed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.Cleans++
+	s.rec.SpaceFreed += freed
+}
+
+// noteTrim adds the memory a working set trim reclaimed to the session.
+func (s *sessionStats) noteTrim(freed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.WorkingSetTrimmed += freed
+}
+
+// noteOptimization adds to the session's optimizations.
+func (s *sessionStats) noteOptimization(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.Optimizations = append(s.rec.Optimizations, name)
+}
+
+// finish stops sampling and returns the session's record.
+func (s *sessionStats) finish() SessionRecord {
+	close(s.done)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.rec
+	rec.Duration = time.Since(rec.Started).Round(time.Second)
+	if s.samples > 0 {
+		rec.AvgCPU = s.cpuTotal / float64(s.samples)
+		rec.AvgRAM = s.ramTotal / float64(s.samples)
+	}
+	if s.gpuSamples > 0 {
+		n := float64(s.gpuSamples)
+		rec.AvgGPU = s.gpuTotal / n
+		rec.GPUBound = float64(s.gpuBound) / n * 100
+		rec.VRAMPressure = float64(s.vramPressure) / n * 100
+	}
+	rec.Cleans += int(memory.GetCurrentStats().TrimCount - s.trimsStart)
+	return rec
+}
+
+// recordSession appends the finished session to the history.
+func recordSession(s *sessionStats) {
+	rec := s.finish()
+	if err := appendSessionHistory(rec); err != nil {
+		log.Printf("[SysCleaner] Failed to record gaming session: %v", err)
+		return
+	}
+	log.Printf("[SysCleaner] Recorded gaming session: %q, %s", rec.Game, rec.Duration)
+}
+
+// optimizationsFor names the optimizations config turns on.
+func optimizationsFor(config Config) []string {
+	opts := []string{"services stopped", "network tuning"}
+	if !strings.EqualFold(config.PowerPlan, PowerPlanNone) {
+		opts = append(opts, "power plan")
+	}
+	add := func(on bool, name string) {
+		if on {
+			opts = append(opts, name)
+		}
+	}
+	add(config.AutoDetectGames, "priority boost")
+	add(len(config.SuspendProcesses) > 0 || config.SuspendBackgroundApps, "apps suspended")
+	add(config.FocusMode, "focus mode")
+	add(config.DiscreteGPU, "discrete GPU")
+	add(config.GameSettings != (GameSettings{}), "Windows game settings")
+	add(config.HighResTimer, "timer resolution")
+	add(config.PurgeStandby, "standby purge")
+	add(config.USBLatency, "USB power saving off")
+	add(config.TrimWorkingSets, "working set trim")
+	add(config.Streaming != nil, "streamer mode")
+	return opts
+}
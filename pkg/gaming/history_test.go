@@ -0,0 +1,90 @@
+package gaming
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"syscleaner/pkg/monitor"
+)
+
+func TestSessionHistory_AppendAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gaming-history.jsonl")
+	orig := historyPath
+	historyPath = func() string { return path }
+	defer func() { historyPath = orig }()
+
+	if recs, err := SessionHistory(); err != nil || len(recs) != 0 {
+		t.Fatalf("empty history = %v, %v", recs, err)
+	}
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	for i := 0; i < maxSessionHistory+2; i++ {
+		rec := SessionRecord{Game: "CS2", Started: start.Add(time.Duration(i) * time.Hour), Duration: time.Hour}
+		if err := appendSessionHistory(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recs, err := SessionHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != maxSessionHistory {
+		t.Fatalf("kept %d sessions, want %d", len(recs), maxSessionHistory)
+	}
+	if want := start.Add(time.Duration(maxSessionHistory+1) * time.Hour); !recs[0].Started.Equal(want) {
+		t.Errorf("newest session started %v, want %v", recs[0].Started, want)
+	}
+}
+
+func TestSummarizeSessions(t *testing.T) {
+	recs := []SessionRecord{
+		{Game: "CS2", Duration: time.Hour, AvgCPU: 40, AvgRAM: 50, PeakRAM: 70},
+		{Game: "CS2", Duration: 2 * time.Hour, AvgCPU: 60, AvgRAM: 60, PeakRAM: 80},
+		{Game: "Valorant", Duration: 30 * time.Minute, AvgCPU: 30},
+	}
+	s := SummarizeSessions(recs)
+	if len(s) != 2 || s[0].Game != "CS2" {
+		t.Fatalf("summaries = %+v, want CS2 first", s)
+	}
+	if s[0].Sessions != 2 || s[0].TotalTime != 3*time.Hour || s[0].AvgCPU != 50 || s[0].PeakRAM != 80 {
+		t.Errorf("CS2 summary = %+v", s[0])
+	}
+}
+
+func TestSessionStats_Finish(t *testing.T) {
+	s := &sessionStats{rec: SessionRecord{Started: time.Now()}, done: make(chan struct{})}
+	s.add(20, 50)
+	s.add(60, 70)
+	s.noteGame("CS2")
+	s.noteGame("steam.exe")
+	s.noteClean(1024)
+	s.noteTrim(300 << 20)
+	s.noteTrim(200 << 20)
+
+	rec := s.finish()
+	if rec.Game != "CS2" || rec.AvgCPU != 40 || rec.PeakCPU != 60 || rec.AvgRAM != 60 || rec.PeakRAM != 70 {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.Cleans != 1 || rec.SpaceFreed != 1024 {
+		t.Errorf("cleans = %d (%d bytes), want 1 (1024 bytes)", rec.Cleans, rec.SpaceFreed)
+	}
+	if rec.WorkingSetTrimmed != 500<<20 {
+		t.Errorf("working set trimmed = %d, want 500 MB", rec.WorkingSetTrimmed)
+	}
+}
+
+func TestSessionStats_GPU(t *testing.T) {
+	s := &sessionStats{rec: SessionRecord{Started: time.Now()}, done: make(chan struct{})}
+	s.addGPU(monitor.GPUStatus{Utilization: 99, VRAMUsed: 7 << 30, VRAMTotal: 7 << 30, TemperatureC: 80})
+	s.addGPU(monitor.GPUStatus{Utilization: 61, VRAMUsed: 4 << 30, VRAMTotal: 8 << 30, TemperatureC: 72})
+	s.addGPU(monitor.GPUStatus{}) // nothing measured
+
+	rec := s.finish()
+	if rec.AvgGPU != 80 || rec.PeakGPU != 99 || rec.PeakVRAM != 100 || rec.PeakGPUTemp != 80 {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.GPUBound != 50 || rec.VRAMPressure != 50 {
+		t.Errorf("GPU bound %.0f%%, VRAM pressure %.0f%%; want 50%% each", rec.GPUBound, rec.VRAMPressure)
+	}
+}
@@ -0,0 +1,102 @@
+package gaming
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"syscleaner/pkg/monitor"
+)
+
+var (
+	latencyMu sync.Mutex
+	// latencyTargets maps a lower-cased game name or executable to the
+	// servers probed while it runs.
+	latencyTargets = make(map[string][]string)
+	// latency is the probe of the current session, if any.
+	latency       *monitor.LatencyProbe
+	latencyGame   string
+	latencyCancel context.CancelFunc
+)
+
+// SetLatencyTargets sets the "host:port" servers to probe per game, keyed
+// by game name or executable, from the user's profile.
+func SetLatencyTargets(targets map[string][]string) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	latencyTargets = make(map[string][]string, len(targets))
+	for name, servers := range targets {
+		for _, s := range servers {
+			if err := monitor.ValidateLatencyTarget(s); err != nil {
+				log.Printf("[SysCleaner] Ignoring latency target for %s: %v", name, err)
+				continue
+			}
+			latencyTargets[strings.ToLower(name)] = append(latencyTargets[strings.ToLower(name)], s)
+		}
+	}
+}
+
+// LatencyTargetsFor returns the servers configured for a game, given its
+// name or one of its executables.
+func LatencyTargetsFor(game string) []string {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	return latencyTargetsFor(game)
+}
+
+func latencyTargetsFor(game string) []string {
+	if t, ok := latencyTargets[strings.ToLower(game)]; ok {
+		return t
+	}
+	if p := GetGameProfileByExe(game); p != nil {
+		return latencyTargets[strings.ToLower(p.Name)]
+	}
+	if g, ok := InstalledGameByExe(game); ok {
+		return latencyTargets[strings.ToLower(g.Name)]
+	}
+	return nil
+}
+
+// startLatencyProbe starts probing game's servers, unless a probe is
+// already running or none are configured.
+func startLatencyProbe(game string) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	if latency != nil {
+		return
+	}
+	targets := latencyTargetsFor(game)
+	if len(targets) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	latency, latencyGame, latencyCancel = &monitor.LatencyProbe{Targets: targets}, game, cancel
+	log.Printf("[SysCleaner] Probing %s servers: %s", game, strings.Join(targets, ", "))
+	go latency.Run(ctx)
+}
+
+// stopLatencyProbe stops the probe and logs what it measured.
+func stopLatencyProbe() {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	if latency == nil {
+		return
+	}
+	latencyCancel()
+	for _, s := range latency.Stats() {
+		log.Printf("[SysCleaner] %s server latency: %s", latencyGame, s)
+	}
+	latency, latencyGame, latencyCancel = nil, "", nil
+}
+
+// LatencyStats returns the game whose servers are being probed and the
+// results so far, or "" when no probe is running.
+func LatencyStats() (string, []monitor.LatencyStats) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	if latency == nil {
+		return "", nil
+	}
+	return latencyGame, latency.Stats()
+}
@@ -0,0 +1,21 @@
+package gaming
+
+import "testing"
+
+func TestLatencyTargetsFor(t *testing.T) {
+	SetLatencyTargets(map[string][]string{
+		"CS2":      {"sto.example.net:27015", "no-port.example.net"},
+		"game.exe": {"10.0.0.1:443"},
+	})
+	t.Cleanup(func() { SetLatencyTargets(nil) })
+
+	if got := LatencyTargetsFor("cs2.exe"); len(got) != 1 || got[0] != "sto.example.net:27015" {
+		t.Errorf("cs2.exe targets = %v, want the valid CS2 server only", got)
+	}
+	if got := LatencyTargetsFor("Game.exe"); len(got) != 1 {
+		t.Errorf("Game.exe targets = %v", got)
+	}
+	if got := LatencyTargetsFor("other.exe"); got != nil {
+		t.Errorf("other.exe targets = %v, want none", got)
+	}
+}
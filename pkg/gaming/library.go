@@ -0,0 +1,114 @@
+package gaming
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"syscleaner/pkg/cleaner"
+)
+
+// InstalledGame is a game found in a launcher's library.
+type InstalledGame struct {
+	Name     string
+	Launcher string // e.g. "Epic Games"
+	// ID is the launcher's own identifier for the game.
+	ID         string
+	InstallDir string
+	// Executables are the game's executables as full paths.
+	Executables []string
+}
+
+// libraryScanner finds the games one launcher has installed.
+type libraryScanner struct {
+	launcher string
+	scan     func() ([]InstalledGame, error)
+}
+
+// libraryScanners are the launchers ScanLibraries looks at.
+var libraryScanners = []libraryScanner{
+	{"Epic Games", func() ([]InstalledGame, error) { return scanEpicManifests(epicManifestDir()) }},
+	{"Xbox", func() ([]InstalledGame, error) { return scanXboxGames(xboxGameRoots()) }},
+	{"Battle.net", func() ([]InstalledGame, error) { return scanBattleNet(battleNetDBPath()) }},
+	{"GOG Galaxy", scanGOG},
+	{"EA app", func() ([]InstalledGame, error) { return scanEAManifests(eaManifestDir()) }},
+}
+
+var (
+	libraryMu sync.Mutex
+	library   []InstalledGame
+)
+
+// shaderCacheDirNames are the folders games keep compiled shaders in,
+// directly inside their install folder.
+var shaderCacheDirNames = []string{"ShaderCache", "ShaderCaches", "PSOCache"}
+
+func init() {
+	cleaner.GameShaderCacheDirs = installedShaderCacheDirs
+}
+
+// installedShaderCacheDirs returns the shader cache folders of installed
+// games, scanning the launcher libraries first if that has not been done.
+func installedShaderCacheDirs() []string {
+	games := InstalledGames()
+	if len(games) == 0 {
+		games = ScanLibraries()
+	}
+	var dirs []string
+	for _, g := range games {
+		for _, name := range shaderCacheDirNames {
+			dir := filepath.Join(g.InstallDir, name)
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// ScanLibraries finds the games installed through every supported
+// launcher and remembers them for InstalledGames and InstalledGameByExe.
+// A launcher that cannot be read is logged and skipped.
+func ScanLibraries() []InstalledGame {
+	var games []InstalledGame
+	for _, s := range libraryScanners {
+		found, err := s.scan()
+		if err != nil {
+			log.Printf("[SysCleaner] Could not read %s library: %v", s.launcher, err)
+			continue
+		}
+		games = append(games, found...)
+	}
+	sort.Slice(games, func(i, j int) bool { return strings.ToLower(games[i].Name) < strings.ToLower(games[j].Name) })
+
+	libraryMu.Lock()
+	library = games
+	libraryMu.Unlock()
+	return games
+}
+
+// InstalledGames returns the games found by the last ScanLibraries.
+func InstalledGames() []InstalledGame {
+	libraryMu.Lock()
+	defer libraryMu.Unlock()
+	return append([]InstalledGame(nil), library...)
+}
+
+// InstalledGameByExe returns the installed game that exe, a file name or
+// full path, belongs to.
+func InstalledGameByExe(exe string) (InstalledGame, bool) {
+	base := filepath.Base(exe)
+	libraryMu.Lock()
+	defer libraryMu.Unlock()
+	for _, g := range library {
+		for _, e := range g.Executables {
+			if strings.EqualFold(filepath.Base(e), base) {
+				return g, true
+			}
+		}
+	}
+	return InstalledGame{}, false
+}
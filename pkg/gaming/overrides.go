@@ -0,0 +1,214 @@
+package gaming
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/launcher"
+)
+
+// GameOverride customizes gaming mode for one game. Overrides are keyed by
+// game name (as in Games) or executable name.
+type GameOverride struct {
+	// Priority is the CPU priority class given to the game's process when
+	// auto-detection finds it: "idle", "below normal", "normal",
+	// "above normal" or "high". Empty means high.
+	Priority string `json:"priority,omitempty"`
+
+	// Affinity is a bitmask of the logical CPUs the game may run on (bit 0
+	// is CPU 0). Zero leaves affinity alone.
+	Affinity uint64 `json:"affinity,omitempty"`
+
+	// Cores restricts the game to a set of cores named by topology
+	// instead, such as "physical", "p-cores" or "ccd0" (see AffinityMask).
+	// Affinity takes precedence when both are set.
+	Cores string `json:"cores,omitempty"`
+
+	// StopServices lists services stopped in addition to the defaults
+	// when gaming mode is enabled for this game.
+	StopServices []string `json:"stop_services,omitempty"`
+
+	// KeepServices lists default services left running for this game,
+	// e.g. ones its launcher depends on.
+	KeepServices []string `json:"keep_services,omitempty"`
+
+	// CleanCategories lists cleaner categories (e.g. "Shader Cache") run
+	// before gaming mode is enabled for this game.
+	CleanCategories []string `json:"clean_categories,omitempty"`
+
+	// DSCP tags the game's network traffic with this DSCP value, e.g. 46
+	// (DSCPExpedited), while it runs. Zero leaves it to the profile.
+	DSCP int `json:"dscp,omitempty"`
+
+	// PreLaunch lists tools run, with SysCleaner's rights, before gaming
+	// mode is enabled for this game.
+	PreLaunch []launcher.Action `json:"pre_launch,omitempty"`
+
+	// ShaderCaches lists the game's shader cache folders by vendor (see
+	// ShaderCacheVendors), for backing up, restoring or clearing them for
+	// this game alone. Paths may use %VARIABLES%; relative ones are inside
+	// the game's install folder.
+	ShaderCaches map[string][]string `json:"shader_caches,omitempty"`
+}
+
+// priorityClasses maps normalized priority names to Windows priority
+// classes. Realtime is deliberately absent.
+var priorityClasses = map[string]uint32{
+	"idle":        0x40,
+	"belownormal": 0x4000,
+	"normal":      0x20,
+	"abovenormal": 0x8000,
+	"high":        0x80,
+}
+
+// highPriorityClass is the class given to detected games by default.
+const highPriorityClass = 0x80
+
+// priorityClass returns the Windows priority class for a priority name,
+// accepting "Above Normal", "above_normal" and "abovenormal" alike.
+func priorityClass(name string) (uint32, error) {
+	if name == "" {
+		return highPriorityClass, nil
+	}
+	key := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(name))
+	class, ok := priorityClasses[key]
+	if !ok {
+		return 0, fmt.Errorf("unknown priority %q (valid: idle, below normal, normal, above normal, high)", name)
+	}
+	return class, nil
+}
+
+// Validate reports the first invalid setting in o.
+func (o GameOverride) Validate() error {
+	if _, err := priorityClass(o.Priority); err != nil {
+		return err
+	}
+	if o.Cores != "" {
+		if err := ValidateAffinity(o.Cores); err != nil {
+			return err
+		}
+	}
+	if o.DSCP < 0 || o.DSCP > 63 {
+		return fmt.Errorf("invalid dscp %d (valid: 0-63)", o.DSCP)
+	}
+	var opts cleaner.CleanOptions
+	for _, c := range o.CleanCategories {
+		if err := opts.Select(cleaner.Category(c)); err != nil {
+			return err
+		}
+	}
+	for _, a := range o.PreLaunch {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
+	return validateShaderCaches(o.ShaderCaches)
+}
+
+var (
+	overridesMu sync.Mutex
+	// gameOverrides maps a lower-cased game or executable name to its
+	// override.
+	gameOverrides = make(map[string]GameOverride)
+)
+
+// SetGameOverrides sets the per-game overrides from the user's config.
+func SetGameOverrides(overrides map[string]GameOverride) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	gameOverrides = make(map[string]GameOverride, len(overrides))
+	for name, o := range overrides {
+		gameOverrides[strings.ToLower(name)] = o
+	}
+}
+
+// OverrideFor returns the override for a game, given its name or one of
+// its executables; installed games (see ScanLibraries) are found by
+// executable too. An override keyed by executable wins over one keyed by
+// the game's name.
+func OverrideFor(game string) (GameOverride, bool) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	if o, ok := gameOverrides[strings.ToLower(game)]; ok {
+		return o, true
+	}
+	if p := GetGameProfileByExe(game); p != nil {
+		o, ok := gameOverrides[strings.ToLower(p.Name)]
+		return o, ok
+	}
+	if p := GetGameProfile(game); p != nil {
+		for _, exe := range p.Executables {
+			if o, ok := gameOverrides[strings.ToLower(exe)]; ok {
+				return o, true
+			}
+		}
+	}
+	if g, ok := InstalledGameByExe(game); ok {
+		o, ok := gameOverrides[strings.ToLower(g.Name)]
+		return o, ok
+	}
+	return GameOverride{}, false
+}
+
+// hasOverride reports whether an override is keyed by exe itself, which
+// makes exe count as a game even if it is not in the built-in list.
+func hasOverride(exe string) bool {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	_, ok := gameOverrides[strings.ToLower(exe)]
+	return ok
+}
+
+// servicesFor returns the services to stop for a game's override: the
+// defaults plus StopServices, minus KeepServices and the services the game
+// profile says must keep running.
+func servicesFor(game string, o GameOverride) []string {
+	keep := make(map[string]bool)
+	for _, s := range o.KeepServices {
+		keep[strings.ToLower(s)] = true
+	}
+	p := GetGameProfile(game)
+	if p == nil {
+		p = GetGameProfileByExe(game)
+	}
+	if p != nil {
+		for _, s := range p.PreserveServices {
+			keep[strings.ToLower(s)] = true
+		}
+	}
+
+	var out []string
+	seen := make(map[string]bool)
+	for _, s := range append(append([]string(nil), servicesToStop...), o.StopServices...) {
+		lower := strings.ToLower(s)
+		if keep[lower] || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+// preLaunchClean runs the override's clean categories at background I/O
+// priority before gaming mode starts. It reports whether a clean ran and
+// the space it freed.
+func preLaunchClean(game string, o GameOverride) (bool, int64) {
+	if len(o.CleanCategories) == 0 {
+		return false, 0
+	}
+	opts := cleaner.CleanOptions{Background: true}
+	for _, c := range o.CleanCategories {
+		if err := opts.Select(cleaner.Category(c)); err != nil {
+			log.Printf("[SysCleaner] Skipping pre-launch clean for %s: %v", game, err)
+			return false, 0
+		}
+	}
+	log.Printf("[SysCleaner] Running pre-launch clean for %s...", game)
+	result := cleaner.PerformClean(opts)
+	log.Printf("[SysCleaner] Pre-launch clean for %s freed %s", game, cleaner.FormatBytes(result.SpaceFreed))
+	return true, result.SpaceFreed
+}
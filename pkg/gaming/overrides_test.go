@@ -0,0 +1,61 @@
+package gaming
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOverrideFor_NameAndExe(t *testing.T) {
+	SetGameOverrides(map[string]GameOverride{
+		"Valorant":   {Priority: "above normal"},
+		"MyGame.exe": {Affinity: 0x3},
+	})
+	t.Cleanup(func() { SetGameOverrides(nil) })
+
+	if o, ok := OverrideFor("VALORANT-Win64-Shipping.exe"); !ok || o.Priority != "above normal" {
+		t.Errorf("expected the Valorant override by exe, got %+v, %v", o, ok)
+	}
+	if o, ok := OverrideFor("mygame.exe"); !ok || o.Affinity != 0x3 {
+		t.Errorf("expected the MyGame.exe override, got %+v, %v", o, ok)
+	}
+	if _, ok := OverrideFor("cs2.exe"); ok {
+		t.Error("cs2.exe has no override")
+	}
+	if !isGameProcess("MyGame.exe") {
+		t.Error("an exe with an override should count as a game")
+	}
+}
+
+func TestPriorityClass(t *testing.T) {
+	for name, want := range map[string]uint32{"": 0x80, "Above Normal": 0x8000, "below_normal": 0x4000, "normal": 0x20} {
+		if got, err := priorityClass(name); err != nil || got != want {
+			t.Errorf("priorityClass(%q) = %#x, %v; want %#x", name, got, err, want)
+		}
+	}
+	if _, err := priorityClass("realtime"); err == nil {
+		t.Error("realtime should be rejected")
+	}
+}
+
+func TestGameOverrideValidate(t *testing.T) {
+	if err := (GameOverride{CleanCategories: []string{"Shader Cache"}}).Validate(); err != nil {
+		t.Errorf("valid override rejected: %v", err)
+	}
+	if err := (GameOverride{CleanCategories: []string{"Shaders"}}).Validate(); err == nil {
+		t.Error("expected an error for an unknown clean category")
+	}
+}
+
+func TestServicesFor(t *testing.T) {
+	got := servicesFor("Valorant", GameOverride{StopServices: []string{"Spooler", "vgc"}, KeepServices: []string{"wsearch"}})
+	joined := strings.Join(got, ",")
+	if !strings.Contains(joined, "Spooler") {
+		t.Errorf("expected extra service stopped, got %v", got)
+	}
+	if strings.Contains(joined, "WSearch") {
+		t.Errorf("kept service should not be stopped, got %v", got)
+	}
+	if strings.Contains(joined, "vgc") {
+		t.Errorf("the game's preserved services should not be stopped, got %v", got)
+	}
+}
@@ -0,0 +1,69 @@
+package gaming
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"syscleaner/pkg/power"
+)
+
+// Power plans gaming mode can switch to (Config.PowerPlan).
+const (
+	PowerPlanHigh     = "high"
+	PowerPlanUltimate = "ultimate"
+	PowerPlanNone     = "none"
+)
+
+// These are variables so tests can fake the power plans.
+var (
+	ensureHighPerformance = power.EnsureHighPerformance
+	ensureUltimate        = power.EnsureUltimate
+)
+
+// ValidatePowerPlan reports whether plan is a known power plan setting.
+func ValidatePowerPlan(plan string) error {
+	switch strings.ToLower(plan) {
+	case "", PowerPlanHigh, PowerPlanUltimate, PowerPlanNone:
+		return nil
+	}
+	return fmt.Errorf("unknown power plan %q (valid: high, ultimate, none)", plan)
+}
+
+// gamingPowerScheme returns the GUID of the plan to switch to for plan,
+// or "" for PowerPlanNone. A hidden built-in plan is duplicated so it can
+// be activated. When Ultimate Performance can't be made available, High
+// Performance is used instead.
+func gamingPowerScheme(plan string) (string, error) {
+	switch strings.ToLower(plan) {
+	case PowerPlanNone:
+		return "", nil
+	case PowerPlanUltimate:
+		guid, err := ensureUltimate()
+		if err == nil {
+			return guid, nil
+		}
+		log.Printf("[SysCleaner] Ultimate Performance plan unavailable, using High Performance: %v", err)
+	}
+	return ensureHighPerformance()
+}
+
+// setGamingPowerPlan switches to the plan for plan, logging failures.
+func setGamingPowerPlan(plan string) {
+	if err := ValidatePowerPlan(plan); err != nil {
+		log.Printf("[SysCleaner] %v, using High Performance", err)
+		plan = PowerPlanHigh
+	}
+	guid, err := gamingPowerScheme(plan)
+	if err != nil {
+		log.Printf("[SysCleaner] Could not find a performance power plan: %v", err)
+		return
+	}
+	if guid == "" {
+		return
+	}
+	log.Printf("[SysCleaner] Setting power plan %s...", guid)
+	if err := power.Activate(guid); err != nil {
+		log.Printf("[SysCleaner] Failed to set power plan %s: %v", guid, err)
+	}
+}
@@ -0,0 +1,43 @@
+package gaming
+
+import (
+	"errors"
+	"testing"
+
+	"syscleaner/pkg/power"
+)
+
+func TestGamingPowerScheme(t *testing.T) {
+	origHigh, origUltimate := ensureHighPerformance, ensureUltimate
+	defer func() { ensureHighPerformance, ensureUltimate = origHigh, origUltimate }()
+
+	ensureHighPerformance = func() (string, error) { return power.HighPerformance, nil }
+	ensureUltimate = func() (string, error) { return power.UltimatePerformance, nil }
+
+	if guid, err := gamingPowerScheme(""); err != nil || guid != power.HighPerformance {
+		t.Errorf("default plan = %q, %v; want High Performance", guid, err)
+	}
+	if guid, err := gamingPowerScheme(PowerPlanNone); err != nil || guid != "" {
+		t.Errorf("none = %q, %v; want no plan", guid, err)
+	}
+	if guid, err := gamingPowerScheme("Ultimate"); err != nil || guid != power.UltimatePerformance {
+		t.Errorf("ultimate = %q, %v; want Ultimate Performance", guid, err)
+	}
+
+	// Without Ultimate Performance, High Performance is used.
+	ensureUltimate = func() (string, error) { return "", errors.New("duplicate failed") }
+	if guid, err := gamingPowerScheme(PowerPlanUltimate); err != nil || guid != power.HighPerformance {
+		t.Errorf("ultimate unavailable = %q, %v; want High Performance", guid, err)
+	}
+}
+
+func TestValidatePowerPlan(t *testing.T) {
+	for _, plan := range []string{"", "high", "Ultimate", "none"} {
+		if err := ValidatePowerPlan(plan); err != nil {
+			t.Errorf("ValidatePowerPlan(%q) = %v", plan, err)
+		}
+	}
+	if err := ValidatePowerPlan("turbo"); err == nil {
+		t.Error("ValidatePowerPlan(\"turbo\") = nil")
+	}
+}
@@ -7,3 +7,17 @@ import "fmt"
 func terminateProcessByName(name string) error {
 	return fmt.Errorf("process termination not available on this platform")
 }
+
+func suspendProcessesByName(names []string) []uint32 {
+	return nil
+}
+
+func resumeProcesses(pids []uint32) {}
+
+func processAffinityNative(pid uint32) (uint64, error) {
+	return 0, fmt.Errorf("process affinity not available on this platform")
+}
+
+func setProcessAffinityNative(pid uint32, mask uint64) error {
+	return fmt.Errorf("process affinity not available on this platform")
+}
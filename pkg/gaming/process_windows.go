@@ -10,6 +10,16 @@ import (
 	"golang.org/x/sys/windows"
 )
 
+var (
+	ntdll                = windows.NewLazySystemDLL("ntdll.dll")
+	procNtSuspendProcess = ntdll.NewProc("NtSuspendProcess")
+	procNtResumeProcess  = ntdll.NewProc("NtResumeProcess")
+
+	kernel32                   = windows.NewLazySystemDLL("kernel32.dll")
+	procGetProcessAffinityMask = kernel32.NewProc("GetProcessAffinityMask")
+	procSetProcessAffinityMask = kernel32.NewProc("SetProcessAffinityMask")
+)
+
 // terminateProcessByName finds and terminates a process by its executable name
 // using native Windows APIs instead of spawning taskkill.exe child processes.
 // This avoids triggering AV heuristics from rapid child process spawning.
@@ -56,3 +66,104 @@ func terminateProcessByName(name string) error {
 	}
 	return nil
 }
+
+// suspendProcessesByName suspends every running process whose executable
+// name matches one of names, except those the anti-cheat guard protects,
+// and returns the PIDs it suspended.
+func suspendProcessesByName(names []string) []uint32 {
+	if len(names) == 0 {
+		return nil
+	}
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[strings.ToLower(n)] = true
+	}
+
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	guarded := guardedPIDs()
+	var suspended []uint32
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		if !want[strings.ToLower(windows.UTF16ToString(entry.ExeFile[:]))] {
+			continue
+		}
+		if p, ok := guarded[entry.ProcessID]; ok {
+			guardLog("not suspending %s (PID %d): %s", p.Name, p.PID, p.Reason)
+			continue
+		}
+		if callOnProcess(procNtSuspendProcess, entry.ProcessID) == nil {
+			suspended = append(suspended, entry.ProcessID)
+		}
+	}
+	return suspended
+}
+
+// resumeProcesses resumes processes suspended by suspendProcessesByName.
+func resumeProcesses(pids []uint32) {
+	for _, pid := range pids {
+		_ = callOnProcess(procNtResumeProcess, pid)
+	}
+}
+
+func callOnProcess(proc *windows.LazyProc, pid uint32) error {
+	handle, err := windows.OpenProcess(windows.PROCESS_SUSPEND_RESUME, false, pid)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(handle)
+
+	if status, _, _ := proc.Call(uintptr(handle)); status != 0 {
+		return fmt.Errorf("NTSTATUS 0x%x", status)
+	}
+	return nil
+}
+
+// processAffinityNative returns the CPU affinity mask of a process.
+func processAffinityNative(pid uint32) (uint64, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	var procMask, sysMask uintptr
+	if r, _, err := procGetProcessAffinityMask.Call(uintptr(handle),
+		uintptr(unsafe.Pointer(&procMask)), uintptr(unsafe.Pointer(&sysMask))); r == 0 {
+		return 0, fmt.Errorf("failed to read affinity of process %d: %w", pid, err)
+	}
+	return uint64(procMask), nil
+}
+
+// setProcessAffinityNative restricts a process to the CPUs in mask. The
+// mask is clipped to the CPUs the system has; a mask naming none of them
+// is an error.
+func setProcessAffinityNative(pid uint32, mask uint64) error {
+	handle, err := windows.OpenProcess(
+		windows.PROCESS_SET_INFORMATION|windows.PROCESS_QUERY_LIMITED_INFORMATION,
+		false, pid)
+	if err != nil {
+		return fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	var procMask, sysMask uintptr
+	if r, _, err := procGetProcessAffinityMask.Call(uintptr(handle),
+		uintptr(unsafe.Pointer(&procMask)), uintptr(unsafe.Pointer(&sysMask))); r == 0 {
+		return fmt.Errorf("failed to read affinity of process %d: %w", pid, err)
+	}
+	mask &= uint64(sysMask)
+	if mask == 0 {
+		return fmt.Errorf("affinity mask names no CPUs on this system")
+	}
+	if r, _, err := procSetProcessAffinityMask.Call(uintptr(handle), uintptr(mask)); r == 0 {
+		return fmt.Errorf("failed to set affinity of process %d: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package gaming
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// DSCPExpedited is the Expedited Forwarding code point (46), the usual
+// choice for latency-sensitive game traffic.
+const DSCPExpedited = 46
+
+var (
+	qosMu sync.Mutex
+	// qosOverrides maps a lower-cased game name to the DSCP value set by the
+	// active profile, overriding GameProfile.DSCP.
+	qosOverrides = make(map[string]int)
+	// activeQoS maps a lower-cased executable to the DSCP value of the
+	// policy created for its current session.
+	activeQoS = make(map[string]int)
+)
+
+// SetQoSOverrides sets per-game DSCP values, keyed by game name or
+// executable, from the user's profile. A value of 0 turns tagging off for
+// that game.
+func SetQoSOverrides(dscp map[string]int) {
+	qosMu.Lock()
+	defer qosMu.Unlock()
+	qosOverrides = make(map[string]int, len(dscp))
+	for name, v := range dscp {
+		qosOverrides[strings.ToLower(name)] = v
+	}
+}
+
+// dscpForExe returns the DSCP value to tag exe's traffic with, or 0. A
+// game override's DSCP comes first, then the profile's value for the exe
+// or the game's name, then the built-in game profile's.
+func dscpForExe(exe string) int {
+	if o, ok := OverrideFor(exe); ok && o.DSCP > 0 {
+		return o.DSCP
+	}
+	name := ""
+	if p := GetGameProfileByExe(exe); p != nil {
+		name = p.Name
+	} else if g, ok := InstalledGameByExe(exe); ok {
+		name = g.Name
+	}
+
+	qosMu.Lock()
+	defer qosMu.Unlock()
+	if v, ok := qosOverrides[strings.ToLower(exe)]; ok {
+		return v
+	}
+	if v, ok := qosOverrides[strings.ToLower(name)]; ok && name != "" {
+		return v
+	}
+	if p := GetGameProfileByExe(exe); p != nil {
+		return p.DSCP
+	}
+	return 0
+}
+
+// recordQoSPolicy adds a policy to the session file before it is
+// created, so a run that dies without disabling gaming mode has it
+// removed by RecoverSession. It reports false once gaming mode is off.
+var recordQoSPolicy = func(name string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if !gamingModeEnabled {
+		return false
+	}
+	current.QoSPolicies = append(current.QoSPolicies, name)
+	current.save()
+	return true
+}
+
+// qosPolicyName is the name of the QoS policy created for exe.
+func qosPolicyName(exe string) string {
+	return "SysCleaner " + exe
+}
+
+// qosPolicyValues returns the registry values of a policy-based QoS rule
+// that tags all of exe's traffic with dscp.
+func qosPolicyValues(exe string, dscp int) map[string]string {
+	return map[string]string{
+		"Version":                 "1.0",
+		"Application Name":        exe,
+		"Protocol":                "*",
+		"Local Port":              "*",
+		"Local IP":                "*",
+		"Local IP Prefix Length":  "*",
+		"Remote Port":             "*",
+		"Remote IP":               "*",
+		"Remote IP Prefix Length": "*",
+		"DSCP Value":              fmt.Sprint(dscp),
+		"Throttle Rate":           "-1",
+	}
+}
+
+// syncQoSSessions creates a QoS policy for each running game that has a
+// DSCP value and removes policies for games that have exited. running holds
+// the executable names of the game processes seen this tick.
+func syncQoSSessions(running []string) {
+	seen := make(map[string]string, len(running))
+	for _, exe := range running {
+		seen[strings.ToLower(exe)] = exe
+	}
+
+	qosMu.Lock()
+	var stale []string
+	for exe := range activeQoS {
+		if _, ok := seen[exe]; !ok {
+			stale = append(stale, exe)
+		}
+	}
+	qosMu.Unlock()
+
+	for _, exe := range stale {
+		endQoSSession(exe)
+	}
+	for lower, exe := range seen {
+		qosMu.Lock()
+		_, active := activeQoS[lower]
+		qosMu.Unlock()
+		if active {
+			continue
+		}
+		dscp := dscpForExe(exe)
+		if dscp <= 0 {
+			continue
+		}
+		if !recordQoSPolicy(qosPolicyName(lower)) {
+			continue
+		}
+		if err := createQoSPolicy(qosPolicyName(lower), exe, dscp); err != nil {
+			log.Printf("[SysCleaner] Could not create QoS policy for %s: %v", exe, err)
+			continue
+		}
+		log.Printf("[SysCleaner] Tagging %s traffic with DSCP %d", exe, dscp)
+		qosMu.Lock()
+		activeQoS[lower] = dscp
+		qosMu.Unlock()
+	}
+}
+
+// endQoSSession removes the QoS policy created for exe.
+func endQoSSession(exe string) {
+	lower := strings.ToLower(exe)
+	if err := removeQoSPolicy(qosPolicyName(lower)); err != nil {
+		log.Printf("[SysCleaner] Could not remove QoS policy for %s: %v", exe, err)
+	} else {
+		log.Printf("[SysCleaner] Removed QoS policy for %s", exe)
+	}
+	qosMu.Lock()
+	delete(activeQoS, lower)
+	qosMu.Unlock()
+}
+
+// resetQoSSessions forgets the policies of the ended session, which
+// session.restore has removed.
+func resetQoSSessions() {
+	qosMu.Lock()
+	defer qosMu.Unlock()
+	activeQoS = make(map[string]int)
+}
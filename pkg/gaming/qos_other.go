@@ -0,0 +1,15 @@
+//go:build !windows
+
+package gaming
+
+import "fmt"
+
+// createQoSPolicy is only available on Windows.
+var createQoSPolicy = func(name, exe string, dscp int) error {
+	return fmt.Errorf("QoS policies are only available on Windows")
+}
+
+// removeQoSPolicy is only available on Windows.
+var removeQoSPolicy = func(name string) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package gaming
+
+import "testing"
+
+func TestSyncQoSSessions_CreatesAndRemovesPolicies(t *testing.T) {
+	created := map[string]int{}
+	origCreate, origRemove, origRecord := createQoSPolicy, removeQoSPolicy, recordQoSPolicy
+	var recorded []string
+	recordQoSPolicy = func(name string) bool {
+		recorded = append(recorded, name)
+		return true
+	}
+	createQoSPolicy = func(name, exe string, dscp int) error {
+		created[name] = dscp
+		return nil
+	}
+	removeQoSPolicy = func(name string) error {
+		delete(created, name)
+		return nil
+	}
+	SetQoSOverrides(map[string]int{"CS2": DSCPExpedited, "Valorant": 0})
+	t.Cleanup(func() {
+		createQoSPolicy, removeQoSPolicy, recordQoSPolicy = origCreate, origRemove, origRecord
+		SetQoSOverrides(nil)
+	})
+
+	syncQoSSessions([]string{"cs2.exe", "VALORANT.exe", "steam.exe"})
+	if len(created) != 1 || created[qosPolicyName("cs2.exe")] != DSCPExpedited {
+		t.Fatalf("expected a single DSCP 46 policy for cs2.exe, got %v", created)
+	}
+	if len(recorded) != 1 || recorded[0] != qosPolicyName("cs2.exe") {
+		t.Errorf("recorded policies = %v, want the cs2.exe policy", recorded)
+	}
+
+	// Game exits: its policy is removed.
+	syncQoSSessions(nil)
+	if len(created) != 0 {
+		t.Errorf("expected policies removed after exit, got %v", created)
+	}
+}
+
+func TestQoSPolicyValues(t *testing.T) {
+	v := qosPolicyValues("cs2.exe", 46)
+	if v["Application Name"] != "cs2.exe" || v["DSCP Value"] != "46" || v["Version"] != "1.0" {
+		t.Errorf("unexpected policy values: %v", v)
+	}
+}
+
+func TestDSCPForExe(t *testing.T) {
+	SetQoSOverrides(map[string]int{"custom.exe": 34, "CS2": 10})
+	SetGameOverrides(map[string]GameOverride{"cs2.exe": {DSCP: DSCPExpedited}})
+	t.Cleanup(func() {
+		SetQoSOverrides(nil)
+		SetGameOverrides(nil)
+	})
+
+	for exe, want := range map[string]int{
+		"cs2.exe":     DSCPExpedited, // game override beats the profile
+		"Custom.exe":  34,            // profile keyed by exe
+		"unknown.exe": 0,
+	} {
+		if got := dscpForExe(exe); got != want {
+			t.Errorf("dscpForExe(%q) = %d, want %d", exe, got, want)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package gaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/power"
+)
+
+// normalPriorityClass is NORMAL_PRIORITY_CLASS, what boosted processes are
+// returned to when their original class is unknown.
+const normalPriorityClass = 0x20
+
+// sessionProcess is a process whose priority or affinity gaming mode
+// changed, with the values it had before.
+type sessionProcess struct {
+	Name string `json:"name"`
+	// Created is the process start time (ms since the epoch), which tells
+	// the process apart from a later one given the same PID.
+	Created  int64  `json:"created"`
+	Priority uint32 `json:"priority,omitempty"`
+	// Affinity is the original affinity mask; 0 means it was not changed.
+	Affinity uint64 `json:"affinity,omitempty"`
+}
+
+// suspendedProcess is a process gaming mode suspended. Created is its
+// start time, as in sessionProcess, so a later process given the same PID
+// is not resumed in its place.
+type suspendedProcess struct {
+	PID     uint32 `json:"pid"`
+	Created int64  `json:"created"`
+}
+
+// session records everything a gaming mode session changed so it can be
+// undone, by Disable or, if SysCleaner died first, by RecoverSession on a
+// later run. It is saved to disk after every change.
+type session struct {
+	Started time.Time `json:"started"`
+	// OwnerPID and OwnerCreated identify the SysCleaner process running
+	// the session; while it is alive the session is not stale.
+	OwnerPID     int32 `json:"owner_pid"`
+	OwnerCreated int64 `json:"owner_created"`
+	// Detached is set when the owner exited normally and left gaming mode
+	// on, as `syscleaner gaming --enable` does (see DetachSession). The
+	// session then outlives its owner and only Disable ends it; a session
+	// whose owner exits without setting it was left by a crash.
+	Detached bool `json:"detached,omitempty"`
+
+	// PowerScheme is the GUID of the power plan active before the session.
+	PowerScheme     string                   `json:"power_scheme,omitempty"`
+	StoppedServices []string                 `json:"stopped_services,omitempty"`
+	Suspended       []suspendedProcess       `json:"suspended,omitempty"`
+	Processes       map[int32]sessionProcess `json:"processes,omitempty"`
+	// GameSettings holds the Windows game setting values changed for the
+	// session (see GameSettings).
+	GameSettings []savedDWord `json:"game_settings,omitempty"`
+	// QoSPolicies names the QoS policies created for running games.
+	QoSPolicies []string `json:"qos_policies,omitempty"`
+	// USBSuspend is the USB selective suspend setting before the session
+	// and InputDevices the mice and keyboards kept from sleeping.
+	USBSuspend   *savedPowerSetting `json:"usb_suspend,omitempty"`
+	InputDevices []string           `json:"input_devices,omitempty"`
+
+	// Extreme is set while extreme mode is on, at ExtremeLevel ("" for
+	// sessions recorded before levels, which were ExtremeFull).
+	// ShellStopped records that Explorer was stopped and ExtremeServices
+	// the services extreme mode stopped on top of gaming mode's.
+	Extreme         bool     `json:"extreme,omitempty"`
+	ExtremeLevel    string   `json:"extreme_level,omitempty"`
+	ShellStopped    bool     `json:"shell_stopped,omitempty"`
+	ExtremeServices []string `json:"extreme_services,omitempty"`
+}
+
+// sessionPath is where the running session is recorded.
+var sessionPath = func() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", "gaming-session.json")
+}
+
+// newSession starts a session record owned by this process.
+func newSession() session {
+	s := session{
+		Started:   time.Now(),
+		OwnerPID:  int32(os.Getpid()),
+		Processes: make(map[int32]sessionProcess),
+	}
+	s.OwnerCreated = processCreated(s.OwnerPID)
+	return s
+}
+
+// processCreated returns the start time of pid, or 0 if it is not running.
+func processCreated(pid int32) int64 {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return 0
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return 0
+	}
+	return created
+}
+
+// stale reports whether the process that ran s has exited without ending
+// or detaching the session.
+func (s session) stale() bool {
+	if s.Detached {
+		return false
+	}
+	created := processCreated(s.OwnerPID)
+	return created == 0 || created != s.OwnerCreated
+}
+
+func (s session) save() {
+	path := sessionPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("[SysCleaner] Failed to save gaming session: %v", err)
+		return
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		log.Printf("[SysCleaner] Failed to save gaming session: %v", err)
+	}
+}
+
+func loadSession() (session, error) {
+	var s session
+	data, err := os.ReadFile(sessionPath())
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parsing gaming session: %w", err)
+	}
+	return s, nil
+}
+
+func removeSession() {
+	if err := os.Remove(sessionPath()); err != nil && !os.IsNotExist(err) {
+		log.Printf("[SysCleaner] Failed to remove gaming session: %v", err)
+	}
+}
+
+// restore undoes the changes recorded in s. Processes that have exited, or
+// whose PID now belongs to another process, are skipped.
+func (s session) restore() {
+	if runtime.GOOS != "windows" {
+		return
+	}
+	// Bring the desktop back first; the rest can take a while.
+	if s.ShellStopped && !processRunning("explorer.exe") {
+		log.Println("[SysCleaner] Restarting Windows Explorer...")
+		if err := startWindowsExplorer(); err != nil {
+			log.Printf("[SysCleaner] Failed to restart explorer: %v", err)
+		}
+	}
+	if s.Extreme {
+		for i, svc := range s.ExtremeServices {
+			startService(svc)
+			if i > 0 && i%3 == 0 {
+				time.Sleep(500 * time.Millisecond)
+			}
+		}
+		if normalizeExtremeLevel(s.ExtremeLevel) == ExtremeFull {
+			enableVisualEffects()
+		}
+	}
+
+	log.Println("[SysCleaner] Restoring background services...")
+	for _, svc := range s.StoppedServices {
+		log.Printf("[SysCleaner] Starting service: %s", svc)
+		startService(svc)
+	}
+
+	if len(s.Suspended) > 0 {
+		log.Println("[SysCleaner] Resuming suspended processes...")
+		resumeProcesses(stillSuspended(s.Suspended))
+	}
+
+	restoreGameSettings(s.GameSettings)
+
+	for _, name := range s.QoSPolicies {
+		if err := removeQoSPolicy(name); err != nil {
+			log.Printf("[SysCleaner] Failed to remove QoS policy %s: %v", name, err)
+		}
+	}
+
+	if err := restoreFocusMode(); err != nil {
+		log.Printf("[SysCleaner] Failed to restore focus mode settings: %v", err)
+	}
+
+	restoreUSBTweaks(usbTweaks{Suspend: s.USBSuspend, Devices: s.InputDevices})
+
+	scheme := s.PowerScheme
+	if scheme == "" {
+		scheme = power.Balanced
+	}
+	log.Printf("[SysCleaner] Restoring power plan %s...", scheme)
+	if err := power.Activate(scheme); err != nil {
+		log.Printf("[SysCleaner] Failed to restore power plan %s: %v", scheme, err)
+	}
+
+	log.Println("[SysCleaner] Restoring process priorities...")
+	for pid, p := range s.Processes {
+		if created := processCreated(pid); created == 0 || created != p.Created {
+			continue
+		}
+		class := p.Priority
+		if !validPriorityClass(class) {
+			class = normalPriorityClass
+		}
+		if err := setProcessPriorityNative(uint32(pid), class); err != nil {
+			log.Printf("[SysCleaner] Failed to restore priority for PID %d: %v", pid, err)
+		} else {
+			logger.Audit(logger.AuditPriority, fmt.Sprintf("%s (PID %d)", p.Name, pid),
+				fmt.Sprintf("restored to priority class 0x%x", class), "")
+		}
+		if p.Affinity != 0 {
+			if err := setProcessAffinityNative(uint32(pid), p.Affinity); err != nil {
+				log.Printf("[SysCleaner] Failed to restore affinity for PID %d: %v", pid, err)
+			}
+		}
+	}
+}
+
+// stillSuspended returns the PIDs of the processes in procs that are still
+// the ones gaming mode suspended, leaving out any that exited and any PID
+// now used by another process.
+func stillSuspended(procs []suspendedProcess) []uint32 {
+	var pids []uint32
+	for _, p := range procs {
+		if created := processCreated(int32(p.PID)); created != 0 && created == p.Created {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
+
+// validPriorityClass reports whether class is one gaming mode can set.
+func validPriorityClass(class uint32) bool {
+	for _, c := range priorityClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// StaleSession reports whether a gaming mode session was left running by
+// a SysCleaner process that has since exited, e.g. after a crash.
+func StaleSession() bool {
+	s, err := loadSession()
+	return err == nil && s.stale()
+}
+
+// DetachSession marks this process's gaming mode session as meant to
+// outlive it, for a run that enables gaming mode and then exits, such as
+// the CLI's --enable. Later runs leave the session alone until Disable
+// ends it, instead of recovering it as left by a crash. It does nothing
+// when gaming mode is off.
+func DetachSession() {
+	mu.Lock()
+	defer mu.Unlock()
+	if !gamingModeEnabled {
+		return
+	}
+	current.Detached = true
+	current.save()
+}
+
+// RecoverSession undoes a gaming mode session left by a SysCleaner
+// process that exited without disabling it. It reports whether there was
+// one; a session still owned by a running process is left alone. Restoring
+// needs administrator rights, and without them the session is kept for a
+// later attempt.
+func RecoverSession() (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return recoverSession(false)
+}
+
+// recoverSession is RecoverSession with mu held. With detached set it
+// also ends a session detached by the run that started it, as Disable
+// does from a later run.
+func recoverSession(detached bool) (bool, error) {
+	s, err := loadSession()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[SysCleaner] Discarding gaming session: %v", err)
+			removeSession()
+		}
+		return false, nil
+	}
+	if !s.stale() && !(detached && s.Detached) {
+		return false, nil
+	}
+	if err := admin.RequireElevation("Restoring a gaming session"); err != nil {
+		return true, err
+	}
+	if err := admin.RequireWriteAccess("restoring a gaming session"); err != nil {
+		return true, err
+	}
+	if s.Detached {
+		log.Printf("[SysCleaner] Restoring settings from the gaming session started %s",
+			s.Started.Format("2006-01-02 15:04"))
+	} else {
+		log.Printf("[SysCleaner] Restoring settings from a gaming session started %s that did not end",
+			s.Started.Format("2006-01-02 15:04"))
+	}
+	s.restore()
+	removeSession()
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package gaming
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestStaleSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gaming-session.json")
+	origPath := sessionPath
+	defer func() { sessionPath = origPath }()
+	sessionPath = func() string { return path }
+
+	if StaleSession() {
+		t.Fatal("no session file should mean no stale session")
+	}
+
+	s := newSession()
+	s.StoppedServices = []string{"WSearch"}
+	s.Processes[1234] = sessionProcess{Name: "cs2.exe", Created: 1, Priority: 0x20, Affinity: 0xF}
+	s.save()
+	if StaleSession() {
+		t.Error("a session owned by this running process is not stale")
+	}
+
+	loaded, err := loadSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.StoppedServices) != 1 || loaded.Processes[1234].Affinity != 0xF {
+		t.Errorf("session not saved intact: %+v", loaded)
+	}
+
+	// The same PID started at another time is a different process.
+	s.OwnerCreated++
+	s.save()
+	if !StaleSession() {
+		t.Error("a session whose owner has exited should be stale")
+	}
+
+	// A session its owner detached on a normal exit outlives it.
+	s.Detached = true
+	s.save()
+	if StaleSession() {
+		t.Error("a detached session is not stale")
+	}
+	if found, err := RecoverSession(); found || err != nil {
+		t.Errorf("RecoverSession = %v, %v; a detached session is left for Disable", found, err)
+	}
+	if _, err := loadSession(); err != nil {
+		t.Errorf("the detached session should be kept: %v", err)
+	}
+}
+
+func TestValidPriorityClass(t *testing.T) {
+	if !validPriorityClass(0x8000) || validPriorityClass(0) || validPriorityClass(0x100) {
+		t.Error("only the classes gaming mode sets are valid; realtime is not")
+	}
+}
+
+func TestRunWatchdog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gaming-session.json")
+	origPath := sessionPath
+	defer func() { sessionPath = origPath }()
+	sessionPath = func() string { return path }
+
+	if RunWatchdog([]string{"--enable"}) {
+		t.Error("other arguments should not start the watchdog")
+	}
+	// With no session to watch the watchdog exits at once.
+	if !RunWatchdog([]string{WatchdogArg, "1234"}) {
+		t.Error("the watchdog argument should be handled")
+	}
+
+	// A session its live owner detached is left on: the watchdog exits
+	// without restoring it.
+	s := newSession()
+	s.Detached = true
+	s.save()
+	if !RunWatchdog([]string{WatchdogArg, strconv.Itoa(os.Getpid())}) {
+		t.Error("the watchdog argument should be handled")
+	}
+	if _, err := loadSession(); err != nil {
+		t.Errorf("the watchdog should leave a detached session: %v", err)
+	}
+}
+
+func TestStillSuspended(t *testing.T) {
+	self := int32(os.Getpid())
+	procs := []suspendedProcess{
+		{PID: uint32(self), Created: processCreated(self)},
+		// The same PID started at another time is a different process.
+		{PID: uint32(self), Created: processCreated(self) + 1},
+	}
+	if got := stillSuspended(procs); len(got) != 1 || got[0] != uint32(self) {
+		t.Errorf("stillSuspended = %v, want only the process with a matching start time", got)
+	}
+}
@@ -0,0 +1,439 @@
+package gaming
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"syscleaner/pkg/admin"
+)
+
+// ShaderCacheVendors are the keys a game override's ShaderCaches may use:
+// the GPU vendor whose driver writes the cache, "directx" for the cache
+// Windows keeps for every vendor, and "game" for a cache the game keeps
+// itself.
+var ShaderCacheVendors = []string{"nvidia", "amd", "intel", "directx", "game"}
+
+// ShaderCache is one shader cache folder declared by a game override.
+type ShaderCache struct {
+	Vendor string
+	Path   string
+}
+
+// envRef matches a %VARIABLE% reference in a shader cache path.
+var envRef = regexp.MustCompile(`%([A-Za-z0-9_()]+)%`)
+
+func validShaderCacheVendor(vendor string) bool {
+	for _, v := range ShaderCacheVendors {
+		if v == vendor {
+			return true
+		}
+	}
+	return false
+}
+
+// validateShaderCaches reports the first unknown vendor or empty path.
+func validateShaderCaches(caches map[string][]string) error {
+	for vendor, paths := range caches {
+		if !validShaderCacheVendor(vendor) {
+			return fmt.Errorf("unknown shader cache vendor %q (valid: %s)", vendor, strings.Join(ShaderCacheVendors, ", "))
+		}
+		for _, p := range paths {
+			if strings.TrimSpace(p) == "" {
+				return fmt.Errorf("empty %s shader cache path", vendor)
+			}
+		}
+	}
+	return nil
+}
+
+// ShaderCachesFor returns the shader cache folders game's override declares,
+// limited to vendor unless it is empty. Environment variables written as
+// %LOCALAPPDATA% are expanded, and relative paths are taken to be inside
+// the game's install folder.
+func ShaderCachesFor(game, vendor string) ([]ShaderCache, error) {
+	if vendor != "" && !validShaderCacheVendor(vendor) {
+		return nil, fmt.Errorf("unknown shader cache vendor %q (valid: %s)", vendor, strings.Join(ShaderCacheVendors, ", "))
+	}
+	o, ok := OverrideFor(game)
+	if !ok || len(o.ShaderCaches) == 0 {
+		return nil, fmt.Errorf("no shader caches are declared for %s", game)
+	}
+	installDir := ""
+	if g, ok := installedGame(game); ok {
+		installDir = g.InstallDir
+	}
+
+	var caches []ShaderCache
+	for _, v := range ShaderCacheVendors {
+		if vendor != "" && v != vendor {
+			continue
+		}
+		for _, p := range o.ShaderCaches[v] {
+			path, err := expandShaderCachePath(p, installDir)
+			if err != nil {
+				return nil, fmt.Errorf("%s shader cache: %w", v, err)
+			}
+			caches = append(caches, ShaderCache{Vendor: v, Path: path})
+		}
+	}
+	if len(caches) == 0 {
+		return nil, fmt.Errorf("no %s shader caches are declared for %s", vendor, game)
+	}
+	return caches, nil
+}
+
+// installedGame returns the installed game named game or owning the
+// executable game.
+func installedGame(game string) (InstalledGame, bool) {
+	if g, ok := InstalledGameByExe(game); ok {
+		return g, true
+	}
+	for _, g := range InstalledGames() {
+		if strings.EqualFold(g.Name, game) {
+			return g, true
+		}
+	}
+	return InstalledGame{}, false
+}
+
+// expandShaderCachePath expands environment variables in p and resolves it
+// against installDir. Clearing the cache deletes everything in the folder,
+// so the result must be inside the game's install folder or a folder GPU
+// drivers keep shader caches in (see shaderCacheRoots); the user's profile,
+// the Windows folder and the folders holding either are refused outright.
+func expandShaderCachePath(p, installDir string) (string, error) {
+	expanded := envRef.ReplaceAllStringFunc(p, func(ref string) string {
+		if v := os.Getenv(strings.Trim(ref, "%")); v != "" {
+			return v
+		}
+		return ref
+	})
+	if strings.Contains(expanded, "%") {
+		return "", fmt.Errorf("%s: unknown environment variable", p)
+	}
+	if !filepath.IsAbs(expanded) {
+		if installDir == "" {
+			return "", fmt.Errorf("%s is relative, but the game's install folder is not known", p)
+		}
+		expanded = filepath.Join(installDir, expanded)
+	}
+	expanded = filepath.Clean(expanded)
+	if filepath.Dir(expanded) == expanded {
+		return "", fmt.Errorf("%s is a drive root", p)
+	}
+
+	real := realPath(expanded)
+	for _, dir := range protectedDirs() {
+		if pathWithin(dir, real) {
+			return "", fmt.Errorf("%s is or holds the system or user folder %s", p, dir)
+		}
+	}
+	for _, v := range []string{"WINDIR", "SystemRoot"} {
+		if dir := os.Getenv(v); dir != "" && pathWithin(real, realPath(dir)) {
+			return "", fmt.Errorf("%s is inside the Windows folder", p)
+		}
+	}
+	if installDir != "" {
+		if dir := realPath(installDir); pathWithin(real, dir) && !pathWithin(dir, real) {
+			return expanded, nil
+		}
+	}
+	for _, root := range shaderCacheRoots() {
+		if pathWithin(real, realPath(root)) {
+			return expanded, nil
+		}
+	}
+	return "", fmt.Errorf("%s is neither inside the game's install folder nor in a known shader cache folder", p)
+}
+
+// shaderCacheRoots returns the folders the GPU drivers and DirectX keep
+// their shader caches in. It is a variable so tests can use their own.
+var shaderCacheRoots = func() []string {
+	var roots []string
+	if local := os.Getenv("LOCALAPPDATA"); local != "" {
+		low := filepath.Join(filepath.Dir(local), "LocalLow")
+		roots = append(roots,
+			filepath.Join(local, "NVIDIA"), filepath.Join(low, "NVIDIA"),
+			filepath.Join(local, "AMD"), filepath.Join(low, "AMD"),
+			filepath.Join(local, "Intel"), filepath.Join(low, "Intel"),
+			filepath.Join(local, "D3DSCache"))
+	}
+	if data := os.Getenv("ProgramData"); data != "" {
+		roots = append(roots, filepath.Join(data, "NVIDIA Corporation", "NV_Cache"))
+	}
+	return roots
+}
+
+// protectedDirs returns the folders a shader cache may never be or hold:
+// the user's profile and its AppData folders, and the Windows, Program
+// Files and ProgramData folders.
+func protectedDirs() []string {
+	var dirs []string
+	for _, v := range []string{"USERPROFILE", "APPDATA", "LOCALAPPDATA", "PUBLIC",
+		"WINDIR", "SystemRoot", "ProgramFiles", "ProgramFiles(x86)", "ProgramData"} {
+		if dir := os.Getenv(v); dir != "" {
+			dirs = append(dirs, realPath(dir))
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, realPath(home))
+	}
+	return dirs
+}
+
+// realPath returns path with symbolic links and junctions resolved, or
+// just cleaned if it does not exist.
+func realPath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return filepath.Clean(path)
+}
+
+// pathWithin reports whether path is dir or inside it, ignoring case as
+// Windows does.
+func pathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(strings.ToLower(dir), strings.ToLower(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// shaderCacheBackupDir returns where the backup of cache is kept:
+// <user config dir>/SysCleaner/shader-cache/<game>/<vendor>/<folder name>.
+func shaderCacheBackupDir(game string, cache ShaderCache) string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "SysCleaner", "shader-cache", backupName(canonicalGame(game)), cache.Vendor, backupName(filepath.Base(cache.Path)))
+}
+
+// canonicalGame returns the name game is listed under, so a backup made
+// for an executable is found again by game name and the other way round.
+func canonicalGame(game string) string {
+	if p := GetGameProfile(game); p != nil {
+		return p.Name
+	}
+	if p := GetGameProfileByExe(game); p != nil {
+		return p.Name
+	}
+	if g, ok := installedGame(game); ok {
+		return g.Name
+	}
+	return game
+}
+
+// backupName makes name safe to use as a folder name.
+func backupName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < 32 {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// HasShaderCacheBackup reports whether a backup of cache exists for game.
+func HasShaderCacheBackup(game string, cache ShaderCache) bool {
+	info, err := os.Stat(shaderCacheBackupDir(game, cache))
+	return err == nil && info.IsDir()
+}
+
+// BackupShaderCaches copies game's shader cache folders, limited to vendor
+// unless it is empty, replacing any earlier backup. Missing folders are
+// skipped. It returns the bytes copied.
+func BackupShaderCaches(game, vendor string) (int64, error) {
+	if err := admin.RequireWriteAccess("backing up the shader caches of " + game); err != nil {
+		return 0, err
+	}
+	caches, err := ShaderCachesFor(game, vendor)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	var errs []error
+	for _, c := range caches {
+		if info, err := os.Stat(c.Path); err != nil || !info.IsDir() {
+			log.Printf("[SysCleaner] Shader cache %s not found, skipping backup", c.Path)
+			continue
+		}
+		dst := shaderCacheBackupDir(game, c)
+		if err := os.RemoveAll(dst); err != nil {
+			errs = append(errs, fmt.Errorf("removing old backup of %s: %w", c.Path, err))
+			continue
+		}
+		n, err := copyTree(c.Path, dst)
+		total += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("backing up %s: %w", c.Path, err))
+			continue
+		}
+		log.Printf("[SysCleaner] Backed up %s shader cache %s for %s", c.Vendor, c.Path, game)
+	}
+	return total, errors.Join(errs...)
+}
+
+// RestoreShaderCaches replaces game's shader cache folders, limited to
+// vendor unless it is empty, with their backups. Folders without a backup
+// are left alone. It returns the bytes restored.
+func RestoreShaderCaches(game, vendor string) (int64, error) {
+	if err := admin.RequireWriteAccess("restoring the shader caches of " + game); err != nil {
+		return 0, err
+	}
+	caches, err := ShaderCachesFor(game, vendor)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	var errs []error
+	restored := 0
+	for _, c := range caches {
+		if !HasShaderCacheBackup(game, c) {
+			continue
+		}
+		if _, err := clearDir(c.Path); err != nil {
+			errs = append(errs, fmt.Errorf("clearing %s: %w", c.Path, err))
+			continue
+		}
+		n, err := copyTree(shaderCacheBackupDir(game, c), c.Path)
+		total += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", c.Path, err))
+			continue
+		}
+		restored++
+		log.Printf("[SysCleaner] Restored %s shader cache %s for %s", c.Vendor, c.Path, game)
+	}
+	if restored == 0 && len(errs) == 0 {
+		return 0, fmt.Errorf("no shader cache backup for %s", game)
+	}
+	return total, errors.Join(errs...)
+}
+
+// ClearShaderCaches deletes the contents of game's shader cache folders,
+// limited to vendor unless it is empty, so the driver or game rebuilds
+// them. It returns the bytes freed.
+func ClearShaderCaches(game, vendor string) (int64, error) {
+	if err := admin.RequireWriteAccess("clearing the shader caches of " + game); err != nil {
+		return 0, err
+	}
+	caches, err := ShaderCachesFor(game, vendor)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	var errs []error
+	for _, c := range caches {
+		n, err := clearDir(c.Path)
+		total += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("clearing %s: %w", c.Path, err))
+			continue
+		}
+		log.Printf("[SysCleaner] Cleared %s shader cache %s for %s", c.Vendor, c.Path, game)
+	}
+	return total, errors.Join(errs...)
+}
+
+// clearDir deletes everything inside dir, keeping dir itself, and returns
+// the size of the files deleted. A missing dir is not an error.
+func clearDir(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var freed int64
+	var errs []error
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		size := treeSize(path)
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		freed += size
+	}
+	return freed, errors.Join(errs...)
+}
+
+func treeSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// copyTree copies the folder src to dst, creating dst, and returns the
+// bytes copied. Symbolic links are skipped.
+func copyTree(src, dst string) (int64, error) {
+	var total int64
+	var paths []string
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return total, err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := os.Lstat(path)
+		if err != nil {
+			return total, err
+		}
+		switch {
+		case info.IsDir():
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return total, err
+			}
+		case info.Mode().IsRegular():
+			n, err := copyFile(path, target)
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
@@ -0,0 +1,113 @@
+package gaming
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"syscleaner/pkg/admin"
+)
+
+// fakeShaderCacheRoots makes dir the only known shader cache folder.
+func fakeShaderCacheRoots(t *testing.T, dir string) {
+	t.Helper()
+	orig := shaderCacheRoots
+	t.Cleanup(func() { shaderCacheRoots = orig })
+	shaderCacheRoots = func() []string { return []string{dir} }
+}
+
+func TestShaderCaches_BackupClearRestore(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SHADERTEST", t.TempDir())
+	fakeShaderCacheRoots(t, filepath.Join(os.Getenv("SHADERTEST"), "NVIDIA"))
+	nv := filepath.Join(os.Getenv("SHADERTEST"), "NVIDIA", "DXCache")
+	if err := os.MkdirAll(filepath.Join(nv, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(nv, "a.bin"), []byte("abcd"), 0644)
+	os.WriteFile(filepath.Join(nv, "sub", "b.bin"), []byte("ef"), 0644)
+
+	SetGameOverrides(map[string]GameOverride{
+		"MyGame.exe": {ShaderCaches: map[string][]string{"nvidia": {`%SHADERTEST%/NVIDIA/DXCache`}}},
+	})
+	t.Cleanup(func() { SetGameOverrides(nil) })
+
+	caches, err := ShaderCachesFor("mygame.exe", "")
+	if err != nil || len(caches) != 1 || caches[0].Path != nv {
+		t.Fatalf("ShaderCachesFor = %v, %v; want %s", caches, err, nv)
+	}
+	if _, err := ShaderCachesFor("mygame.exe", "amd"); err == nil {
+		t.Error("expected an error for a vendor with no caches")
+	}
+	if _, err := RestoreShaderCaches("mygame.exe", ""); err == nil {
+		t.Error("restoring without a backup should fail")
+	}
+
+	admin.SetAuditMode(true)
+	_, err = ClearShaderCaches("mygame.exe", "")
+	admin.SetAuditMode(false)
+	if entries, _ := os.ReadDir(nv); !errors.Is(err, admin.ErrAuditMode) || len(entries) != 2 {
+		t.Fatalf("ClearShaderCaches in audit mode = %v, left %d entries; want ErrAuditMode and nothing deleted", err, len(entries))
+	}
+
+	if n, err := BackupShaderCaches("mygame.exe", "nvidia"); err != nil || n != 6 {
+		t.Fatalf("BackupShaderCaches = %d, %v; want 6 bytes", n, err)
+	}
+	if n, err := ClearShaderCaches("mygame.exe", ""); err != nil || n != 6 {
+		t.Fatalf("ClearShaderCaches = %d, %v; want 6 bytes", n, err)
+	}
+	if entries, _ := os.ReadDir(nv); len(entries) != 0 {
+		t.Fatalf("cache not cleared: %v", entries)
+	}
+	if _, err := RestoreShaderCaches("mygame.exe", ""); err != nil {
+		t.Fatalf("RestoreShaderCaches: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(nv, "sub", "b.bin")); err != nil || string(data) != "ef" {
+		t.Errorf("restored file = %q, %v", data, err)
+	}
+}
+
+func TestShaderCachePaths(t *testing.T) {
+	install := t.TempDir()
+	if got, err := expandShaderCachePath("ShaderCache", install); err != nil || got != filepath.Join(install, "ShaderCache") {
+		t.Errorf("relative path = %q, %v", got, err)
+	}
+	for _, p := range []string{"ShaderCache", "%NO_SUCH_VARIABLE%/cache", "/"} {
+		if _, err := expandShaderCachePath(p, ""); err == nil {
+			t.Errorf("%q: expected an error", p)
+		}
+	}
+
+	cacheRoot := t.TempDir()
+	fakeShaderCacheRoots(t, cacheRoot)
+	home := t.TempDir()
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("WINDIR", filepath.Join(home, "Windows"))
+	if got, err := expandShaderCachePath(filepath.Join(cacheRoot, "DxCache"), install); err != nil {
+		t.Errorf("known cache folder = %q, %v", got, err)
+	}
+	for _, p := range []string{
+		filepath.Join("..", ".."), // escapes the install folder
+		".",                       // the install folder itself
+		home,                      // the user profile
+		filepath.Dir(home),        // holds the user profile
+		filepath.Join(home, "Windows", "ShaderCache"), // inside the Windows folder
+		filepath.Join(home, "Documents"),              // not a cache folder
+	} {
+		if _, err := expandShaderCachePath(p, install); err == nil {
+			t.Errorf("%q: expected the path to be refused", p)
+		}
+	}
+	if err := (GameOverride{ShaderCaches: map[string][]string{"3dfx": {"x"}}}).Validate(); err == nil {
+		t.Error("an unknown vendor should be rejected")
+	}
+
+	p := NewSharedProfile("x", GameOverride{ShaderCaches: map[string][]string{
+		"amd":  {`%LOCALAPPDATA%\AMD\DxCache`},
+		"game": {`%USERPROFILE%\Documents`},
+	}})
+	if risks := p.Risks(); len(risks) != 1 || risks[0].Risk != RiskHigh {
+		t.Errorf("risks = %v, want one high risk for the Documents folder", risks)
+	}
+}
@@ -0,0 +1,124 @@
+package gaming
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SharedProfileFormat marks a file as a shared game profile.
+const SharedProfileFormat = "syscleaner-game-profile"
+
+// sharedProfileVersion is the layout version written by NewSharedProfile.
+const sharedProfileVersion = 1
+
+// sharedProfileSchema is the JSON Schema for shared game profiles, for
+// tools that build or check them outside SysCleaner.
+//
+//go:embed gameprofile.schema.json
+var sharedProfileSchema []byte
+
+// SharedProfileSchema returns the JSON Schema describing SharedProfile
+// files.
+func SharedProfileSchema() []byte {
+	return append([]byte(nil), sharedProfileSchema...)
+}
+
+// SharedProfile is a game's tuned settings in a form players can trade:
+// the game's override (priority, affinity, services to stop and keep,
+// pre-launch cleans and tools) plus who made it and why.
+type SharedProfile struct {
+	Format   string       `json:"format"`
+	Version  int          `json:"version"`
+	Game     string       `json:"game"`
+	Author   string       `json:"author,omitempty"`
+	Notes    string       `json:"notes,omitempty"`
+	Settings GameOverride `json:"settings"`
+}
+
+// NewSharedProfile wraps a game's override for export.
+func NewSharedProfile(game string, o GameOverride) SharedProfile {
+	return SharedProfile{Format: SharedProfileFormat, Version: sharedProfileVersion, Game: game, Settings: o}
+}
+
+// ParseSharedProfile decodes and validates a shared game profile. Fields
+// the schema does not define are rejected rather than ignored, so a typo
+// in a hand-edited profile does not silently drop a setting.
+func ParseSharedProfile(data []byte) (SharedProfile, error) {
+	var p SharedProfile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return p, fmt.Errorf("parsing game profile: %w", err)
+	}
+	if p.Format != SharedProfileFormat {
+		return p, fmt.Errorf("not a SysCleaner game profile")
+	}
+	if p.Version < 1 || p.Version > sharedProfileVersion {
+		return p, fmt.Errorf("game profile version %d is not supported (newest: %d)", p.Version, sharedProfileVersion)
+	}
+	if strings.TrimSpace(p.Game) == "" {
+		return p, fmt.Errorf("game profile does not name a game")
+	}
+	if err := p.Settings.Validate(); err != nil {
+		return p, fmt.Errorf("game profile for %s: %w", p.Game, err)
+	}
+	return p, nil
+}
+
+// ProfileRisk is something an imported game profile would do that could
+// break the game or the system, or run code.
+type ProfileRisk struct {
+	Risk   Risk
+	Reason string
+}
+
+func (r ProfileRisk) String() string {
+	return fmt.Sprintf("[%s] %s", r.Risk, r.Reason)
+}
+
+// Risks lists what applying p would do that needs the user's attention,
+// rated like extreme mode stop list entries: stopping anti-cheat, audio or
+// input services is critical and pre-launch tools are high risk, since
+// they run with administrator rights. So is a shader cache folder that
+// does not look like a cache, since clearing it deletes its contents.
+func (p SharedProfile) Risks() []ProfileRisk {
+	var risks []ProfileRisk
+	for _, svc := range p.Settings.StopServices {
+		if why, ok := criticalEntries[strings.ToLower(svc)]; ok {
+			risks = append(risks, ProfileRisk{RiskCritical, fmt.Sprintf("stops the %s service %s", why, svc)})
+			continue
+		}
+		for name, r := range defaultRisks {
+			if strings.EqualFold(name, svc) {
+				risks = append(risks, ProfileRisk{r, fmt.Sprintf("stops the service %s", svc)})
+			}
+		}
+	}
+	for _, a := range p.Settings.PreLaunch {
+		cmd := strings.TrimSpace(a.Command + " " + strings.Join(a.Args, " "))
+		risks = append(risks, ProfileRisk{RiskHigh, fmt.Sprintf("runs %s as administrator before the game", cmd)})
+	}
+	for _, vendor := range ShaderCacheVendors {
+		for _, path := range p.Settings.ShaderCaches[vendor] {
+			if !strings.Contains(strings.ToLower(path), "cache") {
+				risks = append(risks, ProfileRisk{RiskHigh, fmt.Sprintf("declares %s as a %s shader cache; clearing it deletes everything in it", path, vendor)})
+			}
+		}
+	}
+	return risks
+}
+
+// Blocking returns the risks that keep a profile from being imported:
+// critical ones always, and high ones unless allowHigh is set.
+func Blocking(risks []ProfileRisk, allowHigh bool) []ProfileRisk {
+	var out []ProfileRisk
+	for _, r := range risks {
+		if r.Risk == RiskCritical || (r.Risk == RiskHigh && !allowHigh) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package gaming
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"syscleaner/pkg/launcher"
+)
+
+func TestParseSharedProfile(t *testing.T) {
+	good := `{"format": "syscleaner-game-profile", "version": 1, "game": "cs2.exe",
+		"settings": {"priority": "above normal", "stop_services": ["Spooler"]}}`
+	p, err := ParseSharedProfile([]byte(good))
+	if err != nil {
+		t.Fatalf("ParseSharedProfile: %v", err)
+	}
+	if p.Game != "cs2.exe" || p.Settings.Priority != "above normal" {
+		t.Errorf("got %+v", p)
+	}
+
+	for name, data := range map[string]string{
+		"format":   `{"format": "other", "version": 1, "game": "x", "settings": {}}`,
+		"version":  `{"format": "syscleaner-game-profile", "version": 2, "game": "x", "settings": {}}`,
+		"no game":  `{"format": "syscleaner-game-profile", "version": 1, "settings": {}}`,
+		"unknown":  `{"format": "syscleaner-game-profile", "version": 1, "game": "x", "settings": {"priorty": "high"}}`,
+		"priority": `{"format": "syscleaner-game-profile", "version": 1, "game": "x", "settings": {"priority": "realtime"}}`,
+	} {
+		if _, err := ParseSharedProfile([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSharedProfile_Risks(t *testing.T) {
+	p := NewSharedProfile("Valorant", GameOverride{
+		StopServices: []string{"vgc", "Spooler", "DiagTrack"},
+		PreLaunch:    []launcher.Action{{Command: "tool.exe", Args: []string{"/fast"}}},
+	})
+	risks := p.Risks()
+	got := make(map[Risk]int)
+	for _, r := range risks {
+		got[r.Risk]++
+	}
+	if got[RiskCritical] != 1 || got[RiskMedium] != 1 || got[RiskHigh] != 1 || len(risks) != 3 {
+		t.Errorf("risks = %v", risks)
+	}
+	if n := len(Blocking(risks, true)); n != 1 {
+		t.Errorf("Blocking(allowHigh) = %d risks, want the critical one", n)
+	}
+	if n := len(Blocking(risks, false)); n != 2 {
+		t.Errorf("Blocking = %d risks, want 2", n)
+	}
+}
+
+// TestSharedProfileSchema checks that the published schema describes the
+// same fields the decoder accepts.
+func TestSharedProfileSchema(t *testing.T) {
+	var schema struct {
+		Properties map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"properties"`
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(SharedProfileSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	check := func(name string, props map[string]json.RawMessage, typ reflect.Type) {
+		var fields, keys []string
+		for i := 0; i < typ.NumField(); i++ {
+			fields = append(fields, strings.Split(typ.Field(i).Tag.Get("json"), ",")[0])
+		}
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(fields)
+		sort.Strings(keys)
+		if !reflect.DeepEqual(fields, keys) {
+			t.Errorf("%s: schema properties %v, struct fields %v", name, keys, fields)
+		}
+	}
+	top := make(map[string]json.RawMessage)
+	for k := range schema.Properties {
+		top[k] = nil
+	}
+	check("profile", top, reflect.TypeOf(SharedProfile{}))
+	check("settings", schema.Properties["settings"].Properties, reflect.TypeOf(GameOverride{}))
+	check("action", schema.Defs["action"].Properties, reflect.TypeOf(launcher.Action{}))
+}
@@ -0,0 +1,196 @@
+package gaming
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Risk rates how likely stopping an extreme mode entry is to break
+// something the user needs while gaming.
+type Risk string
+
+const (
+	RiskLow    Risk = "low"
+	RiskMedium Risk = "medium"
+	RiskHigh   Risk = "high"
+	// RiskCritical entries (audio, anti-cheat, input) are never stopped
+	// unless the entry sets Force.
+	RiskCritical Risk = "critical"
+)
+
+// Stop entry kinds.
+const (
+	StopService = "service"
+	StopProcess = "process"
+)
+
+// StopEntry is a service or background app extreme mode stops.
+type StopEntry struct {
+	Name string `json:"name"`
+	// Kind is "service" (the default) or "process" for an executable to
+	// close.
+	Kind string `json:"kind,omitempty"`
+	// Risk defaults to low. Entries SysCleaner knows to be critical are
+	// treated as critical whatever they are tagged.
+	Risk Risk `json:"risk,omitempty"`
+	// Force stops the entry even when it is critical.
+	Force bool   `json:"force,omitempty"`
+	Note  string `json:"note,omitempty"`
+}
+
+// IsProcess reports whether e is an app to close rather than a service.
+func (e StopEntry) IsProcess() bool {
+	return strings.EqualFold(e.Kind, StopProcess)
+}
+
+// EffectiveRisk returns e's risk, raised to critical for entries SysCleaner
+// knows to be critical.
+func (e StopEntry) EffectiveRisk() Risk {
+	if _, ok := criticalEntries[strings.ToLower(e.Name)]; ok {
+		return RiskCritical
+	}
+	if e.Risk == "" {
+		return RiskLow
+	}
+	return Risk(strings.ToLower(string(e.Risk)))
+}
+
+// Validate reports the first invalid setting in e.
+func (e StopEntry) Validate() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return fmt.Errorf("stop list entry has no name")
+	}
+	switch strings.ToLower(e.Kind) {
+	case "", StopService, StopProcess:
+	default:
+		return fmt.Errorf("stop list entry %s: unknown kind %q (valid: service, process)", e.Name, e.Kind)
+	}
+	switch Risk(strings.ToLower(string(e.Risk))) {
+	case "", RiskLow, RiskMedium, RiskHigh, RiskCritical:
+	default:
+		return fmt.Errorf("stop list entry %s: unknown risk %q (valid: low, medium, high, critical)", e.Name, e.Risk)
+	}
+	return nil
+}
+
+// criticalEntries are services and processes that break audio, anti-cheat
+// or input when stopped, with the reason.
+var criticalEntries = map[string]string{
+	"audiosrv":             "audio",
+	"audioendpointbuilder": "audio",
+	"vgc":                  "anti-cheat",
+	"vgk":                  "anti-cheat",
+	"easyanticheat":        "anti-cheat",
+	"easyanticheat_eos":    "anti-cheat",
+	"beservice":            "anti-cheat",
+	"pnkbstra":             "anti-cheat",
+	"pnkbstrb":             "anti-cheat",
+	"faceit":               "anti-cheat",
+	"hidserv":              "input",
+	"tabletinputservice":   "input",
+	"ctfmon.exe":           "input",
+	"textinputhost.exe":    "input",
+}
+
+// defaultRisks rates the built-in entries that are not low risk.
+var defaultRisks = map[string]Risk{
+	"Spooler":           RiskMedium,
+	"SCardSvr":          RiskMedium,
+	"ScDeviceEnum":      RiskMedium,
+	"stisvc":            RiskMedium,
+	"FrameServer":       RiskMedium, // webcams used for streaming
+	"iphlpsvc":          RiskMedium,
+	"WpnService":        RiskMedium,
+	"WpnUserService":    RiskMedium,
+	"XblAuthManager":    RiskMedium, // Game Pass sign-in
+	"XboxGipSvc":        RiskMedium, // Xbox controllers
+	"XboxNetApiSvc":     RiskMedium,
+	"TermService":       RiskHigh,
+	"SessionEnv":        RiskHigh,
+	"IKEEXT":            RiskHigh, // VPNs
+	"Discord.exe":       RiskMedium,
+	"DiscordPTB.exe":    RiskMedium,
+	"DiscordCanary.exe": RiskMedium,
+	"Teams.exe":         RiskMedium,
+	"ms-teams.exe":      RiskMedium,
+	"msedge.exe":        RiskMedium,
+}
+
+// DefaultStopList returns the built-in extreme mode stop list: the
+// services and apps SysCleaner stops when no list is configured.
+func DefaultStopList() []StopEntry {
+	var list []StopEntry
+	for _, name := range extremeServicesToStop {
+		list = append(list, StopEntry{Name: name, Kind: StopService, Risk: defaultRisk(name)})
+	}
+	for _, name := range processesToKill {
+		list = append(list, StopEntry{Name: name, Kind: StopProcess, Risk: defaultRisk(name)})
+	}
+	return list
+}
+
+func defaultRisk(name string) Risk {
+	if _, ok := criticalEntries[strings.ToLower(name)]; ok {
+		return RiskCritical
+	}
+	if r, ok := defaultRisks[name]; ok {
+		return r
+	}
+	return RiskLow
+}
+
+var (
+	stopListMu sync.Mutex
+	stopList   []StopEntry
+)
+
+// SetExtremeStopList replaces the extreme mode stop list. nil restores
+// DefaultStopList.
+func SetExtremeStopList(list []StopEntry) {
+	stopListMu.Lock()
+	defer stopListMu.Unlock()
+	stopList = append([]StopEntry(nil), list...)
+}
+
+// ExtremeStopList returns the configured stop list, or DefaultStopList.
+func ExtremeStopList() []StopEntry {
+	stopListMu.Lock()
+	defer stopListMu.Unlock()
+	if stopList == nil {
+		return DefaultStopList()
+	}
+	return append([]StopEntry(nil), stopList...)
+}
+
+// stopPlan splits list into the services and processes extreme mode may
+// stop, leaving out critical entries that are not forced.
+func stopPlan(list []StopEntry) (services, processes []string, refused []StopEntry) {
+	for _, e := range list {
+		if e.EffectiveRisk() == RiskCritical && !e.Force {
+			refused = append(refused, e)
+			continue
+		}
+		if e.IsProcess() {
+			processes = append(processes, e.Name)
+		} else {
+			services = append(services, e.Name)
+		}
+	}
+	return services, processes, refused
+}
+
+// extremeStopPlan is stopPlan for the current stop list, logging each
+// refused entry.
+func extremeStopPlan() (services, processes []string) {
+	services, processes, refused := stopPlan(ExtremeStopList())
+	for _, e := range refused {
+		reason := criticalEntries[strings.ToLower(e.Name)]
+		if reason == "" {
+			reason = "tagged critical"
+		}
+		log.Printf("[SysCleaner] Not stopping %s (%s); set force on its stop list entry to stop it anyway", e.Name, reason)
+	}
+	return services, processes
+}
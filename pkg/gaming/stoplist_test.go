@@ -0,0 +1,54 @@
+package gaming
+
+import "testing"
+
+func TestStopPlan_RefusesCritical(t *testing.T) {
+	list := []StopEntry{
+		{Name: "WSearch"},
+		{Name: "Spotify.exe", Kind: StopProcess},
+		{Name: "Audiosrv", Risk: RiskLow}, // known critical whatever its tag
+		{Name: "MyService", Risk: RiskCritical},
+		{Name: "vgc", Force: true},
+	}
+	services, processes, refused := stopPlan(list)
+	if len(services) != 2 || services[0] != "WSearch" || services[1] != "vgc" {
+		t.Errorf("services = %v, want WSearch and the forced vgc", services)
+	}
+	if len(processes) != 1 || processes[0] != "Spotify.exe" {
+		t.Errorf("processes = %v", processes)
+	}
+	if len(refused) != 2 {
+		t.Errorf("refused = %v, want Audiosrv and MyService", refused)
+	}
+}
+
+func TestDefaultStopList(t *testing.T) {
+	var input bool
+	for _, e := range DefaultStopList() {
+		if err := e.Validate(); err != nil {
+			t.Error(err)
+		}
+		if e.Name == "TabletInputService" {
+			input = e.Risk == RiskCritical
+		}
+	}
+	if !input {
+		t.Error("TabletInputService should be tagged critical (input)")
+	}
+	for _, name := range GetProcessesToKill() {
+		if name == "" {
+			t.Error("empty process name in the default plan")
+		}
+	}
+}
+
+func TestStopEntry_Validate(t *testing.T) {
+	for _, e := range []StopEntry{{}, {Name: "x", Kind: "driver"}, {Name: "x", Risk: "extreme"}} {
+		if e.Validate() == nil {
+			t.Errorf("%+v should be invalid", e)
+		}
+	}
+	if err := (StopEntry{Name: "Spooler", Kind: "Service", Risk: "Medium"}).Validate(); err != nil {
+		t.Errorf("kinds and risks are case-insensitive: %v", err)
+	}
+}
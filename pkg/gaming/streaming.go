@@ -0,0 +1,133 @@
+package gaming
+
+import (
+	"log"
+	"runtime"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// StreamingApps are the streaming and recording apps streamer mode boosts
+// when a profile does not list its own.
+var StreamingApps = []string{
+	"obs64.exe", "obs32.exe", "obs.exe",
+	"Streamlabs OBS.exe", "Streamlabs Desktop.exe",
+	"XSplit.Core.exe", "XSplit.Broadcaster.exe",
+	"TwitchStudio.exe", "vMix64.exe",
+}
+
+// CaptureProcesses are capture, camera, voice and audio routing processes
+// a stream depends on. Streamer mode never suspends or closes them.
+var CaptureProcesses = []string{
+	// Streaming app helpers
+	"obs-ffmpeg-mux.exe", "obs-browser-page.exe",
+	// GPU capture and effects
+	"nvcontainer.exe", "NVIDIA Share.exe", "NVIDIA Broadcast.exe", "RadeonSoftware.exe",
+	// Audio
+	"audiodg.exe", "voicemeeter.exe", "voicemeeterpro.exe", "voicemeeter8.exe", "voicemeeter8x64.exe",
+	"WaveLink.exe",
+	// Cameras, capture cards and controllers
+	"Camera Hub.exe", "StreamDeck.exe", "4KCaptureUtility.exe",
+	// Voice chat the stream carries
+	"Discord.exe", "DiscordPTB.exe", "DiscordCanary.exe",
+}
+
+// StreamingConfig turns on streamer mode: streaming apps run at high
+// priority alongside the game, optionally pinned to their own cores, and
+// nothing the stream depends on is suspended or closed. Streaming apps are
+// boosted by the same monitor as games, so only with AutoDetectGames.
+type StreamingConfig struct {
+	// Apps are the streaming apps to boost. Empty means StreamingApps.
+	Apps []string
+
+	// EncoderCores pins the streaming apps, and with them their encoder
+	// threads, to a set of cores named as for AffinityMask, e.g. "ccd1"
+	// or "6-7". Empty leaves their affinity alone.
+	EncoderCores string
+
+	// Protect lists further processes never suspended or closed, on top
+	// of the streaming apps and CaptureProcesses.
+	Protect []string
+}
+
+// streaming is the running session's streamer mode settings, nil when it
+// is off; guarded by mu.
+var streaming *StreamingConfig
+
+func (s *StreamingConfig) apps() []string {
+	if len(s.Apps) > 0 {
+		return s.Apps
+	}
+	return StreamingApps
+}
+
+// isApp reports whether name is one of the streaming apps s boosts.
+func (s *StreamingConfig) isApp(name string) bool {
+	if s == nil {
+		return false
+	}
+	return containsFold(s.apps(), name)
+}
+
+// protects reports whether streamer mode keeps name running.
+func (s *StreamingConfig) protects(name string) bool {
+	if s == nil {
+		return false
+	}
+	return s.isApp(name) || containsFold(CaptureProcesses, name) || containsFold(s.Protect, name)
+}
+
+// protected returns every process name streamer mode keeps running.
+func (s *StreamingConfig) protected() []string {
+	if s == nil {
+		return nil
+	}
+	out := append(append([]string(nil), s.apps()...), CaptureProcesses...)
+	return append(out, s.Protect...)
+}
+
+func containsFold(list []string, name string) bool {
+	for _, n := range list {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamingApp reports whether name is a streaming app the running
+// session boosts.
+func streamingApp(name string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return streaming.isApp(name)
+}
+
+// boostStreamingApp raises a streaming app to high priority and pins it to
+// the encoder cores, recording the original values for Disable.
+func boostStreamingApp(p *process.Process) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !gamingModeEnabled || streaming == nil {
+		return
+	}
+	if _, exists := current.Processes[p.Pid]; exists {
+		return
+	}
+	name, _ := p.Name()
+	if guardProcessName("boosting", name) {
+		return
+	}
+	nice := recordSessionProcess(p, name)
+
+	if runtime.GOOS == "windows" {
+		log.Printf("[SysCleaner] Boosting priority for streaming app: %s (PID: %d)", name, p.Pid)
+		mask := uint64(0)
+		if streaming.EncoderCores != "" {
+			mask = resolveAffinity(name, streaming.EncoderCores)
+		}
+		tweakProcess(p.Pid, name, nice, highPriorityClass, mask)
+	}
+}
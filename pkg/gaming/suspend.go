@@ -0,0 +1,60 @@
+package gaming
+
+import (
+	"log"
+	"strings"
+)
+
+// BackgroundApps are heavyweight apps worth suspending while a game runs:
+// browsers, Electron apps and sync clients. They are suspended rather than
+// closed, so tabs and unsaved work survive and they pick up where they
+// left off once gaming mode ends.
+var BackgroundApps = []string{
+	// Browsers
+	"chrome.exe", "msedge.exe", "firefox.exe", "brave.exe", "opera.exe", "vivaldi.exe",
+	// Electron apps
+	"Teams.exe", "ms-teams.exe", "Slack.exe", "Code.exe", "Notion.exe", "WhatsApp.exe",
+	// Sync clients
+	"OneDrive.exe", "Dropbox.exe", "GoogleDriveFS.exe", "iCloudDrive.exe",
+}
+
+// neverSuspend are processes whose suspension would hang the desktop.
+var neverSuspend = map[string]bool{
+	"explorer.exe": true, "dwm.exe": true, "csrss.exe": true, "winlogon.exe": true,
+	"svchost.exe": true, "lsass.exe": true, "audiodg.exe": true,
+}
+
+// suspendList returns the executables to suspend for config: its
+// SuspendProcesses, plus BackgroundApps when SuspendBackgroundApps is set,
+// less anything whitelisted, a game, an anti-cheat, part of Windows
+// itself or, in streamer mode, something the stream depends on.
+func suspendList(config Config) []string {
+	names := config.SuspendProcesses
+	if config.SuspendBackgroundApps {
+		names = append(append([]string(nil), names...), BackgroundApps...)
+	}
+	skip := make(map[string]bool, len(config.Whitelist))
+	for _, name := range config.Whitelist {
+		skip[strings.ToLower(name)] = true
+	}
+
+	var list []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		switch {
+		case seen[lower]:
+		case skip[lower]:
+			log.Printf("[SysCleaner] Not suspending whitelisted process: %s", name)
+		case guardProcessName("suspending", name):
+		case config.Streaming.protects(name):
+			log.Printf("[SysCleaner] Not suspending %s (streamer mode)", name)
+		case neverSuspend[lower] || isGameProcess(name):
+			log.Printf("[SysCleaner] Not suspending %s", name)
+		default:
+			list = append(list, name)
+		}
+		seen[lower] = true
+	}
+	return list
+}
@@ -0,0 +1,54 @@
+package gaming
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuspendList(t *testing.T) {
+	got := suspendList(Config{
+		SuspendProcesses: []string{"wallpaper64.exe", "explorer.exe", "cs2.exe", "Wallpaper64.exe"},
+	})
+	if want := []string{"wallpaper64.exe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("suspendList = %v, want %v", got, want)
+	}
+
+	got = suspendList(Config{
+		SuspendBackgroundApps: true,
+		Whitelist:             []string{"CHROME.EXE"},
+	})
+	if len(got) != len(BackgroundApps)-1 {
+		t.Errorf("suspendList with background apps = %v, want all but chrome.exe", got)
+	}
+	for _, name := range got {
+		if name == "chrome.exe" {
+			t.Error("whitelisted chrome.exe would be suspended")
+		}
+	}
+}
+
+func TestSuspendList_Streaming(t *testing.T) {
+	cfg := Config{
+		SuspendProcesses:      []string{"obs64.exe", "WaveLink.exe", "MyCam.exe", "wallpaper64.exe"},
+		SuspendBackgroundApps: true,
+		Streaming:             &StreamingConfig{Protect: []string{"mycam.exe"}},
+	}
+	got := suspendList(cfg)
+	for _, name := range got {
+		if cfg.Streaming.protects(name) {
+			t.Errorf("streamer mode would suspend %s", name)
+		}
+	}
+	if len(got) != len(BackgroundApps)+1 {
+		t.Errorf("suspendList = %v, want wallpaper64.exe and the background apps", got)
+	}
+
+	custom := &StreamingConfig{Apps: []string{"MyEncoder.exe"}}
+	if !custom.isApp("myencoder.exe") || custom.isApp("obs64.exe") {
+		t.Error("custom streaming apps should replace the defaults")
+	}
+	var off *StreamingConfig
+	if off.isApp("obs64.exe") || off.protects("audiodg.exe") || off.protected() != nil {
+		t.Error("a nil StreamingConfig should protect nothing")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"golang.org/x/sys/windows/registry"
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/mgr"
+
+	"syscleaner/pkg/reglog"
 )
 
 // stopServiceNative stops a Windows service using the Service Control Manager API.
@@ -83,10 +85,13 @@ func startServiceNative(name string) error {
 //	windows.ABOVE_NORMAL_PRIORITY_CLASS = 0x00008000
 //	windows.HIGH_PRIORITY_CLASS         = 0x00000080
 //	windows.REALTIME_PRIORITY_CLASS     = 0x00000100
+//
+// Only PROCESS_SET_INFORMATION is requested: Xbox / Microsoft Store games
+// run in app containers whose security descriptor refuses
+// PROCESS_QUERY_INFORMATION even to administrators, but still allows
+// setting the priority class.
 func setProcessPriorityNative(pid uint32, priorityClass uint32) error {
-	handle, err := windows.OpenProcess(
-		windows.PROCESS_SET_INFORMATION|windows.PROCESS_QUERY_INFORMATION,
-		false, pid)
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_INFORMATION, false, pid)
 	if err != nil {
 		return fmt.Errorf("failed to open process %d: %w", pid, err)
 	}
@@ -103,13 +108,6 @@ func setProcessPriorityNative(pid uint32, priorityClass uint32) error {
 // instead of spawning reg.exe child processes.
 func setVisualEffectsNative(enable bool) {
 	// Set UserPreferencesMask in HKCU\Control Panel\Desktop
-	key, err := registry.OpenKey(registry.CURRENT_USER,
-		`Control Panel\Desktop`, registry.SET_VALUE)
-	if err != nil {
-		log.Printf("[SysCleaner] Failed to open Desktop registry key: %v", err)
-		return
-	}
-
 	var mask []byte
 	if enable {
 		// Default visual effects enabled
@@ -118,26 +116,19 @@ func setVisualEffectsNative(enable bool) {
 		// Minimal visual effects for performance
 		mask = []byte{0x90, 0x12, 0x03, 0x80, 0x10, 0x00, 0x00, 0x00}
 	}
-	if err := key.SetBinaryValue("UserPreferencesMask", mask); err != nil {
+	if err := reglog.SetBinaryValue(registry.CURRENT_USER,
+		`Control Panel\Desktop`, "UserPreferencesMask", mask); err != nil {
 		log.Printf("[SysCleaner] Failed to set UserPreferencesMask: %v", err)
 	}
-	key.Close()
 
 	// Set EnableTransparency in Themes\Personalize
-	themeKey, _, err := registry.CreateKey(registry.CURRENT_USER,
-		`SOFTWARE\Microsoft\Windows\CurrentVersion\Themes\Personalize`,
-		registry.SET_VALUE)
-	if err != nil {
-		log.Printf("[SysCleaner] Failed to open Themes registry key: %v", err)
-		return
-	}
-	defer themeKey.Close()
-
 	var transparencyVal uint32
 	if enable {
 		transparencyVal = 1
 	}
-	if err := themeKey.SetDWordValue("EnableTransparency", transparencyVal); err != nil {
+	if err := reglog.SetDWordValue(registry.CURRENT_USER,
+		`SOFTWARE\Microsoft\Windows\CurrentVersion\Themes\Personalize`,
+		"EnableTransparency", transparencyVal); err != nil {
 		log.Printf("[SysCleaner] Failed to set EnableTransparency: %v", err)
 	}
 }
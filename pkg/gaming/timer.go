@@ -0,0 +1,43 @@
+package gaming
+
+import (
+	"log"
+	"time"
+)
+
+// gamingTimerResolution is the timer resolution requested while gaming
+// mode is on. Windows rounds it to the finest the hardware supports,
+// usually 0.5 ms.
+const gamingTimerResolution = 500 * time.Microsecond
+
+// timerRequested is set while this process holds a timer resolution
+// request; guarded by mu.
+var timerRequested bool
+
+// requestTimerResolution asks for gamingTimerResolution. The request
+// belongs to this process and Windows drops it when the process exits,
+// so it lasts while SysCleaner keeps running (the GUI or --watch) and
+// needs no entry in the session file.
+func requestTimerResolution() {
+	if timerRequested {
+		return
+	}
+	actual, err := setTimerResolution(gamingTimerResolution, true)
+	if err != nil {
+		log.Printf("[SysCleaner] Could not raise the timer resolution: %v", err)
+		return
+	}
+	timerRequested = true
+	log.Printf("[SysCleaner] Timer resolution is now %.2f ms", float64(actual)/float64(time.Millisecond))
+}
+
+// releaseTimerResolution drops the request made by requestTimerResolution.
+func releaseTimerResolution() {
+	if !timerRequested {
+		return
+	}
+	timerRequested = false
+	if _, err := setTimerResolution(gamingTimerResolution, false); err != nil {
+		log.Printf("[SysCleaner] Could not release the timer resolution: %v", err)
+	}
+}
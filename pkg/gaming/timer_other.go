@@ -0,0 +1,13 @@
+//go:build !windows
+
+package gaming
+
+import (
+	"fmt"
+	"time"
+)
+
+// setTimerResolution is only available on Windows.
+var setTimerResolution = func(res time.Duration, set bool) (time.Duration, error) {
+	return 0, fmt.Errorf("timer resolution is only available on Windows")
+}
@@ -0,0 +1,26 @@
+package gaming
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerResolution_RequestAndRelease(t *testing.T) {
+	var calls []bool
+	orig := setTimerResolution
+	defer func() { setTimerResolution, timerRequested = orig, false }()
+	setTimerResolution = func(res time.Duration, set bool) (time.Duration, error) {
+		calls = append(calls, set)
+		return res, nil
+	}
+
+	releaseTimerResolution() // nothing requested yet
+	requestTimerResolution()
+	requestTimerResolution()
+	releaseTimerResolution()
+	releaseTimerResolution()
+
+	if len(calls) != 2 || !calls[0] || calls[1] {
+		t.Errorf("setTimerResolution calls = %v, want [true false]", calls)
+	}
+}
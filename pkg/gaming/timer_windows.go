@@ -0,0 +1,26 @@
+//go:build windows
+
+package gaming
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+var procNtSetTimerResolution = ntdll.NewProc("NtSetTimerResolution")
+
+// setTimerResolution makes or releases a timer resolution request through
+// NtSetTimerResolution and returns the resolution now in effect.
+var setTimerResolution = func(res time.Duration, set bool) (time.Duration, error) {
+	var flag uintptr
+	if set {
+		flag = 1
+	}
+	var current uint32
+	status, _, _ := procNtSetTimerResolution.Call(uintptr(res/100), flag, uintptr(unsafe.Pointer(&current)))
+	if status != 0 {
+		return 0, fmt.Errorf("NtSetTimerResolution: NTSTATUS 0x%x", status)
+	}
+	return time.Duration(current) * 100, nil
+}
@@ -0,0 +1,236 @@
+package gaming
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CPUCore is one physical core.
+type CPUCore struct {
+	// Mask has a bit set for each of the core's logical processors.
+	Mask uint64
+	// EfficiencyClass is higher for faster cores on hybrid CPUs (P-cores
+	// above E-cores) and the same for every core otherwise.
+	EfficiencyClass int
+}
+
+// CPUTopology describes the processor's cores and how they share L3
+// cache. Only processor group 0, the one affinity masks apply to, is
+// covered.
+type CPUTopology struct {
+	Cores []CPUCore
+	// L3 has one mask per L3 cache; on dual-CCD Ryzen CPUs each CCD has
+	// its own.
+	L3 []uint64
+}
+
+// Logical returns the mask of every logical processor.
+func (t CPUTopology) Logical() uint64 {
+	var m uint64
+	for _, c := range t.Cores {
+		m |= c.Mask
+	}
+	return m
+}
+
+var (
+	topologyOnce sync.Once
+	topology     CPUTopology
+	topologyErr  error
+)
+
+// Topology returns the CPU topology of this machine.
+func Topology() (CPUTopology, error) {
+	topologyOnce.Do(func() { topology, topologyErr = cpuTopology() })
+	return topology, topologyErr
+}
+
+// Relationships and their layout in SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX
+// records, as returned by GetLogicalProcessorInformationEx.
+const (
+	relationProcessorCore = 0
+	relationCache         = 2
+)
+
+// parseProcessorInfo decodes the SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX
+// records in buf into a topology.
+func parseProcessorInfo(buf []byte) (CPUTopology, error) {
+	var t CPUTopology
+	for len(buf) >= 8 {
+		relationship := binary.LittleEndian.Uint32(buf)
+		size := int(binary.LittleEndian.Uint32(buf[4:]))
+		if size < 8 || size > len(buf) {
+			return t, fmt.Errorf("malformed processor information")
+		}
+		rec := buf[:size]
+		buf = buf[size:]
+
+		switch relationship {
+		case relationProcessorCore:
+			// Flags, EfficiencyClass, Reserved[20], GroupCount, then
+			// GROUP_AFFINITY{Mask, Group, Reserved[3]} at offset 32.
+			if len(rec) < 42 || binary.LittleEndian.Uint16(rec[40:]) != 0 {
+				continue
+			}
+			t.Cores = append(t.Cores, CPUCore{
+				Mask:            binary.LittleEndian.Uint64(rec[32:]),
+				EfficiencyClass: int(rec[9]),
+			})
+		case relationCache:
+			// Level, Associativity, LineSize, CacheSize, Type,
+			// Reserved[18], GroupCount, then GROUP_AFFINITY at offset 40.
+			if len(rec) < 50 || rec[8] != 3 || binary.LittleEndian.Uint16(rec[48:]) != 0 {
+				continue
+			}
+			t.L3 = append(t.L3, binary.LittleEndian.Uint64(rec[40:]))
+		}
+	}
+	if len(t.Cores) == 0 {
+		return t, fmt.Errorf("no processor cores reported")
+	}
+	return t, nil
+}
+
+// cpuListPattern matches CPU lists such as "0-7,12".
+var cpuListPattern = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*$`)
+
+// ValidateAffinity reports whether spec is a well-formed affinity
+// specification (see AffinityMask), without checking it against this
+// machine's CPUs.
+func ValidateAffinity(spec string) error {
+	_, err := affinityMask(spec, CPUTopology{}, false)
+	return err
+}
+
+// AffinityMask turns an affinity specification into a mask of logical
+// processors on a CPU with topology t. A specification is one of:
+//
+//   - a hex mask such as 0xFF
+//   - a CPU list such as 0-7,12
+//   - words naming core sets, all of which must hold: "physical" (one
+//     logical processor per core, i.e. no SMT siblings), "p-cores" and
+//     "e-cores" (performance and efficiency cores of hybrid CPUs), "ccd0",
+//     "ccd1", ... (the cores sharing one L3 cache, a CCD on dual-CCD
+//     Ryzen CPUs) and "all". "only" and "cores" are ignored, so
+//     "physical cores only" and "CCD0 only" read naturally.
+func AffinityMask(spec string, t CPUTopology) (uint64, error) {
+	return affinityMask(spec, t, true)
+}
+
+func affinityMask(spec string, t CPUTopology, resolve bool) (uint64, error) {
+	s := strings.ToLower(strings.TrimSpace(spec))
+	if s == "" {
+		return 0, fmt.Errorf("empty affinity")
+	}
+	if strings.HasPrefix(s, "0x") {
+		m, err := strconv.ParseUint(s[2:], 16, 64)
+		if err != nil || m == 0 {
+			return 0, fmt.Errorf("invalid affinity mask %q", spec)
+		}
+		return m, nil
+	}
+	if list := strings.ReplaceAll(s, " ", ""); cpuListPattern.MatchString(list) {
+		return cpuList(list, spec)
+	}
+
+	mask := ^uint64(0)
+	if resolve {
+		mask = t.Logical()
+	}
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == '+' || r == ',' }) {
+		word = strings.NewReplacer("-", "", "_", "").Replace(word)
+		var m uint64
+		switch {
+		case word == "only" || word == "cores" || word == "core" || word == "all":
+			continue
+		case word == "physical":
+			for _, c := range t.Cores {
+				m |= c.Mask & -c.Mask // lowest logical processor of the core
+			}
+		case word == "pcores" || word == "performance":
+			m = coresByClass(t, true)
+		case word == "ecores" || word == "efficiency":
+			m = coresByClass(t, false)
+		case strings.HasPrefix(word, "ccd"):
+			n, err := strconv.Atoi(word[3:])
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid affinity %q: %q is not ccd0, ccd1, ...", spec, word)
+			}
+			if resolve && n >= len(t.L3) {
+				return 0, fmt.Errorf("affinity %q: this CPU has %d L3 cache group(s)", spec, len(t.L3))
+			}
+			if resolve {
+				m = t.L3[n]
+			}
+		default:
+			return 0, fmt.Errorf("invalid affinity %q: unknown %q (want physical, p-cores, e-cores, ccd0, a CPU list or a hex mask)", spec, word)
+		}
+		if resolve {
+			mask &= m
+		}
+	}
+	if resolve && mask == 0 {
+		return 0, fmt.Errorf("affinity %q selects no CPUs on this machine", spec)
+	}
+	return mask, nil
+}
+
+// coresByClass returns the cores of the highest efficiency class, or of
+// the lowest when fastest is false. On CPUs whose cores are all alike
+// there are no E-cores, so that returns 0.
+func coresByClass(t CPUTopology, fastest bool) uint64 {
+	lo, hi := 255, -1
+	for _, c := range t.Cores {
+		lo, hi = min(lo, c.EfficiencyClass), max(hi, c.EfficiencyClass)
+	}
+	want := hi
+	if !fastest {
+		if lo == hi {
+			return 0
+		}
+		want = lo
+	}
+	var m uint64
+	for _, c := range t.Cores {
+		if c.EfficiencyClass == want {
+			m |= c.Mask
+		}
+	}
+	return m
+}
+
+// cpuList turns a CPU list such as 0-7,12 into a mask.
+func cpuList(list, spec string) (uint64, error) {
+	var m uint64
+	for _, part := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, _ := strconv.Atoi(lo)
+		last := first
+		if isRange {
+			last, _ = strconv.Atoi(hi)
+		}
+		if first > last || last >= 64 {
+			return 0, fmt.Errorf("invalid affinity %q: CPUs must be 0-63 in ascending ranges", spec)
+		}
+		for i := first; i <= last; i++ {
+			m |= 1 << i
+		}
+	}
+	return m, nil
+}
+
+// describeMask lists the CPUs in mask, for logs.
+func describeMask(mask uint64) string {
+	var cpus []string
+	for mask != 0 {
+		i := bits.TrailingZeros64(mask)
+		cpus = append(cpus, strconv.Itoa(i))
+		mask &^= 1 << i
+	}
+	return strings.Join(cpus, ",")
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package gaming
+
+import "fmt"
+
+func cpuTopology() (CPUTopology, error) {
+	return CPUTopology{}, fmt.Errorf("CPU topology not available on this platform")
+}
@@ -0,0 +1,91 @@
+package gaming
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// coreRecord and l3Record encode SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX
+// records as GetLogicalProcessorInformationEx returns them.
+func coreRecord(mask uint64, class byte) []byte {
+	rec := make([]byte, 48)
+	binary.LittleEndian.PutUint32(rec, relationProcessorCore)
+	binary.LittleEndian.PutUint32(rec[4:], 48)
+	rec[9] = class
+	binary.LittleEndian.PutUint16(rec[30:], 1)
+	binary.LittleEndian.PutUint64(rec[32:], mask)
+	return rec
+}
+
+func l3Record(mask uint64) []byte {
+	rec := make([]byte, 56)
+	binary.LittleEndian.PutUint32(rec, relationCache)
+	binary.LittleEndian.PutUint32(rec[4:], 56)
+	rec[8] = 3
+	binary.LittleEndian.PutUint16(rec[38:], 1)
+	binary.LittleEndian.PutUint64(rec[40:], mask)
+	return rec
+}
+
+func TestParseProcessorInfo(t *testing.T) {
+	// Two cores with SMT siblings, sharing one L3.
+	var buf []byte
+	buf = append(buf, coreRecord(0x3, 0)...)
+	buf = append(buf, coreRecord(0xC, 0)...)
+	buf = append(buf, l3Record(0xF)...)
+
+	topo, err := parseProcessorInfo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topo.Cores) != 2 || topo.Cores[1].Mask != 0xC || len(topo.L3) != 1 || topo.L3[0] != 0xF {
+		t.Errorf("unexpected topology %+v", topo)
+	}
+	if _, err := parseProcessorInfo(buf[:20]); err == nil {
+		t.Error("expected an error for a truncated record")
+	}
+}
+
+func TestAffinityMask(t *testing.T) {
+	// A dual-CCD CPU with two SMT cores per CCD.
+	ryzen := CPUTopology{
+		Cores: []CPUCore{{Mask: 0x3}, {Mask: 0xC}, {Mask: 0x30}, {Mask: 0xC0}},
+		L3:    []uint64{0x0F, 0xF0},
+	}
+	// A hybrid CPU with two SMT P-cores and two E-cores.
+	hybrid := CPUTopology{
+		Cores: []CPUCore{{Mask: 0x3, EfficiencyClass: 1}, {Mask: 0xC, EfficiencyClass: 1}, {Mask: 0x10}, {Mask: 0x20}},
+		L3:    []uint64{0x3F},
+	}
+	for _, tc := range []struct {
+		spec string
+		topo CPUTopology
+		want uint64
+	}{
+		{"all", ryzen, 0xFF},
+		{"physical cores only", ryzen, 0x55},
+		{"CCD0 only", ryzen, 0x0F},
+		{"ccd1 physical", ryzen, 0x50},
+		{"p-cores", hybrid, 0x0F},
+		{"E-Cores", hybrid, 0x30},
+		{"p-cores+physical", hybrid, 0x05},
+		{"0-3, 6", ryzen, 0x4F},
+		{"0xF0", ryzen, 0xF0},
+	} {
+		got, err := AffinityMask(tc.spec, tc.topo)
+		if err != nil || got != tc.want {
+			t.Errorf("AffinityMask(%q) = 0x%x, %v; want 0x%x", tc.spec, got, err, tc.want)
+		}
+	}
+	for _, spec := range []string{"", "ccd2", "e-cores", "fast cores", "7-3", "0x"} {
+		if _, err := AffinityMask(spec, ryzen); err == nil {
+			t.Errorf("AffinityMask(%q) should fail", spec)
+		}
+	}
+	if err := ValidateAffinity("ccd3 physical"); err != nil {
+		t.Errorf("ccd3 is well-formed even if this CPU lacks it: %v", err)
+	}
+	if err := ValidateAffinity("fast cores"); err == nil {
+		t.Error("expected fast cores to be rejected")
+	}
+}
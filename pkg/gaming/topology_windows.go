@@ -0,0 +1,31 @@
+package gaming
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetLogicalProcessorInformationEx = kernel32.NewProc("GetLogicalProcessorInformationEx")
+
+const relationAll = 0xffff
+
+// cpuTopology reads the cores and L3 caches from
+// GetLogicalProcessorInformationEx.
+func cpuTopology() (CPUTopology, error) {
+	var size uint32
+	procGetLogicalProcessorInformationEx.Call(relationAll, 0, uintptr(unsafe.Pointer(&size)))
+	if size == 0 {
+		return CPUTopology{}, fmt.Errorf("failed to size processor information")
+	}
+	buf := make([]byte, size)
+	if r, _, err := procGetLogicalProcessorInformationEx.Call(relationAll,
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size))); r == 0 {
+		if err == windows.ERROR_INSUFFICIENT_BUFFER {
+			return CPUTopology{}, fmt.Errorf("processor information changed while reading it")
+		}
+		return CPUTopology{}, fmt.Errorf("failed to read processor information: %w", err)
+	}
+	return parseProcessorInfo(buf[:size])
+}
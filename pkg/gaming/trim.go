@@ -0,0 +1,96 @@
+package gaming
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"syscleaner/pkg/memory"
+)
+
+// minTrimWorkingSet is the smallest working set worth trimming.
+const minTrimWorkingSet = 16 << 20
+
+// processMemory is a running process and its working set, in bytes.
+type processMemory struct {
+	PID        int32
+	Name       string
+	WorkingSet uint64
+}
+
+// These are variables so tests can fake the processes and trimming.
+var (
+	listProcessMemory = listProcessMemoryNative
+	processWorkingSet = processWorkingSetNative
+	emptyWorkingSet   = memory.EmptyProcessWorkingSet
+)
+
+func listProcessMemoryNative() ([]processMemory, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	var out []processMemory
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		mi, err := p.MemoryInfo()
+		if err != nil {
+			continue
+		}
+		out = append(out, processMemory{PID: p.Pid, Name: name, WorkingSet: mi.RSS})
+	}
+	return out, nil
+}
+
+func processWorkingSetNative(pid int32) (uint64, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return 0, err
+	}
+	mi, err := p.MemoryInfo()
+	if err != nil {
+		return 0, err
+	}
+	return mi.RSS, nil
+}
+
+// trimBackgroundWorkingSets empties the working sets of background
+// processes, pushing their pages out to the standby list and page file so
+// a game starting up gets the physical memory. Whitelisted processes,
+// games, anti-cheats, Windows' own processes, what streamer mode protects
+// and processes too small to matter are left alone. It returns how many
+// processes were trimmed and the bytes their working sets shrank by.
+func trimBackgroundWorkingSets(whitelist []string, streaming *StreamingConfig) (int, int64) {
+	procs, err := listProcessMemory()
+	if err != nil {
+		log.Printf("[SysCleaner] Could not list processes to trim: %v", err)
+		return 0, 0
+	}
+	self := int32(os.Getpid())
+	var trimmed int
+	var freed int64
+	for _, p := range procs {
+		lower := strings.ToLower(p.Name)
+		switch {
+		case p.PID == self || p.WorkingSet < minTrimWorkingSet:
+		case containsFold(whitelist, p.Name) || containsFold(ProcessWhitelist, p.Name):
+		case neverSuspend[lower] || isGameProcess(p.Name) || streaming.protects(p.Name):
+		case guardProcessName("trimming", p.Name):
+		default:
+			if err := emptyWorkingSet(uint32(p.PID)); err != nil {
+				continue
+			}
+			trimmed++
+			if after, err := processWorkingSet(p.PID); err == nil && after < p.WorkingSet {
+				freed += int64(p.WorkingSet - after)
+			}
+		}
+	}
+	log.Printf("[SysCleaner] Trimmed the working sets of %d background process(es), reclaiming %d MB", trimmed, freed>>20)
+	return trimmed, freed
+}
@@ -0,0 +1,38 @@
+package gaming
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTrimBackgroundWorkingSets(t *testing.T) {
+	origList, origSize, origEmpty := listProcessMemory, processWorkingSet, emptyWorkingSet
+	t.Cleanup(func() { listProcessMemory, processWorkingSet, emptyWorkingSet = origList, origSize, origEmpty })
+
+	const mb = 1 << 20
+	procs := []processMemory{
+		{PID: 10, Name: "chrome.exe", WorkingSet: 400 * mb},
+		{PID: 11, Name: "Spotify.exe", WorkingSet: 200 * mb}, // whitelisted
+		{PID: 12, Name: "cs2.exe", WorkingSet: 900 * mb},     // a game
+		{PID: 13, Name: "dwm.exe", WorkingSet: 100 * mb},     // Windows
+		{PID: 14, Name: "obs64.exe", WorkingSet: 300 * mb},   // streamer mode
+		{PID: 15, Name: "tiny.exe", WorkingSet: 1 * mb},      // too small
+		{PID: 16, Name: "Slack.exe", WorkingSet: 250 * mb},
+		{PID: int32(os.Getpid()), Name: "syscleaner.exe", WorkingSet: 50 * mb},
+	}
+	listProcessMemory = func() ([]processMemory, error) { return procs, nil }
+	var emptied []uint32
+	emptyWorkingSet = func(pid uint32) error {
+		emptied = append(emptied, pid)
+		return nil
+	}
+	processWorkingSet = func(pid int32) (uint64, error) { return 50 * mb, nil }
+
+	n, freed := trimBackgroundWorkingSets([]string{"spotify.exe"}, &StreamingConfig{})
+	if n != 2 || len(emptied) != 2 || emptied[0] != 10 || emptied[1] != 16 {
+		t.Fatalf("trimmed %d, PIDs %v; want chrome.exe and Slack.exe", n, emptied)
+	}
+	if freed != 550*mb {
+		t.Errorf("freed %d MB, want 550 MB", freed/mb)
+	}
+}
@@ -0,0 +1,123 @@
+package gaming
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"syscleaner/pkg/power"
+)
+
+// savedPowerSetting is a power plan setting's values, on AC and battery,
+// before gaming mode changed them.
+type savedPowerSetting struct {
+	Scheme string `json:"scheme"`
+	AC     uint32 `json:"ac"`
+	DC     uint32 `json:"dc"`
+}
+
+// devicePower is an input device's "Allow the computer to turn off this
+// device to save power" setting.
+type devicePower struct {
+	Instance string
+	Enabled  bool
+}
+
+// These are variables so tests can fake the power plan and devices.
+var (
+	usbPowerScheme    = power.Active
+	readPowerSetting  = power.Read
+	writePowerSetting = power.Write
+	inputDevicePower  = inputDevicePowerNative
+	setDevicePower    = setDevicePowerNative
+)
+
+// usbTweaks is what applyUSBTweaks changed, for the session record.
+type usbTweaks struct {
+	Suspend *savedPowerSetting
+	Devices []string
+}
+
+// applyUSBTweaks turns off USB selective suspend in the active power plan
+// and power saving on mice and keyboards, so a device idle for a moment
+// does not wake with a delay. record is called with what was changed
+// before each change is made.
+func applyUSBTweaks(record func(usbTweaks)) {
+	var done usbTweaks
+	if scheme, err := usbPowerScheme(); err != nil {
+		log.Printf("[SysCleaner] Could not read the active power plan: %v", err)
+	} else if ac, dc, err := readPowerSetting(scheme, power.USBSelectiveSuspend); err != nil {
+		log.Printf("[SysCleaner] Could not read the USB selective suspend setting: %v", err)
+	} else if ac != 0 || dc != 0 {
+		done.Suspend = &savedPowerSetting{Scheme: scheme, AC: ac, DC: dc}
+		record(done)
+		if err := writePowerSetting(scheme, power.USBSelectiveSuspend, 0, 0); err != nil {
+			log.Printf("[SysCleaner] Failed to disable USB selective suspend: %v", err)
+		} else {
+			log.Println("[SysCleaner] Disabled USB selective suspend")
+		}
+	}
+
+	devices, err := inputDevicePower()
+	if err != nil {
+		log.Printf("[SysCleaner] Could not read input device power settings: %v", err)
+		return
+	}
+	for _, d := range devices {
+		if d.Enabled {
+			done.Devices = append(done.Devices, d.Instance)
+		}
+	}
+	if len(done.Devices) == 0 {
+		return
+	}
+	record(done)
+	if err := setDevicePower(done.Devices, false); err != nil {
+		log.Printf("[SysCleaner] Failed to keep input devices awake: %v", err)
+	} else {
+		log.Printf("[SysCleaner] Kept %d mouse and keyboard device(s) from sleeping", len(done.Devices))
+	}
+}
+
+// restoreUSBTweaks puts back what applyUSBTweaks changed.
+func restoreUSBTweaks(t usbTweaks) {
+	if s := t.Suspend; s != nil {
+		if err := writePowerSetting(s.Scheme, power.USBSelectiveSuspend, s.AC, s.DC); err != nil {
+			log.Printf("[SysCleaner] Failed to restore USB selective suspend: %v", err)
+		}
+	}
+	if len(t.Devices) > 0 {
+		if err := setDevicePower(t.Devices, true); err != nil {
+			log.Printf("[SysCleaner] Failed to restore input device power saving: %v", err)
+		}
+	}
+}
+
+// vidPIDPattern matches the vendor and product part of a device ID.
+var vidPIDPattern = regexp.MustCompile(`VID_[0-9A-F]{4}&PID_[0-9A-F]{4}(&MI_[0-9A-F]{2})?`)
+
+// matchInputDevices returns the power-managed device instances that belong
+// to the mice and keyboards with the given device IDs. The power setting
+// often sits on the USB device the HID mouse or keyboard hangs off, so
+// instances are matched by vendor and product rather than by exact ID.
+func matchInputDevices(inputIDs, instances []string) []string {
+	ids := make(map[string]bool)
+	for _, id := range inputIDs {
+		if m := vidPIDPattern.FindString(strings.ToUpper(id)); m != "" {
+			// Also match the composite device the interface belongs to.
+			ids[m] = true
+			ids[m[:len("VID_0000&PID_0000")]] = true
+		}
+	}
+	var out []string
+	for _, inst := range instances {
+		upper := strings.ToUpper(inst)
+		if !strings.HasPrefix(upper, `USB\`) && !strings.HasPrefix(upper, `HID\`) {
+			continue
+		}
+		if m := vidPIDPattern.FindString(upper); m != "" && ids[m] {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package gaming
+
+import "fmt"
+
+func inputDevicePowerNative() ([]devicePower, error) {
+	return nil, fmt.Errorf("device power management not available on this platform")
+}
+
+func setDevicePowerNative(instances []string, enable bool) error {
+	return fmt.Errorf("device power management not available on this platform")
+}
@@ -0,0 +1,77 @@
+package gaming
+
+import (
+	"reflect"
+	"testing"
+
+	"syscleaner/pkg/power"
+)
+
+func TestUSBTweaks_ApplyRestore(t *testing.T) {
+	origActive, origRead, origWrite := usbPowerScheme, readPowerSetting, writePowerSetting
+	origDevices, origSet := inputDevicePower, setDevicePower
+	t.Cleanup(func() {
+		usbPowerScheme, readPowerSetting, writePowerSetting = origActive, origRead, origWrite
+		inputDevicePower, setDevicePower = origDevices, origSet
+	})
+
+	suspend := [2]uint32{1, 2}
+	deviceOn := map[string]bool{`USB\VID_046D&PID_C539\1`: true, `HID\VID_1532&PID_0084\2`: false}
+	usbPowerScheme = func() (string, error) { return "scheme", nil }
+	readPowerSetting = func(scheme string, s power.Setting) (uint32, uint32, error) {
+		return suspend[0], suspend[1], nil
+	}
+	writePowerSetting = func(scheme string, s power.Setting, ac, dc uint32) error {
+		suspend = [2]uint32{ac, dc}
+		return nil
+	}
+	inputDevicePower = func() ([]devicePower, error) {
+		var out []devicePower
+		for inst, on := range deviceOn {
+			out = append(out, devicePower{Instance: inst, Enabled: on})
+		}
+		return out, nil
+	}
+	setDevicePower = func(instances []string, enable bool) error {
+		for _, inst := range instances {
+			deviceOn[inst] = enable
+		}
+		return nil
+	}
+
+	var recorded usbTweaks
+	applyUSBTweaks(func(u usbTweaks) { recorded = u })
+	if suspend != [2]uint32{0, 0} || deviceOn[`USB\VID_046D&PID_C539\1`] {
+		t.Fatalf("after apply: suspend = %v, devices = %v", suspend, deviceOn)
+	}
+	want := usbTweaks{
+		Suspend: &savedPowerSetting{Scheme: "scheme", AC: 1, DC: 2},
+		Devices: []string{`USB\VID_046D&PID_C539\1`},
+	}
+	if !reflect.DeepEqual(recorded, want) {
+		t.Fatalf("recorded %+v, want %+v", recorded, want)
+	}
+
+	restoreUSBTweaks(recorded)
+	if suspend != [2]uint32{1, 2} || !deviceOn[`USB\VID_046D&PID_C539\1`] || deviceOn[`HID\VID_1532&PID_0084\2`] {
+		t.Errorf("after restore: suspend = %v, devices = %v", suspend, deviceOn)
+	}
+}
+
+func TestMatchInputDevices(t *testing.T) {
+	ids := []string{
+		`HID\VID_046D&PID_C539&MI_01&COL01\7&1F2C&0&0000`,
+		`ACPI\PNP0303\4&1D4`,
+	}
+	instances := []string{
+		`USB\VID_046D&PID_C539\5&2A7C_0`,
+		`USB\VID_046D&PID_C539&MI_01\6&3B8D_0`,
+		`USB\VID_046D&PID_C540\5&2A7C_0`,
+		`PCI\VEN_8086&DEV_A36D\3&11583659&0&A0`,
+	}
+	got := matchInputDevices(ids, instances)
+	want := instances[:2]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchInputDevices = %v, want %v", got, want)
+	}
+}
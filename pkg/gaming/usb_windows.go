@@ -0,0 +1,141 @@
+//go:build windows
+
+package gaming
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// inputDevicePowerNative returns the power setting of every mouse and
+// keyboard, from WMI's MSPower_DeviceEnable class.
+func inputDevicePowerNative() ([]devicePower, error) {
+	var ids []string
+	err := wmiQuery(`root\cimv2`, "SELECT PNPDeviceID FROM Win32_PointingDevice", func(item *ole.IDispatch) error {
+		ids = append(ids, wmiString(item, "PNPDeviceID"))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = wmiQuery(`root\cimv2`, "SELECT PNPDeviceID FROM Win32_Keyboard", func(item *ole.IDispatch) error {
+		ids = append(ids, wmiString(item, "PNPDeviceID"))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool)
+	var instances []string
+	err = wmiQuery(`root\wmi`, "SELECT InstanceName, Enable FROM MSPower_DeviceEnable", func(item *ole.IDispatch) error {
+		name := wmiString(item, "InstanceName")
+		instances = append(instances, name)
+		if v, err := oleutil.GetProperty(item, "Enable"); err == nil {
+			on, _ := v.Value().(bool)
+			enabled[name] = on
+			v.Clear()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []devicePower
+	for _, inst := range matchInputDevices(ids, instances) {
+		out = append(out, devicePower{Instance: inst, Enabled: enabled[inst]})
+	}
+	return out, nil
+}
+
+// setDevicePowerNative turns power saving on or off for the given
+// MSPower_DeviceEnable instances.
+func setDevicePowerNative(instances []string, enable bool) error {
+	want := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		want[inst] = true
+	}
+	var errs []error
+	err := wmiQuery(`root\wmi`, "SELECT InstanceName, Enable FROM MSPower_DeviceEnable", func(item *ole.IDispatch) error {
+		name := wmiString(item, "InstanceName")
+		if !want[name] {
+			return nil
+		}
+		if _, err := oleutil.PutProperty(item, "Enable", enable); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return nil
+		}
+		if res, err := oleutil.CallMethod(item, "Put_"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		} else {
+			res.Clear()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// wmiQuery runs a WQL query in namespace and calls fn for every result.
+func wmiQuery(namespace, query string, fn func(item *ole.IDispatch) error) error {
+	// COM objects belong to the thread that created them.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED); err != nil {
+		var oleErr *ole.OleError
+		// S_FALSE: COM was already initialized on this thread.
+		if !errors.As(err, &oleErr) || oleErr.Code() != 1 {
+			return fmt.Errorf("initializing COM: %w", err)
+		}
+	}
+	defer ole.CoUninitialize()
+
+	unknown, err := oleutil.CreateObject("WbemScripting.SWbemLocator")
+	if err != nil {
+		return fmt.Errorf("creating WMI locator: %w", err)
+	}
+	defer unknown.Release()
+	locator, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return fmt.Errorf("creating WMI locator: %w", err)
+	}
+	defer locator.Release()
+
+	serviceRaw, err := oleutil.CallMethod(locator, "ConnectServer", nil, namespace)
+	if err != nil {
+		return fmt.Errorf("connecting to WMI: %w", err)
+	}
+	service := serviceRaw.ToIDispatch()
+	defer serviceRaw.Clear()
+
+	resultRaw, err := oleutil.CallMethod(service, "ExecQuery", query)
+	if err != nil {
+		return fmt.Errorf("querying WMI: %w", err)
+	}
+	result := resultRaw.ToIDispatch()
+	defer resultRaw.Clear()
+
+	return oleutil.ForEach(result, func(v *ole.VARIANT) error {
+		item := v.ToIDispatch()
+		return fn(item)
+	})
+}
+
+// wmiString returns a string property of a WMI object, or "".
+func wmiString(item *ole.IDispatch, name string) string {
+	v, err := oleutil.GetProperty(item, name)
+	if err != nil {
+		return ""
+	}
+	defer v.Clear()
+	s, _ := v.Value().(string)
+	return s
+}
@@ -0,0 +1,86 @@
+package gaming
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// WatchdogArg starts the executable as an extreme mode watchdog for the
+// process whose PID follows it (see RunWatchdog).
+const WatchdogArg = "--extreme-watchdog"
+
+// watchdogInterval is how often the watchdog checks on its owner.
+const watchdogInterval = 2 * time.Second
+
+// startWatchdog launches a copy of this executable that restores Explorer
+// and the stopped services if this process dies while extreme mode is on.
+// It exits by itself once the session ends normally.
+func startWatchdog() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, WatchdogArg, strconv.Itoa(os.Getpid()))
+	cmd.SysProcAttr = getSysProcAttr()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// RunWatchdog runs the extreme mode watchdog if args, the command-line
+// arguments after the program name, ask for it, and reports whether they
+// did. The watchdog waits for the gaming session owned by the given PID to
+// end: if it is ended normally, or its owner detaches it to leave extreme
+// mode on after a normal exit (see DetachSession), the watchdog just
+// exits, and if the owner dies first the watchdog restores the session
+// (see RecoverSession), so a crash does not leave the user without a
+// desktop.
+func RunWatchdog(args []string) bool {
+	if len(args) != 2 || args[0] != WatchdogArg {
+		return false
+	}
+	owner, err := strconv.Atoi(args[1])
+	if err != nil {
+		log.Printf("[SysCleaner] Watchdog: invalid PID %q", args[1])
+		return true
+	}
+	for {
+		s, err := loadSession()
+		if err != nil || s.OwnerPID != int32(owner) {
+			return true // the session ended normally
+		}
+		if s.Detached {
+			return true // the owner exited normally and left it on
+		}
+		if s.stale() {
+			log.Printf("[SysCleaner] Watchdog: SysCleaner (PID %d) exited during extreme mode, restoring the desktop", owner)
+			if _, err := RecoverSession(); err != nil {
+				log.Printf("[SysCleaner] Watchdog: %v", err)
+			}
+			return true
+		}
+		time.Sleep(watchdogInterval)
+	}
+}
+
+// processRunning reports whether a process with the given executable
+// name is running.
+func processRunning(name string) bool {
+	procs, err := process.Processes()
+	if err != nil {
+		return false
+	}
+	for _, p := range procs {
+		if n, err := p.Name(); err == nil && strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package gaming
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// DefaultWatchInterval is how often a Watcher checks whether the game it
+// is boosting has exited, and polls for new games where process start
+// events are unavailable.
+const DefaultWatchInterval = 5 * time.Second
+
+// errNoStartEvents is returned by watchProcessStarts where the platform
+// has no process start events.
+var errNoStartEvents = errors.New("process start events are not available on this platform")
+
+// Watcher turns gaming mode on when a known game starts and off again when
+// it exits. Games are matched by executable against the game database
+// (see Games), installed launcher libraries (see ScanLibraries), game
+// overrides keyed by executable, and the Games field.
+//
+// Process starts are taken from WMI's Win32_ProcessStartTrace events; if
+// those are unavailable (they need administrator rights) the process list
+// is polled every Interval instead.
+type Watcher struct {
+	// Config is passed to Enable when a game starts, with Game set to the
+	// detected game.
+	Config Config
+
+	// Games maps further executables to watch for, such as those of
+	// custom profiles, to the game name applied for them.
+	Games map[string]string
+
+	// Interval is how often the watcher checks for exits and, when
+	// polling, for new games. Zero uses DefaultWatchInterval.
+	Interval time.Duration
+
+	// OnChange, if set, is called after the watcher turns gaming mode on
+	// (started is true) or off for game, with the error Enable or Disable
+	// returned.
+	OnChange func(game string, started bool, err error)
+
+	mu   sync.Mutex
+	game string
+	pid  int32
+}
+
+// NewWatcher returns a Watcher applying cfg, with game process priority
+// boosting turned on.
+func NewWatcher(cfg Config) *Watcher {
+	cfg.AutoDetectGames = true
+	return &Watcher{Config: cfg, Interval: DefaultWatchInterval}
+}
+
+// Game returns the game the watcher turned gaming mode on for, or "".
+func (w *Watcher) Game() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.game
+}
+
+type processStart struct {
+	name string
+	pid  int32
+}
+
+// Run watches for games until ctx is done. Gaming mode the watcher turned
+// on is turned off before it returns.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	starts := make(chan processStart, 16)
+	startsErr := make(chan error, 1)
+	go func() {
+		startsErr <- watchProcessStarts(ctx, func(name string, pid int32) {
+			select {
+			case starts <- processStart{name, pid}:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	ScanLibraries()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	polling := false
+	w.scan()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.revert()
+			return nil
+		case err := <-startsErr:
+			startsErr = nil
+			if ctx.Err() == nil {
+				log.Printf("[SysCleaner] Game watcher: %v; polling every %s instead", err, interval)
+				polling = true
+			}
+		case p := <-starts:
+			w.started(p.name, p.pid)
+		case <-ticker.C:
+			w.checkExited()
+			if polling {
+				w.scan()
+			}
+		}
+	}
+}
+
+// match returns the game exe belongs to, if it is one the watcher looks
+// for.
+func (w *Watcher) match(exe string) (string, bool) {
+	for e, game := range w.Games {
+		if strings.EqualFold(e, exe) {
+			return game, true
+		}
+	}
+	if p := GetGameProfileByExe(exe); p != nil {
+		return p.Name, true
+	}
+	if g, ok := InstalledGameByExe(exe); ok {
+		return g.Name, true
+	}
+	if hasOverride(exe) {
+		return exe, true
+	}
+	return "", false
+}
+
+// started turns gaming mode on for a newly started process, if it is a
+// game and nothing is being boosted yet.
+func (w *Watcher) started(exe string, pid int32) {
+	game, ok := w.match(exe)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	if w.game != "" {
+		w.mu.Unlock()
+		return
+	}
+	if IsEnabled() {
+		w.mu.Unlock()
+		log.Printf("[SysCleaner] Game watcher: %s started, but gaming mode is already on", game)
+		return
+	}
+
+	log.Printf("[SysCleaner] Game watcher: %s started (%s, PID %d), enabling gaming mode", game, exe, pid)
+	cfg := w.Config
+	cfg.Game = game
+	err := Enable(cfg)
+	if err != nil {
+		log.Printf("[SysCleaner] Game watcher: could not enable gaming mode for %s: %v", game, err)
+	} else {
+		w.game, w.pid = game, pid
+	}
+	w.mu.Unlock()
+	w.notify(game, true, err)
+}
+
+// checkExited turns gaming mode off once no process of the boosted game
+// is left.
+func (w *Watcher) checkExited() {
+	w.mu.Lock()
+	game, pid := w.game, w.pid
+	w.mu.Unlock()
+	if game == "" {
+		return
+	}
+	if alive, err := process.PidExists(pid); err != nil || alive {
+		return
+	}
+	// Launchers often hand over to another executable of the same game.
+	if procs, err := process.Processes(); err == nil {
+		for _, p := range procs {
+			name, err := p.Name()
+			if err != nil {
+				continue
+			}
+			if g, ok := w.match(name); ok && g == game {
+				w.mu.Lock()
+				w.pid = p.Pid
+				w.mu.Unlock()
+				return
+			}
+		}
+	}
+	log.Printf("[SysCleaner] Game watcher: %s exited, disabling gaming mode", game)
+	w.revert()
+}
+
+// revert turns off gaming mode the watcher turned on.
+func (w *Watcher) revert() {
+	w.mu.Lock()
+	if w.game == "" {
+		w.mu.Unlock()
+		return
+	}
+	game := w.game
+	w.game, w.pid = "", 0
+	var err error
+	// Gaming mode may have been turned off by hand meanwhile.
+	if IsEnabled() {
+		err = Disable()
+	}
+	w.mu.Unlock()
+	if err != nil {
+		log.Printf("[SysCleaner] Game watcher: could not disable gaming mode: %v", err)
+	}
+	w.notify(game, false, err)
+}
+
+// scan looks for a game that is already running.
+func (w *Watcher) scan() {
+	if w.Game() != "" {
+		return
+	}
+	procs, err := process.Processes()
+	if err != nil {
+		return
+	}
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		if _, ok := w.match(name); ok {
+			w.started(name, p.Pid)
+			return
+		}
+	}
+}
+
+func (w *Watcher) notify(game string, started bool, err error) {
+	if w.OnChange != nil {
+		w.OnChange(game, started, err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package gaming
+
+import "context"
+
+func watchProcessStarts(ctx context.Context, fn func(name string, pid int32)) error {
+	return errNoStartEvents
+}
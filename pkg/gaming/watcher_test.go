@@ -0,0 +1,31 @@
+package gaming
+
+import "testing"
+
+func TestWatcher_Match(t *testing.T) {
+	SetGameOverrides(map[string]GameOverride{"mygame.exe": {Priority: "above normal"}})
+	defer SetGameOverrides(nil)
+
+	w := NewWatcher(Config{})
+	w.Games = map[string]string{"Indie.exe": "Indie Game"}
+
+	tests := []struct {
+		exe    string
+		game   string
+		wantOK bool
+	}{
+		{"VALORANT-Win64-Shipping.exe", "Valorant", true},
+		{"indie.EXE", "Indie Game", true},
+		{"MyGame.exe", "MyGame.exe", true},
+		{"notepad.exe", "", false},
+	}
+	for _, tt := range tests {
+		game, ok := w.match(tt.exe)
+		if game != tt.game || ok != tt.wantOK {
+			t.Errorf("match(%q) = %q, %v; want %q, %v", tt.exe, game, ok, tt.game, tt.wantOK)
+		}
+	}
+	if !w.Config.AutoDetectGames {
+		t.Error("NewWatcher should turn on priority boosting")
+	}
+}
@@ -0,0 +1,111 @@
+//go:build windows
+
+package gaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// wbemErrTimedOut is the SCODE SWbemEventSource.NextEvent fails with when
+// no event arrives within its timeout.
+const wbemErrTimedOut = 0x80043001
+
+// watchProcessStarts calls fn for every process started until ctx is done,
+// using WMI's Win32_ProcessStartTrace events. It returns an error straight
+// away if the subscription fails, which it does without administrator
+// rights.
+func watchProcessStarts(ctx context.Context, fn func(name string, pid int32)) error {
+	// COM objects belong to the thread that created them.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED); err != nil {
+		var oleErr *ole.OleError
+		// S_FALSE: COM was already initialized on this thread.
+		if !errors.As(err, &oleErr) || oleErr.Code() != 1 {
+			return fmt.Errorf("initializing COM: %w", err)
+		}
+	}
+	defer ole.CoUninitialize()
+
+	unknown, err := oleutil.CreateObject("WbemScripting.SWbemLocator")
+	if err != nil {
+		return fmt.Errorf("creating WMI locator: %w", err)
+	}
+	defer unknown.Release()
+	locator, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return fmt.Errorf("creating WMI locator: %w", err)
+	}
+	defer locator.Release()
+
+	serviceRaw, err := oleutil.CallMethod(locator, "ConnectServer", nil, `root\cimv2`)
+	if err != nil {
+		return fmt.Errorf("connecting to WMI: %w", err)
+	}
+	service := serviceRaw.ToIDispatch()
+	defer serviceRaw.Clear()
+
+	sourceRaw, err := oleutil.CallMethod(service, "ExecNotificationQuery", "SELECT ProcessName, ProcessID FROM Win32_ProcessStartTrace")
+	if err != nil {
+		return fmt.Errorf("subscribing to process start events: %w", err)
+	}
+	source := sourceRaw.ToIDispatch()
+	defer sourceRaw.Clear()
+
+	for ctx.Err() == nil {
+		// Wait at most a second so ctx is checked regularly.
+		eventRaw, err := oleutil.CallMethod(source, "NextEvent", 1000)
+		if err != nil {
+			if isWMITimeout(err) {
+				continue
+			}
+			return fmt.Errorf("reading process start events: %w", err)
+		}
+		event := eventRaw.ToIDispatch()
+		name, nameErr := oleutil.GetProperty(event, "ProcessName")
+		pid, pidErr := oleutil.GetProperty(event, "ProcessID")
+		if nameErr == nil && pidErr == nil {
+			fn(name.ToString(), int32(variantInt(pid)))
+		}
+		if nameErr == nil {
+			name.Clear()
+		}
+		if pidErr == nil {
+			pid.Clear()
+		}
+		eventRaw.Clear()
+	}
+	return nil
+}
+
+// isWMITimeout reports whether err is NextEvent's timeout.
+func isWMITimeout(err error) bool {
+	var oleErr *ole.OleError
+	if !errors.As(err, &oleErr) {
+		return false
+	}
+	info, ok := oleErr.SubError().(ole.EXCEPINFO)
+	return ok && info.SCODE() == wbemErrTimedOut
+}
+
+// variantInt returns the integer held by a WMI uint32 property.
+func variantInt(v *ole.VARIANT) int64 {
+	switch n := v.Value().(type) {
+	case int32:
+		return int64(n)
+	case uint32:
+		return int64(n)
+	case int64:
+		return n
+	case uint64:
+		return int64(n)
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+package gaming
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// WhitelistWarning flags a process whitelist entry that matches no known or
+// running process, which usually means a typo.
+type WhitelistWarning struct {
+	Entry string
+	// Suggestion is the closest known or running name, or "" if nothing is
+	// close enough to guess.
+	Suggestion string
+}
+
+func (w WhitelistWarning) String() string {
+	if w.Suggestion != "" {
+		return fmt.Sprintf("%q matches no known or running process; did you mean %q?", w.Entry, w.Suggestion)
+	}
+	return fmt.Sprintf("%q matches no known or running process", w.Entry)
+}
+
+// shellProcesses are Windows processes worth recognising even when the
+// whitelist is checked while they are not running, e.g. in Extreme Mode.
+var shellProcesses = []string{"explorer.exe", "dwm.exe", "ShellExperienceHost.exe", "StartMenuExperienceHost.exe"}
+
+// KnownProcessNames returns the process names SysCleaner knows about
+// without looking at what is running: the apps Extreme Mode closes (by
+// default and per the configured stop list), the Windows shell and the
+// executables and companion processes of the known games.
+func KnownProcessNames() []string {
+	names := append(append([]string{}, processesToKill...), GetProcessesToKill()...)
+	names = append(names, shellProcesses...)
+	for _, g := range Games() {
+		names = append(names, g.Executables...)
+		names = append(names, g.PreserveProcesses...)
+	}
+	return names
+}
+
+// RunningProcessNames returns the names of the running processes, sorted
+// and without duplicates.
+func RunningProcessNames() []string {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil || name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateWhitelist checks whitelist against the known and running process
+// names. See CheckWhitelist.
+func ValidateWhitelist(whitelist []string) []WhitelistWarning {
+	return CheckWhitelist(whitelist, append(KnownProcessNames(), RunningProcessNames()...))
+}
+
+// CheckWhitelist returns a warning for each whitelist entry that is not in
+// names, compared case-insensitively as CloseBackgroundApps does. Each
+// warning suggests the closest name within a few edits, or the entry with
+// ".exe" added when that is a known name.
+func CheckWhitelist(whitelist, names []string) []WhitelistWarning {
+	known := make(map[string]string, len(names))
+	for _, n := range names {
+		known[strings.ToLower(n)] = n
+	}
+	var warnings []WhitelistWarning
+	for _, entry := range whitelist {
+		lower := strings.ToLower(strings.TrimSpace(entry))
+		if _, ok := known[lower]; ok {
+			continue
+		}
+		w := WhitelistWarning{Entry: entry}
+		if n, ok := known[lower+".exe"]; ok {
+			w.Suggestion = n
+		} else {
+			w.Suggestion = closestName(lower, names)
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// closestName returns the name in names nearest to entry (lower case) by
+// edit distance, if it is at most a quarter of the entry's length (and at
+// least one edit) away.
+func closestName(entry string, names []string) string {
+	limit := len(entry) / 4
+	if limit < 1 {
+		limit = 1
+	}
+	best, bestDist := "", limit+1
+	for _, n := range names {
+		if d := editDistance(entry, strings.ToLower(n)); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,32 @@
+package gaming
+
+import "testing"
+
+func TestCheckWhitelist(t *testing.T) {
+	names := []string{"explorer.exe", "Discord.exe", "obs64.exe"}
+	got := CheckWhitelist([]string{"DISCORD.EXE", "explorer.ex", "obs64", "zz.exe"}, names)
+	want := []WhitelistWarning{
+		{Entry: "explorer.ex", Suggestion: "explorer.exe"},
+		{Entry: "obs64", Suggestion: "obs64.exe"},
+		{Entry: "zz.exe"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("warnings = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("warning %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{{"", "abc", 3}, {"explorer.ex", "explorer.exe", 1}, {"steam.exe", "stema.exe", 2}, {"same", "same", 0}} {
+		if got := editDistance(c.a, c.b); got != c.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package gaming
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// microsoftGameConfig holds the fields SysCleaner uses from the
+// MicrosoftGame.config file in an Xbox / Microsoft Store game's Content
+// folder.
+type microsoftGameConfig struct {
+	Identity struct {
+		Name string `xml:"Name,attr"`
+	} `xml:"Identity"`
+	Executables []struct {
+		Name               string `xml:"Name,attr"`
+		TargetDeviceFamily string `xml:"TargetDeviceFamily,attr"`
+	} `xml:"ExecutableList>Executable"`
+	ShellVisuals struct {
+		DefaultDisplayName string `xml:"DefaultDisplayName,attr"`
+	} `xml:"ShellVisuals"`
+}
+
+// parseGamingRoot returns the folder named by a .GamingRoot file, which
+// the Xbox app writes to the root of each drive it installs games to. The
+// file is "RGBX", a 4-byte header field, then a NUL-terminated UTF-16LE
+// path relative to the drive root, normally "XboxGames".
+func parseGamingRoot(data []byte) (string, bool) {
+	if len(data) < 10 || string(data[:4]) != "RGBX" {
+		return "", false
+	}
+	var units []uint16
+	for i := 8; i+1 < len(data); i += 2 {
+		u := binary.LittleEndian.Uint16(data[i:])
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	path := string(utf16.Decode(units))
+	return path, path != ""
+}
+
+// scanXboxGames finds the games installed under each of roots, the Xbox
+// app's game folders. Each game is a folder holding Content\
+// MicrosoftGame.config; folders without one are skipped.
+func scanXboxGames(roots []string) ([]InstalledGame, error) {
+	var games []InstalledGame
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return games, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			content := filepath.Join(root, e.Name(), "Content")
+			data, err := os.ReadFile(filepath.Join(content, "MicrosoftGame.config"))
+			if err != nil {
+				continue
+			}
+			var cfg microsoftGameConfig
+			if err := xml.Unmarshal(data, &cfg); err != nil {
+				continue
+			}
+			g := InstalledGame{
+				Name:       cfg.ShellVisuals.DefaultDisplayName,
+				Launcher:   "Xbox",
+				ID:         cfg.Identity.Name,
+				InstallDir: content,
+			}
+			// Localized names are resource references; the folder name
+			// is the best readable name then.
+			if g.Name == "" || strings.HasPrefix(g.Name, "ms-resource:") {
+				g.Name = e.Name()
+			}
+			for _, exe := range cfg.Executables {
+				if exe.Name == "" || (exe.TargetDeviceFamily != "" && !strings.EqualFold(exe.TargetDeviceFamily, "PC")) {
+					continue
+				}
+				g.Executables = append(g.Executables, filepath.Join(content, filepath.FromSlash(strings.ReplaceAll(exe.Name, `\`, "/"))))
+			}
+			if len(g.Executables) > 0 {
+				games = append(games, g)
+			}
+		}
+	}
+	return games, nil
+}
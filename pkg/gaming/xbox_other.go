@@ -0,0 +1,7 @@
+//go:build !windows
+
+package gaming
+
+func xboxGameRoots() []string {
+	return nil
+}
@@ -0,0 +1,61 @@
+package gaming
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestParseGamingRoot(t *testing.T) {
+	data := []byte("RGBX\x01\x00\x00\x00")
+	for _, u := range utf16.Encode([]rune("XboxGames")) {
+		data = binary.LittleEndian.AppendUint16(data, u)
+	}
+	data = append(data, 0, 0)
+	if dir, ok := parseGamingRoot(data); !ok || dir != "XboxGames" {
+		t.Errorf("parseGamingRoot = %q, %v; want XboxGames", dir, ok)
+	}
+	if _, ok := parseGamingRoot([]byte("garbage!!")); ok {
+		t.Error("parseGamingRoot accepted a file without the RGBX header")
+	}
+}
+
+func TestScanXboxGames(t *testing.T) {
+	root := t.TempDir()
+	content := filepath.Join(root, "Forza Horizon 5", "Content")
+	if err := os.MkdirAll(content, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := `<?xml version="1.0" encoding="utf-8"?>
+<Game configVersion="0">
+  <Identity Name="Microsoft.624F8B84B80" Publisher="CN=Microsoft" Version="1.0.0.0"/>
+  <ExecutableList>
+    <Executable Name="ForzaHorizon5.exe" TargetDeviceFamily="PC" Id="Game"/>
+    <Executable Name="ForzaHorizon5_Xbox.exe" TargetDeviceFamily="Scarlett" Id="Console"/>
+  </ExecutableList>
+  <ShellVisuals DefaultDisplayName="ms-resource:DisplayName"/>
+</Game>`
+	if err := os.WriteFile(filepath.Join(content, "MicrosoftGame.config"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "Not A Game"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	games, err := scanXboxGames([]string{root, filepath.Join(root, "missing")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 {
+		t.Fatalf("got %d games, want 1: %+v", len(games), games)
+	}
+	g := games[0]
+	if g.Name != "Forza Horizon 5" || g.ID != "Microsoft.624F8B84B80" || g.Launcher != "Xbox" {
+		t.Errorf("game = %+v", g)
+	}
+	if len(g.Executables) != 1 || filepath.Base(g.Executables[0]) != "ForzaHorizon5.exe" {
+		t.Errorf("executables = %v", g.Executables)
+	}
+}
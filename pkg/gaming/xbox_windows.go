@@ -0,0 +1,25 @@
+//go:build windows
+
+package gaming
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// xboxGameRoots returns the game folders the Xbox app installs to, found
+// from the .GamingRoot file at the root of each drive.
+func xboxGameRoots() []string {
+	var roots []string
+	for drive := 'C'; drive <= 'Z'; drive++ {
+		root := string(drive) + `:\`
+		data, err := os.ReadFile(root + ".GamingRoot")
+		if err != nil {
+			continue
+		}
+		if dir, ok := parseGamingRoot(data); ok {
+			roots = append(roots, filepath.Join(root, dir))
+		}
+	}
+	return roots
+}
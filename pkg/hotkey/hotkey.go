@@ -0,0 +1,112 @@
+// Package hotkey registers system-wide keyboard shortcuts, which fire even
+// while a full-screen game has focus.
+package hotkey
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Modifier keys, with the values RegisterHotKey takes.
+const (
+	ModAlt   = 0x1
+	ModCtrl  = 0x2
+	ModShift = 0x4
+	ModWin   = 0x8
+)
+
+// Hotkey is a key combination.
+type Hotkey struct {
+	Modifiers uint32
+	// Key is the Windows virtual-key code.
+	Key uint32
+}
+
+var modifierNames = map[string]uint32{
+	"ctrl": ModCtrl, "control": ModCtrl,
+	"alt":   ModAlt,
+	"shift": ModShift,
+	"win":   ModWin, "windows": ModWin,
+}
+
+// namedKeys maps key names other than letters, digits and F1-F24 to
+// virtual-key codes.
+var namedKeys = map[string]uint32{
+	"space": 0x20, "pageup": 0x21, "pagedown": 0x22, "end": 0x23, "home": 0x24,
+	"left": 0x25, "up": 0x26, "right": 0x27, "down": 0x28,
+	"insert": 0x2D, "delete": 0x2E, "pause": 0x13, "scrolllock": 0x91,
+	"numpad0": 0x60, "numpad1": 0x61, "numpad2": 0x62, "numpad3": 0x63, "numpad4": 0x64,
+	"numpad5": 0x65, "numpad6": 0x66, "numpad7": 0x67, "numpad8": 0x68, "numpad9": 0x69,
+}
+
+// Parse reads a combination such as "Ctrl+Alt+G" or "Ctrl+Shift+F9". At
+// least one of Ctrl, Alt or Win is required, so a hotkey cannot swallow a
+// key games use on its own or with Shift.
+func Parse(s string) (Hotkey, error) {
+	var h Hotkey
+	parts := strings.Split(s, "+")
+	for i, part := range parts {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if i < len(parts)-1 {
+			mod, ok := modifierNames[name]
+			if !ok {
+				return h, fmt.Errorf("hotkey %q: unknown modifier %q (valid: Ctrl, Alt, Shift, Win)", s, part)
+			}
+			h.Modifiers |= mod
+			continue
+		}
+		key, ok := keyCode(name)
+		if !ok {
+			return h, fmt.Errorf("hotkey %q: unknown key %q", s, part)
+		}
+		h.Key = key
+	}
+	if h.Modifiers&(ModCtrl|ModAlt|ModWin) == 0 {
+		return h, fmt.Errorf("hotkey %q needs Ctrl, Alt or Win", s)
+	}
+	return h, nil
+}
+
+func keyCode(name string) (uint32, bool) {
+	if len(name) == 1 && (name[0] >= 'a' && name[0] <= 'z' || name[0] >= '0' && name[0] <= '9') {
+		return uint32(strings.ToUpper(name)[0]), true
+	}
+	var n int
+	if _, err := fmt.Sscanf(name, "f%d", &n); err == nil && n >= 1 && n <= 24 && name == fmt.Sprintf("f%d", n) {
+		return uint32(0x70 + n - 1), true
+	}
+	code, ok := namedKeys[name]
+	return code, ok
+}
+
+// Binding runs Action when Keys is pressed.
+type Binding struct {
+	Keys   Hotkey
+	Name   string // for error messages, e.g. "toggle gaming mode"
+	Action func()
+}
+
+var (
+	mu   sync.Mutex
+	stop func()
+)
+
+// Set replaces the registered hotkeys with bindings; an empty list just
+// unregisters them. A combination another program has already registered
+// is reported in the returned error, and the other bindings still work.
+// Actions run on their own goroutine.
+func Set(bindings []Binding) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if stop != nil {
+		stop()
+		stop = nil
+	}
+	if len(bindings) == 0 {
+		return nil
+	}
+	var err error
+	stop, err = listen(bindings)
+	return err
+}
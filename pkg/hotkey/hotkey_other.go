@@ -0,0 +1,9 @@
+//go:build !windows
+
+package hotkey
+
+import "fmt"
+
+func listen(bindings []Binding) (func(), error) {
+	return nil, fmt.Errorf("global hotkeys are only available on Windows")
+}
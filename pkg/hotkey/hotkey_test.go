@@ -0,0 +1,22 @@
+package hotkey
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	for s, want := range map[string]Hotkey{
+		"Ctrl+Alt+G":        {ModCtrl | ModAlt, 'G'},
+		"ctrl + shift + F9": {ModCtrl | ModShift, 0x78},
+		"Win+Numpad1":       {ModWin, 0x61},
+		"Alt+5":             {ModAlt, '5'},
+	} {
+		got, err := Parse(s)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "G", "Shift+G", "Ctrl+Hyper+G", "Ctrl+F25", "Ctrl+F1x", "Ctrl+"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) should fail", s)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+//go:build windows
+
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                = windows.NewLazySystemDLL("user32.dll")
+	procRegisterHotKey    = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey  = user32.NewProc("UnregisterHotKey")
+	procGetMessage        = user32.NewProc("GetMessageW")
+	procPostThreadMessage = user32.NewProc("PostThreadMessageW")
+)
+
+const (
+	wmHotkey = 0x0312
+	wmQuit   = 0x0012
+	// modNoRepeat stops a held-down combination from firing repeatedly.
+	modNoRepeat = 0x4000
+)
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// listen registers the bindings on a dedicated thread, which receives
+// their WM_HOTKEY messages, and returns a function that unregisters them.
+func listen(bindings []Binding) (func(), error) {
+	type started struct {
+		thread uint32
+		err    error
+	}
+	ready := make(chan started)
+	go func() {
+		// Hotkeys belong to the thread that registered them.
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		var errs []error
+		registered := make(map[uintptr]Binding)
+		for i, b := range bindings {
+			id := uintptr(i + 1)
+			if r, _, err := procRegisterHotKey.Call(0, id, uintptr(b.Keys.Modifiers|modNoRepeat), uintptr(b.Keys.Key)); r == 0 {
+				errs = append(errs, fmt.Errorf("registering the %s hotkey: %w", b.Name, err))
+				continue
+			}
+			registered[id] = b
+		}
+		defer func() {
+			for id := range registered {
+				procUnregisterHotKey.Call(0, id)
+			}
+		}()
+		ready <- started{windows.GetCurrentThreadId(), errors.Join(errs...)}
+
+		var m msg
+		for {
+			r, _, _ := procGetMessage.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(r) <= 0 { // WM_QUIT or an error
+				return
+			}
+			if m.message == wmHotkey {
+				if b, ok := registered[m.wParam]; ok {
+					go b.Action()
+				}
+			}
+		}
+	}()
+	s := <-ready
+	return func() { procPostThreadMessage.Call(uintptr(s.thread), wmQuit, 0, 0) }, s.err
+}
@@ -0,0 +1,193 @@
+// Package launcher starts external tools for profile hooks with a
+// per-action environment, working directory, priority and window style.
+package launcher
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"syscleaner/pkg/admin"
+)
+
+// Priority classes accepted by Action.Priority.
+const (
+	PriorityIdle        = "idle"
+	PriorityBelowNormal = "below_normal"
+	PriorityNormal      = "normal"
+	PriorityAboveNormal = "above_normal"
+	PriorityHigh        = "high"
+)
+
+// Window styles accepted by Action.Window. There is deliberately no
+// "hidden" style: hidden child processes trigger AV heuristics.
+const (
+	// WindowInherit shares SysCleaner's console, if any (the default).
+	WindowInherit = "inherit"
+	// WindowNewConsole gives console tools their own console window.
+	WindowNewConsole = "new_console"
+	// WindowDetached runs the tool with no console at all.
+	WindowDetached = "detached"
+)
+
+// Action describes one external tool launch.
+type Action struct {
+	Name    string   `json:"name"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+
+	// Env sets or overrides environment variables for this action only.
+	// Values may reference other variables as %VAR%.
+	Env map[string]string `json:"env,omitempty"`
+	Dir string            `json:"dir,omitempty"`
+
+	Priority string `json:"priority,omitempty"`
+	Window   string `json:"window,omitempty"`
+
+	// Wait blocks until the tool exits; otherwise it is left running.
+	Wait bool `json:"wait,omitempty"`
+}
+
+// Validate checks that the action has a command and known priority and
+// window values.
+func (a Action) Validate() error {
+	if strings.TrimSpace(a.Command) == "" {
+		return fmt.Errorf("action %q has no command", a.Name)
+	}
+	if _, ok := priorityClasses[strings.ToLower(a.Priority)]; !ok && a.Priority != "" {
+		return fmt.Errorf("action %q: unknown priority %q", a.Name, a.Priority)
+	}
+	if _, ok := windowFlags[strings.ToLower(a.Window)]; !ok && a.Window != "" {
+		return fmt.Errorf("action %q: unknown window style %q", a.Name, a.Window)
+	}
+	return nil
+}
+
+// Cmd builds the command for a. profileEnv is applied on top of the
+// current environment and a.Env on top of that; %VAR% references in the
+// command, arguments, directory and values are expanded against the
+// result.
+func (a Action) Cmd(profileEnv map[string]string) (*exec.Cmd, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	env := mergeEnv(os.Environ(), profileEnv, a.Env)
+	lookup := envLookup(env)
+
+	args := make([]string, len(a.Args))
+	for i, arg := range a.Args {
+		args[i] = expand(arg, lookup)
+	}
+	cmd := exec.Command(expand(a.Command, lookup), args...)
+	cmd.Env = env
+	cmd.Dir = expand(a.Dir, lookup)
+	cmd.SysProcAttr = sysProcAttr(strings.ToLower(a.Priority), strings.ToLower(a.Window))
+	return cmd, nil
+}
+
+// Run launches a, waiting for it to exit if a.Wait is set.
+func Run(a Action, profileEnv map[string]string) error {
+	cmd, err := a.Cmd(profileEnv)
+	if err != nil {
+		return err
+	}
+	if err := admin.RequireWriteAccess("launching " + a.label()); err != nil {
+		return err
+	}
+	log.Printf("[SysCleaner] Launching %s: %s", a.label(), cmd.Path)
+	if a.Wait {
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", a.label(), err)
+		}
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", a.label(), err)
+	}
+	// Reap the process in the background so it doesn't linger as a handle.
+	go cmd.Wait()
+	return nil
+}
+
+// RunAll launches each action in order. A failing action is logged and
+// the rest still run; the errors are returned together.
+func RunAll(actions []Action, profileEnv map[string]string) []error {
+	var errs []error
+	for _, a := range actions {
+		if err := Run(a, profileEnv); err != nil {
+			log.Printf("[SysCleaner] Action failed: %v", err)
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (a Action) label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Command
+}
+
+// mergeEnv applies each overlay to base in order. Variable names are
+// matched case-insensitively, as on Windows.
+func mergeEnv(base []string, overlays ...map[string]string) []string {
+	env := append([]string(nil), base...)
+	for _, overlay := range overlays {
+		for name, val := range overlay {
+			val = expand(val, envLookup(env))
+			entry := name + "=" + val
+			replaced := false
+			for i, kv := range env {
+				if k, _, ok := strings.Cut(kv, "="); ok && strings.EqualFold(k, name) {
+					env[i] = entry
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				env = append(env, entry)
+			}
+		}
+	}
+	return env
+}
+
+func envLookup(env []string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		for _, kv := range env {
+			if k, v, ok := strings.Cut(kv, "="); ok && strings.EqualFold(k, name) {
+				return v, true
+			}
+		}
+		return "", false
+	}
+}
+
+// expand replaces %VAR% references using lookup. Unknown variables are
+// left as-is.
+func expand(s string, lookup func(string) (string, bool)) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		name := s[start+1 : start+1+end]
+		b.WriteString(s[:start])
+		if val, ok := lookup(name); ok && name != "" {
+			b.WriteString(val)
+		} else {
+			b.WriteString(s[start : start+end+2])
+		}
+		s = s[start+end+2:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package launcher
+
+import "syscall"
+
+// Priority classes and window styles only change how the process is
+// created on Windows; elsewhere they are accepted and ignored.
+var priorityClasses = map[string]uint32{
+	"": 0, PriorityIdle: 0, PriorityBelowNormal: 0, PriorityNormal: 0,
+	PriorityAboveNormal: 0, PriorityHigh: 0,
+}
+
+var windowFlags = map[string]uint32{
+	"": 0, WindowInherit: 0, WindowNewConsole: 0, WindowDetached: 0,
+}
+
+func sysProcAttr(priority, window string) *syscall.SysProcAttr {
+	return nil
+}
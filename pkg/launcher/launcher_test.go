@@ -0,0 +1,71 @@
+package launcher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeEnv_OverlaysInOrder(t *testing.T) {
+	base := []string{"PATH=/bin", "Home=/home/user"}
+	profile := map[string]string{"GAME_DIR": "%HOME%/games", "MODE": "profile"}
+	action := map[string]string{"MODE": "action"}
+
+	env := mergeEnv(base, profile, action)
+	lookup := envLookup(env)
+
+	if v, _ := lookup("GAME_DIR"); v != "/home/user/games" {
+		t.Errorf("GAME_DIR = %q, want %%HOME%% expanded case-insensitively", v)
+	}
+	if v, _ := lookup("MODE"); v != "action" {
+		t.Errorf("MODE = %q, want the action value to win", v)
+	}
+	if len(env) != 4 {
+		t.Errorf("env = %v, want overrides replaced rather than appended", env)
+	}
+}
+
+func TestExpand_LeavesUnknownVariables(t *testing.T) {
+	lookup := envLookup([]string{"A=1"})
+	if got := expand("%A%-%B%-50%", lookup); got != "1-%B%-50%" {
+		t.Errorf("expand = %q", got)
+	}
+}
+
+func TestAction_Validate(t *testing.T) {
+	tests := []struct {
+		action Action
+		ok     bool
+	}{
+		{Action{Command: "obs64.exe"}, true},
+		{Action{Command: "tool.exe", Priority: "High", Window: WindowNewConsole}, true},
+		{Action{Name: "empty"}, false},
+		{Action{Command: "tool.exe", Priority: "realtime"}, false},
+		{Action{Command: "tool.exe", Window: "hidden"}, false},
+	}
+	for _, tt := range tests {
+		err := tt.action.Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", tt.action, err, tt.ok)
+		}
+	}
+}
+
+func TestAction_Cmd(t *testing.T) {
+	t.Setenv("TOOLS", "/opt/tools")
+	a := Action{
+		Command: "%TOOLS%/bin/tool",
+		Args:    []string{"--dir", "%WORK%"},
+		Dir:     "%WORK%",
+		Env:     map[string]string{"WORK": "%TOOLS%/work"},
+	}
+	cmd, err := a.Cmd(nil)
+	if err != nil {
+		t.Fatalf("Cmd: %v", err)
+	}
+	if !strings.HasPrefix(cmd.Path, "/opt/tools/bin/tool") {
+		t.Errorf("Path = %q", cmd.Path)
+	}
+	if cmd.Args[2] != "/opt/tools/work" || cmd.Dir != "/opt/tools/work" {
+		t.Errorf("Args = %v, Dir = %q, want %%WORK%% expanded", cmd.Args, cmd.Dir)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package launcher
+
+import "syscall"
+
+var priorityClasses = map[string]uint32{
+	"":                  0,
+	PriorityIdle:        0x00000040, // IDLE_PRIORITY_CLASS
+	PriorityBelowNormal: 0x00004000, // BELOW_NORMAL_PRIORITY_CLASS
+	PriorityNormal:      0x00000020, // NORMAL_PRIORITY_CLASS
+	PriorityAboveNormal: 0x00008000, // ABOVE_NORMAL_PRIORITY_CLASS
+	PriorityHigh:        0x00000080, // HIGH_PRIORITY_CLASS
+}
+
+var windowFlags = map[string]uint32{
+	"":               0,
+	WindowInherit:    0,
+	WindowNewConsole: 0x00000010, // CREATE_NEW_CONSOLE
+	WindowDetached:   0x00000008, // DETACHED_PROCESS
+}
+
+func sysProcAttr(priority, window string) *syscall.SysProcAttr {
+	// NOTE: Do NOT set HideWindow: true — it triggers AV heuristics.
+	return &syscall.SysProcAttr{
+		CreationFlags: priorityClasses[priority] | windowFlags[window],
+	}
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Audit actions.
+const (
+	AuditDelete      = "delete"       // a batch of files deleted or quarantined
+	AuditRegistry    = "registry"     // a registry value set or deleted
+	AuditServiceStop = "service-stop" // a Windows service stopped
+	AuditPriority    = "priority"     // a process's priority class changed
+)
+
+// AuditEntry is one destructive action in the audit log.
+//
+// Undo is a token describing how to revert the action, or "" when it cannot
+// be reverted (files deleted without quarantine):
+//
+//	quarantine:<dir>        restore the files with "syscleaner quarantine --restore"
+//	reg:<key>\<name>=<data> the .reg assignment that restores the old value
+//	service-start:<name>    start the service again
+//	priority:<pid>=<nice>   the process's priority before the change
+type AuditEntry struct {
+	Time        time.Time `json:"time"`
+	OperationID string    `json:"operation_id,omitempty"`
+	Action      string    `json:"action"`
+	Target      string    `json:"target"`
+	Detail      string    `json:"detail,omitempty"`
+	Undo        string    `json:"undo,omitempty"`
+}
+
+var (
+	auditMu   sync.Mutex
+	auditPath string
+)
+
+// DefaultAuditPath returns <user config dir>/SysCleaner/audit.jsonl, next
+// to the log file.
+func DefaultAuditPath() string {
+	return filepath.Join(filepath.Dir(DefaultLogPath()), "audit.jsonl")
+}
+
+// SetAuditPath changes the file Audit appends to. An empty path restores
+// DefaultAuditPath.
+func SetAuditPath(path string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditPath = path
+}
+
+// AuditPath returns the file Audit appends to.
+func AuditPath() string {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditPath == "" {
+		return DefaultAuditPath()
+	}
+	return auditPath
+}
+
+// Audit appends an entry for a destructive action to the audit log,
+// tagged with the running operation's ID. The audit log is separate from
+// the normal log, is never rotated or truncated by SysCleaner, and is only
+// ever appended to. Failures are logged, not returned, so callers can
+// audit unconditionally after the action succeeds.
+func Audit(action, target, detail, undo string) {
+	e := AuditEntry{
+		Time:        time.Now(),
+		OperationID: CurrentOperationID(),
+		Action:      action,
+		Target:      target,
+		Detail:      detail,
+		Undo:        undo,
+	}
+	if err := appendAudit(AuditPath(), e); err != nil {
+		log.Printf("[SysCleaner] Could not write audit log: %v", err)
+	}
+}
+
+func appendAudit(path string, e AuditEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// AuditFilter selects audit entries. Zero fields match everything.
+type AuditFilter struct {
+	Action      string
+	OperationID string
+	Since       time.Time
+	Grep        string // case-insensitive substring of the target or detail
+}
+
+// Match reports whether e passes the filter.
+func (f AuditFilter) Match(e AuditEntry) bool {
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	if f.OperationID != "" && e.OperationID != f.OperationID {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Grep != "" {
+		grep := strings.ToLower(f.Grep)
+		if !strings.Contains(strings.ToLower(e.Target), grep) && !strings.Contains(strings.ToLower(e.Detail), grep) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadAudit returns the entries in the audit log at path that pass f,
+// oldest first. A missing file has no entries.
+func ReadAudit(path string, f AuditFilter) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e AuditEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return entries, fmt.Errorf("logger: %s line %d: %w", filepath.Base(path), n, err)
+		}
+		if f.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
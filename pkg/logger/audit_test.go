@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudit_AppendsAndFilters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	SetAuditPath(path)
+	defer SetAuditPath("")
+
+	op := StartOperation("clean")
+	Audit(AuditDelete, "User Temp", "12 files, 3.0 MB", "")
+	op.End()
+	Audit(AuditServiceStop, "SysMain", "", "service-start:SysMain")
+
+	all, err := ReadAudit(path, AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d entries, want 2", len(all))
+	}
+	if all[0].OperationID != op.ID || all[1].OperationID != "" {
+		t.Errorf("operation IDs = %q, %q; want %q, \"\"", all[0].OperationID, all[1].OperationID, op.ID)
+	}
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   string
+	}{
+		{"action", AuditFilter{Action: AuditServiceStop}, "SysMain"},
+		{"operation", AuditFilter{OperationID: op.ID}, "User Temp"},
+		{"grep", AuditFilter{Grep: "12 FILES"}, "User Temp"},
+	}
+	for _, tt := range tests {
+		got, err := ReadAudit(path, tt.filter)
+		if err != nil || len(got) != 1 || got[0].Target != tt.want {
+			t.Errorf("%s: got %+v, %v; want one entry for %s", tt.name, got, err, tt.want)
+		}
+	}
+	if got, _ := ReadAudit(path, AuditFilter{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Errorf("since filter kept %d entries", len(got))
+	}
+
+	// Entries are appended, never rewritten.
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Errorf("audit file has %d lines, want 2", n)
+	}
+}
+
+func TestReadAudit_MissingFile(t *testing.T) {
+	entries, err := ReadAudit(filepath.Join(t.TempDir(), "none.jsonl"), AuditFilter{})
+	if err != nil || entries != nil {
+		t.Errorf("ReadAudit = %v, %v; want nil, nil", entries, err)
+	}
+}
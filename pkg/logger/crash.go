@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recentLineCount is how many recent log lines a crash report includes.
+const recentLineCount = 200
+
+// recent holds the last log lines written through a Logger or the
+// standard log package (see CaptureStandardLog), for crash reports.
+var recent = &lineRing{max: recentLineCount}
+
+type lineRing struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+}
+
+func (r *lineRing) add(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, line)
+	if len(r.lines) > r.max {
+		r.lines = r.lines[len(r.lines)-r.max:]
+	}
+}
+
+func (r *lineRing) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+// Write records each line of p.
+func (r *lineRing) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.add(line)
+	}
+	return len(p), nil
+}
+
+// RecentLines returns the last log lines written, oldest first.
+func RecentLines() []string {
+	return recent.snapshot()
+}
+
+// CaptureStandardLog points the standard log package at w, keeping the
+// recent lines for crash reports as well and applying redaction (see
+// SetRedaction). Use it instead of log.SetOutput.
+func CaptureStandardLog(w io.Writer) {
+	log.SetOutput(redactWriter{io.MultiWriter(w, recent)})
+}
+
+// CrashDir returns the directory crash reports are written to, next to
+// the default log file.
+func CrashDir() string {
+	return filepath.Join(filepath.Dir(DefaultLogPath()), "crashes")
+}
+
+// pendingCrashFile names the file in CrashDir holding the path of a crash
+// report the user has not seen yet.
+const pendingCrashFile = "pending"
+
+// WriteCrashReport writes a crash report for the panic value v with the
+// given stack trace, plus the recent log lines, to a new file in CrashDir
+// and marks it as pending (see PendingCrashReport). It returns the file's
+// path.
+func WriteCrashReport(v any, stack []byte) (string, error) {
+	dir := CrashDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("logger: create crash directory %s: %w", dir, err)
+	}
+	now := time.Now()
+	path := filepath.Join(dir, "crash-"+now.Format("20060102-150405")+".log")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "SysCleaner crashed at %s\n", now.Format(timestampLayout))
+	fmt.Fprintf(&b, "Go %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if id := CurrentOperationID(); id != "" {
+		fmt.Fprintf(&b, "Operation: %s\n", id)
+	}
+	fmt.Fprintf(&b, "\npanic: %v\n\n%s\n", v, stack)
+	lines := RecentLines()
+	fmt.Fprintf(&b, "--- last %d log lines ---\n", len(lines))
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+
+	if err := os.WriteFile(path, []byte(Redact(b.String())), 0o644); err != nil {
+		return "", fmt.Errorf("logger: write crash report: %w", err)
+	}
+	_ = os.WriteFile(filepath.Join(dir, pendingCrashFile), []byte(path), 0o644)
+	return path, nil
+}
+
+// Recover writes a crash report if the calling goroutine is panicking,
+// then exits the process. Defer it first thing in main and in each
+// goroutine that should not take the process down silently:
+//
+//	defer logger.Recover()
+func Recover() {
+	v := recover()
+	if v == nil {
+		return
+	}
+	stack := debug.Stack()
+	path, err := WriteCrashReport(v, stack)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "panic: %v\n\n%s\n(could not save crash report: %v)\n", v, stack, err)
+	} else {
+		fmt.Fprintf(os.Stderr, "panic: %v\n\nSysCleaner crashed. The crash report was saved to %s\n", v, path)
+	}
+	os.Exit(2)
+}
+
+// Go runs fn in a new goroutine guarded by Recover.
+func Go(fn func()) {
+	go func() {
+		defer Recover()
+		fn()
+	}()
+}
+
+// PendingCrashReport returns the crash report written since the last call
+// to AcknowledgeCrashReport, if there is one and it still exists.
+func PendingCrashReport() (string, bool) {
+	data, err := os.ReadFile(filepath.Join(CrashDir(), pendingCrashFile))
+	if err != nil {
+		return "", false
+	}
+	path := strings.TrimSpace(string(data))
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// AcknowledgeCrashReport clears the pending crash report, so it is only
+// offered once.
+func AcknowledgeCrashReport() {
+	_ = os.Remove(filepath.Join(CrashDir(), pendingCrashFile))
+}
+
+// CrashReports returns the saved crash reports, newest first.
+func CrashReports() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(CrashDir(), "crash-*.log"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteCrashReport(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	l, err := New(LevelInfo, filepath.Join(t.TempDir(), "syscleaner.log"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.Info("about to crash")
+
+	if _, ok := PendingCrashReport(); ok {
+		t.Fatal("pending crash report before any crash")
+	}
+	path, err := WriteCrashReport("boom", []byte("goroutine 1 [running]:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"panic: boom", "goroutine 1 [running]:", "[INFO] about to crash"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("crash report missing %q:\n%s", want, data)
+		}
+	}
+
+	if pending, ok := PendingCrashReport(); !ok || pending != path {
+		t.Errorf("PendingCrashReport = %q, %v; want %q", pending, ok, path)
+	}
+	AcknowledgeCrashReport()
+	if _, ok := PendingCrashReport(); ok {
+		t.Error("crash report still pending after AcknowledgeCrashReport")
+	}
+	reports, err := CrashReports()
+	if err != nil || len(reports) != 1 || reports[0] != path {
+		t.Errorf("CrashReports = %v, %v; want [%s]", reports, err, path)
+	}
+}
@@ -1,10 +1,12 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -35,6 +37,21 @@ func (ll LogLevel) String() string {
 	}
 }
 
+// ParseLevel converts a level name such as "debug" or "WARN" to a LogLevel.
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
 // Logger provides structured, leveled logging to a file and optionally to the
 // console (stdout). All writes are serialised with a mutex so the logger is
 // safe for concurrent use.
@@ -45,6 +62,19 @@ type Logger struct {
 	mu      sync.Mutex
 }
 
+// fallbackLevel filters the package-level functions while DefaultLogger is
+// nil.
+var fallbackLevel = LevelInfo
+
+// SetLevel sets the minimum level logged by the package-level functions,
+// including DefaultLogger if one is set.
+func SetLevel(level LogLevel) {
+	fallbackLevel = level
+	if DefaultLogger != nil {
+		DefaultLogger.SetLevel(level)
+	}
+}
+
 // DefaultLogger is the package-level logger instance that the convenience
 // functions (Debug, Info, Warn, Error) delegate to.  Set it with SetDefault.
 var DefaultLogger *Logger
@@ -89,6 +119,13 @@ func New(level LogLevel, logPath string, console bool) (*Logger, error) {
 	}, nil
 }
 
+// SetLevel changes the minimum level l writes.
+func (l *Logger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
 // Close closes the underlying log file.
 func (l *Logger) Close() error {
 	l.mu.Lock()
@@ -99,18 +136,24 @@ func (l *Logger) Close() error {
 // log is the internal method that formats and writes a single log line.  Lines
 // that are below the configured level are silently discarded.
 //
-// Format: [2006-01-02 15:04:05] [LEVEL] message
+// Format: [2006-01-02 15:04:05] [LEVEL] message, with the message starting
+// with [op=ID] while an operation is running (see StartOperation).
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	if level < l.level {
 		return
 	}
 
-	msg := fmt.Sprintf(format, args...)
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	line := fmt.Sprintf("[%s] [%s] %s\n", timestamp, level.String(), msg)
-
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	msg := Redact(fmt.Sprintf(format, args...))
+	if id := CurrentOperationID(); id != "" {
+		msg = "[op=" + id + "] " + msg
+	}
+	now := time.Now()
+	line := fmt.Sprintf("[%s] [%s] %s\n", now.Format(timestampLayout), level.String(), msg)
+	ship(Entry{Time: now, Level: level, Message: msg, Line: strings.TrimSuffix(line, "\n")})
+	recent.add(strings.TrimSuffix(line, "\n"))
 
 	// Write to the log file.
 	_, _ = l.file.WriteString(line)
@@ -142,43 +185,43 @@ func (l *Logger) Error(format string, args ...interface{}) {
 }
 
 // ---------------------------------------------------------------------------
-// Package-level convenience functions — these delegate to DefaultLogger.
-// If DefaultLogger is nil the message is routed through the standard "log"
-// package so that callers never silently lose log output.
+// Package-level convenience functions — these delegate to the slog.Logger
+// set with SetSlogLogger, or else DefaultLogger. If neither is set the
+// message is routed through the standard "log" package so that callers
+// never silently lose log output.
 // ---------------------------------------------------------------------------
 
-// Debug logs a message at Debug level using the DefaultLogger.
-func Debug(format string, args ...interface{}) {
+// logAt writes one message for the package-level functions.
+func logAt(level LogLevel, format string, args ...interface{}) {
+	if s := slogLogger(); s != nil {
+		s.Log(context.Background(), toSlogLevel(level), Redact(fmt.Sprintf(format, args...)))
+		return
+	}
 	if DefaultLogger != nil {
-		DefaultLogger.Debug(format, args...)
+		DefaultLogger.log(level, format, args...)
 		return
 	}
-	log.Printf("[DEBUG] "+format, args...)
+	if level >= fallbackLevel {
+		log.Printf("["+level.String()+"] "+format, args...)
+	}
+}
+
+// Debug logs a message at Debug level using the DefaultLogger.
+func Debug(format string, args ...interface{}) {
+	logAt(LevelDebug, format, args...)
 }
 
 // Info logs a message at Info level using the DefaultLogger.
 func Info(format string, args ...interface{}) {
-	if DefaultLogger != nil {
-		DefaultLogger.Info(format, args...)
-		return
-	}
-	log.Printf("[INFO] "+format, args...)
+	logAt(LevelInfo, format, args...)
 }
 
 // Warn logs a message at Warn level using the DefaultLogger.
 func Warn(format string, args ...interface{}) {
-	if DefaultLogger != nil {
-		DefaultLogger.Warn(format, args...)
-		return
-	}
-	log.Printf("[WARN] "+format, args...)
+	logAt(LevelWarn, format, args...)
 }
 
 // Error logs a message at Error level using the DefaultLogger.
 func Error(format string, args ...interface{}) {
-	if DefaultLogger != nil {
-		DefaultLogger.Error(format, args...)
-		return
-	}
-	log.Printf("[ERROR] "+format, args...)
+	logAt(LevelError, format, args...)
 }
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Operation is one clean, optimize or boost run. While it runs, its ID is
+// added to every log line, and the run's result carries the same ID, so a
+// report attached to a support ticket can be matched to its log segment.
+type Operation struct {
+	ID      string
+	Kind    string
+	Started time.Time
+}
+
+var (
+	opMu sync.Mutex
+	// ops are the running operations, oldest first.
+	ops []*Operation
+	// stdPrefix and stdFlags are the standard log settings from before
+	// the first operation started.
+	stdPrefix string
+	stdFlags  int
+)
+
+// StartOperation begins an operation of the given kind ("clean",
+// "optimize-network", ...) and returns it; call End when it finishes. IDs
+// look like clean-20240301-101500-3f9a.
+//
+// When operations overlap, log lines written through the standard log
+// package carry the ID of the newest one, since that package has a single
+// prefix.
+func StartOperation(kind string) *Operation {
+	now := time.Now()
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	op := &Operation{
+		ID:      fmt.Sprintf("%s-%s-%s", kind, now.Format("20060102-150405"), hex.EncodeToString(suffix)),
+		Kind:    kind,
+		Started: now,
+	}
+
+	opMu.Lock()
+	if len(ops) == 0 {
+		stdPrefix, stdFlags = log.Prefix(), log.Flags()
+		log.SetFlags(stdFlags | log.Lmsgprefix)
+	}
+	ops = append(ops, op)
+	setStdPrefix()
+	opMu.Unlock()
+
+	log.Printf("[SysCleaner] Operation %s started", op.ID)
+	return op
+}
+
+// End marks the operation finished.
+func (o *Operation) End() {
+	log.Printf("[SysCleaner] Operation %s finished after %s", o.ID, time.Since(o.Started).Round(time.Millisecond))
+
+	opMu.Lock()
+	defer opMu.Unlock()
+	for i, op := range ops {
+		if op == o {
+			ops = append(ops[:i], ops[i+1:]...)
+			break
+		}
+	}
+	setStdPrefix()
+}
+
+// CurrentOperationID returns the ID of the newest running operation, or ""
+// if none is running.
+func CurrentOperationID() string {
+	opMu.Lock()
+	defer opMu.Unlock()
+	if len(ops) == 0 {
+		return ""
+	}
+	return ops[len(ops)-1].ID
+}
+
+// setStdPrefix points the standard log prefix at the newest operation.
+// opMu must be held.
+func setStdPrefix() {
+	if len(ops) == 0 {
+		log.SetPrefix(stdPrefix)
+		log.SetFlags(stdFlags)
+		return
+	}
+	log.SetPrefix(stdPrefix + "[op=" + ops[len(ops)-1].ID + "] ")
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestStartOperation_TagsLogLines(t *testing.T) {
+	var buf bytes.Buffer
+	flags, out, prefix := log.Flags(), log.Writer(), log.Prefix()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetFlags(flags)
+		log.SetOutput(out)
+		log.SetPrefix(prefix)
+	}()
+
+	path := filepath.Join(t.TempDir(), "syscleaner.log")
+	l, err := New(LevelInfo, path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	op := StartOperation("clean")
+	if !regexp.MustCompile(`^clean-\d{8}-\d{6}-[0-9a-f]{4}$`).MatchString(op.ID) {
+		t.Errorf("ID = %q", op.ID)
+	}
+	if CurrentOperationID() != op.ID {
+		t.Errorf("CurrentOperationID = %q, want %q", CurrentOperationID(), op.ID)
+	}
+	log.Printf("[SysCleaner] Cleaning User Temp...")
+	l.Info("deleted %d files", 3)
+	op.End()
+	log.Printf("[SysCleaner] after")
+
+	if CurrentOperationID() != "" {
+		t.Error("operation still current after End")
+	}
+	std := buf.String()
+	if !strings.Contains(std, "[op="+op.ID+"] [SysCleaner] Cleaning User Temp...") {
+		t.Errorf("standard log = %q", std)
+	}
+	if !strings.HasSuffix(std, "\n[SysCleaner] after\n") {
+		t.Errorf("prefix not removed after End: %q", std)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "[INFO] [op="+op.ID+"] deleted 3 files") {
+		t.Errorf("log file = %q", data)
+	}
+}
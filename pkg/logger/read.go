@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// timestampLayout is the layout of the timestamp that starts each line.
+const timestampLayout = "2006-01-02 15:04:05"
+
+// Entry is one line read back from a log file.
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	// Line is the line as written, without the trailing newline.
+	Line string
+}
+
+// ParseLine splits a line written by Logger into an Entry. ok is false for
+// lines in another format; Line is still set, so callers can show them.
+func ParseLine(line string) (e Entry, ok bool) {
+	e.Line = line
+	// [2006-01-02 15:04:05] [LEVEL] message
+	if len(line) < len(timestampLayout)+2 || line[0] != '[' || line[len(timestampLayout)+1] != ']' {
+		return e, false
+	}
+	t, err := time.ParseInLocation(timestampLayout, line[1:len(timestampLayout)+1], time.Local)
+	if err != nil {
+		return e, false
+	}
+	rest := strings.TrimPrefix(line[len(timestampLayout)+2:], " ")
+	end := strings.IndexByte(rest, ']')
+	if !strings.HasPrefix(rest, "[") || end < 0 {
+		return e, false
+	}
+	level, err := ParseLevel(rest[1:end])
+	if err != nil {
+		return e, false
+	}
+	e.Time, e.Level, e.Message = t, level, strings.TrimPrefix(rest[end+1:], " ")
+	return e, true
+}
+
+// Filter selects log entries. The zero Filter matches every line.
+type Filter struct {
+	MinLevel LogLevel
+	// Since, if set, drops entries written before it.
+	Since time.Time
+	// Grep, if set, must match the line.
+	Grep *regexp.Regexp
+}
+
+// Match reports whether e passes f. Lines that did not parse only have to
+// match Grep, so continuation lines of a message are not lost.
+func (f Filter) Match(e Entry, parsed bool) bool {
+	if f.Grep != nil && !f.Grep.MatchString(e.Line) {
+		return false
+	}
+	if !parsed {
+		return f.MinLevel == LevelDebug && f.Since.IsZero()
+	}
+	return e.Level >= f.MinLevel && !e.Time.Before(f.Since)
+}
+
+// Tail returns the last n entries of the log file at path that match f, or
+// all of them if n <= 0, together with the file size, from which Follow can
+// carry on.
+func Tail(path string, n int, f Filter) ([]Entry, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("logger: open log file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var out []Entry
+	r := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			offset += int64(len(line))
+			if e, ok := ParseLine(strings.TrimRight(line, "\r\n")); f.Match(e, ok) {
+				out = append(out, e)
+				if n > 0 && len(out) > n {
+					out = out[1:]
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("logger: read log file %s: %w", path, err)
+		}
+	}
+	return out, offset, nil
+}
+
+// FollowInterval is how often Follow checks the log file for new lines.
+var FollowInterval = 500 * time.Millisecond
+
+// Follow calls fn for each matching line appended to the log file at path
+// after offset, until ctx is done. If the file shrinks (it was truncated or
+// replaced) reading starts again from the beginning. A partly written last
+// line is held back until it is complete.
+func Follow(ctx context.Context, path string, offset int64, f Filter, fn func(Entry)) error {
+	ticker := time.NewTicker(FollowInterval)
+	defer ticker.Stop()
+	var partial string
+	for {
+		if info, err := os.Stat(path); err == nil {
+			if info.Size() < offset {
+				offset, partial = 0, ""
+			}
+			if info.Size() > offset {
+				data, err := readFrom(path, offset)
+				if err != nil {
+					return err
+				}
+				offset += int64(len(data))
+				lines := strings.Split(partial+string(data), "\n")
+				partial = lines[len(lines)-1]
+				for _, line := range lines[:len(lines)-1] {
+					if e, ok := ParseLine(strings.TrimRight(line, "\r")); f.Match(e, ok) {
+						fn(e)
+					}
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("logger: open log file %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("logger: read log file %s: %w", path, err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("logger: read log file %s: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	e, ok := ParseLine("[2024-03-01 10:15:00] [WARN] Low disk on C:")
+	if !ok {
+		t.Fatal("line did not parse")
+	}
+	want := time.Date(2024, 3, 1, 10, 15, 0, 0, time.Local)
+	if !e.Time.Equal(want) || e.Level != LevelWarn || e.Message != "Low disk on C:" {
+		t.Errorf("entry = %+v", e)
+	}
+	if _, ok := ParseLine("2024/03/01 10:15:00 [SysCleaner] plain log line"); ok {
+		t.Error("standard log line should not parse")
+	}
+}
+
+func TestTailAndFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syscleaner.log")
+	content := "[2024-03-01 10:00:00] [DEBUG] starting\n" +
+		"[2024-03-01 10:05:00] [INFO] cleaned 12 MB\n" +
+		"[2024-03-01 10:10:00] [ERROR] access denied: C:\\Windows\\Temp\n" +
+		"[2024-03-01 10:20:00] [WARN] low disk\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, offset, err := Tail(path, 0, Filter{MinLevel: LevelInfo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || offset != int64(len(content)) {
+		t.Fatalf("got %d entries at offset %d", len(entries), offset)
+	}
+	entries, _, _ = Tail(path, 1, Filter{})
+	if len(entries) != 1 || entries[0].Message != "low disk" {
+		t.Errorf("last entry = %+v", entries)
+	}
+	since := time.Date(2024, 3, 1, 10, 6, 0, 0, time.Local)
+	entries, _, _ = Tail(path, 0, Filter{Since: since, Grep: regexp.MustCompile("(?i)denied")})
+	if len(entries) != 1 || entries[0].Level != LevelError {
+		t.Errorf("filtered entries = %+v", entries)
+	}
+
+	orig := FollowInterval
+	FollowInterval = 10 * time.Millisecond
+	defer func() { FollowInterval = orig }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	got := make(chan Entry, 4)
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, offset, Filter{MinLevel: LevelWarn}, func(e Entry) { got <- e })
+	}()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("[2024-03-01 10:30:00] [INFO] skipped\n[2024-03-01 10:31:00] [ERROR] fail")
+	time.Sleep(50 * time.Millisecond)
+	f.WriteString("ed\n")
+	f.Close()
+
+	select {
+	case e := <-got:
+		if e.Message != "failed" {
+			t.Errorf("followed entry = %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no entry followed")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow: %v", err)
+	}
+}
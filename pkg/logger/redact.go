@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// userPlaceholder replaces user names in redacted text.
+const userPlaceholder = "%USER%"
+
+// profilePattern matches the user name in a profile path such as
+// C:\Users\alice, including the escaped backslashes found in JSON.
+var profilePattern = regexp.MustCompile(`(?i)\b([a-z]:\\{1,2}users\\{1,2})([^\\/:*?"'<>|\s]+)`)
+
+// sharedProfiles are folders under C:\Users that do not belong to a user.
+var sharedProfiles = map[string]bool{
+	"public":    true,
+	"default":   true,
+	"all users": true,
+}
+
+// redactor removes the current user's identity from text.
+type redactor struct {
+	home string
+	user *regexp.Regexp
+}
+
+// redaction is the active redactor, or nil when redaction is off.
+var redaction atomic.Pointer[redactor]
+
+// SetRedaction turns privacy redaction on or off. While it is on, user
+// names and profile paths (C:\Users\alice becomes C:\Users\%USER%) are
+// removed from log lines, crash reports and exported clean reports, so
+// they can be shared publicly.
+func SetRedaction(on bool) {
+	if !on {
+		redaction.Store(nil)
+		return
+	}
+	r := &redactor{}
+	r.home, _ = os.UserHomeDir()
+	if u, err := user.Current(); err == nil {
+		name := u.Username
+		if i := strings.LastIndexAny(name, `\/`); i >= 0 {
+			name = name[i+1:]
+		}
+		// Very short names would match too much unrelated text.
+		if len(name) >= 3 {
+			r.user = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name) + `\b`)
+		}
+	}
+	redaction.Store(r)
+}
+
+// Redacting reports whether privacy redaction is on.
+func Redacting() bool {
+	return redaction.Load() != nil
+}
+
+// Redact returns s with user names and profile paths replaced, if
+// redaction is on, or s unchanged.
+func Redact(s string) string {
+	r := redaction.Load()
+	if r == nil {
+		return s
+	}
+	s = profilePattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := profilePattern.FindStringSubmatch(m)
+		if sharedProfiles[strings.ToLower(parts[2])] {
+			return m
+		}
+		return parts[1] + userPlaceholder
+	})
+	// Profiles outside C:\Users, such as roaming or redirected ones.
+	if r.home != "" {
+		redacted := userPlaceholder
+		if i := strings.LastIndexAny(r.home, `\/`); i >= 0 {
+			redacted = r.home[:i+1] + userPlaceholder
+		}
+		s = replaceFold(s, r.home, redacted)
+		s = replaceFold(s, strings.ReplaceAll(r.home, `\`, `\\`), strings.ReplaceAll(redacted, `\`, `\\`))
+	}
+	if r.user != nil {
+		s = r.user.ReplaceAllLiteralString(s, userPlaceholder)
+	}
+	return s
+}
+
+// replaceFold replaces every case-insensitive occurrence of old in s.
+func replaceFold(s, old, repl string) string {
+	if old == "" || !strings.Contains(strings.ToLower(s), strings.ToLower(old)) {
+		return s
+	}
+	return regexp.MustCompile(`(?i)`+regexp.QuoteMeta(old)).ReplaceAllLiteralString(s, repl)
+}
+
+// redactWriter redacts everything written through it.
+type redactWriter struct{ w io.Writer }
+
+func (w redactWriter) Write(p []byte) (int, error) {
+	if !Redacting() {
+		return w.w.Write(p)
+	}
+	if _, err := io.WriteString(w.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	redaction.Store(&redactor{home: `D:\Profiles\alice`, user: regexp.MustCompile(`(?i)\balice\b`)})
+	defer SetRedaction(false)
+
+	tests := []struct{ in, want string }{
+		{`Deleting C:\Users\bob\AppData\Local\Temp\x.tmp`, `Deleting C:\Users\%USER%\AppData\Local\Temp\x.tmp`},
+		{`{"path": "C:\\Users\\Bob\\file"}`, `{"path": "C:\\Users\\%USER%\\file"}`},
+		{`C:\Users\Public\Desktop`, `C:\Users\Public\Desktop`},
+		{`D:\Profiles\Alice\Documents`, `D:\Profiles\%USER%\Documents`},
+		{`signed in as ALICE`, `signed in as %USER%`},
+		{`malice is not a name`, `malice is not a name`},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	SetRedaction(false)
+	if in := `C:\Users\bob\x`; Redact(in) != in {
+		t.Errorf("Redact with redaction off changed %q", in)
+	}
+}
+
+func TestLogger_Redacts(t *testing.T) {
+	redaction.Store(&redactor{})
+	defer SetRedaction(false)
+
+	path := filepath.Join(t.TempDir(), "syscleaner.log")
+	l, err := New(LevelInfo, path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info(`locked: C:\Users\bob\ntuser.dat`)
+	l.Close()
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `locked: C:\Users\%USER%\ntuser.dat`) || strings.Contains(string(data), "bob") {
+		t.Errorf("log file = %q", data)
+	}
+}
@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink receives batches of log entries, e.g. a remote log collector.
+type Sink interface {
+	Send(entries []Entry) error
+}
+
+// Shipper batches log entries and forwards them to a Sink in the
+// background. A batch that fails to send is retried, with a growing delay,
+// until it succeeds; while the sink is down new entries are buffered up to
+// MaxBuffered and the oldest are dropped after that.
+//
+// Set the fields before calling Start.
+type Shipper struct {
+	Sink          Sink
+	BatchSize     int           // entries per Send; a full batch is sent at once
+	FlushInterval time.Duration // longest an entry waits before being sent
+	MaxBuffered   int
+	// RetryDelay is the first wait after a failed Send; it doubles up to
+	// MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	mu      sync.Mutex
+	buf     []Entry
+	dropped int
+	trimmed int // entries dropped from the front since next
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewShipper returns a Shipper for sink with the default batching and
+// retry settings.
+func NewShipper(sink Sink) *Shipper {
+	return &Shipper{
+		Sink:          sink,
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+		MaxBuffered:   10000,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: time.Minute,
+	}
+}
+
+// Start begins forwarding entries.
+func (s *Shipper) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return
+	}
+	s.wake = make(chan struct{}, 1)
+	s.done = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.run(s.done, s.stopped)
+}
+
+// Close sends what is buffered, making one attempt, and stops the
+// Shipper.
+func (s *Shipper) Close() error {
+	s.mu.Lock()
+	done, stopped := s.done, s.stopped
+	s.done = nil
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	close(done)
+	<-stopped
+
+	for {
+		batch := s.next()
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.Sink.Send(batch); err != nil {
+			return fmt.Errorf("logger: shipping buffered log entries: %w", err)
+		}
+		s.remove(len(batch))
+	}
+}
+
+// Add queues e for sending.
+func (s *Shipper) Add(e Entry) {
+	s.mu.Lock()
+	s.buf = append(s.buf, e)
+	if s.MaxBuffered > 0 && len(s.buf) > s.MaxBuffered {
+		n := len(s.buf) - s.MaxBuffered
+		s.dropped += n
+		s.trimmed += n
+		s.buf = s.buf[n:]
+	}
+	full := s.BatchSize > 0 && len(s.buf) >= s.BatchSize
+	wake := s.wake
+	s.mu.Unlock()
+	if full && wake != nil {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Write queues each line of p as an Info entry, or at the level named by
+// a "[WARN]"-style tag in it, so the standard log package can be pointed at
+// a Shipper.
+func (s *Shipper) Write(p []byte) (int, error) {
+	now := time.Now()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		s.Add(Entry{Time: now, Level: lineLevel(line), Message: line, Line: line})
+	}
+	return len(p), nil
+}
+
+// lineLevel returns the level named by a "[WARN]"-style tag in a line
+// written through the standard log package, or Info if there is none.
+func lineLevel(line string) LogLevel {
+	for _, level := range []LogLevel{LevelDebug, LevelWarn, LevelError} {
+		if strings.Contains(line, "["+level.String()+"]") {
+			return level
+		}
+	}
+	return LevelInfo
+}
+
+func (s *Shipper) run(done, stopped chan struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+	delay := s.RetryDelay
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		for {
+			batch := s.next()
+			if len(batch) == 0 {
+				break
+			}
+			if err := s.Sink.Send(batch); err != nil {
+				// Not logged through the standard log package, which may
+				// itself be feeding this Shipper.
+				fmt.Fprintf(os.Stderr, "[SysCleaner] Log shipping failed, retrying in %s: %v\n", delay, err)
+				select {
+				case <-done:
+					return
+				case <-time.After(delay):
+				}
+				if delay *= 2; delay > s.MaxRetryDelay {
+					delay = s.MaxRetryDelay
+				}
+				continue
+			}
+			delay = s.RetryDelay
+			s.remove(len(batch))
+		}
+	}
+}
+
+// next returns up to BatchSize buffered entries without removing them. A
+// note about dropped entries is put first.
+func (s *Shipper) next() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dropped > 0 {
+		msg := fmt.Sprintf("[SysCleaner] Log shipping dropped %d entries while the sink was unavailable", s.dropped)
+		s.buf = append([]Entry{{Time: time.Now(), Level: LevelWarn, Message: msg, Line: msg}}, s.buf...)
+		s.dropped = 0
+	}
+	s.trimmed = 0
+	n := len(s.buf)
+	if s.BatchSize > 0 && n > s.BatchSize {
+		n = s.BatchSize
+	}
+	return append([]Entry(nil), s.buf[:n]...)
+}
+
+// remove drops the n entries returned by next, less any that Add has
+// already dropped to stay within MaxBuffered.
+func (s *Shipper) remove(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n -= s.trimmed
+	s.trimmed = 0
+	if n > len(s.buf) {
+		n = len(s.buf)
+	}
+	if n > 0 {
+		s.buf = s.buf[n:]
+	}
+}
+
+// shipper receives every entry written by a Logger, if set.
+var (
+	shipperMu sync.Mutex
+	shipper   *Shipper
+)
+
+// SetShipper sends the entries written by every Logger to s, replacing and
+// closing the previous Shipper. Pass nil to stop shipping.
+func SetShipper(s *Shipper) error {
+	shipperMu.Lock()
+	old := shipper
+	shipper = s
+	shipperMu.Unlock()
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+func ship(e Entry) {
+	shipperMu.Lock()
+	s := shipper
+	shipperMu.Unlock()
+	if s != nil {
+		s.Add(e)
+	}
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSink struct {
+	mu      sync.Mutex
+	fail    int // Sends to fail before succeeding
+	batches [][]Entry
+}
+
+func (f *fakeSink) Send(entries []Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail > 0 {
+		f.fail--
+		return errors.New("collector down")
+	}
+	f.batches = append(f.batches, entries)
+	return nil
+}
+
+func (f *fakeSink) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, b := range f.batches {
+		for _, e := range b {
+			out = append(out, e.Message)
+		}
+	}
+	return out
+}
+
+func TestShipper_BatchesAndRetries(t *testing.T) {
+	sink := &fakeSink{fail: 2}
+	s := NewShipper(sink)
+	s.BatchSize = 2
+	s.FlushInterval = time.Hour
+	s.RetryDelay = time.Millisecond
+	s.Start()
+
+	s.Add(Entry{Message: "one"})
+	s.Add(Entry{Message: "two"})
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.sent()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := sink.sent(); len(got) != 2 || got[0] != "one" {
+		t.Fatalf("sent = %v, want the full batch after retries", got)
+	}
+
+	// A partial batch waits for the flush interval, or Close.
+	s.Write([]byte("2024/03/01 10:00:00 [WARN] three\n"))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sink.mu.Lock()
+	last := sink.batches[len(sink.batches)-1]
+	sink.mu.Unlock()
+	if len(last) != 1 || last[0].Level != LevelWarn {
+		t.Errorf("last batch = %+v", last)
+	}
+}
+
+func TestShipper_DropsOldestWhenFull(t *testing.T) {
+	sink := &fakeSink{}
+	s := NewShipper(sink)
+	s.MaxBuffered = 2
+	for _, m := range []string{"a", "b", "c"} {
+		s.Add(Entry{Message: m})
+	}
+	batch := s.next()
+	if len(batch) != 3 || !strings.Contains(batch[0].Message, "dropped 1") || batch[1].Message != "b" {
+		t.Errorf("batch = %+v", batch)
+	}
+}
+
+func TestSyslogSink_Format(t *testing.T) {
+	e := Entry{Time: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Level: LevelError, Message: "disk full"}
+	got := SyslogSink{Hostname: "PC-07"}.format(e)
+	if !strings.HasPrefix(got, "<131>1 2024-03-01T10:00:00Z PC-07 syscleaner ") || !strings.HasSuffix(got, " - - disk full") {
+		t.Errorf("format = %q", got)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var got []map[string]any
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	sink, err := NewHTTPSink(srv.URL, "t0ken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Client = srv.Client()
+	if err := sink.Send([]Entry{{Level: LevelInfo, Message: "hello"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["message"] != "hello" || got[0]["level"] != "INFO" {
+		t.Errorf("posted = %v", got)
+	}
+
+	sink.Token = "wrong"
+	if err := sink.Send([]Entry{{Message: "x"}}); err == nil {
+		t.Error("a 401 should fail the batch")
+	}
+	if _, err := NewHTTPSink("http://logs.example.invalid/ingest", ""); err == nil {
+		t.Error("plain http endpoint should be refused")
+	}
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// sinkTimeout bounds each connection or request made by a sink.
+const sinkTimeout = 10 * time.Second
+
+// SyslogSink sends entries to a syslog server as RFC 5424 messages. Over
+// TCP and TLS the messages are octet-counted (RFC 6587).
+type SyslogSink struct {
+	Network string // "udp", "tcp" or "tls"
+	Addr    string // host:port
+	// Hostname and AppName fill the header fields; they default to the
+	// computer name and "syscleaner".
+	Hostname string
+	AppName  string
+}
+
+// syslogFacility is local0, the facility usually left for applications.
+const syslogFacility = 16
+
+// syslogSeverity maps a level to a syslog severity.
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case LevelDebug:
+		return 7
+	case LevelWarn:
+		return 4
+	case LevelError:
+		return 3
+	}
+	return 6
+}
+
+// Send writes entries over one connection.
+func (s SyslogSink) Send(entries []Entry) error {
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: sinkTimeout}
+	switch s.Network {
+	case "udp", "tcp":
+		conn, err = dialer.Dial(s.Network, s.Addr)
+	case "tls":
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.Addr, nil)
+	default:
+		return fmt.Errorf("logger: unknown syslog network %q (want udp, tcp or tls)", s.Network)
+	}
+	if err != nil {
+		return fmt.Errorf("logger: connecting to syslog server %s: %w", s.Addr, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(sinkTimeout))
+
+	for _, e := range entries {
+		msg := s.format(e)
+		if s.Network != "udp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := io.WriteString(conn, msg); err != nil {
+			return fmt.Errorf("logger: writing to syslog server %s: %w", s.Addr, err)
+		}
+	}
+	return nil
+}
+
+// format renders e as an RFC 5424 message without structured data.
+func (s SyslogSink) format(e Entry) string {
+	host := s.Hostname
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	if host == "" {
+		host = "-"
+	}
+	app := s.AppName
+	if app == "" {
+		app = "syscleaner"
+	}
+	msg := e.Message
+	if msg == "" {
+		msg = e.Line
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		syslogFacility*8+syslogSeverity(e.Level), e.Time.Format(time.RFC3339), host, app, os.Getpid(), msg)
+}
+
+// HTTPSink posts entries as a JSON array to an HTTPS endpoint:
+//
+//	[{"time": "...", "level": "WARN", "host": "PC-07", "message": "..."}]
+type HTTPSink struct {
+	URL string
+	// Token, if set, is sent as a bearer token.
+	Token  string
+	Client *http.Client
+}
+
+// NewHTTPSink returns an HTTPSink for rawURL, which must use https.
+func NewHTTPSink(rawURL, token string) (*HTTPSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("logger: invalid log endpoint %q", rawURL)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("logger: log endpoint %q must use https", rawURL)
+	}
+	return &HTTPSink{URL: rawURL, Token: token, Client: &http.Client{Timeout: sinkTimeout}}, nil
+}
+
+type httpEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Host    string    `json:"host"`
+	Message string    `json:"message"`
+}
+
+// Send posts entries in one request. Any status other than 2xx is an
+// error, so the batch is retried.
+func (s *HTTPSink) Send(entries []Entry) error {
+	host, _ := os.Hostname()
+	body := make([]httpEntry, len(entries))
+	for i, e := range entries {
+		msg := e.Message
+		if msg == "" {
+			msg = e.Line
+		}
+		body[i] = httpEntry{Time: e.Time, Level: e.Level.String(), Host: host, Message: msg}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("logger: encoding log entries: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("logger: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		// The error text includes the URL, which may carry a key.
+		return fmt.Errorf("logger: posting log entries: %s", strings.ReplaceAll(err.Error(), s.URL, "<log endpoint>"))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("logger: log endpoint returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// toSlogLevel returns the slog level for level.
+func toSlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// fromSlogLevel returns the LogLevel for level, rounding custom levels
+// down to the nearest one.
+func fromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	}
+	return LevelError
+}
+
+// Handler is a slog.Handler that writes records through a Logger, so code
+// using slog ends up in the SysCleaner log file. Attributes are appended
+// to the message as key=value; groups prefix their keys with "group.".
+type Handler struct {
+	l      *Logger
+	attrs  []slog.Attr
+	prefix string
+}
+
+// Handler returns a slog.Handler writing through l.
+func (l *Logger) Handler() *Handler {
+	return &Handler{l: l}
+}
+
+// Enabled reports whether l writes records at level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	h.l.mu.Lock()
+	defer h.l.mu.Unlock()
+	return fromSlogLevel(level) >= h.l.level
+}
+
+// Handle writes r as one log line.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(r.Message)
+	for _, a := range h.attrs {
+		appendAttr(&b, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.prefix, a)
+		return true
+	})
+	h.l.log(fromSlogLevel(r.Level), "%s", b.String())
+	return nil
+}
+
+// WithAttrs returns a Handler that adds attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		if h.prefix != "" {
+			a.Key = h.prefix + a.Key
+		}
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup returns a Handler that puts later attributes under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, sub := range a.Value.Group() {
+			appendAttr(b, p, sub)
+		}
+		return
+	}
+	v := a.Value.String()
+	if strings.ContainsAny(v, " \t\"=") || v == "" {
+		v = fmt.Sprintf("%q", v)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, v)
+}
+
+// external, if set, receives what the package-level functions log.
+var (
+	externalMu sync.Mutex
+	external   *slog.Logger
+)
+
+// SetSlogLogger routes the package-level functions (Debug, Info, Warn,
+// Error) to s instead of DefaultLogger or the standard log package, for
+// applications that embed SysCleaner's packages and have their own logging
+// stack. Pass nil to undo it. See also RedirectStandardLog.
+func SetSlogLogger(s *slog.Logger) {
+	externalMu.Lock()
+	defer externalMu.Unlock()
+	external = s
+}
+
+func slogLogger() *slog.Logger {
+	externalMu.Lock()
+	defer externalMu.Unlock()
+	return external
+}
+
+// RedirectStandardLog sends the output of the standard log package, which
+// most SysCleaner packages (pkg/cleaner, pkg/optimizer, ...) write to, to s.
+// Each line becomes a record at the level named by a "[WARN]"-style tag,
+// or Info, with the "[SysCleaner]" and level tags removed. The standard
+// log's date and time flags are turned off, as s records the time.
+func RedirectStandardLog(s *slog.Logger) {
+	log.SetFlags(0)
+	log.SetOutput(slogWriter{s})
+}
+
+type slogWriter struct{ s *slog.Logger }
+
+func (w slogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		level := lineLevel(line)
+		msg := strings.TrimPrefix(line, "[SysCleaner] ")
+		msg = strings.TrimPrefix(msg, "["+level.String()+"] ")
+		w.s.Log(context.Background(), toSlogLevel(level), Redact(msg))
+	}
+	return len(p), nil
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var _ slog.Handler = (*Handler)(nil)
+
+func TestHandler_WritesThroughLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syscleaner.log")
+	l, err := New(LevelInfo, path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	s := slog.New(l.Handler()).With("job", "weekly")
+	s.Debug("hidden")
+	s.WithGroup("clean").Warn("freed space", "bytes", 1024, "drive", "C:", "note", "two words")
+
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	if strings.Contains(got, "hidden") {
+		t.Errorf("debug record written below the Info level:\n%s", got)
+	}
+	want := `[WARN] freed space job=weekly clean.bytes=1024 clean.drive=C: clean.note="two words"`
+	if !strings.Contains(got, want) {
+		t.Errorf("log = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestSetSlogLogger_AndRedirectStandardLog(t *testing.T) {
+	var buf bytes.Buffer
+	s := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	SetSlogLogger(s)
+	defer SetSlogLogger(nil)
+
+	Warn("low disk on %s", "D:")
+	if !strings.Contains(buf.String(), `level=WARN msg="low disk on D:"`) {
+		t.Errorf("slog output = %q", buf.String())
+	}
+
+	buf.Reset()
+	flags, out := log.Flags(), log.Writer()
+	defer func() {
+		log.SetFlags(flags)
+		log.SetOutput(out)
+	}()
+	RedirectStandardLog(s)
+	log.Printf("[SysCleaner] [ERROR] access denied")
+	if !strings.Contains(buf.String(), `level=ERROR msg="access denied"`) {
+		t.Errorf("slog output = %q", buf.String())
+	}
+}
@@ -0,0 +1,53 @@
+package memory
+
+import "sync"
+
+var (
+	// monitorRefs counts the StartContinuousMonitor calls not yet matched
+	// by StopContinuousMonitor; monitorDone is closed when it drops to 0.
+	monitorRefs int
+	monitorDone chan struct{}
+	monitorMu   sync.Mutex
+)
+
+// acquireMonitor takes a reference to the continuous monitor. first
+// reports whether the caller must start it; done is closed when the last
+// reference is released.
+func acquireMonitor() (done chan struct{}, first bool) {
+	monitorMu.Lock()
+	defer monitorMu.Unlock()
+	monitorRefs++
+	if monitorRefs == 1 {
+		monitorDone = make(chan struct{})
+		return monitorDone, true
+	}
+	return monitorDone, false
+}
+
+// releaseMonitor drops a reference taken by acquireMonitor, closing done
+// with the last one. Extra releases are ignored.
+func releaseMonitor() {
+	monitorMu.Lock()
+	defer monitorMu.Unlock()
+	if monitorRefs == 0 {
+		return
+	}
+	monitorRefs--
+	if monitorRefs == 0 {
+		close(monitorDone)
+		monitorDone = nil
+	}
+}
+
+// shouldTrim reports whether the continuous monitor should purge standby
+// memory, given the previous and current readings (in percent of total
+// RAM). It trims when free memory is low while standby is above its
+// threshold, and whenever standby crosses its threshold from below.
+// prevStandby is negative before the first reading.
+func shouldTrim(freePercent, prevStandby, standby float64) bool {
+	if standby <= StandbyThresholdPercent {
+		return false
+	}
+	crossed := prevStandby >= 0 && prevStandby <= StandbyThresholdPercent
+	return freePercent < FreeMemoryThresholdPercent || crossed
+}
@@ -20,6 +20,14 @@ type MemoryStats struct {
 	TrimCount      int64
 }
 
+// MonitorInterval and the thresholds configure the continuous monitor. They
+// have no effect on non-Windows platforms.
+var (
+	MonitorInterval            = 5 * time.Second
+	FreeMemoryThresholdPercent = 15.0
+	StandbyThresholdPercent    = 40.0
+)
+
 // StartContinuousMonitor is not available on non-Windows platforms
 func StartContinuousMonitor(statsCallback func(MemoryStats)) {
 	// No-op on non-Windows
@@ -45,6 +53,11 @@ func EnableSeProfileSingleProcessPrivilege() error {
 	return fmt.Errorf("privilege management is only available on Windows")
 }
 
+// EmptyProcessWorkingSet is not available on non-Windows
+func EmptyProcessWorkingSet(pid uint32) error {
+	return fmt.Errorf("memory operations are only available on Windows")
+}
+
 // PurgeStandbyList is not available on non-Windows
 func PurgeStandbyList() error {
 	return fmt.Errorf("memory operations are only available on Windows")
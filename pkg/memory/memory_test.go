@@ -0,0 +1,54 @@
+package memory
+
+import "testing"
+
+func TestShouldTrim(t *testing.T) {
+	origFree, origStandby := FreeMemoryThresholdPercent, StandbyThresholdPercent
+	defer func() { FreeMemoryThresholdPercent, StandbyThresholdPercent = origFree, origStandby }()
+	FreeMemoryThresholdPercent, StandbyThresholdPercent = 15, 40
+
+	cases := []struct {
+		name                string
+		free, prev, standby float64
+		want                bool
+	}{
+		{"low free, high standby", 10, 50, 50, true},
+		{"low free, low standby", 10, 20, 20, false},
+		{"standby crosses threshold", 30, 35, 45, true},
+		{"standby stays above threshold", 30, 45, 50, false},
+		{"first reading above threshold", 30, -1, 50, false},
+		{"standby falls below threshold", 10, 50, 30, false},
+	}
+	for _, c := range cases {
+		if got := shouldTrim(c.free, c.prev, c.standby); got != c.want {
+			t.Errorf("%s: shouldTrim = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestMonitorRefCount(t *testing.T) {
+	done, first := acquireMonitor() // gaming mode
+	if !first {
+		t.Fatal("the first caller should start the monitor")
+	}
+	if _, first := acquireMonitor(); first { // extreme mode
+		t.Fatal("a second caller should share the running monitor")
+	}
+	releaseMonitor() // extreme mode turned off
+	select {
+	case <-done:
+		t.Fatal("the monitor stopped while gaming mode still needs it")
+	default:
+	}
+	releaseMonitor()
+	releaseMonitor() // an extra stop is ignored
+	select {
+	case <-done:
+	default:
+		t.Fatal("the monitor kept running after the last stop")
+	}
+	if _, first := acquireMonitor(); !first {
+		t.Error("a start after the last stop should start the monitor again")
+	}
+	releaseMonitor()
+}
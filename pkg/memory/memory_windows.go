@@ -5,13 +5,15 @@ package memory
 import (
 	"fmt"
 	"log"
-	"sync"
+	"os"
 	"syscall"
 	"time"
 	"unsafe"
 
 	"github.com/shirou/gopsutil/v3/mem"
 	"golang.org/x/sys/windows"
+
+	"syscleaner/pkg/admin"
 )
 
 // Memory list commands for NtSetSystemInformation
@@ -30,19 +32,17 @@ const (
 )
 
 var (
-	ntdll                      = windows.NewLazySystemDLL("ntdll.dll")
-	procNtSetSystemInformation = ntdll.NewProc("NtSetSystemInformation")
-	psapi                      = windows.NewLazySystemDLL("psapi.dll")
-	procEmptyWorkingSet        = psapi.NewProc("EmptyWorkingSet")
-
-	monitorActive bool
-	monitorDone   chan struct{}
-	monitorMu     sync.Mutex
+	ntdll                        = windows.NewLazySystemDLL("ntdll.dll")
+	procNtSetSystemInformation   = ntdll.NewProc("NtSetSystemInformation")
+	procNtQuerySystemInformation = ntdll.NewProc("NtQuerySystemInformation")
+	psapi                        = windows.NewLazySystemDLL("psapi.dll")
+	procEmptyWorkingSet          = psapi.NewProc("EmptyWorkingSet")
 
 	// Configurable thresholds
-	FreeMemoryThresholdPercent float64 = 15.0  // Trigger cleanup when free RAM drops below this %
-	StandbyThresholdPercent    float64 = 40.0  // Only clear standby if it exceeds this % of total
-	MinCleanInterval           = 30 * time.Second // Don't clean more often than this
+	FreeMemoryThresholdPercent float64 = 15.0             // Trigger cleanup when free RAM drops below this %
+	StandbyThresholdPercent    float64 = 40.0             // Only clear standby if it exceeds this % of total
+	MinCleanInterval                   = 30 * time.Second // Don't clean more often than this
+	MonitorInterval                    = 5 * time.Second  // How often the continuous monitor samples RAM
 	lastCleanTime              time.Time
 	trimCountTotal             int64
 )
@@ -118,6 +118,9 @@ func enablePrivilege(token windows.Token, privilegeName string) error {
 // This is the equivalent of RAMMap's "Empty Standby List".
 // Requires SeProfileSingleProcessPrivilege.
 func PurgeStandbyList() error {
+	if err := admin.RequireWriteAccess("purging the standby list"); err != nil {
+		return err
+	}
 	cmd := int32(MemoryPurgeStandbyList)
 	ret, _, err := procNtSetSystemInformation.Call(
 		uintptr(SystemMemoryListInformation),
@@ -133,6 +136,9 @@ func PurgeStandbyList() error {
 // PurgeLowPriorityStandby clears only low-priority standby pages.
 // This is gentler than PurgeStandbyList and less likely to cause stutter.
 func PurgeLowPriorityStandby() error {
+	if err := admin.RequireWriteAccess("purging low-priority standby memory"); err != nil {
+		return err
+	}
 	cmd := int32(MemoryPurgeLowPriorityStandbyList)
 	ret, _, err := procNtSetSystemInformation.Call(
 		uintptr(SystemMemoryListInformation),
@@ -148,6 +154,9 @@ func PurgeLowPriorityStandby() error {
 // EmptyProcessWorkingSet trims the working set of a specific process.
 // This is gentler than purging the standby list.
 func EmptyProcessWorkingSet(pid uint32) error {
+	if err := admin.RequireWriteAccess(fmt.Sprintf("trimming working set of PID %d", pid)); err != nil {
+		return err
+	}
 	handle, err := windows.OpenProcess(
 		windows.PROCESS_QUERY_INFORMATION|windows.PROCESS_SET_QUOTA,
 		false, pid)
@@ -164,25 +173,27 @@ func EmptyProcessWorkingSet(pid uint32) error {
 }
 
 // StartContinuousMonitor begins monitoring RAM and trimming standby memory
-// when free RAM drops below the threshold. This is ONLY active during
-// Extreme Gaming Mode.
+// when free RAM drops below the threshold. Gaming mode starts it when its
+// config asks for standby purging, and Extreme Gaming Mode starts it too.
+// The monitor is shared and counted: it keeps running until every Start
+// has been matched by a StopContinuousMonitor, and only the first caller's
+// statsCallback is used.
 //
 // Strategy (to avoid performance drops):
-//  1. Check free memory every 5 seconds
-//  2. If free memory < 15% of total AND standby > 40% of total:
+//  1. Check free memory every MonitorInterval (5 seconds by default)
+//  2. If free memory < FreeMemoryThresholdPercent of total AND standby >
+//     StandbyThresholdPercent of total, or standby has just risen past
+//     StandbyThresholdPercent (both set from the ram_monitor config,
+//     15% and 50% by default):
 //     a. First attempt: PurgeLowPriorityStandby (gentle)
-//     b. If still low after 10s: PurgeStandbyList (aggressive)
-//  3. Never trim more often than every 30 seconds
+//     b. If still low after 2s: PurgeStandbyList (aggressive)
+//  3. Never trim more often than every MinCleanInterval (30 seconds)
 //  4. Log every trim action with before/after stats
 func StartContinuousMonitor(statsCallback func(MemoryStats)) {
-	monitorMu.Lock()
-	if monitorActive {
-		monitorMu.Unlock()
+	done, first := acquireMonitor()
+	if !first {
 		return
 	}
-	monitorDone = make(chan struct{})
-	monitorActive = true
-	monitorMu.Unlock()
 
 	// Enable required privileges
 	if err := EnableSeProfileSingleProcessPrivilege(); err != nil {
@@ -191,50 +202,33 @@ func StartContinuousMonitor(statsCallback func(MemoryStats)) {
 	}
 
 	go func() {
-		ticker := time.NewTicker(5 * time.Second)
+		lastStandbyPercent := -1.0
+		interval := MonitorInterval
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
 			select {
-			case <-monitorDone:
+			case <-done:
 				return
 			case <-ticker.C:
-				vmem, err := mem.VirtualMemory()
-				if err != nil {
+				stats, ok := currentStats()
+				if !ok {
 					continue
 				}
-
-				totalGB := float64(vmem.Total) / 1024 / 1024 / 1024
-				usedGB := float64(vmem.Used) / 1024 / 1024 / 1024
-				freeGB := float64(vmem.Available) / 1024 / 1024 / 1024
-				// Standby ≈ Available - Free (approximation)
-				standbyGB := freeGB - (float64(vmem.Free) / 1024 / 1024 / 1024)
-				if standbyGB < 0 {
-					standbyGB = 0
-				}
-
-				freePercent := (freeGB / totalGB) * 100
-				standbyPercent := (standbyGB / totalGB) * 100
-
-				stats := MemoryStats{
-					TotalGB:        totalGB,
-					UsedGB:         usedGB,
-					FreeGB:         freeGB,
-					StandbyGB:      standbyGB,
-					UsedPercent:    vmem.UsedPercent,
-					FreePercent:    freePercent,
-					StandbyPercent: standbyPercent,
-					LastTrimTime:   lastCleanTime,
-					TrimCount:      trimCountTotal,
-				}
+				freePercent, standbyPercent := stats.FreePercent, stats.StandbyPercent
 
 				if statsCallback != nil {
 					statsCallback(stats)
 				}
 
 				// Should we trim?
-				if freePercent < FreeMemoryThresholdPercent &&
-					standbyPercent > StandbyThresholdPercent &&
+				prevStandby := lastStandbyPercent
+				lastStandbyPercent = standbyPercent
+				if shouldTrim(freePercent, prevStandby, standbyPercent) &&
 					time.Since(lastCleanTime) > MinCleanInterval {
 
 					log.Printf("[SysCleaner] RAM Monitor: Free=%.1f%%, Standby=%.1f%% - Trimming...",
@@ -271,14 +265,10 @@ func StartContinuousMonitor(statsCallback func(MemoryStats)) {
 	}()
 }
 
-// StopContinuousMonitor stops the RAM monitor.
+// StopContinuousMonitor releases one StartContinuousMonitor call, stopping
+// the RAM monitor once no caller needs it any more.
 func StopContinuousMonitor() {
-	monitorMu.Lock()
-	defer monitorMu.Unlock()
-	if monitorActive && monitorDone != nil {
-		close(monitorDone)
-		monitorActive = false
-	}
+	releaseMonitor()
 }
 
 // TrimNow immediately trims standby memory.
@@ -300,31 +290,73 @@ func TrimNow() error {
 
 // GetCurrentStats returns current memory statistics.
 func GetCurrentStats() MemoryStats {
+	stats, _ := currentStats()
+	return stats
+}
+
+// currentStats reads memory statistics, reporting false if memory usage
+// can't be read. Standby memory comes from the kernel's page lists where
+// this process may query them, and is otherwise estimated as available
+// minus free memory.
+func currentStats() (MemoryStats, bool) {
 	vmem, err := mem.VirtualMemory()
-	if err != nil {
-		return MemoryStats{}
+	if err != nil || vmem.Total == 0 {
+		return MemoryStats{}, false
 	}
 
-	totalGB := float64(vmem.Total) / 1024 / 1024 / 1024
-	usedGB := float64(vmem.Used) / 1024 / 1024 / 1024
-	freeGB := float64(vmem.Available) / 1024 / 1024 / 1024
-	standbyGB := freeGB - (float64(vmem.Free) / 1024 / 1024 / 1024)
-	if standbyGB < 0 {
-		standbyGB = 0
+	const gb = 1024 * 1024 * 1024
+	standby, err := standbyBytes()
+	if err != nil {
+		standby = 0
+		if vmem.Available > vmem.Free {
+			standby = vmem.Available - vmem.Free
+		}
 	}
-
-	freePercent := (freeGB / totalGB) * 100
-	standbyPercent := (standbyGB / totalGB) * 100
+	total := float64(vmem.Total)
 
 	return MemoryStats{
-		TotalGB:        totalGB,
-		UsedGB:         usedGB,
-		FreeGB:         freeGB,
-		StandbyGB:      standbyGB,
+		TotalGB:        total / gb,
+		UsedGB:         float64(vmem.Used) / gb,
+		FreeGB:         float64(vmem.Available) / gb,
+		StandbyGB:      float64(standby) / gb,
 		UsedPercent:    vmem.UsedPercent,
-		FreePercent:    freePercent,
-		StandbyPercent: standbyPercent,
+		FreePercent:    float64(vmem.Available) / total * 100,
+		StandbyPercent: float64(standby) / total * 100,
 		LastTrimTime:   lastCleanTime,
 		TrimCount:      trimCountTotal,
+	}, true
+}
+
+// systemMemoryListInformation mirrors SYSTEM_MEMORY_LIST_INFORMATION, the
+// page counts NtQuerySystemInformation returns for
+// SystemMemoryListInformation.
+type systemMemoryListInformation struct {
+	ZeroPageCount             uintptr
+	FreePageCount             uintptr
+	ModifiedPageCount         uintptr
+	ModifiedNoWritePageCount  uintptr
+	BadPageCount              uintptr
+	PageCountByPriority       [8]uintptr // the standby list, by priority
+	RepurposedPagesByPriority [8]uintptr
+	ModifiedPageCountPageFile uintptr
+}
+
+// standbyBytes returns the size of the standby list. The query needs
+// SeProfileSingleProcessPrivilege (see EnableSeProfileSingleProcessPrivilege).
+func standbyBytes() (uint64, error) {
+	var info systemMemoryListInformation
+	ret, _, err := procNtQuerySystemInformation.Call(
+		uintptr(SystemMemoryListInformation),
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info),
+		0,
+	)
+	if ret != 0 {
+		return 0, fmt.Errorf("NtQuerySystemInformation failed: %v (NTSTATUS: 0x%x)", err, ret)
+	}
+	var pages uint64
+	for _, n := range info.PageCountByPriority {
+		pages += uint64(n)
 	}
+	return pages * uint64(os.Getpagesize()), nil
 }
@@ -3,6 +3,7 @@ package monitor
 import (
 	"math"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,7 +32,12 @@ func CheckDiskSpace() DiskStatus {
 	if drive == "" {
 		drive = "C:"
 	}
+	return CheckDrive(drive)
+}
 
+// CheckDrive checks free space on the given drive (e.g. "D:").
+func CheckDrive(drive string) DiskStatus {
+	drive = strings.TrimRight(drive, `\`)
 	path := drive + "\\"
 
 	usage, err := disk.Usage(path)
@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FrameStats summarises the frames one process presented over the last
+// FrameWindow.
+type FrameStats struct {
+	PID    uint32
+	Frames int
+	// FPS is the average frame rate and Low1 the "1% low": the frame rate
+	// of the slowest 1% of frames, which shows stutter the average hides.
+	FPS  float64
+	Low1 float64
+	// AvgFrameTime and MaxFrameTime are the mean and worst time between
+	// presents.
+	AvgFrameTime time.Duration
+	MaxFrameTime time.Duration
+}
+
+// FrameWindow is how much frame history FrameStats covers.
+var FrameWindow = 5 * time.Second
+
+// FrameCapture records when processes present frames, from the DXGI
+// Present events Windows publishes through ETW, as PresentMon does. It
+// sees Direct3D 10-12 games and others that present through DXGI.
+type FrameCapture struct {
+	mu       sync.Mutex
+	presents map[uint32][]time.Duration // present times by PID, oldest first
+	stop     func() error
+}
+
+// StartFrameCapture starts an ETW session that records present events.
+// It needs administrator rights. Stop must be called to end the session.
+func StartFrameCapture() (*FrameCapture, error) {
+	c := &FrameCapture{presents: make(map[uint32][]time.Duration)}
+	stop, err := startPresentTrace(c.record)
+	if err != nil {
+		return nil, err
+	}
+	c.stop = stop
+	return c, nil
+}
+
+// Stop ends the ETW session.
+func (c *FrameCapture) Stop() error {
+	c.mu.Lock()
+	stop := c.stop
+	c.stop = nil
+	c.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+	return stop()
+}
+
+// record notes a present by pid at t, a monotonic timestamp, and drops
+// history older than FrameWindow.
+func (c *FrameCapture) record(pid uint32, t time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := append(c.presents[pid], t)
+	i := 0
+	for i < len(p) && t-p[i] > FrameWindow {
+		i++
+	}
+	c.presents[pid] = p[i:]
+}
+
+// Stats returns the frame statistics of pid.
+func (c *FrameCapture) Stats(pid uint32) FrameStats {
+	c.mu.Lock()
+	presents := append([]time.Duration(nil), c.presents[pid]...)
+	c.mu.Unlock()
+	s := frameStats(presents)
+	s.PID = pid
+	return s
+}
+
+// FocusedStats returns the frame statistics of the process owning the
+// foreground window, which is normally the game being played.
+func (c *FrameCapture) FocusedStats() (FrameStats, bool) {
+	pid, ok := foregroundPID()
+	if !ok {
+		return FrameStats{}, false
+	}
+	return c.Stats(pid), true
+}
+
+// frameStats computes frame statistics from present times, oldest first.
+func frameStats(presents []time.Duration) FrameStats {
+	if len(presents) < 2 {
+		return FrameStats{}
+	}
+	frameTimes := make([]time.Duration, 0, len(presents)-1)
+	for i := 1; i < len(presents); i++ {
+		frameTimes = append(frameTimes, presents[i]-presents[i-1])
+	}
+	total := presents[len(presents)-1] - presents[0]
+	if total <= 0 {
+		return FrameStats{}
+	}
+
+	sort.Slice(frameTimes, func(i, j int) bool { return frameTimes[i] > frameTimes[j] })
+	slowest := frameTimes[:max(1, len(frameTimes)/100)]
+	var slowTotal time.Duration
+	for _, ft := range slowest {
+		slowTotal += ft
+	}
+
+	return FrameStats{
+		Frames:       len(frameTimes),
+		FPS:          float64(len(frameTimes)) / total.Seconds(),
+		Low1:         float64(len(slowest)) / slowTotal.Seconds(),
+		AvgFrameTime: total / time.Duration(len(frameTimes)),
+		MaxFrameTime: frameTimes[0],
+	}
+}
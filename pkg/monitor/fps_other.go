@@ -0,0 +1,18 @@
+//go:build !windows || !(amd64 || arm64)
+
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// startPresentTrace is only available on 64-bit Windows.
+func startPresentTrace(sink func(pid uint32, t time.Duration)) (func() error, error) {
+	return nil, fmt.Errorf("frame capture is only available on 64-bit Windows")
+}
+
+// foregroundPID is only available on Windows.
+func foregroundPID() (uint32, bool) {
+	return 0, false
+}
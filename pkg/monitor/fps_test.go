@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameStats(t *testing.T) {
+	// 199 frames of 5 ms and one 50 ms hitch.
+	var presents []time.Duration
+	var now time.Duration
+	for i := 0; i < 200; i++ {
+		presents = append(presents, now)
+		now += 5 * time.Millisecond
+		if i == 100 {
+			now += 45 * time.Millisecond
+		}
+	}
+	presents = append(presents, now)
+
+	s := frameStats(presents)
+	if s.Frames != 200 {
+		t.Fatalf("Frames = %d, want 200", s.Frames)
+	}
+	if s.MaxFrameTime != 50*time.Millisecond {
+		t.Errorf("MaxFrameTime = %v, want 50ms", s.MaxFrameTime)
+	}
+	// Two slowest frames: 50 ms and 5 ms, so 2 frames in 55 ms.
+	if want := 2 / 0.055; s.Low1 < want-0.01 || s.Low1 > want+0.01 {
+		t.Errorf("Low1 = %.2f, want %.2f", s.Low1, want)
+	}
+	if want := 200 / 1.045; s.FPS < want-0.01 || s.FPS > want+0.01 {
+		t.Errorf("FPS = %.2f, want %.2f", s.FPS, want)
+	}
+
+	if s := frameStats(presents[:1]); s.Frames != 0 {
+		t.Errorf("one present gave %+v, want no frames", s)
+	}
+}
+
+func TestFrameCapture_RecordDropsOldPresents(t *testing.T) {
+	c := &FrameCapture{presents: make(map[uint32][]time.Duration)}
+	c.record(7, 0)
+	c.record(7, FrameWindow/2)
+	c.record(7, FrameWindow+time.Second)
+	if n := len(c.presents[7]); n != 2 {
+		t.Errorf("kept %d presents, want 2", n)
+	}
+}
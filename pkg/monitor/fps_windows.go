@@ -0,0 +1,231 @@
+//go:build windows && (amd64 || arm64)
+
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	advapi32                     = windows.NewLazySystemDLL("advapi32.dll")
+	procStartTraceW              = advapi32.NewProc("StartTraceW")
+	procControlTraceW            = advapi32.NewProc("ControlTraceW")
+	procEnableTraceEx2           = advapi32.NewProc("EnableTraceEx2")
+	procOpenTraceW               = advapi32.NewProc("OpenTraceW")
+	procProcessTrace             = advapi32.NewProc("ProcessTrace")
+	procCloseTrace               = advapi32.NewProc("CloseTrace")
+	procQueryPerfFreq            = windows.NewLazySystemDLL("kernel32.dll").NewProc("QueryPerformanceFrequency")
+	user32                       = windows.NewLazySystemDLL("user32.dll")
+	procGetForegroundWindow      = user32.NewProc("GetForegroundWindow")
+	procGetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
+)
+
+// dxgiProvider is Microsoft-Windows-DXGI; its event 42 is Present_Start,
+// logged each time a swap chain presents a frame.
+var dxgiProvider = windows.GUID{Data1: 0xCA11C036, Data2: 0x0102, Data3: 0x4A2D, Data4: [8]byte{0xA6, 0xAD, 0xF0, 0x3C, 0xFE, 0xD5, 0xD3, 0xC9}}
+
+const (
+	dxgiPresentStart = 42
+
+	presentTraceName = "SysCleaner Frame Capture"
+
+	wnodeFlagTracedGUID            = 0x00020000
+	eventTraceRealTimeMode         = 0x00000100
+	eventTraceControlStop          = 1
+	eventControlCodeEnableProvider = 1
+	traceLevelInformation          = 4
+	processTraceModeRealTime       = 0x00000100
+	processTraceModeEventRecord    = 0x10000000
+	invalidProcessTraceHandle      = ^uint64(0)
+	clientContextQPC               = 1
+)
+
+// eventTraceProperties is EVENT_TRACE_PROPERTIES followed by room for the
+// session name, which ETW copies there.
+type eventTraceProperties struct {
+	// WNODE_HEADER
+	BufferSize        uint32
+	ProviderID        uint32
+	HistoricalContext uint64
+	TimeStamp         int64
+	GUID              windows.GUID
+	ClientContext     uint32
+	Flags             uint32
+
+	LogBufferSize       uint32
+	MinimumBuffers      uint32
+	MaximumBuffers      uint32
+	MaximumFileSize     uint32
+	LogFileMode         uint32
+	FlushTimer          uint32
+	EnableFlags         uint32
+	AgeLimit            int32
+	NumberOfBuffers     uint32
+	FreeBuffers         uint32
+	EventsLost          uint32
+	BuffersWritten      uint32
+	LogBuffersLost      uint32
+	RealTimeBuffersLost uint32
+	LoggerThreadID      windows.Handle
+	LogFileNameOffset   uint32
+	LoggerNameOffset    uint32
+
+	name [256]uint16
+}
+
+func newEventTraceProperties() *eventTraceProperties {
+	p := &eventTraceProperties{}
+	p.BufferSize = uint32(unsafe.Sizeof(*p))
+	p.ClientContext = clientContextQPC
+	p.Flags = wnodeFlagTracedGUID
+	p.LogFileMode = eventTraceRealTimeMode
+	p.LoggerNameOffset = uint32(unsafe.Offsetof(p.name))
+	return p
+}
+
+// eventTraceLogfile is EVENT_TRACE_LOGFILEW. CurrentEvent and
+// LogfileHeader are output fields this package does not read.
+type eventTraceLogfile struct {
+	LogFileName         *uint16
+	LoggerName          *uint16
+	CurrentTime         int64
+	BuffersRead         uint32
+	ProcessTraceMode    uint32
+	CurrentEvent        [88]byte
+	LogfileHeader       [280]byte
+	BufferCallback      uintptr
+	BufferSize          uint32
+	Filled              uint32
+	EventsLost          uint32
+	EventRecordCallback uintptr
+	IsKernelTrace       uint32
+	Context             uintptr
+}
+
+// eventRecord is the start of EVENT_RECORD: its EVENT_HEADER.
+type eventRecord struct {
+	Size          uint16
+	HeaderType    uint16
+	Flags         uint16
+	EventProperty uint16
+	ThreadID      uint32
+	ProcessID     uint32
+	TimeStamp     int64
+	ProviderID    windows.GUID
+	EventID       uint16
+}
+
+var (
+	// presentSink receives the presents of the running trace; only one
+	// trace runs at a time.
+	presentMu   sync.Mutex
+	presentSink func(pid uint32, t time.Duration)
+	qpcFreq     int64
+
+	callbackOnce sync.Once
+	callback     uintptr
+)
+
+// onEvent is the ETW event callback. It runs on the ProcessTrace thread.
+func onEvent(rec *eventRecord) uintptr {
+	if rec.EventID != dxgiPresentStart || rec.ProviderID != dxgiProvider {
+		return 0
+	}
+	presentMu.Lock()
+	sink, freq := presentSink, qpcFreq
+	presentMu.Unlock()
+	if sink != nil && freq > 0 {
+		sec, frac := rec.TimeStamp/freq, rec.TimeStamp%freq
+		sink(rec.ProcessID, time.Duration(sec)*time.Second+time.Duration(frac)*time.Second/time.Duration(freq))
+	}
+	return 0
+}
+
+// startPresentTrace starts a real-time ETW session for DXGI present
+// events and passes each to sink, returning a function that ends it.
+func startPresentTrace(sink func(pid uint32, t time.Duration)) (func() error, error) {
+	presentMu.Lock()
+	defer presentMu.Unlock()
+	if presentSink != nil {
+		return nil, fmt.Errorf("frame capture is already running")
+	}
+	if r, _, err := procQueryPerfFreq.Call(uintptr(unsafe.Pointer(&qpcFreq))); r == 0 {
+		return nil, fmt.Errorf("QueryPerformanceFrequency: %w", err)
+	}
+	callbackOnce.Do(func() { callback = syscall.NewCallback(onEvent) })
+
+	name, _ := windows.UTF16PtrFromString(presentTraceName)
+	var session uint64
+	props := newEventTraceProperties()
+	r, _, _ := procStartTraceW.Call(uintptr(unsafe.Pointer(&session)), uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(props)))
+	if windows.Errno(r) == windows.ERROR_ALREADY_EXISTS {
+		// Left behind by a run that exited without stopping it.
+		stopTrace(name)
+		props = newEventTraceProperties()
+		r, _, _ = procStartTraceW.Call(uintptr(unsafe.Pointer(&session)), uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(props)))
+	}
+	if r != 0 {
+		return nil, fmt.Errorf("starting ETW session: %w", windows.Errno(r))
+	}
+
+	if r, _, _ := procEnableTraceEx2.Call(uintptr(session), uintptr(unsafe.Pointer(&dxgiProvider)),
+		eventControlCodeEnableProvider, traceLevelInformation, 0, 0, 0, 0); r != 0 {
+		stopTrace(name)
+		return nil, fmt.Errorf("enabling the DXGI provider: %w", windows.Errno(r))
+	}
+
+	logfile := eventTraceLogfile{
+		LoggerName:          name,
+		ProcessTraceMode:    processTraceModeRealTime | processTraceModeEventRecord,
+		EventRecordCallback: callback,
+	}
+	trace, _, _ := procOpenTraceW.Call(uintptr(unsafe.Pointer(&logfile)))
+	if uint64(trace) == invalidProcessTraceHandle {
+		stopTrace(name)
+		return nil, fmt.Errorf("opening ETW session: %w", windows.GetLastError())
+	}
+
+	presentSink = sink
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h := uint64(trace)
+		procProcessTrace.Call(uintptr(unsafe.Pointer(&h)), 1, 0, 0)
+	}()
+
+	return func() error {
+		err := stopTrace(name)
+		procCloseTrace.Call(trace)
+		<-done
+		presentMu.Lock()
+		presentSink = nil
+		presentMu.Unlock()
+		return err
+	}, nil
+}
+
+// stopTrace stops the ETW session called name.
+func stopTrace(name *uint16) error {
+	props := newEventTraceProperties()
+	if r, _, _ := procControlTraceW.Call(0, uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(props)), eventTraceControlStop); r != 0 {
+		return fmt.Errorf("stopping ETW session: %w", windows.Errno(r))
+	}
+	return nil
+}
+
+// foregroundPID returns the PID owning the foreground window.
+func foregroundPID() (uint32, bool) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return 0, false
+	}
+	var pid uint32
+	procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	return pid, pid != 0
+}
@@ -0,0 +1,225 @@
+package monitor
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GPUStatus is a snapshot of the GPU's power state and what is using it.
+type GPUStatus struct {
+	Name             string
+	PowerState       string // e.g. "P8"; empty when the driver doesn't report it
+	GraphicsClockMHz int
+	MemoryClockMHz   int
+	// Utilization is the busiest engine's load across all processes, as a
+	// percentage.
+	Utilization float64
+	// VRAMUsed and VRAMTotal are dedicated video memory in use and
+	// installed, in bytes. VRAMTotal is zero when it is not known.
+	VRAMUsed  uint64
+	VRAMTotal uint64
+	// TemperatureC is the GPU core temperature, or zero when it is not
+	// known; it is read through NVML, so only NVIDIA GPUs report it.
+	TemperatureC int
+	Processes    []GPUProcess
+	// Awake is set when the GPU is not in a low-power state: a performance
+	// state of P0-P2, or utilization at or above GPUAwakeThreshold.
+	Awake bool
+}
+
+// GPUProcess is a process using the GPU.
+type GPUProcess struct {
+	PID         uint32
+	Name        string
+	Utilization float64
+}
+
+var (
+	// GPUAwakeThreshold is the utilization (percent) above which an idle
+	// GPU is considered kept awake.
+	GPUAwakeThreshold = 5.0
+	// GPUProcessThreshold is the per-process utilization (percent) above
+	// which a process is reported as keeping the GPU busy.
+	GPUProcessThreshold = 1.0
+	// GPUBoundThreshold is the utilization (percent) at which a game is
+	// considered held back by the GPU rather than the CPU.
+	GPUBoundThreshold = 95.0
+	// VRAMPressureThreshold is the share of video memory in use (percent)
+	// above which textures start spilling into system RAM, a common
+	// cause of stutter.
+	VRAMPressureThreshold = 90.0
+)
+
+// gpuIgnoredProcesses always use a little GPU time and are never reported.
+var gpuIgnoredProcesses = map[string]bool{
+	"dwm.exe":   true,
+	"csrss.exe": true,
+	"system":    true,
+}
+
+// CheckGPU samples the GPU's power state, clocks and per-process load. On
+// systems where a source is unavailable the matching fields stay empty.
+func CheckGPU() GPUStatus {
+	status := readGPUClocks()
+	sampleGPUInto(&status)
+	return status
+}
+
+// SampleGPU samples the GPU's load, video memory and temperature, leaving
+// out the power state and clocks, which need nvidia-smi to be started. It
+// takes about a second and is meant for sampling while a game runs.
+func SampleGPU() GPUStatus {
+	var status GPUStatus
+	sampleGPUInto(&status)
+	return status
+}
+
+func sampleGPUInto(status *GPUStatus) {
+	status.Utilization, status.Processes = readGPUProcessUsage()
+	status.VRAMUsed, status.VRAMTotal, status.TemperatureC = readGPUSensors()
+	status.Awake = isHighPowerState(status.PowerState) || status.Utilization >= GPUAwakeThreshold
+}
+
+// VRAMPercent returns the share of video memory in use, or zero when the
+// total is not known.
+func (s GPUStatus) VRAMPercent() float64 {
+	if s.VRAMTotal == 0 {
+		return 0
+	}
+	return min(100, float64(s.VRAMUsed)/float64(s.VRAMTotal)*100)
+}
+
+// GPUBound reports whether the GPU is at or above GPUBoundThreshold.
+func (s GPUStatus) GPUBound() bool {
+	return s.Utilization >= GPUBoundThreshold
+}
+
+// VRAMPressure reports whether video memory use is above
+// VRAMPressureThreshold.
+func (s GPUStatus) VRAMPressure() bool {
+	return s.VRAMPercent() > VRAMPressureThreshold
+}
+
+// isHighPowerState reports whether an NVIDIA-style performance state
+// ("P0".."P12") is one of the full-clock states.
+func isHighPowerState(pstate string) bool {
+	if !strings.HasPrefix(pstate, "P") {
+		return false
+	}
+	n, err := strconv.Atoi(pstate[1:])
+	return err == nil && n <= 2
+}
+
+// parseNvidiaSMI parses one line of
+// "nvidia-smi --query-gpu=name,pstate,clocks.gr,clocks.mem --format=csv,noheader,nounits".
+func parseNvidiaSMI(out string) (GPUStatus, bool) {
+	line := strings.TrimSpace(strings.SplitN(out, "\n", 2)[0])
+	fields := strings.Split(line, ",")
+	if len(fields) < 4 {
+		return GPUStatus{}, false
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	gr, _ := strconv.Atoi(fields[2])
+	memClock, _ := strconv.Atoi(fields[3])
+	return GPUStatus{Name: fields[0], PowerState: fields[1], GraphicsClockMHz: gr, MemoryClockMHz: memClock}, true
+}
+
+// gpuEngineSample is one "GPU Engine" performance counter instance.
+type gpuEngineSample struct {
+	Instance string
+	Value    float64
+}
+
+// aggregateGPUEngines turns GPU Engine counter samples (instances named
+// like "pid_1234_luid_0x0_0x1_phys_0_eng_0_engtype_3D") into the overall
+// utilization and per-process loads. Like Task Manager, a process's load
+// is its busiest engine type, and the overall load is the busiest engine
+// type summed across processes.
+func aggregateGPUEngines(samples []gpuEngineSample) (float64, map[uint32]float64) {
+	perPID := make(map[uint32]map[string]float64)
+	perEngine := make(map[string]float64)
+	for _, s := range samples {
+		pid, engine, ok := parseGPUEngineInstance(s.Instance)
+		if !ok {
+			continue
+		}
+		if perPID[pid] == nil {
+			perPID[pid] = make(map[string]float64)
+		}
+		perPID[pid][engine] += s.Value
+		perEngine[engine] += s.Value
+	}
+
+	var total float64
+	for _, v := range perEngine {
+		if v > total {
+			total = v
+		}
+	}
+	loads := make(map[uint32]float64, len(perPID))
+	for pid, engines := range perPID {
+		for _, v := range engines {
+			if v > loads[pid] {
+				loads[pid] = v
+			}
+		}
+	}
+	if total > 100 {
+		total = 100
+	}
+	return total, loads
+}
+
+// sumAdapterMemory adds up "GPU Adapter Memory" counter samples, one per
+// adapter, skipping a "_Total" instance if the counter has one.
+func sumAdapterMemory(samples []gpuEngineSample) uint64 {
+	var total float64
+	for _, s := range samples {
+		if s.Instance == "_Total" || s.Value < 0 {
+			continue
+		}
+		total += s.Value
+	}
+	return uint64(total)
+}
+
+func parseGPUEngineInstance(name string) (uint32, string, bool) {
+	if !strings.HasPrefix(name, "pid_") {
+		return 0, "", false
+	}
+	rest := name[len("pid_"):]
+	end := strings.IndexByte(rest, '_')
+	if end < 0 {
+		return 0, "", false
+	}
+	pid, err := strconv.ParseUint(rest[:end], 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	engine := ""
+	if i := strings.LastIndex(name, "engtype_"); i >= 0 {
+		engine = name[i+len("engtype_"):]
+	}
+	return uint32(pid), engine, true
+}
+
+// gpuOffenders returns the processes above GPUProcessThreshold, busiest
+// first, skipping system processes that always use the GPU.
+func gpuOffenders(loads map[uint32]float64, names func(uint32) string) []GPUProcess {
+	var procs []GPUProcess
+	for pid, load := range loads {
+		if load < GPUProcessThreshold {
+			continue
+		}
+		name := names(pid)
+		if gpuIgnoredProcesses[strings.ToLower(name)] {
+			continue
+		}
+		procs = append(procs, GPUProcess{PID: pid, Name: name, Utilization: load})
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].Utilization > procs[j].Utilization })
+	return procs
+}
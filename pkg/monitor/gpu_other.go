@@ -0,0 +1,18 @@
+//go:build !windows
+
+package monitor
+
+// readGPUClocks is only available on Windows.
+func readGPUClocks() GPUStatus {
+	return GPUStatus{}
+}
+
+// readGPUProcessUsage is only available on Windows.
+func readGPUProcessUsage() (float64, []GPUProcess) {
+	return 0, nil
+}
+
+// readGPUSensors is only available on Windows.
+func readGPUSensors() (used, total uint64, tempC int) {
+	return 0, 0, 0
+}
@@ -0,0 +1,72 @@
+package monitor
+
+import "testing"
+
+func TestParseNvidiaSMI(t *testing.T) {
+	status, ok := parseNvidiaSMI("NVIDIA GeForce RTX 3070, P8, 210, 405\n")
+	if !ok {
+		t.Fatal("expected line to parse")
+	}
+	if status.Name != "NVIDIA GeForce RTX 3070" || status.PowerState != "P8" ||
+		status.GraphicsClockMHz != 210 || status.MemoryClockMHz != 405 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if _, ok := parseNvidiaSMI("No devices were found"); ok {
+		t.Error("expected error output to be rejected")
+	}
+
+}
+
+func TestGPUPressure(t *testing.T) {
+	s := GPUStatus{Utilization: 97, VRAMUsed: 4 << 30, VRAMTotal: 8 << 30}
+	if !s.GPUBound() || s.VRAMPressure() || s.VRAMPercent() != 50 {
+		t.Errorf("unexpected pressure for %+v", s)
+	}
+	s = GPUStatus{Utilization: 60, VRAMUsed: 7700 << 20, VRAMTotal: 8 << 30}
+	if s.GPUBound() || !s.VRAMPressure() {
+		t.Errorf("unexpected pressure for %+v", s)
+	}
+	if (GPUStatus{VRAMUsed: 1 << 30}).VRAMPercent() != 0 {
+		t.Error("VRAMPercent should be zero when the total is unknown")
+	}
+	samples := []gpuEngineSample{{"luid_0x0_0x1_phys_0", 1 << 30}, {"luid_0x0_0x2_phys_0", 512 << 20}, {"_Total", 99}}
+	if got := sumAdapterMemory(samples); got != 1<<30+512<<20 {
+		t.Errorf("sumAdapterMemory = %d", got)
+	}
+}
+
+func TestIsHighPowerState(t *testing.T) {
+	cases := map[string]bool{"P0": true, "P2": true, "P5": false, "P8": false, "": false, "[N/A]": false}
+	for pstate, want := range cases {
+		if got := isHighPowerState(pstate); got != want {
+			t.Errorf("isHighPowerState(%q) = %v, want %v", pstate, got, want)
+		}
+	}
+}
+
+func TestAggregateGPUEngines(t *testing.T) {
+	samples := []gpuEngineSample{
+		{"pid_100_luid_0x0_0x1_phys_0_eng_0_engtype_3D", 6},
+		{"pid_100_luid_0x0_0x1_phys_0_eng_1_engtype_3D", 2},
+		{"pid_100_luid_0x0_0x1_phys_0_eng_4_engtype_VideoDecode", 5},
+		{"pid_200_luid_0x0_0x1_phys_0_eng_0_engtype_3D", 3},
+		{"pid_300_luid_0x0_0x1_phys_0_eng_0_engtype_Copy", 0.5},
+		{"_Total", 99},
+	}
+	total, loads := aggregateGPUEngines(samples)
+
+	// 3D is the busiest engine type: 6+2 from pid 100 and 3 from pid 200.
+	if total != 11 {
+		t.Errorf("total = %v, want 11", total)
+	}
+	if loads[100] != 8 || loads[200] != 3 || loads[300] != 0.5 {
+		t.Errorf("unexpected loads: %v", loads)
+	}
+
+	names := map[uint32]string{100: "msedge.exe", 200: "dwm.exe", 300: "explorer.exe"}
+	procs := gpuOffenders(loads, func(pid uint32) string { return names[pid] })
+	if len(procs) != 1 || procs[0].Name != "msedge.exe" || procs[0].PID != 100 {
+		t.Errorf("offenders = %+v, want only msedge.exe", procs)
+	}
+}
@@ -0,0 +1,225 @@
+//go:build windows
+
+package monitor
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	// nvml.dll ships with NVIDIA's driver in System32.
+	nvml                            = windows.NewLazySystemDLL("nvml.dll")
+	procNvmlInit                    = nvml.NewProc("nvmlInit_v2")
+	procNvmlShutdown                = nvml.NewProc("nvmlShutdown")
+	procNvmlDeviceGetHandleByIndex  = nvml.NewProc("nvmlDeviceGetHandleByIndex_v2")
+	procNvmlDeviceGetMemoryInfo     = nvml.NewProc("nvmlDeviceGetMemoryInfo")
+	procNvmlDeviceGetTemperature    = nvml.NewProc("nvmlDeviceGetTemperature")
+	pdh                             = windows.NewLazySystemDLL("pdh.dll")
+	procPdhOpenQueryW               = pdh.NewProc("PdhOpenQueryW")
+	procPdhAddEnglishCounterW       = pdh.NewProc("PdhAddEnglishCounterW")
+	procPdhCollectQueryData         = pdh.NewProc("PdhCollectQueryData")
+	procPdhGetFormattedCounterArray = pdh.NewProc("PdhGetFormattedCounterArrayW")
+	procPdhCloseQuery               = pdh.NewProc("PdhCloseQuery")
+)
+
+const (
+	pdhFmtDouble  = 0x00000200
+	pdhMoreData   = 0x800007D2
+	gpuEnginePath = `\GPU Engine(*)\Utilization Percentage`
+	// gpuMemoryPath is the dedicated video memory each adapter has in
+	// use, which the graphics kernel (D3DKMT) reports for every vendor.
+	gpuMemoryPath = `\GPU Adapter Memory(*)\Dedicated Usage`
+	// displayClassKey holds one subkey per display adapter driver.
+	displayClassKey = `SYSTEM\CurrentControlSet\Control\Class\{4d36e968-e325-11ce-bfc1-08002be10318}`
+)
+
+// nvmlMemory mirrors nvmlMemory_t.
+type nvmlMemory struct {
+	Total, Free, Used uint64
+}
+
+// nvmlTemperatureGPU is NVML_TEMPERATURE_GPU, the core sensor.
+const nvmlTemperatureGPU = 0
+
+// pdhFmtCounterValueItemDouble mirrors PDH_FMT_COUNTERVALUE_ITEM_W with a
+// double value.
+type pdhFmtCounterValueItemDouble struct {
+	Name    *uint16
+	CStatus uint32
+	_       uint32
+	Value   float64
+}
+
+// readGPUClocks asks nvidia-smi for the power state and clocks. Other
+// vendors expose no equivalent without their SDKs, so only the adapter
+// name is filled in for them.
+func readGPUClocks() GPUStatus {
+	cmd := exec.Command("nvidia-smi",
+		"--query-gpu=name,pstate,clocks.gr,clocks.mem",
+		"--format=csv,noheader,nounits")
+	// NOTE: Do NOT set HideWindow: true — it triggers AV heuristics.
+	cmd.SysProcAttr = &syscall.SysProcAttr{}
+	out, err := cmd.Output()
+	if err != nil {
+		return GPUStatus{}
+	}
+	status, _ := parseNvidiaSMI(string(out))
+	return status
+}
+
+// readGPUProcessUsage samples the "GPU Engine" performance counters, the
+// same source Task Manager uses, over one second.
+func readGPUProcessUsage() (float64, []GPUProcess) {
+	samples, err := sampleGPUEngines(time.Second)
+	if err != nil {
+		return 0, nil
+	}
+	total, loads := aggregateGPUEngines(samples)
+	return total, gpuOffenders(loads, processName)
+}
+
+// readGPUSensors reads video memory and temperature from NVML on NVIDIA
+// GPUs. Elsewhere, video memory in use comes from the graphics kernel's
+// adapter memory counters and the total from the display driver's
+// registry entry, and the temperature is unknown.
+func readGPUSensors() (used, total uint64, tempC int) {
+	if used, total, tempC, ok := readNVML(); ok {
+		return used, total, tempC
+	}
+	if samples, err := samplePDHArray(gpuMemoryPath, 0); err == nil {
+		used = sumAdapterMemory(samples)
+	}
+	return used, adapterMemorySize(), 0
+}
+
+// readNVML reads the first NVIDIA GPU's memory and temperature.
+func readNVML() (used, total uint64, tempC int, ok bool) {
+	if nvml.Load() != nil {
+		return 0, 0, 0, false
+	}
+	if r, _, _ := procNvmlInit.Call(); r != 0 {
+		return 0, 0, 0, false
+	}
+	defer procNvmlShutdown.Call()
+
+	var device uintptr
+	if r, _, _ := procNvmlDeviceGetHandleByIndex.Call(0, uintptr(unsafe.Pointer(&device))); r != 0 {
+		return 0, 0, 0, false
+	}
+	var mem nvmlMemory
+	if r, _, _ := procNvmlDeviceGetMemoryInfo.Call(device, uintptr(unsafe.Pointer(&mem))); r != 0 {
+		return 0, 0, 0, false
+	}
+	var temp uint32
+	if r, _, _ := procNvmlDeviceGetTemperature.Call(device, nvmlTemperatureGPU, uintptr(unsafe.Pointer(&temp))); r == 0 {
+		tempC = int(temp)
+	}
+	return mem.Used, mem.Total, tempC, true
+}
+
+// adapterMemorySize returns the largest display adapter's video memory, as
+// recorded by its driver, or zero.
+func adapterMemorySize() (total uint64) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, displayClassKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return 0
+	}
+	defer k.Close()
+	names, err := k.ReadSubKeyNames(-1)
+	if err != nil {
+		return 0
+	}
+	for _, name := range names {
+		sub, err := registry.OpenKey(k, name, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		if size, _, err := sub.GetIntegerValue("HardwareInformation.qwMemorySize"); err == nil && size > total {
+			total = size
+		}
+		sub.Close()
+	}
+	return total
+}
+
+func sampleGPUEngines(interval time.Duration) ([]gpuEngineSample, error) {
+	return samplePDHArray(gpuEnginePath, interval)
+}
+
+// samplePDHArray reads every instance of a performance counter. Rate
+// counters need an interval between two collections; for others, pass
+// zero to collect once.
+func samplePDHArray(counterPath string, interval time.Duration) ([]gpuEngineSample, error) {
+	var query windows.Handle
+	if r, _, _ := procPdhOpenQueryW.Call(0, 0, uintptr(unsafe.Pointer(&query))); r != 0 {
+		return nil, syscall.Errno(r)
+	}
+	defer procPdhCloseQuery.Call(uintptr(query))
+
+	path, err := windows.UTF16PtrFromString(counterPath)
+	if err != nil {
+		return nil, err
+	}
+	var counter windows.Handle
+	if r, _, _ := procPdhAddEnglishCounterW.Call(uintptr(query), uintptr(unsafe.Pointer(path)), 0, uintptr(unsafe.Pointer(&counter))); r != 0 {
+		return nil, syscall.Errno(r)
+	}
+
+	// Rate counters need two collections to produce a value.
+	if r, _, _ := procPdhCollectQueryData.Call(uintptr(query)); r != 0 {
+		return nil, syscall.Errno(r)
+	}
+	if interval > 0 {
+		time.Sleep(interval)
+		if r, _, _ := procPdhCollectQueryData.Call(uintptr(query)); r != 0 {
+			return nil, syscall.Errno(r)
+		}
+	}
+
+	var size, count uint32
+	r, _, _ := procPdhGetFormattedCounterArray.Call(uintptr(counter), pdhFmtDouble,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&count)), 0)
+	if r != pdhMoreData {
+		return nil, syscall.Errno(r)
+	}
+	buf := make([]byte, size)
+	r, _, _ = procPdhGetFormattedCounterArray.Call(uintptr(counter), pdhFmtDouble,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&buf[0])))
+	if r != 0 {
+		return nil, syscall.Errno(r)
+	}
+
+	items := unsafe.Slice((*pdhFmtCounterValueItemDouble)(unsafe.Pointer(&buf[0])), count)
+	samples := make([]gpuEngineSample, 0, count)
+	for _, item := range items {
+		samples = append(samples, gpuEngineSample{
+			Instance: windows.UTF16PtrToString(item.Name),
+			Value:    item.Value,
+		})
+	}
+	return samples, nil
+}
+
+// processName resolves a PID to its executable name.
+func processName(pid uint32) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return ""
+	}
+	path := windows.UTF16ToString(buf[:size])
+	return path[strings.LastIndex(path, `\`)+1:]
+}
@@ -0,0 +1,186 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// LatencyStats summarises the probes sent to one server.
+type LatencyStats struct {
+	Target string
+	Sent   int
+	Lost   int
+	// Avg, Min and Max are round-trip times of the answered probes.
+	Avg time.Duration
+	Min time.Duration
+	Max time.Duration
+	// Jitter is the mean difference between consecutive round-trip times.
+	Jitter time.Duration
+}
+
+// LossPercent is the share of probes that went unanswered.
+func (s LatencyStats) LossPercent() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Lost) / float64(s.Sent) * 100
+}
+
+func (s LatencyStats) String() string {
+	return fmt.Sprintf("%s: avg %v, min %v, max %v, jitter %v, loss %.1f%% (%d probes)",
+		s.Target, s.Avg.Round(time.Millisecond/10), s.Min.Round(time.Millisecond/10),
+		s.Max.Round(time.Millisecond/10), s.Jitter.Round(time.Millisecond/10), s.LossPercent(), s.Sent)
+}
+
+// latencySamples is how many probes per target LatencyProbe keeps.
+const latencySamples = 300
+
+// LatencyProbe measures round-trip time to game servers by timing TCP
+// connects, which needs no administrator rights and passes firewalls
+// that drop ping. Targets are "host:port".
+type LatencyProbe struct {
+	Targets  []string
+	Interval time.Duration // between probes of a target; 2s when zero
+	Timeout  time.Duration // before a probe counts as lost; 1s when zero
+
+	mu      sync.Mutex
+	samples map[string][]time.Duration // 0 marks a lost probe
+
+	// dial connects to a target, replaceable in tests.
+	dial func(ctx context.Context, target string) error
+}
+
+// ValidateLatencyTarget reports whether target is a "host:port" a probe
+// can connect to.
+func ValidateLatencyTarget(target string) error {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil || host == "" || port == "" {
+		return fmt.Errorf("invalid server %q: want host:port", target)
+	}
+	return nil
+}
+
+// Run probes every target until ctx is done.
+func (p *LatencyProbe) Run(ctx context.Context) {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var wg sync.WaitGroup
+		for _, target := range p.Targets {
+			wg.Add(1)
+			go func(target string) {
+				defer wg.Done()
+				p.probe(ctx, target)
+			}(target)
+		}
+		wg.Wait()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe times one connect to target and records it.
+func (p *LatencyProbe) probe(ctx context.Context, target string) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	dial := p.dial
+	if dial == nil {
+		dial = dialTCP
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dial(ctx, target)
+	rtt := time.Since(start)
+	if ctx.Err() == context.Canceled {
+		return // shutting down, not a lost probe
+	}
+	if err != nil {
+		rtt = 0
+	} else if rtt == 0 {
+		rtt = 1 // keep answered probes distinct from lost ones
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.samples == nil {
+		p.samples = make(map[string][]time.Duration)
+	}
+	s := append(p.samples[target], rtt)
+	if len(s) > latencySamples {
+		s = s[len(s)-latencySamples:]
+	}
+	p.samples[target] = s
+}
+
+func dialTCP(ctx context.Context, target string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Stats returns the statistics of every target, in Targets order.
+func (p *LatencyProbe) Stats() []LatencyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]LatencyStats, 0, len(p.Targets))
+	for _, target := range p.Targets {
+		s := latencyStats(p.samples[target])
+		s.Target = target
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// latencyStats summarises round-trip times, where 0 marks a lost probe.
+func latencyStats(samples []time.Duration) LatencyStats {
+	s := LatencyStats{Sent: len(samples)}
+	var total, jitter time.Duration
+	var prev time.Duration
+	answered, pairs := 0, 0
+	for _, rtt := range samples {
+		if rtt == 0 {
+			s.Lost++
+			continue
+		}
+		if answered == 0 || rtt < s.Min {
+			s.Min = rtt
+		}
+		s.Max = max(s.Max, rtt)
+		total += rtt
+		if answered > 0 {
+			d := rtt - prev
+			if d < 0 {
+				d = -d
+			}
+			jitter += d
+			pairs++
+		}
+		prev = rtt
+		answered++
+	}
+	if answered > 0 {
+		s.Avg = total / time.Duration(answered)
+	}
+	if pairs > 0 {
+		s.Jitter = jitter / time.Duration(pairs)
+	}
+	return s
+}
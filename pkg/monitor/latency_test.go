@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencyStats(t *testing.T) {
+	ms := time.Millisecond
+	s := latencyStats([]time.Duration{20 * ms, 0, 30 * ms, 10 * ms})
+	if s.Sent != 4 || s.Lost != 1 || s.LossPercent() != 25 {
+		t.Errorf("sent/lost = %d/%d (%.0f%%), want 4/1 (25%%)", s.Sent, s.Lost, s.LossPercent())
+	}
+	if s.Avg != 20*ms || s.Min != 10*ms || s.Max != 30*ms {
+		t.Errorf("avg/min/max = %v/%v/%v, want 20ms/10ms/30ms", s.Avg, s.Min, s.Max)
+	}
+	// |30-20| and |10-30|.
+	if s.Jitter != 15*ms {
+		t.Errorf("Jitter = %v, want 15ms", s.Jitter)
+	}
+}
+
+func TestLatencyProbe_RecordsLostProbes(t *testing.T) {
+	p := &LatencyProbe{Targets: []string{"up:1", "down:1"}}
+	p.dial = func(ctx context.Context, target string) error {
+		if target == "down:1" {
+			return errors.New("refused")
+		}
+		return nil
+	}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		for _, target := range p.Targets {
+			p.probe(ctx, target)
+		}
+	}
+
+	stats := p.Stats()
+	if stats[0].Target != "up:1" || stats[0].Sent != 3 || stats[0].Lost != 0 {
+		t.Errorf("up: %+v", stats[0])
+	}
+	if stats[1].Sent != 3 || stats[1].Lost != 3 {
+		t.Errorf("down: %+v", stats[1])
+	}
+}
+
+func TestValidateLatencyTarget(t *testing.T) {
+	for target, ok := range map[string]bool{
+		"euw1.example.net:443": true,
+		"10.0.0.1:27015":       true,
+		"[::1]:80":             true,
+		"example.net":          false,
+		":443":                 false,
+	} {
+		if err := ValidateLatencyTarget(target); (err == nil) != ok {
+			t.Errorf("ValidateLatencyTarget(%q) = %v", target, err)
+		}
+	}
+}
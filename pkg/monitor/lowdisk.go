@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// LowDiskWatcher polls a set of drives and calls OnLow when one drops below
+// a free-space threshold. Each drive triggers at most once per Cooldown so a
+// clean that cannot free enough space does not run in a loop.
+type LowDiskWatcher struct {
+	Drives           []string      // e.g. "C:", "D:"
+	ThresholdPercent float64       // trigger when free % is below this (0 disables)
+	ThresholdGB      float64       // trigger when free GB is below this (0 disables)
+	Interval         time.Duration // polling interval
+	Cooldown         time.Duration // minimum time between triggers per drive
+	OnLow            func(DiskStatus)
+
+	mu          sync.Mutex
+	done        chan struct{}
+	lastTrigger map[string]time.Time
+
+	// check is CheckDrive, replaceable in tests.
+	check func(string) DiskStatus
+}
+
+// Start begins polling in a background goroutine. Calling Start on a
+// running watcher has no effect.
+func (w *LowDiskWatcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		return
+	}
+	w.done = make(chan struct{})
+	done := w.done
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		w.Poll()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				w.Poll()
+			}
+		}
+	}()
+}
+
+// Stop ends polling. It is safe to call on a stopped watcher.
+func (w *LowDiskWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		close(w.done)
+		w.done = nil
+	}
+}
+
+// Poll checks every drive once and fires OnLow for those below threshold.
+func (w *LowDiskWatcher) Poll() {
+	check := w.check
+	if check == nil {
+		check = CheckDrive
+	}
+
+	for _, drive := range w.Drives {
+		status := check(drive)
+		if status.TotalGB == 0 || !w.isLow(status) {
+			continue
+		}
+
+		w.mu.Lock()
+		if w.lastTrigger == nil {
+			w.lastTrigger = make(map[string]time.Time)
+		}
+		last, seen := w.lastTrigger[status.DriveLetter]
+		ready := !seen || time.Since(last) >= w.Cooldown
+		if ready {
+			w.lastTrigger[status.DriveLetter] = time.Now()
+		}
+		w.mu.Unlock()
+
+		if ready && w.OnLow != nil {
+			w.OnLow(status)
+		}
+	}
+}
+
+func (w *LowDiskWatcher) isLow(s DiskStatus) bool {
+	if w.ThresholdPercent > 0 && s.FreePercent < w.ThresholdPercent {
+		return true
+	}
+	return w.ThresholdGB > 0 && s.FreeGB < w.ThresholdGB
+}
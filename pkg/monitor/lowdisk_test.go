@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLowDiskWatcher_TriggersOncePerCooldown(t *testing.T) {
+	free := 5.0
+	var triggered []string
+	w := &LowDiskWatcher{
+		Drives:           []string{"C:", "D:"},
+		ThresholdPercent: 10,
+		Cooldown:         time.Hour,
+		OnLow:            func(s DiskStatus) { triggered = append(triggered, s.DriveLetter) },
+		check: func(drive string) DiskStatus {
+			if drive == "D:" {
+				return DiskStatus{DriveLetter: drive, TotalGB: 100, FreeGB: 50, FreePercent: 50}
+			}
+			return DiskStatus{DriveLetter: drive, TotalGB: 100, FreeGB: free, FreePercent: free}
+		},
+	}
+
+	w.Poll()
+	w.Poll()
+	if len(triggered) != 1 || triggered[0] != "C:" {
+		t.Fatalf("expected a single trigger for C:, got %v", triggered)
+	}
+
+	w.Cooldown = 0
+	w.Poll()
+	if len(triggered) != 2 {
+		t.Errorf("expected a second trigger after cooldown, got %v", triggered)
+	}
+}
+
+func TestLowDiskWatcher_GBThreshold(t *testing.T) {
+	w := &LowDiskWatcher{ThresholdGB: 20}
+	if !w.isLow(DiskStatus{FreeGB: 15, FreePercent: 30}) {
+		t.Error("expected 15 GB free to be below a 20 GB threshold")
+	}
+	if w.isLow(DiskStatus{FreeGB: 25, FreePercent: 5}) {
+		t.Error("percent threshold is disabled, so 25 GB free should not trigger")
+	}
+}
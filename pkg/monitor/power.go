@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"syscleaner/pkg/sysinfo"
+)
+
+// PowerWatcher polls the power source and dock state and calls OnChange
+// with the first reading and whenever the machine moves between AC and
+// battery or is docked or undocked. Battery level changes alone do not
+// fire.
+type PowerWatcher struct {
+	Interval time.Duration // polling interval
+	OnChange func(sysinfo.PowerStatus)
+
+	mu   sync.Mutex
+	done chan struct{}
+	last *sysinfo.PowerStatus
+
+	// check is sysinfo.Power, replaceable in tests.
+	check func() sysinfo.PowerStatus
+}
+
+// Start begins polling in a background goroutine. Calling Start on a
+// running watcher has no effect.
+func (w *PowerWatcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		return
+	}
+	w.done = make(chan struct{})
+	done := w.done
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		w.Poll()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				w.Poll()
+			}
+		}
+	}()
+}
+
+// Stop ends polling. It is safe to call on a stopped watcher.
+func (w *PowerWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		close(w.done)
+		w.done = nil
+	}
+}
+
+// Poll reads the power state once and fires OnChange if it changed.
+func (w *PowerWatcher) Poll() {
+	check := w.check
+	if check == nil {
+		check = sysinfo.Power
+	}
+	status := check()
+	if !status.Known {
+		return
+	}
+
+	w.mu.Lock()
+	changed := w.last == nil || w.last.OnBattery != status.OnBattery ||
+		w.last.DockKnown != status.DockKnown || w.last.Docked != status.Docked
+	w.last = &status
+	w.mu.Unlock()
+
+	if changed && w.OnChange != nil {
+		w.OnChange(status)
+	}
+}
@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"testing"
+
+	"syscleaner/pkg/sysinfo"
+)
+
+func TestPowerWatcher_FiresOnSourceAndDockChanges(t *testing.T) {
+	status := sysinfo.PowerStatus{Known: true, BatteryPercent: 80}
+	var got []sysinfo.PowerStatus
+	w := &PowerWatcher{
+		OnChange: func(s sysinfo.PowerStatus) { got = append(got, s) },
+		check:    func() sysinfo.PowerStatus { return status },
+	}
+
+	w.Poll()
+	status.BatteryPercent = 70
+	w.Poll()
+	if len(got) != 1 {
+		t.Fatalf("expected only the first reading, got %d calls", len(got))
+	}
+
+	status.OnBattery = true
+	w.Poll()
+	status.DockKnown, status.Docked = true, true
+	w.Poll()
+	if len(got) != 3 || !got[1].OnBattery || !got[2].Docked {
+		t.Errorf("unexpected changes %+v", got)
+	}
+
+	status.Known = false
+	w.Poll()
+	if len(got) != 3 {
+		t.Error("an unknown power state should not fire")
+	}
+}
@@ -0,0 +1,27 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimerResolution is the system timer's resolution. Windows runs the
+// timer at the finest resolution any process has asked for, so Current
+// shows whether a timer boost is in effect.
+type TimerResolution struct {
+	Current time.Duration
+	// Coarsest and Finest bound what the hardware supports, typically
+	// 15.625 ms and 0.5 ms.
+	Coarsest time.Duration
+	Finest   time.Duration
+}
+
+// CheckTimerResolution reads the system timer resolution.
+func CheckTimerResolution() (TimerResolution, error) {
+	return readTimerResolution()
+}
+
+// String formats the current resolution in milliseconds, e.g. "0.50 ms".
+func (r TimerResolution) String() string {
+	return fmt.Sprintf("%.2f ms", float64(r.Current)/float64(time.Millisecond))
+}
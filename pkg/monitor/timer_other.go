@@ -0,0 +1,10 @@
+//go:build !windows
+
+package monitor
+
+import "fmt"
+
+// readTimerResolution is only available on Windows.
+func readTimerResolution() (TimerResolution, error) {
+	return TimerResolution{}, fmt.Errorf("timer resolution is only available on Windows")
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package monitor
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procNtQueryTimerResolution = windows.NewLazySystemDLL("ntdll.dll").NewProc("NtQueryTimerResolution")
+
+// readTimerResolution calls NtQueryTimerResolution, which reports in
+// 100 ns units.
+func readTimerResolution() (TimerResolution, error) {
+	var coarsest, finest, current uint32
+	status, _, _ := procNtQueryTimerResolution.Call(
+		uintptr(unsafe.Pointer(&coarsest)),
+		uintptr(unsafe.Pointer(&finest)),
+		uintptr(unsafe.Pointer(&current)))
+	if status != 0 {
+		return TimerResolution{}, fmt.Errorf("NtQueryTimerResolution: NTSTATUS 0x%x", status)
+	}
+	return TimerResolution{
+		Current:  time.Duration(current) * 100,
+		Coarsest: time.Duration(coarsest) * 100,
+		Finest:   time.Duration(finest) * 100,
+	}, nil
+}
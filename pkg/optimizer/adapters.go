@@ -0,0 +1,484 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/sysrestore"
+)
+
+// Network adapter settings TuneAdapters manages. Each is on or off.
+const (
+	// AdapterPowerSaving is "Allow the computer to turn off this device to
+	// save power" in Device Manager.
+	AdapterPowerSaving = "power-saving"
+	// AdapterRSS is receive-side scaling, spreading received packets over
+	// several CPU cores.
+	AdapterRSS = "rss"
+	// AdapterInterruptModeration batches interrupts, saving CPU time at the
+	// cost of latency.
+	AdapterInterruptModeration = "interrupt-moderation"
+	// AdapterChecksumOffload has the adapter compute and check IP, TCP and
+	// UDP checksums instead of the CPU.
+	AdapterChecksumOffload = "checksum-offload"
+	// AdapterLSO is Large Send Offload: the adapter splits large outgoing
+	// buffers into packets.
+	AdapterLSO = "lso"
+	// AdapterRSC is Receive Segment Coalescing: the adapter merges
+	// received packets before handing them on, which holds them back.
+	AdapterRSC = "rsc"
+)
+
+// adapterSetting is how an adapter setting is read and written with the
+// NetAdapter cmdlets. Values are kept as the driver reports them, so a
+// restore puts back exactly what it had.
+type adapterSetting struct {
+	Name  string
+	Label string
+	// read is PowerShell that sets $v to the setting's value, a string,
+	// for the adapter named $n, and leaves it $null when the adapter lacks
+	// the setting.
+	read string
+	// isOn reports whether a value has the setting on.
+	isOn func(value string) bool
+	// value returns the value that turns the setting on or off, given its
+	// current one.
+	value func(current string, on bool) string
+	// write returns PowerShell that sets the setting of the adapter named
+	// by the quoted name to value.
+	write func(quoted, value string) string
+}
+
+// adapterSettings are the settings TuneAdapters knows, in display order.
+var adapterSettings = []adapterSetting{
+	{
+		Name:  AdapterPowerSaving,
+		Label: "Power saving",
+		read: `$p = Get-NetAdapterPowerManagement -Name $n -ErrorAction SilentlyContinue; ` +
+			`if ($p -and [string]$p.AllowComputerToTurnOffDevice -in 'Enabled','Disabled') { $v = [string]$p.AllowComputerToTurnOffDevice }`,
+		isOn:  func(value string) bool { return value == "Enabled" },
+		value: func(_ string, on bool) string { return onOff(on, "Enabled", "Disabled") },
+		write: func(quoted, value string) string {
+			return fmt.Sprintf("Set-NetAdapterPowerManagement -Name %s -AllowComputerToTurnOffDevice %s", quoted, value)
+		},
+	},
+	{
+		Name:  AdapterRSS,
+		Label: "Receive-side scaling",
+		read:  `$r = Get-NetAdapterRss -Name $n -ErrorAction SilentlyContinue; if ($r) { $v = [string][bool]$r.Enabled }`,
+		isOn:  func(value string) bool { return value == "True" },
+		value: func(_ string, on bool) string { return onOff(on, "True", "False") },
+		write: func(quoted, value string) string {
+			return fmt.Sprintf("%s-NetAdapterRss -Name %s", onOff(value == "True", "Enable", "Disable"), quoted)
+		},
+	},
+	advancedAdapterSetting(AdapterInterruptModeration, "Interrupt moderation", "1", "*InterruptModeration"),
+	// 3 is "Rx & Tx Enabled"; 1 and 2 are Tx or Rx only.
+	advancedAdapterSetting(AdapterChecksumOffload, "Checksum offload", "3",
+		"*IPChecksumOffloadIPv4", "*TCPChecksumOffloadIPv4", "*TCPChecksumOffloadIPv6",
+		"*UDPChecksumOffloadIPv4", "*UDPChecksumOffloadIPv6"),
+	advancedAdapterSetting(AdapterLSO, "Large send offload", "1", "*LsoV2IPv4", "*LsoV2IPv6"),
+	advancedAdapterSetting(AdapterRSC, "Receive segment coalescing", "1", "*RscIPv4", "*RscIPv6"),
+}
+
+// advancedAdapterSetting is a setting kept in standardized advanced
+// properties of the driver, keywords whose registry value is 0 when off
+// and on when on. Its value lists the keywords the driver has with their
+// values, e.g. "*LsoV2IPv4=1;*LsoV2IPv6=1". The setting is on when any
+// keyword is, and all of them are switched together.
+func advancedAdapterSetting(name, label, on string, keywords ...string) adapterSetting {
+	quoted := make([]string, len(keywords))
+	for i, k := range keywords {
+		quoted[i] = "'" + k + "'"
+	}
+	return adapterSetting{
+		Name:  name,
+		Label: label,
+		read: fmt.Sprintf(`$parts = @(); foreach ($k in @(%s)) { `+
+			`$a = Get-NetAdapterAdvancedProperty -Name $n -RegistryKeyword $k -ErrorAction SilentlyContinue; `+
+			`if ($a) { $parts += $k + '=' + [string]$a.RegistryValue } }; if ($parts) { $v = $parts -join ';' }`,
+			strings.Join(quoted, ",")),
+		isOn: func(value string) bool {
+			for _, kv := range keywordValues(value) {
+				if kv[1] != "0" {
+					return true
+				}
+			}
+			return false
+		},
+		value: func(current string, enable bool) string {
+			kvs := keywordValues(current)
+			parts := make([]string, len(kvs))
+			for i, kv := range kvs {
+				parts[i] = kv[0] + "=" + onOff(enable, on, "0")
+			}
+			return strings.Join(parts, ";")
+		},
+		write: func(quoted, value string) string {
+			cmds := make([]string, 0, len(keywords))
+			for _, kv := range keywordValues(value) {
+				cmds = append(cmds, fmt.Sprintf("Set-NetAdapterAdvancedProperty -Name %s -RegistryKeyword '%s' -RegistryValue '%s'",
+					quoted, strings.ReplaceAll(kv[0], "'", "''"), strings.ReplaceAll(kv[1], "'", "''")))
+			}
+			return strings.Join(cmds, "; ")
+		},
+	}
+}
+
+// keywordValues splits an advanced setting's value into keyword and value
+// pairs.
+func keywordValues(value string) [][2]string {
+	var kvs [][2]string
+	for _, part := range strings.Split(value, ";") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			kvs = append(kvs, [2]string{k, v})
+		}
+	}
+	return kvs
+}
+
+func onOff(on bool, yes, no string) string {
+	if on {
+		return yes
+	}
+	return no
+}
+
+func findAdapterSetting(name string) (adapterSetting, bool) {
+	for _, s := range adapterSettings {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return adapterSetting{}, false
+}
+
+// AdapterSettings returns the names of the settings TuneAdapters knows,
+// in display order.
+func AdapterSettings() []string {
+	names := make([]string, len(adapterSettings))
+	for i, s := range adapterSettings {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// AdapterSettingLabel names a setting for display.
+func AdapterSettingLabel(name string) string {
+	if s, ok := findAdapterSetting(name); ok {
+		return s.Label
+	}
+	return name
+}
+
+// NetworkAdapter is a physical network adapter and the settings it
+// supports.
+type NetworkAdapter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Settings holds each supported setting's value as the driver reports
+	// it. A setting the driver lacks is missing, which is the capability
+	// probe.
+	Settings map[string]string `json:"settings"`
+}
+
+// Supports reports whether the adapter has a setting.
+func (a NetworkAdapter) Supports(setting string) bool {
+	_, ok := a.Settings[setting]
+	return ok
+}
+
+// Enabled reports whether a setting is on, and whether the adapter has it.
+func (a NetworkAdapter) Enabled(setting string) (on, supported bool) {
+	value, supported := a.Settings[setting]
+	s, known := findAdapterSetting(setting)
+	if !supported || !known {
+		return false, false
+	}
+	return s.isOn(value), true
+}
+
+// AdapterTuning is the state TuneAdapters sets each setting to; settings
+// not in it are left alone.
+type AdapterTuning map[string]bool
+
+// GamingAdapterTuning is the recommended tuning for games. It keeps
+// adapters awake, spreads receive work over cores and delivers every
+// packet's interrupt at once, trading some CPU time for latency. Checksums
+// stay offloaded, which costs no latency, while LSO and RSC, which hold
+// packets back to batch them, are turned off; RSC in particular causes
+// latency spikes with some Realtek drivers.
+func GamingAdapterTuning() AdapterTuning {
+	return AdapterTuning{
+		AdapterPowerSaving:         false,
+		AdapterRSS:                 true,
+		AdapterInterruptModeration: false,
+		AdapterChecksumOffload:     true,
+		AdapterLSO:                 false,
+		AdapterRSC:                 false,
+	}
+}
+
+// AdapterChange is a setting TuneAdapters changed, or in a dry run would
+// change.
+type AdapterChange struct {
+	Adapter string
+	Setting string
+	From    bool
+	To      bool
+
+	// fromValue and toValue are the driver's values.
+	fromValue string
+	toValue   string
+}
+
+// AdaptersResult holds network adapter tuning results.
+type AdaptersResult struct {
+	Changes []AdapterChange
+	// Skipped lists the settings adapters don't support, as
+	// "adapter: setting".
+	Skipped     []string
+	Errors      []string
+	DryRun      bool
+	OperationID string
+}
+
+// These are variables so tests can fake the adapters.
+var (
+	listAdapters     = listAdaptersNative
+	setAdapterOption = setAdapterOptionNative
+)
+
+// adaptersBackupPath is where adapter settings are kept before SysCleaner
+// changes them, by adapter and setting.
+var adaptersBackupPath = func() string {
+	return backupPath("adapters-backup.json")
+}
+
+// ListAdapters returns the physical network adapters with the settings
+// each supports.
+func ListAdapters() ([]NetworkAdapter, error) {
+	return listAdapters()
+}
+
+// TuneAdapters sets the supported settings of the named adapter, or of
+// every physical adapter when name is "", to tuning, after making a
+// restore point (see sysrestore.Before). Each adapter restarts, dropping
+// its connection for a few seconds, as its driver applies a change. The
+// values before SysCleaner first changed them are kept for
+// RestoreAdapters.
+func TuneAdapters(name string, tuning AdapterTuning, opts OptimizeOptions) AdaptersResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-adapters", dryRun))
+	defer op.End()
+	result := AdaptersResult{DryRun: dryRun, OperationID: op.ID}
+
+	adapters, err := listAdapters()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	adapters, err = selectAdapters(adapters, name)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	changes, skipped := planAdapterChanges(adapters, tuning)
+	result.Skipped = skipped
+	if dryRun || len(changes) == 0 {
+		result.Changes = changes
+		return result
+	}
+	if err := admin.RequireWriteAccess("tuning network adapters"); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	backup, err := loadBackupMap[map[string]string](adaptersBackupPath())
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	for _, c := range changes {
+		if _, ok := backup[c.Adapter][c.Setting]; ok {
+			continue
+		}
+		if backup[c.Adapter] == nil {
+			backup[c.Adapter] = make(map[string]string)
+		}
+		backup[c.Adapter][c.Setting] = c.fromValue
+	}
+	if err := saveBackup(adaptersBackupPath(), backup); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("saving the adapter settings: %v", err))
+		return result
+	}
+	if runtime.GOOS == "windows" {
+		sysrestore.Before("network adapter tuning")
+	}
+	for _, c := range changes {
+		if err := setAdapterOption(c.Adapter, c.Setting, c.toValue); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s: %v", c.Adapter, AdapterSettingLabel(c.Setting), err))
+			continue
+		}
+		log.Printf("[SysCleaner] Set %s on adapter %s to %s (was %s)", c.Setting, c.Adapter, c.toValue, c.fromValue)
+		result.Changes = append(result.Changes, c)
+	}
+	return result
+}
+
+// selectAdapters returns the adapter named name, matched case-
+// insensitively, or all of them when name is "".
+func selectAdapters(adapters []NetworkAdapter, name string) ([]NetworkAdapter, error) {
+	if name == "" {
+		return adapters, nil
+	}
+	for _, a := range adapters {
+		if strings.EqualFold(a.Name, name) {
+			return []NetworkAdapter{a}, nil
+		}
+	}
+	return nil, fmt.Errorf("no physical network adapter named %q", name)
+}
+
+// planAdapterChanges lists the settings that differ from tuning, and the
+// settings in tuning adapters don't support.
+func planAdapterChanges(adapters []NetworkAdapter, tuning AdapterTuning) ([]AdapterChange, []string) {
+	var changes []AdapterChange
+	var skipped []string
+	for _, a := range adapters {
+		for _, s := range adapterSettings {
+			want, ok := tuning[s.Name]
+			if !ok {
+				continue
+			}
+			current, supported := a.Settings[s.Name]
+			switch {
+			case !supported:
+				skipped = append(skipped, a.Name+": "+s.Label)
+			case s.isOn(current) != want:
+				changes = append(changes, AdapterChange{Adapter: a.Name, Setting: s.Name, From: !want, To: want,
+					fromValue: current, toValue: s.value(current, want)})
+			}
+		}
+	}
+	return changes, skipped
+}
+
+// RestoreAdapters puts back every adapter setting SysCleaner changed. It
+// returns how many were restored; those that could not be are reported in
+// the error and kept for another try.
+func RestoreAdapters() (int, error) {
+	if err := admin.RequireWriteAccess("restoring network adapter settings"); err != nil {
+		return 0, err
+	}
+	backup, err := loadBackupMap[map[string]string](adaptersBackupPath())
+	if err != nil {
+		return 0, err
+	}
+	if len(backup) == 0 {
+		return 0, errors.New("SysCleaner has not changed any network adapter settings")
+	}
+	names := make([]string, 0, len(backup))
+	for name := range backup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	restored := 0
+	var errs []error
+	for _, name := range names {
+		for _, s := range adapterSettings {
+			value, ok := backup[name][s.Name]
+			if !ok {
+				continue
+			}
+			if err := setAdapterOption(name, s.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", name, s.Label, err))
+				continue
+			}
+			log.Printf("[SysCleaner] Restored %s on adapter %s to %s", s.Name, name, value)
+			delete(backup[name], s.Name)
+			restored++
+		}
+		if len(backup[name]) == 0 {
+			delete(backup, name)
+		}
+	}
+	if err := saveBackup(adaptersBackupPath(), backup); err != nil {
+		errs = append(errs, err)
+	}
+	return restored, errors.Join(errs...)
+}
+
+// adaptersScript prints the physical adapters as NetworkAdapter in JSON,
+// probing each for the settings in adapterSettings.
+func adaptersScript() string {
+	var b strings.Builder
+	b.WriteString("ConvertTo-Json -Compress -Depth 3 -InputObject @(Get-NetAdapter -Physical | ForEach-Object { ")
+	b.WriteString("$n = $_.Name; $s = @{}; ")
+	for _, s := range adapterSettings {
+		fmt.Fprintf(&b, "$v = $null; %s; if ($null -ne $v) { $s['%s'] = $v }; ", s.read, s.Name)
+	}
+	b.WriteString("[pscustomobject]@{ name = $n; description = $_.InterfaceDescription; settings = $s } })")
+	return b.String()
+}
+
+// adapterOptionScript returns a script that sets a setting of the named
+// adapter to value.
+func adapterOptionScript(adapter, setting, value string) (string, error) {
+	s, ok := findAdapterSetting(setting)
+	if !ok {
+		return "", fmt.Errorf("unknown adapter setting %q", setting)
+	}
+	quoted := "'" + strings.ReplaceAll(adapter, "'", "''") + "'"
+	return "$ErrorActionPreference = 'Stop'; " + s.write(quoted, value), nil
+}
+
+// parseAdapters parses the output of adaptersScript.
+func parseAdapters(data []byte) ([]NetworkAdapter, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var adapters []NetworkAdapter
+	if err := json.Unmarshal(data, &adapters); err != nil {
+		return nil, fmt.Errorf("parsing network adapters: %w", err)
+	}
+	for i := range adapters {
+		if adapters[i].Settings == nil {
+			adapters[i].Settings = make(map[string]string)
+		}
+	}
+	return adapters, nil
+}
+
+// PrintAdaptersResult displays network adapter tuning results.
+func PrintAdaptersResult(result AdaptersResult) {
+	verb := "Changed"
+	if result.DryRun {
+		verb = "Would change"
+	}
+	fmt.Printf("  %s %d adapter setting(s)\n", verb, len(result.Changes))
+	for _, c := range result.Changes {
+		fmt.Printf("    %s: %s %s -> %s\n", c.Adapter, AdapterSettingLabel(c.Setting),
+			onOff(c.From, "on", "off"), onOff(c.To, "on", "off"))
+	}
+	for _, s := range result.Skipped {
+		fmt.Printf("    Not supported: %s\n", s)
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", strings.TrimSpace(e))
+	}
+	printOperation(result.OperationID)
+	if len(result.Changes) > 0 && !result.DryRun {
+		fmt.Println("  Restore with: syscleaner adapters --restore")
+	}
+}
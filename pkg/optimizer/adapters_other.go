@@ -0,0 +1,13 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func listAdaptersNative() ([]NetworkAdapter, error) {
+	return nil, fmt.Errorf("network adapter tuning is only available on Windows")
+}
+
+func setAdapterOptionNative(adapter, setting, value string) error {
+	return fmt.Errorf("network adapter tuning is only available on Windows")
+}
@@ -0,0 +1,138 @@
+package optimizer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAdapters(t *testing.T) {
+	adapters, err := parseAdapters([]byte(`[{"name":"Ethernet","description":"Realtek PCIe GbE Family Controller",` +
+		`"settings":{"power-saving":"Enabled","rss":"False","interrupt-moderation":"*InterruptModeration=1",` +
+		`"checksum-offload":"*IPChecksumOffloadIPv4=3;*TCPChecksumOffloadIPv4=1","rsc":"*RscIPv4=0;*RscIPv6=0"}},` +
+		`{"name":"Wi-Fi","description":"Intel Wi-Fi 6 AX201","settings":{"power-saving":"Disabled"}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adapters) != 2 {
+		t.Fatalf("got %d adapters, want 2", len(adapters))
+	}
+	for setting, want := range map[string]bool{
+		AdapterPowerSaving:         true,
+		AdapterRSS:                 false,
+		AdapterInterruptModeration: true,
+		AdapterChecksumOffload:     true,
+		AdapterRSC:                 false,
+	} {
+		if on, ok := adapters[0].Enabled(setting); !ok || on != want {
+			t.Errorf("Ethernet %s = %v, %v; want %v and supported", setting, on, ok, want)
+		}
+	}
+	if adapters[0].Supports(AdapterLSO) || adapters[1].Supports(AdapterInterruptModeration) {
+		t.Errorf("unsupported settings reported: %+v", adapters)
+	}
+
+	if adapters, err := parseAdapters([]byte(" \r\n")); err != nil || adapters != nil {
+		t.Errorf("empty output = %v, %v; want no adapters", adapters, err)
+	}
+	if _, err := parseAdapters([]byte("not json")); err == nil {
+		t.Error("invalid output should fail to parse")
+	}
+}
+
+func TestAdapterScripts(t *testing.T) {
+	script := adaptersScript()
+	for _, s := range adapterSettings {
+		if !strings.Contains(script, "$s['"+s.Name+"']") {
+			t.Errorf("adaptersScript does not probe %s", s.Name)
+		}
+	}
+
+	got, err := adapterOptionScript("Bob's NIC", AdapterInterruptModeration, "*InterruptModeration=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "-Name 'Bob''s NIC' -RegistryKeyword '*InterruptModeration' -RegistryValue '0'") {
+		t.Errorf("interrupt moderation script = %q", got)
+	}
+	got, _ = adapterOptionScript("Ethernet", AdapterLSO, "*LsoV2IPv4=1;*LsoV2IPv6=0")
+	if strings.Count(got, "Set-NetAdapterAdvancedProperty") != 2 || !strings.Contains(got, "'*LsoV2IPv6' -RegistryValue '0'") {
+		t.Errorf("LSO script = %q", got)
+	}
+	got, _ = adapterOptionScript("Ethernet", AdapterPowerSaving, "Disabled")
+	if !strings.HasSuffix(got, "Set-NetAdapterPowerManagement -Name 'Ethernet' -AllowComputerToTurnOffDevice Disabled") {
+		t.Errorf("power saving script = %q", got)
+	}
+	if _, err := adapterOptionScript("Ethernet", "jumbo", "9014"); err == nil {
+		t.Error("an unknown setting should be rejected")
+	}
+}
+
+func TestTuneAdapters_Restore(t *testing.T) {
+	dir := t.TempDir()
+	origPath, origList, origSet := adaptersBackupPath, listAdapters, setAdapterOption
+	t.Cleanup(func() { adaptersBackupPath, listAdapters, setAdapterOption = origPath, origList, origSet })
+
+	adapters := []NetworkAdapter{
+		{Name: "Ethernet", Settings: map[string]string{
+			AdapterPowerSaving:         "Enabled",
+			AdapterRSS:                 "False",
+			AdapterInterruptModeration: "*InterruptModeration=1",
+			AdapterChecksumOffload:     "*TCPChecksumOffloadIPv4=1;*UDPChecksumOffloadIPv4=0",
+			AdapterLSO:                 "*LsoV2IPv4=1;*LsoV2IPv6=1",
+			AdapterRSC:                 "*RscIPv4=1;*RscIPv6=0",
+		}},
+		{Name: "Wi-Fi", Settings: map[string]string{AdapterPowerSaving: "Disabled"}},
+	}
+	original := make(map[string]string)
+	for k, v := range adapters[0].Settings {
+		original[k] = v
+	}
+	adaptersBackupPath = func() string { return dir + "/adapters-backup.json" }
+	listAdapters = func() ([]NetworkAdapter, error) { return adapters, nil }
+	setAdapterOption = func(adapter, setting, value string) error {
+		for _, a := range adapters {
+			if a.Name == adapter {
+				a.Settings[setting] = value
+			}
+		}
+		return nil
+	}
+
+	// Checksum offload is partly on (Tx only), which counts as on.
+	preview := TuneAdapters("", GamingAdapterTuning(), OptimizeOptions{DryRun: true})
+	if len(preview.Changes) != 5 || len(preview.Skipped) != 5 || adapters[0].Settings[AdapterPowerSaving] != "Enabled" {
+		t.Fatalf("dry run = %+v; want 5 changes, 5 skipped and nothing changed", preview)
+	}
+
+	result := TuneAdapters("ethernet", GamingAdapterTuning(), OptimizeOptions{})
+	if len(result.Errors) > 0 || len(result.Changes) != 5 {
+		t.Fatalf("TuneAdapters = %+v; want five changes", result)
+	}
+	if got := adapters[0].Settings[AdapterRSC]; got != "*RscIPv4=0;*RscIPv6=0" {
+		t.Errorf("RSC = %q, want both keywords off", got)
+	}
+	// A second change keeps the first original value.
+	result = TuneAdapters("", AdapterTuning{AdapterLSO: true, AdapterPowerSaving: true}, OptimizeOptions{})
+	if len(result.Errors) > 0 || len(result.Changes) != 3 {
+		t.Fatalf("TuneAdapters = %+v; want three changes", result)
+	}
+	if r := TuneAdapters("Bluetooth", GamingAdapterTuning(), OptimizeOptions{}); len(r.Errors) == 0 {
+		t.Error("an unknown adapter should be an error")
+	}
+
+	n, err := RestoreAdapters()
+	if err != nil || n != 6 {
+		t.Fatalf("RestoreAdapters = %d, %v; want 6 restored", n, err)
+	}
+	for setting, value := range original {
+		if adapters[0].Settings[setting] != value {
+			t.Errorf("Ethernet %s = %q after restore, want %q", setting, adapters[0].Settings[setting], value)
+		}
+	}
+	if adapters[1].Settings[AdapterPowerSaving] != "Disabled" {
+		t.Error("Wi-Fi power saving should be back off")
+	}
+	if _, err := RestoreAdapters(); err == nil {
+		t.Error("restoring with nothing changed should be an error")
+	}
+}
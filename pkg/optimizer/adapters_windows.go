@@ -0,0 +1,38 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func runAdapterScript(script string) ([]byte, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// listAdaptersNative probes the physical adapters with the NetAdapter
+// cmdlets, which read the MSFT_NetAdapter* WMI classes.
+func listAdaptersNative() ([]NetworkAdapter, error) {
+	out, err := runAdapterScript(adaptersScript())
+	if err != nil {
+		return nil, fmt.Errorf("listing network adapters: %w", err)
+	}
+	return parseAdapters(out)
+}
+
+func setAdapterOptionNative(adapter, setting, value string) error {
+	script, err := adapterOptionScript(adapter, setting, value)
+	if err != nil {
+		return err
+	}
+	_, err = runAdapterScript(script)
+	return err
+}
@@ -0,0 +1,68 @@
+package optimizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// backupPath returns where the backup file name is kept, in SysCleaner's
+// folder in the user config directory. Services, tasks, the page file,
+// network adapters and MTUs each keep what they had before SysCleaner
+// first changed them in such a file, for their restore.
+func backupPath(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", name)
+}
+
+// loadBackup reads the JSON backup at path. A missing backup is not an
+// error; ok reports whether there was one.
+func loadBackup[T any](path string) (v T, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return v, false, nil
+	} else if err != nil {
+		return v, false, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return v, true, nil
+}
+
+// loadBackupMap is loadBackup for a backup keyed by name, returning an
+// empty map when there is none so entries can be added to it.
+func loadBackupMap[V any](path string) (map[string]V, error) {
+	backup, _, err := loadBackup[map[string]V](path)
+	if err != nil {
+		return nil, err
+	}
+	if backup == nil {
+		backup = make(map[string]V)
+	}
+	return backup, nil
+}
+
+// saveBackup writes v to path as JSON. A map or slice with nothing left in
+// it removes the file instead, as everything in it has been restored.
+func saveBackup[T any](path string, v T) error {
+	if rv := reflect.ValueOf(v); (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.Len() == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
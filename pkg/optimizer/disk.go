@@ -0,0 +1,176 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Media types of the disk under a volume, as MSFT_PhysicalDisk reports
+// them.
+const (
+	MediaSSD         = "SSD"
+	MediaHDD         = "HDD"
+	MediaSCM         = "SCM"
+	MediaUnspecified = "Unspecified"
+)
+
+// What OptimizeDisk does for a volume.
+const (
+	// DiskRetrim sends TRIM for the volume's free space (defrag /L).
+	DiskRetrim = "retrim"
+	// DiskDefrag includes the volume in the weekly defragmentation task.
+	DiskDefrag = "defrag"
+	// DiskNone leaves the volume alone.
+	DiskNone = "none"
+)
+
+// defragTaskName is the scheduled task that defragments hard disks.
+const defragTaskName = "SysCleanerDefrag"
+
+// diskVolume is a volume with a drive letter and the media type of its
+// disk.
+type diskVolume struct {
+	Drive     string `json:"drive"`
+	MediaType string `json:"media"`
+}
+
+// VolumeAction is what OptimizeDisk did for a volume, or in a dry run
+// would do.
+type VolumeAction struct {
+	Drive string
+	// MediaType is MediaSSD, MediaHDD, MediaSCM or MediaUnspecified.
+	MediaType string
+	// Action is DiskRetrim, DiskDefrag or DiskNone.
+	Action string
+	// Done reports whether the action succeeded; always false in a dry
+	// run.
+	Done  bool
+	Error string
+}
+
+// These are variables so tests can fake the disks and the commands run on
+// them.
+var (
+	listDiskVolumes = listDiskVolumesNative
+	runDiskCommand  = runDiskCommandNative
+)
+
+// diskVolumesScript lists the lettered volumes with the MSFT_PhysicalDisk
+// media type of the disk each is on, as JSON.
+const diskVolumesScript = `$media = @{}; Get-PhysicalDisk | ForEach-Object { $media[[string]$_.DeviceId] = [string]$_.MediaType }; ` +
+	`ConvertTo-Json -Compress -InputObject @(Get-Partition | Where-Object { [string]$_.DriveLetter -match '^[A-Za-z]$' } | ` +
+	`ForEach-Object { [pscustomobject]@{ drive = ([string]$_.DriveLetter).ToUpper() + ':'; media = $media[[string]$_.DiskNumber] } })`
+
+// parseDiskVolumes parses the output of diskVolumesScript.
+func parseDiskVolumes(data []byte) ([]diskVolume, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var volumes []diskVolume
+	if err := json.Unmarshal(data, &volumes); err != nil {
+		return nil, fmt.Errorf("parsing volumes: %w", err)
+	}
+	for i := range volumes {
+		if volumes[i].MediaType == "" {
+			volumes[i].MediaType = MediaUnspecified
+		}
+	}
+	return volumes, nil
+}
+
+// planDiskActions picks each volume's action from its media type: retrim
+// on SSDs, defragmentation on hard disks. A volume whose media type is
+// unspecified, as in most virtual machines, is checked for a seek penalty
+// and otherwise left alone, so an SSD is never defragmented.
+func planDiskActions(volumes []diskVolume) []VolumeAction {
+	actions := make([]VolumeAction, 0, len(volumes))
+	for _, v := range volumes {
+		media := v.MediaType
+		if media == MediaUnspecified {
+			if ssd, err := driveIsSSD(v.Drive); err == nil && ssd {
+				media = MediaSSD
+			}
+		}
+		a := VolumeAction{Drive: v.Drive, MediaType: media, Action: DiskNone}
+		switch media {
+		case MediaSSD:
+			a.Action = DiskRetrim
+		case MediaHDD:
+			a.Action = DiskDefrag
+		}
+		actions = append(actions, a)
+	}
+	return actions
+}
+
+// applyDiskActions runs the planned actions: TRIM is enabled and each SSD
+// volume retrimmed now, and the weekly defragmentation task is set to
+// cover exactly the hard disk volumes, or removed when there are none.
+func applyDiskActions(actions []VolumeAction) {
+	var trimErr error
+	trimTried := false
+	var hdds []string
+	for i := range actions {
+		a := &actions[i]
+		switch a.Action {
+		case DiskRetrim:
+			if !trimTried {
+				trimErr = runDiskCommand("fsutil", "behavior", "set", "DisableDeleteNotify", "0")
+				trimTried = true
+			}
+			err := trimErr
+			if err == nil {
+				err = runDiskCommand("defrag", a.Drive, "/L")
+			}
+			if err != nil {
+				a.Error = err.Error()
+			} else {
+				a.Done = true
+			}
+		case DiskDefrag:
+			hdds = append(hdds, a.Drive)
+		}
+	}
+
+	if len(hdds) == 0 {
+		// A task from an earlier run must not keep defragmenting a drive
+		// that is now an SSD; it may not exist, so errors are ignored.
+		runDiskCommand("schtasks", "/delete", "/tn", defragTaskName, "/f")
+		return
+	}
+	err := runDiskCommand("schtasks", "/create", "/tn", defragTaskName,
+		"/sc", "weekly", "/d", "SUN", "/st", "03:00",
+		"/tr", "defrag "+strings.Join(hdds, " ")+" /D", "/f")
+	for i := range actions {
+		if actions[i].Action != DiskDefrag {
+			continue
+		}
+		if err != nil {
+			actions[i].Error = err.Error()
+		} else {
+			actions[i].Done = true
+		}
+	}
+}
+
+// describeVolumeAction describes a volume's action for PrintDiskResult.
+func describeVolumeAction(a VolumeAction, dryRun bool) string {
+	switch {
+	case a.Action == DiskRetrim && dryRun:
+		return "would retrim (defrag " + a.Drive + " /L)"
+	case a.Action == DiskRetrim && a.Done:
+		return "retrimmed"
+	case a.Action == DiskDefrag && dryRun:
+		return "would defragment weekly (task " + defragTaskName + ", Sundays at 3:00 AM)"
+	case a.Action == DiskDefrag && a.Done:
+		return "weekly defragmentation scheduled (Sundays at 3:00 AM)"
+	case a.Action == DiskNone && a.MediaType == MediaSCM:
+		return "left alone (storage-class memory)"
+	case a.Action == DiskNone:
+		return "left alone (media type unknown)"
+	}
+	return "failed: " + a.Error
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func listDiskVolumesNative() ([]diskVolume, error) {
+	return nil, fmt.Errorf("disk optimization is only available on Windows")
+}
+
+func runDiskCommandNative(name string, args ...string) error {
+	return fmt.Errorf("disk optimization is only available on Windows")
+}
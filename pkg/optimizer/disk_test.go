@@ -0,0 +1,75 @@
+package optimizer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanDiskActions(t *testing.T) {
+	orig := driveIsSSD
+	t.Cleanup(func() { driveIsSSD = orig })
+	driveIsSSD = func(drive string) (bool, error) {
+		if drive == "F:" {
+			return false, errors.New("no seek penalty data")
+		}
+		return drive == "E:", nil
+	}
+
+	volumes, err := parseDiskVolumes([]byte(`[{"drive":"C:","media":"SSD"},{"drive":"D:","media":"HDD"},` +
+		`{"drive":"E:","media":"Unspecified"},{"drive":"F:","media":null},{"drive":"G:","media":"SCM"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range planDiskActions(volumes) {
+		got = append(got, a.Drive+a.MediaType+":"+a.Action)
+	}
+	// An unspecified disk with no seek penalty counts as an SSD; one that
+	// cannot be checked is never defragmented.
+	want := []string{"C:SSD:retrim", "D:HDD:defrag", "E:SSD:retrim", "F:Unspecified:none", "G:SCM:none"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planDiskActions = %v, want %v", got, want)
+	}
+}
+
+func TestApplyDiskActions(t *testing.T) {
+	orig := runDiskCommand
+	t.Cleanup(func() { runDiskCommand = orig })
+	var ran []string
+	runDiskCommand = func(name string, args ...string) error {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	actions := []VolumeAction{
+		{Drive: "C:", MediaType: MediaSSD, Action: DiskRetrim},
+		{Drive: "D:", MediaType: MediaHDD, Action: DiskDefrag},
+		{Drive: "E:", MediaType: MediaSSD, Action: DiskRetrim},
+		{Drive: "F:", MediaType: MediaHDD, Action: DiskDefrag},
+		{Drive: "G:", MediaType: MediaUnspecified, Action: DiskNone},
+	}
+	applyDiskActions(actions)
+	want := []string{
+		"fsutil behavior set DisableDeleteNotify 0",
+		"defrag C: /L",
+		"defrag E: /L",
+		"schtasks /create /tn SysCleanerDefrag /sc weekly /d SUN /st 03:00 /tr defrag D: F: /D /f",
+	}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %q, want %q", ran, want)
+	}
+	for _, a := range actions {
+		if a.Done != (a.Action != DiskNone) {
+			t.Errorf("%s: Done = %v", a.Drive, a.Done)
+		}
+	}
+
+	// Without hard disks, an old defrag task is removed.
+	ran = nil
+	applyDiskActions([]VolumeAction{{Drive: "C:", MediaType: MediaSSD, Action: DiskRetrim}})
+	if last := ran[len(ran)-1]; last != "schtasks /delete /tn SysCleanerDefrag /f" {
+		t.Errorf("last command = %q, want the defrag task deleted", last)
+	}
+}
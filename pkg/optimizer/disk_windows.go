@@ -0,0 +1,30 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// listDiskVolumesNative reads the volumes' media types from
+// MSFT_PhysicalDisk.
+func listDiskVolumesNative() ([]diskVolume, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", diskVolumesScript)
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing volumes: %w", err)
+	}
+	return parseDiskVolumes(out)
+}
+
+func runDiskCommandNative(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = getSysProcAttr()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
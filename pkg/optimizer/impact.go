@@ -0,0 +1,171 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Startup impact ratings, from a program's measured boot cost.
+const (
+	ImpactHigh        = "High"
+	ImpactMedium      = "Medium"
+	ImpactLow         = "Low"
+	ImpactNotMeasured = "Not measured"
+)
+
+// Boot costs at or above which a program rates High or Medium.
+const (
+	highImpactCost   = time.Second
+	mediumImpactCost = 300 * time.Millisecond
+)
+
+// bootDelay is one Diagnostics-Performance event 101: a program Windows
+// measured slowing down a boot.
+type bootDelay struct {
+	// Exe is the program's file name, in lower case.
+	Exe  string
+	Time time.Time
+	// Total is how long the program took to start during the boot.
+	Total time.Duration
+}
+
+// bootDelays returns the boot delay events Windows recorded. It is a
+// variable so tests can fake the event log.
+var bootDelays = bootDelaysNative
+
+// perfEvent is the part of an event read from the event log's XML.
+type perfEvent struct {
+	System struct {
+		EventID     int `xml:"EventID"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+	} `xml:"System"`
+	Data []struct {
+		Name  string `xml:"Name,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"EventData>Data"`
+}
+
+// parseBootDelays parses events from the Diagnostics-Performance log as
+// wevtutil prints them, keeping the boot delay ones.
+func parseBootDelays(data []byte) ([]bootDelay, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var out []bootDelay
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("parsing boot performance events: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Event" {
+			continue
+		}
+		var ev perfEvent
+		if err := dec.DecodeElement(&ev, &start); err != nil {
+			return nil, fmt.Errorf("parsing boot performance events: %w", err)
+		}
+		if ev.System.EventID != 101 {
+			continue
+		}
+		d := bootDelay{}
+		d.Time, _ = time.Parse(time.RFC3339Nano, ev.System.TimeCreated.SystemTime)
+		for _, f := range ev.Data {
+			switch f.Name {
+			case "Name":
+				d.Exe = strings.ToLower(strings.TrimSpace(f.Value))
+			case "TotalTime":
+				if ms, err := strconv.Atoi(strings.TrimSpace(f.Value)); err == nil {
+					d.Total = time.Duration(ms) * time.Millisecond
+				}
+			}
+		}
+		if d.Exe != "" && d.Total > 0 {
+			out = append(out, d)
+		}
+	}
+}
+
+// commandExe returns the file name, in lower case, of the program a
+// startup command runs.
+func commandExe(command string) string {
+	command = strings.TrimSpace(command)
+	if strings.HasPrefix(command, `"`) {
+		if end := strings.Index(command[1:], `"`); end >= 0 {
+			command = command[1 : end+1]
+		}
+	} else if i := strings.Index(strings.ToLower(command), ".exe"); i >= 0 {
+		command = command[:i+len(".exe")]
+	} else if fields := strings.Fields(command); len(fields) > 0 {
+		command = fields[0]
+	}
+	if i := strings.LastIndexAny(command, `\/`); i >= 0 {
+		command = command[i+1:]
+	}
+	return strings.ToLower(command)
+}
+
+// impactRating rates a measured boot cost the way Task Manager's Startup
+// tab does.
+func impactRating(cost time.Duration) string {
+	switch {
+	case cost >= highImpactCost:
+		return ImpactHigh
+	case cost >= mediumImpactCost:
+		return ImpactMedium
+	default:
+		return ImpactLow
+	}
+}
+
+// rateStartupImpact sets each program's StartupCost to its average boot
+// delay and its Impact from that. A program is matched to events by the
+// file its command runs or, for Startup folder shortcuts, its name. Only
+// events from before a program was disabled (per its StartupApproved
+// data) count for it; one with no events is "Not measured".
+func rateStartupImpact(programs []StartupProgram, delays []bootDelay) {
+	for i := range programs {
+		p := &programs[i]
+		exes := map[string]bool{commandExe(p.Path): true}
+		exes[strings.ToLower(strings.TrimSuffix(p.Name, filepath.Ext(p.Name)))+".exe"] = true
+
+		var total time.Duration
+		var n int
+		for _, d := range delays {
+			if !exes[d.Exe] || !p.DisabledAt.IsZero() && d.Time.After(p.DisabledAt) {
+				continue
+			}
+			total += d.Total
+			n++
+		}
+		if n == 0 {
+			p.StartupCost, p.Impact = 0, ImpactNotMeasured
+			continue
+		}
+		p.StartupCost = total / time.Duration(n)
+		p.Impact = impactRating(p.StartupCost)
+	}
+}
+
+// RecommendStartup returns the enabled programs worth disabling, costliest
+// first: those measured slowing down boots and those in
+// AutoDisableStartup.
+func RecommendStartup(programs []StartupProgram) []StartupProgram {
+	var out []StartupProgram
+	for _, p := range programs {
+		if !p.Disabled && (p.StartupCost >= mediumImpactCost || autoDisabled(p.Name)) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].StartupCost > out[b].StartupCost })
+	return out
+}
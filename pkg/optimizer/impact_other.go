@@ -0,0 +1,7 @@
+//go:build !windows
+
+package optimizer
+
+func bootDelaysNative() ([]bootDelay, error) {
+	return nil, nil
+}
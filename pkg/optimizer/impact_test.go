@@ -0,0 +1,83 @@
+package optimizer
+
+import (
+	"testing"
+	"time"
+)
+
+const bootEvents = `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><EventID>101</EventID>` +
+	`<TimeCreated SystemTime='2024-03-01T08:00:00.0000000Z'/></System><EventData><Data Name='Name'>OneDrive.exe</Data>` +
+	`<Data Name='FriendlyName'>Microsoft OneDrive</Data><Data Name='TotalTime'>3000</Data><Data Name='DegradationTime'>2000</Data></EventData></Event>
+<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><EventID>101</EventID>` +
+	`<TimeCreated SystemTime='2024-03-05T08:00:00.0000000Z'/></System><EventData><Data Name='Name'>OneDrive.exe</Data>` +
+	`<Data Name='TotalTime'>1000</Data></EventData></Event>
+<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><EventID>100</EventID>` +
+	`<TimeCreated SystemTime='2024-03-05T08:00:00.0000000Z'/></System><EventData><Data Name='BootTime'>40000</Data></EventData></Event>
+<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><EventID>101</EventID>` +
+	`<TimeCreated SystemTime='2024-03-05T08:00:00.0000000Z'/></System><EventData><Data Name='Name'>updater.exe</Data>` +
+	`<Data Name='TotalTime'>400</Data></EventData></Event>`
+
+func TestParseBootDelays(t *testing.T) {
+	delays, err := parseBootDelays([]byte(bootEvents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 3 {
+		t.Fatalf("parseBootDelays = %+v, want the three event 101s", delays)
+	}
+	if delays[0].Exe != "onedrive.exe" || delays[0].Total != 3*time.Second ||
+		!delays[0].Time.Equal(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("first delay = %+v", delays[0])
+	}
+}
+
+func TestCommandExe(t *testing.T) {
+	for cmd, want := range map[string]string{
+		`"C:\Program Files\Microsoft OneDrive\OneDrive.exe" /background`: "onedrive.exe",
+		`C:\Program Files\Vendor\Updater.EXE -silent`:                    "updater.exe",
+		`rundll32.exe shell32.dll,Control_RunDLL`:                        "rundll32.exe",
+		`C:\Tools\start.cmd`: "start.cmd",
+	} {
+		if got := commandExe(cmd); got != want {
+			t.Errorf("commandExe(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
+
+func TestRateStartupImpact(t *testing.T) {
+	delays, err := parseBootDelays([]byte(bootEvents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	programs := []StartupProgram{
+		{Name: "OneDrive", Path: `"C:\OneDrive\OneDrive.exe" /background`},
+		{Name: "Updater.lnk", Path: `C:\Startup\Updater.lnk`, Source: StartupFolder},
+		// Disabled before the second OneDrive event, so only the first counts.
+		{Name: "OneDrive", Path: `C:\Other\OneDrive.exe`, Disabled: true,
+			DisabledAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{Name: "Quiet", Path: `C:\Quiet\quiet.exe`},
+		{Name: "Spotify", Path: `C:\Spotify\Spotify.exe`},
+	}
+	rateStartupImpact(programs, delays)
+
+	want := []struct {
+		cost   time.Duration
+		impact string
+	}{
+		{2 * time.Second, ImpactHigh},
+		{400 * time.Millisecond, ImpactMedium},
+		{3 * time.Second, ImpactHigh},
+		{0, ImpactNotMeasured},
+		{0, ImpactNotMeasured},
+	}
+	for i, w := range want {
+		if programs[i].StartupCost != w.cost || programs[i].Impact != w.impact {
+			t.Errorf("%s = %v %s, want %v %s", programs[i].Name, programs[i].StartupCost, programs[i].Impact, w.cost, w.impact)
+		}
+	}
+
+	rec := RecommendStartup(programs)
+	if len(rec) != 3 || rec[0].Name != "OneDrive" || rec[1].Name != "Updater.lnk" || rec[2].Name != "Spotify" {
+		t.Errorf("RecommendStartup = %+v, want OneDrive, Updater.lnk, then the unmeasured Spotify", rec)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// bootDelaysNative reads the newest boot delay events from the
+// Diagnostics-Performance log, which needs administrator rights.
+func bootDelaysNative() ([]bootDelay, error) {
+	cmd := exec.Command("wevtutil", "qe", "Microsoft-Windows-Diagnostics-Performance/Operational",
+		"/q:*[System[(EventID=101)]]", "/f:xml", "/rd:true", "/c:500")
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("wevtutil: %w", err)
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return nil, nil
+	}
+	return parseBootDelays(out)
+}
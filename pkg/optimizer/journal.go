@@ -0,0 +1,233 @@
+package optimizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/reglog"
+)
+
+// ErrNoJournal is returned by LastJournal when no optimizer run is left
+// to undo.
+var ErrNoJournal = errors.New("no optimizer changes to undo")
+
+// Journal records every registry value one optimizer run set or deleted,
+// with its data beforehand, and every global TCP setting it changed with
+// netsh, so Undo can put them back. It is kept whether or not registry
+// logging (see pkg/reglog) is on.
+type Journal struct {
+	// ID is the run's operation ID (see logger.StartOperation).
+	ID         string          `json:"id"`
+	Started    time.Time       `json:"started"`
+	Changes    []reglog.Change `json:"changes"`
+	TCPChanges []TCPChange     `json:"tcp_changes,omitempty"`
+	// Undone is when Undo put the changes back, zero until then.
+	Undone time.Time `json:"undone,omitempty"`
+	// Backup names the run's registry backup (see RegistryBackups), if it
+	// wrote to the registry.
+	Backup string `json:"backup,omitempty"`
+
+	// dryRun makes the journal collect the changes a preview run would
+	// make, without making or saving them.
+	dryRun bool
+	backup *regBackup
+}
+
+// journalDir is where journals are kept, one file per run.
+var journalDir = func() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "SysCleaner", "optimizer-journal")
+}
+
+// restoreChange puts back the value a change replaced or deleted. It is a
+// variable so tests can fake the registry.
+var restoreChange = restoreChangeNative
+
+// TCPChange is a global TCP setting a run changed with "netsh int tcp set
+// global", which is not a registry value.
+type TCPChange struct {
+	Time time.Time `json:"time"`
+	// Setting is the netsh parameter, e.g. "autotuninglevel".
+	Setting string `json:"setting"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+func (c TCPChange) String() string {
+	return fmt.Sprintf("netsh int tcp global %s: %s -> %s", c.Setting, c.Old, c.New)
+}
+
+func newJournal(id string) *Journal {
+	return &Journal{ID: id, Started: time.Now()}
+}
+
+// add records a change and saves the journal, so a run that dies halfway
+// can still be undone.
+func (j *Journal) add(c reglog.Change) {
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+	j.Changes = append(j.Changes, c)
+	if j.dryRun {
+		return
+	}
+	if err := j.save(); err != nil {
+		log.Printf("[SysCleaner] Failed to save the optimizer journal: %v", err)
+	}
+}
+
+// addTCP records a TCP setting change and saves the journal.
+func (j *Journal) addTCP(c TCPChange) {
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+	j.TCPChanges = append(j.TCPChanges, c)
+	if j.dryRun {
+		return
+	}
+	if err := j.save(); err != nil {
+		log.Printf("[SysCleaner] Failed to save the optimizer journal: %v", err)
+	}
+}
+
+// setTCPGlobal sets a global TCP setting, whose value is now old, with
+// netsh, journaling old. In a dry run it only journals. It reports whether
+// the setting changed; one that already has val is left out.
+func (j *Journal) setTCPGlobal(setting, old, val string) (bool, error) {
+	if old == val {
+		return false, nil
+	}
+	if !j.dryRun {
+		if err := setTCPSetting(setting, val); err != nil {
+			return false, err
+		}
+	}
+	j.addTCP(TCPChange{Setting: setting, Old: old, New: val})
+	return true, nil
+}
+
+// Count returns how many registry values and TCP settings the run changed.
+func (j Journal) Count() int {
+	return len(j.Changes) + len(j.TCPChanges)
+}
+
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := journalDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, j.ID+".json"), data, 0644)
+}
+
+// Journals returns the recorded optimizer runs, newest first.
+func Journals() ([]Journal, error) {
+	entries, err := os.ReadDir(journalDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var out []Journal
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		j, err := loadJournal(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			log.Printf("[SysCleaner] Skipping optimizer journal %s: %v", e.Name(), err)
+			continue
+		}
+		out = append(out, j)
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Started.After(out[b].Started) })
+	return out, nil
+}
+
+func loadJournal(id string) (Journal, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return Journal{}, fmt.Errorf("invalid journal ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(journalDir(), id+".json"))
+	if os.IsNotExist(err) {
+		return Journal{}, fmt.Errorf("no optimizer journal %q", id)
+	} else if err != nil {
+		return Journal{}, err
+	}
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return Journal{}, fmt.Errorf("parsing optimizer journal %q: %w", id, err)
+	}
+	return j, nil
+}
+
+// LastJournal returns the newest optimizer run that changed something and
+// has not been undone, or ErrNoJournal.
+func LastJournal() (Journal, error) {
+	journals, err := Journals()
+	if err != nil {
+		return Journal{}, err
+	}
+	for _, j := range journals {
+		if j.Undone.IsZero() && j.Count() > 0 {
+			return j, nil
+		}
+	}
+	return Journal{}, ErrNoJournal
+}
+
+// Undo puts back every registry value and TCP setting the run journalID
+// changed, newest change first so a value changed twice gets its original
+// data. It returns how many were restored; those that could not be are
+// reported in the error, and the journal stays open so Undo can be
+// retried.
+func Undo(journalID string) (int, error) {
+	if err := admin.RequireWriteAccess("undoing optimizer run " + journalID); err != nil {
+		return 0, err
+	}
+	j, err := loadJournal(journalID)
+	if err != nil {
+		return 0, err
+	}
+	if !j.Undone.IsZero() {
+		return 0, fmt.Errorf("optimizer run %s was already undone on %s", j.ID, j.Undone.Format("2006-01-02 15:04"))
+	}
+
+	restored := 0
+	var errs []error
+	for i := len(j.Changes) - 1; i >= 0; i-- {
+		c := j.Changes[i]
+		if err := restoreChange(c); err != nil {
+			errs = append(errs, fmt.Errorf(`%s\%s: %w`, c.Key(), c.Name, err))
+			continue
+		}
+		restored++
+	}
+	for i := len(j.TCPChanges) - 1; i >= 0; i-- {
+		c := j.TCPChanges[i]
+		if err := setTCPSetting(c.Setting, c.Old); err != nil {
+			errs = append(errs, fmt.Errorf("netsh int tcp global %s: %w", c.Setting, err))
+			continue
+		}
+		restored++
+	}
+	if len(errs) > 0 {
+		return restored, errors.Join(errs...)
+	}
+	j.Undone = time.Now()
+	return restored, j.save()
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package optimizer
+
+import (
+	"fmt"
+
+	"syscleaner/pkg/reglog"
+)
+
+func restoreChangeNative(c reglog.Change) error {
+	return fmt.Errorf("undoing registry changes is only available on Windows")
+}
+
+func exportRegKeyNative(root, path string) (string, bool, error) {
+	return "", false, fmt.Errorf("registry backups are only available on Windows")
+}
+
+func importRegFileNative(path string) error {
+	return fmt.Errorf("registry backups are only available on Windows")
+}
@@ -0,0 +1,95 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"syscleaner/pkg/reglog"
+)
+
+func TestJournal_UndoLast(t *testing.T) {
+	dir := t.TempDir()
+	origDir, origRestore := journalDir, restoreChange
+	journalDir = func() string { return dir }
+	t.Cleanup(func() { journalDir, restoreChange = origDir, origRestore })
+
+	if _, err := LastJournal(); !errors.Is(err, ErrNoJournal) {
+		t.Fatalf("LastJournal with no runs = %v, want ErrNoJournal", err)
+	}
+
+	older := newJournal("optimize-network-1")
+	older.Started = time.Now().Add(-time.Hour)
+	older.add(reglog.Change{Root: "HKEY_LOCAL_MACHINE", Path: `SOFTWARE\Net`, Name: "Throttle",
+		New: &reglog.Value{Kind: reglog.KindDWord, Integer: 0xffffffff}})
+
+	newer := newJournal("optimize-startup-2")
+	run := &reglog.Value{Kind: reglog.KindString, String: `C:\OneDrive.exe /background`}
+	newer.add(reglog.Change{Root: "HKEY_CURRENT_USER", Path: `Run`, Name: "OneDrive", Old: run})
+	newer.add(reglog.Change{Root: "HKEY_CURRENT_USER", Path: `Run`, Name: "Spotify",
+		Old: &reglog.Value{Kind: reglog.KindString, String: "spotify.exe"}})
+	newJournal("optimize-startup-3").save() // changed nothing
+
+	j, err := LastJournal()
+	if err != nil || j.ID != "optimize-startup-2" {
+		t.Fatalf("LastJournal = %s, %v; want optimize-startup-2", j.ID, err)
+	}
+
+	var restored []string
+	fail := true
+	restoreChange = func(c reglog.Change) error {
+		if c.Name == "Spotify" && fail {
+			return errors.New("access denied")
+		}
+		restored = append(restored, c.Name)
+		return nil
+	}
+	if n, err := Undo(j.ID); err == nil || n != 1 {
+		t.Fatalf("Undo with a failure = %d, %v; want 1 and an error", n, err)
+	}
+	if j, _ := LastJournal(); j.ID != "optimize-startup-2" {
+		t.Fatal("a partly undone run should stay open")
+	}
+
+	fail, restored = false, nil
+	if n, err := Undo(j.ID); err != nil || n != 2 {
+		t.Fatalf("Undo = %d, %v", n, err)
+	}
+	if len(restored) != 2 || restored[0] != "Spotify" || restored[1] != "OneDrive" {
+		t.Errorf("restored %v, want newest change first", restored)
+	}
+	if _, err := Undo(j.ID); err == nil {
+		t.Error("undoing a run twice should fail")
+	}
+	if j, err := LastJournal(); err != nil || j.ID != "optimize-network-1" {
+		t.Errorf("LastJournal after undo = %s, %v; want optimize-network-1", j.ID, err)
+	}
+	if _, err := Undo(`..\config`); err == nil {
+		t.Error("a journal ID with a path should be rejected")
+	}
+}
+
+func TestJournal_DryRunIsNotSaved(t *testing.T) {
+	dir := t.TempDir()
+	origDir := journalDir
+	journalDir = func() string { return dir }
+	t.Cleanup(func() { journalDir = origDir })
+
+	j := newJournal("optimize-startup-preview-1")
+	j.dryRun = true
+	j.add(reglog.Change{Root: "HKEY_CURRENT_USER", Path: `Run`, Name: "OneDrive",
+		Old: &reglog.Value{Kind: reglog.KindString, String: "OneDrive.exe"}})
+	if len(j.Changes) != 1 {
+		t.Fatalf("dry-run journal has %d change(s), want 1", len(j.Changes))
+	}
+	if journals, err := Journals(); err != nil || len(journals) != 0 {
+		t.Fatalf("Journals after a dry run = %v, %v; want none", journals, err)
+	}
+
+	if r := OptimizeNetwork(OptimizeOptions{DryRun: true}); !r.DryRun {
+		t.Error("OptimizeNetwork with DryRun should report a dry run")
+	}
+	if r := OptimizeDisk(OptimizeOptions{DryRun: true}); !r.DryRun {
+		t.Error("OptimizeDisk with DryRun should report a dry run")
+	}
+}
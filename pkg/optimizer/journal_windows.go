@@ -0,0 +1,144 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+// deleteValue deletes a registry value, journaling its data. In a dry run
+// it only journals.
+func (j *Journal) deleteValue(root registry.Key, path, name string) error {
+	old := reglog.Lookup(root, path, name)
+	if j.dryRun {
+		if old == nil {
+			return registry.ErrNotExist
+		}
+	} else if err := j.backupKey(reglog.RootName(root), path); err != nil {
+		return err
+	} else if err := reglog.DeleteValue(root, path, name); err != nil {
+		return err
+	}
+	j.add(reglog.Change{Time: time.Now(), Root: reglog.RootName(root), Path: path, Name: name, Old: old})
+	return nil
+}
+
+// setDWordValue writes a DWORD, journaling the value it replaces. In a
+// dry run it only journals, and leaves out a value that already has val.
+func (j *Journal) setDWordValue(root registry.Key, path, name string, val uint32) error {
+	old := reglog.Lookup(root, path, name)
+	if j.dryRun {
+		if old != nil && old.Kind == reglog.KindDWord && old.Integer == uint64(val) {
+			return nil
+		}
+	} else if err := j.backupKey(reglog.RootName(root), path); err != nil {
+		return err
+	} else if err := reglog.SetDWordValue(root, path, name, val); err != nil {
+		return err
+	}
+	j.add(reglog.Change{Time: time.Now(), Root: reglog.RootName(root), Path: path, Name: name,
+		Old: old, New: &reglog.Value{Kind: reglog.KindDWord, Integer: uint64(val)}})
+	return nil
+}
+
+// setBinaryValue writes a REG_BINARY value, journaling the value it
+// replaces. In a dry run it only journals.
+func (j *Journal) setBinaryValue(root registry.Key, path, name string, val []byte) error {
+	old := reglog.Lookup(root, path, name)
+	if !j.dryRun {
+		if err := j.backupKey(reglog.RootName(root), path); err != nil {
+			return err
+		}
+		if err := reglog.SetBinaryValue(root, path, name, val); err != nil {
+			return err
+		}
+	}
+	j.add(reglog.Change{Time: time.Now(), Root: reglog.RootName(root), Path: path, Name: name,
+		Old: old, New: &reglog.Value{Kind: reglog.KindBinary, Binary: val}})
+	return nil
+}
+
+// setValue writes a value of any kind, journaling the value it replaces.
+// In a dry run it only journals, and leaves out a value that already has
+// that data.
+func (j *Journal) setValue(root registry.Key, path, name string, val reglog.Value) error {
+	old := reglog.Lookup(root, path, name)
+	if j.dryRun {
+		if old != nil && old.Equal(val) {
+			return nil
+		}
+	} else if err := j.backupKey(reglog.RootName(root), path); err != nil {
+		return err
+	} else if err := reglog.SetValue(root, path, name, val); err != nil {
+		return err
+	}
+	j.add(reglog.Change{Time: time.Now(), Root: reglog.RootName(root), Path: path, Name: name, Old: old, New: &val})
+	return nil
+}
+
+// exportRegKeyNative exports the key at root\path, subkeys included, with
+// "reg export", returning the .reg text without its header and whether the
+// key exists.
+func exportRegKeyNative(root, path string) (string, bool, error) {
+	rootKey, err := reglog.RootKey(root)
+	if err != nil {
+		return "", false, err
+	}
+	k, err := registry.OpenKey(rootKey, path, registry.QUERY_VALUE)
+	if err == registry.ErrNotExist {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	k.Close()
+
+	f, err := os.CreateTemp("", "syscleaner-*.reg")
+	if err != nil {
+		return "", true, err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	cmd := exec.Command("reg", "export", root+`\`+path, f.Name(), "/y")
+	cmd.SysProcAttr = getSysProcAttr()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", true, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", true, err
+	}
+	body, err := regFileBody(decodeRegFile(data))
+	return body, true, err
+}
+
+func importRegFileNative(path string) error {
+	cmd := exec.Command("reg", "import", path)
+	cmd.SysProcAttr = getSysProcAttr()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func restoreChangeNative(c reglog.Change) error {
+	root, err := reglog.RootKey(c.Root)
+	if err != nil {
+		return err
+	}
+	if c.Old == nil {
+		err := reglog.DeleteValue(root, c.Path, c.Name)
+		if err == registry.ErrNotExist {
+			return nil
+		}
+		return err
+	}
+	return reglog.SetValue(root, c.Path, c.Name, *c.Old)
+}
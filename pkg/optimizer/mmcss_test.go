@@ -0,0 +1,52 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+
+	"syscleaner/pkg/reglog"
+)
+
+func TestApplyMMCSSProfile(t *testing.T) {
+	dir := t.TempDir()
+	origDir, origSet := journalDir, setMMCSSValue
+	t.Cleanup(func() { journalDir, setMMCSSValue = origDir, origSet })
+	journalDir = func() string { return dir }
+
+	// GPU Priority already has the profile's value; Priority cannot be
+	// written.
+	setMMCSSValue = func(j *Journal, v mmcssValue) error {
+		switch {
+		case v.name == "Priority":
+			return errors.New("access denied")
+		case v.name == "GPU Priority" && j.dryRun:
+			return nil
+		}
+		j.add(reglog.Change{Root: "HKEY_LOCAL_MACHINE", Path: v.path, Name: v.name})
+		return nil
+	}
+
+	preview := newJournal("optimize-network-preview-1")
+	preview.dryRun = true
+	var result NetworkResult
+	applyMMCSSProfile(preview, &result)
+	if len(result.Optimizations) != 3 || len(result.Errors) != 1 {
+		t.Fatalf("dry run = %v, errors %v; want 3 values and 1 error", result.Optimizations, result.Errors)
+	}
+	for _, o := range result.Optimizations {
+		if o[:7] != "Would: " {
+			t.Errorf("dry-run optimization %q should start with Would:", o)
+		}
+	}
+
+	j := newJournal("optimize-network-2")
+	result = NetworkResult{}
+	applyMMCSSProfile(j, &result)
+	if len(j.Changes) != 4 || len(result.Optimizations) != 4 || result.LatencyReduction != 2 {
+		t.Errorf("journaled %d, optimizations %v, latency %d; want 4, 4 and 2ms",
+			len(j.Changes), result.Optimizations, result.LatencyReduction)
+	}
+	if last, err := LastJournal(); err != nil || last.ID != j.ID {
+		t.Errorf("LastJournal = %s, %v; the MMCSS values should be undoable", last.ID, err)
+	}
+}
@@ -0,0 +1,255 @@
+package optimizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/admin"
+)
+
+// DefaultMTUEndpoint is the host ProbePathMTU pings when none is given.
+const DefaultMTUEndpoint = "1.1.1.1"
+
+// IPv4 MTU bounds. Ethernet carries 1500 bytes; PPPoE takes 8 of them and
+// VPNs more.
+const (
+	MinMTU = 576
+	MaxMTU = 9000
+	// icmpOverhead is the IPv4 and ICMP headers around an echo payload.
+	icmpOverhead = 28
+	// ethernetMTU is the largest MTU ProbePathMTU tries.
+	ethernetMTU = 1500
+)
+
+// Outcomes of an echo request sent with Don't Fragment set.
+const (
+	echoOK = iota
+	// echoTooBig means a link on the way needed the packet fragmented.
+	echoTooBig
+	// echoLost means no reply came, which on a path that silently drops
+	// oversized packets (a "black hole") is all a probe gets.
+	echoLost
+)
+
+// echoTries is how often a size is sent before it counts as too big, so a
+// packet lost by chance does not lower the result.
+const echoTries = 2
+
+// echoTimeout is how long an echo request waits for its reply.
+const echoTimeout = time.Second
+
+// IPInterface is a connected network interface and its IPv4 MTU.
+type IPInterface struct {
+	Alias string `json:"alias"`
+	MTU   int    `json:"mtu"`
+}
+
+// PathMTU is the largest packet that reaches an endpoint unfragmented.
+type PathMTU struct {
+	Endpoint string
+	// Address is the IPv4 address the endpoint resolved to.
+	Address string
+	MTU     int
+}
+
+// These are variables so tests can fake ICMP and the interfaces.
+var (
+	sendEcho         = sendEchoNative
+	listIPInterfaces = listIPInterfacesNative
+	setInterfaceMTU  = setInterfaceMTUNative
+)
+
+// mtuBackupPath is where interface MTUs are kept before SysCleaner changes
+// them.
+var mtuBackupPath = func() string {
+	return backupPath("mtu-backup.json")
+}
+
+// ListIPInterfaces returns the connected interfaces and their MTUs.
+func ListIPInterfaces() ([]IPInterface, error) {
+	return listIPInterfaces()
+}
+
+// ProbePathMTU finds the path MTU to endpoint, a host name or IPv4
+// address, by sending pings with Don't Fragment set and halving the range
+// of sizes between one that arrived and one that did not. It needs no
+// administrator rights, but endpoint must answer ping.
+func ProbePathMTU(ctx context.Context, endpoint string) (PathMTU, error) {
+	if endpoint == "" {
+		endpoint = DefaultMTUEndpoint
+	}
+	addr, err := net.DefaultResolver.LookupIP(ctx, "ip4", endpoint)
+	if err != nil || len(addr) == 0 {
+		return PathMTU{}, fmt.Errorf("resolving %s: %w", endpoint, err)
+	}
+	result := PathMTU{Endpoint: endpoint, Address: addr[0].String()}
+	result.MTU, err = probePathMTU(ctx, addr[0])
+	return result, err
+}
+
+func probePathMTU(ctx context.Context, ip net.IP) (int, error) {
+	fits := func(mtu int) (bool, error) {
+		for i := 0; i < echoTries; i++ {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			status, err := sendEcho(ip, mtu-icmpOverhead, echoTimeout)
+			if err != nil {
+				return false, err
+			}
+			switch status {
+			case echoOK:
+				return true, nil
+			case echoTooBig:
+				return false, nil
+			}
+		}
+		return false, nil
+	}
+
+	if ok, err := fits(MinMTU); err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, fmt.Errorf("%s does not answer ping; try another endpoint", ip)
+	}
+	if ok, err := fits(ethernetMTU); err != nil {
+		return 0, err
+	} else if ok {
+		return ethernetMTU, nil
+	}
+	// lo always fits and hi never does.
+	lo, hi := MinMTU, ethernetMTU
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		ok, err := fits(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// SetMTU sets the IPv4 MTU of the interface named alias, first keeping
+// the one it had for RestoreMTU. An interface changed twice keeps its
+// first MTU.
+func SetMTU(alias string, mtu int) error {
+	if mtu < MinMTU || mtu > MaxMTU {
+		return fmt.Errorf("invalid MTU %d (valid: %d to %d)", mtu, MinMTU, MaxMTU)
+	}
+	if err := admin.RequireWriteAccess("setting the MTU of " + alias); err != nil {
+		return err
+	}
+	interfaces, err := listIPInterfaces()
+	if err != nil {
+		return err
+	}
+	var current *IPInterface
+	for i := range interfaces {
+		if strings.EqualFold(interfaces[i].Alias, alias) {
+			current = &interfaces[i]
+		}
+	}
+	if current == nil {
+		return fmt.Errorf("no connected interface named %q", alias)
+	}
+	if current.MTU == mtu {
+		return nil
+	}
+
+	backup, err := loadBackupMap[int](mtuBackupPath())
+	if err != nil {
+		return err
+	}
+	if _, ok := backup[current.Alias]; !ok {
+		backup[current.Alias] = current.MTU
+		if err := saveBackup(mtuBackupPath(), backup); err != nil {
+			return fmt.Errorf("saving the MTU of %s: %w", current.Alias, err)
+		}
+	}
+	if err := setInterfaceMTU(current.Alias, mtu); err != nil {
+		return fmt.Errorf("%s: %w", current.Alias, err)
+	}
+	log.Printf("[SysCleaner] Set the MTU of %s to %d (was %d)", current.Alias, mtu, current.MTU)
+	return nil
+}
+
+// RestoreMTU puts back the MTU of every interface SysCleaner changed. It
+// returns how many were restored; those that could not be are reported in
+// the error and kept for another try.
+func RestoreMTU() (int, error) {
+	if err := admin.RequireWriteAccess("restoring interface MTUs"); err != nil {
+		return 0, err
+	}
+	backup, err := loadBackupMap[int](mtuBackupPath())
+	if err != nil {
+		return 0, err
+	}
+	if len(backup) == 0 {
+		return 0, errors.New("SysCleaner has not changed any MTU")
+	}
+	aliases := make([]string, 0, len(backup))
+	for alias := range backup {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	restored := 0
+	var errs []error
+	for _, alias := range aliases {
+		if err := setInterfaceMTU(alias, backup[alias]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", alias, err))
+			continue
+		}
+		log.Printf("[SysCleaner] Restored the MTU of %s to %d", alias, backup[alias])
+		delete(backup, alias)
+		restored++
+	}
+	if err := saveBackup(mtuBackupPath(), backup); err != nil {
+		errs = append(errs, err)
+	}
+	return restored, errors.Join(errs...)
+}
+
+// OriginalMTUs returns the MTUs interfaces had before SysCleaner changed
+// them, by alias.
+func OriginalMTUs() (map[string]int, error) {
+	return loadBackupMap[int](mtuBackupPath())
+}
+
+// ipInterfacesScript prints the connected IPv4 interfaces, but loopback,
+// as IPInterface in JSON.
+const ipInterfacesScript = `ConvertTo-Json -Compress -InputObject @(Get-NetIPInterface -AddressFamily IPv4 -ConnectionState Connected | ` +
+	`Where-Object InterfaceAlias -notlike 'Loopback*' | ForEach-Object { [pscustomobject]@{ alias = $_.InterfaceAlias; mtu = [int]$_.NlMtu } })`
+
+// mtuScript returns a script that sets the IPv4 MTU of the interface
+// named alias. Set-NetIPInterface keeps it across restarts.
+func mtuScript(alias string, mtu int) string {
+	return fmt.Sprintf("$ErrorActionPreference = 'Stop'; Set-NetIPInterface -InterfaceAlias '%s' -AddressFamily IPv4 -NlMtuBytes %d",
+		strings.ReplaceAll(alias, "'", "''"), mtu)
+}
+
+// parseIPInterfaces parses the output of ipInterfacesScript.
+func parseIPInterfaces(data []byte) ([]IPInterface, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var interfaces []IPInterface
+	if err := json.Unmarshal(data, &interfaces); err != nil {
+		return nil, fmt.Errorf("parsing network interfaces: %w", err)
+	}
+	return interfaces, nil
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package optimizer
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+func sendEchoNative(ip net.IP, payload int, timeout time.Duration) (int, error) {
+	return 0, fmt.Errorf("MTU probing is only available on Windows")
+}
+
+func listIPInterfacesNative() ([]IPInterface, error) {
+	return nil, fmt.Errorf("MTU configuration is only available on Windows")
+}
+
+func setInterfaceMTUNative(alias string, mtu int) error {
+	return fmt.Errorf("MTU configuration is only available on Windows")
+}
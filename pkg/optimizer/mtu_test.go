@@ -0,0 +1,101 @@
+package optimizer
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbePathMTU(t *testing.T) {
+	orig := sendEcho
+	t.Cleanup(func() { sendEcho = orig })
+
+	for _, tc := range []struct {
+		name     string
+		pathMTU  int
+		blackout bool // oversized packets are dropped, not refused
+		want     int
+	}{
+		{"ethernet", 1500, false, 1500},
+		{"pppoe", 1492, false, 1492},
+		{"vpn black hole", 1420, true, 1420},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sendEcho = func(ip net.IP, payload int, timeout time.Duration) (int, error) {
+				switch {
+				case payload+icmpOverhead <= tc.pathMTU:
+					return echoOK, nil
+				case tc.blackout:
+					return echoLost, nil
+				}
+				return echoTooBig, nil
+			}
+			got, err := probePathMTU(context.Background(), net.IPv4(1, 1, 1, 1))
+			if err != nil || got != tc.want {
+				t.Errorf("probePathMTU = %d, %v; want %d", got, err, tc.want)
+			}
+		})
+	}
+
+	sendEcho = func(ip net.IP, payload int, timeout time.Duration) (int, error) { return echoLost, nil }
+	if _, err := probePathMTU(context.Background(), net.IPv4(1, 1, 1, 1)); err == nil {
+		t.Error("an endpoint that does not answer should be an error")
+	}
+}
+
+func TestSetMTU_Restore(t *testing.T) {
+	dir := t.TempDir()
+	origPath, origList, origSet := mtuBackupPath, listIPInterfaces, setInterfaceMTU
+	t.Cleanup(func() { mtuBackupPath, listIPInterfaces, setInterfaceMTU = origPath, origList, origSet })
+
+	mtus := map[string]int{"Ethernet": 1500, "PPPoE": 1480}
+	mtuBackupPath = func() string { return dir + "/mtu-backup.json" }
+	listIPInterfaces = func() ([]IPInterface, error) {
+		return []IPInterface{{"Ethernet", mtus["Ethernet"]}, {"PPPoE", mtus["PPPoE"]}}, nil
+	}
+	setInterfaceMTU = func(alias string, mtu int) error {
+		mtus[alias] = mtu
+		return nil
+	}
+
+	if err := SetMTU("pppoe", 1492); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMTU("PPPoE", 1452); err != nil {
+		t.Fatal(err)
+	}
+	if mtus["PPPoE"] != 1452 {
+		t.Fatalf("PPPoE MTU = %d, want 1452", mtus["PPPoE"])
+	}
+	if err := SetMTU("Ethernet", 100); err == nil {
+		t.Error("an MTU below 576 should be rejected")
+	}
+	if err := SetMTU("Wi-Fi", 1500); err == nil {
+		t.Error("an unknown interface should be an error")
+	}
+	if orig, err := OriginalMTUs(); err != nil || orig["PPPoE"] != 1480 || len(orig) != 1 {
+		t.Errorf("OriginalMTUs = %v, %v; want only PPPoE at 1480", orig, err)
+	}
+
+	if n, err := RestoreMTU(); err != nil || n != 1 {
+		t.Fatalf("RestoreMTU = %d, %v; want 1", n, err)
+	}
+	if mtus["PPPoE"] != 1480 {
+		t.Errorf("PPPoE MTU after restore = %d, want 1480", mtus["PPPoE"])
+	}
+	if _, err := RestoreMTU(); err == nil {
+		t.Error("restoring with nothing changed should be an error")
+	}
+}
+
+func TestMTUScripts(t *testing.T) {
+	if got := mtuScript("Bob's VPN", 1400); !strings.HasSuffix(got, "-InterfaceAlias 'Bob''s VPN' -AddressFamily IPv4 -NlMtuBytes 1400") {
+		t.Errorf("mtuScript = %q", got)
+	}
+	interfaces, err := parseIPInterfaces([]byte(`[{"alias":"Ethernet","mtu":1500}]`))
+	if err != nil || len(interfaces) != 1 || interfaces[0].MTU != 1500 {
+		t.Errorf("parseIPInterfaces = %+v, %v", interfaces, err)
+	}
+}
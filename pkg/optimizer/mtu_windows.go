@@ -0,0 +1,125 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	iphlpapi            = windows.NewLazySystemDLL("iphlpapi.dll")
+	procIcmpCreateFile  = iphlpapi.NewProc("IcmpCreateFile")
+	procIcmpCloseHandle = iphlpapi.NewProc("IcmpCloseHandle")
+	procIcmpSendEcho    = iphlpapi.NewProc("IcmpSendEcho")
+)
+
+// ICMP status codes from ipexport.h.
+const (
+	ipSuccess      = 0
+	ipPacketTooBig = 11009
+	ipReqTimedOut  = 11010
+)
+
+// ipFlagDF is IP_FLAG_DF, Don't Fragment.
+const ipFlagDF = 0x2
+
+// ipOptionInformation is IP_OPTION_INFORMATION.
+type ipOptionInformation struct {
+	TTL         uint8
+	TOS         uint8
+	Flags       uint8
+	OptionsSize uint8
+	OptionsData uintptr
+}
+
+// icmpEchoReply is ICMP_ECHO_REPLY.
+type icmpEchoReply struct {
+	Address       uint32
+	Status        uint32
+	RoundTripTime uint32
+	DataSize      uint16
+	Reserved      uint16
+	Data          uintptr
+	Options       ipOptionInformation
+}
+
+// sendEchoNative sends one ping of payload bytes to ip with Don't
+// Fragment set, through the ICMP API, which needs no administrator rights
+// and reports status codes rather than ping's translated output.
+func sendEchoNative(ip net.IP, payload int, timeout time.Duration) (int, error) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return 0, fmt.Errorf("%s is not an IPv4 address", ip)
+	}
+	h, _, err := procIcmpCreateFile.Call()
+	if windows.Handle(h) == windows.InvalidHandle {
+		return 0, fmt.Errorf("IcmpCreateFile: %w", err)
+	}
+	defer procIcmpCloseHandle.Call(h)
+
+	data := make([]byte, payload)
+	opts := ipOptionInformation{TTL: 128, Flags: ipFlagDF}
+	reply := make([]byte, int(unsafe.Sizeof(icmpEchoReply{}))+payload+8+64)
+	var dataPtr uintptr
+	if payload > 0 {
+		dataPtr = uintptr(unsafe.Pointer(&data[0]))
+	}
+	n, _, err := procIcmpSendEcho.Call(h,
+		uintptr(binary.LittleEndian.Uint32(ip4)),
+		dataPtr, uintptr(payload),
+		uintptr(unsafe.Pointer(&opts)),
+		uintptr(unsafe.Pointer(&reply[0])), uintptr(len(reply)),
+		uintptr(timeout.Milliseconds()))
+	if n == 0 {
+		var errno syscall.Errno
+		if errors.As(err, &errno) {
+			switch errno {
+			case ipPacketTooBig:
+				return echoTooBig, nil
+			case ipReqTimedOut:
+				return echoLost, nil
+			}
+		}
+		return 0, fmt.Errorf("IcmpSendEcho: %w", err)
+	}
+	switch (*icmpEchoReply)(unsafe.Pointer(&reply[0])).Status {
+	case ipSuccess:
+		return echoOK, nil
+	case ipPacketTooBig:
+		return echoTooBig, nil
+	}
+	return echoLost, nil
+}
+
+func runMTUScript(script string) ([]byte, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+func listIPInterfacesNative() ([]IPInterface, error) {
+	out, err := runMTUScript(ipInterfacesScript)
+	if err != nil {
+		return nil, fmt.Errorf("listing network interfaces: %w", err)
+	}
+	return parseIPInterfaces(out)
+}
+
+func setInterfaceMTUNative(alias string, mtu int) error {
+	_, err := runMTUScript(mtuScript(alias, mtu))
+	return err
+}
@@ -5,8 +5,38 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/reglog"
+	"syscleaner/pkg/sysinfo"
+	"syscleaner/pkg/sysrestore"
 )
 
+// OptimizeOptions configures an optimization run.
+type OptimizeOptions struct {
+	// DryRun reports what the run would change without changing anything,
+	// as cleaner.CleanOptions.DryRun does for a clean. Audit mode (see
+	// admin.AuditMode) forces it on.
+	DryRun bool
+	// KeepFontSmoothing leaves font smoothing on when
+	// OptimizeVisualEffects turns the other effects off.
+	KeepFontSmoothing bool
+	// UltimatePower makes OptimizePower switch to Ultimate Performance
+	// instead of High Performance.
+	UltimatePower bool
+	// DisableNagle makes OptimizeNetwork send and acknowledge small
+	// packets at once on every interface (TcpNoDelay and TcpAckFrequency),
+	// which interactive games benefit from at the cost of more packets.
+	DisableNagle bool
+}
+
+// dryRun reports whether a run with opts must only preview its changes.
+func (o OptimizeOptions) dryRun() bool {
+	return o.DryRun || admin.AuditMode()
+}
+
 // Results holds overall optimization results.
 type Results struct {
 	StartupDisabled int
@@ -14,37 +44,120 @@ type Results struct {
 
 // StartupResult holds startup optimization results.
 type StartupResult struct {
+	// Disabled counts the programs disabled, or in a dry run the ones
+	// that would be.
 	Disabled int
 	Programs []StartupProgram
+	// DryRun reports whether the result came from a preview run.
+	DryRun bool
+	// OperationID identifies the run in the log (see
+	// logger.StartOperation) and its journal (see Undo).
+	OperationID string
+	// RegistryChanges lists the registry values the run changed, or in a
+	// dry run the ones it would change.
+	RegistryChanges []reglog.Change
 }
 
 // StartupProgram represents a startup entry.
 type StartupProgram struct {
-	Name     string
-	Path     string
-	Impact   string
+	Name string
+	// Path is the command the entry runs.
+	Path string
+	// Impact rates StartupCost: ImpactHigh, ImpactMedium, ImpactLow or
+	// ImpactNotMeasured.
+	Impact string
+	// StartupCost is how long the program took to start in the boots
+	// Windows measured (see rateStartupImpact).
+	StartupCost time.Duration
+	// Source is where the entry comes from: StartupRun, StartupRunOnce,
+	// StartupFolder, StartupTask or StartupApp.
+	Source string
+	// Location is the registry key, folder or task folder holding the
+	// entry, e.g.
+	// HKEY_CURRENT_USER\SOFTWARE\Microsoft\Windows\CurrentVersion\Run.
+	Location string
 	Disabled bool
+	// DisabledAt is when the entry was disabled, if Windows recorded it.
+	DisabledAt time.Time
 }
 
 // NetworkResult holds network optimization results.
 type NetworkResult struct {
 	LatencyReduction int
-	Optimizations    []string
+	// Optimizations describes the settings changed. In a dry run each
+	// starts with "Would: ".
+	Optimizations   []string
+	Errors          []string
+	DryRun          bool
+	OperationID     string
+	RegistryChanges []reglog.Change
+	// TCPChanges lists the global TCP settings the run changed, or in a
+	// dry run the ones it would change.
+	TCPChanges []TCPChange
 }
 
 // DiskResult holds disk optimization results.
 type DiskResult struct {
-	IsSSD     bool
+	// IsSSD reports whether the system drive is on an SSD.
+	IsSSD bool
+	// Scheduled reports whether any volume was retrimmed or scheduled
+	// for defragmentation, or in a dry run whether any would be.
 	Scheduled bool
+	// Volumes lists each lettered volume and what was done for it.
+	Volumes     []VolumeAction
+	Errors      []string
+	DryRun      bool
+	OperationID string
 }
 
-// OptimizeStartup disables unnecessary startup programs.
-func OptimizeStartup() StartupResult {
-	return optimizeStartupPlatform()
+// OptimizeStartup disables the startup programs named in
+// AutoDisableStartup (see DisableStartup), after making a restore point
+// (see sysrestore.Before).
+func OptimizeStartup(opts OptimizeOptions) StartupResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-startup", dryRun))
+	defer op.End()
+	if runtime.GOOS == "windows" && !dryRun {
+		sysrestore.Before("startup optimization")
+	}
+	j := newJournal(op.ID)
+	j.dryRun = dryRun
+	result := optimizeStartup(j)
+	result.DryRun = dryRun
+	result.OperationID = op.ID
+	result.RegistryChanges = j.Changes
+	return result
 }
 
-// OptimizeNetwork optimizes network settings for low latency.
-func OptimizeNetwork() NetworkResult {
+// OptimizeNetwork optimizes network settings for low latency, after making
+// a restore point (see sysrestore.Before).
+func OptimizeNetwork(opts OptimizeOptions) NetworkResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-network", dryRun))
+	defer op.End()
+	if runtime.GOOS == "windows" && !dryRun {
+		sysrestore.Before("network optimization")
+	}
+	j := newJournal(op.ID)
+	j.dryRun = dryRun
+	result := optimizeNetwork(j, opts.DisableNagle)
+	result.DryRun = dryRun
+	result.OperationID = op.ID
+	result.RegistryChanges = j.Changes
+	result.TCPChanges = j.TCPChanges
+	return result
+}
+
+// operationKind names a run for logger.StartOperation, marking previews
+// the way the cleaner's "analyze" runs are.
+func operationKind(kind string, dryRun bool) string {
+	if dryRun {
+		return kind + "-preview"
+	}
+	return kind
+}
+
+func optimizeNetwork(j *Journal, noNagle bool) NetworkResult {
 	result := NetworkResult{}
 
 	if runtime.GOOS != "windows" {
@@ -52,11 +165,12 @@ func OptimizeNetwork() NetworkResult {
 		return result
 	}
 
+	// These are left to Windows on current versions or have no readable
+	// previous value, so they are not journaled.
 	commands := []struct {
 		args []string
 		desc string
 	}{
-		{[]string{"netsh", "int", "tcp", "set", "global", "autotuninglevel=normal"}, "Set TCP auto-tuning to normal"},
 		{[]string{"netsh", "int", "tcp", "set", "global", "chimney=enabled"}, "Enable TCP chimney offload"},
 		{[]string{"netsh", "int", "tcp", "set", "global", "dca=enabled"}, "Enable direct cache access"},
 		{[]string{"netsh", "int", "tcp", "set", "global", "netdma=enabled"}, "Enable NetDMA"},
@@ -65,78 +179,133 @@ func OptimizeNetwork() NetworkResult {
 	}
 
 	for _, c := range commands {
-		cmd := exec.Command(c.args[0], c.args[1:]...)
-		if runtime.GOOS == "windows" {
-			cmd.SysProcAttr = getSysProcAttr()
+		if j.dryRun {
+			result.Optimizations = append(result.Optimizations,
+				fmt.Sprintf("Would: %s (%s)", c.desc, strings.Join(c.args, " ")))
+			continue
 		}
+		cmd := exec.Command(c.args[0], c.args[1:]...)
+		cmd.SysProcAttr = getSysProcAttr()
 		if err := cmd.Run(); err == nil {
 			result.Optimizations = append(result.Optimizations, c.desc)
 			result.LatencyReduction += 2
 		}
 	}
 
-	// Disable network throttling via registry
-	if err := setNetworkThrottling(); err == nil {
-		result.Optimizations = append(result.Optimizations, "Disabled network throttling")
-		result.LatencyReduction += 2
+	optimizeTCPGlobals(j, &result)
+
+	applyMMCSSProfile(j, &result)
+
+	if noNagle {
+		n, err := disableNagle(j)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, fmt.Sprintf("disabling Nagle's algorithm: %v", err))
+		case n > 0 && j.dryRun:
+			result.Optimizations = append(result.Optimizations,
+				fmt.Sprintf("Would: Disable Nagle's algorithm on %d interface(s)", n))
+		case n > 0:
+			result.Optimizations = append(result.Optimizations,
+				fmt.Sprintf("Disabled Nagle's algorithm on %d interface(s)", n))
+			result.LatencyReduction += 2
+		}
 	}
 
 	return result
 }
 
-// OptimizeDisk optimizes disk performance.
-func OptimizeDisk() DiskResult {
+// OptimizeDisk optimizes each lettered volume for the media type of its
+// disk (see planDiskActions): SSDs are retrimmed and hard disks
+// defragmented weekly, never the other way round.
+func OptimizeDisk(opts OptimizeOptions) DiskResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-disk", dryRun))
+	defer op.End()
+	result := optimizeDisk(dryRun)
+	result.DryRun = dryRun
+	result.OperationID = op.ID
+	return result
+}
+
+func optimizeDisk(dryRun bool) DiskResult {
 	result := DiskResult{}
 
 	if runtime.GOOS != "windows" {
 		return result
 	}
 
-	// Detect SSD
-	out, err := exec.Command("powershell", "-Command",
-		"Get-PhysicalDisk | Where-Object MediaType -eq 'SSD' | Measure-Object | Select-Object -ExpandProperty Count").Output()
-	if err == nil {
-		count := strings.TrimSpace(string(out))
-		if count != "0" && count != "" {
-			result.IsSSD = true
-		}
+	volumes, err := listDiskVolumes()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
 	}
-
-	if result.IsSSD {
-		// Enable TRIM for SSD
-		cmd := exec.Command("fsutil", "behavior", "set", "DisableDeleteNotify", "0")
-		if runtime.GOOS == "windows" {
-			cmd.SysProcAttr = getSysProcAttr()
-		}
-		if err := cmd.Run(); err == nil {
-			result.Scheduled = true
-		}
-	} else {
-		// Schedule weekly defrag for HDD
-		cmd := exec.Command("schtasks", "/create", "/tn", "SysCleanerDefrag",
-			"/sc", "weekly", "/d", "SUN", "/st", "03:00",
-			"/tr", "defrag C: /O", "/f")
-		if runtime.GOOS == "windows" {
-			cmd.SysProcAttr = getSysProcAttr()
+	result.Volumes = planDiskActions(volumes)
+	for _, v := range result.Volumes {
+		if strings.EqualFold(v.Drive, sysinfo.SystemDrive()) {
+			result.IsSSD = v.MediaType == MediaSSD
 		}
-		if err := cmd.Run(); err == nil {
+		if v.Action != DiskNone {
 			result.Scheduled = true
 		}
 	}
 
+	if dryRun {
+		// Detection only reads; TRIM, retrim and the defrag task are left
+		// alone.
+		return result
+	}
+	if err := admin.RequireWriteAccess("optimizing disks"); err != nil {
+		result.Scheduled = false
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	applyDiskActions(result.Volumes)
+	result.Scheduled = false
+	for _, v := range result.Volumes {
+		result.Scheduled = result.Scheduled || v.Done
+	}
 	return result
 }
 
+// detectSSD reports whether the PC has an SSD. It is a variable so tests
+// can fake it.
+var detectSSD = detectSSDNative
+
+func detectSSDNative() bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	out, err := exec.Command("powershell", "-Command",
+		"Get-PhysicalDisk | Where-Object MediaType -eq 'SSD' | Measure-Object | Select-Object -ExpandProperty Count").Output()
+	if err != nil {
+		return false
+	}
+	count := strings.TrimSpace(string(out))
+	return count != "0" && count != ""
+}
+
 // PrintStartupResult displays startup optimization results.
 func PrintStartupResult(result StartupResult) {
-	fmt.Printf("  Startup programs disabled: %d\n", result.Disabled)
+	if result.DryRun {
+		fmt.Printf("  Startup programs that would be disabled: %d\n", result.Disabled)
+	} else {
+		fmt.Printf("  Startup programs disabled: %d\n", result.Disabled)
+	}
 	for _, p := range result.Programs {
 		status := "kept"
-		if p.Disabled {
+		if p.Disabled && result.DryRun {
+			status = "WOULD DISABLE"
+		} else if p.Disabled {
 			status = "DISABLED"
 		}
 		fmt.Printf("    [%s] %s (%s)\n", status, p.Name, p.Impact)
 	}
+	printRegistryChanges(result.DryRun, result.RegistryChanges)
+	printOperation(result.OperationID)
+	if len(result.RegistryChanges) > 0 && !result.DryRun {
+		printUndo(result.OperationID)
+	}
 }
 
 // PrintNetworkResult displays network optimization results.
@@ -145,19 +314,47 @@ func PrintNetworkResult(result NetworkResult) {
 	for _, opt := range result.Optimizations {
 		fmt.Printf("    - %s\n", opt)
 	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", e)
+	}
+	printRegistryChanges(result.DryRun, result.RegistryChanges)
+	printOperation(result.OperationID)
+	if len(result.RegistryChanges)+len(result.TCPChanges) > 0 && !result.DryRun {
+		printUndo(result.OperationID)
+	}
 }
 
 // PrintDiskResult displays disk optimization results.
 func PrintDiskResult(result DiskResult) {
-	if result.IsSSD {
-		fmt.Println("  Disk type: SSD")
-		if result.Scheduled {
-			fmt.Println("  TRIM enabled for optimal SSD performance")
-		}
-	} else {
-		fmt.Println("  Disk type: HDD")
-		if result.Scheduled {
-			fmt.Println("  Weekly defragmentation scheduled (Sundays at 3:00 AM)")
-		}
+	for _, v := range result.Volumes {
+		fmt.Printf("  %s (%s): %s\n", v.Drive, v.MediaType, describeVolumeAction(v, result.DryRun))
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("  Error: %s\n", e)
+	}
+	printOperation(result.OperationID)
+}
+
+// printRegistryChanges lists the registry values a dry run would change.
+// A real run's changes are in its journal (see Journals).
+func printRegistryChanges(dryRun bool, changes []reglog.Change) {
+	if !dryRun || len(changes) == 0 {
+		return
+	}
+	fmt.Println("  Registry values that would change:")
+	for _, c := range changes {
+		fmt.Printf("    %s\\%s: %s -> %s\n", c.Key(), c.Name, reglog.DescribeValue(c.Old), reglog.DescribeValue(c.New))
+	}
+}
+
+func printOperation(id string) {
+	if id != "" {
+		fmt.Printf("  Operation: %s\n", id)
+	}
+}
+
+func printUndo(id string) {
+	if id != "" {
+		fmt.Printf("  Undo with: syscleaner optimize --undo %s\n", id)
 	}
 }
@@ -8,10 +8,10 @@ func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{}
 }
 
-func optimizeStartupPlatform() StartupResult {
-	return StartupResult{}
+func setMMCSSValueNative(j *Journal, v mmcssValue) error {
+	return nil
 }
 
-func setNetworkThrottling() error {
-	return nil
+func disableNagle(j *Journal) (int, error) {
+	return 0, nil
 }
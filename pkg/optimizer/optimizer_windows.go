@@ -3,16 +3,13 @@
 package optimizer
 
 import (
+	"fmt"
 	"syscall"
 
 	"golang.org/x/sys/windows/registry"
-)
 
-var unnecessaryStartup = []string{
-	"OneDrive", "Skype", "Spotify", "Discord",
-	"Steam", "EpicGamesLauncher", "AdobeUpdater",
-	"iTunes", "iTunesHelper",
-}
+	"syscleaner/pkg/reglog"
+)
 
 func getSysProcAttr() *syscall.SysProcAttr {
 	// NOTE: Do NOT set HideWindow: true — it triggers AV heuristics
@@ -21,72 +18,68 @@ func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{}
 }
 
-func optimizeStartupPlatform() StartupResult {
-	result := StartupResult{}
-
-	regPaths := []struct {
-		root registry.Key
-		path string
-	}{
-		{registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows\CurrentVersion\Run`},
-		{registry.CURRENT_USER, `SOFTWARE\Microsoft\Windows\CurrentVersion\Run`},
+func setMMCSSValueNative(j *Journal, v mmcssValue) error {
+	if v.str != "" {
+		return j.setValue(registry.LOCAL_MACHINE, v.path, v.name, reglog.Value{Kind: reglog.KindString, String: v.str})
 	}
+	return j.setDWordValue(registry.LOCAL_MACHINE, v.path, v.name, v.dword)
+}
 
-	for _, rp := range regPaths {
-		key, err := registry.OpenKey(rp.root, rp.path, registry.QUERY_VALUE|registry.SET_VALUE)
-		if err != nil {
-			continue
-		}
+// tcpInterfacesPath holds each network interface's TCP/IP parameters, one
+// subkey per interface GUID.
+const tcpInterfacesPath = `SYSTEM\CurrentControlSet\Services\Tcpip\Parameters\Interfaces`
 
-		names, err := key.ReadValueNames(-1)
-		if err != nil {
-			key.Close()
+// disableNagle sets TcpAckFrequency and TCPNoDelay on every interface
+// with an IPv4 address, so small packets are acknowledged and sent without
+// waiting to be batched. It returns how many interfaces it set; in a dry
+// run, how many it would change.
+func disableNagle(j *Journal) (int, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, tcpInterfacesPath, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return 0, err
+	}
+	ids, err := k.ReadSubKeyNames(-1)
+	k.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, id := range ids {
+		path := tcpInterfacesPath + `\` + id
+		if !interfaceHasAddress(path) {
 			continue
 		}
-
-		for _, name := range names {
-			val, _, err := key.GetStringValue(name)
-			if err != nil {
-				continue
+		before := len(j.Changes)
+		for _, name := range []string{"TcpAckFrequency", "TCPNoDelay"} {
+			if err := j.setDWordValue(registry.LOCAL_MACHINE, path, name, 1); err != nil {
+				return n, fmt.Errorf("interface %s: %w", id, err)
 			}
-
-			isUnnecessary := false
-			for _, u := range unnecessaryStartup {
-				if name == u {
-					isUnnecessary = true
-					break
-				}
-			}
-
-			prog := StartupProgram{
-				Name: name,
-				Path: val,
-			}
-
-			if isUnnecessary {
-				prog.Impact = "High"
-				if err := key.DeleteValue(name); err == nil {
-					prog.Disabled = true
-					result.Disabled++
-				}
-			} else {
-				prog.Impact = "Low"
-			}
-			result.Programs = append(result.Programs, prog)
 		}
-		key.Close()
+		if len(j.Changes) > before {
+			n++
+		}
 	}
-
-	return result
+	return n, nil
 }
 
-func setNetworkThrottling() error {
-	key, _, err := registry.CreateKey(registry.LOCAL_MACHINE,
-		`SOFTWARE\Microsoft\Windows NT\CurrentVersion\Multimedia\SystemProfile`,
-		registry.SET_VALUE)
+// interfaceHasAddress reports whether the interface at path has an IPv4
+// address, assigned by DHCP or set by hand.
+func interfaceHasAddress(path string) bool {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
 	if err != nil {
-		return err
+		return false
+	}
+	defer k.Close()
+	if addr, _, err := k.GetStringValue("DhcpIPAddress"); err == nil && addr != "" && addr != "0.0.0.0" {
+		return true
+	}
+	if addrs, _, err := k.GetStringsValue("IPAddress"); err == nil {
+		for _, addr := range addrs {
+			if addr != "" && addr != "0.0.0.0" {
+				return true
+			}
+		}
 	}
-	defer key.Close()
-	return key.SetDWordValue("NetworkThrottlingIndex", 0xffffffff)
+	return false
 }
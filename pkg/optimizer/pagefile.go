@@ -0,0 +1,401 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/sysinfo"
+	"syscleaner/pkg/sysrestore"
+)
+
+// PageFile is a page file Windows is set to create at boot.
+type PageFile struct {
+	// Path is the file, e.g. C:\pagefile.sys.
+	Path string `json:"path"`
+	// InitialMB and MaximumMB are its sizes. Both are 0 when Windows
+	// sizes it ("System managed size").
+	InitialMB int `json:"initial_mb"`
+	MaximumMB int `json:"maximum_mb"`
+}
+
+// Drive returns the drive the page file is on, e.g. "C:".
+func (p PageFile) Drive() string {
+	if len(p.Path) < 2 || p.Path[1] != ':' {
+		return ""
+	}
+	return strings.ToUpper(p.Path[:2])
+}
+
+// SystemManaged reports whether Windows sizes the page file.
+func (p PageFile) SystemManaged() bool {
+	return p.InitialMB == 0 && p.MaximumMB == 0
+}
+
+// PageFileConfig is how Windows places and sizes its page files.
+type PageFileConfig struct {
+	// Automatic is "Automatically manage paging file size for all
+	// drives"; Files is ignored while it is set.
+	Automatic bool       `json:"automatic"`
+	Files     []PageFile `json:"files"`
+}
+
+// PageFileStatus is the page file configuration and how much of the page
+// file is in use.
+type PageFileStatus struct {
+	PageFileConfig
+	// AllocatedMB, UsageMB and PeakMB are the current size, use and
+	// highest use since boot, across all page files.
+	AllocatedMB int `json:"allocated_mb"`
+	UsageMB     int `json:"usage_mb"`
+	PeakMB      int `json:"peak_mb"`
+	RAMMB       int `json:"ram_mb"`
+}
+
+// fixedDrive is a local fixed drive a page file can go on.
+type fixedDrive struct {
+	Drive  string `json:"drive"`
+	FreeMB int    `json:"free_mb"`
+	SSD    bool   `json:"-"`
+}
+
+// PageFilePlan is the fixed-size page file ConfigurePageFile sets up.
+type PageFilePlan struct {
+	Drive  string
+	SizeMB int
+	// Warnings are what the user should know before applying it.
+	Warnings []string
+}
+
+// Page file sizing bounds.
+const (
+	minPageFileMB = 4096
+	maxPageFileMB = 16384
+	// pageFileHeadroomMB is the free space left on a drive after the page
+	// file is created.
+	pageFileHeadroomMB = 10240
+)
+
+// These are variables so tests can fake WMI and the drives.
+var (
+	queryPageFile   = queryPageFileNative
+	listFixedDrives = listFixedDrivesNative
+	applyPageFile   = applyPageFileNative
+	driveIsSSD      = sysinfo.IsSSD
+)
+
+// pageFileBackupPath is where the page file configuration is kept before
+// SysCleaner changes it.
+var pageFileBackupPath = func() string {
+	return backupPath("pagefile-backup.json")
+}
+
+// PageFileInfo returns the page file configuration and usage.
+func PageFileInfo() (PageFileStatus, error) {
+	return queryPageFile()
+}
+
+// recommendedPageFileMB sizes a fixed page file: one and a half times RAM
+// up to 8 GB of RAM and as much as RAM above that, kept between 4 and 16
+// GB but never below the peak use seen since boot plus a quarter.
+func recommendedPageFileMB(ramMB, peakMB int) int {
+	size := ramMB
+	if ramMB <= 8192 {
+		size = ramMB * 3 / 2
+	}
+	size = max(minPageFileMB, min(size, maxPageFileMB))
+	return max(size, peakMB*5/4)
+}
+
+// pickPageFileDrive returns the fastest fixed drive with room for a page
+// file of sizeMB: SSDs before hard disks, then the system drive, so crash
+// dumps keep working, then the most free space.
+func pickPageFileDrive(drives []fixedDrive, sizeMB int, systemDrive string) (fixedDrive, error) {
+	var fit []fixedDrive
+	for _, d := range drives {
+		if d.FreeMB >= sizeMB+pageFileHeadroomMB {
+			fit = append(fit, d)
+		}
+	}
+	if len(fit) == 0 {
+		return fixedDrive{}, fmt.Errorf("no drive has %d MB free for a %d MB page file", sizeMB+pageFileHeadroomMB, sizeMB)
+	}
+	sort.SliceStable(fit, func(a, b int) bool {
+		da, db := fit[a], fit[b]
+		if da.SSD != db.SSD {
+			return da.SSD
+		}
+		if sa, sb := strings.EqualFold(da.Drive, systemDrive), strings.EqualFold(db.Drive, systemDrive); sa != sb {
+			return sa
+		}
+		return da.FreeMB > db.FreeMB
+	})
+	return fit[0], nil
+}
+
+// pageFileWarnings lists what applying plan means on this PC.
+func pageFileWarnings(status PageFileStatus, plan PageFilePlan, ssd bool, systemDrive string) []string {
+	warnings := []string{"The new page file takes effect after a restart."}
+	if !strings.EqualFold(plan.Drive, systemDrive) {
+		warnings = append(warnings, fmt.Sprintf("With no page file on %s, Windows cannot save a memory dump after a crash.", systemDrive))
+	}
+	if !ssd {
+		warnings = append(warnings, plan.Drive+" is a hard disk; paging to it is slow.")
+	}
+	if plan.SizeMB < status.RAMMB {
+		warnings = append(warnings, "The page file is smaller than RAM, so a complete memory dump will not fit.")
+	}
+	if status.PeakMB > 0 && plan.SizeMB < status.PeakMB*5/4 {
+		warnings = append(warnings, fmt.Sprintf("Up to %d MB of page file has been used since boot; programs may run out of memory.", status.PeakMB))
+	}
+	return warnings
+}
+
+// PlanPageFile works out a fixed-size page file. An empty drive or a
+// sizeMB of 0 picks the fastest drive with room and the recommended size.
+func PlanPageFile(drive string, sizeMB int) (PageFilePlan, error) {
+	status, err := queryPageFile()
+	if err != nil {
+		return PageFilePlan{}, err
+	}
+	return planPageFile(status, drive, sizeMB)
+}
+
+func planPageFile(status PageFileStatus, drive string, sizeMB int) (PageFilePlan, error) {
+	drives, err := listFixedDrives()
+	if err != nil {
+		return PageFilePlan{}, err
+	}
+	for i := range drives {
+		drives[i].SSD, _ = driveIsSSD(drives[i].Drive)
+	}
+	if sizeMB == 0 {
+		sizeMB = recommendedPageFileMB(status.RAMMB, status.PeakMB)
+	} else if sizeMB < minPageFileMB/2 {
+		return PageFilePlan{}, fmt.Errorf("a %d MB page file is too small (at least %d MB)", sizeMB, minPageFileMB/2)
+	}
+
+	system := strings.ToUpper(sysinfo.SystemDrive())
+	var target fixedDrive
+	if drive == "" {
+		if target, err = pickPageFileDrive(drives, sizeMB, system); err != nil {
+			return PageFilePlan{}, err
+		}
+	} else {
+		drive = strings.ToUpper(strings.TrimSuffix(strings.TrimSuffix(drive, `\`), ":") + ":")
+		found := false
+		for _, d := range drives {
+			if strings.EqualFold(d.Drive, drive) {
+				target, found = d, true
+			}
+		}
+		if !found {
+			return PageFilePlan{}, fmt.Errorf("%s is not a local fixed drive", drive)
+		}
+		// The page file already there is replaced, so its space counts. As
+		// for a drive picked automatically, pageFileHeadroomMB must be left
+		// free, so the page file cannot fill the drive.
+		free := target.FreeMB
+		for _, f := range status.Files {
+			if f.Drive() == drive {
+				free += f.MaximumMB
+			}
+		}
+		if free < sizeMB+pageFileHeadroomMB {
+			return PageFilePlan{}, fmt.Errorf("%s has only %d MB free; a %d MB page file needs %d MB to leave %d MB free",
+				drive, free, sizeMB, sizeMB+pageFileHeadroomMB, pageFileHeadroomMB)
+		}
+	}
+
+	plan := PageFilePlan{Drive: target.Drive, SizeMB: sizeMB}
+	plan.Warnings = pageFileWarnings(status, plan, target.SSD, system)
+	return plan, nil
+}
+
+// PageFileResult holds page file configuration results.
+type PageFileResult struct {
+	Plan PageFilePlan
+	// Previous is the configuration before the change.
+	Previous    PageFileConfig
+	Errors      []string
+	DryRun      bool
+	OperationID string
+}
+
+// ConfigurePageFile replaces the page files with one fixed-size page file
+// as PlanPageFile works it out, after making a restore point (see
+// sysrestore.Before). The configuration before SysCleaner first changed it
+// is kept for RestorePageFile.
+func ConfigurePageFile(drive string, sizeMB int, opts OptimizeOptions) PageFileResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-pagefile", dryRun))
+	defer op.End()
+	result := PageFileResult{DryRun: dryRun, OperationID: op.ID}
+
+	status, err := queryPageFile()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	plan, err := planPageFile(status, drive, sizeMB)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	result.Plan = plan
+	result.Previous = status.PageFileConfig
+	if dryRun {
+		return result
+	}
+	if err := admin.RequireWriteAccess("configuring the page file"); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	if _, ok, err := loadBackup[PageFileConfig](pageFileBackupPath()); err == nil && !ok {
+		if err := saveBackup(pageFileBackupPath(), status.PageFileConfig); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("saving the page file configuration: %v", err))
+			return result
+		}
+	} else if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	if runtime.GOOS == "windows" {
+		sysrestore.Before("page file configuration")
+	}
+	cfg := PageFileConfig{Files: []PageFile{{
+		Path:      plan.Drive + `\pagefile.sys`,
+		InitialMB: plan.SizeMB,
+		MaximumMB: plan.SizeMB,
+	}}}
+	if err := applyPageFile(cfg); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	return result
+}
+
+// RestorePageFile puts back the page file configuration from before
+// SysCleaner changed it. Like any page file change, it takes effect after
+// a restart.
+func RestorePageFile() error {
+	if err := admin.RequireWriteAccess("restoring the page file configuration"); err != nil {
+		return err
+	}
+	cfg, ok, err := loadBackup[PageFileConfig](pageFileBackupPath())
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.New("SysCleaner has not changed the page file")
+	}
+	if err := applyPageFile(cfg); err != nil {
+		return err
+	}
+	return os.Remove(pageFileBackupPath())
+}
+
+// pageFileStatusScript prints the page file configuration and usage as a
+// PageFileStatus in JSON.
+const pageFileStatusScript = `$cs = Get-CimInstance Win32_ComputerSystem; ` +
+	`$usage = @(Get-CimInstance Win32_PageFileUsage); ` +
+	`ConvertTo-Json -Compress -InputObject ([pscustomobject]@{ ` +
+	`automatic = [bool]$cs.AutomaticManagedPagefile; ` +
+	`ram_mb = [int]($cs.TotalPhysicalMemory / 1MB); ` +
+	`files = @(Get-CimInstance Win32_PageFileSetting | ForEach-Object { [pscustomobject]@{ path = $_.Name; initial_mb = [int]$_.InitialSize; maximum_mb = [int]$_.MaximumSize } }); ` +
+	`allocated_mb = [int]($usage | Measure-Object AllocatedBaseSize -Sum).Sum; ` +
+	`usage_mb = [int]($usage | Measure-Object CurrentUsage -Sum).Sum; ` +
+	`peak_mb = [int]($usage | Measure-Object PeakUsage -Sum).Sum })`
+
+// fixedDrivesScript prints the local fixed drives as fixedDrive in JSON.
+const fixedDrivesScript = `ConvertTo-Json -Compress -InputObject @(Get-CimInstance Win32_LogicalDisk -Filter 'DriveType=3' | ` +
+	`ForEach-Object { [pscustomobject]@{ drive = $_.DeviceID; free_mb = [int64]($_.FreeSpace / 1MB) } })`
+
+// pageFileApplyScript returns a script that sets up cfg: automatic
+// management, or else exactly cfg.Files.
+func pageFileApplyScript(cfg PageFileConfig) string {
+	var b strings.Builder
+	b.WriteString("$ErrorActionPreference = 'Stop'; ")
+	b.WriteString("Get-CimInstance Win32_ComputerSystem | Set-CimInstance -Property @{ AutomaticManagedPagefile = $false }; ")
+	b.WriteString("Get-CimInstance Win32_PageFileSetting | Remove-CimInstance; ")
+	if cfg.Automatic {
+		b.WriteString("Get-CimInstance Win32_ComputerSystem | Set-CimInstance -Property @{ AutomaticManagedPagefile = $true }")
+		return b.String()
+	}
+	for _, f := range cfg.Files {
+		fmt.Fprintf(&b, "New-CimInstance -ClassName Win32_PageFileSetting -Property @{ Name = '%s' } | "+
+			"Set-CimInstance -Property @{ InitialSize = [uint32]%d; MaximumSize = [uint32]%d }; ",
+			strings.ReplaceAll(f.Path, "'", "''"), f.InitialMB, f.MaximumMB)
+	}
+	return strings.TrimSuffix(b.String(), "; ")
+}
+
+// parsePageFileStatus parses the output of pageFileStatusScript.
+func parsePageFileStatus(data []byte) (PageFileStatus, error) {
+	var status PageFileStatus
+	if err := json.Unmarshal(bytes.TrimSpace(data), &status); err != nil {
+		return status, fmt.Errorf("parsing page file settings: %w", err)
+	}
+	return status, nil
+}
+
+// parseFixedDrives parses the output of fixedDrivesScript.
+func parseFixedDrives(data []byte) ([]fixedDrive, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var drives []fixedDrive
+	if err := json.Unmarshal(data, &drives); err != nil {
+		return nil, fmt.Errorf("parsing drives: %w", err)
+	}
+	return drives, nil
+}
+
+// PrintPageFileStatus displays the page file configuration and usage.
+func PrintPageFileStatus(status PageFileStatus) {
+	switch {
+	case status.Automatic:
+		fmt.Println("  Page file: managed automatically by Windows")
+	case len(status.Files) == 0:
+		fmt.Println("  Page file: none")
+	}
+	if !status.Automatic {
+		for _, f := range status.Files {
+			if f.SystemManaged() {
+				fmt.Printf("  %s: system managed size\n", f.Path)
+			} else {
+				fmt.Printf("  %s: %d - %d MB\n", f.Path, f.InitialMB, f.MaximumMB)
+			}
+		}
+	}
+	fmt.Printf("  Allocated %d MB, in use %d MB, peak %d MB (RAM %d MB)\n",
+		status.AllocatedMB, status.UsageMB, status.PeakMB, status.RAMMB)
+}
+
+// PrintPageFileResult displays page file configuration results.
+func PrintPageFileResult(result PageFileResult) {
+	if result.Plan.Drive != "" {
+		verb := "Set"
+		if result.DryRun {
+			verb = "Would set"
+		}
+		fmt.Printf("  %s a fixed %d MB page file on %s\n", verb, result.Plan.SizeMB, result.Plan.Drive)
+		for _, w := range result.Plan.Warnings {
+			fmt.Printf("    Warning: %s\n", w)
+		}
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", strings.TrimSpace(e))
+	}
+	printOperation(result.OperationID)
+	if len(result.Errors) == 0 && !result.DryRun {
+		fmt.Println("  Restore with: syscleaner pagefile --restore")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func queryPageFileNative() (PageFileStatus, error) {
+	return PageFileStatus{}, fmt.Errorf("page file configuration is only available on Windows")
+}
+
+func listFixedDrivesNative() ([]fixedDrive, error) {
+	return nil, fmt.Errorf("page file configuration is only available on Windows")
+}
+
+func applyPageFileNative(cfg PageFileConfig) error {
+	return fmt.Errorf("page file configuration is only available on Windows")
+}
@@ -0,0 +1,128 @@
+package optimizer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecommendedPageFileMB(t *testing.T) {
+	for _, tc := range []struct{ ram, peak, want int }{
+		{2048, 0, 4096},
+		{8192, 0, 12288},
+		{16384, 0, 16384},
+		{65536, 0, 16384},
+		{16384, 16000, 20000},
+	} {
+		if got := recommendedPageFileMB(tc.ram, tc.peak); got != tc.want {
+			t.Errorf("recommendedPageFileMB(%d, %d) = %d, want %d", tc.ram, tc.peak, got, tc.want)
+		}
+	}
+}
+
+func TestPickPageFileDrive(t *testing.T) {
+	drives := []fixedDrive{
+		{Drive: "D:", FreeMB: 500000},
+		{Drive: "C:", FreeMB: 30000, SSD: true},
+		{Drive: "E:", FreeMB: 200000, SSD: true},
+	}
+	if d, err := pickPageFileDrive(drives, 16384, "C:"); err != nil || d.Drive != "C:" {
+		t.Errorf("pick = %+v, %v; want the system SSD", d, err)
+	}
+	// A full system drive gives way to the other SSD, not the hard disk.
+	drives[1].FreeMB = 20000
+	if d, err := pickPageFileDrive(drives, 16384, "C:"); err != nil || d.Drive != "E:" {
+		t.Errorf("pick = %+v, %v; want E:", d, err)
+	}
+	if _, err := pickPageFileDrive(drives[1:2], 16384, "C:"); err == nil {
+		t.Error("pick with no room = nil error")
+	}
+}
+
+func TestConfigurePageFile_Restore(t *testing.T) {
+	dir := t.TempDir()
+	origPath, origQuery, origList, origApply, origSSD := pageFileBackupPath, queryPageFile, listFixedDrives, applyPageFile, driveIsSSD
+	t.Cleanup(func() {
+		pageFileBackupPath, queryPageFile, listFixedDrives, applyPageFile, driveIsSSD = origPath, origQuery, origList, origApply, origSSD
+	})
+	t.Setenv("SystemDrive", "C:")
+
+	original := PageFileConfig{Automatic: true, Files: []PageFile{{Path: `C:\pagefile.sys`}}}
+	cfg := original
+	pageFileBackupPath = func() string { return dir + "/pagefile-backup.json" }
+	queryPageFile = func() (PageFileStatus, error) {
+		return PageFileStatus{PageFileConfig: cfg, RAMMB: 16384, PeakMB: 2048}, nil
+	}
+	listFixedDrives = func() ([]fixedDrive, error) {
+		return []fixedDrive{{Drive: "C:", FreeMB: 8000}, {Drive: "D:", FreeMB: 100000}}, nil
+	}
+	applyPageFile = func(c PageFileConfig) error {
+		cfg = c
+		return nil
+	}
+	driveIsSSD = func(drive string) (bool, error) { return drive == "D:", nil }
+
+	preview := ConfigurePageFile("", 0, OptimizeOptions{DryRun: true})
+	if len(preview.Errors) > 0 || preview.Plan.Drive != "D:" || preview.Plan.SizeMB != 16384 {
+		t.Fatalf("dry run = %+v; want 16384 MB on D:", preview)
+	}
+	if !reflect.DeepEqual(cfg, original) {
+		t.Fatalf("dry run changed the configuration to %+v", cfg)
+	}
+	warnings := strings.Join(preview.Plan.Warnings, "\n")
+	if !strings.Contains(warnings, "memory dump after a crash") {
+		t.Errorf("warnings %q do not mention crash dumps", warnings)
+	}
+
+	// An explicit drive keeps the same headroom as one picked automatically.
+	if full := ConfigurePageFile("C:", 4096, OptimizeOptions{DryRun: true}); len(full.Errors) == 0 {
+		t.Errorf("a 4096 MB page file on C: with 8000 MB free = %+v, want an error", full.Plan)
+	}
+
+	result := ConfigurePageFile("", 0, OptimizeOptions{})
+	if len(result.Errors) > 0 {
+		t.Fatalf("ConfigurePageFile errors: %v", result.Errors)
+	}
+	want := PageFileConfig{Files: []PageFile{{Path: `D:\pagefile.sys`, InitialMB: 16384, MaximumMB: 16384}}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("configuration = %+v, want %+v", cfg, want)
+	}
+	// A second run keeps the configuration from before the first.
+	ConfigurePageFile("D:", 8192, OptimizeOptions{})
+	if cfg.Files[0].MaximumMB != 8192 {
+		t.Fatalf("second run configuration = %+v", cfg)
+	}
+
+	if err := RestorePageFile(); err != nil {
+		t.Fatalf("RestorePageFile: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, original) {
+		t.Errorf("restored %+v, want %+v", cfg, original)
+	}
+	if err := RestorePageFile(); err == nil {
+		t.Error("second RestorePageFile = nil, want an error")
+	}
+}
+
+func TestPageFileApplyScript(t *testing.T) {
+	script := pageFileApplyScript(PageFileConfig{Files: []PageFile{{Path: `D:\pagefile.sys`, InitialMB: 4096, MaximumMB: 8192}}})
+	for _, want := range []string{"AutomaticManagedPagefile = $false", "Remove-CimInstance", `Name = 'D:\pagefile.sys'`, "InitialSize = [uint32]4096", "MaximumSize = [uint32]8192"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script %q lacks %q", script, want)
+		}
+	}
+	if script := pageFileApplyScript(PageFileConfig{Automatic: true}); !strings.HasSuffix(script, "AutomaticManagedPagefile = $true }") {
+		t.Errorf("automatic script = %q", script)
+	}
+}
+
+func TestParsePageFileStatus(t *testing.T) {
+	out := `{"automatic":false,"ram_mb":16310,"files":[{"path":"C:\\pagefile.sys","initial_mb":0,"maximum_mb":0}],"allocated_mb":2432,"usage_mb":310,"peak_mb":780}`
+	status, err := parsePageFileStatus([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Automatic || len(status.Files) != 1 || !status.Files[0].SystemManaged() || status.Files[0].Drive() != "C:" || status.PeakMB != 780 {
+		t.Errorf("parsePageFileStatus = %+v", status)
+	}
+}
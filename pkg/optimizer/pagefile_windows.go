@@ -0,0 +1,46 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func runPageFileScript(script string) ([]byte, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// queryPageFileNative reads the page file settings from WMI's
+// Win32_ComputerSystem, Win32_PageFileSetting and Win32_PageFileUsage.
+func queryPageFileNative() (PageFileStatus, error) {
+	out, err := runPageFileScript(pageFileStatusScript)
+	if err != nil {
+		return PageFileStatus{}, fmt.Errorf("reading page file settings: %w", err)
+	}
+	return parsePageFileStatus(out)
+}
+
+func listFixedDrivesNative() ([]fixedDrive, error) {
+	out, err := runPageFileScript(fixedDrivesScript)
+	if err != nil {
+		return nil, fmt.Errorf("listing drives: %w", err)
+	}
+	return parseFixedDrives(out)
+}
+
+// applyPageFileNative replaces the Win32_PageFileSetting instances with
+// cfg.
+func applyPageFileNative(cfg PageFileConfig) error {
+	if _, err := runPageFileScript(pageFileApplyScript(cfg)); err != nil {
+		return fmt.Errorf("configuring the page file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package optimizer
+
+import (
+	"fmt"
+	"runtime"
+
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/power"
+)
+
+// These are variables so tests can fake the power plans.
+var (
+	activePowerPlan       = power.Active
+	ensureHighPerformance = power.EnsureHighPerformance
+	ensureUltimate        = power.EnsureUltimate
+	activatePowerPlan     = power.Activate
+	readPowerSetting      = power.Read
+	writePowerSetting     = power.Write
+)
+
+// PowerResult holds power plan optimization results.
+type PowerResult struct {
+	// Optimizations describes the settings changed. In a dry run each
+	// starts with "Would: ".
+	Optimizations []string
+	Errors        []string
+	DryRun        bool
+	OperationID   string
+	// Previous is the plan that was active before, for switching back.
+	Previous string
+}
+
+// OptimizePower switches to the High Performance plan, or Ultimate
+// Performance with opts.UltimatePower (creating it if Windows hides it),
+// and turns off USB selective suspend on AC power in it. Battery settings
+// are left alone. Power plans are not in the registry journal; the result
+// names the previous plan to switch back to.
+func OptimizePower(opts OptimizeOptions) PowerResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-power", dryRun))
+	defer op.End()
+	result := PowerResult{DryRun: dryRun, OperationID: op.ID}
+
+	if runtime.GOOS != "windows" {
+		result.Optimizations = append(result.Optimizations, "Power plan optimization is only available on Windows")
+		return result
+	}
+	previous, err := activePowerPlan()
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("reading the active power plan: %v", err))
+		return result
+	}
+	result.Previous = previous
+
+	name, target, ensure := "High Performance", power.HighPerformance, ensureHighPerformance
+	if opts.UltimatePower {
+		name, target, ensure = "Ultimate Performance", power.UltimatePerformance, ensureUltimate
+	}
+	if dryRun {
+		// Ensuring a hidden plan would create it, so a preview only names it.
+		if previous != target {
+			result.Optimizations = append(result.Optimizations, "Would: switch to the "+name+" power plan")
+		}
+		result.Optimizations = append(result.Optimizations, "Would: turn off USB selective suspend on AC power")
+		return result
+	}
+
+	plan, err := ensure()
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s power plan: %v", name, err))
+		return result
+	}
+	if plan != previous {
+		if err := activatePowerPlan(plan); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			return result
+		}
+		result.Optimizations = append(result.Optimizations, "Switched to the "+name+" power plan")
+	}
+	if ac, dc, err := readPowerSetting(plan, power.USBSelectiveSuspend); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("reading USB selective suspend: %v", err))
+	} else if ac != 0 {
+		if err := writePowerSetting(plan, power.USBSelectiveSuspend, 0, dc); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("turning off USB selective suspend: %v", err))
+		} else {
+			result.Optimizations = append(result.Optimizations, "Turned off USB selective suspend on AC power")
+		}
+	}
+	return result
+}
+
+// PrintPowerResult displays power plan optimization results.
+func PrintPowerResult(result PowerResult) {
+	for _, opt := range result.Optimizations {
+		fmt.Printf("    - %s\n", opt)
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", e)
+	}
+	printOperation(result.OperationID)
+	if len(result.Optimizations) > 0 && !result.DryRun && result.Previous != "" {
+		fmt.Printf("  Switch back with: syscleaner power --activate %s\n", result.Previous)
+	}
+}
@@ -0,0 +1,119 @@
+package optimizer
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/reglog"
+	"syscleaner/pkg/sysrestore"
+)
+
+// privacySetting is a registry DWORD OptimizePrivacy sets.
+type privacySetting struct {
+	desc string
+	// machine puts the value under HKEY_LOCAL_MACHINE, which needs
+	// administrator rights, rather than HKEY_CURRENT_USER.
+	machine bool
+	path    string
+	name    string
+	value   uint32
+}
+
+// PrivacyResult holds privacy optimization results.
+type PrivacyResult struct {
+	// Optimizations describes the settings changed. In a dry run each
+	// starts with "Would: ".
+	Optimizations []string
+	// Skipped describes the settings left alone for lack of rights.
+	Skipped         []string
+	DryRun          bool
+	OperationID     string
+	RegistryChanges []reglog.Change
+}
+
+// minimumTelemetryLevel returns the lowest diagnostic data level edition
+// honors: Security (0) on Enterprise, Education and Server, Required (1)
+// elsewhere, where 0 is treated as 1 anyway.
+func minimumTelemetryLevel(edition string) uint32 {
+	e := strings.ToLower(edition)
+	if strings.HasPrefix(e, "enterprise") || strings.HasPrefix(e, "education") || strings.Contains(e, "server") {
+		return 0
+	}
+	return 1
+}
+
+func privacySettings() []privacySetting {
+	return []privacySetting{
+		{"Set diagnostic data to the minimum level", true,
+			`SOFTWARE\Policies\Microsoft\Windows\DataCollection`, "AllowTelemetry", minimumTelemetryLevel(windowsEdition())},
+		{"Disable tailored experiences", false,
+			`Software\Microsoft\Windows\CurrentVersion\Privacy`, "TailoredExperiencesWithDiagnosticDataEnabled", 0},
+		{"Disable the advertising ID", false,
+			`Software\Microsoft\Windows\CurrentVersion\AdvertisingInfo`, "Enabled", 0},
+	}
+}
+
+// OptimizePrivacy sets diagnostic data to the minimum level the edition
+// supports and turns off tailored experiences and the advertising ID,
+// after making a restore point (see sysrestore.Before). Every value is
+// journaled, so Undo puts it back.
+func OptimizePrivacy(opts OptimizeOptions) PrivacyResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-privacy", dryRun))
+	defer op.End()
+	if runtime.GOOS == "windows" && !dryRun {
+		sysrestore.Before("privacy optimization")
+	}
+	j := newJournal(op.ID)
+	j.dryRun = dryRun
+	result := optimizePrivacy(j)
+	result.DryRun = dryRun
+	result.OperationID = op.ID
+	result.RegistryChanges = j.Changes
+	return result
+}
+
+func optimizePrivacy(j *Journal) PrivacyResult {
+	result := PrivacyResult{}
+	if runtime.GOOS != "windows" {
+		result.Optimizations = append(result.Optimizations, "Privacy optimization is only available on Windows")
+		return result
+	}
+
+	elevated := admin.IsElevated()
+	for _, s := range privacySettings() {
+		if s.machine && !elevated && !j.dryRun {
+			result.Skipped = append(result.Skipped, s.desc+" (needs administrator rights)")
+			continue
+		}
+		before := len(j.Changes)
+		if err := setPrivacyValue(j, s); err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s (%v)", s.desc, err))
+			continue
+		}
+		if !j.dryRun {
+			result.Optimizations = append(result.Optimizations, s.desc)
+		} else if len(j.Changes) > before {
+			result.Optimizations = append(result.Optimizations, "Would: "+s.desc)
+		}
+	}
+	return result
+}
+
+// PrintPrivacyResult displays privacy optimization results.
+func PrintPrivacyResult(result PrivacyResult) {
+	for _, opt := range result.Optimizations {
+		fmt.Printf("    - %s\n", opt)
+	}
+	for _, s := range result.Skipped {
+		fmt.Printf("    Skipped: %s\n", s)
+	}
+	printRegistryChanges(result.DryRun, result.RegistryChanges)
+	printOperation(result.OperationID)
+	if len(result.RegistryChanges) > 0 && !result.DryRun {
+		printUndo(result.OperationID)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func setPrivacyValue(j *Journal, s privacySetting) error {
+	return fmt.Errorf("privacy settings are only available on Windows")
+}
+
+func windowsEdition() string {
+	return ""
+}
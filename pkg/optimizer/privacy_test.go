@@ -0,0 +1,19 @@
+package optimizer
+
+import "testing"
+
+func TestMinimumTelemetryLevel(t *testing.T) {
+	for edition, want := range map[string]uint32{
+		"Professional":     1,
+		"Core":             1,
+		"Enterprise":       0,
+		"EnterpriseS":      0,
+		"Education":        0,
+		"ServerDatacenter": 0,
+		"":                 1,
+	} {
+		if got := minimumTelemetryLevel(edition); got != want {
+			t.Errorf("minimumTelemetryLevel(%q) = %d, want %d", edition, got, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+func setPrivacyValue(j *Journal, s privacySetting) error {
+	root := registry.CURRENT_USER
+	if s.machine {
+		root = registry.LOCAL_MACHINE
+	}
+	return j.setDWordValue(root, s.path, s.name, s.value)
+}
+
+// windowsEdition returns the EditionID of the running Windows.
+func windowsEdition() string {
+	v := reglog.Lookup(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, "EditionID")
+	if v == nil {
+		return ""
+	}
+	return v.String
+}
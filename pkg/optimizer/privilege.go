@@ -0,0 +1,22 @@
+package optimizer
+
+import "syscleaner/pkg/admin"
+
+// PlanForPrivileges reports which of the selected optimizations will be
+// skipped when the process is not elevated, and returns the selection
+// that can still run.
+func PlanForPrivileges(startup, network, disk, elevated bool) (bool, bool, bool, []admin.Skip) {
+	if elevated {
+		return startup, network, disk, nil
+	}
+	var skips []admin.Skip
+	if network {
+		skips = append(skips, admin.Skip{Item: "Network optimization", Reason: "netsh TCP settings and the network throttling key require administrator rights"})
+		network = false
+	}
+	if disk {
+		skips = append(skips, admin.Skip{Item: "Disk optimization", Reason: "changing TRIM and scheduling defrag require administrator rights"})
+		disk = false
+	}
+	return startup, network, disk, skips
+}
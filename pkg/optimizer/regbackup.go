@@ -0,0 +1,224 @@
+package optimizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"syscleaner/pkg/admin"
+)
+
+// RegistryBackup is a .reg file of the registry keys one optimizer run
+// wrote to, exported, subkeys included, before the run changed them. It is
+// kept apart from the journal: importing it puts back every value the keys
+// held, but leaves values the run added to a key that already existed, so
+// Undo remains the exact way back.
+type RegistryBackup struct {
+	// Name is the file name, e.g. "20261015-150405-optimize-network.reg".
+	Name    string
+	Path    string
+	Created time.Time
+	Size    int64
+}
+
+// regBackupDir is where registry backups are kept, one file per run.
+var regBackupDir = func() string {
+	return backupPath("registry-backups")
+}
+
+// These are variables so tests can fake reg.exe.
+var (
+	exportRegKey  = exportRegKeyNative
+	importRegFile = importRegFileNative
+)
+
+// regFileHeader starts every .reg file reg.exe writes and imports.
+const regFileHeader = "Windows Registry Editor Version 5.00"
+
+// backupStamp is the timestamp registry backup names start with.
+const backupStamp = "20060102-150405"
+
+// regBackup is the .reg file a run's keys are exported to, created at the
+// run's first registry write.
+type regBackup struct {
+	path string
+	// keys are the keys already exported, lowercased.
+	keys map[string]bool
+	body strings.Builder
+}
+
+// backupKey exports the key at root\path to the run's registry backup
+// before the run first writes to it. A key that does not exist yet is
+// written as a deletion, so importing the backup removes the key the run
+// created. In a dry run it does nothing.
+func (j *Journal) backupKey(root, path string) error {
+	if j.dryRun {
+		return nil
+	}
+	key := root + `\` + path
+	if j.backup == nil {
+		j.backup = &regBackup{keys: make(map[string]bool)}
+	}
+	if j.backup.keys[strings.ToLower(key)] {
+		return nil
+	}
+	text, exists, err := exportRegKey(root, path)
+	if err != nil {
+		return fmt.Errorf("backing up %s: %w", key, err)
+	}
+	if !exists {
+		text = "[-" + key + "]\r\n"
+	}
+	if j.backup.path == "" {
+		if j.backup.path, err = createRegBackup(j.Started, j.ID); err != nil {
+			return fmt.Errorf("backing up %s: %w", key, err)
+		}
+		j.Backup = filepath.Base(j.backup.path)
+	}
+	j.backup.body.WriteString("\r\n" + text)
+	// The file is rewritten on every key so it always holds a complete,
+	// importable backup even if the run dies halfway.
+	data := encodeRegFile(regFileHeader + "\r\n" + j.backup.body.String())
+	if err := os.WriteFile(j.backup.path, data, 0644); err != nil {
+		return fmt.Errorf("backing up %s: %w", key, err)
+	}
+	j.backup.keys[strings.ToLower(key)] = true
+	return nil
+}
+
+// createRegBackup creates the empty backup file of the run started at
+// started with operation ID id, named after the time and the operation's
+// kind.
+func createRegBackup(started time.Time, id string) (string, error) {
+	dir := regBackupDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	base := started.Format(backupStamp) + "-" + operationKindOf(id)
+	name := base + ".reg"
+	for n := 2; ; n++ {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return f.Name(), nil
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+		name = fmt.Sprintf("%s-%d.reg", base, n)
+	}
+}
+
+// operationKindOf returns the kind of an operation ID as
+// logger.StartOperation makes them, "<kind>-<date>-<time>-<suffix>", or
+// the whole ID if it is not one.
+func operationKindOf(id string) string {
+	parts := strings.Split(id, "-")
+	if n := len(parts); n > 3 {
+		if _, err := time.Parse(backupStamp, parts[n-3]+"-"+parts[n-2]); err == nil {
+			return strings.Join(parts[:n-3], "-")
+		}
+	}
+	return id
+}
+
+// RegistryBackups returns the registry backups, newest first.
+func RegistryBackups() ([]RegistryBackup, error) {
+	entries, err := os.ReadDir(regBackupDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var out []RegistryBackup
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".reg") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		b := RegistryBackup{
+			Name:    e.Name(),
+			Path:    filepath.Join(regBackupDir(), e.Name()),
+			Created: info.ModTime(),
+			Size:    info.Size(),
+		}
+		if len(b.Name) > len(backupStamp) {
+			if t, err := time.ParseInLocation(backupStamp, b.Name[:len(backupStamp)], time.Local); err == nil {
+				b.Created = t
+			}
+		}
+		out = append(out, b)
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Created.After(out[b].Created) })
+	return out, nil
+}
+
+// RestoreRegistryBackup imports the registry backup named name, as
+// RegistryBackups lists it, putting the keys it holds back as they were
+// before that run.
+func RestoreRegistryBackup(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") ||
+		!strings.EqualFold(filepath.Ext(name), ".reg") {
+		return fmt.Errorf("invalid registry backup name %q", name)
+	}
+	if err := admin.RequireWriteAccess("restoring registry backup " + name); err != nil {
+		return err
+	}
+	path := filepath.Join(regBackupDir(), name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("no registry backup %q", name)
+	} else if err != nil {
+		return err
+	}
+	if err := importRegFile(path); err != nil {
+		return fmt.Errorf("importing %s: %w", name, err)
+	}
+	log.Printf("[SysCleaner] Restored registry backup %s", name)
+	return nil
+}
+
+// decodeRegFile returns the text of a .reg file, which reg.exe writes as
+// UTF-16 with a byte order mark.
+func decodeRegFile(data []byte) string {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xfe {
+		return strings.TrimPrefix(string(data), "\ufeff")
+	}
+	data = data[2:]
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// encodeRegFile encodes text as reg.exe does, so values that are not
+// ASCII survive an import.
+func encodeRegFile(text string) []byte {
+	units := utf16.Encode([]rune(text))
+	data := make([]byte, 2+len(units)*2)
+	data[0], data[1] = 0xff, 0xfe
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(data[2+i*2:], u)
+	}
+	return data
+}
+
+// regFileBody returns the keys of an exported .reg file, without its
+// header.
+func regFileBody(text string) (string, error) {
+	text = strings.TrimLeft(text, "\r\n")
+	if !strings.HasPrefix(text, regFileHeader) {
+		return "", errors.New("not a registry export")
+	}
+	return strings.TrimLeft(strings.TrimPrefix(text, regFileHeader), "\r\n"), nil
+}
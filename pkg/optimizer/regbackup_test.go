@@ -0,0 +1,95 @@
+package optimizer
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournal_BackupKey(t *testing.T) {
+	dir := t.TempDir()
+	origDir, origExport, origImport := regBackupDir, exportRegKey, importRegFile
+	t.Cleanup(func() { regBackupDir, exportRegKey, importRegFile = origDir, origExport, origImport })
+	regBackupDir = func() string { return dir }
+
+	var exported []string
+	exportRegKey = func(root, path string) (string, bool, error) {
+		exported = append(exported, path)
+		if path == `SOFTWARE\New` {
+			return "", false, nil
+		}
+		return "[" + root + `\` + path + "]\r\n\"Throttle\"=dword:0000000a\r\n", true, nil
+	}
+
+	preview := newJournal("optimize-network-preview-20261015-150405-ab12")
+	preview.dryRun = true
+	if err := preview.backupKey("HKEY_LOCAL_MACHINE", `SOFTWARE\Net`); err != nil || len(exported) != 0 {
+		t.Fatalf("dry-run backupKey exported %v, %v; want nothing", exported, err)
+	}
+
+	j := newJournal("optimize-network-20261015-150405-ab12")
+	j.Started = time.Date(2026, 10, 15, 15, 4, 5, 0, time.Local)
+	for _, path := range []string{`SOFTWARE\Net`, `software\net`, `SOFTWARE\New`} {
+		if err := j.backupKey("HKEY_LOCAL_MACHINE", path); err != nil {
+			t.Fatalf("backupKey(%s): %v", path, err)
+		}
+	}
+	if len(exported) != 2 {
+		t.Errorf("exported %v; each key should be exported once", exported)
+	}
+	if j.Backup != "20261015-150405-optimize-network.reg" {
+		t.Errorf("Backup = %q, want 20261015-150405-optimize-network.reg", j.Backup)
+	}
+
+	data, err := os.ReadFile(j.backup.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := decodeRegFile(data)
+	for _, want := range []string{regFileHeader + "\r\n", `[HKEY_LOCAL_MACHINE\SOFTWARE\Net]`, `[-HKEY_LOCAL_MACHINE\SOFTWARE\New]`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("backup is missing %q:\n%s", want, text)
+		}
+	}
+
+	backups, err := RegistryBackups()
+	if err != nil || len(backups) != 1 || !backups[0].Created.Equal(j.Started) {
+		t.Fatalf("RegistryBackups = %+v, %v; want the run's backup", backups, err)
+	}
+
+	var imported string
+	importRegFile = func(path string) error {
+		imported = path
+		return nil
+	}
+	if err := RestoreRegistryBackup(`..\` + j.Backup); err == nil {
+		t.Error("RestoreRegistryBackup accepted a path outside the backup folder")
+	}
+	if err := RestoreRegistryBackup(j.Backup); err != nil || imported != backups[0].Path {
+		t.Errorf("RestoreRegistryBackup imported %q, %v; want %s", imported, err, backups[0].Path)
+	}
+}
+
+func TestRegFileBody(t *testing.T) {
+	export := encodeRegFile(regFileHeader + "\r\n\r\n[HKEY_CURRENT_USER\\Software\\Test]\r\n\"Name\"=\"café\"\r\n")
+	body, err := regFileBody(decodeRegFile(export))
+	if err != nil || body != "[HKEY_CURRENT_USER\\Software\\Test]\r\n\"Name\"=\"café\"\r\n" {
+		t.Errorf("regFileBody = %q, %v", body, err)
+	}
+	if _, err := regFileBody("REGEDIT4\r\n"); err == nil {
+		t.Error("regFileBody accepted a file without the version 5 header")
+	}
+}
+
+func TestOperationKindOf(t *testing.T) {
+	for id, want := range map[string]string{
+		"optimize-network-20261015-150405-ab12":         "optimize-network",
+		"optimize-privacy-preview-20261015-150405-ab12": "optimize-privacy-preview",
+		"optimize-network-2":                            "optimize-network-2",
+	} {
+		if got := operationKindOf(id); got != want {
+			t.Errorf("operationKindOf(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/sysrestore"
+)
+
+// Service start types, as SetServiceStartType takes them.
+const (
+	StartAutomatic        = "automatic"
+	StartAutomaticDelayed = "automatic-delayed"
+	StartManual           = "manual"
+	StartDisabled         = "disabled"
+	// StartBoot and StartSystem are drivers' start types; they are never
+	// changed.
+	StartBoot   = "boot"
+	StartSystem = "system"
+)
+
+// ErrServiceNotInstalled is returned for a service that does not exist.
+var ErrServiceNotInstalled = errors.New("service not installed")
+
+// serviceCandidate is a service most PCs can do without.
+type serviceCandidate struct {
+	Name   string
+	Reason string
+	// Recommended is the start type to set: StartManual or StartDisabled.
+	Recommended string
+	// Applies, if set, reports whether the service is unneeded here.
+	Applies func() bool
+}
+
+// serviceCandidates is the curated list of services OptimizeServices
+// changes. Manual is preferred where Windows or an app may still start the
+// service on demand.
+var serviceCandidates = []serviceCandidate{
+	{"Fax", "Sends and receives faxes; unneeded without a fax modem", StartDisabled, nil},
+	{"DiagTrack", "Connected User Experiences and Telemetry", StartDisabled, nil},
+	{"dmwappushservice", "Routes WAP push messages for telemetry", StartDisabled, nil},
+	{"RetailDemo", "Retail store demo mode", StartDisabled, nil},
+	{"MapsBroker", "Downloaded Maps Manager; offline maps update on demand", StartManual, nil},
+	{"WMPNetworkSvc", "Windows Media Player library sharing", StartManual, nil},
+	{"RemoteRegistry", "Lets other computers edit this registry", StartDisabled, nil},
+	{"XblAuthManager", "Xbox Live sign-in; no Xbox app is installed", StartManual, noXboxFeatures},
+	{"XblGameSave", "Xbox Live cloud saves; no Xbox app is installed", StartManual, noXboxFeatures},
+	{"XboxNetApiSvc", "Xbox Live networking; no Xbox app is installed", StartManual, noXboxFeatures},
+	{"XboxGipSvc", "Xbox accessory management; no Xbox app is installed", StartManual, noXboxFeatures},
+	{"SysMain", "Superfetch preloading gains little on an SSD", StartManual, func() bool { return detectSSD() }},
+}
+
+func noXboxFeatures() bool {
+	return !xboxFeaturesUsed()
+}
+
+// These are variables so tests can fake the Service Control Manager and
+// the system checks.
+var (
+	queryStartType   = queryStartTypeNative
+	setStartType     = setStartTypeNative
+	xboxFeaturesUsed = xboxFeaturesUsedNative
+)
+
+// servicesBackupPath is where the start types services had before
+// SysCleaner changed them are kept.
+var servicesBackupPath = func() string {
+	return backupPath("services-backup.json")
+}
+
+// ServiceInfo is a candidate service and its state.
+type ServiceInfo struct {
+	Name   string
+	Reason string
+	// StartType is the current start type.
+	StartType   string
+	Recommended string
+	// Applies reports whether the service is unneeded on this PC. Xbox
+	// services are while no Xbox app is installed, SysMain is on an SSD.
+	Applies bool
+	// Original is the start type before SysCleaner changed it, or "".
+	Original string
+}
+
+// ServiceChange is a start type OptimizeServices changed, or in a dry run
+// would change.
+type ServiceChange struct {
+	Name string
+	From string
+	To   string
+}
+
+// ServicesResult holds services optimization results.
+type ServicesResult struct {
+	Changes []ServiceChange
+	// Errors describes the services that could not be changed.
+	Errors      []string
+	DryRun      bool
+	OperationID string
+}
+
+// startRank orders start types from least to most eager.
+func startRank(t string) int {
+	switch t {
+	case StartDisabled:
+		return 0
+	case StartManual:
+		return 1
+	case StartAutomatic, StartAutomaticDelayed:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// ListServices returns the installed candidate services.
+func ListServices() ([]ServiceInfo, error) {
+	backup, err := loadBackupMap[string](servicesBackupPath())
+	if err != nil {
+		return nil, err
+	}
+	var out []ServiceInfo
+	for _, c := range serviceCandidates {
+		current, err := queryStartType(c.Name)
+		if errors.Is(err, ErrServiceNotInstalled) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		out = append(out, ServiceInfo{
+			Name:        c.Name,
+			Reason:      c.Reason,
+			StartType:   current,
+			Recommended: c.Recommended,
+			Applies:     c.Applies == nil || c.Applies(),
+			Original:    backup[c.Name],
+		})
+	}
+	return out, nil
+}
+
+// OptimizeServices sets every candidate service that is unneeded on this
+// PC and starts more eagerly than recommended to its recommended start
+// type, after making a restore point (see sysrestore.Before). Original
+// start types are kept for RestoreServices.
+func OptimizeServices(opts OptimizeOptions) ServicesResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-services", dryRun))
+	defer op.End()
+	result := ServicesResult{DryRun: dryRun, OperationID: op.ID}
+
+	services, err := ListServices()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	if runtime.GOOS == "windows" && !dryRun {
+		sysrestore.Before("services optimization")
+	}
+	for _, s := range services {
+		if !s.Applies || startRank(s.StartType) <= startRank(s.Recommended) {
+			continue
+		}
+		change := ServiceChange{Name: s.Name, From: s.StartType, To: s.Recommended}
+		if !dryRun {
+			if err := SetServiceStartType(s.Name, s.Recommended); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	return result
+}
+
+// SetServiceStartType sets a service's start type, StartManual,
+// StartDisabled, StartAutomatic or StartAutomaticDelayed, first keeping
+// the one it had for RestoreServices. A service changed twice keeps its
+// first start type.
+func SetServiceStartType(name, startType string) error {
+	switch startType {
+	case StartAutomatic, StartAutomaticDelayed, StartManual, StartDisabled:
+	default:
+		return fmt.Errorf("invalid start type %q (valid: %s, %s, %s, %s)", startType,
+			StartAutomatic, StartAutomaticDelayed, StartManual, StartDisabled)
+	}
+	if err := admin.RequireWriteAccess("changing the start type of service " + name); err != nil {
+		return err
+	}
+	current, err := queryStartType(name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if current == StartBoot || current == StartSystem {
+		return fmt.Errorf("%s is a driver and is left alone", name)
+	}
+	if current == startType {
+		return nil
+	}
+
+	backup, err := loadBackupMap[string](servicesBackupPath())
+	if err != nil {
+		return err
+	}
+	if _, ok := backup[name]; !ok {
+		backup[name] = current
+		if err := saveBackup(servicesBackupPath(), backup); err != nil {
+			return fmt.Errorf("saving the original start type of %s: %w", name, err)
+		}
+	}
+	if err := setStartType(name, startType); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	log.Printf("[SysCleaner] Set service %s to %s (was %s)", name, startType, current)
+	return nil
+}
+
+// RestoreServices puts back the start type of every service SysCleaner
+// changed. It returns how many were restored; those that could not be are
+// reported in the error and kept for another try.
+func RestoreServices() (int, error) {
+	if err := admin.RequireWriteAccess("restoring service start types"); err != nil {
+		return 0, err
+	}
+	backup, err := loadBackupMap[string](servicesBackupPath())
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(backup))
+	for name := range backup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	restored := 0
+	var errs []error
+	for _, name := range names {
+		if err := setStartType(name, backup[name]); err != nil && !errors.Is(err, ErrServiceNotInstalled) {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		log.Printf("[SysCleaner] Restored service %s to %s", name, backup[name])
+		delete(backup, name)
+		restored++
+	}
+	if err := saveBackup(servicesBackupPath(), backup); err != nil {
+		errs = append(errs, err)
+	}
+	return restored, errors.Join(errs...)
+}
+
+// PrintServicesResult displays services optimization results.
+func PrintServicesResult(result ServicesResult) {
+	verb := "Changed"
+	if result.DryRun {
+		verb = "Would change"
+	}
+	fmt.Printf("  %s %d service(s)\n", verb, len(result.Changes))
+	for _, c := range result.Changes {
+		fmt.Printf("    %s: %s -> %s\n", c.Name, c.From, c.To)
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", strings.TrimSpace(e))
+	}
+	printOperation(result.OperationID)
+	if len(result.Changes) > 0 && !result.DryRun {
+		fmt.Println("  Restore with: syscleaner services --restore")
+	}
+}
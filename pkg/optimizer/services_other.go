@@ -0,0 +1,17 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func queryStartTypeNative(name string) (string, error) {
+	return "", fmt.Errorf("managing services is only available on Windows")
+}
+
+func setStartTypeNative(name, startType string) error {
+	return fmt.Errorf("managing services is only available on Windows")
+}
+
+func xboxFeaturesUsedNative() bool {
+	return false
+}
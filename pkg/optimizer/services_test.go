@@ -0,0 +1,103 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOptimizeServices_Restore(t *testing.T) {
+	dir := t.TempDir()
+	origPath, origQuery, origSet := servicesBackupPath, queryStartType, setStartType
+	origXbox, origSSD := xboxFeaturesUsed, detectSSD
+	t.Cleanup(func() {
+		servicesBackupPath, queryStartType, setStartType = origPath, origQuery, origSet
+		xboxFeaturesUsed, detectSSD = origXbox, origSSD
+	})
+
+	services := map[string]string{
+		"Fax":            StartAutomatic,
+		"MapsBroker":     StartAutomaticDelayed,
+		"WMPNetworkSvc":  StartManual,
+		"XblAuthManager": StartManual,
+		"XboxGipSvc":     StartAutomatic,
+		"SysMain":        StartAutomatic,
+	}
+	servicesBackupPath = func() string { return dir + "/services-backup.json" }
+	queryStartType = func(name string) (string, error) {
+		if st, ok := services[name]; ok {
+			return st, nil
+		}
+		return "", ErrServiceNotInstalled
+	}
+	setStartType = func(name, st string) error {
+		services[name] = st
+		return nil
+	}
+	xboxFeaturesUsed = func() bool { return false }
+	detectSSD = func() bool { return false }
+
+	preview := OptimizeServices(OptimizeOptions{DryRun: true})
+	if len(preview.Changes) != 3 || services["Fax"] != StartAutomatic {
+		t.Fatalf("dry run = %+v, services = %v; want 3 changes and nothing changed", preview, services)
+	}
+
+	result := OptimizeServices(OptimizeOptions{})
+	if len(result.Errors) > 0 {
+		t.Fatalf("OptimizeServices errors: %v", result.Errors)
+	}
+	want := map[string]string{
+		"Fax":            StartDisabled,
+		"MapsBroker":     StartManual,
+		"WMPNetworkSvc":  StartManual,
+		"XblAuthManager": StartManual,
+		"XboxGipSvc":     StartManual,
+		"SysMain":        StartAutomatic, // not on an SSD
+	}
+	for name, st := range want {
+		if services[name] != st {
+			t.Errorf("%s = %s, want %s", name, services[name], st)
+		}
+	}
+
+	// A second change keeps the first original start type.
+	if err := SetServiceStartType("Fax", StartManual); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetServiceStartType("Fax", "off"); err == nil {
+		t.Error("an unknown start type should be rejected")
+	}
+	list, err := ListServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range list {
+		if s.Name == "Fax" && s.Original != StartAutomatic {
+			t.Errorf("Fax original = %q, want automatic", s.Original)
+		}
+		if s.Name == "SysMain" && s.Applies {
+			t.Error("SysMain should not apply without an SSD")
+		}
+	}
+
+	fail := true
+	setStartType = func(name, st string) error {
+		if name == "MapsBroker" && fail {
+			return errors.New("access denied")
+		}
+		services[name] = st
+		return nil
+	}
+	if n, err := RestoreServices(); err == nil || n != 2 {
+		t.Fatalf("RestoreServices with a failure = %d, %v; want 2 and an error", n, err)
+	}
+	fail = false
+	if n, err := RestoreServices(); err != nil || n != 1 {
+		t.Fatalf("RestoreServices retry = %d, %v; want 1", n, err)
+	}
+	if services["Fax"] != StartAutomatic || services["MapsBroker"] != StartAutomaticDelayed || services["XboxGipSvc"] != StartAutomatic {
+		t.Errorf("after restore: %v", services)
+	}
+	if n, err := RestoreServices(); err != nil || n != 0 {
+		t.Errorf("RestoreServices with nothing recorded = %d, %v", n, err)
+	}
+}
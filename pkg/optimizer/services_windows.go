@@ -0,0 +1,118 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// openService opens a service through the Service Control Manager.
+func openService(name string) (*mgr.Mgr, *mgr.Service, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to SCM: %w", err)
+	}
+	s, err := m.OpenService(name)
+	if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+		m.Disconnect()
+		return nil, nil, ErrServiceNotInstalled
+	} else if err != nil {
+		m.Disconnect()
+		return nil, nil, fmt.Errorf("failed to open service %s: %w", name, err)
+	}
+	return m, s, nil
+}
+
+func queryStartTypeNative(name string) (string, error) {
+	m, s, err := openService(name)
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+	defer s.Close()
+
+	cfg, err := s.Config()
+	if err != nil {
+		return "", err
+	}
+	switch cfg.StartType {
+	case mgr.StartAutomatic:
+		if cfg.DelayedAutoStart {
+			return StartAutomaticDelayed, nil
+		}
+		return StartAutomatic, nil
+	case mgr.StartManual:
+		return StartManual, nil
+	case mgr.StartDisabled:
+		return StartDisabled, nil
+	case windows.SERVICE_BOOT_START:
+		return StartBoot, nil
+	default:
+		return StartSystem, nil
+	}
+}
+
+func setStartTypeNative(name, startType string) error {
+	m, s, err := openService(name)
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	defer s.Close()
+
+	cfg, err := s.Config()
+	if err != nil {
+		return err
+	}
+	cfg.DelayedAutoStart = false
+	switch startType {
+	case StartAutomatic:
+		cfg.StartType = mgr.StartAutomatic
+	case StartAutomaticDelayed:
+		cfg.StartType = mgr.StartAutomatic
+		cfg.DelayedAutoStart = true
+	case StartManual:
+		cfg.StartType = mgr.StartManual
+	case StartDisabled:
+		cfg.StartType = mgr.StartDisabled
+	default:
+		return fmt.Errorf("cannot set start type %q", startType)
+	}
+	return s.UpdateConfig(cfg)
+}
+
+// xboxPackages are the package name prefixes of the Xbox apps. The Game
+// Bar is left out as it comes with Windows.
+var xboxPackages = []string{"microsoft.gamingapp_", "microsoft.xboxapp_"}
+
+// xboxFeaturesUsedNative reports whether an Xbox app is installed for the
+// current user.
+func xboxFeaturesUsedNative() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER,
+		`Software\Classes\Local Settings\Software\Microsoft\Windows\CurrentVersion\AppModel\Repository\Packages`,
+		registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		// Without the list, assume they are used rather than break them.
+		return true
+	}
+	defer key.Close()
+	names, err := key.ReadSubKeyNames(-1)
+	if err != nil {
+		return true
+	}
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		for _, prefix := range xboxPackages {
+			if strings.HasPrefix(lower, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package optimizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"syscleaner/pkg/logger"
+)
+
+// AutoDisableStartup names the startup entries OptimizeStartup disables.
+// Any other entry can be disabled with DisableStartup.
+var AutoDisableStartup = []string{
+	"OneDrive", "Skype", "Spotify", "Discord",
+	"Steam", "EpicGamesLauncher", "AdobeUpdater",
+	"iTunes", "iTunesHelper",
+}
+
+// ErrStartupNotFound is returned when a startup entry is gone.
+var ErrStartupNotFound = errors.New("startup entry not found")
+
+// Where a startup entry comes from, as StartupProgram.Source.
+const (
+	// StartupRun is a value under a Run key.
+	StartupRun = "run"
+	// StartupRunOnce is a value under a RunOnce key, run at the next
+	// logon and then deleted by Windows.
+	StartupRunOnce = "runonce"
+	// StartupFolder is a file in a user's or the common Startup folder.
+	StartupFolder = "folder"
+	// StartupTask is a Task Scheduler task started at logon.
+	StartupTask = "task"
+	// StartupApp is a packaged (Store) app's startup task.
+	StartupApp = "app"
+)
+
+// Task Manager keeps a startup entry's state in a StartupApproved value of
+// the same name, leaving the Run value alone: an odd first byte means
+// disabled, and bytes 4-11 hold when it was disabled as a FILETIME.
+const (
+	approvedEnabledFlag  = 0x02
+	approvedDisabledFlag = 0x03
+	approvedDataLen      = 12
+
+	// filetimeUnixEpoch is the Unix epoch in 100ns intervals since 1601.
+	filetimeUnixEpoch = 116444736000000000
+)
+
+// approvedData returns the StartupApproved data for an entry enabled or
+// disabled at t.
+func approvedData(enabled bool, t time.Time) []byte {
+	data := make([]byte, approvedDataLen)
+	if enabled {
+		data[0] = approvedEnabledFlag
+		return data
+	}
+	data[0] = approvedDisabledFlag
+	binary.LittleEndian.PutUint64(data[4:], uint64(t.UnixNano()/100+filetimeUnixEpoch))
+	return data
+}
+
+// approvedEnabled reports whether StartupApproved data marks an entry
+// enabled. An entry with no data is enabled.
+func approvedEnabled(data []byte) bool {
+	return len(data) == 0 || data[0]&1 == 0
+}
+
+// approvedTime returns when StartupApproved data says its entry was
+// disabled, or the zero time.
+func approvedTime(data []byte) time.Time {
+	if len(data) < approvedDataLen || approvedEnabled(data) {
+		return time.Time{}
+	}
+	ft := binary.LittleEndian.Uint64(data[4:])
+	if ft <= filetimeUnixEpoch {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ft-filetimeUnixEpoch)*100)
+}
+
+// autoDisabled reports whether name is in AutoDisableStartup. A Startup
+// folder entry is matched without its extension.
+func autoDisabled(name string) bool {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	for _, n := range AutoDisableStartup {
+		if strings.EqualFold(n, name) || strings.EqualFold(n, base) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListStartupPrograms returns the programs started at logon, enabled or
+// not: the Run and RunOnce keys of the current user and the machine, the
+// user's and the common Startup folders, Task Scheduler tasks with a logon
+// trigger outside \Microsoft\, and packaged apps' startup tasks. Each is
+// rated from Windows' boot measurements (see rateStartupImpact).
+func ListStartupPrograms() ([]StartupProgram, error) {
+	programs, err := listStartupPrograms()
+	if err != nil {
+		return nil, err
+	}
+	delays, err := bootDelays()
+	if err != nil {
+		log.Printf("[SysCleaner] Could not read boot performance events: %v", err)
+	}
+	rateStartupImpact(programs, delays)
+	return programs, nil
+}
+
+// startupFolderPrograms returns the files in a Startup folder. A missing
+// folder has none.
+func startupFolderPrograms(dir string) []StartupProgram {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []StartupProgram
+	for _, e := range entries {
+		if e.IsDir() || strings.EqualFold(e.Name(), "desktop.ini") {
+			continue
+		}
+		out = append(out, StartupProgram{
+			Name:     e.Name(),
+			Path:     filepath.Join(dir, e.Name()),
+			Location: dir,
+			Source:   StartupFolder,
+		})
+	}
+	return out
+}
+
+// DisableStartup stops p starting with Windows. Run and Startup folder
+// entries and app startup tasks are disabled the way Task Manager does,
+// keeping the entry so EnableStartup can turn it back on, and logon tasks
+// are disabled in Task Scheduler. A RunOnce value is deleted, as it cannot
+// be disabled. Registry changes are journaled like an optimizer run (see
+// Undo).
+func DisableStartup(p StartupProgram) error {
+	return setStartupEnabled(p, false)
+}
+
+// EnableStartup lets p start with Windows again.
+func EnableStartup(p StartupProgram) error {
+	return setStartupEnabled(p, true)
+}
+
+func setStartupEnabled(p StartupProgram, enabled bool) error {
+	kind := "startup-disable"
+	if enabled {
+		kind = "startup-enable"
+	}
+	op := logger.StartOperation(kind)
+	defer op.End()
+	return setStartupApproved(newJournal(op.ID), p, enabled)
+}
+
+func optimizeStartup(j *Journal) StartupResult {
+	result := StartupResult{}
+	if runtime.GOOS != "windows" {
+		return result
+	}
+	programs, err := ListStartupPrograms()
+	if err != nil {
+		log.Printf("[SysCleaner] Could not list startup programs: %v", err)
+		return result
+	}
+	for _, p := range programs {
+		if autoDisabled(p.Name) && !p.Disabled {
+			if err := setStartupApproved(j, p, false); err == nil {
+				p.Disabled = true
+				result.Disabled++
+			}
+		}
+		result.Programs = append(result.Programs, p)
+	}
+	return result
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func listStartupPrograms() ([]StartupProgram, error) {
+	return nil, fmt.Errorf("managing startup programs is only available on Windows")
+}
+
+func setStartupApproved(j *Journal, p StartupProgram, enabled bool) error {
+	return fmt.Errorf("managing startup programs is only available on Windows")
+}
@@ -0,0 +1,79 @@
+package optimizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApprovedData(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	disabled := approvedData(false, at)
+	if len(disabled) != approvedDataLen || approvedEnabled(disabled) {
+		t.Fatalf("disabled data %x should mark the entry disabled", disabled)
+	}
+	if got := approvedTime(disabled); !got.Equal(at) {
+		t.Errorf("approvedTime = %v, want %v", got, at)
+	}
+
+	enabled := approvedData(true, at)
+	if !approvedEnabled(enabled) || !approvedTime(enabled).IsZero() {
+		t.Errorf("enabled data %x should mark the entry enabled with no time", enabled)
+	}
+	if !approvedEnabled(nil) {
+		t.Error("an entry with no StartupApproved data should be enabled")
+	}
+	// Task Manager also writes 0x06 for enabled and 0x07 for disabled.
+	if !approvedEnabled([]byte{0x06}) || approvedEnabled([]byte{0x07}) {
+		t.Error("the low bit of the first byte should decide the state")
+	}
+}
+
+func TestAutoDisabled(t *testing.T) {
+	if !autoDisabled("spotify") || !autoDisabled("Spotify.lnk") {
+		t.Error("Spotify should be auto-disabled, with or without an extension")
+	}
+	if autoDisabled("SecurityHealth") {
+		t.Error("SecurityHealth should not be auto-disabled")
+	}
+}
+
+func TestStartupFolderPrograms(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Spotify.lnk", "desktop.ini", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := startupFolderPrograms(dir)
+	if len(got) != 2 {
+		t.Fatalf("startupFolderPrograms = %+v, want Spotify.lnk and notes.txt", got)
+	}
+	if got[0].Name != "Spotify.lnk" || got[0].Source != StartupFolder || got[0].Location != dir {
+		t.Errorf("Spotify.lnk = %+v", got[0])
+	}
+	if got := startupFolderPrograms(filepath.Join(dir, "missing")); got != nil {
+		t.Errorf("a missing folder should have no entries, got %v", got)
+	}
+}
+
+func TestParseScheduledTasks(t *testing.T) {
+	data := []byte(`[{"path":"\\Vendor\\","name":"Updater Logon","state":"Disabled","command":"C:\\Vendor\\update.exe /logon"},` +
+		`{"path":"\\","name":"Helper","state":"Ready","command":""}]` + "\r\n")
+	tasks, err := parseScheduledTasks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].FullName() != `\Vendor\Updater Logon` || tasks[0].enabled() || !tasks[1].enabled() {
+		t.Errorf("parseScheduledTasks = %+v", tasks)
+	}
+	if tasks, err := parseScheduledTasks([]byte("\r\n")); err != nil || tasks != nil {
+		t.Errorf("empty output = %v, %v; want no tasks", tasks, err)
+	}
+}
@@ -0,0 +1,241 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/reglog"
+)
+
+// runLocation is a Run or RunOnce key and the StartupApproved key Explorer
+// checks before starting its entries ("" for RunOnce, which has none).
+type runLocation struct {
+	source   string
+	root     registry.Key
+	path     string
+	approved string
+}
+
+const approvedBase = `SOFTWARE\Microsoft\Windows\CurrentVersion\Explorer\StartupApproved\`
+
+var runLocations = []runLocation{
+	{StartupRun, registry.CURRENT_USER, `SOFTWARE\Microsoft\Windows\CurrentVersion\Run`, approvedBase + "Run"},
+	{StartupRun, registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows\CurrentVersion\Run`, approvedBase + "Run"},
+	{StartupRun, registry.LOCAL_MACHINE, `SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Run`, approvedBase + "Run32"},
+	{StartupRunOnce, registry.CURRENT_USER, `SOFTWARE\Microsoft\Windows\CurrentVersion\RunOnce`, ""},
+	{StartupRunOnce, registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows\CurrentVersion\RunOnce`, ""},
+}
+
+func (l runLocation) String() string {
+	return reglog.RootName(l.root) + `\` + l.path
+}
+
+// folderLocation is a Startup folder and the root of its StartupApproved
+// key.
+type folderLocation struct {
+	dir  string
+	root registry.Key
+}
+
+func startupFolders() []folderLocation {
+	return []folderLocation{
+		{filepath.Join(os.Getenv("APPDATA"), `Microsoft\Windows\Start Menu\Programs\Startup`), registry.CURRENT_USER},
+		{filepath.Join(os.Getenv("ProgramData"), `Microsoft\Windows\Start Menu\Programs\StartUp`), registry.LOCAL_MACHINE},
+	}
+}
+
+// appStartupKey holds packaged apps' startup tasks, one subkey per package
+// family and task, each with a State value.
+const appStartupKey = `Software\Classes\Local Settings\Software\Microsoft\Windows\CurrentVersion\AppModel\SystemAppData`
+
+// Packaged app startup task states (Windows.ApplicationModel.StartupTaskState).
+const (
+	appTaskDisabled         = 0
+	appTaskDisabledByUser   = 1
+	appTaskEnabled          = 2
+	appTaskDisabledByPolicy = 3
+	appTaskEnabledByPolicy  = 4
+)
+
+// logonTaskFilter selects third-party tasks with a logon trigger.
+const logonTaskFilter = `| Where-Object { $_.TaskPath -notlike '\Microsoft\*' -and ` +
+	`@($_.Triggers | Where-Object { $_.CimClass.CimClassName -eq 'MSFT_TaskLogonTrigger' }).Count -gt 0 }`
+
+func listStartupPrograms() ([]StartupProgram, error) {
+	programs := runPrograms()
+	for _, f := range startupFolders() {
+		for _, p := range startupFolderPrograms(f.dir) {
+			if v := reglog.Lookup(f.root, approvedBase+"StartupFolder", p.Name); v != nil && v.Kind == reglog.KindBinary {
+				p.Disabled = !approvedEnabled(v.Binary)
+				p.DisabledAt = approvedTime(v.Binary)
+			}
+			programs = append(programs, p)
+		}
+	}
+	if tasks, err := listScheduledTasks(logonTaskFilter); err != nil {
+		log.Printf("[SysCleaner] Could not list logon tasks: %v", err)
+	} else {
+		for _, t := range tasks {
+			programs = append(programs, StartupProgram{
+				Name:     t.Name,
+				Path:     t.Command,
+				Location: t.Path,
+				Source:   StartupTask,
+				Disabled: !t.enabled(),
+			})
+		}
+	}
+	return append(programs, appStartupPrograms()...), nil
+}
+
+func runPrograms() []StartupProgram {
+	var programs []StartupProgram
+	for _, loc := range runLocations {
+		key, err := registry.OpenKey(loc.root, loc.path, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		names, err := key.ReadValueNames(-1)
+		if err != nil {
+			key.Close()
+			continue
+		}
+		for _, name := range names {
+			val, _, err := key.GetStringValue(name)
+			if err != nil {
+				continue
+			}
+			p := StartupProgram{Name: name, Path: val, Location: loc.String(), Source: loc.source}
+			if loc.approved != "" {
+				if v := reglog.Lookup(loc.root, loc.approved, name); v != nil && v.Kind == reglog.KindBinary {
+					p.Disabled = !approvedEnabled(v.Binary)
+					p.DisabledAt = approvedTime(v.Binary)
+				}
+			}
+			programs = append(programs, p)
+		}
+		key.Close()
+	}
+	return programs
+}
+
+func appStartupPrograms() []StartupProgram {
+	key, err := registry.OpenKey(registry.CURRENT_USER, appStartupKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+	packages, err := key.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil
+	}
+	var programs []StartupProgram
+	for _, pkg := range packages {
+		pkey, err := registry.OpenKey(key, pkg, registry.ENUMERATE_SUB_KEYS)
+		if err != nil {
+			continue
+		}
+		tasks, _ := pkey.ReadSubKeyNames(-1)
+		pkey.Close()
+		for _, task := range tasks {
+			path := appStartupKey + `\` + pkg + `\` + task
+			v := reglog.Lookup(registry.CURRENT_USER, path, "State")
+			if v == nil || v.Kind != reglog.KindDWord {
+				continue
+			}
+			state := v.Integer
+			programs = append(programs, StartupProgram{
+				Name:     task,
+				Path:     pkg,
+				Location: reglog.RootName(registry.CURRENT_USER) + `\` + path,
+				Source:   StartupApp,
+				Disabled: state != appTaskEnabled && state != appTaskEnabledByPolicy,
+			})
+		}
+	}
+	return programs
+}
+
+// setStartupApproved enables or disables p where its source keeps that
+// state. An entry already in that state is left alone.
+func setStartupApproved(j *Journal, p StartupProgram, enabled bool) error {
+	switch p.Source {
+	case StartupFolder:
+		for _, f := range startupFolders() {
+			if !strings.EqualFold(f.dir, p.Location) {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(f.dir, p.Name)); err != nil {
+				return fmt.Errorf("%w: %s in %s", ErrStartupNotFound, p.Name, p.Location)
+			}
+			return setApproved(j, f.root, approvedBase+"StartupFolder", p.Name, enabled)
+		}
+	case StartupTask:
+		// Task Scheduler keeps the state; a preview has nothing to journal.
+		if j.dryRun {
+			return nil
+		}
+		return setTaskEnabled(p.Location+p.Name, enabled)
+	case StartupApp:
+		path, ok := strings.CutPrefix(p.Location, reglog.RootName(registry.CURRENT_USER)+`\`)
+		if !ok {
+			break
+		}
+		v := reglog.Lookup(registry.CURRENT_USER, path, "State")
+		if v == nil || v.Kind != reglog.KindDWord {
+			return fmt.Errorf("%w: %s in %s", ErrStartupNotFound, p.Name, p.Path)
+		}
+		switch v.Integer {
+		case appTaskDisabledByPolicy, appTaskEnabledByPolicy:
+			return fmt.Errorf("%s is set by policy", p.Name)
+		}
+		on := v.Integer == appTaskEnabled
+		if on == enabled {
+			return nil
+		}
+		state := uint32(appTaskDisabledByUser)
+		if enabled {
+			state = appTaskEnabled
+		}
+		return j.setDWordValue(registry.CURRENT_USER, path, "State", state)
+	default:
+		for _, loc := range runLocations {
+			if !strings.EqualFold(loc.String(), p.Location) {
+				continue
+			}
+			if reglog.Lookup(loc.root, loc.path, p.Name) == nil {
+				return fmt.Errorf("%w: %s in %s", ErrStartupNotFound, p.Name, p.Location)
+			}
+			if loc.approved != "" {
+				return setApproved(j, loc.root, loc.approved, p.Name, enabled)
+			}
+			if enabled {
+				return nil
+			}
+			err := j.deleteValue(loc.root, loc.path, p.Name)
+			if errors.Is(err, registry.ErrNotExist) {
+				return fmt.Errorf("%w: %s in %s", ErrStartupNotFound, p.Name, p.Location)
+			}
+			return err
+		}
+	}
+	return fmt.Errorf("unknown startup location %q", p.Location)
+}
+
+// setApproved writes an entry's StartupApproved value.
+func setApproved(j *Journal, root registry.Key, path, name string, enabled bool) error {
+	old := reglog.Lookup(root, path, name)
+	if old == nil && enabled || old != nil && old.Kind == reglog.KindBinary && approvedEnabled(old.Binary) == enabled {
+		return nil
+	}
+	return j.setBinaryValue(root, path, name, approvedData(enabled, time.Now()))
+}
@@ -0,0 +1,298 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/logger"
+)
+
+// Task debloat levels, from least to most reaching. Each disables the
+// tasks of the ones before it and more.
+const (
+	// TaskLevelSafe disables telemetry upload tasks nothing depends on.
+	TaskLevelSafe = "safe"
+	// TaskLevelRecommended also disables feedback, error reporting and
+	// other background data collection.
+	TaskLevelRecommended = "recommended"
+	// TaskLevelAggressive also disables maintenance tasks such as the
+	// scheduled defrag and system assessments; run them by hand instead.
+	TaskLevelAggressive = "aggressive"
+)
+
+// TaskLevels lists the task debloat levels in order.
+var TaskLevels = []string{TaskLevelSafe, TaskLevelRecommended, TaskLevelAggressive}
+
+// debloatTask is a scheduled task DebloatTasks may disable.
+type debloatTask struct {
+	Name   string
+	Level  string
+	Reason string
+}
+
+var debloatTasks = []debloatTask{
+	{`\Microsoft\Windows\Customer Experience Improvement Program\Consolidator`, TaskLevelSafe, "Uploads Customer Experience Improvement Program data"},
+	{`\Microsoft\Windows\Customer Experience Improvement Program\UsbCeip`, TaskLevelSafe, "Collects USB usage data for CEIP"},
+	{`\Microsoft\Windows\Customer Experience Improvement Program\KernelCeipTask`, TaskLevelSafe, "Collects kernel usage data for CEIP"},
+	{`\Microsoft\Windows\Application Experience\Microsoft Compatibility Appraiser`, TaskLevelSafe, "Scans programs for compatibility telemetry"},
+	{`\Microsoft\Windows\Application Experience\ProgramDataUpdater`, TaskLevelSafe, "Collects program telemetry for CEIP"},
+	{`\Microsoft\Windows\Autochk\Proxy`, TaskLevelSafe, "Uploads disk check data for CEIP"},
+	{`\Microsoft\Windows\DiskDiagnostic\Microsoft-Windows-DiskDiagnosticDataCollector`, TaskLevelSafe, "Uploads disk diagnostics for CEIP"},
+	{`\Microsoft\Windows\Application Experience\StartupAppTask`, TaskLevelRecommended, "Rescans startup entries in the background"},
+	{`\Microsoft\Windows\Feedback\Siuf\DmClient`, TaskLevelRecommended, "Feedback hub data collection"},
+	{`\Microsoft\Windows\Feedback\Siuf\DmClientOnScenarioDownload`, TaskLevelRecommended, "Feedback hub data collection"},
+	{`\Microsoft\Windows\Windows Error Reporting\QueueReporting`, TaskLevelRecommended, "Sends queued error reports"},
+	{`\Microsoft\Windows\CloudExperienceHost\CreateObjectTask`, TaskLevelRecommended, "Cloud experience setup prompts"},
+	{`\Microsoft\Windows\Maps\MapsUpdateTask`, TaskLevelRecommended, "Updates offline maps"},
+	{`\Microsoft\Windows\Defrag\ScheduledDefrag`, TaskLevelAggressive, "Scheduled drive optimization; run it by hand or use SysCleaner's"},
+	{`\Microsoft\Windows\Maintenance\WinSAT`, TaskLevelAggressive, "System performance assessment"},
+	{`\Microsoft\Windows\DiskFootprint\Diagnostics`, TaskLevelAggressive, "Storage usage diagnostics"},
+	{`\Microsoft\Windows\Power Efficiency Diagnostics\AnalyzeSystem`, TaskLevelAggressive, "Power efficiency analysis"},
+	{`\Microsoft\Windows\Diagnosis\Scheduled`, TaskLevelAggressive, "Scheduled troubleshooting and diagnostics"},
+}
+
+// These are variables so tests can fake Task Scheduler.
+var (
+	queryTasks = listScheduledTasks
+	changeTask = setTaskEnabled
+)
+
+// debloatTaskFilter selects Windows' own tasks, where every debloat task
+// lives.
+const debloatTaskFilter = `-TaskPath '\Microsoft\Windows\*'`
+
+// tasksBackupPath is where the tasks SysCleaner disabled are listed.
+var tasksBackupPath = func() string {
+	return backupPath("tasks-backup.json")
+}
+
+// ValidateTaskLevel reports whether level is a known task debloat level.
+func ValidateTaskLevel(level string) error {
+	if taskLevelRank(level) < 0 {
+		return fmt.Errorf("unknown task level %q (valid: %s)", level, strings.Join(TaskLevels, ", "))
+	}
+	return nil
+}
+
+func taskLevelRank(level string) int {
+	for i, l := range TaskLevels {
+		if strings.EqualFold(l, level) {
+			return i
+		}
+	}
+	return -1
+}
+
+// TaskInfo is a debloat task installed on this PC.
+type TaskInfo struct {
+	// Name is the task's folder and name, e.g.
+	// \Microsoft\Windows\Autochk\Proxy.
+	Name    string
+	Level   string
+	Reason  string
+	Enabled bool
+	// DisabledBySysCleaner reports whether RestoreTasks will re-enable it.
+	DisabledBySysCleaner bool
+}
+
+// TasksResult holds task debloat results.
+type TasksResult struct {
+	// Disabled lists the tasks disabled, or in a dry run the ones that
+	// would be.
+	Disabled    []string
+	Errors      []string
+	DryRun      bool
+	OperationID string
+}
+
+// ListDebloatTasks returns the debloat tasks installed on this PC, in
+// level order.
+func ListDebloatTasks() ([]TaskInfo, error) {
+	tasks, err := queryTasks(debloatTaskFilter)
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]scheduledTask, len(tasks))
+	for _, t := range tasks {
+		installed[strings.ToLower(t.FullName())] = t
+	}
+	disabled, err := loadTasksBackup()
+	if err != nil {
+		return nil, err
+	}
+	var out []TaskInfo
+	for _, d := range debloatTasks {
+		t, ok := installed[strings.ToLower(d.Name)]
+		if !ok {
+			continue
+		}
+		out = append(out, TaskInfo{
+			Name:                 d.Name,
+			Level:                d.Level,
+			Reason:               d.Reason,
+			Enabled:              t.enabled(),
+			DisabledBySysCleaner: disabled[strings.ToLower(d.Name)],
+		})
+	}
+	return out, nil
+}
+
+// FindDebloatTask returns the debloat task called name, given as its full
+// name or just its last part (e.g. "Consolidator").
+func FindDebloatTask(name string) (string, error) {
+	for _, d := range debloatTasks {
+		if strings.EqualFold(d.Name, name) || strings.EqualFold(d.Name[strings.LastIndex(d.Name, `\`)+1:], name) {
+			return d.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a task SysCleaner manages", name)
+}
+
+// DebloatTasks disables every enabled debloat task at level or below.
+func DebloatTasks(level string, opts OptimizeOptions) TasksResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("debloat-tasks", dryRun))
+	defer op.End()
+	result := TasksResult{DryRun: dryRun, OperationID: op.ID}
+
+	if err := ValidateTaskLevel(level); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	tasks, err := ListDebloatTasks()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	max := taskLevelRank(level)
+	for _, t := range tasks {
+		if !t.Enabled || taskLevelRank(t.Level) > max {
+			continue
+		}
+		if !dryRun {
+			if err := DisableTask(t.Name); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+		}
+		result.Disabled = append(result.Disabled, t.Name)
+	}
+	return result
+}
+
+// DisableTask disables a debloat task and records it for RestoreTasks.
+func DisableTask(name string) error {
+	if err := admin.RequireWriteAccess("disabling scheduled task " + name); err != nil {
+		return err
+	}
+	disabled, err := loadTasksBackup()
+	if err != nil {
+		return err
+	}
+	disabled[strings.ToLower(name)] = true
+	// Recorded first, so a task disabled by a run that dies is still restored.
+	if err := saveTasksBackup(disabled); err != nil {
+		return err
+	}
+	if err := changeTask(name, false); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	log.Printf("[SysCleaner] Disabled scheduled task %s", name)
+	return nil
+}
+
+// EnableTask re-enables a debloat task.
+func EnableTask(name string) error {
+	if err := admin.RequireWriteAccess("enabling scheduled task " + name); err != nil {
+		return err
+	}
+	if err := changeTask(name, true); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	disabled, err := loadTasksBackup()
+	if err != nil {
+		return err
+	}
+	delete(disabled, strings.ToLower(name))
+	return saveTasksBackup(disabled)
+}
+
+// RestoreTasks re-enables every task SysCleaner disabled. It returns how
+// many were; those that could not be are reported in the error and kept
+// for another try.
+func RestoreTasks() (int, error) {
+	if err := admin.RequireWriteAccess("re-enabling scheduled tasks"); err != nil {
+		return 0, err
+	}
+	disabled, err := loadTasksBackup()
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(disabled))
+	for name := range disabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	restored := 0
+	var errs []error
+	for _, name := range names {
+		// Task names are not case-sensitive, so the recorded lower case works.
+		if err := changeTask(name, true); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		delete(disabled, name)
+		restored++
+	}
+	if err := saveTasksBackup(disabled); err != nil {
+		errs = append(errs, err)
+	}
+	return restored, errors.Join(errs...)
+}
+
+// loadTasksBackup returns the tasks SysCleaner disabled, by lower-case
+// full name.
+func loadTasksBackup() (map[string]bool, error) {
+	names, _, err := loadBackup[[]string](tasksBackupPath())
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool, len(names))
+	for _, n := range names {
+		disabled[n] = true
+	}
+	return disabled, nil
+}
+
+func saveTasksBackup(disabled map[string]bool) error {
+	names := make([]string, 0, len(disabled))
+	for n := range disabled {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return saveBackup(tasksBackupPath(), names)
+}
+
+// PrintTasksResult displays task debloat results.
+func PrintTasksResult(result TasksResult) {
+	verb := "Disabled"
+	if result.DryRun {
+		verb = "Would disable"
+	}
+	fmt.Printf("  %s %d scheduled task(s)\n", verb, len(result.Disabled))
+	for _, name := range result.Disabled {
+		fmt.Printf("    %s\n", name)
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", e)
+	}
+	printOperation(result.OperationID)
+	if len(result.Disabled) > 0 && !result.DryRun {
+		fmt.Println("  Re-enable with: syscleaner tasks --restore")
+	}
+}
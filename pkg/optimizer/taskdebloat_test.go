@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDebloatTasks_Restore(t *testing.T) {
+	dir := t.TempDir()
+	origPath, origQuery, origChange := tasksBackupPath, queryTasks, changeTask
+	t.Cleanup(func() { tasksBackupPath, queryTasks, changeTask = origPath, origQuery, origChange })
+
+	tasks := []scheduledTask{
+		{Path: `\Microsoft\Windows\Customer Experience Improvement Program\`, Name: "Consolidator", State: "Ready"},
+		{Path: `\Microsoft\Windows\Autochk\`, Name: "Proxy", State: "Disabled"}, // the user's own choice
+		{Path: `\Microsoft\Windows\Windows Error Reporting\`, Name: "QueueReporting", State: "Ready"},
+		{Path: `\Microsoft\Windows\Defrag\`, Name: "ScheduledDefrag", State: "Ready"},
+		{Path: `\Microsoft\Windows\Other\`, Name: "Unlisted", State: "Ready"},
+	}
+	tasksBackupPath = func() string { return dir + "/tasks-backup.json" }
+	queryTasks = func(where string) ([]scheduledTask, error) { return tasks, nil }
+	var fail string
+	changeTask = func(name string, enable bool) error {
+		if strings.EqualFold(name, fail) {
+			return errors.New("access denied")
+		}
+		for i := range tasks {
+			if strings.EqualFold(tasks[i].FullName(), name) {
+				tasks[i].State = "Disabled"
+				if enable {
+					tasks[i].State = "Ready"
+				}
+			}
+		}
+		return nil
+	}
+
+	if r := DebloatTasks("extreme", OptimizeOptions{}); len(r.Errors) == 0 {
+		t.Error("an unknown level should be reported")
+	}
+	preview := DebloatTasks(TaskLevelRecommended, OptimizeOptions{DryRun: true})
+	if len(preview.Disabled) != 2 || tasks[0].State != "Ready" {
+		t.Fatalf("dry run = %+v; want 2 tasks and nothing changed", preview)
+	}
+
+	r := DebloatTasks(TaskLevelRecommended, OptimizeOptions{})
+	if len(r.Errors) > 0 || len(r.Disabled) != 2 {
+		t.Fatalf("DebloatTasks = %+v", r)
+	}
+	if tasks[0].State != "Disabled" || tasks[2].State != "Disabled" || tasks[3].State != "Ready" {
+		t.Fatalf("after recommended: %+v", tasks)
+	}
+
+	name, err := FindDebloatTask("scheduleddefrag")
+	if err != nil || name != `\Microsoft\Windows\Defrag\ScheduledDefrag` {
+		t.Fatalf("FindDebloatTask = %q, %v", name, err)
+	}
+	if err := DisableTask(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnableTask(name); err != nil || tasks[3].State != "Ready" {
+		t.Fatalf("EnableTask = %v, state %s", err, tasks[3].State)
+	}
+
+	list, err := ListDebloatTasks()
+	if err != nil || len(list) != 4 {
+		t.Fatalf("ListDebloatTasks = %+v, %v; want the 4 listed tasks", list, err)
+	}
+	if !list[0].DisabledBySysCleaner || list[1].DisabledBySysCleaner || list[3].DisabledBySysCleaner {
+		t.Errorf("DisabledBySysCleaner wrong: %+v", list)
+	}
+
+	fail = `\Microsoft\Windows\Windows Error Reporting\QueueReporting`
+	if n, err := RestoreTasks(); err == nil || n != 1 {
+		t.Fatalf("RestoreTasks with a failure = %d, %v; want 1 and an error", n, err)
+	}
+	fail = ""
+	if n, err := RestoreTasks(); err != nil || n != 1 {
+		t.Fatalf("RestoreTasks retry = %d, %v; want 1", n, err)
+	}
+	if tasks[0].State != "Ready" || tasks[1].State != "Disabled" || tasks[2].State != "Ready" {
+		t.Errorf("after restore: %+v; the user's disabled task should stay disabled", tasks)
+	}
+}
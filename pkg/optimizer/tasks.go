@@ -0,0 +1,49 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// scheduledTask is a Task Scheduler task, as listed by listScheduledTasks.
+type scheduledTask struct {
+	// Path is the task's folder, e.g. \Microsoft\Windows\Autochk\.
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// State is Ready, Running, Queued, Disabled or Unknown.
+	State   string `json:"state"`
+	Command string `json:"command"`
+}
+
+// FullName returns the task's folder and name, as schtasks /tn takes it.
+func (t scheduledTask) FullName() string {
+	return t.Path + t.Name
+}
+
+func (t scheduledTask) enabled() bool {
+	return !strings.EqualFold(t.State, "Disabled")
+}
+
+// scheduledTaskScript lists the tasks Get-ScheduledTask returns with where
+// after it, parameters or a pipeline filter (e.g. "-TaskPath '\Foo\*'" or
+// "| Where-Object State -eq 'Ready'"), as a JSON array.
+func scheduledTaskScript(where string) string {
+	return "ConvertTo-Json -Compress -InputObject @(Get-ScheduledTask " + where +
+		" | ForEach-Object { [pscustomobject]@{ path = $_.TaskPath; name = $_.TaskName; state = [string]$_.State;" +
+		" command = (@($_.Actions | ForEach-Object { ($_.Execute + ' ' + $_.Arguments).Trim() }) -join '; ') } })"
+}
+
+// parseScheduledTasks parses the output of scheduledTaskScript.
+func parseScheduledTasks(data []byte) ([]scheduledTask, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var tasks []scheduledTask
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("parsing scheduled tasks: %w", err)
+	}
+	return tasks, nil
+}
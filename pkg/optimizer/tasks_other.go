@@ -0,0 +1,13 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func listScheduledTasks(where string) ([]scheduledTask, error) {
+	return nil, fmt.Errorf("scheduled tasks are only available on Windows")
+}
+
+func setTaskEnabled(fullName string, enable bool) error {
+	return fmt.Errorf("scheduled tasks are only available on Windows")
+}
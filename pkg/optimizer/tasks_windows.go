@@ -0,0 +1,35 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// listScheduledTasks lists the tasks matching the PowerShell filter where
+// (see scheduledTaskScript).
+func listScheduledTasks(where string) ([]scheduledTask, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", scheduledTaskScript(where))
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing scheduled tasks: %w", err)
+	}
+	return parseScheduledTasks(out)
+}
+
+// setTaskEnabled enables or disables the task with the given full name.
+func setTaskEnabled(fullName string, enable bool) error {
+	flag := "/disable"
+	if enable {
+		flag = "/enable"
+	}
+	cmd := exec.Command("schtasks", "/change", "/tn", fullName, flag)
+	cmd.SysProcAttr = getSysProcAttr()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("schtasks: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
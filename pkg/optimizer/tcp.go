@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Global TCP settings OptimizeNetwork journals, as "netsh int tcp set
+// global" names them.
+const (
+	// TCPAutoTuning is the receive window auto-tuning level.
+	TCPAutoTuning = "autotuninglevel"
+	// TCPECN is Explicit Congestion Notification.
+	TCPECN = "ecncapability"
+)
+
+// tcpGlobals are the global TCP settings OptimizeNetwork sets.
+var tcpGlobals = []struct {
+	setting string
+	value   string
+	desc    string
+}{
+	{TCPAutoTuning, "normal", "Set TCP receive window auto-tuning to normal"},
+	// Some home routers drop or mangle ECN-marked packets.
+	{TCPECN, "disabled", "Disable ECN"},
+}
+
+// These are variables so tests can fake netsh and the TCP settings.
+var (
+	queryTCPGlobals = queryTCPGlobalsNative
+	setTCPSetting   = setTCPSettingNative
+)
+
+// tcpGlobalsScript prints the Internet TCP setting template, which the
+// netsh global settings change, as JSON keyed by netsh parameter.
+const tcpGlobalsScript = `$t = Get-NetTCPSetting -SettingName Internet; ` +
+	`ConvertTo-Json -Compress -InputObject ([pscustomobject]@{ autotuninglevel = [string]$t.AutoTuningLevelLocal; ecncapability = [string]$t.EcnCapability })`
+
+// parseTCPGlobals parses the output of tcpGlobalsScript into the values
+// netsh takes, e.g. "Normal" into "normal". Settings that could not be
+// read are left out.
+func parseTCPGlobals(data []byte) (map[string]string, error) {
+	var raw map[string]string
+	if err := json.Unmarshal(bytes.TrimSpace(data), &raw); err != nil {
+		return nil, fmt.Errorf("parsing TCP settings: %w", err)
+	}
+	globals := make(map[string]string, len(raw))
+	for setting, val := range raw {
+		if val != "" {
+			globals[setting] = strings.ToLower(val)
+		}
+	}
+	return globals, nil
+}
+
+// optimizeTCPGlobals sets the settings in tcpGlobals through j, so Undo
+// can put back the values they had. A setting whose value could not be
+// read is left alone, as it could not be undone.
+func optimizeTCPGlobals(j *Journal, result *NetworkResult) {
+	current, err := queryTCPGlobals()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return
+	}
+	for _, g := range tcpGlobals {
+		old, ok := current[g.setting]
+		if !ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("could not read TCP setting %s; left alone", g.setting))
+			continue
+		}
+		changed, err := j.setTCPGlobal(g.setting, old, g.value)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", g.desc, err))
+		case changed && j.dryRun:
+			result.Optimizations = append(result.Optimizations,
+				fmt.Sprintf("Would: %s (netsh int tcp set global %s=%s, now %s)", g.desc, g.setting, g.value, old))
+		case changed:
+			result.Optimizations = append(result.Optimizations, g.desc)
+			result.LatencyReduction += 2
+		}
+	}
+}
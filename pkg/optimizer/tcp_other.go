@@ -0,0 +1,13 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func queryTCPGlobalsNative() (map[string]string, error) {
+	return nil, fmt.Errorf("TCP settings are only available on Windows")
+}
+
+func setTCPSettingNative(setting, val string) error {
+	return fmt.Errorf("TCP settings are only available on Windows")
+}
@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTCPGlobals(t *testing.T) {
+	globals, err := parseTCPGlobals([]byte(`{"autotuninglevel":"HighlyRestricted","ecncapability":""}` + "\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if globals[TCPAutoTuning] != "highlyrestricted" {
+		t.Errorf("auto-tuning = %q, want highlyrestricted", globals[TCPAutoTuning])
+	}
+	if _, ok := globals[TCPECN]; ok {
+		t.Error("an unreadable setting should be left out")
+	}
+	if _, err := parseTCPGlobals([]byte("Get-NetTCPSetting : not found")); err == nil {
+		t.Error("invalid output should fail to parse")
+	}
+}
+
+func TestOptimizeTCPGlobals_Undo(t *testing.T) {
+	dir := t.TempDir()
+	origDir, origQuery, origSet := journalDir, queryTCPGlobals, setTCPSetting
+	t.Cleanup(func() { journalDir, queryTCPGlobals, setTCPSetting = origDir, origQuery, origSet })
+
+	globals := map[string]string{TCPAutoTuning: "disabled", TCPECN: "disabled"}
+	journalDir = func() string { return dir }
+	queryTCPGlobals = func() (map[string]string, error) {
+		out := make(map[string]string)
+		for k, v := range globals {
+			out[k] = v
+		}
+		return out, nil
+	}
+	fail := false
+	setTCPSetting = func(setting, val string) error {
+		if fail {
+			return errors.New("netsh failed")
+		}
+		globals[setting] = val
+		return nil
+	}
+
+	preview := newJournal("optimize-network-preview-1")
+	preview.dryRun = true
+	var result NetworkResult
+	optimizeTCPGlobals(preview, &result)
+	if len(preview.TCPChanges) != 1 || globals[TCPAutoTuning] != "disabled" || len(result.Optimizations) != 1 {
+		t.Fatalf("dry run journaled %v, optimizations %v, globals %v; want auto-tuning only and nothing changed",
+			preview.TCPChanges, result.Optimizations, globals)
+	}
+
+	j := newJournal("optimize-network-2")
+	result = NetworkResult{}
+	optimizeTCPGlobals(j, &result)
+	if globals[TCPAutoTuning] != "normal" || len(j.TCPChanges) != 1 || len(result.Errors) > 0 {
+		t.Fatalf("globals = %v, journal = %v, errors = %v", globals, j.TCPChanges, result.Errors)
+	}
+
+	last, err := LastJournal()
+	if err != nil || last.ID != j.ID || last.Count() != 1 {
+		t.Fatalf("LastJournal = %+v, %v; want the run with one TCP change", last, err)
+	}
+	fail = true
+	if n, err := Undo(j.ID); err == nil || n != 0 {
+		t.Fatalf("Undo with netsh failing = %d, %v; want 0 and an error", n, err)
+	}
+	fail = false
+	if n, err := Undo(j.ID); err != nil || n != 1 {
+		t.Fatalf("Undo = %d, %v; want 1", n, err)
+	}
+	if globals[TCPAutoTuning] != "disabled" {
+		t.Errorf("auto-tuning after undo = %q, want disabled", globals[TCPAutoTuning])
+	}
+
+	delete(globals, TCPECN)
+	result = NetworkResult{}
+	optimizeTCPGlobals(newJournal("optimize-network-3"), &result)
+	if len(result.Errors) != 1 {
+		t.Errorf("errors = %v; an unreadable setting should be reported and left alone", result.Errors)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// queryTCPGlobalsNative reads the global TCP settings with
+// Get-NetTCPSetting, whose output, unlike "netsh int tcp show global", is
+// not translated.
+func queryTCPGlobalsNative() (map[string]string, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", tcpGlobalsScript)
+	cmd.SysProcAttr = getSysProcAttr()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("reading TCP settings: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return parseTCPGlobals(out)
+}
+
+func setTCPSettingNative(setting, val string) error {
+	cmd := exec.Command("netsh", "int", "tcp", "set", "global", setting+"="+val)
+	cmd.SysProcAttr = getSysProcAttr()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package optimizer
+
+import (
+	"fmt"
+	"runtime"
+
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/reglog"
+)
+
+// visualSetting is a current-user registry value OptimizeVisualEffects
+// sets.
+type visualSetting struct {
+	desc  string
+	path  string
+	name  string
+	value reglog.Value
+}
+
+const (
+	desktopKey       = `Control Panel\Desktop`
+	explorerAdvanced = `Software\Microsoft\Windows\CurrentVersion\Explorer\Advanced`
+)
+
+// Performance Options' "Adjust for best performance" and "Custom" choices.
+const (
+	visualFXBestPerformance = 2
+	visualFXCustom          = 3
+)
+
+// bestPerformanceMask is the UserPreferencesMask "Adjust for best
+// performance" writes: menu, tooltip and window animations, shadows and
+// smooth scrolling off.
+var bestPerformanceMask = []byte{0x90, 0x12, 0x03, 0x80, 0x10, 0x00, 0x00, 0x00}
+
+func dwordValue(v uint32) reglog.Value {
+	return reglog.Value{Kind: reglog.KindDWord, Integer: uint64(v)}
+}
+func stringValue(s string) reglog.Value { return reglog.Value{Kind: reglog.KindString, String: s} }
+
+// visualSettings returns what "Adjust for best performance" changes, or
+// with keepFontSmoothing the same less the font smoothing, which Windows
+// then calls a custom setting.
+func visualSettings(keepFontSmoothing bool) []visualSetting {
+	fx := uint32(visualFXBestPerformance)
+	if keepFontSmoothing {
+		fx = visualFXCustom
+	}
+	settings := []visualSetting{
+		{"Set Performance Options to best performance", `Software\Microsoft\Windows\CurrentVersion\Explorer\VisualEffects`,
+			"VisualFXSetting", dwordValue(fx)},
+		{"Turn off menu, tooltip and window animations and shadows", desktopKey,
+			"UserPreferencesMask", reglog.Value{Kind: reglog.KindBinary, Binary: bestPerformanceMask}},
+		{"Show only outlines while dragging windows", desktopKey, "DragFullWindows", stringValue("0")},
+		{"Turn off minimize and maximize animations", desktopKey + `\WindowMetrics`, "MinAnimate", stringValue("0")},
+		{"Turn off taskbar animations", explorerAdvanced, "TaskbarAnimations", dwordValue(0)},
+		{"Turn off translucent selection", explorerAdvanced, "ListviewAlphaSelect", dwordValue(0)},
+		{"Turn off icon label shadows", explorerAdvanced, "ListviewShadow", dwordValue(0)},
+		{"Turn off Peek", `Software\Microsoft\Windows\DWM`, "EnableAeroPeek", dwordValue(0)},
+		{"Turn off transparency", `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, "EnableTransparency", dwordValue(0)},
+	}
+	if !keepFontSmoothing {
+		settings = append(settings, visualSetting{"Turn off font smoothing", desktopKey, "FontSmoothing", stringValue("0")})
+	}
+	return settings
+}
+
+// VisualEffectsResult holds visual effects optimization results.
+type VisualEffectsResult struct {
+	// Optimizations describes the settings changed. In a dry run each
+	// starts with "Would: ".
+	Optimizations   []string
+	Errors          []string
+	DryRun          bool
+	OperationID     string
+	RegistryChanges []reglog.Change
+}
+
+// OptimizeVisualEffects switches Windows' visual effects to "Adjust for
+// best performance", keeping font smoothing when opts.KeepFontSmoothing
+// is set. The values are journaled, so Undo puts them back; undone
+// effects show fully after signing out and back in.
+func OptimizeVisualEffects(opts OptimizeOptions) VisualEffectsResult {
+	dryRun := opts.dryRun()
+	op := logger.StartOperation(operationKind("optimize-visual", dryRun))
+	defer op.End()
+	j := newJournal(op.ID)
+	j.dryRun = dryRun
+	result := VisualEffectsResult{DryRun: dryRun, OperationID: op.ID}
+
+	if runtime.GOOS != "windows" {
+		result.Optimizations = append(result.Optimizations, "Visual effects optimization is only available on Windows")
+		return result
+	}
+	for _, s := range visualSettings(opts.KeepFontSmoothing) {
+		before := len(j.Changes)
+		if err := setVisualValue(j, s); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", s.desc, err))
+			continue
+		}
+		if !dryRun {
+			result.Optimizations = append(result.Optimizations, s.desc)
+		} else if len(j.Changes) > before {
+			result.Optimizations = append(result.Optimizations, "Would: "+s.desc)
+		}
+	}
+	if !dryRun {
+		applyVisualEffects(opts.KeepFontSmoothing)
+	}
+	result.RegistryChanges = j.Changes
+	return result
+}
+
+// PrintVisualEffectsResult displays visual effects optimization results.
+func PrintVisualEffectsResult(result VisualEffectsResult) {
+	for _, opt := range result.Optimizations {
+		fmt.Printf("    - %s\n", opt)
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("    Error: %s\n", e)
+	}
+	printRegistryChanges(result.DryRun, result.RegistryChanges)
+	printOperation(result.OperationID)
+	if len(result.RegistryChanges) > 0 && !result.DryRun {
+		printUndo(result.OperationID)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package optimizer
+
+import "fmt"
+
+func setVisualValue(j *Journal, s visualSetting) error {
+	return fmt.Errorf("visual effects are only available on Windows")
+}
+
+func applyVisualEffects(keepFontSmoothing bool) {}
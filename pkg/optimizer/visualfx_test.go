@@ -0,0 +1,33 @@
+package optimizer
+
+import "testing"
+
+func TestVisualSettings(t *testing.T) {
+	find := func(settings []visualSetting, name string) *visualSetting {
+		for i := range settings {
+			if settings[i].name == name {
+				return &settings[i]
+			}
+		}
+		return nil
+	}
+
+	best := visualSettings(false)
+	if s := find(best, "VisualFXSetting"); s == nil || s.value.Integer != visualFXBestPerformance {
+		t.Errorf("best performance VisualFXSetting = %+v", s)
+	}
+	if s := find(best, "FontSmoothing"); s == nil || s.value.String != "0" {
+		t.Errorf("best performance should turn off font smoothing, got %+v", s)
+	}
+
+	custom := visualSettings(true)
+	if s := find(custom, "VisualFXSetting"); s == nil || s.value.Integer != visualFXCustom {
+		t.Errorf("keeping font smoothing should be a custom setting, got %+v", s)
+	}
+	if find(custom, "FontSmoothing") != nil {
+		t.Error("keeping font smoothing should leave FontSmoothing alone")
+	}
+	if len(custom) != len(best)-1 {
+		t.Errorf("custom has %d settings, want %d", len(custom), len(best)-1)
+	}
+}
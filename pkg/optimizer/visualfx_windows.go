@@ -0,0 +1,59 @@
+//go:build windows
+
+package optimizer
+
+import (
+	"log"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	user32                   = windows.NewLazySystemDLL("user32.dll")
+	procSystemParametersInfo = user32.NewProc("SystemParametersInfoW")
+)
+
+const (
+	spiSetDragFullWindows     = 0x0025
+	spiSetAnimation           = 0x0049
+	spiSetFontSmoothing       = 0x004B
+	spiSetUIEffects           = 0x103F
+	spiSetClientAreaAnimation = 0x1043
+
+	// spifSendChange broadcasts a change without writing it to the
+	// profile; the journaled registry values already hold it.
+	spifSendChange = 0x2
+)
+
+func setVisualValue(j *Journal, s visualSetting) error {
+	return j.setValue(registry.CURRENT_USER, s.path, s.name, s.value)
+}
+
+// spiCall is a SystemParametersInfo action and its parameters.
+type spiCall struct {
+	action uint32
+	param  uintptr
+	pv     unsafe.Pointer
+}
+
+// applyVisualEffects tells running programs about the changed effects, so
+// they take effect without signing out.
+func applyVisualEffects(keepFontSmoothing bool) {
+	animation := struct{ Size, MinAnimate uint32 }{Size: 8}
+	calls := []spiCall{
+		{spiSetUIEffects, 0, nil},
+		{spiSetClientAreaAnimation, 0, nil},
+		{spiSetDragFullWindows, 0, nil},
+		{spiSetAnimation, uintptr(animation.Size), unsafe.Pointer(&animation)},
+	}
+	if !keepFontSmoothing {
+		calls = append(calls, spiCall{spiSetFontSmoothing, 0, nil})
+	}
+	for _, c := range calls {
+		if r, _, err := procSystemParametersInfo.Call(uintptr(c.action), c.param, uintptr(c.pv), spifSendChange); r == 0 {
+			log.Printf("[SysCleaner] SystemParametersInfo(0x%X) failed: %v", c.action, err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package power lists, creates and activates Windows power plans and reads
+// and writes their settings. Plans are named by GUID, lowercased, in the
+// form powercfg takes.
+package power
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"syscleaner/pkg/admin"
+)
+
+// Built-in power plans.
+const (
+	Balanced        = "381b4222-f694-41f0-9685-ff5bb260df2e"
+	HighPerformance = "8c5e7fda-e8bf-4a96-9a85-a6e23a8c635c"
+	PowerSaver      = "a1841308-3541-4fab-bc81-f71556f20b4a"
+	// UltimatePerformance is hidden on most editions until it is
+	// duplicated.
+	UltimatePerformance = "e9a42b02-d5df-448d-aa00-03f14749eb61"
+)
+
+// GUIDs SysCleaner gives its copies of the built-in plans when Windows
+// hides them (Ultimate Performance everywhere but Workstation editions,
+// High Performance on Modern Standby laptops), so a later run finds the
+// copy instead of making another.
+const (
+	highPerformanceCopy = "4f7a2c1e-5b3d-4e8a-9c6f-1d2e3a4b5c6d"
+	ultimateCopy        = "7e1d9b3a-2c4f-4a6e-8b5d-3f9c1e7a2b4d"
+)
+
+// Setting is a power plan setting and the subgroup it belongs to.
+type Setting struct {
+	Subgroup string
+	GUID     string
+}
+
+// Settings SysCleaner tunes.
+var (
+	// ProcessorMinState is "Minimum processor state", in percent.
+	ProcessorMinState = Setting{processorSubgroup, "893dee8e-2bef-41e0-89c6-b55d0929964c"}
+	// ProcessorMaxState is "Maximum processor state", in percent.
+	ProcessorMaxState = Setting{processorSubgroup, "bc5038f7-23e0-4960-96da-33abaf5935ec"}
+	// USBSelectiveSuspend is "USB selective suspend setting": 1 enabled,
+	// 0 disabled.
+	USBSelectiveSuspend = Setting{"2a737441-1930-4402-8d77-b2bebba308a3", "48e6b7a6-50f5-4782-a5d4-53bb8f07e226"}
+)
+
+const processorSubgroup = "54533251-82be-4824-96c1-47eeb4bba3fd"
+
+// ErrUnknownPlan is returned for a plan that is not installed.
+var ErrUnknownPlan = errors.New("unknown power plan")
+
+// Plan is an installed power plan.
+type Plan struct {
+	GUID   string
+	Name   string
+	Active bool
+}
+
+// These are variables so tests can fake powercfg and the power API.
+var (
+	listPlans       = listPlansNative
+	duplicatePlan   = duplicatePlanNative
+	activePlan      = activePlanNative
+	setActivePlan   = setActivePlanNative
+	readValueIndex  = readValueIndexNative
+	writeValueIndex = writeValueIndexNative
+)
+
+var guidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// planLine matches a plan in powercfg /list output. Only the GUID, the
+// name in parentheses and the trailing star are relied on, as the rest is
+// translated.
+var planLine = regexp.MustCompile(`(` + guidPattern.String() + `)\s*(?:\((.*)\))?\s*(\*)?\s*$`)
+
+// parsePlans parses powercfg /list output.
+func parsePlans(out string) []Plan {
+	var plans []Plan
+	for _, line := range strings.Split(out, "\n") {
+		m := planLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		plans = append(plans, Plan{GUID: strings.ToLower(m[1]), Name: m[2], Active: m[3] != ""})
+	}
+	return plans
+}
+
+// List returns the installed power plans.
+func List() ([]Plan, error) {
+	out, err := listPlans()
+	if err != nil {
+		return nil, fmt.Errorf("listing power plans: %w", err)
+	}
+	return parsePlans(out), nil
+}
+
+// Active returns the GUID of the active power plan.
+func Active() (string, error) {
+	return activePlan()
+}
+
+// Find returns the installed plan whose GUID or name is name, ignoring
+// case.
+func Find(name string) (Plan, error) {
+	plans, err := List()
+	if err != nil {
+		return Plan{}, err
+	}
+	for _, p := range plans {
+		if strings.EqualFold(p.GUID, name) || strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return Plan{}, fmt.Errorf("%w: %s", ErrUnknownPlan, name)
+}
+
+// Activate makes plan the active power plan.
+func Activate(plan string) error {
+	plan = strings.ToLower(plan)
+	if len(plan) != 36 || !guidPattern.MatchString(plan) {
+		return fmt.Errorf("%w: %s", ErrUnknownPlan, plan)
+	}
+	if err := admin.RequireWriteAccess("activating power plan " + plan); err != nil {
+		return err
+	}
+	if err := setActivePlan(plan); err != nil {
+		return fmt.Errorf("activating power plan %s: %w", plan, err)
+	}
+	log.Printf("[SysCleaner] Activated power plan %s", plan)
+	return nil
+}
+
+// EnsureHighPerformance returns the GUID of the High Performance plan, or
+// of a copy of it when Windows hides it.
+func EnsureHighPerformance() (string, error) {
+	return available(HighPerformance, highPerformanceCopy)
+}
+
+// EnsureUltimate returns the GUID of the Ultimate Performance plan, or of
+// a copy of it, creating the copy when Windows hides the plan.
+func EnsureUltimate() (string, error) {
+	return available(UltimatePerformance, ultimateCopy)
+}
+
+// available returns guid if it is listed, or else its copy under
+// copyGUID, creating the copy when there is none yet.
+func available(guid, copyGUID string) (string, error) {
+	plans, err := List()
+	if err != nil {
+		return "", err
+	}
+	listed := make(map[string]bool, len(plans))
+	for _, p := range plans {
+		listed[p.GUID] = true
+	}
+	switch {
+	case listed[guid]:
+		return guid, nil
+	case listed[copyGUID]:
+		return copyGUID, nil
+	}
+	if err := admin.RequireWriteAccess("creating power plan " + copyGUID + " from hidden plan " + guid); err != nil {
+		return "", err
+	}
+	if err := duplicatePlan(guid, copyGUID); err != nil {
+		return "", fmt.Errorf("duplicating power plan %s: %w", guid, err)
+	}
+	log.Printf("[SysCleaner] Created power plan %s from hidden plan %s", copyGUID, guid)
+	return copyGUID, nil
+}
+
+// Read returns a setting's values in plan, on AC power and on battery.
+func Read(plan string, s Setting) (ac, dc uint32, err error) {
+	return readValueIndex(strings.ToLower(plan), s.Subgroup, s.GUID)
+}
+
+// Write sets a setting's values in plan, on AC power and on battery. The
+// change takes effect at once if plan is active.
+func Write(plan string, s Setting, ac, dc uint32) error {
+	if err := admin.RequireWriteAccess("changing power plan " + plan + " setting " + s.GUID); err != nil {
+		return err
+	}
+	return writeValueIndex(strings.ToLower(plan), s.Subgroup, s.GUID, ac, dc)
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package power
+
+import "fmt"
+
+var errNoPowerPlans = fmt.Errorf("power plans are only available on Windows")
+
+func listPlansNative() (string, error) {
+	return "", errNoPowerPlans
+}
+
+func duplicatePlanNative(src, dst string) error {
+	return errNoPowerPlans
+}
+
+func activePlanNative() (string, error) {
+	return "", errNoPowerPlans
+}
+
+func setActivePlanNative(plan string) error {
+	return errNoPowerPlans
+}
+
+func readValueIndexNative(plan, subgroup, setting string) (uint32, uint32, error) {
+	return 0, 0, errNoPowerPlans
+}
+
+func writeValueIndexNative(plan, subgroup, setting string, ac, dc uint32) error {
+	return errNoPowerPlans
+}
@@ -0,0 +1,115 @@
+package power
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"syscleaner/pkg/admin"
+)
+
+const powercfgList = `
+Existing Power Schemes (* Active)
+-----------------------------------
+Power Scheme GUID: 381b4222-f694-41f0-9685-ff5bb260df2e  (Balanced) *
+Power Scheme GUID: 8C5E7FDA-E8BF-4A96-9A85-A6E23A8C635C  (High performance)
+`
+
+func fakePowercfg(t *testing.T, list string) *[]string {
+	t.Helper()
+	origList, origDup := listPlans, duplicatePlan
+	t.Cleanup(func() { listPlans, duplicatePlan = origList, origDup })
+
+	var duplicated []string
+	listPlans = func() (string, error) { return list, nil }
+	duplicatePlan = func(src, dst string) error {
+		duplicated = append(duplicated, src)
+		list += "Power Scheme GUID: " + dst + "  (Copy)\n"
+		return nil
+	}
+	return &duplicated
+}
+
+func TestParsePlans(t *testing.T) {
+	got := parsePlans(powercfgList)
+	want := []Plan{
+		{GUID: Balanced, Name: "Balanced", Active: true},
+		{GUID: HighPerformance, Name: "High performance"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePlans = %+v, want %+v", got, want)
+	}
+}
+
+func TestEnsureUltimate(t *testing.T) {
+	duplicated := fakePowercfg(t, powercfgList)
+
+	if guid, err := EnsureHighPerformance(); err != nil || guid != HighPerformance {
+		t.Errorf("EnsureHighPerformance = %q, %v; want the listed plan", guid, err)
+	}
+	// Ultimate Performance is hidden: it is copied once and the copy reused.
+	for i := 0; i < 2; i++ {
+		if guid, err := EnsureUltimate(); err != nil || guid != ultimateCopy {
+			t.Errorf("EnsureUltimate = %q, %v; want the copy %s", guid, err, ultimateCopy)
+		}
+	}
+	if len(*duplicated) != 1 || (*duplicated)[0] != UltimatePerformance {
+		t.Errorf("duplicated %v, want Ultimate Performance once", *duplicated)
+	}
+}
+
+func TestFind(t *testing.T) {
+	fakePowercfg(t, powercfgList)
+
+	for _, name := range []string{"high PERFORMANCE", "8c5e7fda-e8bf-4a96-9a85-a6e23a8c635c"} {
+		if p, err := Find(name); err != nil || p.GUID != HighPerformance {
+			t.Errorf("Find(%q) = %+v, %v", name, p, err)
+		}
+	}
+	if _, err := Find("Turbo"); !errors.Is(err, ErrUnknownPlan) {
+		t.Errorf("Find(\"Turbo\") = %v, want ErrUnknownPlan", err)
+	}
+}
+
+func TestActivate(t *testing.T) {
+	orig := setActivePlan
+	t.Cleanup(func() { setActivePlan = orig })
+	var active string
+	setActivePlan = func(plan string) error {
+		active = plan
+		return nil
+	}
+
+	if err := Activate("8C5E7FDA-E8BF-4A96-9A85-A6E23A8C635C"); err != nil || active != HighPerformance {
+		t.Errorf("Activate = %v, active %q", err, active)
+	}
+	if err := Activate("High performance"); !errors.Is(err, ErrUnknownPlan) {
+		t.Errorf("Activate by name = %v, want ErrUnknownPlan", err)
+	}
+}
+
+func TestAuditModeChangesNothing(t *testing.T) {
+	duplicated := fakePowercfg(t, powercfgList)
+	origActive, origWrite := setActivePlan, writeValueIndex
+	t.Cleanup(func() {
+		setActivePlan, writeValueIndex = origActive, origWrite
+		admin.SetAuditMode(false)
+	})
+	changed := false
+	setActivePlan = func(string) error { changed = true; return nil }
+	writeValueIndex = func(string, string, string, uint32, uint32) error { changed = true; return nil }
+	admin.SetAuditMode(true)
+
+	if _, err := EnsureUltimate(); !errors.Is(err, admin.ErrAuditMode) || len(*duplicated) != 0 {
+		t.Errorf("EnsureUltimate in audit mode = %v, duplicated %v", err, *duplicated)
+	}
+	if err := Activate(HighPerformance); !errors.Is(err, admin.ErrAuditMode) {
+		t.Errorf("Activate in audit mode = %v, want ErrAuditMode", err)
+	}
+	if err := Write(Balanced, USBSelectiveSuspend, 0, 1); !errors.Is(err, admin.ErrAuditMode) {
+		t.Errorf("Write in audit mode = %v, want ErrAuditMode", err)
+	}
+	if changed {
+		t.Error("audit mode changed a power plan")
+	}
+}
@@ -0,0 +1,120 @@
+//go:build windows
+
+package power
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	powrprof                   = windows.NewLazySystemDLL("powrprof.dll")
+	procPowerGetActiveScheme   = powrprof.NewProc("PowerGetActiveScheme")
+	procPowerSetActiveScheme   = powrprof.NewProc("PowerSetActiveScheme")
+	procPowerReadACValueIndex  = powrprof.NewProc("PowerReadACValueIndex")
+	procPowerReadDCValueIndex  = powrprof.NewProc("PowerReadDCValueIndex")
+	procPowerWriteACValueIndex = powrprof.NewProc("PowerWriteACValueIndex")
+	procPowerWriteDCValueIndex = powrprof.NewProc("PowerWriteDCValueIndex")
+)
+
+// powercfg runs powercfg with args and returns its output.
+func powercfg(args ...string) (string, error) {
+	cmd := exec.Command("powercfg", args...)
+	// NOTE: Do NOT set HideWindow: true — it triggers AV heuristics.
+	cmd.SysProcAttr = &syscall.SysProcAttr{}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("powercfg %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+func listPlansNative() (string, error) {
+	return powercfg("/list")
+}
+
+// duplicatePlanNative copies plan src to a new plan with GUID dst. Only
+// powercfg lets the copy's GUID be chosen.
+func duplicatePlanNative(src, dst string) error {
+	_, err := powercfg("/duplicatescheme", src, dst)
+	return err
+}
+
+func guids(ids ...string) ([]windows.GUID, error) {
+	out := make([]windows.GUID, len(ids))
+	for i, id := range ids {
+		g, err := windows.GUIDFromString("{" + id + "}")
+		if err != nil {
+			return nil, fmt.Errorf("invalid GUID %s: %w", id, err)
+		}
+		out[i] = g
+	}
+	return out, nil
+}
+
+func activePlanNative() (string, error) {
+	var guid *windows.GUID
+	if r, _, _ := procPowerGetActiveScheme.Call(0, uintptr(unsafe.Pointer(&guid))); r != 0 {
+		return "", windows.Errno(r)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(guid)))
+	return strings.ToLower(strings.Trim(guid.String(), "{}")), nil
+}
+
+func setActivePlanNative(plan string) error {
+	g, err := guids(plan)
+	if err != nil {
+		return err
+	}
+	if r, _, _ := procPowerSetActiveScheme.Call(0, uintptr(unsafe.Pointer(&g[0]))); r != 0 {
+		return windows.Errno(r)
+	}
+	return nil
+}
+
+func readValueIndexNative(plan, subgroup, setting string) (uint32, uint32, error) {
+	g, err := guids(plan, subgroup, setting)
+	if err != nil {
+		return 0, 0, err
+	}
+	var ac, dc uint32
+	for _, read := range []struct {
+		proc *windows.LazyProc
+		val  *uint32
+	}{{procPowerReadACValueIndex, &ac}, {procPowerReadDCValueIndex, &dc}} {
+		if r, _, _ := read.proc.Call(0, uintptr(unsafe.Pointer(&g[0])), uintptr(unsafe.Pointer(&g[1])),
+			uintptr(unsafe.Pointer(&g[2])), uintptr(unsafe.Pointer(read.val))); r != 0 {
+			return 0, 0, windows.Errno(r)
+		}
+	}
+	return ac, dc, nil
+}
+
+// writeValueIndexNative writes a setting's AC and DC values and, if the
+// plan is active, applies them.
+func writeValueIndexNative(plan, subgroup, setting string, ac, dc uint32) error {
+	g, err := guids(plan, subgroup, setting)
+	if err != nil {
+		return err
+	}
+	for _, write := range []struct {
+		proc *windows.LazyProc
+		val  uint32
+	}{{procPowerWriteACValueIndex, ac}, {procPowerWriteDCValueIndex, dc}} {
+		if r, _, _ := write.proc.Call(0, uintptr(unsafe.Pointer(&g[0])), uintptr(unsafe.Pointer(&g[1])),
+			uintptr(unsafe.Pointer(&g[2])), uintptr(write.val)); r != 0 {
+			return windows.Errno(r)
+		}
+	}
+	if active, err := activePlanNative(); err == nil && active == plan {
+		if r, _, _ := procPowerSetActiveScheme.Call(0, uintptr(unsafe.Pointer(&g[0]))); r != 0 {
+			return windows.Errno(r)
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"syscleaner/pkg/admin"
+	"syscleaner/pkg/reglog"
 
 	"golang.org/x/sys/windows/registry"
 )
@@ -18,6 +19,9 @@ func SetProcessPriority(processName string, cpuPriority, ioPriority, pagePriorit
 	if err := admin.RequireElevation("CPU Priority Management"); err != nil {
 		return err
 	}
+	if err := admin.RequireWriteAccess("setting CPU priority for " + processName); err != nil {
+		return err
+	}
 
 	// Ensure .exe extension
 	if !strings.HasSuffix(strings.ToLower(processName), ".exe") {
@@ -36,29 +40,15 @@ func SetProcessPriority(processName string, cpuPriority, ioPriority, pagePriorit
 		return fmt.Errorf("invalid page priority: %d (valid: 0-5)", pagePriority)
 	}
 
-	// Create process key
-	processKeyPath := baseKeyPath + `\` + processName
-	processKey, _, err := registry.CreateKey(registry.LOCAL_MACHINE, processKeyPath, registry.ALL_ACCESS)
-	if err != nil {
-		return fmt.Errorf("failed to create process key: %w", err)
-	}
-	defer processKey.Close()
-
-	// Create PerfOptions subkey
-	perfKey, _, err := registry.CreateKey(registry.LOCAL_MACHINE, processKeyPath+`\PerfOptions`, registry.ALL_ACCESS)
-	if err != nil {
-		return fmt.Errorf("failed to create PerfOptions key: %w", err)
-	}
-	defer perfKey.Close()
-
-	// Set values
-	if err := perfKey.SetDWordValue("CpuPriorityClass", uint32(cpuPriority)); err != nil {
+	// Set values under PerfOptions (the process key is created implicitly)
+	perfKeyPath := baseKeyPath + `\` + processName + `\PerfOptions`
+	if err := reglog.SetDWordValue(registry.LOCAL_MACHINE, perfKeyPath, "CpuPriorityClass", uint32(cpuPriority)); err != nil {
 		return fmt.Errorf("failed to set CpuPriorityClass: %w", err)
 	}
-	if err := perfKey.SetDWordValue("IoPriority", uint32(ioPriority)); err != nil {
+	if err := reglog.SetDWordValue(registry.LOCAL_MACHINE, perfKeyPath, "IoPriority", uint32(ioPriority)); err != nil {
 		return fmt.Errorf("failed to set IoPriority: %w", err)
 	}
-	if err := perfKey.SetDWordValue("PagePriority", uint32(pagePriority)); err != nil {
+	if err := reglog.SetDWordValue(registry.LOCAL_MACHINE, perfKeyPath, "PagePriority", uint32(pagePriority)); err != nil {
 		return fmt.Errorf("failed to set PagePriority: %w", err)
 	}
 
@@ -70,6 +60,9 @@ func RemoveProcessPriority(processName string) error {
 	if err := admin.RequireElevation("CPU Priority Management"); err != nil {
 		return err
 	}
+	if err := admin.RequireWriteAccess("removing CPU priority for " + processName); err != nil {
+		return err
+	}
 
 	// Ensure .exe extension
 	if !strings.HasSuffix(strings.ToLower(processName), ".exe") {
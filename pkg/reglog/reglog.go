@@ -0,0 +1,359 @@
+package reglog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"syscleaner/pkg/logger"
+)
+
+// ValueKind identifies the registry data type of a logged value.
+type ValueKind int
+
+const (
+	KindString ValueKind = iota
+	KindExpandString
+	KindMultiString
+	KindDWord
+	KindQWord
+	KindBinary
+)
+
+// String returns the Windows name for a ValueKind.
+func (k ValueKind) String() string {
+	switch k {
+	case KindString:
+		return "REG_SZ"
+	case KindExpandString:
+		return "REG_EXPAND_SZ"
+	case KindMultiString:
+		return "REG_MULTI_SZ"
+	case KindDWord:
+		return "REG_DWORD"
+	case KindQWord:
+		return "REG_QWORD"
+	case KindBinary:
+		return "REG_BINARY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Value is a typed registry value captured before or after a write.
+type Value struct {
+	Kind    ValueKind `json:"kind"`
+	String  string    `json:"string,omitempty"`
+	Strings []string  `json:"strings,omitempty"`
+	Integer uint64    `json:"integer,omitempty"`
+	Binary  []byte    `json:"binary,omitempty"`
+}
+
+// Equal reports whether v and o hold the same kind and data.
+func (v Value) Equal(o Value) bool {
+	if v.Kind != o.Kind || v.String != o.String || v.Integer != o.Integer ||
+		string(v.Binary) != string(o.Binary) || len(v.Strings) != len(o.Strings) {
+		return false
+	}
+	for i := range v.Strings {
+		if v.Strings[i] != o.Strings[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Change records a single registry write. Old is nil when the value did not
+// exist before the write; New is nil when the write deleted the value.
+type Change struct {
+	Time time.Time `json:"time"`
+	Root string    `json:"root"` // e.g. "HKEY_LOCAL_MACHINE"
+	Path string    `json:"path"` // key path below Root
+	Name string    `json:"name"` // value name, "" for the default value
+	Old  *Value    `json:"old,omitempty"`
+	New  *Value    `json:"new,omitempty"`
+}
+
+// Key returns the full key path including the root hive.
+func (c Change) Key() string {
+	return c.Root + `\` + c.Path
+}
+
+// Recorder collects registry changes for a single run and mirrors them to a
+// .reg undo file and a human-readable change log. All methods are safe for
+// concurrent use.
+type Recorder struct {
+	mu       sync.Mutex
+	changes  []Change
+	undoPath string
+	logPath  string
+}
+
+var (
+	enabled    bool
+	current    *Recorder
+	recorderMu sync.Mutex
+)
+
+// DefaultDir returns the directory per-run registry logs are written to:
+// <user config dir>/SysCleaner/registry.
+func DefaultDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "SysCleaner", "registry")
+}
+
+// Start enables registry logging and begins a new run whose files are
+// written to dir. Each run gets its own timestamped undo (.reg) and change
+// log (.log) file.
+func Start(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("reglog: create log directory %s: %w", dir, err)
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	r := &Recorder{
+		undoPath: filepath.Join(dir, "undo-"+stamp+".reg"),
+		logPath:  filepath.Join(dir, "changes-"+stamp+".log"),
+	}
+
+	recorderMu.Lock()
+	current = r
+	enabled = true
+	recorderMu.Unlock()
+	return r, nil
+}
+
+// Stop disables registry logging. Files already written are left in place.
+func Stop() {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	enabled = false
+	current = nil
+}
+
+// Enabled reports whether registry writes are currently being mirrored.
+func Enabled() bool {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	return enabled
+}
+
+// Current returns the recorder for the active run, or nil if logging is off.
+func Current() *Recorder {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	return current
+}
+
+// Record adds a change to the active run, and to the audit log whether or
+// not a run is active.
+func Record(c Change) {
+	name := c.Name
+	if name == "" {
+		name = "(Default)"
+	}
+	undo := "-"
+	if c.Old != nil {
+		undo = FormatRegData(*c.Old)
+	}
+	logger.Audit(logger.AuditRegistry, c.Key()+`\`+name,
+		DescribeValue(c.Old)+" -> "+DescribeValue(c.New), "reg:"+c.Key()+`\`+name+"="+undo)
+
+	r := Current()
+	if r == nil {
+		return
+	}
+	r.Record(c)
+}
+
+// Record adds a change and mirrors it to the run's files.
+func (r *Recorder) Record(c Change) {
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, c)
+
+	if r.logPath != "" {
+		if f, err := os.OpenFile(r.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
+			_, _ = f.WriteString(FormatChangeLine(c) + "\n")
+			f.Close()
+		}
+	}
+	if r.undoPath != "" {
+		// The undo file is rewritten on every change so it always holds a
+		// complete, importable snapshot even if the process dies mid-run.
+		_ = os.WriteFile(r.undoPath, []byte(FormatUndoReg(r.changes)), 0o644)
+	}
+}
+
+// Changes returns a copy of all changes recorded in this run.
+func (r *Recorder) Changes() []Change {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Change, len(r.changes))
+	copy(out, r.changes)
+	return out
+}
+
+// UndoPath returns the path of the run's .reg undo file.
+func (r *Recorder) UndoPath() string {
+	return r.undoPath
+}
+
+// LogPath returns the path of the run's human-readable change log.
+func (r *Recorder) LogPath() string {
+	return r.logPath
+}
+
+// ExportUndo writes a .reg file that reverts every change in this run.
+func (r *Recorder) ExportUndo(path string) error {
+	if err := os.WriteFile(path, []byte(FormatUndoReg(r.Changes())), 0o644); err != nil {
+		return fmt.Errorf("reglog: write undo file %s: %w", path, err)
+	}
+	return nil
+}
+
+// ExportLog writes the human-readable change log for this run.
+func (r *Recorder) ExportLog(path string) error {
+	var b strings.Builder
+	for _, c := range r.Changes() {
+		b.WriteString(FormatChangeLine(c))
+		b.WriteString("\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("reglog: write change log %s: %w", path, err)
+	}
+	return nil
+}
+
+// FormatChangeLine renders a change as a single human-readable log line.
+//
+// Format: [2006-01-02 15:04:05] SET HKEY_...\Path\Name: <old> -> <new>
+func FormatChangeLine(c Change) string {
+	action := "SET"
+	if c.New == nil {
+		action = "DELETE"
+	}
+	name := c.Name
+	if name == "" {
+		name = "(Default)"
+	}
+	return fmt.Sprintf("[%s] %s %s\\%s: %s -> %s",
+		c.Time.Format("2006-01-02 15:04:05"), action, c.Key(), name,
+		DescribeValue(c.Old), DescribeValue(c.New))
+}
+
+// DescribeValue renders a value as its kind and data, or "(absent)".
+func DescribeValue(v *Value) string {
+	if v == nil {
+		return "(absent)"
+	}
+	switch v.Kind {
+	case KindString, KindExpandString:
+		return fmt.Sprintf("%s %q", v.Kind, v.String)
+	case KindMultiString:
+		return fmt.Sprintf("%s %q", v.Kind, v.Strings)
+	case KindDWord, KindQWord:
+		return fmt.Sprintf("%s 0x%x", v.Kind, v.Integer)
+	case KindBinary:
+		return fmt.Sprintf("%s %x", v.Kind, v.Binary)
+	default:
+		return v.Kind.String()
+	}
+}
+
+// FormatUndoReg renders a .reg file that restores the pre-change state of
+// every value in changes. Changes are reverted newest-first so a value
+// written several times ends up with its original data.
+func FormatUndoReg(changes []Change) string {
+	var b strings.Builder
+	b.WriteString("Windows Registry Editor Version 5.00\r\n")
+
+	lastKey := ""
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		if c.Key() != lastKey {
+			b.WriteString("\r\n[" + c.Key() + "]\r\n")
+			lastKey = c.Key()
+		}
+		b.WriteString(regValueName(c.Name))
+		b.WriteString("=")
+		if c.Old == nil {
+			b.WriteString("-")
+		} else {
+			b.WriteString(FormatRegData(*c.Old))
+		}
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+func regValueName(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return `"` + escapeRegString(name) + `"`
+}
+
+func escapeRegString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// FormatRegData renders the data half of a .reg value assignment.
+func FormatRegData(v Value) string {
+	switch v.Kind {
+	case KindString:
+		return `"` + escapeRegString(v.String) + `"`
+	case KindDWord:
+		return fmt.Sprintf("dword:%08x", uint32(v.Integer))
+	case KindQWord:
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, v.Integer)
+		return "hex(b):" + hexBytes(buf)
+	case KindExpandString:
+		return "hex(2):" + hexBytes(utf16Bytes([]string{v.String}, false))
+	case KindMultiString:
+		return "hex(7):" + hexBytes(utf16Bytes(v.Strings, true))
+	default:
+		return "hex:" + hexBytes(v.Binary)
+	}
+}
+
+func hexBytes(data []byte) string {
+	parts := make([]string, len(data))
+	for i, c := range data {
+		parts[i] = fmt.Sprintf("%02x", c)
+	}
+	return strings.Join(parts, ",")
+}
+
+// utf16Bytes encodes strings as NUL-terminated UTF-16LE, the on-disk format
+// of REG_EXPAND_SZ and REG_MULTI_SZ data. Multi-strings get an extra
+// terminating NUL.
+func utf16Bytes(ss []string, multi bool) []byte {
+	var units []uint16
+	for _, s := range ss {
+		units = append(units, utf16.Encode([]rune(s))...)
+		units = append(units, 0)
+	}
+	if multi {
+		units = append(units, 0)
+	}
+	out := make([]byte, len(units)*2)
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(out[i*2:], u)
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package reglog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"syscleaner/pkg/logger"
+)
+
+func TestFormatUndoReg_RestoresOldValues(t *testing.T) {
+	changes := []Change{
+		{
+			Root: "HKEY_LOCAL_MACHINE",
+			Path: `SOFTWARE\Test`,
+			Name: "Throttle",
+			Old:  &Value{Kind: KindDWord, Integer: 10},
+			New:  &Value{Kind: KindDWord, Integer: 0xffffffff},
+		},
+		{
+			Root: "HKEY_CURRENT_USER",
+			Path: `SOFTWARE\Run`,
+			Name: "OneDrive",
+			Old:  &Value{Kind: KindString, String: `C:\Program Files\OneDrive.exe "/background"`},
+		},
+	}
+
+	out := FormatUndoReg(changes)
+
+	if !strings.HasPrefix(out, "Windows Registry Editor Version 5.00\r\n") {
+		t.Errorf("missing .reg header, got %q", out)
+	}
+	if !strings.Contains(out, "[HKEY_LOCAL_MACHINE\\SOFTWARE\\Test]\r\n\"Throttle\"=dword:0000000a") {
+		t.Errorf("expected DWORD restore, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"OneDrive"="C:\\Program Files\\OneDrive.exe \"/background\""`) {
+		t.Errorf("expected escaped string restore, got:\n%s", out)
+	}
+
+	// Undo must run newest-first.
+	if strings.Index(out, "OneDrive") > strings.Index(out, "Throttle") {
+		t.Error("expected changes to be reverted in reverse order")
+	}
+}
+
+func TestFormatUndoReg_DeletesNewValues(t *testing.T) {
+	changes := []Change{{
+		Root: "HKEY_CURRENT_USER",
+		Path: `Control Panel\Desktop`,
+		Name: "Created",
+		New:  &Value{Kind: KindDWord, Integer: 1},
+	}}
+
+	out := FormatUndoReg(changes)
+	if !strings.Contains(out, `"Created"=-`) {
+		t.Errorf("expected value deletion for newly-created value, got:\n%s", out)
+	}
+}
+
+func TestFormatRegData(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{"dword", Value{Kind: KindDWord, Integer: 1}, "dword:00000001"},
+		{"qword", Value{Kind: KindQWord, Integer: 0x0102}, "hex(b):02,01,00,00,00,00,00,00"},
+		{"binary", Value{Kind: KindBinary, Binary: []byte{0x90, 0x12}}, "hex:90,12"},
+		{"expand", Value{Kind: KindExpandString, String: "A"}, "hex(2):41,00,00,00"},
+		{"multi", Value{Kind: KindMultiString, Strings: []string{"A", "B"}}, "hex(7):41,00,00,00,42,00,00,00,00,00"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatRegData(tc.value); got != tc.expected {
+				t.Errorf("FormatRegData() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRecorder_MirrorsToRunFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	rec, err := Start(dir)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(Stop)
+
+	Record(Change{
+		Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Root: "HKEY_CURRENT_USER",
+		Path: `SOFTWARE\Test`,
+		Name: "Value",
+		New:  &Value{Kind: KindDWord, Integer: 1},
+	})
+
+	if len(rec.Changes()) != 1 {
+		t.Fatalf("expected 1 recorded change, got %d", len(rec.Changes()))
+	}
+
+	undo, err := os.ReadFile(rec.UndoPath())
+	if err != nil {
+		t.Fatalf("undo file not written: %v", err)
+	}
+	if !strings.Contains(string(undo), `"Value"=-`) {
+		t.Errorf("unexpected undo file contents:\n%s", undo)
+	}
+
+	logData, err := os.ReadFile(rec.LogPath())
+	if err != nil {
+		t.Fatalf("change log not written: %v", err)
+	}
+	if !strings.Contains(string(logData), `[2024-01-02 03:04:05] SET HKEY_CURRENT_USER\SOFTWARE\Test\Value: (absent) -> REG_DWORD 0x1`) {
+		t.Errorf("unexpected change log contents:\n%s", logData)
+	}
+
+	if filepath.Dir(rec.UndoPath()) != dir {
+		t.Errorf("expected undo file in %s, got %s", dir, rec.UndoPath())
+	}
+
+	audit, err := logger.ReadAudit(logger.AuditPath(), logger.AuditFilter{Action: logger.AuditRegistry})
+	if err != nil || len(audit) != 1 {
+		t.Fatalf("audit entries = %v, %v; want 1", audit, err)
+	}
+	if want := `reg:HKEY_CURRENT_USER\SOFTWARE\Test\Value=-`; audit[0].Undo != want {
+		t.Errorf("undo token = %q, want %q", audit[0].Undo, want)
+	}
+}
+
+func TestRecord_NoopWhenDisabled(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	Stop()
+	Record(Change{Root: "HKEY_CURRENT_USER", Path: "X", Name: "Y"})
+	if Current() != nil {
+		t.Error("expected no active recorder after Stop")
+	}
+}
+
+func TestValueEqual(t *testing.T) {
+	a := Value{Kind: KindBinary, Binary: []byte{0x90, 0x12}}
+	if !a.Equal(Value{Kind: KindBinary, Binary: []byte{0x90, 0x12}}) {
+		t.Error("identical binary values should be equal")
+	}
+	if a.Equal(Value{Kind: KindBinary, Binary: []byte{0x90, 0x13}}) {
+		t.Error("different data should not be equal")
+	}
+	if (Value{Kind: KindDWord, Integer: 1}).Equal(Value{Kind: KindQWord, Integer: 1}) {
+		t.Error("different kinds should not be equal")
+	}
+	if (Value{Kind: KindMultiString, Strings: []string{"a"}}).Equal(Value{Kind: KindMultiString, Strings: []string{"a", "b"}}) {
+		t.Error("different string lists should not be equal")
+	}
+}
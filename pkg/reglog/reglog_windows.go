@@ -0,0 +1,176 @@
+//go:build windows
+
+package reglog
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+
+	"syscleaner/pkg/admin"
+)
+
+// RootName returns the hive name used in .reg files for a predefined key.
+func RootName(root registry.Key) string {
+	switch root {
+	case registry.CLASSES_ROOT:
+		return "HKEY_CLASSES_ROOT"
+	case registry.CURRENT_USER:
+		return "HKEY_CURRENT_USER"
+	case registry.LOCAL_MACHINE:
+		return "HKEY_LOCAL_MACHINE"
+	case registry.USERS:
+		return "HKEY_USERS"
+	case registry.CURRENT_CONFIG:
+		return "HKEY_CURRENT_CONFIG"
+	default:
+		return "HKEY_UNKNOWN"
+	}
+}
+
+// RootKey returns the predefined key for a hive name as RootName gives it.
+func RootKey(name string) (registry.Key, error) {
+	for _, root := range []registry.Key{registry.CLASSES_ROOT, registry.CURRENT_USER,
+		registry.LOCAL_MACHINE, registry.USERS, registry.CURRENT_CONFIG} {
+		if RootName(root) == name {
+			return root, nil
+		}
+	}
+	return 0, fmt.Errorf("reglog: unknown registry hive %q", name)
+}
+
+// Lookup reads the value name of the key at root\path, returning nil if
+// the key or value does not exist.
+func Lookup(root registry.Key, path, name string) *Value {
+	key, err := registry.OpenKey(root, path, registry.QUERY_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+	return ReadValue(key, name)
+}
+
+// ReadValue reads a value of any supported type from an open key. It
+// returns nil if the value does not exist or cannot be read.
+func ReadValue(key registry.Key, name string) *Value {
+	_, valType, err := key.GetValue(name, nil)
+	if err != nil {
+		return nil
+	}
+
+	switch valType {
+	case registry.SZ, registry.EXPAND_SZ:
+		s, _, err := key.GetStringValue(name)
+		if err != nil {
+			return nil
+		}
+		kind := KindString
+		if valType == registry.EXPAND_SZ {
+			kind = KindExpandString
+		}
+		return &Value{Kind: kind, String: s}
+	case registry.MULTI_SZ:
+		ss, _, err := key.GetStringsValue(name)
+		if err != nil {
+			return nil
+		}
+		return &Value{Kind: KindMultiString, Strings: ss}
+	case registry.DWORD, registry.QWORD:
+		n, _, err := key.GetIntegerValue(name)
+		if err != nil {
+			return nil
+		}
+		kind := KindDWord
+		if valType == registry.QWORD {
+			kind = KindQWord
+		}
+		return &Value{Kind: kind, Integer: n}
+	default:
+		data, _, err := key.GetBinaryValue(name)
+		if err != nil {
+			return nil
+		}
+		return &Value{Kind: KindBinary, Binary: data}
+	}
+}
+
+// SetDWordValue writes a DWORD through the key at root\path (creating it if
+// needed) and records the change when logging is enabled.
+func SetDWordValue(root registry.Key, path, name string, val uint32) error {
+	return write(root, path, name, &Value{Kind: KindDWord, Integer: uint64(val)}, func(k registry.Key) error {
+		return k.SetDWordValue(name, val)
+	})
+}
+
+// SetStringValue writes a REG_SZ value and records the change.
+func SetStringValue(root registry.Key, path, name, val string) error {
+	return write(root, path, name, &Value{Kind: KindString, String: val}, func(k registry.Key) error {
+		return k.SetStringValue(name, val)
+	})
+}
+
+// SetBinaryValue writes a REG_BINARY value and records the change.
+func SetBinaryValue(root registry.Key, path, name string, val []byte) error {
+	return write(root, path, name, &Value{Kind: KindBinary, Binary: val}, func(k registry.Key) error {
+		return k.SetBinaryValue(name, val)
+	})
+}
+
+// SetValue writes v, of any kind, and records the change.
+func SetValue(root registry.Key, path, name string, v Value) error {
+	return write(root, path, name, &v, func(k registry.Key) error {
+		switch v.Kind {
+		case KindString:
+			return k.SetStringValue(name, v.String)
+		case KindExpandString:
+			return k.SetExpandStringValue(name, v.String)
+		case KindMultiString:
+			return k.SetStringsValue(name, v.Strings)
+		case KindDWord:
+			return k.SetDWordValue(name, uint32(v.Integer))
+		case KindQWord:
+			return k.SetQWordValue(name, v.Integer)
+		default:
+			return k.SetBinaryValue(name, v.Binary)
+		}
+	})
+}
+
+// DeleteValue removes a value and records its previous data so the
+// deletion can be undone.
+func DeleteValue(root registry.Key, path, name string) error {
+	if err := admin.RequireWriteAccess(fmt.Sprintf(`deleting %s\%s\%s`, RootName(root), path, name)); err != nil {
+		return err
+	}
+	key, err := registry.OpenKey(root, path, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	old := ReadValue(key, name)
+	if err := key.DeleteValue(name); err != nil {
+		return err
+	}
+	Record(Change{Time: time.Now(), Root: RootName(root), Path: path, Name: name, Old: old})
+	return nil
+}
+
+func write(root registry.Key, path, name string, newVal *Value, set func(registry.Key) error) error {
+	if err := admin.RequireWriteAccess(fmt.Sprintf(`setting %s\%s\%s`, RootName(root), path, name)); err != nil {
+		return err
+	}
+	key, _, err := registry.CreateKey(root, path, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	old := ReadValue(key, name)
+	if err := set(key); err != nil {
+		return err
+	}
+	Record(Change{Time: time.Now(), Root: RootName(root), Path: path, Name: name, Old: old, New: newVal})
+	return nil
+}
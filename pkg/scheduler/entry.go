@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OptimizeTargets are the values accepted in ScheduleEntry.Optimize.
+var OptimizeTargets = []string{"startup", "network", "disk"}
+
+// ScheduleEntry is a scheduled job declared in the config file. It runs
+// either on a cron spec or at a fixed interval.
+type ScheduleEntry struct {
+	// Name identifies the job in logs and notifications.
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+
+	// Cron is a five-field cron spec ("minute hour day month weekday"),
+	// e.g. "0 3 * * 0" for Sundays at 03:00, or @hourly, @daily, @weekly
+	// or @monthly.
+	Cron string `json:"cron,omitempty"`
+	// Interval runs the job repeatedly with this gap, as a Go duration
+	// such as "6h". Exactly one of Cron and Interval is set.
+	Interval string `json:"interval,omitempty"`
+
+	// Profile is the profile whose clean options are used; empty means
+	// the active profile.
+	Profile string `json:"profile,omitempty"`
+	// Clean runs the profile's clean.
+	Clean bool `json:"clean"`
+	// Optimize lists optimizations to run: "startup", "network", "disk".
+	Optimize []string `json:"optimize,omitempty"`
+}
+
+// minInterval is the shortest Interval accepted, so a typo such as "6s"
+// does not clean continuously.
+const minInterval = time.Minute
+
+// Validate reports the first invalid setting in e.
+func (e ScheduleEntry) Validate() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return fmt.Errorf("schedule has no name")
+	}
+	switch {
+	case e.Cron != "" && e.Interval != "":
+		return fmt.Errorf("schedule %q: set either cron or interval, not both", e.Name)
+	case e.Cron != "":
+		if _, err := parseCron(e.Cron); err != nil {
+			return fmt.Errorf("schedule %q: %w", e.Name, err)
+		}
+	case e.Interval != "":
+		d, err := time.ParseDuration(e.Interval)
+		if err != nil {
+			return fmt.Errorf("schedule %q: invalid interval: %w", e.Name, err)
+		}
+		if d < minInterval {
+			return fmt.Errorf("schedule %q: interval %s is shorter than %s", e.Name, d, minInterval)
+		}
+	default:
+		return fmt.Errorf("schedule %q: set cron or interval", e.Name)
+	}
+	for _, target := range e.Optimize {
+		if !isOptimizeTarget(target) {
+			return fmt.Errorf("schedule %q: unknown optimize target %q (valid: %s)",
+				e.Name, target, strings.Join(OptimizeTargets, ", "))
+		}
+	}
+	if !e.Clean && len(e.Optimize) == 0 {
+		return fmt.Errorf("schedule %q: nothing to run; set clean or optimize", e.Name)
+	}
+	return nil
+}
+
+// Next returns the first time after t that e is due.
+func (e ScheduleEntry) Next(t time.Time) (time.Time, error) {
+	if e.Interval != "" {
+		d, err := time.ParseDuration(e.Interval)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("schedule %q: invalid interval: %w", e.Name, err)
+		}
+		return t.Add(d), nil
+	}
+	spec, err := parseCron(e.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("schedule %q: %w", e.Name, err)
+	}
+	next := spec.next(t)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("schedule %q: cron spec %q never fires", e.Name, e.Cron)
+	}
+	return next, nil
+}
+
+// Runs reports whether e runs the optimization target.
+func (e ScheduleEntry) Runs(target string) bool {
+	for _, t := range e.Optimize {
+		if strings.EqualFold(t, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func isOptimizeTarget(target string) bool {
+	for _, t := range OptimizeTargets {
+		if strings.EqualFold(t, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleEntry_Next(t *testing.T) {
+	// Wednesday 2026-10-14 10:07.
+	from := time.Date(2026, 10, 14, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		name  string
+		entry ScheduleEntry
+		want  time.Time
+	}{
+		{"weekly sunday", ScheduleEntry{Cron: "0 3 * * 0"}, time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)},
+		{"sunday as 7", ScheduleEntry{Cron: "0 3 * * 7"}, time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)},
+		{"every 15 minutes", ScheduleEntry{Cron: "*/15 * * * *"}, time.Date(2026, 10, 14, 10, 15, 0, 0, time.UTC)},
+		{"working hours", ScheduleEntry{Cron: "30 9-17/4 * * 1-5"}, time.Date(2026, 10, 14, 13, 30, 0, 0, time.UTC)},
+		{"monthly macro", ScheduleEntry{Cron: "@monthly"}, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", ScheduleEntry{Cron: "0 0 20 * 5"}, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"leap day", ScheduleEntry{Cron: "0 0 29 2 *"}, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"interval", ScheduleEntry{Interval: "6h"}, from.Add(6 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.entry.Next(from)
+			if err != nil {
+				t.Fatalf("Next: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleEntry_Validate(t *testing.T) {
+	valid := ScheduleEntry{Name: "nightly", Cron: "@daily", Clean: true}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid entry: %v", err)
+	}
+
+	invalid := []ScheduleEntry{
+		{Cron: "@daily", Clean: true},
+		{Name: "both", Cron: "@daily", Interval: "1h", Clean: true},
+		{Name: "neither", Clean: true},
+		{Name: "fields", Cron: "0 3 * *", Clean: true},
+		{Name: "range", Cron: "60 * * * *", Clean: true},
+		{Name: "short", Interval: "10s", Clean: true},
+		{Name: "target", Interval: "1h", Optimize: []string{"registry"}},
+		{Name: "empty", Interval: "1h"},
+		{Name: "never", Cron: "0 0 31 2 *", Clean: true},
+	}
+	for _, e := range invalid {
+		if e.Name == "never" {
+			// Valid syntax; Next reports that it cannot fire.
+			if _, err := e.Next(time.Now()); err == nil {
+				t.Errorf("Next(%q) succeeded, want error", e.Cron)
+			}
+			continue
+		}
+		if err := e.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", e)
+		}
+	}
+}
+
+func TestRunner_FiresDueEntries(t *testing.T) {
+	now := time.Date(2026, 10, 14, 2, 59, 0, 0, time.UTC)
+	var fired []string
+	r := &Runner{
+		Entries: []ScheduleEntry{
+			{Name: "nightly", Enabled: true, Cron: "0 3 * * *", Clean: true},
+			{Name: "off", Enabled: false, Cron: "0 3 * * *", Clean: true},
+			{Name: "hourly", Enabled: true, Interval: "1h", Clean: true},
+		},
+		OnDue: func(e ScheduleEntry) { fired = append(fired, e.Name) },
+		now:   func() time.Time { return now },
+	}
+
+	r.Poll()
+	if len(fired) != 0 {
+		t.Fatalf("fired %v before anything was due", fired)
+	}
+
+	now = now.Add(time.Minute)
+	r.Poll()
+	if len(fired) != 1 || fired[0] != "nightly" {
+		t.Fatalf("fired %v, want [nightly]", fired)
+	}
+	next, ok := r.NextRun("nightly")
+	if want := time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC); !ok || !next.Equal(want) {
+		t.Errorf("NextRun(nightly) = %s, want %s", next, want)
+	}
+	if _, ok := r.NextRun("off"); ok {
+		t.Error("disabled entry was scheduled")
+	}
+
+	now = now.Add(time.Hour)
+	r.Poll()
+	if len(fired) != 2 || fired[1] != "hourly" {
+		t.Errorf("fired %v, want [nightly hourly]", fired)
+	}
+}
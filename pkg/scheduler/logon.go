@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"syscleaner/pkg/admin"
+)
+
+// logonTaskArgs returns the schtasks arguments that create a task named
+// name running command, with the user's highest rights, at every logon.
+func logonTaskArgs(name, command string) []string {
+	return []string{
+		"/create",
+		"/tn", name,
+		"/tr", command,
+		"/sc", "onlogon",
+		"/rl", "highest",
+		"/f",
+	}
+}
+
+// CreateLogonTask registers a Windows scheduled task that runs command
+// with the user's highest rights whenever they log on, replacing any task
+// of the same name.
+func CreateLogonTask(name, command string) error {
+	if runtime.GOOS != "windows" {
+		return fmt.Errorf("scheduled tasks only available on Windows")
+	}
+	if err := admin.RequireWriteAccess("creating the " + name + " task"); err != nil {
+		return err
+	}
+	return schtasks("create", logonTaskArgs(name, command)...)
+}
+
+// RunTask starts the scheduled task name now.
+func RunTask(name string) error {
+	if runtime.GOOS != "windows" {
+		return fmt.Errorf("scheduled tasks only available on Windows")
+	}
+	return schtasks("run", "/run", "/tn", name)
+}
+
+// RemoveTask deletes the scheduled task name.
+func RemoveTask(name string) error {
+	if runtime.GOOS != "windows" {
+		return fmt.Errorf("scheduled tasks only available on Windows")
+	}
+	if err := admin.RequireWriteAccess("removing the " + name + " task"); err != nil {
+		return err
+	}
+	return schtasks("remove", "/delete", "/tn", name, "/f")
+}
+
+// TaskExists reports whether a scheduled task named name exists.
+func TaskExists(name string) (bool, error) {
+	if runtime.GOOS != "windows" {
+		return false, nil
+	}
+	cmd := exec.Command("schtasks", "/query", "/tn", name)
+	cmd.SysProcAttr = getSysProcAttr()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// If the task does not exist schtasks returns an error.
+		if strings.Contains(string(output), "ERROR") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to query scheduled task: %w\n%s", err, string(output))
+	}
+	return true, nil
+}
+
+// schtasks runs schtasks with args; verb describes it in errors.
+func schtasks(verb string, args ...string) error {
+	cmd := exec.Command("schtasks", args...)
+	cmd.SysProcAttr = getSysProcAttr()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to %s scheduled task: %w\n%s", verb, err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogonTaskArgs(t *testing.T) {
+	got := strings.Join(logonTaskArgs("SysCleanerAgent", `"C:\SysCleaner.exe" --agent`), " ")
+	want := `/create /tn SysCleanerAgent /tr "C:\SysCleaner.exe" --agent /sc onlogon /rl highest /f`
+	if got != want {
+		t.Errorf("args = %s\nwant   %s", got, want)
+	}
+}
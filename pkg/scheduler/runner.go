@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Runner fires OnDue for each enabled entry when it falls due. It runs
+// in-process, so jobs only run while SysCleaner is open; entries that came
+// due while it was closed are not caught up.
+type Runner struct {
+	Entries  []ScheduleEntry
+	Interval time.Duration // how often due times are checked
+	OnDue    func(ScheduleEntry)
+
+	mu   sync.Mutex
+	done chan struct{}
+	next map[string]time.Time
+
+	// now is time.Now, replaceable in tests.
+	now func() time.Time
+}
+
+// Start computes each entry's first due time and begins checking in a
+// background goroutine. Calling Start on a running runner has no effect.
+func (r *Runner) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done != nil {
+		return
+	}
+	r.done = make(chan struct{})
+	done := r.done
+	r.schedule()
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				r.Poll()
+			}
+		}
+	}()
+}
+
+// Stop ends checking. It is safe to call on a stopped runner.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done != nil {
+		close(r.done)
+		r.done = nil
+	}
+}
+
+// Poll fires OnDue for every entry whose due time has passed and schedules
+// its next run.
+func (r *Runner) Poll() {
+	r.mu.Lock()
+	if r.next == nil {
+		r.schedule()
+	}
+	now := r.clock()
+	var due []ScheduleEntry
+	for _, e := range r.Entries {
+		at, ok := r.next[e.Name]
+		if !ok || now.Before(at) {
+			continue
+		}
+		due = append(due, e)
+		r.setNext(e, now)
+	}
+	r.mu.Unlock()
+
+	for _, e := range due {
+		if r.OnDue != nil {
+			r.OnDue(e)
+		}
+	}
+}
+
+// NextRun returns when the named entry is next due, or false if it is not
+// scheduled.
+func (r *Runner) NextRun(name string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	at, ok := r.next[name]
+	return at, ok
+}
+
+// schedule sets the first due time of every enabled entry. r.mu is held.
+func (r *Runner) schedule() {
+	r.next = make(map[string]time.Time)
+	now := r.clock()
+	for _, e := range r.Entries {
+		if e.Enabled {
+			r.setNext(e, now)
+		}
+	}
+}
+
+// setNext records e's next due time after t. r.mu is held.
+func (r *Runner) setNext(e ScheduleEntry, t time.Time) {
+	at, err := e.Next(t)
+	if err != nil {
+		log.Printf("[SysCleaner] Not scheduling %q: %v", e.Name, err)
+		delete(r.next, e.Name)
+		return
+	}
+	r.next[e.Name] = at
+}
+
+func (r *Runner) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+
+	"syscleaner/pkg/admin"
 )
 
 // ScheduleConfig holds configuration for a scheduled weekly clean.
@@ -22,6 +24,9 @@ func CreateScheduledClean(cfg ScheduleConfig) error {
 	if runtime.GOOS != "windows" {
 		return fmt.Errorf("scheduled cleaning only available on Windows")
 	}
+	if err := admin.RequireWriteAccess("creating the scheduled clean task"); err != nil {
+		return err
+	}
 
 	exePath, err := os.Executable()
 	if err != nil {
@@ -65,6 +70,9 @@ func RemoveScheduledClean() error {
 	if runtime.GOOS != "windows" {
 		return fmt.Errorf("scheduled cleaning only available on Windows")
 	}
+	if err := admin.RequireWriteAccess("removing the scheduled clean task"); err != nil {
+		return err
+	}
 
 	cmd := exec.Command("schtasks",
 		"/delete",
@@ -0,0 +1,228 @@
+// Package support builds the diagnostic bundle users attach to GitHub
+// issues: configuration, recent logs, the registry change log, system
+// information and recent run reports, with personal information redacted.
+package support
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"syscleaner/pkg/admin"
+	"syscleaner/pkg/cleaner"
+	"syscleaner/pkg/config"
+	"syscleaner/pkg/logger"
+	"syscleaner/pkg/reglog"
+	"syscleaner/pkg/sysinfo"
+)
+
+const (
+	// maxLogBytes is how much of the end of the log file is included.
+	maxLogBytes = 1 << 20
+	// maxLedgerFiles and maxReports cap how many registry change logs and
+	// run reports are included, newest first.
+	maxLedgerFiles = 5
+	maxReports     = 5
+)
+
+// Sources are the locations a bundle is collected from.
+type Sources struct {
+	ConfigDir string
+	// ConfigFile is the config file to include; empty means config.yaml
+	// in ConfigDir.
+	ConfigFile  string
+	LogPath     string
+	RegistryDir string
+	ReportsDir  string
+}
+
+// DefaultSources returns the locations SysCleaner writes to by default.
+func DefaultSources() Sources {
+	var configDir string
+	configFile, _, err := config.ResolveConfigPath()
+	if err == nil {
+		configDir = filepath.Dir(configFile)
+	}
+	return Sources{
+		ConfigDir:   configDir,
+		ConfigFile:  configFile,
+		LogPath:     logger.DefaultLogPath(),
+		RegistryDir: reglog.DefaultDir(),
+		ReportsDir:  cleaner.DefaultReportsDir(),
+	}
+}
+
+// DefaultBundleName returns a timestamped file name for a bundle.
+func DefaultBundleName() string {
+	return "syscleaner-support-" + time.Now().Format("20060102-150405") + ".zip"
+}
+
+// CreateBundle writes a support bundle zip to path and returns the names of
+// the files it contains.
+func CreateBundle(path string, src Sources, mode RedactMode) ([]string, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating bundle: %w", err)
+	}
+	names, err := writeBundle(f, src, newRedactor(mode))
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("writing bundle: %w", cerr)
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return names, nil
+}
+
+// bundleFile is one file to be added to the bundle.
+type bundleFile struct {
+	name string // path inside the zip
+	data string
+}
+
+func writeBundle(w io.Writer, src Sources, r *redactor) ([]string, error) {
+	var files []bundleFile
+	add := func(name, data string) {
+		files = append(files, bundleFile{name: name, data: r.Redact(data)})
+	}
+
+	add("sysinfo.txt", systemSummary())
+
+	if src.ConfigDir != "" {
+		configFile := src.ConfigFile
+		if configFile == "" {
+			configFile = filepath.Join(src.ConfigDir, "config.yaml")
+		}
+		if data, err := os.ReadFile(configFile); err == nil {
+			add("config/config.json", string(data))
+		}
+		profiles, _ := filepath.Glob(filepath.Join(src.ConfigDir, "profiles", "*.json"))
+		for _, p := range profiles {
+			if data, err := os.ReadFile(p); err == nil {
+				add("config/profiles/"+filepath.Base(p), string(data))
+			}
+		}
+	}
+
+	if src.LogPath != "" {
+		if data, err := readTail(src.LogPath, maxLogBytes); err == nil {
+			add("logs/"+filepath.Base(src.LogPath), data)
+		}
+	}
+
+	if src.RegistryDir != "" {
+		ledger, _ := filepath.Glob(filepath.Join(src.RegistryDir, "changes-*.log"))
+		for _, p := range newest(ledger, maxLedgerFiles) {
+			if data, err := os.ReadFile(p); err == nil {
+				add("registry/"+filepath.Base(p), string(data))
+			}
+		}
+	}
+
+	if src.ReportsDir != "" {
+		reports, _ := cleaner.ListRunReports(src.ReportsDir)
+		for _, p := range newest(reports, maxReports) {
+			if data, err := os.ReadFile(p); err == nil {
+				add("reports/"+filepath.Base(p), string(data))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files)+1)
+	for _, f := range files {
+		names = append(names, f.name)
+	}
+	manifest := fmt.Sprintf("SysCleaner support bundle\nCreated: %s\nRedaction: %s\n\nFiles:\n  %s\n",
+		time.Now().Format(time.RFC3339), r.mode, strings.Join(names, "\n  "))
+	files = append([]bundleFile{{name: "manifest.txt", data: manifest}}, files...)
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("writing bundle: %w", err)
+		}
+		if _, err := io.WriteString(fw, f.data); err != nil {
+			return nil, fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	return append([]string{"manifest.txt"}, names...), nil
+}
+
+// systemSummary describes the machine without identifying it.
+func systemSummary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "OS/Arch:         %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Go version:      %s\n", runtime.Version())
+	if info, err := host.Info(); err == nil {
+		fmt.Fprintf(&b, "Platform:        %s %s (%s)\n", info.Platform, info.PlatformVersion, info.KernelVersion)
+		fmt.Fprintf(&b, "Uptime:          %s\n", time.Duration(info.Uptime)*time.Second)
+	}
+	hw := sysinfo.Detect()
+	fmt.Fprintf(&b, "Logical CPUs:    %d\n", hw.LogicalCPUs)
+	if vmem, err := mem.VirtualMemory(); err == nil {
+		fmt.Fprintf(&b, "Memory:          %s total, %.1f%% used\n", cleaner.FormatBytes(int64(vmem.Total)), vmem.UsedPercent)
+	}
+	if hw.SSDKnown {
+		fmt.Fprintf(&b, "System drive SSD: %v\n", hw.SystemDriveSSD)
+	}
+	if vol, err := sysinfo.Volume(sysinfo.SystemDrive()); err == nil {
+		fmt.Fprintf(&b, "System drive FS: %s (compression %v, USN %v)\n", vol.FileSystem, vol.SupportsCompression, vol.SupportsUSN)
+	}
+	fmt.Fprintf(&b, "Elevated:        %v\n", admin.IsElevated())
+	fmt.Fprintf(&b, "Pending reboot:  %v\n", sysinfo.PendingReboot())
+	return b.String()
+}
+
+// readTail returns up to n bytes from the end of the file at path.
+func readTail(path string, n int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > n {
+		if _, err := f.Seek(-n, io.SeekEnd); err != nil {
+			return "", err
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > n {
+		// Drop the partial first line.
+		if i := strings.IndexByte(string(data), '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return string(data), nil
+}
+
+// newest returns at most n of the timestamp-named paths, newest first.
+func newest(paths []string, n int) []string {
+	sorted := append([]string(nil), paths...)
+	sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
@@ -0,0 +1,112 @@
+package support
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+)
+
+// RedactMode selects how personal information is scrubbed from bundle files.
+type RedactMode string
+
+const (
+	// RedactNone copies files unchanged.
+	RedactNone RedactMode = "none"
+	// RedactUser replaces the user name, profile directory and computer
+	// name with placeholders but keeps the rest of each path readable.
+	RedactUser RedactMode = "redact"
+	// RedactHash additionally replaces every path with a short hash, so
+	// repeated paths can still be correlated without being readable.
+	RedactHash RedactMode = "hash"
+)
+
+// ParseRedactMode validates a --redact value.
+func ParseRedactMode(s string) (RedactMode, error) {
+	switch m := RedactMode(strings.ToLower(s)); m {
+	case RedactNone, RedactUser, RedactHash:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown redaction mode %q (use none, redact, or hash)", s)
+	}
+}
+
+// pathPattern matches drive-letter paths, including the escaped backslashes
+// found in JSON files.
+var pathPattern = regexp.MustCompile(`\b[A-Za-z]:\\{1,2}[^\s"'<>|*?,;]*`)
+
+// redactor scrubs identifying strings from text.
+type redactor struct {
+	mode     RedactMode
+	home     string
+	user     *regexp.Regexp
+	computer *regexp.Regexp
+}
+
+func newRedactor(mode RedactMode) *redactor {
+	r := &redactor{mode: mode}
+	if mode == RedactNone {
+		return r
+	}
+	r.home, _ = os.UserHomeDir()
+	if u, err := user.Current(); err == nil {
+		name := u.Username
+		if i := strings.LastIndexAny(name, `\/`); i >= 0 {
+			name = name[i+1:]
+		}
+		r.user = wordPattern(name)
+	}
+	if host, err := os.Hostname(); err == nil {
+		r.computer = wordPattern(host)
+	}
+	return r
+}
+
+// wordPattern matches s case-insensitively as a whole word. Very short
+// names are ignored; they would match too much unrelated text.
+func wordPattern(s string) *regexp.Regexp {
+	if len(s) < 3 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(s) + `\b`)
+}
+
+// Redact returns text with identifying information removed according to
+// the redactor's mode.
+func (r *redactor) Redact(text string) string {
+	if r.mode == RedactNone {
+		return text
+	}
+	if r.mode == RedactHash {
+		text = pathPattern.ReplaceAllStringFunc(text, hashPath)
+	}
+	if r.home != "" {
+		text = replaceFold(text, r.home, "%USERPROFILE%")
+		text = replaceFold(text, strings.ReplaceAll(r.home, `\`, `\\`), "%USERPROFILE%")
+	}
+	if r.user != nil {
+		text = r.user.ReplaceAllString(text, "<user>")
+	}
+	if r.computer != nil {
+		text = r.computer.ReplaceAllString(text, "<computer>")
+	}
+	return text
+}
+
+// hashPath replaces a path with a stable hash of its normalized form.
+func hashPath(p string) string {
+	norm := strings.ToLower(strings.ReplaceAll(p, `\\`, `\`))
+	sum := sha256.Sum256([]byte(norm))
+	return "<path:" + hex.EncodeToString(sum[:4]) + ">"
+}
+
+// replaceFold replaces every case-insensitive occurrence of old in s.
+func replaceFold(s, old, repl string) string {
+	if old == "" {
+		return s
+	}
+	return regexp.MustCompile(`(?i)`+regexp.QuoteMeta(old)).ReplaceAllLiteralString(s, repl)
+}
@@ -0,0 +1,120 @@
+package support
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedactUser(t *testing.T) {
+	r := &redactor{
+		mode:     RedactUser,
+		home:     `C:\Users\alice`,
+		user:     wordPattern("alice"),
+		computer: wordPattern("DESKTOP-42"),
+	}
+	in := `{"exclusions": ["C:\\Users\\Alice\\Downloads"]} deleted C:\Users\alice\AppData\x.tmp on desktop-42 by alice`
+	got := r.Redact(in)
+	want := `{"exclusions": ["%USERPROFILE%\\Downloads"]} deleted %USERPROFILE%\AppData\x.tmp on <computer> by <user>`
+	if got != want {
+		t.Errorf("Redact() =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestRedactHash(t *testing.T) {
+	r := &redactor{mode: RedactHash}
+	got := r.Redact(`"C:\\Games\\Save.dat" then C:\Games\save.dat, error: denied`)
+
+	hashes := regexp.MustCompile(`<path:[0-9a-f]{8}>`).FindAllString(got, -1)
+	if len(hashes) != 2 || hashes[0] != hashes[1] {
+		t.Fatalf("expected the same hash for both spellings, got %q", got)
+	}
+	if strings.Contains(got, "Games") || !strings.Contains(got, "error: denied") {
+		t.Errorf("unexpected redaction: %q", got)
+	}
+}
+
+func TestParseRedactMode(t *testing.T) {
+	if m, err := ParseRedactMode("HASH"); err != nil || m != RedactHash {
+		t.Errorf("ParseRedactMode(HASH) = %q, %v", m, err)
+	}
+	if _, err := ParseRedactMode("partial"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, data string) {
+		p := filepath.Join(dir, rel)
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("cfg/config.yaml", `{"active_profile": "default"}`)
+	write("cfg/profiles/default.json", `{"name": "default"}`)
+	write("syscleaner.log", strings.Repeat("old line\n", 10)+"last line\n")
+	for _, stamp := range []string{"20240101-000000", "20240102-000000", "20240103-000000", "20240104-000000", "20240105-000000", "20240106-000000"} {
+		write("registry/changes-"+stamp+".log", stamp)
+	}
+	write("reports/clean-20240101-000000.json", "{}")
+
+	src := Sources{
+		ConfigDir:   filepath.Join(dir, "cfg"),
+		LogPath:     filepath.Join(dir, "syscleaner.log"),
+		RegistryDir: filepath.Join(dir, "registry"),
+		ReportsDir:  filepath.Join(dir, "reports"),
+	}
+	var buf bytes.Buffer
+	names, err := writeBundle(&buf, src, &redactor{mode: RedactNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		contents[f.Name] = string(data)
+	}
+	if len(contents) != len(names) {
+		t.Errorf("zip has %d files, names lists %d", len(contents), len(names))
+	}
+
+	for _, name := range []string{"manifest.txt", "sysinfo.txt", "config/config.json",
+		"config/profiles/default.json", "logs/syscleaner.log", "reports/clean-20240101-000000.json"} {
+		if _, ok := contents[name]; !ok {
+			t.Errorf("bundle is missing %s", name)
+		}
+	}
+	if _, ok := contents["registry/changes-20240101-000000.log"]; ok {
+		t.Error("expected only the newest registry change logs")
+	}
+	if _, ok := contents["registry/changes-20240106-000000.log"]; !ok {
+		t.Error("expected the newest registry change log")
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	os.WriteFile(path, []byte("first line\nsecond line\nthird\n"), 0o644)
+
+	got, err := readTail(path, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "third\n" {
+		t.Errorf("readTail = %q, want the last whole line", got)
+	}
+}
@@ -0,0 +1,20 @@
+package sysinfo
+
+// PowerStatus describes how the machine is powered. OnBattery and Docked
+// are only meaningful when Known and DockKnown are set.
+type PowerStatus struct {
+	Known     bool
+	OnBattery bool
+	// BatteryPercent is the remaining charge, or -1 if unknown or there
+	// is no battery.
+	BatteryPercent int
+
+	DockKnown bool
+	Docked    bool
+}
+
+// Power returns the current power source and dock state. Desktops without
+// a battery report OnBattery false.
+func Power() PowerStatus {
+	return powerPlatform()
+}
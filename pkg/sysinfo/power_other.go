@@ -0,0 +1,7 @@
+//go:build !windows
+
+package sysinfo
+
+func powerPlatform() PowerStatus {
+	return PowerStatus{BatteryPercent: -1}
+}
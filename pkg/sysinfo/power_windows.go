@@ -0,0 +1,60 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+	user32                   = windows.NewLazySystemDLL("user32.dll")
+	procGetSystemMetrics     = user32.NewProc("GetSystemMetrics")
+)
+
+const (
+	acLineOffline         = 0
+	acLineOnline          = 1
+	batteryFlagNoBattery  = 128
+	batteryPercentUnknown = 255
+	// smSystemDocked is SM_SYSTEMDOCKED (Windows 8 and later).
+	smSystemDocked = 0x2004
+)
+
+// systemPowerStatus mirrors SYSTEM_POWER_STATUS.
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+func powerPlatform() PowerStatus {
+	status := PowerStatus{BatteryPercent: -1}
+
+	var sps systemPowerStatus
+	if r, _, _ := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&sps))); r != 0 {
+		switch sps.ACLineStatus {
+		case acLineOnline:
+			status.Known = true
+		case acLineOffline:
+			status.Known = true
+			status.OnBattery = sps.BatteryFlag&batteryFlagNoBattery == 0
+		}
+		if sps.BatteryFlag&batteryFlagNoBattery == 0 && sps.BatteryLifePercent != batteryPercentUnknown {
+			status.BatteryPercent = int(sps.BatteryLifePercent)
+		}
+	}
+
+	if procGetSystemMetrics.Find() == nil {
+		docked, _, _ := procGetSystemMetrics.Call(smSystemDocked)
+		status.DockKnown = true
+		status.Docked = docked != 0
+	}
+	return status
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package sysinfo
+
+// PendingReboot always reports false outside Windows.
+func PendingReboot() bool {
+	return false
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package sysinfo
+
+import "golang.org/x/sys/windows/registry"
+
+// PendingReboot reports whether Windows has queued work that needs a restart:
+// servicing (CBS) or Windows Update reboot flags, or pending file renames.
+func PendingReboot() bool {
+	for _, path := range []string{
+		`SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\RebootPending`,
+		`SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired`,
+	} {
+		if k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE); err == nil {
+			k.Close()
+			return true
+		}
+	}
+
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Control\Session Manager`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+	renames, _, err := k.GetStringsValue("PendingFileRenameOperations")
+	return err == nil && len(renames) > 0
+}
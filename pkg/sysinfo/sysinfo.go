@@ -0,0 +1,43 @@
+package sysinfo
+
+import (
+	"os"
+	"runtime"
+)
+
+// Info describes the hardware characteristics used to derive default
+// performance settings.
+type Info struct {
+	LogicalCPUs int
+	// SystemDriveSSD reports whether the system drive has no seek penalty.
+	// Only meaningful when SSDKnown is true.
+	SystemDriveSSD bool
+	SSDKnown       bool
+}
+
+// Detect gathers hardware information for the current machine. Detection
+// failures are not fatal; unknown values are reported via SSDKnown.
+func Detect() Info {
+	info := Info{LogicalCPUs: runtime.NumCPU()}
+	if ssd, err := IsSSD(SystemDrive()); err == nil {
+		info.SystemDriveSSD = ssd
+		info.SSDKnown = true
+	}
+	return info
+}
+
+// SystemDrive returns the system drive (e.g. "C:"), reading the SystemDrive
+// environment variable and defaulting to "C:" if it is unset.
+func SystemDrive() string {
+	drive := os.Getenv("SystemDrive")
+	if drive == "" {
+		drive = "C:"
+	}
+	return drive
+}
+
+// IsSSD reports whether the volume for the given drive letter (e.g. "C:")
+// is backed by a device with no seek penalty.
+func IsSSD(drive string) (bool, error) {
+	return isSSDPlatform(drive)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package sysinfo
+
+import "fmt"
+
+func isSSDPlatform(drive string) (bool, error) {
+	return false, fmt.Errorf("drive media detection is only available on Windows")
+}
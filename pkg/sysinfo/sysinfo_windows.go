@@ -0,0 +1,64 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	ioctlStorageQueryProperty    = 0x002d1400
+	storageDeviceSeekPenaltyProp = 7 // StorageDeviceSeekPenaltyProperty
+	propertyStandardQuery        = 0 // PropertyStandardQuery
+)
+
+type storagePropertyQuery struct {
+	PropertyID uint32
+	QueryType  uint32
+	Additional [1]byte
+}
+
+type deviceSeekPenaltyDescriptor struct {
+	Version           uint32
+	Size              uint32
+	IncursSeekPenalty byte
+}
+
+// isSSDPlatform queries the volume's seek penalty via
+// IOCTL_STORAGE_QUERY_PROPERTY instead of spawning PowerShell.
+func isSSDPlatform(drive string) (bool, error) {
+	drive = strings.TrimSuffix(strings.TrimSuffix(drive, `\`), ":")
+	path, err := windows.UTF16PtrFromString(`\\.\` + drive + ":")
+	if err != nil {
+		return false, err
+	}
+
+	// Zero access rights are sufficient for this query and do not require
+	// elevation.
+	handle, err := windows.CreateFile(path, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return false, fmt.Errorf("failed to open volume %s: %w", drive, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	query := storagePropertyQuery{
+		PropertyID: storageDeviceSeekPenaltyProp,
+		QueryType:  propertyStandardQuery,
+	}
+	var desc deviceSeekPenaltyDescriptor
+	var returned uint32
+	err = windows.DeviceIoControl(handle, ioctlStorageQueryProperty,
+		(*byte)(unsafe.Pointer(&query)), uint32(unsafe.Sizeof(query)),
+		(*byte)(unsafe.Pointer(&desc)), uint32(unsafe.Sizeof(desc)),
+		&returned, nil)
+	if err != nil {
+		return false, fmt.Errorf("seek penalty query failed for %s: %w", drive, err)
+	}
+	return desc.IncursSeekPenalty == 0, nil
+}
@@ -0,0 +1,35 @@
+package sysinfo
+
+import "strings"
+
+// VolumeInfo describes a volume's file system and the features the cleaner
+// and optimizers depend on. NTFS supports everything; ReFS, which Dev
+// Drives use, has no file compression and, before Windows 11 24H2, no USN
+// journal, so callers should check the flags rather than the name. Case
+// sensitivity is a per-directory setting on both; see IsCaseSensitiveDir.
+type VolumeInfo struct {
+	Drive      string // e.g. "D:"
+	FileSystem string // e.g. "NTFS", "ReFS", "FAT32"
+	// DevDrive is set for volumes formatted as a Dev Drive.
+	DevDrive            bool
+	SupportsCompression bool
+	SupportsUSN         bool
+}
+
+// IsReFS reports whether the volume is formatted with ReFS.
+func (v VolumeInfo) IsReFS() bool {
+	return strings.EqualFold(v.FileSystem, "ReFS")
+}
+
+// Volume returns file system information for the drive (e.g. "D:").
+func Volume(drive string) (VolumeInfo, error) {
+	drive = strings.TrimSuffix(strings.TrimSuffix(drive, `\`), ":") + ":"
+	return volumePlatform(drive)
+}
+
+// IsCaseSensitiveDir reports whether names in dir are compared case
+// sensitively, as set by "fsutil file setCaseSensitiveInfo" or WSL.
+// Errors are treated as case-insensitive, the Windows default.
+func IsCaseSensitiveDir(dir string) bool {
+	return caseSensitiveDirPlatform(dir)
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package sysinfo
+
+import "fmt"
+
+func volumePlatform(drive string) (VolumeInfo, error) {
+	return VolumeInfo{Drive: drive}, fmt.Errorf("volume information is only available on Windows")
+}
+
+func caseSensitiveDirPlatform(dir string) bool {
+	return false
+}
@@ -0,0 +1,87 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// FSCTL_QUERY_PERSISTENT_VOLUME_STATE
+	fsctlQueryPersistentVolumeState = 0x00090238
+	persistentVolumeStateDevVolume  = 0x00002000
+	fileCSFlagCaseSensitiveDir      = 0x00000001
+)
+
+// filePersistentVolumeInformation mirrors FILE_FS_PERSISTENT_VOLUME_INFORMATION.
+type filePersistentVolumeInformation struct {
+	VolumeFlags uint32
+	FlagMask    uint32
+	Version     uint32
+	Reserved    uint32
+}
+
+func volumePlatform(drive string) (VolumeInfo, error) {
+	info := VolumeInfo{Drive: drive}
+
+	root, err := windows.UTF16PtrFromString(drive + `\`)
+	if err != nil {
+		return info, err
+	}
+	var flags uint32
+	fsName := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumeInformation(root, nil, 0, nil, nil, &flags, &fsName[0], uint32(len(fsName))); err != nil {
+		return info, fmt.Errorf("failed to query volume %s: %w", drive, err)
+	}
+	info.FileSystem = windows.UTF16ToString(fsName)
+	info.SupportsCompression = flags&windows.FILE_FILE_COMPRESSION != 0
+	info.SupportsUSN = flags&windows.FILE_SUPPORTS_USN_JOURNAL != 0
+	info.DevDrive = isDevDrive(root)
+	return info, nil
+}
+
+// isDevDrive asks the file system whether the volume is a Dev Drive. Older
+// Windows builds reject the request, which means it is not one.
+func isDevDrive(root *uint16) bool {
+	handle, err := windows.CreateFile(root, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	in := filePersistentVolumeInformation{FlagMask: persistentVolumeStateDevVolume, Version: 1}
+	var out filePersistentVolumeInformation
+	var returned uint32
+	err = windows.DeviceIoControl(handle, fsctlQueryPersistentVolumeState,
+		(*byte)(unsafe.Pointer(&in)), uint32(unsafe.Sizeof(in)),
+		(*byte)(unsafe.Pointer(&out)), uint32(unsafe.Sizeof(out)),
+		&returned, nil)
+	return err == nil && out.VolumeFlags&persistentVolumeStateDevVolume != 0
+}
+
+func caseSensitiveDirPlatform(dir string) bool {
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return false
+	}
+	handle, err := windows.CreateFile(path, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	// FILE_CASE_SENSITIVE_INFO is a single ULONG of flags.
+	var flags uint32
+	if err := windows.GetFileInformationByHandleEx(handle, windows.FileCaseSensitiveInfo,
+		(*byte)(unsafe.Pointer(&flags)), uint32(unsafe.Sizeof(flags))); err != nil {
+		return false
+	}
+	return flags&fileCSFlagCaseSensitiveDir != 0
+}
@@ -0,0 +1,70 @@
+// Package sysrestore creates System Restore points before SysCleaner makes
+// changes that would be hard to undo by hand, such as extreme mode or
+// deleting startup entries.
+package sysrestore
+
+import (
+	"log"
+	"sync"
+
+	"syscleaner/pkg/admin"
+)
+
+var (
+	mu        sync.Mutex
+	automatic = true
+	// created is set once this run has made a restore point.
+	created bool
+)
+
+// These are variables so tests can fake System Restore.
+var (
+	systemRestoreEnabled = Enabled
+	createRestorePoint   = Create
+	isElevated           = admin.IsElevated
+)
+
+// SetAutomatic turns the restore points made by Before on or off.
+func SetAutomatic(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	automatic = on
+}
+
+// Automatic reports whether Before makes restore points.
+func Automatic() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return automatic
+}
+
+// Before creates a restore point before change, a short description such
+// as "extreme mode", unless automatic restore points are off, System
+// Restore is disabled, the process is not elevated or audit mode is on.
+// Only the first call in a run makes one, since each takes a while and
+// Windows keeps at most one a day by default. Failures are logged rather
+// than returned: a missing restore point does not stop the change.
+func Before(change string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !automatic || created || admin.AuditMode() {
+		return
+	}
+	if !isElevated() {
+		log.Printf("[SysCleaner] Not creating a restore point before %s: administrator rights are required", change)
+		return
+	}
+	if on, err := systemRestoreEnabled(); err != nil {
+		log.Printf("[SysCleaner] Could not tell whether System Restore is enabled: %v", err)
+		return
+	} else if !on {
+		log.Printf("[SysCleaner] Not creating a restore point before %s: System Restore is disabled", change)
+		return
+	}
+	log.Printf("[SysCleaner] Creating a System Restore point before %s...", change)
+	if err := createRestorePoint("SysCleaner: before " + change); err != nil {
+		log.Printf("[SysCleaner] Failed to create a restore point: %v", err)
+		return
+	}
+	created = true
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package sysrestore
+
+import "fmt"
+
+// Enabled reports whether System Restore is enabled. It never is on this
+// platform.
+func Enabled() (bool, error) {
+	return false, nil
+}
+
+// Create is not available on non-Windows platforms.
+func Create(description string) error {
+	return fmt.Errorf("System Restore is only available on Windows")
+}
@@ -0,0 +1,61 @@
+package sysrestore
+
+import (
+	"errors"
+	"testing"
+
+	"syscleaner/pkg/admin"
+)
+
+func TestBefore(t *testing.T) {
+	origEnabled, origCreate, origElevated := systemRestoreEnabled, createRestorePoint, isElevated
+	t.Cleanup(func() {
+		systemRestoreEnabled, createRestorePoint, isElevated = origEnabled, origCreate, origElevated
+		SetAutomatic(true)
+		created = false
+	})
+
+	srOn, elevated := false, true
+	var points []string
+	var fail error
+	systemRestoreEnabled = func() (bool, error) { return srOn, nil }
+	isElevated = func() bool { return elevated }
+	createRestorePoint = func(desc string) error {
+		if fail != nil {
+			return fail
+		}
+		points = append(points, desc)
+		return nil
+	}
+
+	Before("extreme mode")
+	if len(points) != 0 {
+		t.Fatal("a restore point was made with System Restore disabled")
+	}
+
+	srOn, elevated = true, false
+	Before("extreme mode")
+	if len(points) != 0 {
+		t.Fatal("a restore point was made without administrator rights")
+	}
+
+	elevated = true
+	SetAutomatic(false)
+	Before("extreme mode")
+	admin.SetAuditMode(true)
+	SetAutomatic(true)
+	Before("extreme mode")
+	admin.SetAuditMode(false)
+	if len(points) != 0 {
+		t.Fatal("a restore point was made with automatic restore points off or in audit mode")
+	}
+
+	fail = errors.New("boom")
+	Before("extreme mode")
+	fail = nil
+	Before("startup optimization")
+	Before("network optimization")
+	if len(points) != 1 || points[0] != "SysCleaner: before startup optimization" {
+		t.Errorf("points = %q, want one made after the failed attempt", points)
+	}
+}
@@ -0,0 +1,98 @@
+//go:build windows
+
+package sysrestore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	srclient               = windows.NewLazySystemDLL("srclient.dll")
+	procSRSetRestorePointW = srclient.NewProc("SRSetRestorePointW")
+)
+
+// SRSetRestorePoint event and restore point types.
+const (
+	beginSystemChange = 100
+	endSystemChange   = 101
+	modifySettings    = 12
+)
+
+// restorePointInfo is RESTOREPOINTINFOW. The header packs it to one byte,
+// which here matches Go's layout.
+type restorePointInfo struct {
+	EventType      uint32
+	RestorePtType  uint32
+	SequenceNumber int64
+	Description    [256]uint16
+}
+
+// Enabled reports whether System Restore is enabled, that is not turned
+// off by policy and protecting at least one drive.
+func Enabled() (bool, error) {
+	if k, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SOFTWARE\Policies\Microsoft\Windows NT\SystemRestore`, registry.QUERY_VALUE); err == nil {
+		v, _, err := k.GetIntegerValue("DisableSR")
+		k.Close()
+		if err == nil && v == 1 {
+			return false, nil
+		}
+	}
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SOFTWARE\Microsoft\Windows NT\CurrentVersion\SystemRestore`, registry.QUERY_VALUE)
+	if err != nil {
+		return false, err
+	}
+	defer k.Close()
+	// RPSessionInterval is 0 when protection is off for every drive.
+	v, _, err := k.GetIntegerValue("RPSessionInterval")
+	if err == registry.ErrNotExist {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return v != 0, nil
+}
+
+// Create makes a System Restore point with the given description. Windows
+// silently skips it when another was made in the last 24 hours, unless
+// SystemRestorePointCreationFrequency says otherwise.
+func Create(description string) error {
+	if err := procSRSetRestorePointW.Find(); err != nil {
+		return fmt.Errorf("System Restore is not available: %w", err)
+	}
+	info := restorePointInfo{EventType: beginSystemChange, RestorePtType: modifySettings}
+	desc, err := windows.UTF16FromString(description)
+	if err != nil {
+		return err
+	}
+	copy(info.Description[:len(info.Description)-1], desc)
+
+	seq, err := setRestorePoint(&info)
+	if err != nil {
+		return err
+	}
+	// Nothing is changed between the two calls; ending the change at
+	// once leaves a restore point of the state before it.
+	info = restorePointInfo{EventType: endSystemChange, SequenceNumber: seq}
+	_, err = setRestorePoint(&info)
+	return err
+}
+
+// setRestorePoint calls SRSetRestorePointW and returns the restore point's
+// sequence number.
+func setRestorePoint(info *restorePointInfo) (int64, error) {
+	// STATEMGRSTATUS is a DWORD status and an INT64 sequence number,
+	// packed to one byte.
+	var status [12]byte
+	r, _, _ := procSRSetRestorePointW.Call(uintptr(unsafe.Pointer(info)), uintptr(unsafe.Pointer(&status[0])))
+	if r == 0 {
+		return 0, fmt.Errorf("SRSetRestorePoint failed: %w", windows.Errno(binary.LittleEndian.Uint32(status[:4])))
+	}
+	return int64(binary.LittleEndian.Uint64(status[4:])), nil
+}